@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newCurrentCmd creates the current command.
+func newCurrentCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "current",
+		Short: "Print the environment of the last complete switch",
+		Long: `Print the name of the environment the last switch-all switched every
+service to, as recorded in ~/.gzh/dev-env/current.
+
+A switch that leaves some services failed clears the record, since no
+environment is then fully in effect; a switch that fails and rolls back
+leaves it unchanged. Services changed by hand or by other tools since the
+switch are not detected; use dev-env status to see the live state.
+
+The exit status is 0 if an environment is current and 1 otherwise.
+
+Examples:
+  # Print the current environment
+  dev-env current
+
+  # Use it in a shell prompt
+  PS1='[$(dev-env current 2>/dev/null)] \$ '`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := environment.ReadCurrent(currentPath())
+			if err != nil {
+				return err
+			}
+			if name == "" {
+				return errors.New("no environment is current; switch with dev-env switch-all")
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), name)
+			return nil
+		},
+	}
+}
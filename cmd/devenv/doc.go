@@ -10,7 +10,7 @@
 //
 //	cmd := devenv.NewRootCmd()
 //	if err := cmd.Execute(); err != nil {
-//	    os.Exit(1)
+//	    os.Exit(devenv.ExitCode(err))
 //	}
 //
 // Usage in wrapper:
@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+)
+
+// newDoctorCmd creates the doctor command.
+func newDoctorCmd() *cobra.Command {
+	var fix bool
+
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check dev-env's own files for problems",
+		Long: `Check the files dev-env keeps for problems.
+
+Environment files, switch history, and saved configurations can hold account
+IDs and tokens, so they should be readable by their owner only. doctor
+reports everything under ~/.gzh/dev-env and the configuration stores in
+~/.gz/*-configs that the owner's group or other users can access. Nothing is
+changed unless --fix is given, which removes the group and other permission
+bits (0700 for directories, 0600 for files).
+
+Permission checks are skipped on Windows.
+
+The exit status is 0 if no problems remain and 1 otherwise.
+
+Examples:
+  # Report problems
+  dev-env doctor
+
+  # Tighten the permissions it reports
+  dev-env doctor --fix`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			roots := append([]string{config.DefaultBaseDir()}, config.DefaultStorePaths()...)
+			ok, err := runDoctor(cmd.OutOrStdout(), roots, fix)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				cmd.SilenceErrors = true
+				return &ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&fix, "fix", false, "Remove group and other permissions from the reported files")
+
+	return cmd
+}
+
+// runDoctor checks the permissions under roots, fixing them if fix is set.
+// It reports whether no problems remain.
+func runDoctor(out io.Writer, roots []string, fix bool) (bool, error) {
+	if !config.PermissionChecksSupported() {
+		fmt.Fprintln(out, "ℹ️  File permission checks are not supported on this platform")
+		return true, nil
+	}
+
+	issues, err := config.CheckPermissions(roots...)
+	if err != nil {
+		return false, err
+	}
+	if len(issues) == 0 {
+		fmt.Fprintln(out, "✅ File permissions: only the owner can access dev-env's files")
+		return true, nil
+	}
+
+	fmt.Fprintf(out, "⚠️  File permissions: %d path(s) accessible by group or others\n", len(issues))
+	for _, issue := range issues {
+		fmt.Fprintf(out, "   %s\n", issue)
+	}
+
+	if !fix {
+		fmt.Fprintln(out, "Run 'dev-env doctor --fix' to tighten them.")
+		return false, nil
+	}
+	if err := config.FixPermissions(issues); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(out, "🔧 Fixed permissions of %d path(s)\n", len(issues))
+	return true, nil
+}
@@ -0,0 +1,315 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/difffmt"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newEnvCmd creates the env command group.
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Inspect environment configurations",
+	}
+
+	cmd.AddCommand(newEnvDiffCmd())
+	cmd.AddCommand(newEnvEditCmd())
+
+	return cmd
+}
+
+// newEnvDiffCmd creates the env diff command.
+func newEnvDiffCmd() *cobra.Command {
+	var (
+		unified bool
+		live    bool
+		noColor bool
+		format  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff <a> [b]",
+		Short: "Compare two environment configurations",
+		Long: `Compare two environments by name, alias, or file path, or compare an
+environment against the current machine state with --live.
+
+By default the changed service fields are shown as a table of
+"field  old → new" rows. Use --unified to compare the files as text, or
+--format json to emit the differences as records.
+
+Like diff(1), the exit status is 0 if there are no differences, 1 if
+there are differences, and 2 on errors.
+
+Examples:
+  # Show which service settings differ between staging and production
+  dev-env env diff staging production
+
+  # Show a unified diff of two environment files
+  dev-env env diff ./staging.yaml ./production.yaml --unified
+
+  # Show what switching to production would change on this machine
+  dev-env env diff --live production
+
+  # Emit the differences as JSON
+  dev-env env diff staging production --format json`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if live {
+				return cobra.ExactArgs(1)(cmd, args)
+			}
+			return cobra.ExactArgs(2)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			differs, err := runEnvDiff(cmd.Context(), args, live, unified, format, !noColor)
+			if err != nil {
+				return &ExitError{Code: 2, Err: err}
+			}
+			if differs {
+				// The differences were already printed; only the status remains.
+				cmd.SilenceErrors = true
+				return &ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&unified, "unified", false, "Show a unified text diff of the environment files")
+	cmd.Flags().BoolVar(&live, "live", false, "Compare the environment against the current machine state")
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table,json)")
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+
+	return cmd
+}
+
+// runEnvDiff prints the differences between two environments, or between an
+// environment and the live state, and reports whether any were found.
+func runEnvDiff(ctx context.Context, args []string, live, unified bool, format string, useColor bool) (bool, error) {
+	if format != "table" && format != "json" {
+		return false, fmt.Errorf("invalid format: %s (supported: table, json)", format)
+	}
+	if unified && (live || format == "json") {
+		return false, fmt.Errorf("--unified cannot be combined with --live or --format json")
+	}
+
+	paths := make([]string, 0, len(args))
+	for _, arg := range args {
+		path, err := resolveEnvironmentArg(arg)
+		if err != nil {
+			return false, err
+		}
+		paths = append(paths, path)
+	}
+
+	renderer := difffmt.New(useColor)
+
+	if unified {
+		return printUnifiedDiff(renderer, paths[0], paths[1])
+	}
+
+	envs := make([]*environment.Environment, 0, len(paths))
+	for _, path := range paths {
+		env, err := environment.LoadEnvironmentFromFile(path)
+		if err != nil {
+			return false, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		env, err = environment.ExpandComposition(env, environment.SearchPathResolver(environmentSearchPaths()))
+		if err != nil {
+			return false, fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		envs = append(envs, env)
+	}
+
+	var (
+		diffs       []environment.FieldDiff
+		left, right string
+	)
+	if live {
+		switcher := environment.NewEnvironmentSwitcher()
+		registerDefaultSwitchers(switcher)
+
+		var err error
+		diffs, err = switcher.Plan(ctx, envs[0])
+		if err != nil {
+			return false, fmt.Errorf("failed to read current state: %w", err)
+		}
+		left, right = "current", envs[0].Name
+	} else {
+		diffs = environment.DiffEnvironments(envs[0], envs[1])
+		left, right = envs[0].Name, envs[1].Name
+	}
+
+	if format == "json" {
+		if diffs == nil {
+			diffs = []environment.FieldDiff{}
+		}
+		data, err := json.MarshalIndent(diffs, "", "  ")
+		if err != nil {
+			return false, fmt.Errorf("failed to marshal differences: %w", err)
+		}
+		fmt.Println(string(data))
+		return len(diffs) > 0, nil
+	}
+
+	if len(diffs) == 0 {
+		fmt.Println("No differences")
+		return false, nil
+	}
+	fmt.Printf("%s → %s\n", left, right)
+	fmt.Print(renderer.Fields(fieldChanges(diffs)))
+	return true, nil
+}
+
+// printUnifiedDiff prints a unified text diff of two environment files.
+func printUnifiedDiff(renderer *difffmt.Renderer, pathA, pathB string) (bool, error) {
+	dataA, err := os.ReadFile(pathA)
+	if err != nil {
+		return false, fmt.Errorf("failed to read environment file %s: %w", pathA, err)
+	}
+	dataB, err := os.ReadFile(pathB)
+	if err != nil {
+		return false, fmt.Errorf("failed to read environment file %s: %w", pathB, err)
+	}
+
+	output := renderer.Unified(pathA, pathB, string(dataA), string(dataB))
+	if output == "" {
+		fmt.Println("No differences")
+		return false, nil
+	}
+	fmt.Print(output)
+	return true, nil
+}
+
+// newEnvEditCmd creates the env edit command.
+func newEnvEditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <environment>",
+		Short: "Edit an environment file and validate it on save",
+		Long: `Open an environment, by name, alias, or file path, in $VISUAL or $EDITOR
+(vi if neither is set).
+
+When the editor exits, the file gets the same checks as dev-env validate.
+If any fail, the problems are shown and the editor can be re-opened to fix
+them; declining restores the file's original content.
+
+The editor needs a terminal. In scripts, edit the file directly and run
+dev-env validate.
+
+Examples:
+  # Edit the production environment
+  dev-env env edit production
+
+  # Edit with a specific editor
+  EDITOR="code --wait" dev-env env edit ./staging.yaml`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runEnvEdit(cmd, args[0])
+		},
+	}
+
+	cmd.ValidArgsFunction = func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return environment.ListEnvironmentNames(environmentSearchPaths()), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// runEnvEdit edits the environment arg until it validates or the user
+// gives up.
+func runEnvEdit(cmd *cobra.Command, arg string) error {
+	if !term.IsTerminal(os.Stdin.Fd()) || !term.IsTerminal(os.Stdout.Fd()) {
+		return fmt.Errorf("env edit needs an interactive terminal; edit the file directly and run 'dev-env validate' to check it")
+	}
+
+	path, err := resolveEnvironmentArg(arg)
+	if err != nil {
+		return err
+	}
+
+	validator := valueValidator()
+	switcher := validationSwitcher()
+	out := cmd.OutOrStdout()
+	loop := environment.EditLoop{
+		Edit: runEditor,
+		Validate: func(path string) []string {
+			problems, _ := validateEnvironmentFile(path, validator, switcher)
+			return problems
+		},
+		Retry: func(problems []string) bool {
+			fmt.Fprintf(out, "❌ %s\n", path)
+			for _, problem := range problems {
+				fmt.Fprintf(out, "   %s\n", problem)
+			}
+			fmt.Fprint(out, "Re-open the editor? [Y/n]: ")
+			var response string
+			_, _ = fmt.Fscanln(cmd.InOrStdin(), &response)
+			return response != "n" && response != "N" && response != "no"
+		},
+	}
+
+	if err := loop.Run(path); err != nil {
+		if errors.Is(err, environment.ErrEditAborted) {
+			return fmt.Errorf("edit aborted; restored the original %s", path)
+		}
+		return err
+	}
+	fmt.Fprintf(out, "✅ %s\n", path)
+	return nil
+}
+
+// runEditor opens path in $VISUAL, $EDITOR, or vi, attached to the terminal.
+// The variable may include arguments, as in "code --wait".
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		fields = []string{"vi"}
+	}
+
+	// #nosec G204 - The editor is chosen by the user running the command
+	editorCmd := exec.Command(fields[0], append(fields[1:], path)...)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+	return editorCmd.Run()
+}
+
+// resolveEnvironmentArg treats arg as a file path if it exists, otherwise
+// as an environment name or alias.
+func resolveEnvironmentArg(arg string) (string, error) {
+	if info, err := os.Stat(arg); err == nil && !info.IsDir() {
+		return arg, nil
+	}
+	return environment.FindEnvironmentFile(environmentSearchPaths(), arg)
+}
+
+// fieldChanges converts service diffs into rows for difffmt.
+func fieldChanges(diffs []environment.FieldDiff) []difffmt.FieldChange {
+	changes := make([]difffmt.FieldChange, 0, len(diffs))
+	for _, d := range diffs {
+		changes = append(changes, difffmt.FieldChange{
+			Field: d.Service + "." + d.Field,
+			Old:   d.Left,
+			New:   d.Right,
+		})
+	}
+	return changes
+}
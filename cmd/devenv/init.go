@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/setup"
+)
+
+// newInitCmd creates the init command.
+func newInitCmd() *cobra.Command {
+	var (
+		yes              bool
+		services         []string
+		noSnapshot       bool
+		shellIntegration bool
+		shell            string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Set up dev-env for first use",
+		Long: `Walk through setting up dev-env on a new machine:
+
+1. Create ~/.gzh/dev-env/environments
+2. Detect which service CLIs are installed
+3. Choose the services to manage; the rest are saved as disabled in
+   ~/.gzh/dev-env/settings.yaml
+4. Save the current state as the "default" environment
+5. Install shell completion into your shell rc file
+
+Every step asks first and can be skipped. With --yes no questions are asked
+and each step takes its default answer, which suits provisioning scripts.
+
+Examples:
+  # Run the interactive wizard
+  dev-env init
+
+  # Provision non-interactively
+  dev-env init --yes --services aws,kubernetes
+
+  # Also install zsh completion
+  dev-env init --yes --shell-integration --shell zsh`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runInit(cmd, yes, services, !noSnapshot, shellIntegration, shell)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Answer every question with its default")
+	cmd.Flags().StringSliceVar(&services, "services", nil, "Services to enable without asking (aws,gcp,azure,docker,kubernetes,ssh,gpg,pkgmgr)")
+	cmd.Flags().BoolVar(&noSnapshot, "no-snapshot", false, "Skip saving the current state as the default environment")
+	cmd.Flags().BoolVar(&shellIntegration, "shell-integration", false, "Install shell completion by default")
+	cmd.Flags().StringVar(&shell, "shell", filepath.Base(os.Getenv("SHELL")), "Shell to install completion for (bash, zsh)")
+
+	return cmd
+}
+
+// runInit runs the first-run wizard.
+func runInit(cmd *cobra.Command, yes bool, services []string, snapshot, shellIntegration bool, shell string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to find home directory: %w", err)
+	}
+
+	var prompter setup.Prompter = setup.NewLinePrompter(cmd.InOrStdin(), cmd.OutOrStdout())
+	if yes {
+		prompter = setup.DefaultsPrompter{}
+	}
+
+	wizard := &setup.Wizard{
+		Prompter:                prompter,
+		Out:                     cmd.OutOrStdout(),
+		BaseDir:                 filepath.Join(homeDir, ".gzh", "dev-env"),
+		Services:                services,
+		ShellIntegrationDefault: shellIntegration,
+	}
+
+	if snapshot {
+		switcher := environment.NewEnvironmentSwitcher()
+		registerDefaultSwitchers(switcher)
+		wizard.Snapshot = switcher.Snapshot
+	}
+
+	if rcPath, err := setup.RCFile(homeDir, shell); err == nil {
+		wizard.InstallShellIntegration = func() error {
+			return installCompletion(cmd.Root(), cmd.OutOrStdout(), wizard.BaseDir, shell, rcPath)
+		}
+	} else {
+		fmt.Fprintf(cmd.OutOrStdout(), "Shell completion: %v; skipping\n", err)
+	}
+
+	if _, err := wizard.Run(cmd.Context()); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.OutOrStdout(), "✅ dev-env is ready. Run 'dev-env status' to check your services.")
+	return nil
+}
+
+// installCompletion writes the completion script for shell into baseDir and
+// sources it from rcPath.
+func installCompletion(root *cobra.Command, out io.Writer, baseDir, shell, rcPath string) error {
+	var script bytes.Buffer
+	var err error
+	switch shell {
+	case "bash":
+		err = root.GenBashCompletionV2(&script, true)
+	case "zsh":
+		err = root.GenZshCompletion(&script)
+	default:
+		err = fmt.Errorf("unsupported shell: %s", shell)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate completion: %w", err)
+	}
+
+	scriptPath := filepath.Join(baseDir, "completion."+shell)
+	if err := os.MkdirAll(baseDir, config.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create %s: %w", baseDir, err)
+	}
+	if err := os.WriteFile(scriptPath, script.Bytes(), config.PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", scriptPath, err)
+	}
+
+	snippet := fmt.Sprintf("[ -f %q ] && . %q", scriptPath, scriptPath)
+	if err := setup.InstallShellHook(rcPath, snippet); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Installed %s completion; restart your shell or run: . %s\n", shell, rcPath)
+	return nil
+}
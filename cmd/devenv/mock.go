@@ -0,0 +1,98 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/mockservice"
+)
+
+// mockServices, when set, replaces the default checkers and switchers. It is
+// set from --mock or GZH_DEV_ENV_MOCK before any command runs.
+var mockServices *mockservice.Set
+
+// mockStateDir, in mock mode, is a temporary directory holding the switch
+// lock, history, and current environment marker in place of
+// ~/.gzh/dev-env, so mock switches leave the host's state alone. It is
+// removed when the command finishes.
+var mockStateDir string
+
+// addMockFlag adds the hidden --mock flag. Alone it enables the default mock
+// services; --mock=FILE loads a fixture instead.
+func addMockFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("mock", "", "Use mock services from a fixture file instead of the real CLIs")
+	cmd.PersistentFlags().Lookup("mock").NoOptDefVal = "1"
+	_ = cmd.PersistentFlags().MarkHidden("mock")
+}
+
+// enableMockServices loads the mock services selected by --mock or, if the
+// flag is not given, GZH_DEV_ENV_MOCK.
+func enableMockServices(cmd *cobra.Command) error {
+	spec := os.Getenv(mockservice.EnvVar)
+	if flag := cmd.Flags().Lookup("mock"); flag != nil && flag.Changed {
+		spec = flag.Value.String()
+	}
+
+	set, err := mockservice.FromEnv(spec)
+	if err != nil {
+		return err
+	}
+	mockServices = set
+	if set == nil {
+		return nil
+	}
+
+	dir, err := os.MkdirTemp("", "dev-env-mock-")
+	if err != nil {
+		return fmt.Errorf("failed to create mock state directory: %w", err)
+	}
+	mockStateDir = dir
+	cobra.OnFinalize(func() { _ = os.RemoveAll(dir) })
+	fmt.Fprintln(os.Stderr, "🧪 Mock mode: using fake services, no CLI or hook is run")
+	return nil
+}
+
+// switchLockPath returns the switch lock's path, in the mock state
+// directory in mock mode.
+func switchLockPath() string {
+	if mockStateDir != "" {
+		return filepath.Join(mockStateDir, "switch.lock")
+	}
+	return environment.DefaultSwitchLockPath()
+}
+
+// currentPath returns the current environment marker's path, in the mock
+// state directory in mock mode.
+func currentPath() string {
+	if mockStateDir != "" {
+		return filepath.Join(mockStateDir, "current")
+	}
+	return environment.DefaultCurrentPath()
+}
+
+// switchHistory returns the switch history, kept in the mock state
+// directory in mock mode.
+func switchHistory() *environment.History {
+	if mockStateDir != "" {
+		return environment.NewHistory(filepath.Join(mockStateDir, "history.json"))
+	}
+	return environment.NewHistory("")
+}
+
+// switchContext returns the context to switch with. In mock mode, hooks
+// and any other command are answered by a simulator that succeeds without
+// output, so nothing runs on the host.
+func switchContext(ctx context.Context) context.Context {
+	if mockServices == nil {
+		return ctx
+	}
+	return environment.SimulateCommands(ctx, environment.SimulatorConfig{})
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/notify"
+)
+
+// switchNotifiers returns the notifiers to tell when a switch finishes: a
+// webhook posting to webhookURL (--notify-webhook) or notifyWebhook in
+// settings, and desktop notifications if desktop (--notify-desktop) or
+// notifyDesktop in settings is set. An invalid webhook URL is an error, so
+// it is caught before switching rather than after.
+func switchNotifiers(webhookURL string, desktop bool) ([]notify.Notifier, error) {
+	settings, err := config.LoadSettings(config.DefaultSettingsPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		settings = &config.Settings{}
+	}
+
+	var notifiers []notify.Notifier
+	if webhookURL == "" {
+		webhookURL = settings.NotifyWebhook
+	}
+	if webhookURL != "" {
+		webhook, err := notify.NewWebhook(webhookURL)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, webhook)
+	}
+	if desktop || settings.NotifyDesktop {
+		notifiers = append(notifiers, notify.NewDesktop())
+	}
+	return notifiers, nil
+}
+
+// sendNotifications tells notifiers the result of the switch to name.
+// Notification problems are warnings: the switch itself is already done.
+func sendNotifications(ctx context.Context, notifiers []notify.Notifier, name string, result *environment.SwitchResult) {
+	// The switch may have used up its timeout; each notifier has its own.
+	if err := notify.All(context.WithoutCancel(ctx), notifiers, name, result); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to send notification: %v\n", err)
+	}
+}
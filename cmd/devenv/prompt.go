@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/prompt"
+)
+
+// newPromptCmd creates the prompt command.
+func newPromptCmd() *cobra.Command {
+	var (
+		noColor    bool
+		initShell  string
+		shell      string
+		staleAfter time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prompt",
+		Short: "Print the active environment for a shell prompt",
+		Long: `Print a short prompt segment for the active environment: a status glyph
+and the environment name, such as "✓ prod".
+
+The segment is read from cached state only, the last switch in the history
+and the statuses the last dev-env status wrote to the status cache, so it
+runs no CLI and is fast enough for every prompt. The glyph is:
+  ✓  the switch succeeded and no status checked since disagrees
+  !  the switch left some services failed
+  ✗  a service has been checked as inactive or erroring since the switch
+
+If nothing has been checked for longer than --stale-after, the name is
+dimmed and followed by an asterisk; run dev-env status to refresh it.
+Nothing is printed if no switch has been recorded.
+
+Examples:
+  # Print the segment
+  dev-env prompt
+
+  # Add it to the zsh prompt, in ~/.zshrc
+  eval "$(dev-env prompt --init zsh)"
+
+  # Add it to the bash prompt, in ~/.bashrc
+  eval "$(dev-env prompt --init bash)"
+
+  # Add it to the fish prompt, in ~/.config/fish/config.fish
+  dev-env prompt --init fish | source`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if initShell != "" {
+				script, err := prompt.InitScript(initShell, cmd.CommandPath())
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(cmd.OutOrStdout(), script)
+				return nil
+			}
+			if shell != "" && !slices.Contains(prompt.Shells, shell) {
+				return fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(prompt.Shells, ", "))
+			}
+
+			state, err := prompt.Load(prompt.Sources{})
+			if err != nil || state == nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), state.Render(time.Now(), prompt.Options{
+				Color:      !noColor,
+				Shell:      shell,
+				StaleAfter: staleAfter,
+			}))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	cmd.Flags().StringVar(&initShell, "init", "", "Print the snippet that adds the segment to the prompt of this shell (zsh,bash,fish)")
+	cmd.Flags().StringVar(&shell, "shell", "", "Mark color codes as zero-width for this shell's prompt (zsh,bash); set by the --init snippets")
+	cmd.Flags().DurationVar(&staleAfter, "stale-after", prompt.DefaultStaleAfter, "Dim the environment name when nothing has been checked for this long")
+
+	return cmd
+}
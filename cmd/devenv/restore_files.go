@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newRestoreFilesCmd creates the restore-files command.
+func newRestoreFilesCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "restore-files SWITCH_ID",
+		Short: "Restore the configuration files snapshotted before a switch",
+		Long: `Copy the configuration files snapshotted before a switch back into place.
+
+With fileSnapshots: true in ~/.gzh/dev-env/settings.yaml, the files each
+switcher modifies (the kubeconfig, ~/.ssh/config, ~/.docker/config.json,
+~/.aws/config) are copied into ~/.gzh/dev-env/snapshots before every switch.
+Rollback only restores the settings a switcher reads back, so use this when a
+switcher or hook has damaged one of the files themselves.
+
+The switch ID is printed by switch-all and recorded in the switch history.
+
+Examples:
+  # Restore the files as they were before a switch
+  dev-env restore-files 01JZ3K5V8Q2W4X6Y8Z0A1B2C3D
+
+  # Restore without asking for confirmation
+  dev-env restore-files 01JZ3K5V8Q2W4X6Y8Z0A1B2C3D --force`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			snapshots := environment.NewFileSnapshots(environment.DefaultFileSnapshotDir(), 0)
+			snapshot, err := snapshots.Find(args[0])
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			if len(snapshot.Files) == 0 {
+				fmt.Fprintf(out, "No files were snapshotted before switch %s\n", snapshot.SwitchID)
+				return nil
+			}
+
+			fmt.Fprintf(out, "📁 Files snapshotted before switch %s at %s:\n", snapshot.SwitchID, snapshot.TakenAt.Local().Format("2006-01-02 15:04:05"))
+			for _, file := range snapshot.Files {
+				fmt.Fprintf(out, "   %s\n", file.Path)
+			}
+
+			if !force {
+				fmt.Fprint(out, "Overwrite these files? [y/N]: ")
+				var response string
+				_, _ = fmt.Fscanln(cmd.InOrStdin(), &response)
+				if response != "y" && response != "Y" && response != "yes" {
+					return fmt.Errorf("operation canceled by user")
+				}
+			}
+
+			restored, err := snapshots.Restore(snapshot)
+			for _, path := range restored {
+				fmt.Fprintf(out, "   ✓ %s\n", path)
+			}
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "✅ Restored %d files\n", len(restored))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Restore without confirmation")
+
+	return cmd
+}
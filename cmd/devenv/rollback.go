@@ -0,0 +1,110 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newRollbackCmd creates the rollback command.
+func newRollbackCmd() *cobra.Command {
+	var (
+		service   string
+		switchID  string
+		dryRun    bool
+		force     bool
+		allowRoot bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "rollback --service SERVICE",
+		Short: "Undo one service of a switch",
+		Long: `Restore a single service to the state it was in before a switch, leaving
+the other services as the switch left them.
+
+The previous state of every switched service is recorded in the switch
+history, ~/.gzh/dev-env/history.json. Without --switch-id the last switch is
+used; the switch ID is printed by switch-all. The rollback is recorded in
+the history as a "partial-rollback" entry, and since the environment is then
+only partly in effect, dev-env current reports none.
+
+Examples:
+  # Undo the kubernetes part of the last switch
+  dev-env rollback --service kubernetes
+
+  # Show the state that would be restored
+  dev-env rollback --service kubernetes --dry-run
+
+  # Undo the aws part of an earlier switch without confirmation
+  dev-env rollback --service aws --switch-id 01JZ3K5V8Q2W4X6Y8Z0A1B2C3D --force`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if service == "" {
+				return errors.New("--service is required")
+			}
+
+			history := switchHistory()
+			entry, err := history.Find(switchID)
+			if err != nil {
+				return err
+			}
+			state, err := entry.PreviousState(service)
+			if err != nil {
+				return err
+			}
+
+			out := cmd.OutOrStdout()
+			data, err := yaml.Marshal(state)
+			if err != nil {
+				return fmt.Errorf("failed to encode state: %w", err)
+			}
+			fmt.Fprintf(out, "⏪ State of %s before switch %s to %s:\n", service, entry.SwitchID, entry.Environment)
+			for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+				fmt.Fprintf(out, "   %s\n", line)
+			}
+			if dryRun {
+				fmt.Fprintln(out, "👁️  DRY-RUN MODE: No changes will be made")
+				return nil
+			}
+
+			if !force {
+				fmt.Fprintf(out, "Restore %s to this state? [y/N]: ", service)
+				var response string
+				_, _ = fmt.Fscanln(cmd.InOrStdin(), &response)
+				if response != "y" && response != "Y" && response != "yes" {
+					return fmt.Errorf("operation canceled by user")
+				}
+			}
+
+			switcher := environment.NewEnvironmentSwitcher()
+			registerDefaultSwitchers(switcher)
+			switcher.SetHistory(history)
+			switcher.SetCurrentMarker(currentPath())
+
+			if _, err := switcher.RollbackService(switchContext(cmd.Context()), entry, service, environment.SwitchOptions{AllowRoot: allowRoot}); err != nil {
+				if errors.Is(err, environment.ErrSwitchInProgress) {
+					return fmt.Errorf("%w; wait for it to finish, or use switch-all --force-unlock if it is hung", err)
+				}
+				return err
+			}
+			fmt.Fprintf(out, "✅ Rolled back %s to its state before switch %s\n", service, entry.SwitchID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&service, "service", "", "Service to roll back, such as kubernetes or aws")
+	cmd.Flags().StringVar(&switchID, "switch-id", "", "Switch to undo the service of (default: the last switch)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the state that would be restored without restoring it")
+	cmd.Flags().BoolVar(&force, "force", false, "Roll back without confirmation")
+	cmd.Flags().BoolVar(&allowRoot, "allow-root", false, "Allow rolling back as root")
+
+	return cmd
+}
@@ -4,9 +4,46 @@
 package devenv
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/spf13/cobra"
 )
 
+// ExitError is returned by commands whose exit status carries meaning, such
+// as env diff, which follows diff(1). Err is nil when the status alone is the
+// result.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+// Error implements the error interface.
+func (e *ExitError) Error() string {
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// Unwrap returns the underlying error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the process exit status for an error returned by a
+// command: 0 for nil, the code of an ExitError, and 1 otherwise.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return 1
+}
+
 // NewRootCmd creates the root command for development environment management.
 // This command is designed to be used directly or wrapped by a parent CLI.
 func NewRootCmd() *cobra.Command {
@@ -31,6 +68,9 @@ This is useful when setting up new development machines, switching between
 projects, or maintaining consistent environments across multiple machines.
 
 Examples:
+  # Set up dev-env on a new machine
+  dev-env init
+
   # Show status of all development environment services
   dev-env status
 
@@ -40,6 +80,24 @@ Examples:
   # Switch all services to a named environment
   dev-env switch-all --env production
 
+  # Check environment files for typos before switching
+  dev-env validate
+
+  # Check that dev-env's files are private
+  dev-env doctor
+
+  # Print the environment of the last complete switch
+  dev-env current
+
+  # Show the active environment in the zsh prompt
+  eval "$(dev-env prompt --init zsh)"
+
+  # Compare two environments
+  dev-env env diff staging production
+
+  # List Kubernetes contexts, reusing a recent listing
+  dev-env targets kubernetes --cached
+
   # Save current kubeconfig
   dev-env kubeconfig save --name my-cluster
 
@@ -47,12 +105,30 @@ Examples:
   dev-env aws-profile list
   dev-env aws-profile switch production`,
 		SilenceUsage: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := enableCommandTrace(cmd); err != nil {
+				return err
+			}
+			return enableMockServices(cmd)
+		},
 	}
+	addMockFlag(cmd)
+	addTraceFlag(cmd)
 
 	// Add subcommands
 	cmd.AddCommand(newStatusCmd())
 	cmd.AddCommand(newTUICmd())
 	cmd.AddCommand(newSwitchAllCmd())
+	cmd.AddCommand(newEnvCmd())
+	cmd.AddCommand(newCurrentCmd())
+	cmd.AddCommand(newPromptCmd())
+	cmd.AddCommand(newTargetsCmd())
+	cmd.AddCommand(newInitCmd())
+	cmd.AddCommand(newValidateCmd())
+	cmd.AddCommand(newDoctorCmd())
+	cmd.AddCommand(newVerifySwitcherCmd())
+	cmd.AddCommand(newRestoreFilesCmd())
+	cmd.AddCommand(newRollbackCmd())
 
 	return cmd
 }
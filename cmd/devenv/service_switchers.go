@@ -4,32 +4,88 @@
 package devenv
 
 import (
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/aws"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/azure"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/docker"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/builtin"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/gcp"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/kubernetes"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/ssh"
 )
 
-// registerDefaultSwitchers registers all default service switchers.
+// registerDefaultSwitchers registers all default service switchers, the
+// command switchers defined in the settings, and the external switchers if
+// the settings enable them, or the mock services in mock mode. It also
+// sets the switch lock and applies the settings' hook allowlist and file
+// snapshots.
 func registerDefaultSwitchers(switcher *environment.EnvironmentSwitcher) {
-	// Register AWS switcher
-	switcher.RegisterServiceSwitcher("aws", aws.NewSwitcher())
+	switcher.SetSwitchLock(environment.NewSwitchLock(switchLockPath()))
+
+	if mockServices != nil {
+		for _, mock := range mockServices.Switchers() {
+			switcher.Register(mock)
+		}
+		return
+	}
 
-	// Register GCP switcher
-	switcher.RegisterServiceSwitcher("gcp", gcp.NewSwitcher())
+	for _, service := range builtin.Services() {
+		switcher.RegisterServiceSwitcher(service.Name, service.NewSwitcher())
+	}
 
-	// Register Azure switcher
-	switcher.RegisterServiceSwitcher("azure", azure.NewSwitcher())
+	settings, err := config.LoadSettings(config.DefaultSettingsPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
+	switcher.SetHookAllowlist(settings.HookAllowlist)
+	if settings.FileSnapshots {
+		switcher.SetFileSnapshots(environment.NewFileSnapshots(environment.DefaultFileSnapshotDir(), settings.FileSnapshotRetention))
+	}
+	registerCommandSwitchers(switcher, settings)
+	if settings.ExternalSwitchers {
+		registerExternalSwitchers(switcher, config.DefaultSwitchersDir())
+	}
+}
 
-	// Register Docker switcher
-	switcher.RegisterServiceSwitcher("docker", docker.NewSwitcher())
+// registerCommandSwitchers registers the command switchers defined in
+// settings. Invalid ones, and ones named like a registered service, are
+// skipped with a warning.
+func registerCommandSwitchers(switcher *environment.EnvironmentSwitcher, settings *config.Settings) {
+	names := make([]string, 0, len(settings.CommandSwitchers))
+	for name := range settings.CommandSwitchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if switcher.HasSwitcher(name) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping command switcher %q: service already exists\n", name)
+			continue
+		}
+		commandSwitcher, err := environment.NewCommandSwitcher(name, settings.CommandSwitchers[name])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			continue
+		}
+		switcher.Register(commandSwitcher)
+	}
+}
 
-	// Register Kubernetes switcher
-	switcher.RegisterServiceSwitcher("kubernetes", kubernetes.NewSwitcher())
+// registerExternalSwitchers registers the switcher executables found in
+// dir. An executable named like a registered service is skipped with a
+// warning, so a plugin cannot shadow a built-in service.
+func registerExternalSwitchers(switcher *environment.EnvironmentSwitcher, dir string) {
+	external, err := environment.DiscoverExecSwitchers(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return
+	}
 
-	// Register SSH switcher
-	switcher.RegisterServiceSwitcher("ssh", ssh.NewSwitcher())
+	for _, execSwitcher := range external {
+		if switcher.HasSwitcher(execSwitcher.Name()) {
+			fmt.Fprintf(os.Stderr, "Warning: skipping external switcher %s: service %q already exists\n", execSwitcher.Path(), execSwitcher.Name())
+			continue
+		}
+		switcher.Register(execSwitcher)
+	}
 }
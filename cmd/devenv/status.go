@@ -6,16 +6,15 @@ package devenv
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/spf13/cobra"
 
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/aws"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/azure"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/docker"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/gcp"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/kubernetes"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/builtin"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/ssh"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
@@ -27,8 +26,20 @@ func newStatusCmd() *cobra.Command {
 		format      string
 		checkHealth bool
 		watch       bool
+		sequential  bool
 		timeout     time.Duration
 		noColor     bool
+		timings     bool
+		groupBy     string
+		byStatus    bool
+		jumpChecks  []string
+		outputVer   string
+		logFile     string
+		logMaxSize  int
+		logMaxFiles int
+		maxAge      time.Duration
+		onlyChanged bool
+		explain     bool
 	)
 
 	cmd := &cobra.Command{
@@ -47,6 +58,9 @@ This command shows the status of various development environment services:
 The command provides color-coded status indicators, credential expiration
 warnings, and optional health checks for detailed service validation.
 
+Services are checked in parallel by default; --sequential checks them one
+at a time. (switch-all is the reverse: sequential unless --parallel.)
+
 Examples:
   # Show status of all services
   dev-env status
@@ -60,109 +74,260 @@ Examples:
   # Output status in JSON format
   dev-env status --format json
 
+  # Export DEVENV_<SERVICE>_STATUS variables into a shell script
+  eval "$(dev-env status --format env)"
+
+  # Output YAML with the field names used before they were pinned
+  dev-env status --format yaml --output-version legacy
+
+  # Show account, region, credential type, and health columns
+  dev-env status --format wide
+
+  # Section the table into active, inactive, error, and unknown services
+  dev-env status --group-by status
+
+  # Section the table into cloud, container, and access services
+  dev-env status --group-by category
+
+  # Check only the cloud services (aws, gcp, azure)
+  dev-env status --service @cloud
+
   # Watch status in real-time (updates every 30 seconds)
   dev-env status --watch
 
+  # Stay quiet until a status or credential changes
+  dev-env status --watch --only-changed
+
+  # Also append each change to a rotating JSON-lines log
+  dev-env status --watch --log-file ~/.gzh/dev-env/changes.jsonl
+
   # Show status without colors (for scripting)
-  dev-env status --no-color`,
+  dev-env status --no-color
+
+  # Show how long each service check took
+  dev-env status --timings
+
+  # Check services one at a time, e.g. to see which one is slow
+  dev-env status --sequential --timings
+
+  # Check that db1 is reachable through the bastion jump host
+  dev-env status --check-jump bastion:db1
+
+  # The same, with the bastion listening on port 2222
+  dev-env status --check-jump bastion:2222:db1
+
+  # Explain what is wrong with each service and how to fix it
+  dev-env status --explain
+
+  # Fail rather than print statuses checked more than a minute ago
+  dev-env status --format json --max-age 1m
+
+--max-age guards scripts against acting on stale data once statuses can
+be served from the status cache. Statuses checked live are always fresh,
+so until then it never fails.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runStatusCmd(services, format, checkHealth, watch, timeout, !noColor)
+			var changeLog *status.ChangeLogWriter
+			if logFile != "" {
+				if !watch {
+					return fmt.Errorf("--log-file requires --watch")
+				}
+				changeLog = status.NewChangeLogWriter(logFile, int64(logMaxSize)<<20, logMaxFiles)
+			}
+			if onlyChanged && !watch {
+				return fmt.Errorf("--only-changed requires --watch")
+			}
+			if explain && (watch || (format != "table" && format != "wide")) {
+				return fmt.Errorf("--explain requires the table or wide format without --watch; JSON and YAML output include each hint")
+			}
+			if byStatus {
+				if groupBy != "" && groupBy != "status" {
+					return fmt.Errorf("--group-by-status conflicts with --group-by %s", groupBy)
+				}
+				groupBy = "status"
+			}
+			options := status.NewStatusOptions(checkHealth, sequential)
+			options.MaxAge = maxAge
+			return runStatusCmd(services, jumpChecks, format, outputVer, options, watch, onlyChanged, timeout, !noColor, groupBy, timings, explain, changeLog)
 		},
 	}
 
-	cmd.Flags().StringSliceVarP(&services, "service", "s", nil, "Services to check (aws,gcp,azure,docker,kubernetes,ssh)")
-	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table,json,yaml)")
+	cmd.Flags().StringSliceVarP(&services, "service", "s", nil, "Services to check (aws,gcp,azure,docker,kubernetes,ssh,gpg,pkgmgr) or categories (@cloud,@container,@access,@custom)")
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format (table,wide,json,yaml,env)")
+	cmd.Flags().StringVar(&outputVer, "output-version", "stable", "YAML field names: stable (lowerCamelCase, as in JSON) or legacy")
 	cmd.Flags().BoolVar(&checkHealth, "check-health", false, "Perform detailed health checks")
 	cmd.Flags().BoolVar(&watch, "watch", false, "Watch mode - continuously update status")
+	cmd.Flags().BoolVar(&sequential, "sequential", false, "Check services one at a time instead of in parallel")
+	cmd.Flags().BoolVar(&onlyChanged, "only-changed", false, "In watch mode, print a snapshot only when it differs from the previous one")
+	cmd.Flags().StringVar(&logFile, "log-file", "", "In watch mode, append status changes to this file as JSON lines")
+	cmd.Flags().IntVar(&logMaxSize, "log-max-size", status.DefaultChangeLogMaxSize>>20, "Rotate the log file when it reaches this many megabytes")
+	cmd.Flags().IntVar(&logMaxFiles, "log-max-files", status.DefaultChangeLogMaxFiles, "Number of log files to keep, including the current one")
 	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for status checks")
 	cmd.Flags().BoolVar(&noColor, "no-color", false, "Disable colored output")
+	cmd.Flags().StringVar(&groupBy, "group-by", "", "Group table rows under a heading per status or category (table and wide formats)")
+	cmd.Flags().BoolVar(&byStatus, "group-by-status", false, "Group table rows under a heading per status")
+	_ = cmd.Flags().MarkDeprecated("group-by-status", "use --group-by status instead")
+	cmd.Flags().BoolVar(&timings, "timings", false, "Print per-service check timings after the status output")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Print the suggested fix for each service with a known problem after the status output")
+	cmd.Flags().DurationVar(&maxAge, "max-age", 0, "Fail if any status was checked longer ago than this (0 allows any age)")
+	cmd.Flags().StringSliceVar(&jumpChecks, "check-jump", nil, "Check connectivity to TARGET through SSH jump host HOST (HOST[:PORT]:TARGET[:PORT], repeatable)")
 
 	return cmd
 }
 
 // runStatusCmd executes the status command.
-func runStatusCmd(services []string, format string, checkHealth, watch bool, timeout time.Duration, useColor bool) error {
+func runStatusCmd(services, jumpChecks []string, format, outputVer string, options status.StatusOptions, watch, onlyChanged bool, timeout time.Duration, useColor bool, groupBy string, timings, explain bool, changeLog *status.ChangeLogWriter) error {
 	ctx := context.Background()
 
 	// Create service checkers
-	checkers := createServiceCheckers(services)
+	checkers, perService, err := createServiceCheckers(services)
+	if err != nil {
+		return err
+	}
+	options.PerService = perService
+	for _, spec := range jumpChecks {
+		jumpHost, target, err := ssh.ParseJumpSpec(spec)
+		if err != nil {
+			return err
+		}
+		checkers = append(checkers, ssh.NewJumpHostChecker(jumpHost, target))
+	}
 	if len(checkers) == 0 {
 		return fmt.Errorf("no valid services specified")
 	}
 
 	// Create status collector
-	collector := status.NewStatusCollector(checkers, timeout)
+	var recorder *status.TimingRecorder
+	var collectorOpts []status.CollectorOption
+	if timings {
+		recorder = &status.TimingRecorder{}
+		collectorOpts = append(collectorOpts, status.WithObserver(recorder.Observe))
+	}
+	// Write fresh statuses through to the status cache for dev-env prompt.
+	// A watch would hold the cache locked for as long as it runs.
+	if !watch && mockServices == nil {
+		cache, err := status.OpenStatusCache("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			defer cache.Close()
+			collectorOpts = append(collectorOpts, status.WithPersistentCache(cache))
+		}
+	}
+	collector := status.NewStatusCollector(checkers, timeout, collectorOpts...)
 
 	// Create formatter
-	formatter, err := createFormatter(format, useColor)
+	formatter, err := createFormatter(format, outputVer, useColor, groupBy)
 	if err != nil {
 		return fmt.Errorf("invalid format: %w", err)
 	}
 
 	if watch {
-		return runWatchMode(ctx, collector, formatter, checkHealth, timeout)
+		return runWatchMode(ctx, collector, formatter, options, timeout, onlyChanged, recorder, changeLog)
 	}
 
-	return runSingleCheck(ctx, collector, formatter, checkHealth)
+	return runSingleCheck(ctx, collector, formatter, options, recorder, explain)
 }
 
-// createServiceCheckers creates the appropriate service checkers.
-func createServiceCheckers(services []string) []status.ServiceChecker {
-	var checkers []status.ServiceChecker
-
-	// If no services specified, use all available services
-	if len(services) == 0 {
-		services = []string{"aws", "gcp", "azure", "docker", "kubernetes", "ssh"}
+// createServiceCheckers creates the checkers selected by service names and
+// "@category" selectors, or the settings' default services if none are
+// given, including external checkers if the settings enable them. It also
+// returns the per-service options from the settings, warning about services
+// no checker provides.
+func createServiceCheckers(services []string) ([]status.ServiceChecker, map[string]status.ServiceCheckOptions, error) {
+	settings, err := config.LoadSettings(config.DefaultSettingsPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		settings = &config.Settings{}
 	}
 
-	serviceSet := make(map[string]bool)
-	for _, service := range services {
-		serviceSet[strings.ToLower(strings.TrimSpace(service))] = true
+	all := builtin.Checkers()
+	if mockServices != nil {
+		all = mockServices.Checkers()
 	}
-
-	if serviceSet["aws"] {
-		checkers = append(checkers, aws.NewChecker())
+	if settings.ExternalCheckers {
+		all = appendExternalCheckers(all, config.DefaultCheckersDir())
 	}
-	if serviceSet["gcp"] {
-		checkers = append(checkers, gcp.NewChecker())
+	warnUnknownStatusChecks(settings.StatusChecks, all)
+
+	checkers, err := settings.SelectCheckers(all, services)
+	if err != nil {
+		return nil, nil, err
 	}
-	if serviceSet["azure"] {
-		checkers = append(checkers, azure.NewChecker())
+	return checkers, settings.StatusChecks, nil
+}
+
+// warnUnknownStatusChecks warns about statusChecks settings for services
+// that none of checkers provides.
+func warnUnknownStatusChecks(perService map[string]status.ServiceCheckOptions, checkers []status.ServiceChecker) {
+	for _, service := range status.UnknownServices(perService, checkers) {
+		fmt.Fprintf(os.Stderr, "Warning: statusChecks names unknown service %q\n", service)
 	}
-	if serviceSet["docker"] {
-		checkers = append(checkers, docker.NewChecker())
+}
+
+// appendExternalCheckers appends the checker executables found in dir.
+// An executable named like an existing checker is skipped with a warning,
+// so a plugin cannot shadow a built-in service.
+func appendExternalCheckers(checkers []status.ServiceChecker, dir string) []status.ServiceChecker {
+	external, err := status.DiscoverExecCheckers(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return checkers
 	}
-	if serviceSet["kubernetes"] || serviceSet["k8s"] {
-		checkers = append(checkers, kubernetes.NewChecker())
+
+	names := make(map[string]bool, len(checkers))
+	for _, checker := range checkers {
+		names[checker.Name()] = true
 	}
-	if serviceSet["ssh"] {
-		checkers = append(checkers, ssh.NewChecker())
+	for _, checker := range external {
+		if names[checker.Name()] {
+			fmt.Fprintf(os.Stderr, "Warning: skipping external checker %s: service %q already exists\n", checker.Path(), checker.Name())
+			continue
+		}
+		names[checker.Name()] = true
+		checkers = append(checkers, checker)
 	}
-
 	return checkers
 }
 
 // createFormatter creates the appropriate output formatter.
-func createFormatter(format string, useColor bool) (status.StatusFormatter, error) {
-	switch strings.ToLower(format) {
-	case "table":
-		return status.NewStatusTableFormatter(useColor), nil
+func createFormatter(format, outputVer string, useColor bool, groupBy string) (status.StatusFormatter, error) {
+	version, err := status.ParseOutputVersion(outputVer)
+	if err != nil {
+		return nil, err
+	}
+
+	format = strings.ToLower(format)
+	if groupBy != "" && format != "table" && format != "wide" {
+		return nil, fmt.Errorf("--group-by requires the table or wide format")
+	}
+
+	table := &status.StatusTableFormatter{UseColor: useColor, Wide: format == "wide"}
+	switch strings.ToLower(groupBy) {
+	case "":
+	case "status":
+		table.GroupByStatus = true
+	case "category":
+		table.GroupByCategory = true
+	default:
+		return nil, fmt.Errorf("unsupported grouping: %s (supported: status, category)", groupBy)
+	}
+
+	switch format {
+	case "table", "wide":
+		return table, nil
 	case "json":
 		return status.NewStatusJSONFormatter(true), nil
 	case "yaml", "yml":
-		return status.NewStatusYAMLFormatter(), nil
+		return &status.StatusYAMLFormatter{Version: version}, nil
+	case "env":
+		return status.NewStatusEnvFormatter(), nil
 	default:
-		return nil, fmt.Errorf("unsupported format: %s (supported: table, json, yaml)", format)
+		return nil, fmt.Errorf("unsupported format: %s (supported: table, wide, json, yaml, env)", format)
 	}
 }
 
 // runSingleCheck performs a single status check.
-func runSingleCheck(ctx context.Context, collector *status.StatusCollector, formatter status.StatusFormatter, checkHealth bool) error {
-	options := status.StatusOptions{
-		CheckHealth: checkHealth,
-		Parallel:    true,
-	}
-
+func runSingleCheck(ctx context.Context, collector *status.StatusCollector, formatter status.StatusFormatter, options status.StatusOptions, recorder *status.TimingRecorder, explain bool) error {
 	statuses, err := collector.CollectAll(ctx, options)
 	if err != nil {
 		return fmt.Errorf("failed to collect status: %w", err)
@@ -174,43 +339,102 @@ func runSingleCheck(ctx context.Context, collector *status.StatusCollector, form
 	}
 
 	fmt.Print(output)
+	printTimings(recorder)
+	if explain {
+		printExplanations(statuses)
+	}
 	return nil
 }
 
-// runWatchMode runs the status command in watch mode.
-func runWatchMode(ctx context.Context, collector *status.StatusCollector, formatter status.StatusFormatter, checkHealth bool, interval time.Duration) error {
+// printExplanations writes, for each service with a known problem, what is
+// wrong with it and the suggested fix its check attached.
+func printExplanations(statuses []status.ServiceStatus) {
+	fmt.Println("\nExplanations:")
+	explained := false
+	for _, st := range statuses {
+		if st.Hint == "" {
+			continue
+		}
+		problem := string(st.Status)
+		if st.Reason != "" {
+			problem = status.ReasonTitle(st.Reason)
+		}
+		fmt.Printf("  %s (%s): %s\n", st.Name, problem, st.Hint)
+		explained = true
+	}
+	if !explained {
+		fmt.Println("  No known problems found.")
+	}
+}
+
+// runWatchMode runs the status command in watch mode. On a terminal the
+// screen is redrawn each interval; otherwise timestamped snapshots are
+// appended so the output can be piped or redirected. Changes between
+// snapshots are listed under each redraw and, if changeLog is set, appended
+// to it. With onlyChanged, snapshots identical to the previous one are not
+// printed at all.
+func runWatchMode(ctx context.Context, collector *status.StatusCollector, formatter status.StatusFormatter, options status.StatusOptions, interval time.Duration, onlyChanged bool, recorder *status.TimingRecorder, changeLog *status.ChangeLogWriter) error {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	// Clear screen function
-	clearScreen := func() {
-		fmt.Print("\033[2J\033[H")
-	}
-
-	options := status.StatusOptions{
-		CheckHealth: checkHealth,
-		Parallel:    true,
-	}
+	out := status.NewWatchWriter(os.Stdout)
+	recent := status.NewChangeRing(recentChanges)
+	filter := &status.ChangeFilter{OnlyChanged: onlyChanged}
 
 	for {
-		clearScreen()
+		started := time.Now()
+		statuses, err := collector.CollectAll(ctx, options)
 
-		// Show current time
-		fmt.Printf("Last updated: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+		var changes []status.ChangeEvent
+		var logErr error
+		show := true
+		if err == nil {
+			changes, show = filter.Observe(statuses, time.Now())
+			recent.Add(changes...)
+			if changeLog != nil {
+				logErr = changeLog.Append(changes...)
+			}
+		}
 
-		statuses, err := collector.CollectAll(ctx, options)
-		if err != nil {
-			fmt.Printf("Error collecting status: %v\n", err)
+		if !show && logErr == nil {
+			// Drop the timings of the snapshot that is not printed.
+			if recorder != nil {
+				recorder.Take()
+			}
 		} else {
-			output, err := formatter.Format(statuses)
+			if err := out.Begin(started); err != nil {
+				return fmt.Errorf("failed to write output: %w", err)
+			}
+
 			if err != nil {
-				fmt.Printf("Error formatting output: %v\n", err)
+				fmt.Fprintf(out, "Error collecting status: %v\n", err)
 			} else {
-				fmt.Print(output)
+				if logErr != nil {
+					fmt.Fprintf(out, "Error writing change log: %v\n", logErr)
+				}
+
+				output, err := formatter.Format(statuses)
+				if err != nil {
+					fmt.Fprintf(out, "Error formatting output: %v\n", err)
+				} else {
+					fmt.Fprint(out, output)
+					printTimings(recorder)
+				}
+
+				if changes := recent.Events(); out.Terminal() && len(changes) > 0 {
+					fmt.Fprintln(out, "\nRecent changes:")
+					for _, change := range changes {
+						fmt.Fprintf(out, "  %s\n", change)
+					}
+				}
 			}
-		}
 
-		fmt.Println("\nPress Ctrl+C to exit watch mode")
+			if out.Terminal() {
+				fmt.Fprintln(out, "\nPress Ctrl+C to exit watch mode")
+			} else {
+				fmt.Fprintln(out)
+			}
+		}
 
 		select {
 		case <-ctx.Done():
@@ -220,3 +444,31 @@ func runWatchMode(ctx context.Context, collector *status.StatusCollector, format
 		}
 	}
 }
+
+// recentChanges is how many changes watch mode lists under the status.
+const recentChanges = 10
+
+// printTimings writes the timings recorded since the last call as a table,
+// slowest first. It is a no-op on a nil recorder.
+func printTimings(recorder *status.TimingRecorder) {
+	if recorder == nil {
+		return
+	}
+
+	timings := recorder.Take()
+	if len(timings) == 0 {
+		return
+	}
+
+	fmt.Println("\nCheck Timings:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SERVICE\tDURATION\tSTATUS\tERROR")
+	for _, timing := range timings {
+		errStr := "-"
+		if timing.Err != nil {
+			errStr = timing.Err.Error()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", timing.Service, timing.Duration.Round(time.Millisecond), timing.Status, errStr)
+	}
+	w.Flush()
+}
@@ -5,31 +5,71 @@ package devenv
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/difffmt"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 )
 
+// exitMixedState is switch-all's exit status when a failed switch could not
+// be fully rolled back, leaving services in different environments.
+const exitMixedState = 4
+
 // switchAllOptions contains options for the switch-all command.
 type switchAllOptions struct {
 	env         string
 	fromFile    string
+	source      string
 	dryRun      bool
+	check       bool
 	force       bool
 	interactive bool
 	parallel    bool
-	timeout     time.Duration
+	allowRoot   bool
+	partial     bool
+	strictHooks bool
+	noColor     bool
+	retryFailed bool
+	skipValid   bool
+	forceUnlock bool
+	otel        bool
+	timestamps  string
+
+	// notifyWebhook and notifyDesktop send a notification when the
+	// switch finishes.
+	notifyWebhook string
+	notifyDesktop bool
+	timeout       time.Duration
+
+	// set are path=value overrides applied to the loaded environment.
+	set []string
+
+	// recentWindow is how recent another user's switch must be to warn
+	// about it; negative means the settings or the default decide.
+	recentWindow time.Duration
+
+	// stdin is read by --from-file -.
+	stdin io.Reader
+
+	// store, if set, is where environments are loaded from instead of the
+	// store --source selects.
+	store environment.EnvStore
 }
 
 // newSwitchAllCmd creates the switch-all command.
 func newSwitchAllCmd() *cobra.Command {
 	opts := &switchAllOptions{
-		timeout: 5 * time.Minute,
+		timeout:      5 * time.Minute,
+		recentWindow: -1,
 	}
 
 	cmd := &cobra.Command{
@@ -46,48 +86,171 @@ This command provides atomic environment switching across all configured service
 - SSH configurations
 
 All services are switched atomically - either all succeed or all are rolled back.
+If the rollback itself fails, the services left in each environment are listed
+and the command exits with status 4.
+
+If another user switched this machine within the last 30 minutes (see
+--recent-window), the confirmation names them and when, so a colleague's
+environment is not switched away from mid-deployment by accident.
+
+Only one switch runs at a time: a second switch-all fails with "another
+switch is in progress" while the first holds ~/.gzh/dev-env/switch.lock.
+The lock is released when its holder exits, even if it crashes; use
+--force-unlock only to get past a switch that is hung.
+
+Without --env, --from-file, or --interactive, switch-all switches to the
+default environment: $DEVENV_DEFAULT, or defaultEnvironment in
+~/.gzh/dev-env/settings.yaml.
+
+--check makes every check a switch makes before changing anything, and
+reports whether the switch would be accepted, without querying or
+switching any service: the environment, service values, dependencies,
+registered switchers, hooks, service configurations, and the CLIs the
+switchers need. It exits non-zero if any check fails, for use as a CI gate.
+
+--set path=value overrides a field of the loaded environment before it is
+validated, such as services.kubernetes.kubernetes.namespace=pr-123. Values are
+parsed for the field's type (durations, booleans, numbers, comma-separated
+lists); quote path segments that contain dots. Overrides are shown by
+--dry-run and recorded in the switch history, and --retry-failed reuses them.
+
+--notify-webhook URL POSTs {"environment": ..., "result": ...}, the switch
+result as JSON, to URL when a switch finishes, successful or not, and
+--notify-desktop shows a desktop notification (notify-send on Linux,
+osascript on macOS). notifyWebhook and notifyDesktop in settings enable them
+for every switch. Notification failures are warnings.
+
+Environments are looked up in ~/.gzh/dev-env/environments, ./environments,
+and the current directory, or only in the directory given by --source, as a
+path or a file:// URL.
 
 Examples:
-  # Switch to production environment
+  # Switch to the default environment
+  dev-env switch-all
+
+  # Switch to production environment (names are case-insensitive and
+  # may be any alias declared in the environment file)
   dev-env switch-all --env production
 
   # Preview changes without applying
   dev-env switch-all --env production --dry-run
 
+  # Switch to staging with a per-PR namespace
+  dev-env switch-all --env staging --set services.kubernetes.kubernetes.namespace=pr-123
+
+  # Check that a switch to production would be accepted
+  dev-env switch-all --env production --check
+
   # Switch using environment file
   dev-env switch-all --from-file production.yaml
 
+  # Switch to an environment kept in a shared checkout
+  dev-env switch-all --env staging --source file:///srv/team-envs
+
+  # Switch to a generated environment read from standard input
+  generate-env | dev-env switch-all --from-file - --force
+
   # Interactive environment selection
   dev-env switch-all --interactive
 
   # Force switch without confirmation
-  dev-env switch-all --env dev --force`,
+  dev-env switch-all --env dev --force
+
+  # Switch what can be switched, reporting failed services at the end
+  dev-env switch-all --env dev --partial-success
+
+  # Switch to a region newer than the built-in AWS region list
+  dev-env switch-all --env dev --skip-validation
+
+  # Re-attempt only the services that failed in the last partial switch
+  dev-env switch-all --retry-failed
+
+  # Get a desktop notification and a chat webhook call when done
+  dev-env switch-all --env prod --notify-desktop --notify-webhook https://hooks.example.com/devenv
+
+  # Timestamp progress lines to match them against other logs
+  dev-env switch-all --env dev --timestamps=rfc3339`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.stdin = cmd.InOrStdin()
 			return opts.run(cmd.Context())
 		},
 	}
 
 	cmd.Flags().StringVar(&opts.env, "env", "", "Environment name to switch to")
-	cmd.Flags().StringVar(&opts.fromFile, "from-file", "", "Environment configuration file")
+	cmd.Flags().StringVar(&opts.fromFile, "from-file", "", "Environment configuration file, or - to read it from standard input")
+	cmd.Flags().StringVar(&opts.source, "source", "", "Where to find environments: a directory or file:// URL (default: the standard search paths)")
 	cmd.Flags().BoolVar(&opts.dryRun, "dry-run", false, "Preview changes without applying")
+	cmd.Flags().BoolVar(&opts.check, "check", false, "Check that the switch would be accepted without querying or switching any service")
 	cmd.Flags().BoolVar(&opts.force, "force", false, "Force switch without confirmation")
 	cmd.Flags().BoolVar(&opts.interactive, "interactive", false, "Interactive environment selection")
-	cmd.Flags().BoolVar(&opts.parallel, "parallel", false, "Enable parallel service switching")
+	cmd.Flags().BoolVar(&opts.parallel, "parallel", false, "Switch independent services in parallel (default: one at a time)")
+	cmd.Flags().BoolVar(&opts.allowRoot, "allow-root", false, "Allow switching (and running hooks) as root")
+	cmd.Flags().BoolVar(&opts.partial, "partial-success", false, "Keep switching remaining services when one fails")
+	cmd.Flags().BoolVar(&opts.strictHooks, "strict-hooks", false, "Report the switch as failed if a hook with onError continue fails")
+	cmd.Flags().BoolVar(&opts.noColor, "no-color", false, "Disable colored dry-run output")
+	cmd.Flags().BoolVar(&opts.skipValid, "skip-validation", false, "Skip checking regions, namespaces, and other values before switching")
+	cmd.Flags().BoolVar(&opts.retryFailed, "retry-failed", false, "Retry only the services that failed in the last switch")
+	cmd.Flags().BoolVar(&opts.forceUnlock, "force-unlock", false, "Break the lock held by another, hung switch before switching")
+	cmd.Flags().BoolVar(&opts.otel, "otel", false, "Export the switch as OpenTelemetry spans and metrics to the endpoint set by OTEL_* variables")
+	cmd.Flags().StringVar(&opts.notifyWebhook, "notify-webhook", "", "POST the switch result as JSON to this http(s) URL when the switch finishes (default: notifyWebhook in settings)")
+	cmd.Flags().BoolVar(&opts.notifyDesktop, "notify-desktop", false, "Show a desktop notification when the switch finishes")
+	cmd.Flags().StringArrayVar(&opts.set, "set", nil, "Override a field of the environment as path=value, such as services.kubernetes.kubernetes.namespace=pr-123 (repeatable)")
 	cmd.Flags().DurationVar(&opts.timeout, "timeout", opts.timeout, "Operation timeout")
+	cmd.Flags().DurationVar(&opts.recentWindow, "recent-window", opts.recentWindow, "Warn about switches by other users of this account this recent (default 30m or recentSwitchWindow in settings, 0 disables)")
+	cmd.Flags().StringVar(&opts.timestamps, "timestamps", "", "Prefix progress and result lines with the time: clock (HH:MM:SS, the default) or rfc3339")
+	cmd.Flags().Lookup("timestamps").NoOptDefVal = "clock"
 
 	// Make env and from-file mutually exclusive
 	cmd.MarkFlagsMutuallyExclusive("env", "from-file", "interactive")
+	cmd.MarkFlagsMutuallyExclusive("retry-failed", "interactive")
+	cmd.MarkFlagsMutuallyExclusive("check", "dry-run", "retry-failed")
+
+	_ = cmd.RegisterFlagCompletionFunc("env", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return environment.ListEnvironmentNames(environmentSearchPaths()), cobra.ShellCompDirectiveNoFileComp
+	})
 
 	return cmd
 }
 
 // run executes the switch-all command.
 func (opts *switchAllOptions) run(ctx context.Context) error {
+	ctx = switchContext(ctx)
+	history := switchHistory()
+
+	// Retry against the last switched environment unless one is named
+	if opts.retryFailed && opts.env == "" && opts.fromFile == "" {
+		last, err := history.Last()
+		if err != nil {
+			return err
+		}
+		if last == nil || len(last.Failed) == 0 {
+			fmt.Println("✅ Nothing to retry: the last switch has no failed services")
+			return nil
+		}
+		opts.env = last.Environment
+		// Retry with the overrides of the failed switch unless others are
+		// given.
+		if len(opts.set) == 0 {
+			opts.set = last.Overrides
+		}
+	}
+
 	// Load environment configuration
-	env, err := opts.loadEnvironment()
+	store, err := opts.openStore()
+	if err != nil {
+		return err
+	}
+	env, err := opts.loadEnvironment(ctx, store)
 	if err != nil {
 		return fmt.Errorf("failed to load environment: %w", err)
 	}
+	overrides, err := environment.ParseOverrides(opts.set)
+	if err != nil {
+		return err
+	}
+	if err := env.ApplyOverrides(overrides); err != nil {
+		return err
+	}
 
 	// Initialize environment switcher
 	switcher := environment.NewEnvironmentSwitcher()
@@ -96,7 +259,27 @@ func (opts *switchAllOptions) run(ctx context.Context) error {
 	registerDefaultSwitchers(switcher)
 
 	// Set up progress reporting
-	switcher.SetProgressCallback(opts.reportProgress)
+	reporter := environment.NewResultReporter(os.Stdout)
+	if opts.timestamps != "" {
+		layout, err := environment.ParseTimestampLayout(opts.timestamps)
+		if err != nil {
+			return err
+		}
+		reporter.SetTimestamps(layout)
+	}
+	switcher.SetProgressCallback(reporter.ReportProgress)
+	switcher.SetServiceEventCallback(reporter.ReportServiceEvent)
+	switcher.SetHistory(history)
+	switcher.SetCurrentMarker(currentPath())
+	switcher.SetValueValidator(valueValidator())
+	if opts.check {
+		return opts.runCheck(switcher, env)
+	}
+	notifiers, err := switchNotifiers(opts.notifyWebhook, opts.notifyDesktop)
+	if err != nil {
+		return err
+	}
+	defer enableTelemetry(ctx, switcher, opts.otel)()
 
 	// Prepare switch options
 	switchOptions := environment.SwitchOptions{
@@ -105,11 +288,35 @@ func (opts *switchAllOptions) run(ctx context.Context) error {
 		Parallel:        opts.parallel,
 		RollbackOnError: true,
 		Timeout:         opts.timeout,
+		AllowRoot:       opts.allowRoot,
+		PartialSuccess:  opts.partial,
+		StrictHooks:     opts.strictHooks,
+		SkipValidation:  opts.skipValid,
+		Overrides:       opts.set,
+	}
+
+	if opts.dryRun {
+		printOverrides(overrides)
+	}
+	// Plan and drift detection share one state read per service
+	switcher.SetStateCache(environment.NewStateCache())
+	if opts.dryRun && !opts.retryFailed {
+		if err := opts.printPlan(ctx, switcher, env); err != nil {
+			return err
+		}
+	}
+
+	var drifts []environment.Drift
+	if (opts.dryRun || !opts.force) && !opts.retryFailed {
+		drifts = unmanagedDrift(ctx, switcher, history, env)
+	}
+	if opts.dryRun {
+		printDrift(drifts, env)
 	}
 
 	// Confirm operation if not forced or dry-run
 	if !opts.force && !opts.dryRun {
-		if err := opts.confirmSwitch(env); err != nil {
+		if err := opts.confirmSwitch(env, opts.recentSwitchWarning(history), drifts); err != nil {
 			return err
 		}
 	}
@@ -119,88 +326,175 @@ func (opts *switchAllOptions) run(ctx context.Context) error {
 	defer cancel()
 
 	// Perform the switch
-	fmt.Printf("🔄 Switching to environment: %s\n", env.Name)
+	if opts.retryFailed {
+		fmt.Printf("🔁 Retrying failed services for environment: %s\n", env.Name)
+	} else {
+		fmt.Printf("🔄 Switching to environment: %s\n", env.Name)
+	}
 	if opts.dryRun {
 		fmt.Println("👁️  DRY-RUN MODE: No changes will be made")
 	}
 
-	result, err := switcher.SwitchEnvironment(ctx, env, switchOptions)
+	if opts.forceUnlock && !opts.dryRun {
+		if err := environment.NewSwitchLock(switchLockPath()).ForceUnlock(); err != nil {
+			return err
+		}
+		fmt.Println("🔓 Removed the switch lock")
+	}
+
+	var result *environment.SwitchResult
+	if opts.retryFailed {
+		result, err = switcher.RetryFailed(ctx, env, switchOptions)
+	} else {
+		result, err = switcher.SwitchEnvironment(ctx, env, switchOptions)
+	}
+	if result != nil && !opts.dryRun {
+		sendNotifications(ctx, notifiers, env.Name, result)
+	}
+	if errors.Is(err, environment.ErrSwitchInProgress) {
+		return fmt.Errorf("%w; wait for it to finish, or use --force-unlock if it is hung", err)
+	}
 	if err != nil {
+		if result != nil {
+			reporter.Report(result)
+			if reporter.ReportMixedState(result, env) {
+				return &ExitError{Code: exitMixedState, Err: fmt.Errorf("environment switch failed: %w", err)}
+			}
+		}
 		return fmt.Errorf("environment switch failed: %w", err)
 	}
 
 	// Display results
-	opts.displayResults(result)
+	reporter.Report(result)
 
 	if !result.Success {
 		return fmt.Errorf("environment switch completed with errors")
 	}
 
+	if result.Partial {
+		unswitched := len(result.FailedServices) + len(result.SkippedServices)
+		fmt.Printf("⚠️  Partially switched to environment: %s (%d of %d services failed or were skipped)\n",
+			env.Name, unswitched, unswitched+len(result.SwitchedServices))
+		if !opts.dryRun {
+			fmt.Println("   Run 'dev-env switch-all --retry-failed' to retry the failed services")
+		}
+		return nil
+	}
+
 	fmt.Printf("✅ Successfully switched to environment: %s\n", env.Name)
 	return nil
 }
 
-// loadEnvironment loads the environment configuration.
-func (opts *switchAllOptions) loadEnvironment() (*environment.Environment, error) {
-	var data []byte
+// runCheck prints the pre-flight checks of a switch to env, returning an
+// error if any failed.
+func (opts *switchAllOptions) runCheck(switcher *environment.EnvironmentSwitcher, env *environment.Environment) error {
+	checks := switcher.Preflight(env, environment.SwitchOptions{
+		AllowRoot:      opts.allowRoot,
+		SkipValidation: opts.skipValid,
+	})
+
+	fmt.Printf("🔍 Checking switch to environment: %s\n", env.Name)
+	failed := 0
+	for _, check := range checks {
+		if check.Passed() {
+			fmt.Printf("   ✅ %s\n", check.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("   ❌ %s\n", check.Name)
+		for _, problem := range check.Problems {
+			fmt.Printf("      %s\n", problem)
+		}
+	}
+
+	if failed > 0 {
+		return &ExitError{Code: 1, Err: fmt.Errorf("switch to %s would be rejected: %d of %d checks failed", env.Name, failed, len(checks))}
+	}
+	fmt.Printf("✅ Switch to %s would be accepted\n", env.Name)
+	return nil
+}
+
+// openStore returns the store environments are loaded from: opts.store if
+// set, otherwise the one --source selects.
+func (opts *switchAllOptions) openStore() (environment.EnvStore, error) {
+	if opts.store != nil {
+		return opts.store, nil
+	}
+	return environment.OpenEnvStore(opts.source, environmentSearchPaths())
+}
+
+// loadEnvironment loads the environment configuration, resolving names and
+// composed environments in store.
+func (opts *switchAllOptions) loadEnvironment(ctx context.Context, store environment.EnvStore) (*environment.Environment, error) {
+	var env *environment.Environment
 	var err error
 
 	switch {
 	case opts.interactive:
-		return opts.selectEnvironmentInteractively()
+		env, err = opts.selectEnvironmentInteractively(ctx, store)
+		if err != nil {
+			return nil, err
+		}
+	case opts.fromFile == "-":
+		// Standard input holds the environment, so it cannot answer the
+		// confirmation prompt.
+		if !opts.force && !opts.dryRun && !opts.check {
+			return nil, fmt.Errorf("--from-file - requires --force, --dry-run, or --check, since standard input cannot also confirm the switch")
+		}
+		env, err = environment.LoadEnvironmentFromReader(opts.stdin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load environment from standard input: %w", err)
+		}
 	case opts.fromFile != "":
-		data, err = os.ReadFile(opts.fromFile)
+		data, err := os.ReadFile(opts.fromFile)
 		if err != nil {
 			return nil, fmt.Errorf("failed to read environment file %s: %w", opts.fromFile, err)
 		}
-	case opts.env != "":
-		envFile := opts.findEnvironmentFile(opts.env)
-		if envFile == "" {
-			return nil, fmt.Errorf("environment '%s' not found", opts.env)
+		env, err = environment.LoadEnvironment(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse environment configuration: %w", err)
 		}
-		data, err = os.ReadFile(envFile)
+	case opts.env != "":
+		env, err = store.Load(ctx, opts.env)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read environment file %s: %w", envFile, err)
+			return nil, err
 		}
 	default:
-		return nil, fmt.Errorf("must specify --env, --from-file, or --interactive")
+		name := opts.defaultEnvironment()
+		if name == "" {
+			return nil, fmt.Errorf("must specify --env, --from-file, or --interactive, or set a default with %s or defaultEnvironment in settings", config.DefaultEnvironmentEnvVar)
+		}
+		opts.env = name
+		return opts.loadEnvironment(ctx, store)
 	}
 
-	env, err := environment.LoadEnvironment(data)
+	return environment.ExpandComposition(env, environment.StoreResolver(ctx, store))
+}
+
+// defaultEnvironment returns the environment to switch to when none is
+// selected, or "" if there is no default.
+func (opts *switchAllOptions) defaultEnvironment() string {
+	settings, err := config.LoadSettings(config.DefaultSettingsPath())
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse environment configuration: %w", err)
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		settings = &config.Settings{}
 	}
-
-	return env, nil
+	return settings.EnvironmentOrDefault("")
 }
 
-// findEnvironmentFile finds the environment configuration file.
-func (opts *switchAllOptions) findEnvironmentFile(envName string) string {
-	// Search paths for environment files
-	searchPaths := []string{
+// environmentSearchPaths returns the directories searched for environment files.
+func environmentSearchPaths() []string {
+	return []string{
 		filepath.Join(os.Getenv("HOME"), ".gzh", "dev-env", "environments"),
 		filepath.Join(".", "environments"),
 		".",
 	}
-
-	extensions := []string{".yaml", ".yml"}
-
-	for _, searchPath := range searchPaths {
-		for _, ext := range extensions {
-			filename := filepath.Join(searchPath, envName+ext)
-			if _, err := os.Stat(filename); err == nil {
-				return filename
-			}
-		}
-	}
-
-	return ""
 }
 
 // selectEnvironmentInteractively allows interactive environment selection.
-func (opts *switchAllOptions) selectEnvironmentInteractively() (*environment.Environment, error) {
+func (opts *switchAllOptions) selectEnvironmentInteractively(ctx context.Context, store environment.EnvStore) (*environment.Environment, error) {
 	// Find available environments
-	environments, err := opts.findAvailableEnvironments()
+	environments, err := store.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find available environments: %w", err)
 	}
@@ -213,6 +507,9 @@ func (opts *switchAllOptions) selectEnvironmentInteractively() (*environment.Env
 	fmt.Println("Available environments:")
 	for i, env := range environments {
 		fmt.Printf("  %d. %s", i+1, env.Name)
+		if len(env.Aliases) > 0 {
+			fmt.Printf(" (aliases: %s)", strings.Join(env.Aliases, ", "))
+		}
 		if env.Description != "" {
 			fmt.Printf(" - %s", env.Description)
 		}
@@ -230,54 +527,40 @@ func (opts *switchAllOptions) selectEnvironmentInteractively() (*environment.Env
 		return nil, fmt.Errorf("selection out of range")
 	}
 
-	return &environments[selection-1], nil
+	return environments[selection-1], nil
 }
 
-// findAvailableEnvironments finds all available environment configurations.
-func (opts *switchAllOptions) findAvailableEnvironments() ([]environment.Environment, error) {
-	envDir := filepath.Join(os.Getenv("HOME"), ".gzh", "dev-env", "environments")
+// recentSwitchWarning describes the last switch if another user made it
+// within the recent switch window, or returns "". Only the newest entry of
+// this account's history is consulted, so only users sharing the account,
+// such as through sudo, are seen.
+func (opts *switchAllOptions) recentSwitchWarning(history *environment.History) string {
+	window := opts.recentWindow
+	if window < 0 {
+		window = environment.DefaultRecentSwitchWindow
+		if settings, err := config.LoadSettings(config.DefaultSettingsPath()); err == nil && settings.RecentSwitchWindow > 0 {
+			window = settings.RecentSwitchWindow
+		}
+	}
 
-	entries, err := os.ReadDir(envDir)
+	last, err := history.Last()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read environments directory: %w", err)
+		return ""
 	}
-
-	environments := make([]environment.Environment, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-
-		filename := entry.Name()
-		if !isYAMLFile(filename) {
-			continue
-		}
-
-		envPath := filepath.Join(envDir, filename)
-		data, err := os.ReadFile(envPath)
-		if err != nil {
-			continue // Skip files that can't be read
-		}
-
-		env, err := environment.LoadEnvironment(data)
-		if err != nil {
-			continue // Skip invalid environment files
-		}
-
-		environments = append(environments, *env)
+	now := time.Now()
+	if !environment.RecentSwitchByOther(last, environment.CurrentUser(), window, now) {
+		return ""
 	}
-
-	return environments, nil
-}
-
-// isYAMLFile checks if a filename has a YAML extension.
-func isYAMLFile(filename string) bool {
-	ext := filepath.Ext(filename)
-	return ext == ".yaml" || ext == ".yml"
+	return environment.RecentSwitchWarning(last, now)
 }
 
-// confirmSwitch asks for user confirmation.
-func (opts *switchAllOptions) confirmSwitch(env *environment.Environment) error {
+// confirmSwitch asks for user confirmation, leading with warning if set
+// and listing the drifted services the switch leaves alone.
+func (opts *switchAllOptions) confirmSwitch(env *environment.Environment, warning string, drifts []environment.Drift) error {
+	if warning != "" {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	printDrift(drifts, env)
 	fmt.Printf("🔄 About to switch to environment: %s\n", env.Name)
 	if env.Description != "" {
 		fmt.Printf("   Description: %s\n", env.Description)
@@ -286,7 +569,11 @@ func (opts *switchAllOptions) confirmSwitch(env *environment.Environment) error
 	services := env.GetServiceNames()
 	fmt.Printf("   Services: %v\n", services)
 
-	fmt.Print("Continue? [y/N]: ")
+	if len(drifts) > 0 {
+		fmt.Print("Continue, leaving the drifted services as they are? [y/N]: ")
+	} else {
+		fmt.Print("Continue? [y/N]: ")
+	}
 	var response string
 	fmt.Scanln(&response)
 
@@ -297,42 +584,57 @@ func (opts *switchAllOptions) confirmSwitch(env *environment.Environment) error
 	return nil
 }
 
-// reportProgress reports switching progress.
-func (opts *switchAllOptions) reportProgress(progress environment.SwitchProgress) {
-	percentage := float64(progress.CompletedServices) / float64(progress.TotalServices) * 100
-	fmt.Printf("⏳ Progress: %.1f%% (%d/%d) - %s\n",
-		percentage,
-		progress.CompletedServices,
-		progress.TotalServices,
-		progress.Status)
-
-	if progress.CurrentService != "" {
-		fmt.Printf("   Current: %s\n", progress.CurrentService)
+// unmanagedDrift returns the services that have drifted from the last
+// switch and that a switch to env leaves alone. Drift is advisory, so if
+// the last switch's configurations cannot be read there is none, with a
+// warning.
+func unmanagedDrift(ctx context.Context, switcher *environment.EnvironmentSwitcher, history *environment.History, env *environment.Environment) []environment.Drift {
+	baseline, err := history.DriftBaseline(env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: cannot check for drift since the last switch: %v\n", err)
+		return nil
 	}
+	if baseline == nil {
+		return nil
+	}
+	return switcher.Drift(ctx, baseline, nil)
 }
 
-// displayResults displays the switching results.
-func (opts *switchAllOptions) displayResults(result *environment.SwitchResult) {
-	fmt.Printf("\n📊 Switch Results:\n")
-	fmt.Printf("   Duration: %v\n", result.Duration)
-	fmt.Printf("   Success: %v\n", result.Success)
-
-	if len(result.SwitchedServices) > 0 {
-		fmt.Printf("   ✅ Switched: %v\n", result.SwitchedServices)
+// printDrift lists drifted services that a switch to env leaves alone.
+func printDrift(drifts []environment.Drift, env *environment.Environment) {
+	if len(drifts) == 0 {
+		return
+	}
+	fmt.Printf("⚠️  Changed since the last switch and not managed by %s:\n", env.Name)
+	for _, drift := range drifts {
+		fmt.Printf("   %s\n", drift.Summary())
 	}
+}
 
-	if len(result.FailedServices) > 0 {
-		fmt.Printf("   ❌ Failed: %v\n", result.FailedServices)
+// printOverrides prints the overrides applied to the environment, if any.
+func printOverrides(overrides []environment.Override) {
+	if len(overrides) == 0 {
+		return
+	}
+	fmt.Println("🔧 Overrides:")
+	for _, override := range overrides {
+		fmt.Printf("   %s\n", override)
 	}
+}
 
-	if result.RollbackPerformed {
-		fmt.Printf("   🔄 Rollback: Performed\n")
+// printPlan prints the service fields a switch to env would change.
+func (opts *switchAllOptions) printPlan(ctx context.Context, switcher *environment.EnvironmentSwitcher, env *environment.Environment) error {
+	diffs, err := switcher.Plan(ctx, env)
+	if err != nil {
+		return fmt.Errorf("failed to plan switch: %w", err)
 	}
 
-	if len(result.Errors) > 0 {
-		fmt.Printf("\n❌ Errors:\n")
-		for _, err := range result.Errors {
-			fmt.Printf("   [%s] %s: %s\n", err.Time.Format("15:04:05"), err.Service, err.Error)
-		}
+	if len(diffs) == 0 {
+		fmt.Println("📋 Planned changes: none (already up to date)")
+		return nil
 	}
+
+	fmt.Println("📋 Planned changes:")
+	fmt.Print(difffmt.New(!opts.noColor).Fields(fieldChanges(diffs)))
+	return nil
 }
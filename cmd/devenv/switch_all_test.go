@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// memoryStore is an EnvStore holding environments in memory.
+type memoryStore struct {
+	envs []*environment.Environment
+}
+
+func (m memoryStore) List(context.Context) ([]*environment.Environment, error) { return m.envs, nil }
+
+func (m memoryStore) Load(_ context.Context, name string) (*environment.Environment, error) {
+	for _, env := range m.envs {
+		if strings.EqualFold(env.Name, name) || env.HasAlias(name) {
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("environment '%s' not found", name)
+}
+
+// TestSwitchAllOptions_LoadEnvironment tests loading environments, and the
+// environments they compose, from an injected store.
+func TestSwitchAllOptions_LoadEnvironment(t *testing.T) {
+	store := &memoryStore{envs: []*environment.Environment{
+		{
+			Name:     "base",
+			Services: map[string]environment.ServiceConfig{"docker": {Docker: &environment.DockerConfig{Context: "shared"}}},
+		},
+		{
+			Name:     "staging",
+			Aliases:  []string{"stg"},
+			Compose:  []string{"base"},
+			Services: map[string]environment.ServiceConfig{"kubernetes": {Kubernetes: &environment.KubernetesConfig{Context: "staging"}}},
+		},
+	}}
+
+	file := filepath.Join(t.TempDir(), "local.yaml")
+	content := "name: local\ncompose: [base]\nservices:\n  kubernetes:\n    context: kind\n"
+	if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		opts         switchAllOptions
+		wantName     string
+		wantServices []string
+		wantErr      bool
+	}{
+		{"by name", switchAllOptions{env: "staging"}, "staging", []string{"docker", "kubernetes"}, false},
+		{"by alias", switchAllOptions{env: "stg"}, "staging", []string{"docker", "kubernetes"}, false},
+		{"file composing from the store", switchAllOptions{fromFile: file}, "local", []string{"docker", "kubernetes"}, false},
+		{"missing", switchAllOptions{env: "prod"}, "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.store = store
+			got, err := opts.openStore()
+			if err != nil {
+				t.Fatalf("openStore() error = %v", err)
+			}
+			if got != environment.EnvStore(store) {
+				t.Fatalf("openStore() = %v, want the injected store", got)
+			}
+
+			env, err := opts.loadEnvironment(context.Background(), got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("loadEnvironment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			services := env.GetServiceNames()
+			sort.Strings(services)
+			if env.Name != tt.wantName || strings.Join(services, ",") != strings.Join(tt.wantServices, ",") {
+				t.Errorf("loadEnvironment() = %s with %v, want %s with %v", env.Name, services, tt.wantName, tt.wantServices)
+			}
+		})
+	}
+}
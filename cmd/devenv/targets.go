@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newTargetsCmd creates the targets command.
+func newTargetsCmd() *cobra.Command {
+	var (
+		cached bool
+		ttl    time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "targets [service...]",
+		Short: "List the targets each service can switch to",
+		Long: `List the targets each service can switch to: AWS profiles, GCP projects,
+Azure subscriptions, Docker contexts, and Kubernetes contexts.
+
+Listing targets can take several seconds for gcloud and az. With --cached,
+targets fetched within --ttl are read from ~/.gzh/dev-env/targets.json
+instead, and fresh results are written back to it.
+
+Examples:
+  # List targets for all services
+  dev-env targets
+
+  # List Kubernetes contexts, reusing a recent listing
+  dev-env targets kubernetes --cached`,
+		ValidArgs: []string{"aws", "gcp", "azure", "docker", "kubernetes"},
+		Args:      cobra.OnlyValidArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runTargets(cmd, args, cached, ttl)
+		},
+	}
+
+	cmd.Flags().BoolVar(&cached, "cached", false, "Reuse recently fetched targets from the on-disk cache")
+	cmd.Flags().DurationVar(&ttl, "ttl", environment.DefaultTargetCacheTTL, "How long cached targets stay fresh")
+
+	return cmd
+}
+
+// runTargets prints the targets of each requested service.
+func runTargets(cmd *cobra.Command, services []string, cached bool, ttl time.Duration) error {
+	if len(services) == 0 {
+		services = []string{"aws", "gcp", "azure", "docker", "kubernetes"}
+	}
+
+	switcher := environment.NewEnvironmentSwitcher()
+	registerDefaultSwitchers(switcher)
+
+	var cache environment.TargetCache = environment.NewMemoryTargetCache()
+	if cached {
+		cache = environment.NewFileTargetCache("")
+	}
+
+	failed := 0
+	for _, service := range services {
+		snapshot, ok := cache.Get(service)
+		if !cached || !ok || !snapshot.Fresh(ttl, time.Now()) {
+			var err error
+			snapshot, err = switcher.FetchTargets(cmd.Context(), service, cache)
+			if err != nil {
+				fmt.Printf("%s: %v\n", service, err)
+				failed++
+				continue
+			}
+		}
+
+		fmt.Printf("%s (as of %s)\n", service, snapshot.FetchedAt.Format("15:04"))
+		for _, target := range snapshot.Targets {
+			fmt.Printf("  %s\n", target)
+		}
+	}
+
+	if failed == len(services) {
+		return fmt.Errorf("failed to list targets for any service")
+	}
+	return nil
+}
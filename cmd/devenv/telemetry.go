@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/telemetry"
+)
+
+// telemetryFlushTimeout bounds how long exiting waits to export telemetry.
+const telemetryFlushTimeout = 5 * time.Second
+
+// enableTelemetry reports the switches of switcher with OpenTelemetry if
+// enabled (--otel) or openTelemetry is set in settings, and returns the
+// function that exports what was recorded, to call once switching is done.
+// Export problems are warnings: they must not fail the switch.
+func enableTelemetry(ctx context.Context, switcher *environment.EnvironmentSwitcher, enabled bool) func() {
+	if !enabled {
+		settings, err := config.LoadSettings(config.DefaultSettingsPath())
+		enabled = err == nil && settings.OpenTelemetry
+	}
+	if !enabled {
+		return func() {}
+	}
+
+	t, shutdown, err := telemetry.Setup(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return func() {}
+	}
+	switcher.SetTelemetry(t)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		fmt.Fprintf(os.Stderr, "Warning: telemetry: %v\n", err)
+	}))
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), telemetryFlushTimeout)
+		defer cancel()
+		if err := shutdown(ctx); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to export telemetry: %v\n", err)
+		}
+	}
+}
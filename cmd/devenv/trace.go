@@ -0,0 +1,38 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// addTraceFlag adds the --trace-commands flag. Alone it traces to standard
+// error; --trace-commands=FILE appends to FILE instead.
+func addTraceFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().String("trace-commands", "", "Log every external command run as a JSON line to standard error, or to a file with --trace-commands=FILE")
+	cmd.PersistentFlags().Lookup("trace-commands").NoOptDefVal = "-"
+}
+
+// enableCommandTrace starts the command trace selected by --trace-commands
+// or, if the flag is not given, GZH_TRACE_COMMANDS.
+func enableCommandTrace(cmd *cobra.Command) error {
+	spec := os.Getenv(environment.TraceCommandsEnvVar)
+	if flag := cmd.Flags().Lookup("trace-commands"); flag != nil && flag.Changed {
+		spec = flag.Value.String()
+	}
+	if spec == "" || spec == "0" || spec == "false" {
+		return nil
+	}
+
+	w, err := environment.OpenCommandTrace(spec)
+	if err != nil {
+		return err
+	}
+	environment.SetCommandTracer(environment.NewCommandTracer(w))
+	return nil
+}
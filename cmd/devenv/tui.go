@@ -11,6 +11,8 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/tui"
 )
 
@@ -41,12 +43,17 @@ Navigation:
   L            View logs
   P            Settings/preferences
   /            Search
+  o            Toggle raw CLI output (service details)
+  g            Group services by category
   ?            Toggle help
 
 Examples:
   # Launch the TUI dashboard
   dev-env tui
 
+  # Show only some services, instead of the default ones
+  dev-env tui --service aws,@container
+
   # Launch TUI with verbose logging (for debugging)
   dev-env tui --verbose`,
 		SilenceUsage: true,
@@ -54,6 +61,7 @@ Examples:
 	}
 
 	cmd.Flags().BoolP("verbose", "v", false, "Enable verbose logging for debugging")
+	cmd.Flags().StringSliceP("service", "s", nil, "Services to show (aws,gcp,azure,docker,kubernetes,ssh,gpg,pkgmgr) or categories (@cloud,@container,@access,@custom); defaults to defaultServices in the settings")
 
 	return cmd
 }
@@ -61,13 +69,37 @@ Examples:
 // runTUI executes the TUI command.
 func runTUI(cmd *cobra.Command, args []string) error {
 	verbose, _ := cmd.Flags().GetBool("verbose")
+	services, _ := cmd.Flags().GetStringSlice("service")
 
 	// Set up context
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(switchContext(context.Background()))
 	defer cancel()
 
 	// Create TUI model
-	model := tui.NewModel(ctx)
+	checkers, switchers := tui.DefaultCheckers(), tui.DefaultSwitchers()
+	if mockServices != nil {
+		checkers, switchers = mockServices.Checkers(), mockServices.Switchers()
+	}
+	settings, err := config.LoadSettings(config.DefaultSettingsPath())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		settings = &config.Settings{}
+	}
+	warnUnknownStatusChecks(settings.StatusChecks, checkers)
+	checkers, err = settings.SelectCheckers(checkers, services)
+	if err != nil {
+		return err
+	}
+	if len(checkers) == 0 {
+		return fmt.Errorf("no valid services specified")
+	}
+
+	model := tui.NewModelWithServices(ctx, checkers, switchers)
+	model.SetSwitchLock(environment.NewSwitchLock(switchLockPath()))
+	model.SetCurrentMarker(currentPath())
+	// Options for services left out are expected, and warned about above
+	// against all services
+	_ = model.SetServiceCheckOptions(settings.StatusChecks)
 
 	// Configure tea options
 	var opts []tea.ProgramOption
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newValidateCmd creates the validate command.
+func newValidateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "validate [environment...]",
+		Short: "Check environment files for mistakes before switching",
+		Long: `Check environments by name, alias, or file path, or every environment in
+the search paths if none are given.
+
+Besides the file structure, service values are checked so typos are caught
+before any CLI runs:
+- AWS regions against a built-in list, with did-you-mean suggestions
+- GCP region format (and zones given where a region is expected)
+- Kubernetes namespaces as RFC 1123 labels
+- Docker context name characters
+- Azure subscription and tenant IDs that look like, but are not, UUIDs
+- Service names without a switcher, with did-you-mean suggestions
+- Hooks and activate/deactivate scripts against the hook allowlist
+
+Dependency chains deeper than the recommended depth are reported as
+warnings, since they force services to switch one at a time.
+
+Regions newer than the built-in list can be added under awsRegions in
+~/.gzh/dev-env/settings.yaml, or a switch can bypass the check with
+switch-all --skip-validation.
+
+The exit status is 0 if every environment is valid and 1 otherwise.
+
+Examples:
+  # Validate every environment
+  dev-env validate
+
+  # Validate one environment and a file
+  dev-env validate production ./staging.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := runValidate(args); err != nil {
+				if errors.Is(err, errInvalidEnvironments) {
+					cmd.SilenceErrors = true
+					return &ExitError{Code: 1}
+				}
+				return err
+			}
+			return nil
+		},
+	}
+
+	cmd.ValidArgsFunction = func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		return environment.ListEnvironmentNames(environmentSearchPaths()), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return cmd
+}
+
+// errInvalidEnvironments reports that validation found problems, which
+// have already been printed.
+var errInvalidEnvironments = errors.New("invalid environments")
+
+// runValidate validates the named environments, or all of them.
+func runValidate(args []string) error {
+	if len(args) == 0 {
+		args = environment.ListEnvironmentNames(environmentSearchPaths())
+		if len(args) == 0 {
+			return fmt.Errorf("no environments found")
+		}
+	}
+
+	validator := valueValidator()
+	switcher := validationSwitcher()
+	invalid := false
+	for _, arg := range args {
+		path, err := resolveEnvironmentArg(arg)
+		if err != nil {
+			return err
+		}
+
+		problems, warnings := validateEnvironmentFile(path, validator, switcher)
+		if len(problems) == 0 {
+			fmt.Printf("✅ %s\n", path)
+		} else {
+			invalid = true
+			fmt.Printf("❌ %s\n", path)
+			for _, problem := range problems {
+				fmt.Printf("   %s\n", problem)
+			}
+		}
+		for _, warning := range warnings {
+			fmt.Printf("   ⚠️  %s\n", warning)
+		}
+	}
+
+	if invalid {
+		return errInvalidEnvironments
+	}
+	return nil
+}
+
+// validateEnvironmentFile loads and validates one environment file and
+// returns every problem found, and warnings that do not make it invalid.
+// Services without a switcher and hooks rejected by the allowlist are
+// checked against switcher.
+func validateEnvironmentFile(path string, validator environment.ValueValidator, switcher *environment.EnvironmentSwitcher) (problems, warnings []string) {
+	env, err := environment.LoadEnvironmentFromFile(path)
+	if err != nil {
+		return []string{err.Error()}, nil
+	}
+	env, err = environment.ExpandComposition(env, environment.SearchPathResolver(environmentSearchPaths()))
+	if err != nil {
+		return []string{err.Error()}, nil
+	}
+
+	if err := env.Validate(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	resolver := environment.NewDependencyResolver(env.EnabledServices(), env.EnabledDependencies())
+	if err := resolver.ValidateDependencies(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	warnings = resolver.Warnings()
+
+	var valueErrs environment.ValueErrors
+	if err := validator.Validate(env); errors.As(err, &valueErrs) {
+		for _, valueErr := range valueErrs {
+			problems = append(problems, valueErr.Error())
+		}
+	}
+
+	for _, service := range switcher.MissingSwitchers(env) {
+		problem := fmt.Sprintf("unknown service '%s'", service)
+		if suggestion := switcher.SuggestService(service); suggestion != "" {
+			problem += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+		problems = append(problems, problem)
+	}
+	for _, err := range switcher.ValidateHooks(env) {
+		problems = append(problems, fmt.Sprintf("invalid hook %v", err))
+	}
+	return problems, warnings
+}
+
+// validationSwitcher returns a switcher with the default switchers
+// registered, for checking service names and hooks.
+func validationSwitcher() *environment.EnvironmentSwitcher {
+	switcher := environment.NewEnvironmentSwitcher()
+	registerDefaultSwitchers(switcher)
+	return switcher
+}
+
+// valueValidator returns the validator for service values, accepting the
+// extra AWS regions listed in the settings file.
+func valueValidator() environment.ValueValidator {
+	settings, err := config.LoadSettings(config.DefaultSettingsPath())
+	if err != nil || len(settings.AWSRegions) == 0 {
+		return environment.ValueValidator{}
+	}
+
+	regions := append([]string(nil), environment.DefaultAWSRegions...)
+	return environment.ValueValidator{AWSRegions: append(regions, settings.AWSRegions...)}
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package devenv
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// newVerifySwitcherCmd creates the verify-switcher command.
+func newVerifySwitcherCmd() *cobra.Command {
+	var timeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "verify-switcher EXECUTABLE [KEY=VALUE...]",
+		Short: "Check that an external switcher follows the plugin protocol",
+		Long: `Check that an external switcher executable follows the protocol dev-env
+uses for the switchers in ~/.gzh/dev-env/switchers.d.
+
+The executable is asked for its current state, switched to the config given
+as KEY=VALUE arguments, asked again, rolled back, and asked once more. Each
+step is reported, and the exit status is 1 if any step fails.
+
+The switch and rollback are real: the service's state changes while the
+check runs and is restored afterwards.
+
+Examples:
+  # Check a switcher before installing it
+  dev-env verify-switcher ./vault role=dev`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			config := environment.ExtraConfig{}
+			for _, arg := range args[1:] {
+				key, value, ok := strings.Cut(arg, "=")
+				if !ok || key == "" {
+					return fmt.Errorf("invalid config value %q (want KEY=VALUE)", arg)
+				}
+				config[key] = value
+			}
+
+			switcher := environment.NewExecSwitcher(args[0])
+			switcher.Timeout = timeout
+
+			failed := false
+			out := cmd.OutOrStdout()
+			for _, step := range environment.VerifyExecSwitcher(context.Background(), switcher, config) {
+				if step.Err != nil {
+					failed = true
+					fmt.Fprintf(out, "❌ %s: %v\n", step.Name, step.Err)
+					if stderr := environment.CommandStderr(step.Err); stderr != "" {
+						fmt.Fprintf(out, "   stderr: %s\n", stderr)
+					}
+					continue
+				}
+				fmt.Fprintf(out, "✅ %s\n", step.Name)
+			}
+
+			if failed {
+				cmd.SilenceErrors = true
+				return &ExitError{Code: 1}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVar(&timeout, "timeout", environment.DefaultExecSwitcherTimeout, "Timeout for each run of the executable")
+
+	return cmd
+}
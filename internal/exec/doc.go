@@ -1,4 +1,11 @@
 // Package exec provides safe command execution utilities.
 //
+// It runs the external executables that extend dev-env, the checkers in
+// checkers.d and the switchers in switchers.d, which are found and run the
+// same way:
+//   - Discover: The executables in a directory, ordered by name
+//   - Name: The service an executable provides, its base name without extension
+//   - Command: One bounded run of an executable, with its standard output and error
+//
 // This package is internal and not for external use.
 package exec
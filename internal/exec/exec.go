@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ErrTimedOut is wrapped by the error of a Command that ran out of time.
+var ErrTimedOut = errors.New("timed out")
+
+// Discover returns the paths of the executables in dir, ordered by Name.
+// Hidden files, directories, and files without an execute bit are skipped,
+// and a missing dir has none.
+func Discover(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil || !info.Mode().IsRegular() || !IsExecutable(info) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool { return Name(paths[i]) < Name(paths[j]) })
+	return paths, nil
+}
+
+// IsExecutable reports whether a file may be run. Windows has no execute
+// permission bit, so every file qualifies there.
+func IsExecutable(info os.FileInfo) bool {
+	return runtime.GOOS == "windows" || info.Mode().Perm()&0o111 != 0
+}
+
+// Name returns the name of the service the executable at path provides:
+// its base name without extension.
+func Name(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Command is one run of an external executable.
+type Command struct {
+	Path string
+	Args []string
+	// Stdin is written to the executable's standard input if not nil.
+	Stdin []byte
+	// Env is added to the environment the executable inherits.
+	Env []string
+	// Timeout bounds the run.
+	Timeout time.Duration
+}
+
+// Run runs the command and returns what it wrote to standard output and
+// standard error. If it runs out of time, the error wraps ErrTimedOut.
+func (c Command) Run(ctx context.Context) (stdout, stderr []byte, err error) {
+	runCtx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	var out, errOut bytes.Buffer
+	cmd := exec.CommandContext(runCtx, c.Path, c.Args...) // #nosec G204 - executables are installed by the user
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+	// Don't wait for children that keep the output pipes open.
+	cmd.WaitDelay = time.Second
+	if c.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(c.Stdin)
+	}
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+
+	err = cmd.Run()
+	if err != nil && errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %v", ErrTimedOut, c.Timeout)
+	}
+	return out.Bytes(), errOut.Bytes(), err
+}
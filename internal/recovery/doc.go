@@ -0,0 +1,9 @@
+// Package recovery turns panics into errors.
+//
+// Service switchers and status checkers, including plugins, run behind it so
+// a buggy one fails its own service instead of the whole process:
+//   - Recover: Stores a recovered panic in an error and logs it
+//   - Stack: The stack of a recovered panic, starting at the panicking frame
+//
+// This package is internal and not for external use.
+package recovery
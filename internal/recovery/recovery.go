@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package recovery
+
+import (
+	"log"
+	"runtime/debug"
+	"strings"
+)
+
+// Recover stores a recovered panic in *err and logs it as a panic of the
+// named kind of service, such as "status checker". newErr builds the error
+// from the panic value and its Stack. Recover must be deferred directly by
+// the function whose panics it recovers.
+func Recover(kind, service string, err *error, newErr func(value interface{}, stack string) error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	stack := Stack()
+	log.Printf("%s %s panicked: %v\n%s", kind, service, r, stack)
+	*err = newErr(r, stack)
+}
+
+// Stack returns the stack of the recovering goroutine, starting at the
+// frame that panicked rather than in the recovery machinery.
+func Stack() string {
+	stack := string(debug.Stack())
+	i := strings.Index(stack, "\npanic(")
+	if i < 0 {
+		return stack
+	}
+	// Skip the panic call and its file:line.
+	rest := stack[i+1:]
+	for range 2 {
+		if j := strings.IndexByte(rest, '\n'); j >= 0 {
+			rest = rest[j+1:]
+		}
+	}
+	return rest
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package testutil
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// AssertNotInstalled checks that the checker made by newChecker reports
+// its service inactive with the not-installed reason when no CLI is on the
+// PATH. The PATH is emptied before newChecker is called.
+func AssertNotInstalled[C status.ServiceChecker](t *testing.T, newChecker func() C) {
+	t.Helper()
+	t.Setenv("PATH", t.TempDir())
+
+	st, err := newChecker().CheckStatus(context.Background())
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if st.Status != status.StatusInactive || st.Reason != status.ReasonNotInstalled {
+		t.Errorf("CheckStatus() = %v, %q, want %v, %q", st.Status, st.Reason, status.StatusInactive, status.ReasonNotInstalled)
+	}
+}
@@ -1,4 +1,8 @@
 // Package testutil provides test helpers and mock implementations.
 //
+// The helpers are shared by the tests of several packages:
+//   - AssertGolden: Compares output with a golden file, rewriting it with -update
+//   - AssertNotInstalled: Checks a status checker whose CLI is not installed
+//
 // This package is internal and not for external use.
 package testutil
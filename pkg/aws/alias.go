@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// aliasCache remembers account aliases by account ID for the life of a
+// Checker, so watch mode and the TUI do not call IAM on every refresh. An
+// account whose alias could not be read is cached as "" and not retried.
+type aliasCache struct {
+	mu      sync.Mutex
+	aliases map[string]string
+}
+
+// accountAlias returns the alias of accountID, or "" if it has none or the
+// caller may not list it. Looking it up is best-effort: many roles lack
+// iam:ListAccountAliases, which must not affect the status.
+func (a *Checker) accountAlias(ctx context.Context, accountID string) string {
+	a.aliases.mu.Lock()
+	defer a.aliases.mu.Unlock()
+
+	if alias, ok := a.aliases.aliases[accountID]; ok {
+		return alias
+	}
+
+	var alias string
+	if output, err := environment.RunCommand(ctx, "aws", "iam", "list-account-aliases", "--output", "json"); err == nil {
+		alias, _ = parseAccountAliases(output)
+	} else if ctx.Err() != nil {
+		// A canceled check says nothing about the permission; try again
+		// next time.
+		return ""
+	}
+
+	if a.aliases.aliases == nil {
+		a.aliases.aliases = make(map[string]string)
+	}
+	a.aliases.aliases[accountID] = alias
+	return alias
+}
+
+// parseAccountAliases returns the first alias from
+// `aws iam list-account-aliases`. An account has at most one alias.
+func parseAccountAliases(output []byte) (string, error) {
+	var response struct {
+		AccountAliases []string
+	}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return "", fmt.Errorf("failed to parse account aliases: %w", err)
+	}
+	if len(response.AccountAliases) == 0 {
+		return "", nil
+	}
+	return response.AccountAliases[0], nil
+}
+
+// parseCallerAccount returns the account ID from `aws sts get-caller-identity`.
+func parseCallerAccount(output []byte) (string, error) {
+	var identity struct {
+		Account string
+	}
+	if err := json.Unmarshal(output, &identity); err != nil {
+		return "", fmt.Errorf("failed to parse caller identity: %w", err)
+	}
+	return identity.Account, nil
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package aws
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// installAWS puts a fake aws script running body on PATH and returns the
+// file it logs its arguments to.
+func installAWS(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake aws is a shell script")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "aws.log")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + log + "\"\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "aws"), []byte(script), 0o700); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("AWS_PROFILE", "dev")
+	t.Setenv("AWS_REGION", "us-east-1")
+	return log
+}
+
+// aliasCalls counts the list-account-aliases calls logged by installAWS.
+func aliasCalls(t *testing.T, log string) int {
+	t.Helper()
+	data, err := os.ReadFile(log) // #nosec G304 - test log file
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Count(string(data), "iam list-account-aliases")
+}
+
+// TestParseAccountAliases tests parsing `aws iam list-account-aliases` output.
+func TestParseAccountAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"alias", `{"AccountAliases": ["prod"]}`, "prod", false},
+		{"no alias", `{"AccountAliases": []}`, "", false},
+		{"invalid json", `AccessDenied`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAccountAliases([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAccountAliases() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseAccountAliases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseCallerAccount tests reading the account from `aws sts get-caller-identity`.
+func TestParseCallerAccount(t *testing.T) {
+	output := `{"UserId": "AIDAEXAMPLE", "Account": "123456789012", "Arn": "arn:aws:iam::123456789012:user/dev"}`
+	if got, err := parseCallerAccount([]byte(output)); err != nil || got != "123456789012" {
+		t.Errorf("parseCallerAccount() = %q, %v, want %q", got, err, "123456789012")
+	}
+	if _, err := parseCallerAccount([]byte("not json")); err == nil {
+		t.Error("parseCallerAccount() should fail for invalid output")
+	}
+}
+
+// TestChecker_CheckStatus_AccountAlias tests that the account alias is
+// shown when it can be listed, looked up once per checker, and skipped
+// when IAM denies the call.
+func TestChecker_CheckStatus_AccountAlias(t *testing.T) {
+	const callerIdentity = `sts) echo '{"UserId": "AIDAEXAMPLE", "Account": "123456789012", "Arn": "arn:aws:iam::123456789012:user/dev"}' ;;`
+
+	tests := []struct {
+		name      string
+		iam       string
+		wantAlias string
+	}{
+		{
+			name:      "alias listed",
+			iam:       `iam) echo '{"AccountAliases": ["prod"]}' ;;`,
+			wantAlias: "prod",
+		},
+		{
+			name: "access denied",
+			iam:  `iam) echo 'An error occurred (AccessDenied) when calling the ListAccountAliases operation' >&2; exit 254 ;;`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := installAWS(t, "case \"$1\" in\n"+callerIdentity+"\n"+tt.iam+"\nesac\n")
+			checker := NewChecker()
+
+			for i := 0; i < 2; i++ {
+				st, err := checker.CheckStatus(context.Background())
+				if err != nil {
+					t.Fatalf("CheckStatus() error = %v", err)
+				}
+				if st.Current.Account != "123456789012" {
+					t.Errorf("Current.Account = %q, want %q", st.Current.Account, "123456789012")
+				}
+				if got := st.Details["account_alias"]; got != tt.wantAlias {
+					t.Errorf("Details[account_alias] = %q, want %q", got, tt.wantAlias)
+				}
+			}
+
+			if got := aliasCalls(t, log); got != 1 {
+				t.Errorf("list-account-aliases called %d times, want 1 (cached)", got)
+			}
+		})
+	}
+}
@@ -5,13 +5,17 @@ package aws
 
 import (
 	"context"
+	"crypto/sha1" // #nosec G505 - matches the AWS CLI's SSO cache file names
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -23,7 +27,9 @@ const (
 )
 
 // Checker implements status.ServiceChecker for AWS.
-type Checker struct{}
+type Checker struct {
+	aliases aliasCache
+}
 
 // NewChecker creates a new AWS status checker.
 func NewChecker() *Checker {
@@ -35,6 +41,11 @@ func (a *Checker) Name() string {
 	return "aws"
 }
 
+// Category returns the service's category for grouping and selection.
+func (a *Checker) Category() status.Category {
+	return status.CategoryCloud
+}
+
 // CheckStatus checks AWS current status.
 func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
 	st := &status.ServiceStatus{
@@ -49,14 +60,20 @@ func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if AWS CLI is available
 	if !a.isCLIAvailable() {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
 		st.Details["error"] = "AWS CLI not found"
 		return st, nil
 	}
 
+	if version := a.getCLIVersion(ctx); version != "" {
+		st.Details["cli_version"] = version
+	}
+
 	// Get current profile
-	profile := a.getCurrentProfile()
+	profile := a.getCurrentProfile(ctx)
 	if profile == "" {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotConfigured
 		st.Details["error"] = "No AWS profile configured"
 		return st, nil
 	}
@@ -64,11 +81,11 @@ func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	st.Current.Profile = profile
 
 	// Get current region
-	region := a.getCurrentRegion()
+	region := a.getCurrentRegion(ctx)
 	st.Current.Region = region
 
 	// Check credentials validity
-	credStatus, err := a.checkCredentials(ctx)
+	credStatus, accountID, err := a.checkCredentials(ctx)
 	if err != nil {
 		st.Status = status.StatusError
 		st.Details["credential_error"] = err.Error()
@@ -78,8 +95,15 @@ func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	st.Credentials = *credStatus
 	if credStatus.Valid {
 		st.Status = status.StatusActive
+		st.Current.Account = accountID
+		if accountID != "" {
+			if alias := a.accountAlias(ctx, accountID); alias != "" {
+				st.Details["account_alias"] = alias
+			}
+		}
 	} else {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonCredentialsInvalid
 	}
 
 	return st, nil
@@ -95,16 +119,14 @@ func (a *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	}
 
 	// Test STS GetCallerIdentity
-	cmd := exec.CommandContext(ctx, "aws", "sts", "get-caller-identity", "--output", "json")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "aws", "sts", "get-caller-identity", "--output", "json")
 	health.Duration = time.Since(start)
 
 	if err != nil {
 		health.Status = status.StatusError
 		health.Message = fmt.Sprintf("Failed to call AWS STS: %v", err)
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			health.Details["stderr"] = string(exitErr.Stderr)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
 		}
 		return health, nil
 	}
@@ -122,16 +144,37 @@ func (a *Checker) isCLIAvailable() bool {
 	return err == nil
 }
 
+// getCLIVersion returns the installed AWS CLI version, or an empty string.
+func (a *Checker) getCLIVersion(ctx context.Context) string {
+	// AWS CLI v1 writes its version to stderr, v2 to stdout.
+	output, err := environment.RunCombinedCommand(ctx, "aws", "--version")
+	if err != nil {
+		return ""
+	}
+	return parseCLIVersion(string(output))
+}
+
+// parseCLIVersion extracts the version from `aws --version` output.
+// Both v1 ("aws-cli/1.18.69 Python/2.7.18 ...") and v2
+// ("aws-cli/2.13.0 Python/3.11.4 ...") report the same prefix.
+func parseCLIVersion(output string) string {
+	for _, field := range strings.Fields(output) {
+		if version, ok := strings.CutPrefix(field, "aws-cli/"); ok {
+			return version
+		}
+	}
+	return ""
+}
+
 // getCurrentProfile gets the current AWS profile.
-func (a *Checker) getCurrentProfile() string {
+func (a *Checker) getCurrentProfile(ctx context.Context) string {
 	// Check AWS_PROFILE environment variable
 	if profile := os.Getenv("AWS_PROFILE"); profile != "" {
 		return profile
 	}
 
 	// Check AWS config file for default profile
-	cmd := exec.Command("aws", "configure", "list", "--profile", "default")
-	if err := cmd.Run(); err == nil {
+	if _, err := environment.RunCommand(ctx, "aws", "configure", "list", "--profile", "default"); err == nil {
 		return DefaultProfile
 	}
 
@@ -139,7 +182,7 @@ func (a *Checker) getCurrentProfile() string {
 }
 
 // getCurrentRegion gets the current AWS region.
-func (a *Checker) getCurrentRegion() string {
+func (a *Checker) getCurrentRegion(ctx context.Context) string {
 	// Check AWS_REGION environment variable
 	if region := os.Getenv("AWS_REGION"); region != "" {
 		return region
@@ -151,8 +194,7 @@ func (a *Checker) getCurrentRegion() string {
 	}
 
 	// Try to get from AWS config
-	cmd := exec.Command("aws", "configure", "get", "region")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "aws", "configure", "get", "region")
 	if err == nil && len(output) > 0 {
 		return strings.TrimSpace(string(output))
 	}
@@ -160,31 +202,64 @@ func (a *Checker) getCurrentRegion() string {
 	return "us-east-1" // Default fallback
 }
 
-// checkCredentials checks AWS credentials validity.
-func (a *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatus, error) {
+// checkCredentials checks AWS credentials validity and returns the account
+// they belong to, if they are valid.
+func (a *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatus, string, error) {
 	credStatus := &status.CredentialStatus{
 		Valid: false,
 		Type:  "aws-credentials",
 	}
 
 	// Test credentials with a simple STS call
-	cmd := exec.CommandContext(ctx, "aws", "sts", "get-caller-identity")
-	err := cmd.Run()
+	output, err := environment.RunCommand(ctx, "aws", "sts", "get-caller-identity", "--output", "json")
 	if err != nil {
 		credStatus.Warning = CredentialsExpiredMsg
-		return credStatus, nil
+		return credStatus, "", nil
 	}
+	accountID, _ := parseCallerAccount(output)
 
 	credStatus.Valid = true
 
-	// Try to get session token expiration (for assumed roles)
-	cmd = exec.CommandContext(ctx, "aws", "sts", "get-session-token", "--duration-seconds", "900")
-	output, err := cmd.Output()
+	// An SSO login expires independently of the role credentials it
+	// refreshes, so it is what the user has to renew.
+	if data, err := a.readSSOToken(ctx); err == nil {
+		if err := credStatus.SetExpiry(status.AWSSSOExpiry, data); err == nil && !credStatus.ExpiresAt.IsZero() {
+			credStatus.Type = "sso"
+			return credStatus, accountID, nil
+		}
+	}
+
+	// Temporary credentials, such as assumed roles, carry an expiration
+	output, err = environment.RunCommand(ctx, "aws", "configure", "export-credentials", "--format", "process")
 	if err == nil && len(output) > 0 {
-		// Parse session token response to get expiration
-		// This is a simplified check - in practice you'd parse the JSON
-		credStatus.Type = "session-token"
+		if err := credStatus.SetExpiry(status.AWSSessionExpiry, output); err == nil && !credStatus.ExpiresAt.IsZero() {
+			credStatus.Type = "session-token"
+		}
 	}
 
-	return credStatus, nil
+	return credStatus, accountID, nil
+}
+
+// readSSOToken reads the SSO token cache file of the current profile. The
+// file is named after the SHA-1 of the sso_session name, or of the start URL
+// for legacy SSO profiles.
+func (a *Checker) readSSOToken(ctx context.Context) ([]byte, error) {
+	var key string
+	for _, setting := range []string{"sso_session", "sso_start_url"} {
+		output, err := environment.RunCommand(ctx, "aws", "configure", "get", setting)
+		if err == nil && strings.TrimSpace(string(output)) != "" {
+			key = strings.TrimSpace(string(output))
+			break
+		}
+	}
+	if key == "" {
+		return nil, errors.New("profile does not use SSO")
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha1.Sum([]byte(key)) // #nosec G401 - file naming used by the AWS CLI, not security
+	return os.ReadFile(filepath.Join(homeDir, ".aws", "sso", "cache", hex.EncodeToString(sum[:])+".json"))
 }
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -102,7 +103,7 @@ func TestChecker_getCurrentProfile_WithEnvVar(t *testing.T) {
 	testProfile := "test-profile"
 	os.Setenv("AWS_PROFILE", testProfile)
 
-	profile := checker.getCurrentProfile()
+	profile := checker.getCurrentProfile(context.Background())
 	if profile != testProfile {
 		t.Errorf("getCurrentProfile() = %q, want %q", profile, testProfile)
 	}
@@ -128,7 +129,7 @@ func TestChecker_getCurrentRegion_WithEnvVar(t *testing.T) {
 	testRegion := "ap-northeast-2"
 	os.Setenv("AWS_REGION", testRegion)
 
-	region := checker.getCurrentRegion()
+	region := checker.getCurrentRegion(context.Background())
 	if region != testRegion {
 		t.Errorf("getCurrentRegion() = %q, want %q", region, testRegion)
 	}
@@ -151,7 +152,7 @@ func TestChecker_getCurrentRegion_WithDefaultEnvVar(t *testing.T) {
 	testRegion := "eu-west-1"
 	os.Setenv("AWS_DEFAULT_REGION", testRegion)
 
-	region := checker.getCurrentRegion()
+	region := checker.getCurrentRegion(context.Background())
 	if region != testRegion {
 		t.Errorf("getCurrentRegion() = %q, want %q", region, testRegion)
 	}
@@ -167,3 +168,42 @@ func TestConstants(t *testing.T) {
 		t.Errorf("CredentialsExpiredMsg = %q, unexpected value", CredentialsExpiredMsg)
 	}
 }
+
+// TestParseCLIVersion tests version parsing for AWS CLI v1 and v2 output.
+func TestParseCLIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "v2 output",
+			output: "aws-cli/2.13.0 Python/3.11.4 Linux/6.5.0 exe/x86_64.ubuntu.22 prompt/off\n",
+			want:   "2.13.0",
+		},
+		{
+			name:   "v1 output",
+			output: "aws-cli/1.18.69 Python/2.7.18 Linux/5.4.0 botocore/1.17.0\n",
+			want:   "1.18.69",
+		},
+		{
+			name:   "unrecognized output",
+			output: "command not found",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCLIVersion(tt.output); got != tt.want {
+				t.Errorf("parseCLIVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
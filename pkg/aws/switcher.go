@@ -6,18 +6,19 @@ package aws
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 )
 
 // Switcher implements environment.ServiceSwitcher for AWS.
-type Switcher struct{}
+type Switcher struct {
+	run environment.CommandRunner
+}
 
 // NewSwitcher creates a new AWS switcher.
 func NewSwitcher() *Switcher {
-	return &Switcher{}
+	return &Switcher{run: environment.RunCommand}
 }
 
 // Name returns the service name.
@@ -25,6 +26,30 @@ func (a *Switcher) Name() string {
 	return "aws"
 }
 
+// Claims returns the shared resources the switcher modifies: the AWS CLI
+// configuration and, because EKS contexts reference AWS profiles, the
+// kubeconfig.
+func (a *Switcher) Claims() []string {
+	return append([]string{environment.ClaimAWSConfig}, environment.KubeconfigClaims()...)
+}
+
+// Validate checks that config is an AWS configuration.
+func (a *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.AWSConfig)
+	if !ok {
+		return fmt.Errorf("invalid AWS configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no AWS configuration provided")
+	}
+	return nil
+}
+
+// RequiredCLIs returns the aws CLI, which every switch runs.
+func (a *Switcher) RequiredCLIs(interface{}) []string {
+	return []string{"aws"}
+}
+
 // Switch switches to the specified AWS configuration.
 func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 	awsConfig, ok := config.(*environment.AWSConfig)
@@ -34,8 +59,10 @@ func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 
 	// Set AWS profile
 	if awsConfig.Profile != "" {
-		cmd := exec.CommandContext(ctx, "aws", "configure", "set", "profile", awsConfig.Profile)
-		if err := cmd.Run(); err != nil {
+		if err := a.validateProfile(ctx, awsConfig.Profile); err != nil {
+			return err
+		}
+		if _, err := a.run(ctx, "aws", "configure", "set", "profile", awsConfig.Profile); err != nil {
 			return fmt.Errorf("failed to set AWS profile: %w", err)
 		}
 	}
@@ -46,8 +73,7 @@ func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 		if awsConfig.Profile != "" {
 			args = append(args, "--profile", awsConfig.Profile)
 		}
-		cmd := exec.CommandContext(ctx, "aws", args...)
-		if err := cmd.Run(); err != nil {
+		if _, err := a.run(ctx, "aws", args...); err != nil {
 			return fmt.Errorf("failed to set AWS region: %w", err)
 		}
 	}
@@ -55,15 +81,54 @@ func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 	return nil
 }
 
+// ListProfiles returns the profiles defined in ~/.aws/config and
+// ~/.aws/credentials, as reported by `aws configure list-profiles`.
+func (a *Switcher) ListProfiles(ctx context.Context) ([]string, error) {
+	output, err := a.run(ctx, "aws", "configure", "list-profiles")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list AWS profiles: %w", err)
+	}
+
+	var profiles []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if profile := strings.TrimSpace(line); profile != "" {
+			profiles = append(profiles, profile)
+		}
+	}
+	return profiles, nil
+}
+
+// ListTargets returns the available AWS profiles.
+func (a *Switcher) ListTargets(ctx context.Context) ([]string, error) {
+	return a.ListProfiles(ctx)
+}
+
+// validateProfile checks that the profile exists before switching to it.
+func (a *Switcher) validateProfile(ctx context.Context, profile string) error {
+	profiles, err := a.ListProfiles(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range profiles {
+		if p == profile {
+			return nil
+		}
+	}
+
+	if len(profiles) == 0 {
+		return fmt.Errorf("AWS profile %q not found: no profiles configured in ~/.aws/config or ~/.aws/credentials", profile)
+	}
+	return fmt.Errorf("AWS profile %q not found; available profiles: %s", profile, strings.Join(profiles, ", "))
+}
+
 // GetCurrentState retrieves the current AWS configuration state.
 func (a *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 	// Get current AWS profile
-	cmd := exec.CommandContext(ctx, "aws", "configure", "get", "profile")
-	profileOutput, _ := cmd.Output()
+	profileOutput, _ := a.run(ctx, "aws", "configure", "get", "profile")
 
 	// Get current AWS region
-	cmd = exec.CommandContext(ctx, "aws", "configure", "get", "region")
-	regionOutput, _ := cmd.Output()
+	regionOutput, _ := a.run(ctx, "aws", "configure", "get", "region")
 
 	return &environment.AWSConfig{
 		Profile: strings.TrimSpace(string(profileOutput)),
@@ -5,6 +5,8 @@ package aws
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +32,10 @@ func TestSwitcher_Name(t *testing.T) {
 // TestSwitcher_ImplementsInterface verifies Switcher implements ServiceSwitcher.
 func TestSwitcher_ImplementsInterface(t *testing.T) {
 	var _ environment.ServiceSwitcher = (*Switcher)(nil)
+	var _ environment.ResourceClaimer = (*Switcher)(nil)
+	var _ environment.TargetLister = (*Switcher)(nil)
+	var _ environment.ConfigValidator = (*Switcher)(nil)
+	var _ environment.CLIRequirer = (*Switcher)(nil)
 }
 
 // TestSwitcher_Switch_InvalidConfigType tests error handling for invalid config type.
@@ -121,3 +127,102 @@ func TestSwitcher_Rollback_InvalidState(t *testing.T) {
 		t.Error("Rollback() with invalid state should return error")
 	}
 }
+
+// fakeRunner returns a CommandRunner that answers list-profiles with the
+// given output and records every command it receives.
+func fakeRunner(profiles string, listErr error, calls *[]string) environment.CommandRunner {
+	return func(_ context.Context, name string, args ...string) ([]byte, error) {
+		cmdline := name + " " + strings.Join(args, " ")
+		*calls = append(*calls, cmdline)
+		if cmdline == "aws configure list-profiles" {
+			return []byte(profiles), listErr
+		}
+		return nil, nil
+	}
+}
+
+// TestSwitcher_ListProfiles tests parsing of `aws configure list-profiles` output.
+func TestSwitcher_ListProfiles(t *testing.T) {
+	var calls []string
+	switcher := &Switcher{run: fakeRunner("default\nstaging\n\n  prod  \n", nil, &calls)}
+
+	profiles, err := switcher.ListProfiles(context.Background())
+	if err != nil {
+		t.Fatalf("ListProfiles() error = %v", err)
+	}
+
+	want := []string{"default", "staging", "prod"}
+	if strings.Join(profiles, ",") != strings.Join(want, ",") {
+		t.Errorf("ListProfiles() = %v, want %v", profiles, want)
+	}
+}
+
+// TestSwitcher_Switch_ProfileValidation tests that only existing profiles are set.
+func TestSwitcher_Switch_ProfileValidation(t *testing.T) {
+	const sampleProfiles = "default\nstaging\nprod\n"
+
+	tests := []struct {
+		name      string
+		profiles  string
+		listErr   error
+		config    *environment.AWSConfig
+		wantErr   string
+		wantCalls []string
+	}{
+		{
+			name:     "existing profile",
+			profiles: sampleProfiles,
+			config:   &environment.AWSConfig{Profile: "staging", Region: "us-west-2"},
+			wantCalls: []string{
+				"aws configure list-profiles",
+				"aws configure set profile staging",
+				"aws configure set region us-west-2 --profile staging",
+			},
+		},
+		{
+			name:      "missing profile",
+			profiles:  sampleProfiles,
+			config:    &environment.AWSConfig{Profile: "qa", Region: "us-west-2"},
+			wantErr:   `AWS profile "qa" not found; available profiles: default, staging, prod`,
+			wantCalls: []string{"aws configure list-profiles"},
+		},
+		{
+			name:      "no profiles configured",
+			profiles:  "",
+			config:    &environment.AWSConfig{Profile: "qa"},
+			wantErr:   "no profiles configured",
+			wantCalls: []string{"aws configure list-profiles"},
+		},
+		{
+			name:      "list profiles fails",
+			listErr:   errors.New("exit status 255"),
+			config:    &environment.AWSConfig{Profile: "qa"},
+			wantErr:   "failed to list AWS profiles",
+			wantCalls: []string{"aws configure list-profiles"},
+		},
+		{
+			name:      "region only skips validation",
+			config:    &environment.AWSConfig{Region: "eu-west-1"},
+			wantCalls: []string{"aws configure set region eu-west-1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			switcher := &Switcher{run: fakeRunner(tt.profiles, tt.listErr, &calls)}
+
+			err := switcher.Switch(context.Background(), tt.config)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Switch() error = %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Switch() error = %v, want error containing %q", err, tt.wantErr)
+			}
+
+			if strings.Join(calls, "; ") != strings.Join(tt.wantCalls, "; ") {
+				t.Errorf("commands = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -5,12 +5,12 @@ package azure
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -27,6 +27,11 @@ func (a *Checker) Name() string {
 	return "azure"
 }
 
+// Category returns the service's category for grouping and selection.
+func (a *Checker) Category() status.Category {
+	return status.CategoryCloud
+}
+
 // CheckStatus checks Azure current status.
 func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
 	st := &status.ServiceStatus{
@@ -41,10 +46,21 @@ func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if Azure CLI is available
 	if !a.isCLIAvailable() {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
 		st.Details["error"] = "Azure CLI not found"
 		return st, nil
 	}
 
+	if version := a.getCLIVersion(ctx); version != "" {
+		st.Details["cli_version"] = version
+	}
+
+	// Report the cloud even when logged out, since a missing login is often
+	// a login to a different cloud.
+	if cloud := a.getCurrentCloud(ctx); cloud != "" {
+		st.Details["cloud"] = cloud
+	}
+
 	// Get current subscription
 	subscription, err := a.getCurrentSubscription(ctx)
 	if err != nil {
@@ -55,6 +71,7 @@ func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 
 	if subscription == "" {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotConfigured
 		st.Details["error"] = "No Azure subscription configured"
 		return st, nil
 	}
@@ -80,6 +97,7 @@ func (a *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 		st.Status = status.StatusActive
 	} else {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonCredentialsInvalid
 	}
 
 	return st, nil
@@ -95,16 +113,14 @@ func (a *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	}
 
 	// Test Azure connectivity with az account show
-	cmd := exec.CommandContext(ctx, "az", "account", "show", "--output", "json")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "az", "account", "show", "--output", "json")
 	health.Duration = time.Since(start)
 
 	if err != nil {
 		health.Status = status.StatusError
 		health.Message = fmt.Sprintf("Failed to check Azure authentication: %v", err)
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			health.Details["stderr"] = string(exitErr.Stderr)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
 		}
 		return health, nil
 	}
@@ -122,20 +138,54 @@ func (a *Checker) isCLIAvailable() bool {
 	return err == nil
 }
 
+// getCLIVersion returns the installed Azure CLI version, or an empty string.
+func (a *Checker) getCLIVersion(ctx context.Context) string {
+	output, err := environment.RunCommand(ctx, "az", "--version")
+	if err != nil {
+		return ""
+	}
+	return parseCLIVersion(string(output))
+}
+
+// parseCLIVersion extracts the version from `az --version` output.
+// Current releases print "azure-cli    2.53.0 *" while 2.0.x releases
+// printed "azure-cli (2.0.81)".
+func parseCLIVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "azure-cli")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) == 0 {
+			return ""
+		}
+		return strings.Trim(fields[0], "()")
+	}
+	return ""
+}
+
 // getCurrentSubscription gets the current Azure subscription.
 func (a *Checker) getCurrentSubscription(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "az", "account", "show", "--query", "name", "--output", "tsv")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "az", "account", "show", "--query", "name", "--output", "tsv")
 	if err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
+// getCurrentCloud returns the active Azure cloud, or an empty string.
+func (a *Checker) getCurrentCloud(ctx context.Context) string {
+	output, err := environment.RunCommand(ctx, "az", "cloud", "show", "--query", "name", "--output", "tsv")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // getCurrentAccount gets the current Azure account.
 func (a *Checker) getCurrentAccount(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "az", "account", "show", "--query", "user.name", "--output", "tsv")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "az", "account", "show", "--query", "user.name", "--output", "tsv")
 	if err != nil {
 		return "", err
 	}
@@ -150,8 +200,7 @@ func (a *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatu
 	}
 
 	// Test credentials with az account show
-	cmd := exec.CommandContext(ctx, "az", "account", "show")
-	err := cmd.Run()
+	_, err := environment.RunCommand(ctx, "az", "account", "show")
 	if err != nil {
 		credStatus.Warning = "Credentials invalid or expired"
 		return credStatus, nil
@@ -160,8 +209,7 @@ func (a *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatu
 	credStatus.Valid = true
 
 	// Check authentication method
-	cmd = exec.CommandContext(ctx, "az", "account", "show", "--query", "user.type", "--output", "tsv")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "az", "account", "show", "--query", "user.type", "--output", "tsv")
 	if err == nil {
 		userType := strings.TrimSpace(string(output))
 		switch userType {
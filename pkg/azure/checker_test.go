@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -190,3 +191,42 @@ func TestChecker_CheckStatus_ContextCanceled(t *testing.T) {
 		t.Error("CheckStatus() should return non-nil status even with canceled context")
 	}
 }
+
+// TestParseCLIVersion tests version parsing of old and new `az --version` output.
+func TestParseCLIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "current output",
+			output: "azure-cli                         2.53.0 *\n\ncore                              2.53.0 *\ntelemetry                          1.1.0\n",
+			want:   "2.53.0",
+		},
+		{
+			name:   "legacy output",
+			output: "azure-cli (2.0.81)\n\ncommand-modules-nspkg (2.0.3)\ncore (2.0.81)\n",
+			want:   "2.0.81",
+		},
+		{
+			name:   "unrecognized output",
+			output: "az: command not found",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCLIVersion(tt.output); got != tt.want {
+				t.Errorf("parseCLIVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
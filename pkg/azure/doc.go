@@ -2,6 +2,6 @@
 // and status checking.
 //
 // This package implements:
-//   - AzureSwitcher: Switches Azure subscriptions and tenants
+//   - AzureSwitcher: Switches Azure clouds, subscriptions, and tenants
 //   - AzureChecker: Checks Azure service status and health
 package azure
@@ -5,13 +5,18 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 )
 
+// ErrLoginRequired is returned when the cloud switched to has no valid
+// login, which is usual the first time a cloud is used: each cloud keeps
+// its own login.
+var ErrLoginRequired = errors.New("no valid Azure login for this cloud")
+
 // Switcher implements environment.ServiceSwitcher for Azure.
 type Switcher struct{}
 
@@ -25,6 +30,28 @@ func (a *Switcher) Name() string {
 	return "azure"
 }
 
+// Claims returns the shared resources the switcher modifies: the Azure CLI configuration.
+func (a *Switcher) Claims() []string {
+	return []string{environment.ClaimAzureConfig}
+}
+
+// Validate checks that config is an Azure configuration.
+func (a *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.AzureConfig)
+	if !ok {
+		return fmt.Errorf("invalid Azure configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no Azure configuration provided")
+	}
+	return nil
+}
+
+// RequiredCLIs returns the az CLI, which every switch runs.
+func (a *Switcher) RequiredCLIs(interface{}) []string {
+	return []string{"az"}
+}
+
 // Switch switches to the specified Azure configuration.
 func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 	azureConfig, ok := config.(*environment.AzureConfig)
@@ -32,10 +59,17 @@ func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 		return fmt.Errorf("invalid Azure configuration type")
 	}
 
+	// The subscription may only exist in the target cloud, so switch
+	// clouds first.
+	if azureConfig.Cloud != "" {
+		if err := a.switchCloud(ctx, azureConfig); err != nil {
+			return err
+		}
+	}
+
 	// Set Azure subscription
 	if azureConfig.Subscription != "" {
-		cmd := exec.CommandContext(ctx, "az", "account", "set", "--subscription", azureConfig.Subscription)
-		if err := cmd.Run(); err != nil {
+		if _, err := environment.RunCommand(ctx, "az", "account", "set", "--subscription", azureConfig.Subscription); err != nil {
 			return fmt.Errorf("failed to set Azure subscription: %w", err)
 		}
 	}
@@ -43,19 +77,69 @@ func (a *Switcher) Switch(ctx context.Context, config interface{}) error {
 	return nil
 }
 
+// switchCloud makes config.Cloud the active cloud, if it is not already,
+// after checking it against `az cloud list`. A cloud without a valid login
+// fails with ErrLoginRequired and the az login command to run.
+func (a *Switcher) switchCloud(ctx context.Context, config *environment.AzureConfig) error {
+	if current := currentCloud(ctx); strings.EqualFold(current, config.Cloud) {
+		return nil
+	}
+
+	output, err := environment.RunCommand(ctx, "az", "cloud", "list", "--query", "[].name", "-o", "tsv")
+	if err != nil {
+		return fmt.Errorf("failed to list Azure clouds: %w", err)
+	}
+	clouds := strings.Fields(string(output))
+	cloud := ""
+	for _, name := range clouds {
+		if strings.EqualFold(name, config.Cloud) {
+			cloud = name
+			break
+		}
+	}
+	if cloud == "" {
+		return fmt.Errorf("unknown Azure cloud '%s' (available: %s)", config.Cloud, strings.Join(clouds, ", "))
+	}
+
+	if _, err := environment.RunCommand(ctx, "az", "cloud", "set", "--name", cloud); err != nil {
+		return fmt.Errorf("failed to set Azure cloud: %w", err)
+	}
+
+	if _, err := environment.RunCommand(ctx, "az", "account", "show", "-o", "none"); err != nil {
+		return fmt.Errorf("%w: switched to cloud %s; run `%s` and switch again", ErrLoginRequired, cloud, loginCommand(config))
+	}
+	return nil
+}
+
+// loginCommand returns the az login command for config's tenant.
+func loginCommand(config *environment.AzureConfig) string {
+	if config.Tenant != "" {
+		return "az login --tenant " + config.Tenant
+	}
+	return "az login"
+}
+
+// currentCloud returns the active Azure cloud, or "" if it is unknown.
+func currentCloud(ctx context.Context) string {
+	output, err := environment.RunCommand(ctx, "az", "cloud", "show", "--query", "name", "-o", "tsv")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
 // GetCurrentState retrieves the current Azure configuration state.
 func (a *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 	// Get current Azure subscription
-	cmd := exec.CommandContext(ctx, "az", "account", "show", "--query", "id", "-o", "tsv")
-	subscriptionOutput, _ := cmd.Output()
+	subscriptionOutput, _ := environment.RunCommand(ctx, "az", "account", "show", "--query", "id", "-o", "tsv")
 
 	// Get current Azure tenant
-	cmd = exec.CommandContext(ctx, "az", "account", "show", "--query", "tenantId", "-o", "tsv")
-	tenantOutput, _ := cmd.Output()
+	tenantOutput, _ := environment.RunCommand(ctx, "az", "account", "show", "--query", "tenantId", "-o", "tsv")
 
 	return &environment.AzureConfig{
 		Subscription: strings.TrimSpace(string(subscriptionOutput)),
 		Tenant:       strings.TrimSpace(string(tenantOutput)),
+		Cloud:        currentCloud(ctx),
 	}, nil
 }
 
@@ -63,3 +147,19 @@ func (a *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 func (a *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
 	return a.Switch(ctx, previousState)
 }
+
+// ListTargets returns the available Azure subscription IDs.
+func (a *Switcher) ListTargets(ctx context.Context) ([]string, error) {
+	output, err := environment.RunCommand(ctx, "az", "account", "list", "--query", "[].id", "-o", "tsv")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Azure subscriptions: %w", err)
+	}
+
+	var targets []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if target := strings.TrimSpace(line); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
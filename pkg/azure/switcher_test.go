@@ -5,6 +5,11 @@ package azure
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +35,10 @@ func TestSwitcher_Name(t *testing.T) {
 // TestSwitcher_ImplementsInterface verifies Switcher implements ServiceSwitcher.
 func TestSwitcher_ImplementsInterface(t *testing.T) {
 	var _ environment.ServiceSwitcher = (*Switcher)(nil)
+	var _ environment.ResourceClaimer = (*Switcher)(nil)
+	var _ environment.TargetLister = (*Switcher)(nil)
+	var _ environment.ConfigValidator = (*Switcher)(nil)
+	var _ environment.CLIRequirer = (*Switcher)(nil)
 }
 
 // TestSwitcher_Switch_InvalidConfigType tests error handling for invalid config type.
@@ -172,3 +181,144 @@ func TestSwitcher_GetCurrentState_ReturnsAzureConfig(t *testing.T) {
 	t.Logf("Current Azure subscription: %s", azureConfig.Subscription)
 	t.Logf("Current Azure tenant: %s", azureConfig.Tenant)
 }
+
+// installAz puts a fake az on PATH whose active cloud is kept in a file,
+// with logins only for the clouds listed in loggedIn. It returns the log of
+// its invocations.
+func installAz(t *testing.T, cloud string, loggedIn ...string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake az is a shell script")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "az.log")
+	state := filepath.Join(dir, "cloud")
+	if err := os.WriteFile(state, []byte(cloud+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	script := `#!/bin/sh
+echo "$@" >> "` + log + `"
+case "$*" in
+"cloud show --query name -o tsv") cat "` + state + `" ;;
+"cloud list --query [].name -o tsv") printf 'AzureCloud\nAzureUSGovernment\nAzureChinaCloud\n' ;;
+"cloud set --name "*) echo "$4" > "` + state + `" ;;
+"account show -o none")
+	case " ` + strings.Join(loggedIn, " ") + ` " in
+	*" $(cat "` + state + `") "*) ;;
+	*) echo "Please run 'az login' to setup account." >&2; exit 1 ;;
+	esac ;;
+esac
+`
+	if err := os.WriteFile(filepath.Join(dir, "az"), []byte(script), 0o700); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+// TestSwitcher_Switch_Cloud tests switching clouds before the subscription.
+func TestSwitcher_Switch_Cloud(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    environment.AzureConfig
+		loggedIn  []string
+		wantCalls []string
+		wantErr   string
+		wantLogin bool
+	}{
+		{
+			name:     "switches cloud then subscription",
+			config:   environment.AzureConfig{Cloud: "azureusgovernment", Subscription: "gov-sub"},
+			loggedIn: []string{"AzureCloud", "AzureUSGovernment"},
+			wantCalls: []string{
+				"cloud show --query name -o tsv",
+				"cloud list --query [].name -o tsv",
+				"cloud set --name AzureUSGovernment",
+				"account show -o none",
+				"account set --subscription gov-sub",
+			},
+		},
+		{
+			name:     "current cloud is not set again",
+			config:   environment.AzureConfig{Cloud: "AzureCloud", Subscription: "sub"},
+			loggedIn: []string{"AzureCloud"},
+			wantCalls: []string{
+				"cloud show --query name -o tsv",
+				"account set --subscription sub",
+			},
+		},
+		{
+			name:     "unknown cloud",
+			config:   environment.AzureConfig{Cloud: "AzureGovernment", Subscription: "sub"},
+			loggedIn: []string{"AzureCloud"},
+			wantErr:  "unknown Azure cloud 'AzureGovernment' (available: AzureCloud, AzureUSGovernment, AzureChinaCloud)",
+		},
+		{
+			name:      "no login in the new cloud",
+			config:    environment.AzureConfig{Cloud: "AzureUSGovernment", Subscription: "gov-sub", Tenant: "gov-tenant"},
+			loggedIn:  []string{"AzureCloud"},
+			wantErr:   "run `az login --tenant gov-tenant` and switch again",
+			wantLogin: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := installAz(t, "AzureCloud", tt.loggedIn...)
+			config := tt.config
+
+			err := NewSwitcher().Switch(context.Background(), &config)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Switch() error = %v, want %q", err, tt.wantErr)
+				}
+				if errors.Is(err, ErrLoginRequired) != tt.wantLogin {
+					t.Errorf("errors.Is(err, ErrLoginRequired) = %v, want %v", !tt.wantLogin, tt.wantLogin)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Switch() error = %v", err)
+			}
+
+			data, err := os.ReadFile(log) // #nosec G304 - test log file
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Split(strings.TrimSpace(string(data)), "\n"); strings.Join(got, "\n") != strings.Join(tt.wantCalls, "\n") {
+				t.Errorf("az calls = %q, want %q", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+// TestSwitcher_Rollback_Cloud tests that the previous cloud is captured and
+// restored.
+func TestSwitcher_Rollback_Cloud(t *testing.T) {
+	installAz(t, "AzureCloud", "AzureCloud", "AzureUSGovernment")
+	switcher := NewSwitcher()
+	ctx := context.Background()
+
+	previous, err := switcher.GetCurrentState(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentState() error = %v", err)
+	}
+	if got := previous.(*environment.AzureConfig).Cloud; got != "AzureCloud" {
+		t.Fatalf("GetCurrentState() Cloud = %q, want AzureCloud", got)
+	}
+
+	if err := switcher.Switch(ctx, &environment.AzureConfig{Cloud: "AzureUSGovernment"}); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+	if got := currentCloud(ctx); got != "AzureUSGovernment" {
+		t.Fatalf("cloud after Switch() = %q, want AzureUSGovernment", got)
+	}
+
+	if err := switcher.Rollback(ctx, previous); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if got := currentCloud(ctx); got != "AzureCloud" {
+		t.Errorf("cloud after Rollback() = %q, want AzureCloud", got)
+	}
+}
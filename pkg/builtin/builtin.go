@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package builtin
+
+import (
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/aws"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/azure"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/docker"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/gcp"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/gpg"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/kubernetes"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/pkgmgr"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/ssh"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// Service is a built-in service. Every built-in service can be both checked
+// and switched.
+type Service struct {
+	Name string
+	// Binaries are the CLIs the service relies on. The service is usable
+	// if any of them is installed.
+	Binaries    []string
+	NewChecker  func() status.ServiceChecker
+	NewSwitcher func() environment.ServiceSwitcher
+}
+
+// services lists the built-in services in the order they are checked and
+// shown.
+var services = []Service{
+	{
+		Name:        "aws",
+		Binaries:    []string{"aws"},
+		NewChecker:  func() status.ServiceChecker { return aws.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return aws.NewSwitcher() },
+	},
+	{
+		Name:        "gcp",
+		Binaries:    []string{"gcloud"},
+		NewChecker:  func() status.ServiceChecker { return gcp.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return gcp.NewSwitcher() },
+	},
+	{
+		Name:        "azure",
+		Binaries:    []string{"az"},
+		NewChecker:  func() status.ServiceChecker { return azure.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return azure.NewSwitcher() },
+	},
+	{
+		Name:        "docker",
+		Binaries:    []string{"docker"},
+		NewChecker:  func() status.ServiceChecker { return docker.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return docker.NewSwitcher() },
+	},
+	{
+		Name:        "kubernetes",
+		Binaries:    []string{"kubectl"},
+		NewChecker:  func() status.ServiceChecker { return kubernetes.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return kubernetes.NewSwitcher() },
+	},
+	{
+		Name:        "ssh",
+		Binaries:    []string{"ssh"},
+		NewChecker:  func() status.ServiceChecker { return ssh.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return ssh.NewSwitcher() },
+	},
+	{
+		Name:        "gpg",
+		Binaries:    []string{"gpg"},
+		NewChecker:  func() status.ServiceChecker { return gpg.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return gpg.NewSwitcher() },
+	},
+	{
+		Name:        "pkgmgr",
+		Binaries:    []string{"npm", "go"},
+		NewChecker:  func() status.ServiceChecker { return pkgmgr.NewChecker() },
+		NewSwitcher: func() environment.ServiceSwitcher { return pkgmgr.NewSwitcher() },
+	},
+}
+
+// Services returns the built-in services.
+func Services() []Service {
+	return append([]Service(nil), services...)
+}
+
+// Checkers returns a new checker for each built-in service.
+func Checkers() []status.ServiceChecker {
+	checkers := make([]status.ServiceChecker, len(services))
+	for i, service := range services {
+		checkers[i] = service.NewChecker()
+	}
+	return checkers
+}
+
+// Switchers returns a new switcher for each built-in service.
+func Switchers() []environment.ServiceSwitcher {
+	switchers := make([]environment.ServiceSwitcher, len(services))
+	for i, service := range services {
+		switchers[i] = service.NewSwitcher()
+	}
+	return switchers
+}
@@ -0,0 +1,27 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package builtin
+
+import "testing"
+
+// TestServices tests that each built-in service's checker and switcher are
+// named after the service, so every checked service can also be switched.
+func TestServices(t *testing.T) {
+	checkers, switchers := Checkers(), Switchers()
+	if len(checkers) != len(services) || len(switchers) != len(services) {
+		t.Fatalf("Checkers() and Switchers() = %d and %d services, want %d", len(checkers), len(switchers), len(services))
+	}
+
+	for i, service := range Services() {
+		if got := checkers[i].Name(); got != service.Name {
+			t.Errorf("Checkers()[%d].Name() = %q, want %q", i, got, service.Name)
+		}
+		if got := switchers[i].Name(); got != service.Name {
+			t.Errorf("Switchers()[%d].Name() = %q, want %q", i, got, service.Name)
+		}
+		if len(service.Binaries) == 0 {
+			t.Errorf("Services()[%d].Binaries is empty, want the CLIs %s relies on", i, service.Name)
+		}
+	}
+}
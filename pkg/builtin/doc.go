@@ -0,0 +1,9 @@
+// Package builtin registers the services dev-env supports out of the box, so
+// that every command and the TUI check and switch the same services.
+//
+// The main abstractions are:
+//   - Service: a built-in service's name with its checker and switcher
+//     constructors
+//   - Checkers, Switchers: new checkers and switchers of all built-in
+//     services, in the same order
+package builtin
@@ -3,5 +3,8 @@
 //
 // This package implements:
 //   - ConfigManager: Manages configuration file operations
-//   - Storage: File-based configuration storage
+//   - Storage: File-based configuration storage, listed lazily and page by page for large stores
+//   - Format: JSON and YAML listings of saved configurations for tooling
+//   - Watch: Debounced notification of configuration file changes
+//   - Permissions: Checks that dev-env's files are private to their owner
 package config
@@ -0,0 +1,49 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats FormatConfigInfos supports.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+// FormatConfigInfos renders configs as a JSON or YAML list for tooling,
+// such as a script looking for the newest saved kubeconfig. SavedAt is
+// written as an RFC 3339 timestamp in UTC, to the second, and omitted when
+// unknown, as it is for a lazy listing. An empty list renders as [].
+func FormatConfigInfos(configs []ConfigInfo, format string) (string, error) {
+	out := make([]ConfigInfo, len(configs))
+	for i, info := range configs {
+		if !info.SavedAt.IsZero() {
+			info.SavedAt = info.SavedAt.UTC().Truncate(time.Second)
+		}
+		out[i] = info
+	}
+
+	switch format {
+	case FormatJSON:
+		data, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to encode configurations: %w", err)
+		}
+		return string(data) + "\n", nil
+	case FormatYAML:
+		data, err := yaml.Marshal(out)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode configurations: %w", err)
+		}
+		return string(data), nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (use %s or %s)", format, FormatJSON, FormatYAML)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
+)
+
+// goldenConfigInfos returns a fixture with every field of the output
+// contract set, plus a lazily listed entry with only a name and size.
+func goldenConfigInfos() []ConfigInfo {
+	savedAt := time.Date(2025, 3, 4, 5, 6, 7, 890, time.FixedZone("KST", 9*60*60))
+	return []ConfigInfo{
+		{
+			Name:        "prod",
+			Description: "production cluster",
+			SavedAt:     savedAt,
+			SourcePath:  "/home/dev/.kube/config",
+			Size:        2048,
+		},
+		{Name: "staging", Size: 512},
+	}
+}
+
+// TestFormatConfigInfos_Contract tests the JSON and YAML field names against
+// golden files. A failure here means the output contract changed.
+func TestFormatConfigInfos_Contract(t *testing.T) {
+	tests := []struct {
+		format string
+		golden string
+	}{
+		{format: FormatJSON, golden: "configs.golden.json"},
+		{format: FormatYAML, golden: "configs.golden.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			output, err := FormatConfigInfos(goldenConfigInfos(), tt.format)
+			if err != nil {
+				t.Fatalf("FormatConfigInfos() error = %v", err)
+			}
+			testutil.AssertGolden(t, tt.golden, output)
+		})
+	}
+}
+
+// TestFormatConfigInfos tests empty lists and unsupported formats.
+func TestFormatConfigInfos(t *testing.T) {
+	if got, err := FormatConfigInfos(nil, FormatJSON); err != nil || got != "[]\n" {
+		t.Errorf("FormatConfigInfos(nil, json) = %q, %v, want %q", got, err, "[]\n")
+	}
+	if got, err := FormatConfigInfos(nil, FormatYAML); err != nil || got != "[]\n" {
+		t.Errorf("FormatConfigInfos(nil, yaml) = %q, %v, want %q", got, err, "[]\n")
+	}
+	if _, err := FormatConfigInfos(nil, "table"); err == nil {
+		t.Error("FormatConfigInfos() should reject unsupported formats")
+	}
+}
@@ -8,10 +8,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"iter"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/difffmt"
 )
 
 // Manager handles saving, loading, and listing configuration files.
@@ -20,6 +23,9 @@ type Manager struct {
 	configFileName string
 	defaultConfig  string
 	storePath      string
+
+	// watchDebounce overrides DefaultWatchDebounce when positive.
+	watchDebounce time.Duration
 }
 
 // Options represents options for configuration operations.
@@ -38,13 +44,16 @@ type ConfigMetadata struct {
 	SourcePath  string    `json:"source_path"`
 }
 
-// ConfigInfo represents information about a saved configuration.
+// ConfigInfo represents information about a saved configuration. Its JSON
+// and YAML field names are an output contract for tooling; see
+// FormatConfigInfos.
 type ConfigInfo struct {
-	Name        string
-	Description string
-	SavedAt     time.Time
-	SourcePath  string
-	Size        int64
+	Name        string    `json:"name" yaml:"name"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	SavedAt     time.Time `json:"savedAt,omitzero" yaml:"savedAt,omitempty"`
+	SourcePath  string    `json:"sourcePath,omitempty" yaml:"sourcePath,omitempty"`
+	// Size is the size of the saved file in bytes.
+	Size int64 `json:"size" yaml:"size"`
 }
 
 // NewManager creates a new configuration manager.
@@ -78,6 +87,14 @@ func (m *Manager) StorePath() string {
 	return m.storePath
 }
 
+// DefaultStorePaths returns the existing default stores of all services,
+// ~/.gz/<service>-configs.
+func DefaultStorePaths() []string {
+	homeDir, _ := os.UserHomeDir()
+	paths, _ := filepath.Glob(filepath.Join(homeDir, ".gz", "*-configs"))
+	return paths
+}
+
 // Save saves the current configuration to the store.
 func (m *Manager) Save(opts *Options) error {
 	if opts.Name == "" {
@@ -95,7 +112,7 @@ func (m *Manager) Save(opts *Options) error {
 	}
 
 	// Create store directory if it doesn't exist
-	if err := os.MkdirAll(storePath, 0o755); err != nil {
+	if err := os.MkdirAll(storePath, PrivateDirMode); err != nil {
 		return fmt.Errorf("failed to create store directory: %w", err)
 	}
 
@@ -149,7 +166,7 @@ func (m *Manager) Load(opts *Options) (*ConfigMetadata, error) {
 	}
 
 	// Create target directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(opts.ConfigPath), 0o755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(opts.ConfigPath), PrivateDirMode); err != nil {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
 
@@ -165,53 +182,145 @@ func (m *Manager) Load(opts *Options) (*ConfigMetadata, error) {
 	return metadata, nil
 }
 
-// List lists all saved configurations.
-func (m *Manager) List(storePath string) ([]ConfigInfo, error) {
+// Diff renders a unified diff from the saved configuration opts.Name to the
+// current config file at opts.ConfigPath. It returns an empty string when
+// they are identical.
+func (m *Manager) Diff(opts *Options, renderer *difffmt.Renderer) (string, error) {
+	if opts.Name == "" {
+		return "", fmt.Errorf("configuration name is required")
+	}
+
+	storePath := opts.StorePath
 	if storePath == "" {
 		storePath = m.storePath
 	}
 
-	// Check if store directory exists
-	if _, err := os.Stat(storePath); os.IsNotExist(err) {
-		return nil, nil
+	configFile := filepath.Join(storePath, opts.Name+"."+m.configFileName)
+	saved, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("configuration '%s' not found", opts.Name)
 	}
-
-	// Read directory contents
-	entries, err := os.ReadDir(storePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read store directory: %w", err)
+		return "", fmt.Errorf("failed to read saved configuration: %w", err)
 	}
 
-	// Filter for config files
-	configExtension := "." + m.configFileName
-	var configs []ConfigInfo
+	current, err := os.ReadFile(opts.ConfigPath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s config file: %w", m.serviceName, err)
+	}
+
+	return renderer.Unified(configFile, opts.ConfigPath, string(saved), string(current)), nil
+}
+
+// ListOptions controls which saved configurations ListWithOptions and
+// ListIter return and how much they read about each.
+type ListOptions struct {
+	// Lazy skips the metadata files, so each ConfigInfo has only Name and
+	// Size. LoadInfo fills in the rest for a single configuration.
+	Lazy bool
+	// Offset skips that many configurations, in name order.
+	Offset int
+	// Limit caps the number of configurations returned; 0 means no limit.
+	Limit int
+}
+
+// List lists all saved configurations, with their metadata.
+func (m *Manager) List(storePath string) ([]ConfigInfo, error) {
+	return m.ListWithOptions(storePath, ListOptions{})
+}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), configExtension) {
-			continue
+// ListWithOptions lists the saved configurations selected by opts, in
+// name order.
+func (m *Manager) ListWithOptions(storePath string, opts ListOptions) ([]ConfigInfo, error) {
+	var configs []ConfigInfo
+	for info, err := range m.ListIter(storePath, opts) {
+		if err != nil {
+			return nil, err
 		}
+		configs = append(configs, info)
+	}
+	return configs, nil
+}
 
-		configName := strings.TrimSuffix(entry.Name(), configExtension)
-		info := ConfigInfo{Name: configName}
+// ListIter yields the saved configurations selected by opts, in name
+// order, reading each one's size and metadata only as it is yielded, so
+// callers can show rows as they are read. A failure to read the store is
+// yielded as the only error.
+func (m *Manager) ListIter(storePath string, opts ListOptions) iter.Seq2[ConfigInfo, error] {
+	return func(yield func(ConfigInfo, error) bool) {
+		if storePath == "" {
+			storePath = m.storePath
+		}
 
-		// Load metadata if available
-		metadataFile := filepath.Join(storePath, configName+".metadata.json")
-		if metadata, err := loadMetadata(metadataFile); err == nil {
-			info.Description = metadata.Description
-			info.SavedAt = metadata.SavedAt
-			info.SourcePath = metadata.SourcePath
+		// os.ReadDir sorts by file name, which sorts by configuration name
+		// since every config file has the same extension.
+		entries, err := os.ReadDir(storePath)
+		if os.IsNotExist(err) {
+			return
+		}
+		if err != nil {
+			yield(ConfigInfo{}, fmt.Errorf("failed to read store directory: %w", err))
+			return
 		}
 
-		// Get file size
-		configFile := filepath.Join(storePath, entry.Name())
-		if stat, err := os.Stat(configFile); err == nil {
-			info.Size = stat.Size()
+		configExtension := "." + m.configFileName
+		skipped, listed := 0, 0
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), configExtension) {
+				continue
+			}
+			if skipped < opts.Offset {
+				skipped++
+				continue
+			}
+			if opts.Limit > 0 && listed >= opts.Limit {
+				return
+			}
+			listed++
+
+			info := ConfigInfo{Name: strings.TrimSuffix(entry.Name(), configExtension)}
+			if stat, err := entry.Info(); err == nil {
+				info.Size = stat.Size()
+			}
+			if !opts.Lazy {
+				loadInfoMetadata(&info, storePath)
+			}
+			if !yield(info, nil) {
+				return
+			}
 		}
+	}
+}
 
-		configs = append(configs, info)
+// LoadInfo returns the information about a single saved configuration,
+// including its metadata.
+func (m *Manager) LoadInfo(name, storePath string) (*ConfigInfo, error) {
+	if storePath == "" {
+		storePath = m.storePath
 	}
 
-	return configs, nil
+	stat, err := os.Stat(filepath.Join(storePath, name+"."+m.configFileName))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("configuration '%s' not found", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration: %w", err)
+	}
+
+	info := &ConfigInfo{Name: name, Size: stat.Size()}
+	loadInfoMetadata(info, storePath)
+	return info, nil
+}
+
+// loadInfoMetadata fills in info from its metadata file, if there is one.
+func loadInfoMetadata(info *ConfigInfo, storePath string) {
+	metadata, err := loadMetadata(filepath.Join(storePath, info.Name+".metadata.json"))
+	if err != nil {
+		return
+	}
+	info.Description = metadata.Description
+	info.SavedAt = metadata.SavedAt
+	info.SourcePath = metadata.SourcePath
 }
 
 // Delete deletes a saved configuration.
@@ -254,7 +363,8 @@ func (m *Manager) Exists(name, storePath string) bool {
 	return err == nil
 }
 
-// copyFile copies a file from src to dst.
+// copyFile copies a file from src to dst. A new dst is created with
+// PrivateFileMode; an existing one keeps its mode.
 func copyFile(src, dst string) error {
 	sourceFile, err := os.Open(src)
 	if err != nil {
@@ -262,7 +372,7 @@ func copyFile(src, dst string) error {
 	}
 	defer sourceFile.Close()
 
-	destFile, err := os.Create(dst)
+	destFile, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, PrivateFileMode)
 	if err != nil {
 		return err
 	}
@@ -274,7 +384,7 @@ func copyFile(src, dst string) error {
 
 // saveMetadata saves metadata to a JSON file.
 func saveMetadata(filename string, metadata ConfigMetadata) error {
-	file, err := os.Create(filename)
+	file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, PrivateFileMode)
 	if err != nil {
 		return err
 	}
@@ -4,9 +4,15 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/difffmt"
 )
 
 func TestNewManager(t *testing.T) {
@@ -237,3 +243,174 @@ func TestConfigInfo_Fields(t *testing.T) {
 		t.Errorf("Size = %d, want 1024", info.Size)
 	}
 }
+
+func TestManager_Diff(t *testing.T) {
+	tmpDir := t.TempDir()
+	storePath := filepath.Join(tmpDir, "store")
+	configPath := filepath.Join(tmpDir, "config")
+
+	if err := os.WriteFile(configPath, []byte("context: dev\nnamespace: default\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	manager := NewManager("test-service", "config", "config")
+	opts := &Options{Name: "dev", ConfigPath: configPath, StorePath: storePath}
+	if err := manager.Save(opts); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	renderer := difffmt.New(false)
+	diff, err := manager.Diff(opts, renderer)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if diff != "" {
+		t.Errorf("Diff of unchanged config = %q, want empty", diff)
+	}
+
+	if err := os.WriteFile(configPath, []byte("context: dev\nnamespace: platform\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	diff, err = manager.Diff(opts, renderer)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if !strings.Contains(diff, "-namespace: default") || !strings.Contains(diff, "+namespace: platform") {
+		t.Errorf("Diff = %q, want namespace change", diff)
+	}
+
+	if _, err := manager.Diff(&Options{Name: "missing", ConfigPath: configPath, StorePath: storePath}, renderer); err == nil {
+		t.Error("Diff of missing configuration should return error")
+	}
+}
+
+// writeStore fills dir with n saved configurations and their metadata,
+// named config-000, config-001, and so on.
+func writeStore(tb testing.TB, dir string, n int) {
+	tb.Helper()
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("config-%03d", i)
+		if err := os.WriteFile(filepath.Join(dir, name+".config.yaml"), []byte(strings.Repeat("x", i+1)), 0o600); err != nil {
+			tb.Fatal(err)
+		}
+		metadata := ConfigMetadata{Description: "snapshot " + name, SavedAt: time.Unix(int64(i), 0)}
+		if err := saveMetadata(filepath.Join(dir, name+".metadata.json"), metadata); err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+// TestManager_ListWithOptions tests lazy metadata loading and pagination.
+func TestManager_ListWithOptions(t *testing.T) {
+	dir := t.TempDir()
+	writeStore(t, dir, 5)
+	manager := NewManager("test-service", "config.yaml", "default")
+
+	tests := []struct {
+		name      string
+		opts      ListOptions
+		wantNames []string
+	}{
+		{"all", ListOptions{}, []string{"config-000", "config-001", "config-002", "config-003", "config-004"}},
+		{"first page", ListOptions{Limit: 2}, []string{"config-000", "config-001"}},
+		{"second page", ListOptions{Offset: 2, Limit: 2}, []string{"config-002", "config-003"}},
+		{"last page", ListOptions{Offset: 4, Limit: 2}, []string{"config-004"}},
+		{"past the end", ListOptions{Offset: 5}, nil},
+		{"lazy", ListOptions{Lazy: true, Offset: 1, Limit: 1}, []string{"config-001"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs, err := manager.ListWithOptions(dir, tt.opts)
+			if err != nil {
+				t.Fatalf("ListWithOptions() error = %v", err)
+			}
+
+			var names []string
+			for _, info := range configs {
+				names = append(names, info.Name)
+				if info.Size == 0 {
+					t.Errorf("%s: Size = 0, want the file size", info.Name)
+				}
+				if hasMetadata := info.Description != ""; hasMetadata == tt.opts.Lazy {
+					t.Errorf("%s: Description = %q, want metadata loaded = %v", info.Name, info.Description, !tt.opts.Lazy)
+				}
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("ListWithOptions() names = %v, want %v", names, tt.wantNames)
+			}
+		})
+	}
+}
+
+// TestManager_ListIter tests that iteration stops when the caller stops.
+func TestManager_ListIter(t *testing.T) {
+	dir := t.TempDir()
+	writeStore(t, dir, 5)
+	manager := NewManager("test-service", "config.yaml", "default")
+
+	var names []string
+	for info, err := range manager.ListIter(dir, ListOptions{Lazy: true}) {
+		if err != nil {
+			t.Fatalf("ListIter() error = %v", err)
+		}
+		names = append(names, info.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+	if want := []string{"config-000", "config-001"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ListIter() names = %v, want %v", names, want)
+	}
+
+	for _, err := range manager.ListIter(filepath.Join(dir, "missing"), ListOptions{}) {
+		t.Errorf("ListIter() on a missing store yielded %v, want nothing", err)
+	}
+}
+
+// TestManager_LoadInfo tests loading a single configuration's information.
+func TestManager_LoadInfo(t *testing.T) {
+	dir := t.TempDir()
+	writeStore(t, dir, 3)
+	manager := NewManager("test-service", "config.yaml", "default")
+
+	info, err := manager.LoadInfo("config-002", dir)
+	if err != nil {
+		t.Fatalf("LoadInfo() error = %v", err)
+	}
+	want := ConfigInfo{Name: "config-002", Description: "snapshot config-002", SavedAt: time.Unix(2, 0), Size: 3}
+	if info.Name != want.Name || info.Description != want.Description || !info.SavedAt.Equal(want.SavedAt) || info.Size != want.Size {
+		t.Errorf("LoadInfo() = %+v, want %+v", *info, want)
+	}
+
+	if _, err := manager.LoadInfo("missing", dir); err == nil {
+		t.Error("LoadInfo() should fail for a missing configuration")
+	}
+}
+
+// BenchmarkManager_List lists a store of 800 saved configurations, the
+// size of a large shared kubeconfig store, eagerly, lazily, and one page
+// at a time.
+func BenchmarkManager_List(b *testing.B) {
+	dir := b.TempDir()
+	writeStore(b, dir, 800)
+	manager := NewManager("test-service", "config.yaml", "default")
+
+	for _, bm := range []struct {
+		name string
+		opts ListOptions
+	}{
+		{"eager", ListOptions{}},
+		{"lazy", ListOptions{Lazy: true}},
+		{"page", ListOptions{Offset: 400, Limit: 50}},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := manager.ListWithOptions(dir, bm.opts); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Modes for what dev-env writes. Environment files, history, and saved
+// configurations can hold account IDs and tokens, so only the owner may
+// read them.
+const (
+	PrivateDirMode  os.FileMode = 0o700
+	PrivateFileMode os.FileMode = 0o600
+)
+
+// PermissionIssue reports a file or directory that the owner's group or
+// other users can access.
+type PermissionIssue struct {
+	Path string
+	Mode os.FileMode
+	// Want is Mode without the group and other bits.
+	Want os.FileMode
+}
+
+// String describes the issue for display.
+func (i PermissionIssue) String() string {
+	return fmt.Sprintf("%s is accessible by group or others (%04o, want %04o)", i.Path, i.Mode, i.Want)
+}
+
+// PermissionChecksSupported reports whether CheckPermissions inspects file
+// modes on this platform. Windows has no POSIX permission bits to check.
+func PermissionChecksSupported() bool {
+	return posixPermissions
+}
+
+// CheckPermissions walks roots, which may be files or directories, and
+// returns everything in them that is group or world accessible, ordered by
+// path. Missing roots are skipped, symlinks are not followed, and nothing is
+// changed; see FixPermissions.
+func CheckPermissions(roots ...string) ([]PermissionIssue, error) {
+	if !posixPermissions {
+		return nil, nil
+	}
+
+	var issues []PermissionIssue
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root && errors.Is(err, fs.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			if d.Type()&fs.ModeSymlink != 0 {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if mode := info.Mode().Perm(); mode&0o077 != 0 {
+				issues = append(issues, PermissionIssue{Path: path, Mode: mode, Want: mode &^ 0o077})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to check permissions of %s: %w", root, err)
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues, nil
+}
+
+// FixPermissions applies the wanted mode of each issue.
+func FixPermissions(issues []PermissionIssue) error {
+	for _, issue := range issues {
+		if err := os.Chmod(issue.Path, issue.Want); err != nil {
+			return fmt.Errorf("failed to fix permissions of %s: %w", issue.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package config
+
+// posixPermissions is false where file modes do not carry POSIX permission
+// bits, e.g. on Windows, so permission checks are skipped.
+const posixPermissions = false
@@ -0,0 +1,122 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// skipWithoutPOSIXPermissions skips tests of permission bits on Windows.
+func skipWithoutPOSIXPermissions(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX permissions are not supported on Windows")
+	}
+}
+
+// writeWithMode creates path with exactly mode, regardless of the umask.
+func writeWithMode(t *testing.T, path string, mode os.FileMode) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCheckPermissions tests which files and directories are reported.
+func TestCheckPermissions(t *testing.T) {
+	skipWithoutPOSIXPermissions(t)
+
+	root := t.TempDir()
+	if err := os.Chmod(root, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	envDir := filepath.Join(root, "environments")
+	if err := os.Mkdir(envDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(envDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeWithMode(t, filepath.Join(envDir, "prod.yaml"), 0o644)
+	writeWithMode(t, filepath.Join(envDir, "dev.yaml"), 0o600)
+	writeWithMode(t, filepath.Join(root, "history.json"), 0o640)
+	if err := os.Symlink("/etc/passwd", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	issues, err := CheckPermissions(root, filepath.Join(root, "missing"))
+	if err != nil {
+		t.Fatalf("CheckPermissions() error = %v", err)
+	}
+
+	want := []PermissionIssue{
+		{Path: envDir, Mode: 0o755, Want: 0o700},
+		{Path: filepath.Join(envDir, "prod.yaml"), Mode: 0o644, Want: 0o600},
+		{Path: filepath.Join(root, "history.json"), Mode: 0o640, Want: 0o600},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("CheckPermissions() = %v, want %v", issues, want)
+	}
+	for i := range want {
+		if issues[i] != want[i] {
+			t.Errorf("issue %d = %+v, want %+v", i, issues[i], want[i])
+		}
+	}
+}
+
+// TestFixPermissions tests that fixing leaves nothing to report.
+func TestFixPermissions(t *testing.T) {
+	skipWithoutPOSIXPermissions(t)
+
+	root := t.TempDir()
+	if err := os.Chmod(root, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(root, "prod.yaml")
+	writeWithMode(t, path, 0o644)
+
+	issues, err := CheckPermissions(root)
+	if err != nil || len(issues) != 2 {
+		t.Fatalf("CheckPermissions() = %v, %v, want 2 issues", issues, err)
+	}
+	if err := FixPermissions(issues); err != nil {
+		t.Fatalf("FixPermissions() error = %v", err)
+	}
+
+	if issues, _ := CheckPermissions(root); len(issues) != 0 {
+		t.Errorf("CheckPermissions() after fix = %v, want none", issues)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %04o, want 0600", info.Mode().Perm())
+	}
+}
+
+// TestManager_SavePrivate tests that the store and saved files are private.
+func TestManager_SavePrivate(t *testing.T) {
+	skipWithoutPOSIXPermissions(t)
+
+	tmp := t.TempDir()
+	source := filepath.Join(tmp, "config")
+	writeWithMode(t, source, 0o644)
+	store := filepath.Join(tmp, "store")
+
+	manager := NewManager("test-service", "config", ".test/config")
+	if err := manager.Save(&Options{Name: "work", ConfigPath: source, StorePath: store}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	issues, err := CheckPermissions(store)
+	if err != nil {
+		t.Fatalf("CheckPermissions() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("CheckPermissions() = %v, want a private store", issues)
+	}
+}
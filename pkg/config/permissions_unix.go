@@ -0,0 +1,9 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package config
+
+// posixPermissions is true where file modes carry POSIX permission bits.
+const posixPermissions = true
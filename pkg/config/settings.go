@@ -0,0 +1,215 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// DefaultEnvironmentEnvVar names the environment `dev-env switch-all`
+// switches to when none is selected, overriding Settings.DefaultEnvironment.
+const DefaultEnvironmentEnvVar = "DEVENV_DEFAULT"
+
+// Settings holds user preferences for dev-env itself, as opposed to the
+// configurations it manages.
+type Settings struct {
+	// DisabledServices are left out when no services are named explicitly,
+	// e.g. by `dev-env status` without --service.
+	DisabledServices []string `yaml:"disabledServices,omitempty"`
+
+	// DefaultServices are the services, or "@category" selectors, checked
+	// when none are named explicitly. All services are checked if unset.
+	// DisabledServices are left out of them.
+	DefaultServices []string `yaml:"defaultServices,omitempty"`
+
+	// DefaultEnvironment is the environment `dev-env switch-all` switches
+	// to when neither --env, --from-file, nor --interactive is given.
+	DefaultEnvironment string `yaml:"defaultEnvironment,omitempty"`
+
+	// AWSRegions are accepted by value validation in addition to the
+	// built-in list, for regions launched since it was written.
+	AWSRegions []string `yaml:"awsRegions,omitempty"`
+
+	// HookAllowlist, if set, restricts hooks and activate and deactivate
+	// scripts to commands starting with one of these prefixes, for
+	// locked-down machines.
+	HookAllowlist []string `yaml:"hookAllowlist,omitempty"`
+
+	// FileSnapshots copies the configuration files claimed by the
+	// switchers, such as the kubeconfig, into a snapshot before each
+	// switch, so `dev-env restore-files` can put them back.
+	FileSnapshots bool `yaml:"fileSnapshots,omitempty"`
+
+	// FileSnapshotRetention is how many file snapshots to keep, or
+	// environment.DefaultFileSnapshotRetention if unset.
+	FileSnapshotRetention int `yaml:"fileSnapshotRetention,omitempty"`
+
+	// RecentSwitchWindow is how recent another user's switch of this account
+	// must be for switch-all to warn before switching away from it, or
+	// environment.DefaultRecentSwitchWindow if unset.
+	RecentSwitchWindow time.Duration `yaml:"recentSwitchWindow,omitempty"`
+
+	// StatusChecks override the status options for individual services,
+	// keyed by service name, in both `dev-env status` and the TUI.
+	StatusChecks map[string]status.ServiceCheckOptions `yaml:"statusChecks,omitempty"`
+
+	// ExternalCheckers enables the status checker executables in
+	// DefaultCheckersDir. They run with the user's privileges, so loading
+	// them is opt-in.
+	ExternalCheckers bool `yaml:"externalCheckers,omitempty"`
+
+	// ExternalSwitchers enables the switcher executables in
+	// DefaultSwitchersDir, which are opt-in like ExternalCheckers.
+	ExternalSwitchers bool `yaml:"externalSwitchers,omitempty"`
+
+	// OpenTelemetry exports a span and metrics for every switch-all, as
+	// with --otel, to the OTLP endpoint set by the OTEL_* environment
+	// variables.
+	OpenTelemetry bool `yaml:"openTelemetry,omitempty"`
+
+	// NotifyWebhook, if set, receives a POST with the result of every
+	// switch-all as JSON, as with --notify-webhook.
+	NotifyWebhook string `yaml:"notifyWebhook,omitempty"`
+
+	// NotifyDesktop shows a desktop notification when switch-all
+	// finishes, as with --notify-desktop.
+	NotifyDesktop bool `yaml:"notifyDesktop,omitempty"`
+
+	// CommandSwitchers define services switched by running commands, keyed
+	// by service name. Environment files configure them under `extra`.
+	CommandSwitchers map[string]environment.CommandTemplates `yaml:"commandSwitchers,omitempty"`
+}
+
+// DefaultBaseDir returns ~/.gzh/dev-env, which holds environment files,
+// settings, history, and caches.
+func DefaultBaseDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env")
+}
+
+// DefaultSettingsPath returns ~/.gzh/dev-env/settings.yaml.
+func DefaultSettingsPath() string {
+	return filepath.Join(DefaultBaseDir(), "settings.yaml")
+}
+
+// DefaultCheckersDir returns ~/.gzh/dev-env/checkers.d, which holds
+// external status checker executables.
+func DefaultCheckersDir() string {
+	return filepath.Join(DefaultBaseDir(), "checkers.d")
+}
+
+// DefaultSwitchersDir returns ~/.gzh/dev-env/switchers.d, which holds
+// external switcher executables.
+func DefaultSwitchersDir() string {
+	return filepath.Join(DefaultBaseDir(), "switchers.d")
+}
+
+// LoadSettings reads settings from path. A missing file yields empty settings.
+func LoadSettings(path string) (*Settings, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Settings{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read settings: %w", err)
+	}
+
+	var settings Settings
+	if err := yaml.Unmarshal(data, &settings); err != nil {
+		return nil, fmt.Errorf("failed to parse settings %s: %w", path, err)
+	}
+	return &settings, nil
+}
+
+// Save writes the settings to path, creating its directory if needed.
+func (s *Settings) Save(path string) error {
+	data, err := yaml.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal settings: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create settings directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, PrivateFileMode); err != nil {
+		return fmt.Errorf("failed to write settings: %w", err)
+	}
+	return nil
+}
+
+// ServiceEnabled reports whether service is not listed in DisabledServices.
+func (s *Settings) ServiceEnabled(service string) bool {
+	for _, disabled := range s.DisabledServices {
+		if disabled == service {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectCheckers returns the checkers from all to run, in the order of
+// all. If services names any, as service names or "@category" selectors,
+// those are selected; otherwise DefaultServices are, or all services if it
+// is unset, less DisabledServices.
+func (s *Settings) SelectCheckers(all []status.ServiceChecker, services []string) ([]status.ServiceChecker, error) {
+	if len(services) > 0 {
+		selectors, err := normalizeSelectors(services)
+		if err != nil {
+			return nil, err
+		}
+		return status.SelectCheckers(all, selectors), nil
+	}
+
+	selectors, err := normalizeSelectors(s.DefaultServices)
+	if err != nil {
+		return nil, fmt.Errorf("defaultServices: %w", err)
+	}
+	var enabled []status.ServiceChecker
+	for _, checker := range status.SelectCheckers(all, selectors) {
+		if s.ServiceEnabled(checker.Name()) {
+			enabled = append(enabled, checker)
+		}
+	}
+	return enabled, nil
+}
+
+// normalizeSelectors lowercases service selectors, resolves the k8s alias,
+// and checks that categories exist.
+func normalizeSelectors(services []string) ([]string, error) {
+	selectors := make([]string, 0, len(services))
+	for _, service := range services {
+		selector := strings.ToLower(strings.TrimSpace(service))
+		if strings.HasPrefix(selector, status.CategorySelectorPrefix) {
+			if _, err := status.ParseCategory(selector); err != nil {
+				return nil, err
+			}
+		}
+		if selector == "k8s" {
+			selector = "kubernetes"
+		}
+		selectors = append(selectors, selector)
+	}
+	return selectors, nil
+}
+
+// EnvironmentOrDefault returns name if it is set, and otherwise the default
+// environment: $DEVENV_DEFAULT, or DefaultEnvironment if that is unset. It
+// returns "" if there is no default.
+func (s *Settings) EnvironmentOrDefault(name string) string {
+	if name = strings.TrimSpace(name); name != "" {
+		return name
+	}
+	if name = strings.TrimSpace(os.Getenv(DefaultEnvironmentEnvVar)); name != "" {
+		return name
+	}
+	return strings.TrimSpace(s.DefaultEnvironment)
+}
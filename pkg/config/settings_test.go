@@ -0,0 +1,215 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TestSettings_SaveLoad tests a settings round trip and the missing-file default.
+func TestSettings_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "settings.yaml")
+
+	empty, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() on missing file error = %v", err)
+	}
+	if !empty.ServiceEnabled("aws") {
+		t.Error("services should be enabled by default")
+	}
+
+	settings := &Settings{DisabledServices: []string{"azure", "ssh"}}
+	if err := settings.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded, settings) {
+		t.Errorf("LoadSettings() = %+v, want %+v", loaded, settings)
+	}
+	if loaded.ServiceEnabled("ssh") || !loaded.ServiceEnabled("aws") {
+		t.Errorf("ServiceEnabled() does not match DisabledServices %v", loaded.DisabledServices)
+	}
+}
+
+// TestSettings_RecentSwitchWindow tests reading the window as a duration string.
+func TestSettings_RecentSwitchWindow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	if err := os.WriteFile(path, []byte("recentSwitchWindow: 45m\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if settings.RecentSwitchWindow != 45*time.Minute {
+		t.Errorf("RecentSwitchWindow = %v, want %v", settings.RecentSwitchWindow, 45*time.Minute)
+	}
+}
+
+// TestSettings_StatusChecks tests reading per-service status options.
+func TestSettings_StatusChecks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	content := "statusChecks:\n  kubernetes:\n    checkHealth: true\n  azure:\n    checkHealth: false\n    timeout: 20s\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+
+	kubernetes, azure := settings.StatusChecks["kubernetes"], settings.StatusChecks["azure"]
+	if kubernetes.CheckHealth == nil || !*kubernetes.CheckHealth || kubernetes.Timeout != 0 {
+		t.Errorf("statusChecks.kubernetes = %+v, want checkHealth true", kubernetes)
+	}
+	if azure.CheckHealth == nil || *azure.CheckHealth || azure.Timeout != 20*time.Second {
+		t.Errorf("statusChecks.azure = %+v, want checkHealth false, timeout 20s", azure)
+	}
+}
+
+// TestSettings_EnvironmentOrDefault tests that an explicit environment wins
+// over $DEVENV_DEFAULT, which wins over defaultEnvironment.
+func TestSettings_EnvironmentOrDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		explicit string
+		envVar   string
+		setting  string
+		want     string
+	}{
+		{"explicit environment", "staging", "dev", "prod", "staging"},
+		{"environment variable", "", "dev", "prod", "dev"},
+		{"setting", "", "", "prod", "prod"},
+		{"blank values are unset", " ", " ", " prod ", "prod"},
+		{"no default", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(DefaultEnvironmentEnvVar, tt.envVar)
+			settings := &Settings{DefaultEnvironment: tt.setting}
+			if got := settings.EnvironmentOrDefault(tt.explicit); got != tt.want {
+				t.Errorf("EnvironmentOrDefault(%q) = %q, want %q", tt.explicit, got, tt.want)
+			}
+		})
+	}
+}
+
+// stubChecker is a status checker that only has a name and a category.
+type stubChecker struct {
+	name     string
+	category status.Category
+}
+
+func (c stubChecker) Name() string              { return c.name }
+func (c stubChecker) Category() status.Category { return c.category }
+
+func (c stubChecker) CheckStatus(context.Context) (*status.ServiceStatus, error) {
+	return &status.ServiceStatus{Name: c.name}, nil
+}
+
+func (c stubChecker) CheckHealth(context.Context) (*status.HealthStatus, error) {
+	return &status.HealthStatus{}, nil
+}
+
+// TestSettings_SelectCheckers tests that the default services are honored
+// and that naming services overrides them.
+func TestSettings_SelectCheckers(t *testing.T) {
+	all := []status.ServiceChecker{
+		stubChecker{"aws", status.CategoryCloud},
+		stubChecker{"azure", status.CategoryCloud},
+		stubChecker{"docker", status.CategoryContainer},
+		stubChecker{"kubernetes", status.CategoryContainer},
+		stubChecker{"ssh", status.CategoryAccess},
+	}
+
+	tests := []struct {
+		name     string
+		settings Settings
+		services []string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name: "all by default",
+			want: []string{"aws", "azure", "docker", "kubernetes", "ssh"},
+		},
+		{
+			name:     "default services",
+			settings: Settings{DefaultServices: []string{"ssh", "AWS", "@container"}},
+			want:     []string{"aws", "docker", "kubernetes", "ssh"},
+		},
+		{
+			name:     "disabled left out of default services",
+			settings: Settings{DefaultServices: []string{"@cloud", "k8s"}, DisabledServices: []string{"azure"}},
+			want:     []string{"aws", "kubernetes"},
+		},
+		{
+			name:     "disabled left out of all",
+			settings: Settings{DisabledServices: []string{"azure"}},
+			want:     []string{"aws", "docker", "kubernetes", "ssh"},
+		},
+		{
+			name:     "services override defaults",
+			settings: Settings{DefaultServices: []string{"aws"}, DisabledServices: []string{"azure"}},
+			services: []string{"azure", "ssh"},
+			want:     []string{"azure", "ssh"},
+		},
+		{
+			name:     "unknown category in services",
+			services: []string{"@nope"},
+			wantErr:  true,
+		},
+		{
+			name:     "unknown category in defaults",
+			settings: Settings{DefaultServices: []string{"@nope"}},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkers, err := tt.settings.SelectCheckers(all, tt.services)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectCheckers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var got []string
+			for _, checker := range checkers {
+				got = append(got, checker.Name())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectCheckers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSettings_DefaultServices tests reading the default services.
+func TestSettings_DefaultServices(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.yaml")
+	if err := os.WriteFile(path, []byte("defaultServices: [aws, \"@container\"]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := LoadSettings(path)
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if want := []string{"aws", "@container"}; !reflect.DeepEqual(settings.DefaultServices, want) {
+		t.Errorf("DefaultServices = %v, want %v", settings.DefaultServices, want)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchDebounce is how long a watched file must be quiet before a
+// change is reported.
+const DefaultWatchDebounce = 500 * time.Millisecond
+
+// ConfigChangeEvent reports that a watched configuration file changed.
+// Err is set instead of Path when the underlying watcher fails.
+type ConfigChangeEvent struct {
+	Path string
+	Time time.Time
+	Err  error
+}
+
+// Watch watches opts.ConfigPath and sends a ConfigChangeEvent once the file
+// has had no further writes for the debounce interval, so an editor saving
+// in several steps yields a single event. The parent directory is watched
+// so files replaced by rename are still tracked. The channel is closed when
+// ctx is cancelled.
+func (m *Manager) Watch(ctx context.Context, opts *Options) (<-chan ConfigChangeEvent, error) {
+	if opts.ConfigPath == "" {
+		return nil, fmt.Errorf("configuration path is required")
+	}
+
+	path, err := filepath.Abs(opts.ConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve configuration path: %w", err)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("failed to watch %s: %w", filepath.Dir(path), err)
+	}
+
+	debounce := m.watchDebounce
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+
+	events := make(chan ConfigChangeEvent)
+	go func() {
+		defer func() { _ = watcher.Close() }()
+		loop := &watchLoop{paths: map[string]bool{path: true}, debounce: debounce, afterFunc: afterFunc}
+		loop.run(ctx, watcher.Events, watcher.Errors, events)
+	}()
+
+	return events, nil
+}
+
+// watchTimer is the part of *time.Timer a watchLoop uses.
+type watchTimer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// afterFunc is time.AfterFunc returning a watchTimer.
+func afterFunc(d time.Duration, f func()) watchTimer {
+	return time.AfterFunc(d, f)
+}
+
+// watchLoop turns raw watcher events for the files in paths into debounced
+// change events. Each file has its own timer, reset by every raw event.
+type watchLoop struct {
+	paths     map[string]bool
+	debounce  time.Duration
+	afterFunc func(d time.Duration, f func()) watchTimer
+}
+
+// pendingChange is a file's debounce timer. Its generation tells the timer
+// apart from earlier ones for the same file.
+type pendingChange struct {
+	timer      watchTimer
+	generation int
+}
+
+// firing is a debounce timer handing its file back to the loop.
+type firing struct {
+	path       string
+	generation int
+}
+
+// run sends the change events for raw and errs to events until ctx is
+// cancelled or the watcher stops, then closes events.
+func (l *watchLoop) run(ctx context.Context, raw <-chan fsnotify.Event, errs <-chan error, events chan<- ConfigChangeEvent) {
+	defer close(events)
+
+	timers := make(map[string]*pendingChange)
+	defer func() {
+		for _, pending := range timers {
+			pending.timer.Stop()
+		}
+	}()
+	var generation int
+
+	// Timers fire on their own goroutines; they hand the path back here so
+	// that only this goroutine sends events.
+	fired := make(chan firing)
+
+	send := func(event ConfigChangeEvent) bool {
+		select {
+		case events <- event:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event, ok := <-raw:
+			if !ok {
+				return
+			}
+			if !l.paths[event.Name] || event.Op == fsnotify.Chmod {
+				continue
+			}
+			if pending, exists := timers[event.Name]; exists && pending.timer.Stop() {
+				pending.timer.Reset(l.debounce)
+				continue
+			}
+			// A timer that could not be stopped has fired and may still
+			// be handing its path over; a new generation replaces it, so
+			// the change is reported once, a debounce after this event.
+			generation++
+			fire := firing{path: event.Name, generation: generation}
+			timers[fire.path] = &pendingChange{
+				timer: l.afterFunc(l.debounce, func() {
+					select {
+					case fired <- fire:
+					case <-ctx.Done():
+					}
+				}),
+				generation: fire.generation,
+			}
+
+		case fire := <-fired:
+			if pending, exists := timers[fire.path]; !exists || pending.generation != fire.generation {
+				continue
+			}
+			delete(timers, fire.path)
+			if !send(ConfigChangeEvent{Path: fire.path, Time: time.Now()}) {
+				return
+			}
+
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			if !send(ConfigChangeEvent{Time: time.Now(), Err: err}) {
+				return
+			}
+		}
+	}
+}
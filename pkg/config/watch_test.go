@@ -0,0 +1,187 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TestManager_Watch_Debounce tests that a burst of writes yields one event.
+func TestManager_Watch_Debounce(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config")
+	if err := os.WriteFile(configPath, []byte("v0"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewManager("test-service", "config", "config")
+	manager.watchDebounce = 100 * time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := manager.Watch(ctx, &Options{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+
+	// Unrelated files in the same directory are ignored.
+	if err := os.WriteFile(filepath.Join(dir, "other"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate an editor saving in several steps, ending with a rename.
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(configPath, []byte{byte('a' + i)}, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	tmp := filepath.Join(dir, "config.swp")
+	if err := os.WriteFile(tmp, []byte("final"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, configPath); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Err != nil {
+			t.Fatalf("event error = %v", event.Err)
+		}
+		if filepath.Base(event.Path) != "config" {
+			t.Errorf("event path = %s, want config", event.Path)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change event received")
+	}
+
+	select {
+	case event := <-events:
+		t.Errorf("unexpected second event: %+v", event)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
+
+// TestManager_Watch_Cancel tests that cancelling the context closes the channel.
+func TestManager_Watch_Cancel(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+
+	manager := NewManager("test-service", "config", "config")
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := manager.Watch(ctx, &Options{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("Watch() error = %v", err)
+	}
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+// TestManager_Watch_Errors tests invalid watch targets.
+func TestManager_Watch_Errors(t *testing.T) {
+	manager := NewManager("test-service", "config", "config")
+
+	if _, err := manager.Watch(context.Background(), &Options{}); err == nil {
+		t.Error("Watch() with empty path should return error")
+	}
+	missing := filepath.Join(t.TempDir(), "missing", "config")
+	if _, err := manager.Watch(context.Background(), &Options{ConfigPath: missing}); err == nil {
+		t.Error("Watch() in missing directory should return error")
+	}
+}
+
+// fakeTimer is a debounce timer that tests fire by hand. Stop reports
+// whether the timer was still pending, as time.Timer's does.
+type fakeTimer struct {
+	f       func()
+	stopped bool
+	fired   bool
+}
+
+func (f *fakeTimer) Stop() bool {
+	pending := !f.stopped && !f.fired
+	f.stopped = true
+	return pending
+}
+
+func (f *fakeTimer) Reset(time.Duration) bool {
+	pending := !f.stopped && !f.fired
+	f.stopped = false
+	return pending
+}
+
+// TestWatchLoop_DebounceBoundary tests that a raw event arriving just as
+// the debounce timer fires, before the loop has received the fired timer,
+// still yields a single change event.
+func TestWatchLoop_DebounceBoundary(t *testing.T) {
+	created := make(chan *fakeTimer, 2)
+	loop := &watchLoop{
+		paths:    map[string]bool{"/config": true},
+		debounce: time.Minute,
+		afterFunc: func(_ time.Duration, f func()) watchTimer {
+			timer := &fakeTimer{f: f}
+			created <- timer
+			return timer
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	raw := make(chan fsnotify.Event)
+	events := make(chan ConfigChangeEvent)
+	go loop.run(ctx, raw, nil, events)
+
+	raw <- fsnotify.Event{Name: "/config", Op: fsnotify.Write}
+	first := <-created
+	// The first timer fires, but its callback has not reached the loop yet.
+	first.fired = true
+	raw <- fsnotify.Event{Name: "/config", Op: fsnotify.Write}
+	var second *fakeTimer
+	select {
+	case second = <-created:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the fired timer was reset instead of replaced")
+	}
+
+	// The late callback of the first timer is dropped...
+	go first.f()
+	select {
+	case event := <-events:
+		t.Fatalf("event %+v from the superseded timer, want none", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// ...and the second timer reports the change once.
+	second.fired = true
+	go second.f()
+	select {
+	case event := <-events:
+		if event.Path != "/config" || event.Err != nil {
+			t.Errorf("event = %+v, want a change of /config", event)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change event received")
+	}
+	select {
+	case event := <-events:
+		t.Errorf("unexpected second event: %+v", event)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package difffmt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultContext is the number of unchanged lines shown around each change.
+const DefaultContext = 3
+
+// ANSI color codes used for diff output.
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorCyan   = "\033[36m"
+	colorBold   = "\033[1m"
+	colorReset  = "\033[0m"
+	noneMarker  = "(none)"
+	arrowMarker = "→"
+)
+
+// Renderer renders diffs, optionally colorized.
+type Renderer struct {
+	UseColor bool
+	// Context is the number of unchanged lines shown around each hunk.
+	Context int
+}
+
+// New creates a renderer with the default context size.
+func New(useColor bool) *Renderer {
+	return &Renderer{UseColor: useColor, Context: DefaultContext}
+}
+
+// FieldChange describes a single changed field in a structured diff.
+// An empty Old means the field was added; an empty New means it was removed.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// opKind identifies a line-level edit operation.
+type opKind int
+
+const (
+	opEqual opKind = iota
+	opDelete
+	opInsert
+)
+
+// lineOp is a single line in an edit script.
+type lineOp struct {
+	kind opKind
+	text string
+	// oldLine and newLine are 1-based positions of the line before it was
+	// applied in the old and new text.
+	oldLine int
+	newLine int
+}
+
+// Unified renders a unified diff between oldText and newText. It returns an
+// empty string when the texts are identical.
+func (r *Renderer) Unified(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var sb strings.Builder
+	sb.WriteString(r.colorize("--- "+oldName, colorBold))
+	sb.WriteString("\n")
+	sb.WriteString(r.colorize("+++ "+newName, colorBold))
+	sb.WriteString("\n")
+
+	for _, hunk := range r.hunks(ops) {
+		r.writeHunk(&sb, ops[hunk[0]:hunk[1]])
+	}
+
+	return sb.String()
+}
+
+// Fields renders a table of field changes, one per line, as "field  old → new".
+func (r *Renderer) Fields(changes []FieldChange) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, c := range changes {
+		if len(c.Field) > width {
+			width = len(c.Field)
+		}
+	}
+
+	var sb strings.Builder
+	for _, c := range changes {
+		oldValue, newValue := noneMarker, noneMarker
+		if c.Old != "" {
+			oldValue = r.colorize(c.Old, colorRed)
+		}
+		if c.New != "" {
+			newValue = r.colorize(c.New, colorGreen)
+		}
+
+		sb.WriteString(fmt.Sprintf("  %-*s  %s %s %s\n", width, c.Field, oldValue, arrowMarker, newValue))
+	}
+
+	return sb.String()
+}
+
+// hunks groups the edit script into [start, end) ranges of ops, each covering
+// a run of changes plus surrounding context. Changes separated by no more than
+// twice the context share a hunk.
+func (r *Renderer) hunks(ops []lineOp) [][2]int {
+	context := max(r.Context, 0)
+
+	var hunks [][2]int
+	for i, op := range ops {
+		if op.kind == opEqual {
+			continue
+		}
+
+		start := max(i-context, 0)
+		end := min(i+1+context, len(ops))
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+		} else {
+			hunks = append(hunks, [2]int{start, end})
+		}
+	}
+
+	return hunks
+}
+
+// writeHunk writes a single hunk with its @@ header.
+func (r *Renderer) writeHunk(sb *strings.Builder, ops []lineOp) {
+	oldStart, newStart := ops[0].oldLine, ops[0].newLine
+	oldCount, newCount := 0, 0
+	for _, op := range ops {
+		if op.kind != opInsert {
+			oldCount++
+		}
+		if op.kind != opDelete {
+			newCount++
+		}
+	}
+	// An empty range starts at the line before it, per the unified format.
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	sb.WriteString(r.colorize(fmt.Sprintf("@@ -%s +%s @@", hunkRange(oldStart, oldCount), hunkRange(newStart, newCount)), colorCyan))
+	sb.WriteString("\n")
+
+	for _, op := range ops {
+		switch op.kind {
+		case opEqual:
+			sb.WriteString(" " + op.text)
+		case opDelete:
+			sb.WriteString(r.colorize("-"+op.text, colorRed))
+		case opInsert:
+			sb.WriteString(r.colorize("+"+op.text, colorGreen))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// hunkRange formats a hunk range, omitting the count when it is 1.
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprintf("%d", start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// colorize wraps text in a color code if colors are enabled.
+func (r *Renderer) colorize(text, color string) string {
+	if !r.UseColor {
+		return text
+	}
+	return color + text + colorReset
+}
+
+// splitLines splits text into lines, ignoring a single trailing newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a line edit script from a to b using the longest common
+// subsequence. Configuration files are small, so the quadratic table is fine.
+func diffLines(a, b []string) []lineOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]lineOp, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, lineOp{kind: opEqual, text: a[i], oldLine: i + 1, newLine: j + 1})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			ops = append(ops, lineOp{kind: opInsert, text: b[j], oldLine: i + 1, newLine: j + 1})
+			j++
+		default:
+			ops = append(ops, lineOp{kind: opDelete, text: a[i], oldLine: i + 1, newLine: j + 1})
+			i++
+		}
+	}
+
+	return ops
+}
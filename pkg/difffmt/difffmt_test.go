@@ -0,0 +1,123 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package difffmt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
+)
+
+// readFixture reads a file from testdata.
+func readFixture(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return string(data)
+}
+
+// TestRenderer_Unified tests unified diff rendering against golden files.
+func TestRenderer_Unified(t *testing.T) {
+	oldText := readFixture(t, "old.yaml")
+	newText := readFixture(t, "new.yaml")
+
+	tests := []struct {
+		name     string
+		useColor bool
+		golden   string
+	}{
+		{name: "plain", useColor: false, golden: "unified.golden"},
+		{name: "color", useColor: true, golden: "unified_color.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(tt.useColor).Unified("a.yaml", "b.yaml", oldText, newText)
+			testutil.AssertGolden(t, tt.golden, got)
+		})
+	}
+}
+
+// TestRenderer_Fields tests field-change table rendering against golden files.
+func TestRenderer_Fields(t *testing.T) {
+	changes := []FieldChange{
+		{Field: "aws.region", Old: "us-east-1", New: "eu-west-1"},
+		{Field: "kubernetes.namespace", Old: "default", New: "platform"},
+		{Field: "docker.context", New: "remote"},
+		{Field: "ssh.config", Old: "~/.ssh/prod"},
+	}
+
+	tests := []struct {
+		name     string
+		useColor bool
+		golden   string
+	}{
+		{name: "plain", useColor: false, golden: "fields.golden"},
+		{name: "color", useColor: true, golden: "fields_color.golden"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.AssertGolden(t, tt.golden, New(tt.useColor).Fields(changes))
+		})
+	}
+}
+
+// TestRenderer_Identical tests that identical inputs render nothing.
+func TestRenderer_Identical(t *testing.T) {
+	r := New(true)
+
+	if got := r.Unified("a", "b", "same\n", "same\n"); got != "" {
+		t.Errorf("Unified() = %q, want empty", got)
+	}
+	if got := r.Fields(nil); got != "" {
+		t.Errorf("Fields(nil) = %q, want empty", got)
+	}
+}
+
+// TestRenderer_UnifiedEdges tests diffs against empty input and zero context.
+func TestRenderer_UnifiedEdges(t *testing.T) {
+	tests := []struct {
+		name    string
+		context int
+		oldText string
+		newText string
+		want    string
+	}{
+		{
+			name:    "added file",
+			context: DefaultContext,
+			oldText: "",
+			newText: "a\nb\n",
+			want:    "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name:    "removed file",
+			context: DefaultContext,
+			oldText: "a\n",
+			newText: "",
+			want:    "--- old\n+++ new\n@@ -1 +0,0 @@\n-a\n",
+		},
+		{
+			name:    "zero context insertion",
+			context: 0,
+			oldText: "a\nc\n",
+			newText: "a\nb\nc\n",
+			want:    "--- old\n+++ new\n@@ -1,0 +2 @@\n+b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Renderer{Context: tt.context}
+			if got := r.Unified("old", "new", tt.oldText, tt.newText); got != tt.want {
+				t.Errorf("Unified() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
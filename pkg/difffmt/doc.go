@@ -0,0 +1,6 @@
+// Package difffmt renders differences between configurations for display.
+//
+// This package implements:
+//   - Renderer.Unified: Unified diffs of text such as YAML files
+//   - Renderer.Fields: Compact field-change tables for structured diffs
+package difffmt
@@ -5,12 +5,12 @@ package docker
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -30,6 +30,11 @@ func (d *Checker) Name() string {
 	return "docker"
 }
 
+// Category returns the service's category for grouping and selection.
+func (d *Checker) Category() status.Category {
+	return status.CategoryContainer
+}
+
 // CheckStatus checks Docker current status.
 func (d *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
 	st := &status.ServiceStatus{
@@ -44,6 +49,7 @@ func (d *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if Docker CLI is available
 	if !d.isCLIAvailable() {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
 		st.Details["error"] = "Docker CLI not found"
 		return st, nil
 	}
@@ -51,6 +57,7 @@ func (d *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if Docker daemon is running
 	if !d.isDaemonRunning(ctx) {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotRunning
 		st.Details["error"] = "Docker daemon not running"
 		return st, nil
 	}
@@ -75,7 +82,9 @@ func (d *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	return st, nil
 }
 
-// CheckHealth performs detailed health check for Docker.
+// CheckHealth performs detailed health check for Docker. It probes the
+// daemon, then its disk usage and running containers, recording each as a
+// sub-check.
 func (d *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
 	start := time.Now()
 	health := &status.HealthStatus{
@@ -85,41 +94,49 @@ func (d *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	}
 
 	// Test Docker connectivity with docker info
-	cmd := exec.CommandContext(ctx, "docker", "info", "--format", "{{.ServerVersion}}")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "docker", "info", "--format", "{{.ServerVersion}}")
 	health.Duration = time.Since(start)
 
 	if err != nil {
 		health.Status = status.StatusError
 		health.Message = fmt.Sprintf("Failed to connect to Docker daemon: %v", err)
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			health.Details["stderr"] = string(exitErr.Stderr)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
 		}
+		health.AddCheck("daemon", status.StatusError, health.Message, start)
 		return health, nil
 	}
 
 	health.Status = status.StatusActive
 	health.Message = "Docker daemon is running and accessible"
 	health.Details["server_version"] = strings.TrimSpace(string(output))
+	health.AddCheck("daemon", status.StatusActive, "Server version "+strings.TrimSpace(string(output)), start)
 
 	// Get additional Docker info
-	cmd = exec.CommandContext(ctx, "docker", "system", "df", "--format", "table")
-	dfOutput, err := cmd.Output()
+	dfStart := time.Now()
+	dfOutput, err := environment.RunCommand(ctx, "docker", "system", "df", "--format", "{{.Type}}: {{.Size}} ({{.Reclaimable}} reclaimable)")
 	if err == nil {
 		health.Details["disk_usage"] = string(dfOutput)
+		usage := strings.Split(strings.TrimSpace(string(dfOutput)), "\n")
+		health.AddCheck("disk", status.StatusActive, strings.Join(usage, "; "), dfStart)
+	} else {
+		health.AddCheck("disk", status.StatusUnknown, fmt.Sprintf("Failed to get disk usage: %v", err), dfStart)
 	}
 
 	// Check running containers count
-	cmd = exec.CommandContext(ctx, "docker", "ps", "-q")
-	psOutput, err := cmd.Output()
+	psStart := time.Now()
+	psOutput, err := environment.RunCommand(ctx, "docker", "ps", "-q")
 	if err == nil {
 		containerCount := len(strings.Split(strings.TrimSpace(string(psOutput)), "\n"))
 		if strings.TrimSpace(string(psOutput)) == "" {
 			containerCount = 0
 		}
 		health.Details["running_containers"] = containerCount
+		health.AddCheck("containers", status.StatusActive, fmt.Sprintf("%d running", containerCount), psStart)
+	} else {
+		health.AddCheck("containers", status.StatusUnknown, fmt.Sprintf("Failed to list containers: %v", err), psStart)
 	}
+	health.Duration = time.Since(start)
 
 	return health, nil
 }
@@ -132,15 +149,13 @@ func (d *Checker) isCLIAvailable() bool {
 
 // isDaemonRunning checks if Docker daemon is running.
 func (d *Checker) isDaemonRunning(ctx context.Context) bool {
-	cmd := exec.CommandContext(ctx, "docker", "info")
-	err := cmd.Run()
+	_, err := environment.RunCommand(ctx, "docker", "info")
 	return err == nil
 }
 
 // getCurrentContext gets the current Docker context.
 func (d *Checker) getCurrentContext(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "docker", "context", "show")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "docker", "context", "show")
 	if err != nil {
 		// If context command fails, assume default context
 		return DefaultContext, nil
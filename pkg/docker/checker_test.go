@@ -5,9 +5,13 @@ package docker
 
 import (
 	"context"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -87,3 +91,89 @@ func TestChecker_CheckHealth_ReturnsValidHealth(t *testing.T) {
 		t.Errorf("health.Status = %v, not a valid status type", health.Status)
 	}
 }
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
+
+// TestChecker_CheckHealth_SubChecks tests the daemon, disk, and container
+// sub-checks recorded by the health check.
+func TestChecker_CheckHealth_SubChecks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker is a shell script")
+	}
+	tests := []struct {
+		name       string
+		script     string
+		wantStatus status.StatusType
+		want       []status.SubCheck
+	}{
+		{
+			name: "daemon running",
+			script: `case "$1" in
+info) echo "27.3.1" ;;
+system) printf 'Images: 2.1GB (1.2GB (57%%) reclaimable)\nContainers: 12MB (0B (0%%) reclaimable)\n' ;;
+ps) printf 'abc123\ndef456\n' ;;
+esac
+`,
+			wantStatus: status.StatusActive,
+			want: []status.SubCheck{
+				{Name: "daemon", Status: status.StatusActive, Message: "Server version 27.3.1"},
+				{Name: "disk", Status: status.StatusActive, Message: "Images: 2.1GB (1.2GB (57%) reclaimable); Containers: 12MB (0B (0%) reclaimable)"},
+				{Name: "containers", Status: status.StatusActive, Message: "2 running"},
+			},
+		},
+		{
+			name: "disk usage fails",
+			script: `case "$1" in
+info) echo "27.3.1" ;;
+system) exit 1 ;;
+ps) ;;
+esac
+`,
+			wantStatus: status.StatusActive,
+			want: []status.SubCheck{
+				{Name: "daemon", Status: status.StatusActive, Message: "Server version 27.3.1"},
+				{Name: "disk", Status: status.StatusUnknown, Message: "Failed to get disk usage: exit status 1"},
+				{Name: "containers", Status: status.StatusActive, Message: "0 running"},
+			},
+		},
+		{
+			name:       "daemon down",
+			script:     "echo 'Cannot connect to the Docker daemon' >&2; exit 1\n",
+			wantStatus: status.StatusError,
+			want: []status.SubCheck{
+				{Name: "daemon", Status: status.StatusError, Message: "Failed to connect to Docker daemon: exit status 1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "docker"), []byte("#!/bin/sh\n"+tt.script), 0o700); err != nil { // #nosec G306 - test executable
+				t.Fatal(err)
+			}
+			t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+			health, err := NewChecker().CheckHealth(context.Background())
+			if err != nil {
+				t.Fatalf("CheckHealth() error = %v", err)
+			}
+			if health.Status != tt.wantStatus {
+				t.Errorf("CheckHealth() status = %v, want %v", health.Status, tt.wantStatus)
+			}
+			if len(health.Checks) != len(tt.want) {
+				t.Fatalf("Checks = %+v, want %d sub-checks", health.Checks, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := health.Checks[i]
+				if got.Name != want.Name || got.Status != want.Status || got.Message != want.Message {
+					t.Errorf("Checks[%d] = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
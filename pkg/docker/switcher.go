@@ -6,7 +6,6 @@ package docker
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
@@ -25,6 +24,28 @@ func (d *Switcher) Name() string {
 	return "docker"
 }
 
+// Claims returns the shared resources the switcher modifies: the Docker CLI configuration.
+func (d *Switcher) Claims() []string {
+	return []string{environment.ClaimDockerConfig}
+}
+
+// Validate checks that config is a Docker configuration.
+func (d *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.DockerConfig)
+	if !ok {
+		return fmt.Errorf("invalid Docker configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no Docker configuration provided")
+	}
+	return nil
+}
+
+// RequiredCLIs returns the docker CLI, which every switch runs.
+func (d *Switcher) RequiredCLIs(interface{}) []string {
+	return []string{"docker"}
+}
+
 // Switch switches to the specified Docker configuration.
 func (d *Switcher) Switch(ctx context.Context, config interface{}) error {
 	dockerConfig, ok := config.(*environment.DockerConfig)
@@ -34,8 +55,7 @@ func (d *Switcher) Switch(ctx context.Context, config interface{}) error {
 
 	// Set Docker context
 	if dockerConfig.Context != "" {
-		cmd := exec.CommandContext(ctx, "docker", "context", "use", dockerConfig.Context)
-		if err := cmd.Run(); err != nil {
+		if _, err := environment.RunCommand(ctx, "docker", "context", "use", dockerConfig.Context); err != nil {
 			return fmt.Errorf("failed to set Docker context: %w", err)
 		}
 	}
@@ -46,8 +66,7 @@ func (d *Switcher) Switch(ctx context.Context, config interface{}) error {
 // GetCurrentState retrieves the current Docker configuration state.
 func (d *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 	// Get current Docker context
-	cmd := exec.CommandContext(ctx, "docker", "context", "show")
-	contextOutput, _ := cmd.Output()
+	contextOutput, _ := environment.RunCommand(ctx, "docker", "context", "show")
 
 	return &environment.DockerConfig{
 		Context: strings.TrimSpace(string(contextOutput)),
@@ -58,3 +77,19 @@ func (d *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 func (d *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
 	return d.Switch(ctx, previousState)
 }
+
+// ListTargets returns the available Docker contexts.
+func (d *Switcher) ListTargets(ctx context.Context) ([]string, error) {
+	output, err := environment.RunCommand(ctx, "docker", "context", "ls", "--format", "{{.Name}}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker contexts: %w", err)
+	}
+
+	var targets []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if target := strings.TrimSpace(line); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,111 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// claimingSwitcher is a mock switcher that declares resource claims and
+// tracks how many switches holding each claim are in flight.
+type claimingSwitcher struct {
+	mockSwitcher
+	claims   []string
+	inFlight map[string]*atomic.Int32
+	overlap  *atomic.Bool
+	mu       *sync.Mutex
+}
+
+func (c *claimingSwitcher) Claims() []string {
+	return c.claims
+}
+
+func (c *claimingSwitcher) Switch(ctx context.Context, config interface{}) error {
+	for _, claim := range c.claims {
+		if c.inFlight[claim].Add(1) > 1 {
+			c.overlap.Store(true)
+		}
+	}
+	time.Sleep(30 * time.Millisecond)
+	for _, claim := range c.claims {
+		c.inFlight[claim].Add(-1)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mockSwitcher.Switch(ctx, config)
+}
+
+// TestEnvironmentSwitcher_ClaimsSerialize tests that switchers sharing a
+// claim are not run concurrently within a parallel group.
+func TestEnvironmentSwitcher_ClaimsSerialize(t *testing.T) {
+	inFlight := map[string]*atomic.Int32{ClaimKubeconfig: {}, ClaimDockerConfig: {}}
+	overlap := &atomic.Bool{}
+	mu := &sync.Mutex{}
+	newClaiming := func(name string, claims ...string) *claimingSwitcher {
+		return &claimingSwitcher{
+			mockSwitcher: *newMockSwitcher(name),
+			claims:       claims,
+			inFlight:     inFlight,
+			overlap:      overlap,
+			mu:           mu,
+		}
+	}
+
+	es := newTestSwitcher()
+	es.Register(newClaiming("aws", ClaimKubeconfig))
+	es.Register(newClaiming("kubernetes", ClaimKubeconfig))
+	es.Register(newClaiming("docker", ClaimDockerConfig))
+
+	env := &Environment{
+		Name: "test",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "p"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "c"}},
+			"docker":     {Docker: &DockerConfig{Context: "d"}},
+		},
+	}
+
+	start := time.Now()
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{Parallel: true})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if len(result.SwitchedServices) != 3 {
+		t.Errorf("SwitchedServices = %v, want all three", result.SwitchedServices)
+	}
+	if overlap.Load() {
+		t.Error("switchers sharing a claim ran concurrently")
+	}
+	// aws and kubernetes run back to back.
+	if elapsed < 60*time.Millisecond {
+		t.Errorf("SwitchEnvironment() took %v, want >= 60ms for serialized claims", elapsed)
+	}
+}
+
+// TestEnvironmentSwitcher_ClaimLanes tests grouping services by shared claims.
+func TestEnvironmentSwitcher_ClaimLanes(t *testing.T) {
+	claiming := func(name string, claims ...string) *claimingSwitcher {
+		return &claimingSwitcher{mockSwitcher: *newMockSwitcher(name), claims: claims}
+	}
+
+	es := newTestSwitcher()
+	es.Register(claiming("a", "file:x"))
+	es.Register(claiming("b", "file:y"))
+	es.Register(claiming("c", "file:y", "file:x"))
+	es.Register(claiming("d", "file:z"))
+	es.Register(newMockSwitcher("e"))
+
+	got := es.claimLanes([]string{"a", "b", "c", "d", "e"})
+	want := [][]string{{"a", "b", "c"}, {"d"}, {"e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("claimLanes() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,254 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// placeholderPattern matches a {{key}} placeholder in a command template.
+var placeholderPattern = regexp.MustCompile(`\{\{([A-Za-z0-9_.-]+)\}\}`)
+
+// CommandTemplates are the commands a CommandSwitcher runs. Each template
+// is validated like a hook command, then split into words as sh would, so
+// a quoted argument with spaces stays one word, and run as the command and
+// its arguments without a shell. {{key}} placeholders in a word are
+// replaced by the value of key, so a value with spaces stays one argument.
+type CommandTemplates struct {
+	// Switch switches the service, with values from the service's extra
+	// configuration. It is required.
+	Switch string `yaml:"switch"`
+	// Rollback restores a state printed by GetState, with values from that
+	// state. Switch is used if it is empty.
+	Rollback string `yaml:"rollback,omitempty"`
+	// GetState prints the current state as key=value lines. Without it, the
+	// service has no state and is not rolled back.
+	GetState string `yaml:"getState,omitempty"`
+}
+
+// CommandSwitcher implements ServiceSwitcher by running user-configured
+// commands, to switch services without a built-in switcher, e.g. a
+// `vault login` step. Its configuration is the service's Extra block.
+type CommandSwitcher struct {
+	name      string
+	templates CommandTemplates
+	run       CommandRunner
+}
+
+// NewCommandSwitcher creates a switcher for service name that runs the
+// given command templates. It fails if a template is invalid.
+func NewCommandSwitcher(name string, templates CommandTemplates) (*CommandSwitcher, error) {
+	if name == "" {
+		return nil, fmt.Errorf("command switcher name cannot be empty")
+	}
+	if templates.Switch == "" {
+		return nil, fmt.Errorf("command switcher %s: switch command is required", name)
+	}
+
+	for _, t := range []struct{ kind, command string }{
+		{"switch", templates.Switch},
+		{"rollback", templates.Rollback},
+		{"getState", templates.GetState},
+	} {
+		if t.command == "" {
+			continue
+		}
+		if err := ValidateHookCommand(t.command); err != nil {
+			return nil, fmt.Errorf("command switcher %s: invalid %s command: %w", name, t.kind, err)
+		}
+	}
+
+	return &CommandSwitcher{name: name, templates: templates, run: RunCommand}, nil
+}
+
+// Name returns the service name.
+func (c *CommandSwitcher) Name() string {
+	return c.name
+}
+
+// Switch runs the switch command with values from config, an ExtraConfig.
+func (c *CommandSwitcher) Switch(ctx context.Context, config interface{}) error {
+	values, ok := config.(ExtraConfig)
+	if !ok {
+		return fmt.Errorf("invalid %s configuration type", c.name)
+	}
+
+	if err := c.runTemplate(ctx, c.templates.Switch, values); err != nil {
+		return fmt.Errorf("failed to switch %s: %w", c.name, err)
+	}
+	return nil
+}
+
+// Validate checks that config is an ExtraConfig with a value for every
+// placeholder of the switch command.
+func (c *CommandSwitcher) Validate(config interface{}) error {
+	values, ok := config.(ExtraConfig)
+	if !ok {
+		return fmt.Errorf("invalid %s configuration type", c.name)
+	}
+	if _, err := expandCommand(c.templates.Switch, values); err != nil {
+		return fmt.Errorf("invalid %s configuration: %w", c.name, err)
+	}
+	return nil
+}
+
+// RequiredCLIs returns the executables the switcher's commands run.
+func (c *CommandSwitcher) RequiredCLIs(interface{}) []string {
+	var clis []string
+	for _, command := range []string{c.templates.Switch, c.templates.Rollback, c.templates.GetState} {
+		if words, _ := commandWords(command); len(words) > 0 && !slices.Contains(clis, words[0]) {
+			clis = append(clis, words[0])
+		}
+	}
+	return clis
+}
+
+// GetCurrentState runs the get-state command and parses its key=value
+// lines into an ExtraConfig. It returns nil without a get-state command.
+func (c *CommandSwitcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	if c.templates.GetState == "" {
+		return nil, nil
+	}
+
+	args, err := expandCommand(c.templates.GetState, nil)
+	if err != nil {
+		return nil, err
+	}
+	output, err := c.run(ctx, args[0], args[1:]...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s state: %w", c.name, err)
+	}
+	return parseState(output), nil
+}
+
+// Rollback runs the rollback command with values from previousState. A
+// nil state, from a switcher without a get-state command, is not rolled
+// back.
+func (c *CommandSwitcher) Rollback(ctx context.Context, previousState interface{}) error {
+	if previousState == nil {
+		return nil
+	}
+	state, ok := previousState.(ExtraConfig)
+	if !ok {
+		return fmt.Errorf("invalid %s state type", c.name)
+	}
+
+	command := c.templates.Rollback
+	if command == "" {
+		command = c.templates.Switch
+	}
+	if err := c.runTemplate(ctx, command, state); err != nil {
+		return fmt.Errorf("failed to roll back %s: %w", c.name, err)
+	}
+	return nil
+}
+
+// runTemplate expands command with values and runs it.
+func (c *CommandSwitcher) runTemplate(ctx context.Context, command string, values ExtraConfig) error {
+	args, err := expandCommand(command, values)
+	if err != nil {
+		return err
+	}
+	_, err = c.run(ctx, args[0], args[1:]...)
+	return err
+}
+
+// expandCommand splits command into words and replaces their placeholders
+// with values. It fails if a placeholder has no value.
+func expandCommand(command string, values ExtraConfig) ([]string, error) {
+	words, err := commandWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(words) == 0 {
+		return nil, fmt.Errorf("command is empty")
+	}
+
+	var missing []string
+	args := make([]string, len(words))
+	for i, word := range words {
+		args[i] = placeholderPattern.ReplaceAllStringFunc(word, func(placeholder string) string {
+			key := placeholderPattern.FindStringSubmatch(placeholder)[1]
+			value, ok := values[key]
+			if !ok {
+				missing = append(missing, key)
+			}
+			return value
+		})
+	}
+
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("no value for %s in command: %s", strings.Join(missing, ", "), command)
+	}
+	return args, nil
+}
+
+// commandWords splits command into words as sh would: whitespace separates
+// words, quoted text is part of the word it is in with the quotes removed,
+// and a backslash outside single quotes escapes the next character. An
+// unterminated quote is an error.
+func commandWords(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+			continue
+		case c == '\\' && i+1 < len(command):
+			i++
+			word.WriteByte(command[i])
+		case c == '\'' || c == '"':
+			end := closingQuote(command, i)
+			if end < 0 {
+				return nil, fmt.Errorf("command has an unterminated quote: %s", command)
+			}
+			text := command[i+1 : end]
+			if c == '"' {
+				text = doubleQuoteEscapes.Replace(text)
+			}
+			word.WriteString(text)
+			i = end
+		default:
+			word.WriteByte(c)
+		}
+		inWord = true
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// doubleQuoteEscapes removes the backslashes that escape characters inside
+// double quotes, as sh does.
+var doubleQuoteEscapes = strings.NewReplacer(`\"`, `"`, `\\`, `\`, `\$`, `$`, "\\`", "`")
+
+// parseState parses key=value lines, ignoring blank lines and lines
+// without "=".
+func parseState(output []byte) ExtraConfig {
+	state := ExtraConfig{}
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		state[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return state
+}
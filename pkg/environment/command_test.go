@@ -0,0 +1,303 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeRunner records the commands it is asked to run and answers them
+// from canned output keyed by command line.
+type fakeRunner struct {
+	commands [][]string
+	outputs  map[string]string
+	errs     map[string]error
+}
+
+func (f *fakeRunner) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	command := append([]string{name}, args...)
+	f.commands = append(f.commands, command)
+	line := strings.Join(command, " ")
+	return []byte(f.outputs[line]), f.errs[line]
+}
+
+// newTestCommandSwitcher creates a command switcher whose commands are
+// answered by runner.
+func newTestCommandSwitcher(t *testing.T, templates CommandTemplates, runner *fakeRunner) *CommandSwitcher {
+	t.Helper()
+	switcher, err := NewCommandSwitcher("vault", templates)
+	if err != nil {
+		t.Fatalf("NewCommandSwitcher() error = %v", err)
+	}
+	switcher.run = runner.run
+	return switcher
+}
+
+// TestNewCommandSwitcher tests template validation.
+func TestNewCommandSwitcher(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates CommandTemplates
+		wantErr   string
+	}{
+		{"valid", CommandTemplates{Switch: "vault login -method=oidc role={{role}}", GetState: "vault-state"}, ""},
+		{"missing switch", CommandTemplates{Rollback: "vault login"}, "switch command is required"},
+		{"unsafe switch", CommandTemplates{Switch: "vault login; rm -rf ~"}, "invalid switch command"},
+		{"unsafe rollback", CommandTemplates{Switch: "vault login", Rollback: "vault login $(cat token)"}, "invalid rollback command"},
+		{"unsafe get state", CommandTemplates{Switch: "vault login", GetState: "vault status | sh"}, "invalid getState command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCommandSwitcher("vault", tt.templates)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewCommandSwitcher() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewCommandSwitcher() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestCommandSwitcher_Switch tests that config values are substituted into
+// the switch command's arguments.
+func TestCommandSwitcher_Switch(t *testing.T) {
+	runner := &fakeRunner{}
+	switcher := newTestCommandSwitcher(t, CommandTemplates{
+		Switch: "vault login -method=oidc -path={{path}} role={{role}}",
+	}, runner)
+
+	config := ExtraConfig{"path": "oidc/dev", "role": "dev team"}
+	if err := switcher.Switch(context.Background(), config); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+
+	want := [][]string{{"vault", "login", "-method=oidc", "-path=oidc/dev", "role=dev team"}}
+	if !reflect.DeepEqual(runner.commands, want) {
+		t.Errorf("commands = %q, want %q", runner.commands, want)
+	}
+}
+
+// TestCommandSwitcher_Switch_Quoted tests that quoted arguments with spaces
+// stay one argument, as they would in a shell.
+func TestCommandSwitcher_Switch_Quoted(t *testing.T) {
+	runner := &fakeRunner{}
+	switcher := newTestCommandSwitcher(t, CommandTemplates{
+		Switch: `vault write auth/token "display_name=dev {{role}}" 'note=set by dev-env'`,
+	}, runner)
+
+	if err := switcher.Switch(context.Background(), ExtraConfig{"role": "team"}); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+
+	want := [][]string{{"vault", "write", "auth/token", "display_name=dev team", "note=set by dev-env"}}
+	if !reflect.DeepEqual(runner.commands, want) {
+		t.Errorf("commands = %q, want %q", runner.commands, want)
+	}
+}
+
+// TestCommandWords tests splitting commands into words as sh would.
+func TestCommandWords(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{command: "vault  login\trole=dev", want: []string{"vault", "login", "role=dev"}},
+		{command: `tool "a b" 'c d'`, want: []string{"tool", "a b", "c d"}},
+		{command: `tool pre"mid dle"post`, want: []string{"tool", "premid dlepost"}},
+		{command: `tool "say \"hi\""`, want: []string{"tool", `say "hi"`}},
+		{command: `tool 'no \escape'`, want: []string{"tool", `no \escape`}},
+		{command: `tool ""`, want: []string{"tool", ""}},
+		{command: `tool "open`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			got, err := commandWords(tt.command)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("commandWords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandWords() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCommandSwitcher_Switch_Errors tests missing values, wrong config
+// types, and failing commands.
+func TestCommandSwitcher_Switch_Errors(t *testing.T) {
+	runner := &fakeRunner{errs: map[string]error{
+		"vault login role=prod": &CommandError{Stderr: "permission denied\n", Err: errors.New("exit status 2")},
+	}}
+	switcher := newTestCommandSwitcher(t, CommandTemplates{Switch: "vault login role={{role}}"}, runner)
+	ctx := context.Background()
+
+	err := switcher.Switch(ctx, ExtraConfig{})
+	if err == nil || !strings.Contains(err.Error(), "no value for role") {
+		t.Errorf("Switch() error = %v, want a missing value error", err)
+	}
+	if len(runner.commands) != 0 {
+		t.Errorf("commands = %q, want none run with a missing value", runner.commands)
+	}
+
+	if err := switcher.Switch(ctx, &AWSConfig{}); err == nil {
+		t.Error("Switch() should fail for a non-extra configuration")
+	}
+
+	err = switcher.Switch(ctx, ExtraConfig{"role": "prod"})
+	if got := CommandStderr(err); got != "permission denied" {
+		t.Errorf("CommandStderr() = %q, want %q", got, "permission denied")
+	}
+}
+
+// TestCommandSwitcher_StateAndRollback tests that the state printed by the
+// get-state command is substituted into the rollback command.
+func TestCommandSwitcher_StateAndRollback(t *testing.T) {
+	tests := []struct {
+		name      string
+		templates CommandTemplates
+		want      []string
+	}{
+		{
+			name: "rollback command",
+			templates: CommandTemplates{
+				Switch:   "vault login role={{role}}",
+				Rollback: "vault token revoke -self role={{role}}",
+				GetState: "vault-state",
+			},
+			want: []string{"vault", "token", "revoke", "-self", "role=staging"},
+		},
+		{
+			name: "switch command reused",
+			templates: CommandTemplates{
+				Switch:   "vault login role={{role}}",
+				GetState: "vault-state",
+			},
+			want: []string{"vault", "login", "role=staging"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &fakeRunner{outputs: map[string]string{
+				"vault-state": "# current login\nrole = staging\naddr=https://vault:8200\n",
+			}}
+			switcher := newTestCommandSwitcher(t, tt.templates, runner)
+			ctx := context.Background()
+
+			state, err := switcher.GetCurrentState(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentState() error = %v", err)
+			}
+			wantState := ExtraConfig{"role": "staging", "addr": "https://vault:8200"}
+			if !reflect.DeepEqual(state, wantState) {
+				t.Errorf("GetCurrentState() = %v, want %v", state, wantState)
+			}
+
+			if err := switcher.Rollback(ctx, state); err != nil {
+				t.Fatalf("Rollback() error = %v", err)
+			}
+			if got := runner.commands[len(runner.commands)-1]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rollback command = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCommandSwitcher_NoState tests a switcher without a get-state command.
+func TestCommandSwitcher_NoState(t *testing.T) {
+	runner := &fakeRunner{}
+	switcher := newTestCommandSwitcher(t, CommandTemplates{Switch: "vault login"}, runner)
+	ctx := context.Background()
+
+	state, err := switcher.GetCurrentState(ctx)
+	if err != nil || state != nil {
+		t.Fatalf("GetCurrentState() = %v, %v, want nil", state, err)
+	}
+	if err := switcher.Rollback(ctx, state); err != nil {
+		t.Errorf("Rollback() error = %v", err)
+	}
+	if len(runner.commands) != 0 {
+		t.Errorf("commands = %q, want none", runner.commands)
+	}
+}
+
+// TestCommandSwitcher_Preflight tests checking a configuration and listing
+// the executables without running any command.
+func TestCommandSwitcher_Preflight(t *testing.T) {
+	runner := &fakeRunner{}
+	switcher := newTestCommandSwitcher(t, CommandTemplates{
+		Switch:   "vault login role={{role}}",
+		Rollback: "vault login role={{role}}",
+		GetState: "vault-state",
+	}, runner)
+
+	if err := switcher.Validate(ExtraConfig{"role": "prod"}); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+	if err := switcher.Validate(ExtraConfig{}); err == nil || !strings.Contains(err.Error(), "no value for role") {
+		t.Errorf("Validate() error = %v, want the missing role", err)
+	}
+	if err := switcher.Validate(&DockerConfig{}); err == nil {
+		t.Error("Validate() should reject a configuration of another type")
+	}
+	if got := switcher.RequiredCLIs(ExtraConfig{"role": "prod"}); !reflect.DeepEqual(got, []string{"vault", "vault-state"}) {
+		t.Errorf("RequiredCLIs() = %v, want [vault vault-state]", got)
+	}
+	if len(runner.commands) != 0 {
+		t.Errorf("commands = %q, want none", runner.commands)
+	}
+}
+
+// TestEnvironmentSwitcher_CommandSwitcher tests switching and rolling back
+// a command switcher configured through an environment's extra block.
+func TestEnvironmentSwitcher_CommandSwitcher(t *testing.T) {
+	runner := &fakeRunner{outputs: map[string]string{"vault-state": "role=staging\n"}}
+	vault := newTestCommandSwitcher(t, CommandTemplates{
+		Switch:   "vault login role={{role}}",
+		GetState: "vault-state",
+	}, runner)
+	failing := newMockSwitcher("docker")
+	failing.switchError = errors.New("docker failed")
+
+	es := newTestSwitcher()
+	es.Register(vault)
+	es.Register(failing)
+
+	env := &Environment{
+		Name: "prod",
+		Services: map[string]ServiceConfig{
+			"vault":  {Extra: ExtraConfig{"role": "prod"}},
+			"docker": {Docker: &DockerConfig{Context: "prod"}},
+		},
+		Dependencies: []string{"vault -> docker"},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{RollbackOnError: true})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should fail when docker fails")
+	}
+	if !result.RollbackPerformed {
+		t.Error("RollbackPerformed = false, want true")
+	}
+
+	want := [][]string{
+		{"vault-state"},
+		{"vault", "login", "role=prod"},
+		{"vault", "login", "role=staging"},
+	}
+	if !reflect.DeepEqual(runner.commands, want) {
+		t.Errorf("commands = %q, want %q", runner.commands, want)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// EnvironmentResolver loads a named environment, e.g. one referenced by
+// Environment.Compose.
+type EnvironmentResolver func(name string) (*Environment, error)
+
+// SearchPathResolver returns a resolver that finds environments by name or
+// alias in searchPaths, as FindEnvironmentFile does.
+func SearchPathResolver(searchPaths []string) EnvironmentResolver {
+	return func(name string) (*Environment, error) {
+		path, err := FindEnvironmentFile(searchPaths, name)
+		if err != nil {
+			return nil, err
+		}
+		return LoadEnvironmentFromFile(path)
+	}
+}
+
+// ExpandComposition returns env with the environments listed in its Compose
+// field merged in. Composed environments may compose others in turn; cycles
+// are an error.
+//
+// Services, dependencies, and hooks of the composed environments are merged
+// in Compose order, followed by env's own. An environment composed more
+// than once, such as a base shared by two composed environments,
+// contributes its hooks only the first time. A service configured differently
+// by two composed environments is a conflict; env's own configuration for a
+// service overrides the composed ones. Name, aliases, description,
+// activate/deactivate scripts, and timeout are taken from env alone.
+//
+// An environment without Compose is returned unchanged.
+func ExpandComposition(env *Environment, resolve EnvironmentResolver) (*Environment, error) {
+	merged, _, err := expandComposition(env, resolve, nil)
+	return merged, err
+}
+
+// expandComposition expands env; stack holds the names being expanded, for
+// cycle detection. It also returns the environments composed into env,
+// directly or through others, each once and in the order their hooks run.
+func expandComposition(env *Environment, resolve EnvironmentResolver, stack []string) (*Environment, []*Environment, error) {
+	if len(env.Compose) == 0 {
+		return env, nil, nil
+	}
+
+	stack = append(stack, env.Name)
+
+	merged := *env
+	merged.Compose = nil
+	merged.Services = make(map[string]ServiceConfig)
+	merged.Dependencies = nil
+	merged.PreHooks = nil
+	merged.PostHooks = nil
+
+	// owner records which composed environment configured each service.
+	owner := make(map[string]string)
+	seenDependency := make(map[string]bool)
+	addDependencies := func(dependencies []string) {
+		for _, dep := range dependencies {
+			if !seenDependency[dep] {
+				seenDependency[dep] = true
+				merged.Dependencies = append(merged.Dependencies, dep)
+			}
+		}
+	}
+
+	// parts are the composed environments as loaded, whose own hooks are
+	// merged once each.
+	var parts []*Environment
+	seenPart := make(map[string]bool)
+	addPart := func(part *Environment) {
+		if key := strings.ToLower(part.Name); !seenPart[key] {
+			seenPart[key] = true
+			parts = append(parts, part)
+		}
+	}
+
+	for _, name := range env.Compose {
+		for _, expanding := range stack {
+			if strings.EqualFold(expanding, name) {
+				return nil, nil, fmt.Errorf("environment composition cycle: %s -> %s", strings.Join(stack, " -> "), name)
+			}
+		}
+
+		loaded, err := resolve(name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to load composed environment '%s': %w", name, err)
+		}
+		part, nested, err := expandComposition(loaded, resolve, stack)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, p := range nested {
+			addPart(p)
+		}
+		addPart(loaded)
+
+		services := part.GetServiceNames()
+		sort.Strings(services)
+		for _, service := range services {
+			config := part.Services[service]
+			if previous, exists := owner[service]; exists {
+				if !reflect.DeepEqual(merged.Services[service], config) {
+					return nil, nil, fmt.Errorf("service '%s' is configured differently by composed environments '%s' and '%s'", service, previous, part.Name)
+				}
+				continue
+			}
+			owner[service] = part.Name
+			merged.Services[service] = config
+		}
+
+		addDependencies(part.Dependencies)
+	}
+
+	for _, part := range parts {
+		merged.PreHooks = append(merged.PreHooks, part.PreHooks...)
+		merged.PostHooks = append(merged.PostHooks, part.PostHooks...)
+	}
+
+	for service, config := range env.Services {
+		merged.Services[service] = config
+	}
+	addDependencies(env.Dependencies)
+	merged.PreHooks = append(merged.PreHooks, env.PreHooks...)
+	merged.PostHooks = append(merged.PostHooks, env.PostHooks...)
+
+	return &merged, parts, nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// mapResolver resolves environments from a map keyed by name.
+func mapResolver(envs ...*Environment) EnvironmentResolver {
+	byName := make(map[string]*Environment, len(envs))
+	for _, env := range envs {
+		byName[env.Name] = env
+	}
+	return func(name string) (*Environment, error) {
+		env, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("environment '%s' not found", name)
+		}
+		return env, nil
+	}
+}
+
+// TestExpandComposition tests merging the services, dependencies, and hooks
+// of composed environments.
+func TestExpandComposition(t *testing.T) {
+	backend := &Environment{
+		Name: "backend",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "dev"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "dev"}},
+		},
+		Dependencies: []string{"aws -> kubernetes"},
+		PreHooks:     []Hook{{Command: "echo backend"}},
+	}
+	data := &Environment{
+		Name: "data",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "dev"}},
+			"gcp": {GCP: &GCPConfig{Project: "analytics"}},
+		},
+		Dependencies: []string{"aws -> kubernetes", "gcp -> aws"},
+		PreHooks:     []Hook{{Command: "echo data"}},
+	}
+	fullStack := &Environment{
+		Name:     "full-stack",
+		Compose:  []string{"backend", "data"},
+		Services: map[string]ServiceConfig{"kubernetes": {Kubernetes: &KubernetesConfig{Context: "full"}}},
+		PreHooks: []Hook{{Command: "echo full-stack"}},
+	}
+
+	merged, err := ExpandComposition(fullStack, mapResolver(backend, data))
+	if err != nil {
+		t.Fatalf("ExpandComposition() error = %v", err)
+	}
+
+	services := merged.GetServiceNames()
+	sort.Strings(services)
+	if want := []string{"aws", "gcp", "kubernetes"}; !reflect.DeepEqual(services, want) {
+		t.Errorf("services = %v, want %v", services, want)
+	}
+	if got := merged.Services["kubernetes"].Kubernetes.Context; got != "full" {
+		t.Errorf("kubernetes context = %q, want the composing environment's %q", got, "full")
+	}
+	if want := []string{"aws -> kubernetes", "gcp -> aws"}; !reflect.DeepEqual(merged.Dependencies, want) {
+		t.Errorf("Dependencies = %v, want %v", merged.Dependencies, want)
+	}
+
+	var hooks []string
+	for _, hook := range merged.PreHooks {
+		hooks = append(hooks, hook.Command)
+	}
+	if want := []string{"echo backend", "echo data", "echo full-stack"}; !reflect.DeepEqual(hooks, want) {
+		t.Errorf("PreHooks = %v, want %v", hooks, want)
+	}
+	if merged.Name != "full-stack" || merged.Compose != nil {
+		t.Errorf("merged Name = %q, Compose = %v, want full-stack and nil", merged.Name, merged.Compose)
+	}
+	if err := merged.Validate(); err != nil {
+		t.Errorf("merged.Validate() error = %v", err)
+	}
+}
+
+// TestExpandComposition_Diamond tests that an environment composed through
+// two others contributes its hooks once.
+func TestExpandComposition_Diamond(t *testing.T) {
+	base := &Environment{
+		Name:      "base",
+		Services:  map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "dev"}}},
+		PreHooks:  []Hook{{Command: "echo base"}},
+		PostHooks: []Hook{{Command: "echo base-post"}},
+	}
+	b := &Environment{
+		Name:     "b",
+		Compose:  []string{"base"},
+		Services: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "b"}}},
+		PreHooks: []Hook{{Command: "echo b"}},
+	}
+	c := &Environment{
+		Name:      "c",
+		Compose:   []string{"base"},
+		Services:  map[string]ServiceConfig{"gcp": {GCP: &GCPConfig{Project: "c"}}},
+		PostHooks: []Hook{{Command: "echo c-post"}},
+	}
+	a := &Environment{
+		Name:     "a",
+		Compose:  []string{"b", "c"},
+		PreHooks: []Hook{{Command: "echo a"}},
+	}
+
+	merged, err := ExpandComposition(a, mapResolver(base, b, c))
+	if err != nil {
+		t.Fatalf("ExpandComposition() error = %v", err)
+	}
+
+	commands := func(hooks []Hook) []string {
+		var commands []string
+		for _, hook := range hooks {
+			commands = append(commands, hook.Command)
+		}
+		return commands
+	}
+	if got, want := commands(merged.PreHooks), []string{"echo base", "echo b", "echo a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PreHooks = %v, want %v", got, want)
+	}
+	if got, want := commands(merged.PostHooks), []string{"echo base-post", "echo c-post"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("PostHooks = %v, want %v", got, want)
+	}
+	if len(merged.Services) != 3 {
+		t.Errorf("services = %v, want aws, docker, and gcp", merged.GetServiceNames())
+	}
+}
+
+// TestExpandComposition_Errors tests conflict, cycle, and missing environment detection.
+func TestExpandComposition_Errors(t *testing.T) {
+	backend := &Environment{
+		Name:     "backend",
+		Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "dev"}}},
+	}
+	data := &Environment{
+		Name:     "data",
+		Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "analytics"}}},
+	}
+	loopA := &Environment{Name: "loop-a", Compose: []string{"loop-b"}}
+	loopB := &Environment{Name: "loop-b", Compose: []string{"loop-a"}}
+	resolve := mapResolver(backend, data, loopA, loopB)
+
+	tests := []struct {
+		name    string
+		compose []string
+		wantErr string
+	}{
+		{name: "conflicting service", compose: []string{"backend", "data"}, wantErr: "service 'aws' is configured differently by composed environments 'backend' and 'data'"},
+		{name: "cycle", compose: []string{"loop-a"}, wantErr: "composition cycle: full-stack -> loop-a -> loop-b -> loop-a"},
+		{name: "self", compose: []string{"full-stack"}, wantErr: "composition cycle"},
+		{name: "missing", compose: []string{"nope"}, wantErr: "failed to load composed environment 'nope'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{Name: "full-stack", Compose: tt.compose}
+			_, err := ExpandComposition(env, resolve)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ExpandComposition() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSearchPathResolver tests composing environments found on disk by alias.
+func TestSearchPathResolver(t *testing.T) {
+	dir := t.TempDir()
+	writeEnvFile(t, dir, "backend.yaml", "backend", "be")
+
+	composite := "name: full-stack\ncompose: [be]\n"
+	if err := os.WriteFile(filepath.Join(dir, "full-stack.yaml"), []byte(composite), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	env, err := LoadEnvironmentFromFile(filepath.Join(dir, "full-stack.yaml"))
+	if err != nil {
+		t.Fatalf("LoadEnvironmentFromFile() error = %v", err)
+	}
+	merged, err := ExpandComposition(env, SearchPathResolver([]string{dir}))
+	if err != nil {
+		t.Fatalf("ExpandComposition() error = %v", err)
+	}
+	if !merged.HasService("aws") {
+		t.Errorf("merged services = %v, want aws from backend", merged.GetServiceNames())
+	}
+}
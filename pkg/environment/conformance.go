@@ -0,0 +1,83 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConformanceStep is the outcome of one step of VerifyExecSwitcher. Err is
+// nil if the step passed.
+type ConformanceStep struct {
+	Name string
+	Err  error
+}
+
+// VerifyExecSwitcher checks that an external switcher follows the protocol
+// described on ExecSwitcher, so plugin authors can validate their
+// executable before installing it. It switches to config and then rolls
+// back, so it changes the service's real state while it runs. Steps after
+// a failed step that they depend on are not run.
+func VerifyExecSwitcher(ctx context.Context, switcher *ExecSwitcher, config ExtraConfig) []ConformanceStep {
+	var steps []ConformanceStep
+	record := func(name string, err error) bool {
+		steps = append(steps, ConformanceStep{Name: name, Err: err})
+		return err == nil
+	}
+
+	original, err := currentExtraState(ctx, switcher)
+	if !record("current prints a JSON object of strings", err) {
+		return steps
+	}
+
+	if !record("switch accepts the config on stdin", switcher.Switch(ctx, config)) {
+		return steps
+	}
+
+	switched, err := currentExtraState(ctx, switcher)
+	if err == nil {
+		err = checkStateIncludes(switched, config)
+	}
+	record("current reports the switched config", err)
+
+	if !record("rollback accepts the previous state on stdin", switcher.Rollback(ctx, original)) {
+		return steps
+	}
+
+	restored, err := currentExtraState(ctx, switcher)
+	if err == nil {
+		err = checkStateIncludes(restored, original)
+	}
+	record("current reports the previous state after rollback", err)
+
+	return steps
+}
+
+// currentExtraState returns the state of switcher as an ExtraConfig.
+func currentExtraState(ctx context.Context, switcher *ExecSwitcher) (ExtraConfig, error) {
+	state, err := switcher.GetCurrentState(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return state.(ExtraConfig), nil
+}
+
+// checkStateIncludes reports the values of want that state lacks or holds
+// differently.
+func checkStateIncludes(state, want ExtraConfig) error {
+	var mismatches []string
+	for key, value := range want {
+		if got, ok := state[key]; !ok || got != value {
+			mismatches = append(mismatches, fmt.Sprintf("%s=%q (got %q)", key, value, got))
+		}
+	}
+	if len(mismatches) == 0 {
+		return nil
+	}
+	sort.Strings(mismatches)
+	return fmt.Errorf("state is missing %s", strings.Join(mismatches, ", "))
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCurrentPath returns ~/.gzh/dev-env/current, the marker file naming
+// the environment of the last complete switch.
+func DefaultCurrentPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "current")
+}
+
+// ReadCurrent returns the environment named in the marker file at path, or
+// "" if there is none.
+func ReadCurrent(path string) (string, error) {
+	// #nosec G304 - The marker is in dev-env's own directory
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read current environment: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WriteCurrent replaces the marker file at path with name. The file is
+// replaced atomically, so a reader never sees half a name.
+func WriteCurrent(path, name string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create current environment directory: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(name+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write current environment: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write current environment: %w", err)
+	}
+	return nil
+}
+
+// ClearCurrent removes the marker file at path, for when no environment is
+// fully in effect.
+func ClearCurrent(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to clear current environment: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+)
+
+// TestCurrent_WriteReadClear tests the current environment marker file.
+func TestCurrent_WriteReadClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev-env", "current")
+
+	if got, err := ReadCurrent(path); err != nil || got != "" {
+		t.Errorf("ReadCurrent() without a marker = %q, %v, want \"\", nil", got, err)
+	}
+
+	for _, name := range []string{"staging", "production"} {
+		if err := WriteCurrent(path, name); err != nil {
+			t.Fatalf("WriteCurrent(%s) error = %v", name, err)
+		}
+		if got, err := ReadCurrent(path); err != nil || got != name {
+			t.Errorf("ReadCurrent() = %q, %v, want %q", got, err, name)
+		}
+	}
+
+	if err := ClearCurrent(path); err != nil {
+		t.Fatalf("ClearCurrent() error = %v", err)
+	}
+	if got, err := ReadCurrent(path); err != nil || got != "" {
+		t.Errorf("ReadCurrent() after ClearCurrent() = %q, %v, want \"\", nil", got, err)
+	}
+	if err := ClearCurrent(path); err != nil {
+		t.Errorf("ClearCurrent() without a marker error = %v, want nil", err)
+	}
+}
+
+// TestEnvironmentSwitcher_CurrentMarker tests that switches write, clear,
+// or keep the marker depending on how they end.
+func TestEnvironmentSwitcher_CurrentMarker(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "current")
+	aws, docker := newMockSwitcher("aws"), newMockSwitcher("docker")
+	es := newTestSwitcher()
+	es.Register(aws)
+	es.Register(docker)
+	es.SetCurrentMarker(path)
+
+	env := func(name string) *Environment {
+		return &Environment{
+			Name: name,
+			Services: map[string]ServiceConfig{
+				"aws":    {AWS: &AWSConfig{Profile: name}},
+				"docker": {Docker: &DockerConfig{Context: name}},
+			},
+		}
+	}
+	current := func() string {
+		name, err := ReadCurrent(path)
+		if err != nil {
+			t.Fatalf("ReadCurrent() error = %v", err)
+		}
+		return name
+	}
+	ctx := context.Background()
+
+	if _, err := es.SwitchEnvironment(ctx, env("staging"), SwitchOptions{}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if got := current(); got != "staging" {
+		t.Errorf("current after a switch = %q, want staging", got)
+	}
+
+	if _, err := es.SwitchEnvironment(ctx, env("production"), SwitchOptions{DryRun: true}); err != nil {
+		t.Fatalf("dry run SwitchEnvironment() error = %v", err)
+	}
+	if got := current(); got != "staging" {
+		t.Errorf("current after a dry run = %q, want staging", got)
+	}
+
+	docker.switchError = errors.New("context not found")
+	if _, err := es.SwitchEnvironment(ctx, env("production"), SwitchOptions{RollbackOnError: true}); err == nil {
+		t.Fatal("SwitchEnvironment() should fail")
+	}
+	if got := current(); got != "staging" {
+		t.Errorf("current after a failed switch = %q, want staging", got)
+	}
+
+	if _, err := es.SwitchEnvironment(ctx, env("production"), SwitchOptions{PartialSuccess: true}); err != nil {
+		t.Fatalf("partial SwitchEnvironment() error = %v", err)
+	}
+	if got := current(); got != "" {
+		t.Errorf("current after a partial switch = %q, want it cleared", got)
+	}
+}
@@ -5,26 +5,68 @@ package environment
 
 import (
 	"fmt"
+	"path"
+	"slices"
 	"sort"
+	"strings"
 )
 
+// MaxRecommendedDepth is the number of dependency levels beyond which
+// ValidateDependencies warns. Each level waits for the one before it, so a
+// deep chain switches services one at a time even with parallel switching.
+const MaxRecommendedDepth = 4
+
 // DependencyResolver handles service dependency resolution and execution ordering.
 type DependencyResolver struct {
 	services     map[string]ServiceConfig
 	dependencies []string
+	strict       bool
+	warnings     []string
 }
 
-// NewDependencyResolver creates a new dependency resolver.
+// NewDependencyResolver creates a new dependency resolver. The resolver is
+// strict by default: a dependency on an unconfigured service is an error.
 func NewDependencyResolver(services map[string]ServiceConfig, dependencies []string) *DependencyResolver {
 	return &DependencyResolver{
 		services:     services,
 		dependencies: dependencies,
+		strict:       true,
 	}
 }
 
+// SetStrict controls how dependencies on unconfigured services are handled.
+// When strict is false, such dependencies are dropped and reported by
+// Warnings instead of failing resolution. This suits environments assembled
+// from fragments, where a dependency may name a service another fragment adds.
+func (dr *DependencyResolver) SetStrict(strict bool) {
+	dr.strict = strict
+}
+
+// Warnings returns the dependencies dropped by the last resolution in
+// non-strict mode, and the depth warning of the last ValidateDependencies.
+func (dr *DependencyResolver) Warnings() []string {
+	return dr.warnings
+}
+
 // ResolveDependencies resolves service dependencies and returns execution order.
 func (dr *DependencyResolver) ResolveDependencies() ([]ServiceGroup, error) {
-	// Build dependency graph
+	graph, inDegree, err := dr.buildGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	// Check for cycles
+	if err := dr.detectCycles(graph); err != nil {
+		return nil, err
+	}
+
+	// Perform topological sort with level grouping
+	return dr.topologicalSort(graph, inDegree)
+}
+
+// buildGraph returns the dependency graph, mapping each service to the
+// services that depend on it, and the number of services each depends on.
+func (dr *DependencyResolver) buildGraph() (map[string][]string, map[string]int, error) {
 	graph := make(map[string][]string)
 	inDegree := make(map[string]int)
 
@@ -34,35 +76,108 @@ func (dr *DependencyResolver) ResolveDependencies() ([]ServiceGroup, error) {
 		graph[serviceName] = []string{}
 	}
 
+	dr.warnings = nil
+
 	// Parse dependencies and build graph
 	for _, dep := range dr.dependencies {
 		parts := parseDependency(dep)
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid dependency format: %s (expected format: 'service1 -> service2')", dep)
+			return nil, nil, fmt.Errorf("invalid dependency format: %s (expected format: 'service1 -> service2')", dep)
 		}
 
-		from, to := parts[0], parts[1]
+		edges, err := dr.expandDependency(parts[0], parts[1])
+		if err != nil {
+			if dr.strict {
+				return nil, nil, err
+			}
+			dr.warnings = append(dr.warnings, fmt.Sprintf("ignoring dependency '%s': %v", dep, err))
+			continue
+		}
 
-		// Validate that both services exist
-		if _, exists := dr.services[from]; !exists {
-			return nil, fmt.Errorf("dependency source service '%s' not found", from)
+		// Add edges and update in-degree
+		for _, edge := range edges {
+			from, to := edge[0], edge[1]
+			graph[from] = append(graph[from], to)
+			inDegree[to]++
 		}
-		if _, exists := dr.services[to]; !exists {
-			return nil, fmt.Errorf("dependency target service '%s' not found", to)
+	}
+
+	return graph, inDegree, nil
+}
+
+// expandDependency returns the edges of the dependency from -> to, either
+// side of which may be a pattern. A pattern may match the service on the
+// other side, as in "base -> *"; that edge is skipped rather than treated
+// as a cycle.
+func (dr *DependencyResolver) expandDependency(from, to string) ([][2]string, error) {
+	sources, err := dr.expandService(from, "source")
+	if err != nil {
+		return nil, err
+	}
+	targets, err := dr.expandService(to, "target")
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := isServicePattern(from) || isServicePattern(to)
+	var edges [][2]string
+	for _, source := range sources {
+		for _, target := range targets {
+			if pattern && source == target {
+				continue
+			}
+			edges = append(edges, [2]string{source, target})
 		}
+	}
+	return edges, nil
+}
 
-		// Add edge and update in-degree
-		graph[from] = append(graph[from], to)
-		inDegree[to]++
+// expandService returns the configured services one side of a dependency
+// names, sorted: the service itself, or every service a glob pattern such
+// as "k8s-*" matches. role names the side ("source" or "target"). It is an
+// error if the service is not configured or the pattern matches none.
+func (dr *DependencyResolver) expandService(service, role string) ([]string, error) {
+	if !isServicePattern(service) {
+		if _, exists := dr.services[service]; !exists {
+			return nil, fmt.Errorf("dependency %s service '%s' not found", role, service)
+		}
+		return []string{service}, nil
 	}
 
-	// Check for cycles
-	if err := dr.detectCycles(graph); err != nil {
-		return nil, err
+	names := make([]string, 0, len(dr.services))
+	for name := range dr.services {
+		names = append(names, name)
 	}
+	matches, err := matchServices(service, names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dependency %s pattern '%s': %w", role, service, err)
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("dependency %s pattern '%s' matches no service", role, service)
+	}
+	return matches, nil
+}
 
-	// Perform topological sort with level grouping
-	return dr.topologicalSort(graph, inDegree)
+// isServicePattern reports whether one side of a dependency is a glob
+// pattern rather than a service name.
+func isServicePattern(service string) bool {
+	return strings.ContainsAny(service, "*?[")
+}
+
+// matchServices returns the names pattern matches, sorted. Patterns use
+// path.Match syntax.
+func matchServices(pattern string, names []string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	var matches []string
+	for _, name := range names {
+		if ok, _ := path.Match(pattern, name); ok {
+			matches = append(matches, name)
+		}
+	}
+	sort.Strings(matches)
+	return matches, nil
 }
 
 // parseDependency parses a dependency string like "aws -> kubernetes".
@@ -215,13 +330,53 @@ func (dr *DependencyResolver) GetExecutionOrder() ([]string, error) {
 	return order, nil
 }
 
+// Prerequisites returns the services each service directly depends on,
+// sorted, for services with any.
+func (dr *DependencyResolver) Prerequisites() (map[string][]string, error) {
+	graph, _, err := dr.buildGraph()
+	if err != nil {
+		return nil, err
+	}
+
+	prerequisites := make(map[string][]string)
+	for from, dependents := range graph {
+		for _, to := range dependents {
+			if !slices.Contains(prerequisites[to], from) {
+				prerequisites[to] = append(prerequisites[to], from)
+			}
+		}
+	}
+	for _, services := range prerequisites {
+		sort.Strings(services)
+	}
+	return prerequisites, nil
+}
+
 // GetParallelGroups returns groups of services that can be executed in parallel.
 func (dr *DependencyResolver) GetParallelGroups() ([]ServiceGroup, error) {
 	return dr.ResolveDependencies()
 }
 
 // ValidateDependencies validates that all dependencies are satisfiable.
+// A chain deeper than MaxRecommendedDepth is not an error, but is reported
+// by Warnings as a likely sign of over-specified dependencies.
 func (dr *DependencyResolver) ValidateDependencies() error {
-	_, err := dr.ResolveDependencies()
-	return err
+	groups, err := dr.ResolveDependencies()
+	if err != nil {
+		return err
+	}
+
+	if len(groups) > MaxRecommendedDepth {
+		levels := make([]string, len(groups))
+		for i, group := range groups {
+			levels[i] = strings.Join(group.Services, ", ")
+			if len(group.Services) > 1 {
+				levels[i] = "[" + levels[i] + "]"
+			}
+		}
+		dr.warnings = append(dr.warnings, fmt.Sprintf(
+			"dependency chain is %d levels deep (%s), more than the recommended %d; each level waits for the previous one, so check for dependencies that are not needed",
+			len(groups), strings.Join(levels, " -> "), MaxRecommendedDepth))
+	}
+	return nil
 }
@@ -434,3 +434,201 @@ func TestDependencyResolver_SelfDependency(t *testing.T) {
 		t.Error("ResolveDependencies() should error on self-dependency")
 	}
 }
+
+// TestDependencyResolver_Strict tests handling of dependencies on unconfigured services.
+func TestDependencyResolver_Strict(t *testing.T) {
+	services := map[string]ServiceConfig{
+		"aws":        {},
+		"kubernetes": {},
+	}
+	deps := []string{"aws -> kubernetes", "aws -> gcp", "azure -> kubernetes"}
+
+	tests := []struct {
+		name         string
+		strict       bool
+		wantErr      bool
+		wantWarnings int
+	}{
+		{name: "strict errors", strict: true, wantErr: true},
+		{name: "soft drops dangling edges", strict: false, wantWarnings: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewDependencyResolver(services, deps)
+			resolver.SetStrict(tt.strict)
+
+			groups, err := resolver.ResolveDependencies()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveDependencies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(resolver.Warnings()) != tt.wantWarnings {
+				t.Errorf("Warnings() = %v, want %d warnings", resolver.Warnings(), tt.wantWarnings)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// The remaining edge still orders aws before kubernetes.
+			if len(groups) != 2 || groups[0].Services[0] != "aws" || groups[1].Services[0] != "kubernetes" {
+				t.Errorf("ResolveDependencies() = %+v, want [aws] then [kubernetes]", groups)
+			}
+		})
+	}
+}
+
+// TestNewDependencyResolver_DefaultStrict tests that resolvers are strict by default.
+func TestNewDependencyResolver_DefaultStrict(t *testing.T) {
+	resolver := NewDependencyResolver(map[string]ServiceConfig{"aws": {}}, []string{"aws -> gcp"})
+
+	_, err := resolver.ResolveDependencies()
+	if err == nil || !strings.Contains(err.Error(), "target service 'gcp' not found") {
+		t.Errorf("ResolveDependencies() error = %v, want missing target error", err)
+	}
+}
+
+// TestDependencyResolver_ValidateDependencies_Depth tests the warning for deep dependency chains.
+func TestDependencyResolver_ValidateDependencies_Depth(t *testing.T) {
+	services := map[string]ServiceConfig{
+		"aws": {}, "gcp": {}, "azure": {}, "docker": {}, "kubernetes": {}, "ssh": {},
+	}
+
+	tests := []struct {
+		name        string
+		deps        []string
+		wantWarning bool
+	}{
+		{
+			name:        "linear chain of every service",
+			deps:        []string{"ssh -> aws", "aws -> gcp", "gcp -> azure", "azure -> docker", "docker -> kubernetes"},
+			wantWarning: true,
+		},
+		{
+			name: "chain at the recommended depth",
+			deps: []string{"aws -> gcp", "gcp -> docker", "docker -> kubernetes"},
+		},
+		{
+			name: "shallow fan-out",
+			deps: []string{"aws -> docker", "gcp -> docker", "docker -> kubernetes"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewDependencyResolver(services, tt.deps)
+			if err := resolver.ValidateDependencies(); err != nil {
+				t.Fatalf("ValidateDependencies() error = %v, want a warning at most", err)
+			}
+
+			warnings := resolver.Warnings()
+			if gotWarning := len(warnings) > 0; gotWarning != tt.wantWarning {
+				t.Fatalf("Warnings() = %v, wantWarning %v", warnings, tt.wantWarning)
+			}
+			if tt.wantWarning && !strings.Contains(warnings[0], "ssh -> aws -> gcp -> azure -> docker -> kubernetes") {
+				t.Errorf("Warnings()[0] = %q, want it to show the chain", warnings[0])
+			}
+		})
+	}
+}
+
+// TestDependencyResolver_Wildcards tests expanding glob patterns in
+// dependencies to edges between the configured services.
+func TestDependencyResolver_Wildcards(t *testing.T) {
+	services := map[string]ServiceConfig{
+		"base":     {},
+		"k8s-east": {},
+		"k8s-west": {},
+		"k8s-eu":   {},
+		"vpn-1":    {},
+		"vpn-2":    {},
+	}
+
+	tests := []struct {
+		name         string
+		dependencies []string
+		want         map[string][]string
+		errContains  string
+	}{
+		{
+			name:         "pattern target",
+			dependencies: []string{"base -> k8s-*"},
+			want:         map[string][]string{"base": {"k8s-east", "k8s-eu", "k8s-west"}},
+		},
+		{
+			name:         "pattern on both sides",
+			dependencies: []string{"vpn-? -> k8s-[ew]*"},
+			want: map[string][]string{
+				"vpn-1": {"k8s-east", "k8s-eu", "k8s-west"},
+				"vpn-2": {"k8s-east", "k8s-eu", "k8s-west"},
+			},
+		},
+		{
+			name:         "pattern matching its own source is not a cycle",
+			dependencies: []string{"base -> *"},
+			want:         map[string][]string{"base": {"k8s-east", "k8s-eu", "k8s-west", "vpn-1", "vpn-2"}},
+		},
+		{
+			name:         "no match",
+			dependencies: []string{"base -> gke-*"},
+			errContains:  "dependency target pattern 'gke-*' matches no service",
+		},
+		{
+			name:         "invalid pattern",
+			dependencies: []string{"k8s-[ -> base"},
+			errContains:  "invalid dependency source pattern 'k8s-['",
+		},
+		{
+			name:         "cycle through a pattern",
+			dependencies: []string{"base -> k8s-*", "k8s-eu -> base"},
+			errContains:  "circular dependency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewDependencyResolver(services, tt.dependencies)
+			graph, _, err := resolver.buildGraph()
+			if err == nil && tt.errContains != "" {
+				_, err = resolver.ResolveDependencies()
+			}
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Fatalf("error = %v, want %q", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("buildGraph() error = %v", err)
+			}
+
+			for service := range services {
+				got := strings.Join(graph[service], ",")
+				if want := strings.Join(tt.want[service], ","); got != want {
+					t.Errorf("edges from %s = [%s], want [%s]", service, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestEnvironment_EnabledDependencies_Wildcards tests that a pattern
+// matching only disabled services is dropped, like a dependency on a
+// disabled service.
+func TestEnvironment_EnabledDependencies_Wildcards(t *testing.T) {
+	disabled := false
+	env := &Environment{
+		Services: map[string]ServiceConfig{
+			"base":     {Docker: &DockerConfig{Context: "base"}},
+			"k8s-east": {Kubernetes: &KubernetesConfig{Context: "east"}, Enabled: &disabled},
+			"k8s-west": {Kubernetes: &KubernetesConfig{Context: "west"}, Enabled: &disabled},
+			"vpn-1":    {Docker: &DockerConfig{Context: "vpn"}},
+		},
+		Dependencies: []string{"base -> k8s-*", "base -> vpn-*", "base -> gke-*"},
+	}
+
+	got := env.EnabledDependencies()
+	// gke-* matches nothing, so it is kept for resolution to report.
+	if want := []string{"base -> vpn-*", "base -> gke-*"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("EnabledDependencies() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// FieldDiff describes a single service field that differs between two
+// configurations. An empty Left means the field is only set on the right; an
+// empty Right means it is only set on the left. A service configured on one
+// side only yields one record per field with the other side empty.
+type FieldDiff struct {
+	Service string `json:"service"`
+	Field   string `json:"field"`
+	Left    string `json:"left"`
+	Right   string `json:"right"`
+}
+
+// DiffEnvironments compares the services of two environments field by field.
+// Results are sorted by service and field.
+func DiffEnvironments(from, to *Environment) []FieldDiff {
+	names := make(map[string]bool)
+	for name := range from.Services {
+		names[name] = true
+	}
+	for name := range to.Services {
+		names[name] = true
+	}
+
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	var diffs []FieldDiff
+	for _, name := range sorted {
+		diffs = append(diffs, diffServiceConfig(name, from.Services[name], to.Services[name], false)...)
+	}
+	return diffs
+}
+
+// Plan compares each service's current state (Left) against the target
+// environment (Right) and returns the fields a switch would change. Fields
+// the target leaves unset and disabled services are not reported.
+func (es *EnvironmentSwitcher) Plan(ctx context.Context, env *Environment) ([]FieldDiff, error) {
+	var names []string
+	for name := range env.EnabledServices() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var diffs []FieldDiff
+	for _, name := range names {
+		es.mu.RLock()
+		switcher, exists := es.serviceSwitchers[name]
+		es.mu.RUnlock()
+
+		if !exists {
+			return nil, fmt.Errorf("no switcher registered for service: %s", name)
+		}
+
+		state, err := es.currentState(ctx, switcher)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current state for %s: %w", name, err)
+		}
+
+		diffs = append(diffs, diffServiceConfig(name, serviceConfigFromState(state), env.Services[name], true)...)
+	}
+	return diffs, nil
+}
+
+// diffServiceConfig compares two service configurations. When targetOnly is
+// set, only fields present in the target are compared.
+func diffServiceConfig(service string, from, to ServiceConfig, targetOnly bool) []FieldDiff {
+	oldFields := flattenServiceConfig(service, from)
+	newFields := flattenServiceConfig(service, to)
+
+	keys := make(map[string]bool)
+	for k := range newFields {
+		keys[k] = true
+	}
+	if !targetOnly {
+		for k := range oldFields {
+			keys[k] = true
+		}
+	}
+
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		sorted = append(sorted, k)
+	}
+	sort.Strings(sorted)
+
+	var diffs []FieldDiff
+	for _, k := range sorted {
+		if oldFields[k] != newFields[k] {
+			diffs = append(diffs, FieldDiff{
+				Service: service,
+				Field:   k,
+				Left:    oldFields[k],
+				Right:   newFields[k],
+			})
+		}
+	}
+	return diffs
+}
+
+// flattenServiceConfig flattens a service configuration into field paths
+// using its YAML keys, such as "profile" or "namespace". Fields of a block
+// whose type differs from the service name are prefixed with the type.
+func flattenServiceConfig(service string, sc ServiceConfig) map[string]string {
+	fields := make(map[string]string)
+
+	data, err := yaml.Marshal(sc)
+	if err != nil {
+		return fields
+	}
+	var blocks map[string]interface{}
+	if err := yaml.Unmarshal(data, &blocks); err != nil {
+		return fields
+	}
+
+	for kind, block := range blocks {
+		prefix := ""
+		if kind != service {
+			prefix = kind
+		}
+		flattenValue(fields, prefix, block)
+	}
+	return fields
+}
+
+// flattenValue adds value to fields under path, recursing into maps.
+// Empty values are omitted so unset and empty fields compare equal.
+func flattenValue(fields map[string]string, path string, value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			flattenValue(fields, childPath, child)
+		}
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			parts = append(parts, fmt.Sprint(item))
+		}
+		if len(parts) > 0 {
+			fields[path] = strings.Join(parts, ", ")
+		}
+	case nil:
+	default:
+		if s := fmt.Sprint(v); s != "" {
+			fields[path] = s
+		}
+	}
+}
+
+// serviceConfigFromState wraps a switcher's current state in a ServiceConfig.
+func serviceConfigFromState(state interface{}) ServiceConfig {
+	switch s := state.(type) {
+	case *AWSConfig:
+		return ServiceConfig{AWS: s}
+	case *GCPConfig:
+		return ServiceConfig{GCP: s}
+	case *AzureConfig:
+		return ServiceConfig{Azure: s}
+	case *DockerConfig:
+		return ServiceConfig{Docker: s}
+	case *KubernetesConfig:
+		return ServiceConfig{Kubernetes: s}
+	case *SSHConfig:
+		return ServiceConfig{SSH: s}
+	case *GPGConfig:
+		return ServiceConfig{GPG: s}
+	case *PkgMgrConfig:
+		return ServiceConfig{PkgMgr: s}
+	case ExtraConfig:
+		return ServiceConfig{Extra: s}
+	default:
+		return ServiceConfig{}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// TestDiffEnvironments tests field-level comparison of two environments.
+func TestDiffEnvironments(t *testing.T) {
+	from := &Environment{
+		Name: "staging",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "staging", Region: "us-east-1"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "cluster", Namespace: "staging"}},
+			"ssh":        {SSH: &SSHConfig{Config: "~/.ssh/staging"}},
+		},
+	}
+	to := &Environment{
+		Name: "production",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "prod", Region: "us-east-1"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "cluster"}},
+			"gcp":        {GCP: &GCPConfig{Project: "prod", ImpersonationChain: []string{"a@x.iam", "b@x.iam"}}},
+		},
+	}
+
+	want := []FieldDiff{
+		{Service: "aws", Field: "profile", Left: "staging", Right: "prod"},
+		{Service: "gcp", Field: "impersonationChain", Right: "a@x.iam, b@x.iam"},
+		{Service: "gcp", Field: "project", Right: "prod"},
+		{Service: "kubernetes", Field: "namespace", Left: "staging"},
+		{Service: "ssh", Field: "config", Left: "~/.ssh/staging"},
+	}
+
+	got := DiffEnvironments(from, to)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DiffEnvironments() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if diffs := DiffEnvironments(to, to); len(diffs) != 0 {
+		t.Errorf("DiffEnvironments(same) = %+v, want none", diffs)
+	}
+}
+
+// TestEnvironmentSwitcher_Plan tests planning against current service state.
+func TestEnvironmentSwitcher_Plan(t *testing.T) {
+	es := newTestSwitcher()
+	aws := &mockSwitcher{name: "aws", state: &AWSConfig{Profile: "dev", Region: "us-east-1", AccountID: "123"}}
+	docker := &mockSwitcher{name: "docker", state: &DockerConfig{Context: "default"}}
+	es.Register(aws)
+	es.Register(docker)
+
+	env := &Environment{
+		Name: "production",
+		Services: map[string]ServiceConfig{
+			"aws":    {AWS: &AWSConfig{Profile: "prod", Region: "us-east-1"}},
+			"docker": {Docker: &DockerConfig{Context: "default"}},
+		},
+	}
+
+	diffs, err := es.Plan(context.Background(), env)
+	if err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+
+	// AccountID is unset in the target, so it is not reported.
+	want := []FieldDiff{{Service: "aws", Field: "profile", Left: "dev", Right: "prod"}}
+	if !reflect.DeepEqual(diffs, want) {
+		t.Errorf("Plan() = %+v, want %+v", diffs, want)
+	}
+	if aws.switchCalled || docker.switchCalled {
+		t.Error("Plan() should not switch any service")
+	}
+}
+
+// TestEnvironmentSwitcher_Plan_MissingSwitcher tests planning with an unregistered service.
+func TestEnvironmentSwitcher_Plan_MissingSwitcher(t *testing.T) {
+	es := newTestSwitcher()
+	env := &Environment{
+		Name: "production",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "prod"}},
+		},
+	}
+
+	if _, err := es.Plan(context.Background(), env); err == nil {
+		t.Error("Plan() should fail when a service has no switcher")
+	}
+}
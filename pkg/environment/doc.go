@@ -3,8 +3,26 @@
 //
 // The main abstractions are:
 //   - ServiceSwitcher: Interface for switching individual services (AWS, GCP, etc.)
+//   - CommandSwitcher: Switches a service by running user-configured commands
+//   - ExecSwitcher: Switches a service by running an external executable; see VerifyExecSwitcher
 //   - EnvironmentSwitcher: Orchestrates multiple service switches atomically
 //   - DependencyResolver: Handles service dependencies and ordering
+//   - Hook: Commands run around a switch, ordered by runAfter and conditioned on services
+//   - ExpandComposition: Merges environments listed in Compose into one
+//   - Override: Sets one field of an environment by dotted path, as switch-all --set does
+//   - ValueValidator: Checks regions, namespaces, and similar values before a switch
+//   - ResultReporter: Prints switch results with captured CLI output and the
+//     fix hints of status.RemediationHint
+//   - Drift: Services changed since the last switch, read once each through a StateCache
+//   - RetryFailed: Re-attempts the services that failed in the last partial switch
+//   - RollbackService: Restores one service to its state before a recorded switch
+//   - FileSnapshots: Copies the files switchers claim before a switch so they can be restored
+//   - Simulate: Runs a switch against canned CLI responses without invoking real tools
+//   - Preflight: Checks a switch would be accepted without querying or switching services
+//   - ConfigValidator, CLIRequirer: Let switchers check configurations and required CLIs offline
+//   - SwitchLock: Keeps two processes from switching at the same time
+//   - Telemetry: Reports switches, services, and hooks, e.g. as OpenTelemetry spans
+//   - EnvStore, FileStore: Discovers and loads environments, from files by default
 //
 // Example usage:
 //
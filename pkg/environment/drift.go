@@ -0,0 +1,186 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// StateCache remembers each service's current state once read, so that
+// planning a switch and detecting drift before it read every service only
+// once. It is meant to live for one command; states read before a switch
+// are stale after it.
+type StateCache struct {
+	states map[string]cachedState
+	mu     sync.Mutex
+}
+
+// cachedState is a GetCurrentState result.
+type cachedState struct {
+	state interface{}
+	err   error
+}
+
+// NewStateCache creates an empty state cache.
+func NewStateCache() *StateCache {
+	return &StateCache{states: make(map[string]cachedState)}
+}
+
+// get returns the cached state of switcher's service, reading it on the
+// first call.
+func (c *StateCache) get(ctx context.Context, switcher ServiceSwitcher) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	name := switcher.Name()
+	if cached, ok := c.states[name]; ok {
+		return cached.state, cached.err
+	}
+	state, err := safeGetCurrentState(ctx, switcher)
+	c.states[name] = cachedState{state: state, err: err}
+	return state, err
+}
+
+// SetStateCache makes Plan and Drift read service states through cache.
+// Switches always read the state they roll back to afresh.
+func (es *EnvironmentSwitcher) SetStateCache(cache *StateCache) {
+	es.stateCache = cache
+}
+
+// currentState returns switcher's current state, through the state cache
+// if one is set.
+func (es *EnvironmentSwitcher) currentState(ctx context.Context, switcher ServiceSwitcher) (interface{}, error) {
+	if es.stateCache != nil {
+		return es.stateCache.get(ctx, switcher)
+	}
+	return safeGetCurrentState(ctx, switcher)
+}
+
+// Drift is a service whose live state no longer matches the environment
+// last switched to, e.g. because it was changed by hand since. In Diffs,
+// Left is the live value and Right the environment's.
+type Drift struct {
+	Service string      `json:"service"`
+	Diffs   []FieldDiff `json:"diffs"`
+}
+
+// Summary describes where the service is left, such as
+// "kubernetes will remain at context=minikube".
+func (d Drift) Summary() string {
+	fields := make([]string, 0, len(d.Diffs))
+	for _, diff := range d.Diffs {
+		if diff.Left == "" {
+			fields = append(fields, diff.Field+" unset")
+			continue
+		}
+		fields = append(fields, diff.Field+"="+diff.Left)
+	}
+	return fmt.Sprintf("%s will remain at %s", d.Service, strings.Join(fields, ", "))
+}
+
+// Drift compares the live state of the services last switched to last
+// against it, and returns those that differ, sorted by service. Only the
+// services of last are read, so a baseline from History.DriftBaseline
+// reads none that the next switch manages. services
+// limits the comparison to the services the switch actually switched, if
+// given. Services without a registered switcher, or whose state cannot be
+// read or compared, are skipped, since drift is only advisory.
+func (es *EnvironmentSwitcher) Drift(ctx context.Context, last *Environment, services []string) []Drift {
+	switched := make(map[string]bool, len(services))
+	for _, service := range services {
+		switched[service] = true
+	}
+
+	var names []string
+	for name := range last.EnabledServices() {
+		if len(services) == 0 || switched[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var drifts []Drift
+	for _, name := range names {
+		es.mu.RLock()
+		switcher, exists := es.serviceSwitchers[name]
+		es.mu.RUnlock()
+		if !exists {
+			continue
+		}
+
+		state, err := es.currentState(ctx, switcher)
+		if err != nil {
+			continue
+		}
+		// A state of a type no configuration holds cannot be compared
+		live := serviceConfigFromState(state)
+		if live.IsEmpty() {
+			continue
+		}
+		if diffs := diffServiceConfig(name, live, last.Services[name], true); len(diffs) > 0 {
+			drifts = append(drifts, Drift{Service: name, Diffs: diffs})
+		}
+	}
+	return drifts
+}
+
+// switchedConfigs returns env's configurations of the switched services,
+// for HistoryEntry.SwitchedConfigs.
+func switchedConfigs(switched []string, env *Environment) map[string]ServiceConfig {
+	if len(switched) == 0 {
+		return nil
+	}
+	configs := make(map[string]ServiceConfig, len(switched))
+	for _, service := range switched {
+		if config, ok := env.Services[service]; ok {
+			configs[service] = config
+		}
+	}
+	return configs
+}
+
+// DriftBaseline returns what the most recent switch set the services that
+// a switch to target leaves alone to, as recorded in its SwitchedConfigs,
+// for EnvironmentSwitcher.Drift. Services rolled back on their own since
+// were restored on purpose and are left out too. It returns nil, and no
+// error, if nothing has been switched yet, the switch predates
+// SwitchedConfigs, or no service is left.
+func (h *History) DriftBaseline(target *Environment) (*Environment, error) {
+	entries, err := h.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	rolledBack := make(map[string]bool)
+	var last *HistoryEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Type != HistoryPartialRollback {
+			last = &entries[i]
+			break
+		}
+		for _, service := range entries[i].Services {
+			rolledBack[service] = true
+		}
+	}
+	if last == nil || last.SwitchedConfigs == nil {
+		return nil, nil
+	}
+
+	managed := target.EnabledServices()
+	baseline := &Environment{Name: last.Environment, Services: make(map[string]ServiceConfig)}
+	for service, config := range last.SwitchedConfigs {
+		if _, ok := managed[service]; ok || rolledBack[service] {
+			continue
+		}
+		baseline.Services[service] = config
+	}
+	if len(baseline.Services) == 0 {
+		return nil, nil
+	}
+	return baseline, nil
+}
@@ -0,0 +1,257 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// countingSwitcher is a mockSwitcher that counts state reads.
+type countingSwitcher struct {
+	*mockSwitcher
+	reads    int
+	stateErr error
+}
+
+func (c *countingSwitcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	c.reads++
+	if c.stateErr != nil {
+		return nil, c.stateErr
+	}
+	return c.state, nil
+}
+
+// TestEnvironmentSwitcher_Drift tests comparing live state against the
+// environment last switched to.
+func TestEnvironmentSwitcher_Drift(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(&mockSwitcher{name: "aws", state: &AWSConfig{Profile: "prod", Region: "us-east-1"}})
+	es.Register(&mockSwitcher{name: "kubernetes", state: &KubernetesConfig{Context: "minikube"}})
+	es.Register(&mockSwitcher{name: "docker", state: &DockerConfig{Context: "desktop-linux"}})
+	es.Register(&countingSwitcher{mockSwitcher: newMockSwitcher("gcp"), stateErr: errors.New("gcloud not found")})
+	// A state no configuration holds is skipped
+	es.Register(newMockSwitcher("ssh"))
+
+	last := &Environment{
+		Name: "prod",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "prod"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod-cluster", Namespace: "web"}},
+			"docker":     {Docker: &DockerConfig{Context: "remote"}},
+			"gcp":        {GCP: &GCPConfig{Project: "prod"}},
+			"ssh":        {SSH: &SSHConfig{Config: "~/.ssh/prod"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		services []string
+		want     []Drift
+	}{
+		{
+			name: "all services",
+			want: []Drift{
+				{Service: "docker", Diffs: []FieldDiff{{Service: "docker", Field: "context", Left: "desktop-linux", Right: "remote"}}},
+				{Service: "kubernetes", Diffs: []FieldDiff{
+					{Service: "kubernetes", Field: "context", Left: "minikube", Right: "prod-cluster"},
+					{Service: "kubernetes", Field: "namespace", Right: "web"},
+				}},
+			},
+		},
+		{
+			name:     "switched services only",
+			services: []string{"aws", "docker"},
+			want: []Drift{
+				{Service: "docker", Diffs: []FieldDiff{{Service: "docker", Field: "context", Left: "desktop-linux", Right: "remote"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := es.Drift(context.Background(), last, tt.services); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Drift() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHistory_DriftBaseline tests taking the configurations of the last
+// switch that a switch to the target leaves alone from the history.
+func TestHistory_DriftBaseline(t *testing.T) {
+	disabled := false
+	target := &Environment{
+		Name: "staging",
+		Services: map[string]ServiceConfig{
+			"docker": {Docker: &DockerConfig{Context: "default"}},
+			"ssh":    {Enabled: &disabled, SSH: &SSHConfig{Config: "~/.ssh/staging"}},
+		},
+	}
+	prod := HistoryEntry{
+		SwitchID:    "01PROD",
+		Environment: "prod",
+		Services:    []string{"aws", "docker", "kubernetes", "ssh"},
+		SwitchedConfigs: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "prod"}},
+			"docker":     {Docker: &DockerConfig{Context: "remote"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod-cluster"}},
+			"ssh":        {SSH: &SSHConfig{Config: "~/.ssh/prod"}},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		entries []HistoryEntry
+		want    []string
+	}{
+		{
+			name: "no switch",
+		},
+		{
+			name:    "unmanaged services",
+			entries: []HistoryEntry{prod},
+			want:    []string{"aws", "kubernetes", "ssh"},
+		},
+		{
+			name: "rolled back since",
+			entries: []HistoryEntry{prod, {
+				SwitchID: "01ROLLBACK", Type: HistoryPartialRollback, Environment: "prod",
+				Services: []string{"aws"}, RollbackOf: "01PROD",
+			}},
+			want: []string{"kubernetes", "ssh"},
+		},
+		{
+			name: "all managed",
+			entries: []HistoryEntry{{
+				SwitchID: "01DOCKER", Environment: "docker-only", Services: []string{"docker"},
+				SwitchedConfigs: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "remote"}}},
+			}},
+		},
+		{
+			name:    "predates switched configs",
+			entries: []HistoryEntry{{SwitchID: "01OLD", Environment: "prod", Services: []string{"aws"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+			for _, entry := range tt.entries {
+				if err := history.Record(entry); err != nil {
+					t.Fatalf("Record() error = %v", err)
+				}
+			}
+
+			baseline, err := history.DriftBaseline(target)
+			if err != nil {
+				t.Fatalf("DriftBaseline() error = %v", err)
+			}
+			if tt.want == nil {
+				if baseline != nil {
+					t.Errorf("DriftBaseline() = %+v, want nil", baseline)
+				}
+				return
+			}
+			if baseline == nil || baseline.Name != "prod" {
+				t.Fatalf("DriftBaseline() = %+v, want prod", baseline)
+			}
+			var got []string
+			for service := range baseline.Services {
+				got = append(got, service)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DriftBaseline() services = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestDrift_Summary tests describing where a drifted service is left.
+func TestDrift_Summary(t *testing.T) {
+	drift := Drift{Service: "kubernetes", Diffs: []FieldDiff{
+		{Service: "kubernetes", Field: "context", Left: "minikube", Right: "prod-cluster"},
+		{Service: "kubernetes", Field: "namespace", Right: "web"},
+	}}
+	if got, want := drift.Summary(), "kubernetes will remain at context=minikube, namespace unset"; got != want {
+		t.Errorf("Summary() = %q, want %q", got, want)
+	}
+}
+
+// TestEnvironmentSwitcher_StateCache tests that planning and drift
+// detection read each service's state once with a state cache.
+func TestEnvironmentSwitcher_StateCache(t *testing.T) {
+	aws := &countingSwitcher{mockSwitcher: &mockSwitcher{name: "aws", state: &AWSConfig{Profile: "dev"}}}
+	kubernetes := &countingSwitcher{mockSwitcher: &mockSwitcher{name: "kubernetes", state: &KubernetesConfig{Context: "minikube"}}}
+	es := newTestSwitcher()
+	es.Register(aws)
+	es.Register(kubernetes)
+	es.SetStateCache(NewStateCache())
+
+	target := &Environment{Name: "staging", Services: map[string]ServiceConfig{
+		"aws": {AWS: &AWSConfig{Profile: "staging"}},
+	}}
+	last := &Environment{Name: "prod", Services: map[string]ServiceConfig{
+		"aws":        {AWS: &AWSConfig{Profile: "prod"}},
+		"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod-cluster"}},
+	}}
+
+	ctx := context.Background()
+	if _, err := es.Plan(ctx, target); err != nil {
+		t.Fatalf("Plan() error = %v", err)
+	}
+	drifts := es.Drift(ctx, last, nil)
+	if len(drifts) != 2 || drifts[0].Service != "aws" || drifts[1].Service != "kubernetes" {
+		t.Errorf("Drift() = %+v, want aws and kubernetes", drifts)
+	}
+	if aws.reads != 1 || kubernetes.reads != 1 {
+		t.Errorf("state reads = aws %d, kubernetes %d, want 1 each", aws.reads, kubernetes.reads)
+	}
+}
+
+// TestHistory_DriftBaseline_Retried tests detecting drift of a service
+// that failed in a partial switch and switched on the retry.
+func TestHistory_DriftBaseline_Retried(t *testing.T) {
+	gcp := &stateSwitcher{name: "gcp", current: &GCPConfig{Project: "sandbox"}, switchErr: errors.New("token expired")}
+	es := newTestSwitcher()
+	es.Register(&stateSwitcher{name: "aws", current: &AWSConfig{Profile: "default"}})
+	es.Register(gcp)
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es.SetHistory(history)
+
+	env := &Environment{
+		Name: "dev",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "dev"}},
+			"gcp": {GCP: &GCPConfig{Project: "dev"}},
+		},
+	}
+	ctx := context.Background()
+	if _, err := es.SwitchEnvironment(ctx, env, SwitchOptions{PartialSuccess: true}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	gcp.switchErr = nil
+	if _, err := es.RetryFailed(ctx, env, SwitchOptions{}); err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	// gcp is changed by hand after the retry
+	gcp.current = &GCPConfig{Project: "scratch"}
+
+	target := &Environment{Name: "aws-only", Services: map[string]ServiceConfig{
+		"aws": {AWS: &AWSConfig{Profile: "staging"}},
+	}}
+	baseline, err := history.DriftBaseline(target)
+	if err != nil || baseline == nil {
+		t.Fatalf("DriftBaseline() = %+v, %v, want gcp", baseline, err)
+	}
+	want := []Drift{{Service: "gcp", Diffs: []FieldDiff{{Service: "gcp", Field: "project", Left: "scratch", Right: "dev"}}}}
+	if got := es.Drift(ctx, baseline, nil); !reflect.DeepEqual(got, want) {
+		t.Errorf("Drift() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrEditAborted is returned by EditLoop.Run when the user gave up on an
+// invalid file and its original content was restored.
+var ErrEditAborted = errors.New("edit aborted")
+
+// EditLoop edits an environment file until it validates. Each step is a
+// function so the loop can run without a terminal or a real editor.
+type EditLoop struct {
+	// Edit opens path in an editor and returns once the user has quit it.
+	Edit func(path string) error
+	// Validate returns every problem in the file at path, or none if it is
+	// valid.
+	Validate func(path string) []string
+	// Retry is shown the problems of an invalid file and reports whether
+	// to re-open the editor; false aborts the edit.
+	Retry func(problems []string) bool
+}
+
+// Run edits path in place, re-opening the editor after each invalid save
+// until the file validates or the user aborts. On abort, or if the editor
+// fails, the file's original content is restored and ErrEditAborted or the
+// editor's error returned.
+func (l EditLoop) Run(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("failed to read environment file: %w", err)
+	}
+	// #nosec G304 - The path is an environment file the user asked to edit
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read environment file: %w", err)
+	}
+
+	restore := func(cause error) error {
+		// #nosec G306 - The file keeps its original permissions
+		if err := os.WriteFile(path, original, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("%w; failed to restore %s: %v", cause, path, err)
+		}
+		return cause
+	}
+
+	for {
+		if err := l.Edit(path); err != nil {
+			return restore(fmt.Errorf("editor failed: %w", err))
+		}
+
+		problems := l.Validate(path)
+		if len(problems) == 0 {
+			return nil
+		}
+		if !l.Retry(problems) {
+			return restore(ErrEditAborted)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEditLoop_Run tests re-opening the editor until the file validates,
+// and restoring the original content on abort or editor failure.
+func TestEditLoop_Run(t *testing.T) {
+	const original = "name: dev\n"
+	errEditor := errors.New("editor crashed")
+
+	tests := []struct {
+		name string
+		// saves are written by each successive editor run.
+		saves     []string
+		editorErr error
+		// retries answers the retry prompt, in order.
+		retries   []bool
+		wantErr   error
+		wantFile  string
+		wantEdits int
+	}{
+		{
+			name:      "valid on first save",
+			saves:     []string{"name: prod\n"},
+			wantFile:  "name: prod\n",
+			wantEdits: 1,
+		},
+		{
+			name:      "fixed after retry",
+			saves:     []string{"invalid\n", "name: prod\n"},
+			retries:   []bool{true},
+			wantFile:  "name: prod\n",
+			wantEdits: 2,
+		},
+		{
+			name:      "aborted",
+			saves:     []string{"invalid\n", "still invalid\n"},
+			retries:   []bool{true, false},
+			wantErr:   ErrEditAborted,
+			wantFile:  original,
+			wantEdits: 2,
+		},
+		{
+			name:      "editor fails",
+			saves:     []string{"invalid\n"},
+			editorErr: errEditor,
+			wantErr:   errEditor,
+			wantFile:  original,
+			wantEdits: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "dev.yaml")
+			if err := os.WriteFile(path, []byte(original), 0o640); err != nil {
+				t.Fatal(err)
+			}
+
+			edits, retries := 0, 0
+			loop := EditLoop{
+				Edit: func(p string) error {
+					if err := os.WriteFile(p, []byte(tt.saves[edits]), 0o600); err != nil {
+						t.Fatal(err)
+					}
+					edits++
+					return tt.editorErr
+				},
+				Validate: func(p string) []string {
+					data, _ := os.ReadFile(p)
+					if string(data) == "name: prod\n" {
+						return nil
+					}
+					return []string{"environment name is required"}
+				},
+				Retry: func(problems []string) bool {
+					if len(problems) != 1 {
+						t.Errorf("Retry() got problems %v, want 1", problems)
+					}
+					retries++
+					return tt.retries[retries-1]
+				},
+			}
+
+			err := loop.Run(path)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Run() error = %v, want %v", err, tt.wantErr)
+			}
+			if edits != tt.wantEdits {
+				t.Errorf("editor opened %d times, want %d", edits, tt.wantEdits)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil || string(data) != tt.wantFile {
+				t.Errorf("file = %q, %v after Run(), want %q", data, err, tt.wantFile)
+			}
+			if tt.wantFile == original {
+				if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0o640 {
+					t.Errorf("mode = %v after restore, want %v", info.Mode().Perm(), os.FileMode(0o640))
+				}
+			}
+		})
+	}
+}
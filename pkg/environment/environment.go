@@ -5,7 +5,10 @@ package environment
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,6 +25,7 @@ func LoadEnvironment(data []byte) (*Environment, error) {
 		return nil, fmt.Errorf("environment name is required")
 	}
 
+	env.NormalizeValues()
 	return &env, nil
 }
 
@@ -35,6 +39,17 @@ func LoadEnvironmentFromFile(filepath string) (*Environment, error) {
 	return LoadEnvironment(data)
 }
 
+// LoadEnvironmentFromReader loads an environment configuration from r,
+// such as standard input, reading it to the end.
+func LoadEnvironmentFromReader(r io.Reader) (*Environment, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment: %w", err)
+	}
+
+	return LoadEnvironment(data)
+}
+
 // Validate validates the environment configuration.
 func (e *Environment) Validate() error {
 	if e.Name == "" {
@@ -44,6 +59,35 @@ func (e *Environment) Validate() error {
 	if len(e.Services) == 0 {
 		return fmt.Errorf("at least one service must be configured")
 	}
+	if len(e.EnabledServices()) == 0 {
+		return fmt.Errorf("all services are disabled")
+	}
+
+	if e.Activate != "" {
+		if err := ValidateHookCommand(e.Activate); err != nil {
+			return fmt.Errorf("invalid activate script: %w", err)
+		}
+	}
+	if e.Deactivate != "" {
+		if err := ValidateHookCommand(e.Deactivate); err != nil {
+			return fmt.Errorf("invalid deactivate script: %w", err)
+		}
+	}
+
+	for _, alias := range e.Aliases {
+		if strings.TrimSpace(alias) == "" {
+			return fmt.Errorf("empty alias found")
+		}
+	}
+
+	// Reject enabled services declared without any configuration
+	names := e.GetServiceNames()
+	sort.Strings(names)
+	for _, name := range names {
+		if e.Services[name].IsEnabled() && e.Services[name].IsEmpty() {
+			return fmt.Errorf("service '%s' has no configuration", name)
+		}
+	}
 
 	// Validate dependencies
 	for _, dep := range e.Dependencies {
@@ -53,7 +97,85 @@ func (e *Environment) Validate() error {
 		}
 	}
 
-	return nil
+	if err := validateHooks(e.PreHooks, "pre-hook", e.Services); err != nil {
+		return err
+	}
+	return validateHooks(e.PostHooks, "post-hook", e.Services)
+}
+
+// IsEmpty reports whether no service-specific configuration is set.
+func (sc ServiceConfig) IsEmpty() bool {
+	return sc.AWS == nil && sc.GCP == nil && sc.Azure == nil &&
+		sc.Docker == nil && sc.Kubernetes == nil && sc.SSH == nil && sc.GPG == nil &&
+		sc.PkgMgr == nil && sc.Extra == nil
+}
+
+// IsEnabled reports whether the service is switched. Services are enabled
+// unless their configuration sets `enabled: false`.
+func (sc ServiceConfig) IsEnabled() bool {
+	return sc.Enabled == nil || *sc.Enabled
+}
+
+// EnabledServices returns the services that are not disabled.
+func (e *Environment) EnabledServices() map[string]ServiceConfig {
+	services := make(map[string]ServiceConfig, len(e.Services))
+	for name, config := range e.Services {
+		if config.IsEnabled() {
+			services[name] = config
+		}
+	}
+	return services
+}
+
+// EnabledDependencies returns the dependencies between enabled services.
+// A dependency naming a disabled service, or with a pattern matching only
+// disabled services, is dropped rather than treated as an error, so
+// disabling a service never breaks the services around it; the remaining
+// services keep their other ordering constraints. Malformed
+// dependencies and dependencies on undeclared services are kept, so
+// dependency resolution still reports them.
+func (e *Environment) EnabledDependencies() []string {
+	dependencies := make([]string, 0, len(e.Dependencies))
+	for _, dep := range e.Dependencies {
+		disabled := false
+		for _, service := range parseDependency(dep) {
+			if isServicePattern(service) {
+				disabled = disabled || e.patternDisabled(service)
+			} else if config, exists := e.Services[service]; exists && !config.IsEnabled() {
+				disabled = true
+			}
+		}
+		if !disabled {
+			dependencies = append(dependencies, dep)
+		}
+	}
+	return dependencies
+}
+
+// patternDisabled reports whether a dependency pattern matches configured
+// services, but only disabled ones.
+func (e *Environment) patternDisabled(pattern string) bool {
+	matches, err := matchServices(pattern, e.GetServiceNames())
+	if err != nil || len(matches) == 0 {
+		return false
+	}
+	for _, service := range matches {
+		if e.Services[service].IsEnabled() {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAlias reports whether name matches one of the environment's aliases,
+// ignoring case.
+func (e *Environment) HasAlias(name string) bool {
+	for _, alias := range e.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
 }
 
 // GetServiceNames returns a list of configured service names.
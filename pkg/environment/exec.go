@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	plugin "github.com/gizzahub/gzh-cli-dev-env/internal/exec"
+)
+
+// DefaultExecSwitcherTimeout bounds a single run of an external switcher,
+// like the default hook timeout.
+const DefaultExecSwitcherTimeout = 30 * time.Second
+
+// Subcommands an external switcher is invoked with.
+const (
+	// ExecSwitcherCurrent prints the current state as a JSON object.
+	ExecSwitcherCurrent = "current"
+	// ExecSwitcherSwitch reads the service's extra configuration as a JSON
+	// object on standard input and switches to it.
+	ExecSwitcherSwitch = "switch"
+	// ExecSwitcherRollback reads a state printed by current as a JSON
+	// object on standard input and restores it.
+	ExecSwitcherRollback = "rollback"
+)
+
+// ExecSwitcher is a ServiceSwitcher backed by an external executable, so
+// switchers for internal systems can be added without recompiling. Its
+// configuration is the service's Extra block.
+//
+// The executable is run with one of the ExecSwitcher subcommands. Configs
+// and states are JSON objects whose values are all strings, the JSON form
+// of an ExtraConfig. After a successful switch, current must report every
+// configured value, so snapshots and diffs see the switch. Exit status 0 is
+// success and anything else is failure; what the executable writes to
+// standard error is kept in the SwitchError detail. Standard output is
+// ignored except for current. Each run is bounded by Timeout.
+type ExecSwitcher struct {
+	name string
+	path string
+	// Timeout bounds each run, DefaultExecSwitcherTimeout if zero.
+	Timeout time.Duration
+}
+
+// NewExecSwitcher creates a switcher running the executable at path, named
+// after its base name without extension.
+func NewExecSwitcher(path string) *ExecSwitcher {
+	return &ExecSwitcher{name: plugin.Name(path), path: path}
+}
+
+// DiscoverExecSwitchers returns a switcher for every executable in dir,
+// ordered by name. Hidden files are skipped, and a missing dir has none.
+func DiscoverExecSwitchers(dir string) ([]*ExecSwitcher, error) {
+	paths, err := plugin.Discover(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read switchers directory: %w", err)
+	}
+
+	var switchers []*ExecSwitcher
+	for _, path := range paths {
+		switchers = append(switchers, NewExecSwitcher(path))
+	}
+	return switchers, nil
+}
+
+// Name returns the service name.
+func (e *ExecSwitcher) Name() string {
+	return e.name
+}
+
+// Path returns the path of the executable.
+func (e *ExecSwitcher) Path() string {
+	return e.path
+}
+
+// Switch runs the switch subcommand with config, an ExtraConfig.
+func (e *ExecSwitcher) Switch(ctx context.Context, config interface{}) error {
+	values, ok := config.(ExtraConfig)
+	if !ok {
+		return fmt.Errorf("invalid %s configuration type", e.name)
+	}
+	if _, err := e.run(ctx, ExecSwitcherSwitch, values); err != nil {
+		return fmt.Errorf("failed to switch %s: %w", e.name, err)
+	}
+	return nil
+}
+
+// GetCurrentState runs the current subcommand and returns the state it
+// prints as an ExtraConfig.
+func (e *ExecSwitcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	output, err := e.run(ctx, ExecSwitcherCurrent, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s state: %w", e.name, err)
+	}
+
+	state := ExtraConfig{}
+	if err := json.Unmarshal(output, &state); err != nil {
+		return nil, fmt.Errorf("%s printed an invalid state (want a JSON object of strings): %w", e.name, err)
+	}
+	return state, nil
+}
+
+// Rollback runs the rollback subcommand with previousState.
+func (e *ExecSwitcher) Rollback(ctx context.Context, previousState interface{}) error {
+	state, ok := previousState.(ExtraConfig)
+	if !ok {
+		return fmt.Errorf("invalid %s state type", e.name)
+	}
+	if _, err := e.run(ctx, ExecSwitcherRollback, state); err != nil {
+		return fmt.Errorf("failed to roll back %s: %w", e.name, err)
+	}
+	return nil
+}
+
+// run invokes the executable with subcommand, writing input as JSON to its
+// standard input if it is not nil, and returns its standard output. Under
+// Simulate the simulator answers instead.
+func (e *ExecSwitcher) run(ctx context.Context, subcommand string, input ExtraConfig) ([]byte, error) {
+	command := plugin.Command{Path: e.path, Args: []string{subcommand}, Timeout: e.Timeout}
+	if command.Timeout <= 0 {
+		command.Timeout = DefaultExecSwitcherTimeout
+	}
+
+	if recorder, ok := ctx.Value(simulatorKey{}).(*simulationRecorder); ok {
+		runCtx, cancel := context.WithTimeout(ctx, command.Timeout)
+		defer cancel()
+		return recorder.run(runCtx, e.path, subcommand)
+	}
+
+	if input != nil {
+		data, err := json.Marshal(input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode %s input: %w", subcommand, err)
+		}
+		command.Stdin = data
+	}
+	if id := SwitchIDFromContext(ctx); id != "" {
+		command.Env = []string{SwitchIDEnvVar + "=" + id}
+	}
+
+	start := time.Now()
+	stdout, stderr, err := command.Run(ctx)
+	traceCommand(ctx, e.path, command.Args, start, len(stdout), len(stderr), err)
+	if err != nil {
+		if errors.Is(err, plugin.ErrTimedOut) {
+			err = fmt.Errorf("%s %w", subcommand, err)
+		}
+		return stdout, &CommandError{
+			Command: commandLine(e.path, command.Args),
+			Stderr:  string(stderr),
+			Err:     err,
+		}
+	}
+	return stdout, nil
+}
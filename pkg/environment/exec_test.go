@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// skipWithoutShell skips tests whose switchers are shell scripts.
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("external switchers in tests are shell scripts")
+	}
+}
+
+// writeSwitcher writes an executable shell script switcher into dir.
+func writeSwitcher(t *testing.T, dir, name, body string) *ExecSwitcher {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o700); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	return NewExecSwitcher(path)
+}
+
+// exampleSwitcher returns the example switcher, keeping its state in a
+// temporary file.
+func exampleSwitcher(t *testing.T) *ExecSwitcher {
+	t.Helper()
+	skipWithoutShell(t)
+	t.Setenv("EXAMPLE_STATE_FILE", filepath.Join(t.TempDir(), "state.json"))
+	return NewExecSwitcher(filepath.Join("testdata", "switchers.d", "example"))
+}
+
+// TestDiscoverExecSwitchers tests discovery of the example switcher.
+func TestDiscoverExecSwitchers(t *testing.T) {
+	skipWithoutShell(t)
+
+	switchers, err := DiscoverExecSwitchers(filepath.Join("testdata", "switchers.d"))
+	if err != nil {
+		t.Fatalf("DiscoverExecSwitchers() error = %v", err)
+	}
+	if len(switchers) != 1 || switchers[0].Name() != "example" {
+		t.Fatalf("DiscoverExecSwitchers() = %v, want only the example switcher", switchers)
+	}
+
+	missing, err := DiscoverExecSwitchers(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || missing != nil {
+		t.Errorf("DiscoverExecSwitchers() on a missing dir = %v, %v, want none", missing, err)
+	}
+}
+
+// TestVerifyExecSwitcher_Example tests that the example switcher passes
+// the conformance steps.
+func TestVerifyExecSwitcher_Example(t *testing.T) {
+	switcher := exampleSwitcher(t)
+	ctx := context.Background()
+	if err := switcher.Switch(ctx, ExtraConfig{"workspace": "dev"}); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+
+	steps := VerifyExecSwitcher(ctx, switcher, ExtraConfig{"workspace": "staging", "region": "eu west"})
+	if len(steps) != 5 {
+		t.Errorf("VerifyExecSwitcher() ran %d steps, want 5", len(steps))
+	}
+	for _, step := range steps {
+		if step.Err != nil {
+			t.Errorf("step %q failed: %v", step.Name, step.Err)
+		}
+	}
+
+	state, err := switcher.GetCurrentState(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentState() error = %v", err)
+	}
+	if want := (ExtraConfig{"workspace": "dev"}); !reflect.DeepEqual(state, want) {
+		t.Errorf("GetCurrentState() = %v, want %v restored", state, want)
+	}
+}
+
+// TestVerifyExecSwitcher_Failures tests that conformance failures name the
+// failing step.
+func TestVerifyExecSwitcher_Failures(t *testing.T) {
+	skipWithoutShell(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name     string
+		body     string
+		wantStep string
+		wantErr  string
+	}{
+		{
+			name:     "state not an object of strings",
+			body:     `echo '{"ttl": 3600}'` + "\n",
+			wantStep: "current prints a JSON object of strings",
+			wantErr:  "invalid state",
+		},
+		{
+			name:     "switch not reported",
+			body:     `[ "$1" = current ] && echo '{}'; exit 0` + "\n",
+			wantStep: "current reports the switched config",
+			wantErr:  `workspace="staging"`,
+		},
+		{
+			name: "rollback unsupported",
+			body: `case "$1" in
+current) cat "$0.state" 2>/dev/null || echo '{}' ;;
+switch) cat >"$0.state" ;;
+rollback) exit 1 ;;
+esac
+`,
+			wantStep: "rollback accepts the previous state on stdin",
+			wantErr:  "exit status 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			switcher := writeSwitcher(t, dir, strings.ReplaceAll(tt.name, " ", "-"), tt.body)
+			steps := VerifyExecSwitcher(context.Background(), switcher, ExtraConfig{"workspace": "staging"})
+
+			last := steps[len(steps)-1]
+			for _, step := range steps {
+				if step.Err != nil {
+					last = step
+					break
+				}
+			}
+			if last.Name != tt.wantStep || last.Err == nil {
+				t.Fatalf("first failed step = %q (%v), want %q", last.Name, last.Err, tt.wantStep)
+			}
+			if !strings.Contains(last.Err.Error(), tt.wantErr) {
+				t.Errorf("step error = %v, want it to contain %q", last.Err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestExecSwitcher_Timeout tests that a hanging switcher is stopped.
+func TestExecSwitcher_Timeout(t *testing.T) {
+	skipWithoutShell(t)
+	switcher := writeSwitcher(t, t.TempDir(), "slow", "sleep 5\n")
+	switcher.Timeout = 200 * time.Millisecond
+
+	_, err := switcher.GetCurrentState(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "current timed out after 200ms") {
+		t.Errorf("GetCurrentState() error = %v, want a timeout", err)
+	}
+}
+
+// TestEnvironmentSwitcher_ExecSwitcher tests switching an external
+// switcher configured through an environment's extra block, and that its
+// standard error ends up in the switch error detail.
+func TestEnvironmentSwitcher_ExecSwitcher(t *testing.T) {
+	example := exampleSwitcher(t)
+	failing := writeSwitcher(t, t.TempDir(), "vault", `
+case "$1" in
+current) echo '{"role": "dev"}' ;;
+switch) cat >/dev/null; echo "vault: permission denied for role prod" >&2; exit 1 ;;
+esac
+`)
+
+	es := newTestSwitcher()
+	es.Register(example)
+	es.Register(failing)
+
+	env := &Environment{
+		Name: "prod",
+		Services: map[string]ServiceConfig{
+			"example": {Extra: ExtraConfig{"workspace": "prod"}},
+			"vault":   {Extra: ExtraConfig{"role": "prod"}},
+		},
+		Dependencies: []string{"example -> vault"},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{PartialSuccess: true})
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if !reflect.DeepEqual(result.SwitchedServices, []string{"example"}) {
+		t.Errorf("SwitchedServices = %v, want [example]", result.SwitchedServices)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Detail != "vault: permission denied for role prod" {
+		t.Errorf("Errors = %+v, want the vault stderr as detail", result.Errors)
+	}
+
+	state, err := example.GetCurrentState(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentState() error = %v", err)
+	}
+	if want := (ExtraConfig{"workspace": "prod"}); !reflect.DeepEqual(state, want) {
+		t.Errorf("example state = %v, want %v", state, want)
+	}
+}
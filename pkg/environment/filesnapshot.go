@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultFileSnapshotRetention is how many file snapshots are kept unless
+// NewFileSnapshots is given another limit.
+const DefaultFileSnapshotRetention = 10
+
+// fileSnapshotManifest is the name of the manifest in a snapshot directory.
+const fileSnapshotManifest = "manifest.json"
+
+// DefaultFileSnapshotDir returns ~/.gzh/dev-env/snapshots.
+func DefaultFileSnapshotDir() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "snapshots")
+}
+
+// FileSnapshot records the configuration files copied before a switch.
+type FileSnapshot struct {
+	SwitchID string         `json:"switchId"`
+	TakenAt  time.Time      `json:"takenAt"`
+	Files    []SnapshotFile `json:"files"`
+	// Dir is the directory holding the copies.
+	Dir string `json:"-"`
+}
+
+// SnapshotFile is one file in a FileSnapshot.
+type SnapshotFile struct {
+	// Path is where the file was copied from and is restored to.
+	Path string `json:"path"`
+	// Name is the copy's name in the snapshot directory.
+	Name string      `json:"name"`
+	Mode os.FileMode `json:"mode"`
+}
+
+// FileSnapshots stores byte-for-byte copies of the configuration files a
+// switch may modify, one directory per switch, so a file corrupted by a
+// switcher or hook can be restored. Rollback only restores what
+// GetCurrentState captured, not the files themselves. The oldest snapshots
+// are removed once there are more than the retention limit.
+type FileSnapshots struct {
+	dir       string
+	retention int
+	now       func() time.Time
+}
+
+// NewFileSnapshots stores snapshots in dir, or DefaultFileSnapshotDir if
+// empty, keeping the newest retention of them, or
+// DefaultFileSnapshotRetention if retention is not positive.
+func NewFileSnapshots(dir string, retention int) *FileSnapshots {
+	if dir == "" {
+		dir = DefaultFileSnapshotDir()
+	}
+	if retention <= 0 {
+		retention = DefaultFileSnapshotRetention
+	}
+	return &FileSnapshots{dir: dir, retention: retention, now: time.Now}
+}
+
+// ClaimPath returns the file named by a "file:" resource claim, with a
+// leading ~ expanded to the home directory. ok is false for other claims.
+func ClaimPath(claim string) (path string, ok bool) {
+	path, ok = strings.CutPrefix(claim, "file:")
+	if !ok || path == "" {
+		return "", false
+	}
+	if rest, found := strings.CutPrefix(path, "~/"); found {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", false
+		}
+		path = filepath.Join(homeDir, rest)
+	}
+	return path, true
+}
+
+// KubeconfigClaims returns the claims on the kubeconfig files kubectl
+// uses: one "file:" claim for each entry of KUBECONFIG, which may list
+// several files, or ClaimKubeconfig if it is unset. Relative entries are
+// made absolute so that the same file is always the same claim.
+func KubeconfigClaims() []string {
+	var claims []string
+	seen := make(map[string]bool)
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if path == "" {
+			continue
+		}
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if !seen[path] {
+			seen[path] = true
+			claims = append(claims, "file:"+path)
+		}
+	}
+	if len(claims) == 0 {
+		return []string{ClaimKubeconfig}
+	}
+	return claims
+}
+
+// Take copies paths into a new snapshot for the switch switchID. Paths
+// that do not exist are skipped, as are directories, such as the gcloud
+// configuration, which can hold logs and caches far larger than the
+// settings a switch changes. If only removing old snapshots fails, the new
+// snapshot is returned along with the error.
+func (s *FileSnapshots) Take(switchID string, paths []string) (*FileSnapshot, error) {
+	if switchID == "" || filepath.Base(switchID) != switchID {
+		return nil, fmt.Errorf("invalid switch ID for a file snapshot: %q", switchID)
+	}
+
+	snapshot := &FileSnapshot{SwitchID: switchID, TakenAt: s.now().UTC(), Files: []SnapshotFile{}}
+	snapshot.Dir = filepath.Join(s.dir, snapshot.TakenAt.Format("20060102T150405Z")+"-"+switchID)
+	if err := os.MkdirAll(snapshot.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	if err := s.copyFiles(snapshot, paths); err != nil {
+		_ = os.RemoveAll(snapshot.Dir)
+		return nil, err
+	}
+	if err := s.prune(); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// copyFiles copies paths into snapshot.Dir and writes the manifest.
+func (s *FileSnapshots) copyFiles(snapshot *FileSnapshot, paths []string) error {
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		name := fmt.Sprintf("%02d-%s", len(snapshot.Files), filepath.Base(path))
+		if err := copyFile(path, filepath.Join(snapshot.Dir, name), 0o600); err != nil {
+			return fmt.Errorf("failed to snapshot %s: %w", path, err)
+		}
+		snapshot.Files = append(snapshot.Files, SnapshotFile{Path: path, Name: name, Mode: info.Mode().Perm()})
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(snapshot.Dir, fileSnapshotManifest), data, 0o600); err != nil {
+		return fmt.Errorf("failed to write snapshot manifest: %w", err)
+	}
+	return nil
+}
+
+// Find returns the snapshot taken before the switch switchID.
+func (s *FileSnapshots) Find(switchID string) (*FileSnapshot, error) {
+	dirs, err := s.dirs()
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range dirs {
+		if strings.HasSuffix(dir, "-"+switchID) {
+			return s.load(filepath.Join(s.dir, dir))
+		}
+	}
+	return nil, fmt.Errorf("no file snapshot for switch %s", switchID)
+}
+
+// Restore copies the files in snapshot back to where they were taken from,
+// with their original permissions, and returns their paths. Each file is
+// replaced atomically, so an interrupted restore never leaves one half
+// written.
+func (s *FileSnapshots) Restore(snapshot *FileSnapshot) ([]string, error) {
+	restored := make([]string, 0, len(snapshot.Files))
+	for _, file := range snapshot.Files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0o700); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+		}
+
+		tmp := file.Path + ".dev-env-restore"
+		if err := copyFile(filepath.Join(snapshot.Dir, file.Name), tmp, file.Mode); err != nil {
+			_ = os.Remove(tmp)
+			return restored, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+		}
+		if err := os.Rename(tmp, file.Path); err != nil {
+			_ = os.Remove(tmp)
+			return restored, fmt.Errorf("failed to restore %s: %w", file.Path, err)
+		}
+		restored = append(restored, file.Path)
+	}
+	return restored, nil
+}
+
+// load reads the manifest of the snapshot in dir.
+func (s *FileSnapshots) load(dir string) (*FileSnapshot, error) {
+	// #nosec G304 - The manifest is in dev-env's own snapshot directory
+	data, err := os.ReadFile(filepath.Join(dir, fileSnapshotManifest))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot manifest: %w", err)
+	}
+
+	var snapshot FileSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot manifest in %s: %w", dir, err)
+	}
+	snapshot.Dir = dir
+	return &snapshot, nil
+}
+
+// dirs returns the snapshot directory names, newest first. Names start
+// with the time the snapshot was taken, so they sort chronologically.
+func (s *FileSnapshots) dirs() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var dirs []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			dirs = append(dirs, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(dirs)))
+	return dirs, nil
+}
+
+// prune removes the snapshots beyond the retention limit, oldest first.
+func (s *FileSnapshots) prune() error {
+	dirs, err := s.dirs()
+	if err != nil || len(dirs) <= s.retention {
+		return err
+	}
+	for _, dir := range dirs[s.retention:] {
+		if err := os.RemoveAll(filepath.Join(s.dir, dir)); err != nil {
+			return fmt.Errorf("failed to remove old snapshot: %w", err)
+		}
+	}
+	return nil
+}
+
+// copyFile copies src to dst, creating or truncating dst with mode.
+func copyFile(src, dst string, mode os.FileMode) error {
+	// #nosec G304 - Paths come from switcher claims and snapshot manifests
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	// #nosec G306 - mode is the original file's permissions
+	return os.WriteFile(dst, data, mode)
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fileClaimingSwitcher is a mock switcher that claims files.
+type fileClaimingSwitcher struct {
+	*mockSwitcher
+	claims []string
+}
+
+func (f fileClaimingSwitcher) Claims() []string { return f.claims }
+
+// TestClaimPath tests extracting file paths from resource claims.
+func TestClaimPath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+
+	tests := []struct {
+		claim  string
+		want   string
+		wantOK bool
+	}{
+		{ClaimKubeconfig, filepath.Join(home, ".kube", "config"), true},
+		{"file:/etc/hosts", "/etc/hosts", true},
+		{"file:", "", false},
+		{"lock:vpn", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ClaimPath(tt.claim)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ClaimPath(%q) = %q, %v, want %q, %v", tt.claim, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// TestKubeconfigClaims tests that the kubeconfig claims follow KUBECONFIG.
+func TestKubeconfigClaims(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a"), filepath.Join(dir, "b")
+
+	tests := []struct {
+		name       string
+		kubeconfig string
+		want       []string
+	}{
+		{"unset", "", []string{ClaimKubeconfig}},
+		{"one file", a, []string{"file:" + a}},
+		{"path list", a + string(os.PathListSeparator) + b + string(os.PathListSeparator) + a, []string{"file:" + a, "file:" + b}},
+		{"empty entries", string(os.PathListSeparator), []string{ClaimKubeconfig}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.kubeconfig)
+			if got := KubeconfigClaims(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("KubeconfigClaims() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFileSnapshots_TakeAndRestore tests that a snapshot restores the
+// original bytes and permissions of its files.
+func TestFileSnapshots_TakeAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "kube", "config")
+	sshConfig := filepath.Join(dir, "ssh", "config")
+	for path, content := range map[string]string{kubeconfig: "current-context: dev\n", sshConfig: "Host dev\n"} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots := NewFileSnapshots(filepath.Join(dir, "snapshots"), 0)
+	snapshot, err := snapshots.Take("01SWITCH", []string{kubeconfig, sshConfig, filepath.Join(dir, "missing"), filepath.Join(dir, "kube")})
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	if len(snapshot.Files) != 2 {
+		t.Fatalf("Take() copied %d files, want 2 (missing files and directories are skipped)", len(snapshot.Files))
+	}
+
+	// Corrupt one file and delete the other.
+	if err := os.WriteFile(kubeconfig, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Dir(sshConfig)); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := snapshots.Find("01SWITCH")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	restored, err := snapshots.Restore(found)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if len(restored) != 2 {
+		t.Errorf("Restore() = %v, want both files", restored)
+	}
+
+	for path, want := range map[string]string{kubeconfig: "current-context: dev\n", sshConfig: "Host dev\n"} {
+		data, err := os.ReadFile(path)
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v after Restore(), want %q", path, data, err, want)
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().Perm() != 0o640 {
+			t.Errorf("%s mode = %v after Restore(), want %v", path, info.Mode().Perm(), os.FileMode(0o640))
+		}
+	}
+
+	if _, err := snapshots.Find("01OTHER"); err == nil {
+		t.Error("Find() should fail for a switch without a snapshot")
+	}
+}
+
+// TestFileSnapshots_Retention tests that only the newest snapshots are kept.
+func TestFileSnapshots_Retention(t *testing.T) {
+	dir := t.TempDir()
+	snapshots := NewFileSnapshots(dir, 2)
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	snapshots.now = func() time.Time { return at }
+
+	for _, id := range []string{"01A", "01B", "01C"} {
+		if _, err := snapshots.Take(id, nil); err != nil {
+			t.Fatalf("Take(%s) error = %v", id, err)
+		}
+		at = at.Add(time.Minute)
+	}
+
+	if _, err := snapshots.Find("01A"); err == nil {
+		t.Error("the oldest snapshot should have been removed")
+	}
+	for _, id := range []string{"01B", "01C"} {
+		if _, err := snapshots.Find(id); err != nil {
+			t.Errorf("Find(%s) error = %v, want it kept", id, err)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_FileSnapshots tests that claimed files are
+// snapshotted before a switch and the snapshot is recorded in the history.
+func TestEnvironmentSwitcher_FileSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	kubeconfig := filepath.Join(dir, "config")
+	if err := os.WriteFile(kubeconfig, []byte("current-context: dev\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	es := newTestSwitcher()
+	es.Register(fileClaimingSwitcher{newMockSwitcher("kubernetes"), []string{"file:" + kubeconfig, "lock:cluster"}})
+	es.Register(newMockSwitcher("docker"))
+	snapshots := NewFileSnapshots(filepath.Join(dir, "snapshots"), 0)
+	es.SetFileSnapshots(snapshots)
+	history := NewHistory(filepath.Join(dir, "history.json"))
+	es.SetHistory(history)
+
+	env := &Environment{
+		Name: "dev",
+		Services: map[string]ServiceConfig{
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "dev"}},
+			"docker":     {Docker: &DockerConfig{Context: "dev"}},
+		},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{SwitchID: "01SWITCH"})
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if !strings.HasSuffix(result.FileSnapshot, "-01SWITCH") {
+		t.Errorf("FileSnapshot = %q, want the snapshot directory for the switch", result.FileSnapshot)
+	}
+
+	snapshot, err := snapshots.Find("01SWITCH")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if len(snapshot.Files) != 1 || snapshot.Files[0].Path != kubeconfig {
+		t.Errorf("snapshot files = %+v, want only %s", snapshot.Files, kubeconfig)
+	}
+
+	last, err := history.Last()
+	if err != nil || last == nil || last.FileSnapshot != result.FileSnapshot {
+		t.Errorf("history entry = %+v, %v, want FileSnapshot %q", last, err, result.FileSnapshot)
+	}
+
+	dryRun, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{DryRun: true, SwitchID: "01DRYRUN"})
+	if err != nil || dryRun.FileSnapshot != "" {
+		t.Errorf("dry run FileSnapshot = %q, %v, want no snapshot", dryRun.FileSnapshot, err)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// switchOutcome records the outcome of the most recent environment switch.
+type switchOutcome struct {
+	success bool
+	err     string
+	at      time.Time
+}
+
+// recordOutcome stores the outcome of a switch for readiness reporting.
+func (es *EnvironmentSwitcher) recordOutcome(result *SwitchResult, err error) {
+	outcome := &switchOutcome{
+		success: err == nil && result != nil && result.Success,
+		at:      time.Now(),
+	}
+	if err != nil {
+		outcome.err = err.Error()
+	}
+
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.lastSwitch = outcome
+}
+
+// HealthzHandler returns an HTTP handler serving /healthz and /readyz probes.
+//
+// /healthz reports liveness and returns 200 once the switcher is initialized.
+// /readyz reports readiness and returns 503 if the last switch failed; before
+// any switch has run, and after a successful one, it returns 200.
+func (es *EnvironmentSwitcher) HealthzHandler() http.Handler {
+	mux := http.NewServeMux()
+	es.RegisterHealthz(mux)
+	return mux
+}
+
+// RegisterHealthz mounts the /healthz and /readyz probes on an existing mux,
+// so they can share a server with other endpoints.
+func (es *EnvironmentSwitcher) RegisterHealthz(mux *http.ServeMux) {
+	mux.HandleFunc("/healthz", es.serveHealthz)
+	mux.HandleFunc("/readyz", es.serveReadyz)
+}
+
+// serveHealthz handles the liveness probe.
+func (es *EnvironmentSwitcher) serveHealthz(w http.ResponseWriter, _ *http.Request) {
+	es.mu.RLock()
+	initialized := es.serviceSwitchers != nil
+	es.mu.RUnlock()
+
+	if !initialized {
+		http.Error(w, "switcher not initialized", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintln(w, "ok")
+}
+
+// serveReadyz handles the readiness probe.
+func (es *EnvironmentSwitcher) serveReadyz(w http.ResponseWriter, _ *http.Request) {
+	es.mu.RLock()
+	outcome := es.lastSwitch
+	es.mu.RUnlock()
+
+	if outcome != nil && !outcome.success {
+		msg := "last switch failed"
+		if outcome.err != "" {
+			msg = fmt.Sprintf("last switch failed at %s: %s", outcome.at.Format(time.RFC3339), outcome.err)
+		}
+		http.Error(w, msg, http.StatusServiceUnavailable)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	_, _ = fmt.Fprintln(w, "ok")
+}
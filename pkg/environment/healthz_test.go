@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// probe issues a GET against the handler and returns the status code.
+func probe(t *testing.T, handler http.Handler, path string) int {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+// TestEnvironmentSwitcher_HealthzHandler_Liveness tests the liveness probe.
+func TestEnvironmentSwitcher_HealthzHandler_Liveness(t *testing.T) {
+	es := newTestSwitcher()
+
+	if code := probe(t, es.HealthzHandler(), "/healthz"); code != http.StatusOK {
+		t.Errorf("/healthz = %d, want %d", code, http.StatusOK)
+	}
+}
+
+// TestEnvironmentSwitcher_HealthzHandler_Readiness tests readiness across switch outcomes.
+func TestEnvironmentSwitcher_HealthzHandler_Readiness(t *testing.T) {
+	es := newTestSwitcher()
+	handler := es.HealthzHandler()
+
+	if code := probe(t, handler, "/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz before any switch = %d, want %d", code, http.StatusOK)
+	}
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "test"}},
+		},
+	}
+
+	es.Register(newErrorMockSwitcher("aws"))
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err == nil {
+		t.Fatal("SwitchEnvironment() should fail with error mock")
+	}
+
+	if code := probe(t, handler, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz after failed switch = %d, want %d", code, http.StatusServiceUnavailable)
+	}
+
+	es.Register(newMockSwitcher("aws"))
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	if code := probe(t, handler, "/readyz"); code != http.StatusOK {
+		t.Errorf("/readyz after successful switch = %d, want %d", code, http.StatusOK)
+	}
+}
+
+// TestEnvironmentSwitcher_RegisterHealthz tests mounting probes on a shared mux.
+func TestEnvironmentSwitcher_RegisterHealthz(t *testing.T) {
+	es := newTestSwitcher()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	es.RegisterHealthz(mux)
+
+	for _, path := range []string{"/metrics", "/healthz", "/readyz"} {
+		if code := probe(t, mux, path); code != http.StatusOK {
+			t.Errorf("%s = %d, want %d", path, code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxHistoryEntries caps the number of switches kept in the history file.
+const maxHistoryEntries = 50
+
+// HistoryEntry records a completed environment switch. Failed lists the
+// services that failed in a partial switch, or were skipped because a
+// service they depend on failed, for retrying later.
+type HistoryEntry struct {
+	SwitchID string `json:"switchId,omitempty"`
+	// Type is empty for a switch, or HistoryPartialRollback.
+	Type        string    `json:"type,omitempty"`
+	Environment string    `json:"environment"`
+	Deactivate  string    `json:"deactivate,omitempty"`
+	SwitchedAt  time.Time `json:"switchedAt"`
+	// User is the OS user who switched, as returned by CurrentUser.
+	User   string   `json:"user,omitempty"`
+	Failed []string `json:"failed,omitempty"`
+	// FileSnapshot is the directory holding the files copied before the
+	// switch, if file snapshots are enabled.
+	FileSnapshot string `json:"fileSnapshot,omitempty"`
+	// Services are the services the switch switched, or the one a partial
+	// rollback restored.
+	Services []string `json:"services,omitempty"`
+	// PreviousStates are the states of Services before the switch, so one
+	// service can be rolled back on its own; see RollbackService.
+	PreviousStates map[string]ServiceConfig `json:"previousStates,omitempty"`
+	// SwitchedConfigs are the configurations the switch applied to
+	// Services, overrides included, which drift is detected against; see
+	// History.DriftBaseline.
+	SwitchedConfigs map[string]ServiceConfig `json:"switchedConfigs,omitempty"`
+	// RollbackOf is the switch a partial rollback undid part of.
+	RollbackOf string `json:"rollbackOf,omitempty"`
+	// Overrides are the path=value overrides the environment was switched
+	// with; see SwitchOptions.Overrides.
+	Overrides []string `json:"overrides,omitempty"`
+	// Audit records how the switch or partial rollback was run.
+	Audit *AuditRecord `json:"audit,omitempty"`
+	// Retries are the audit records of the RetryFailed runs that
+	// re-attempted the entry's failed services, oldest first.
+	Retries []AuditRecord `json:"retries,omitempty"`
+}
+
+// Audit operations.
+const (
+	AuditSwitch   = "switch"
+	AuditRetry    = "retry"
+	AuditRollback = "rollback"
+)
+
+// AuditRecord is the audit record of one run that changed services: which
+// operation ran where, with which options, and what failed. Its SwitchID is
+// the ID of that run's progress events and hook environment, so the record
+// can be joined with them.
+type AuditRecord struct {
+	Operation string `json:"operation"`
+	SwitchID  string `json:"switchId"`
+	Host      string `json:"host,omitempty"`
+	// Options are the switch options the run was given that change what it
+	// does, such as "force" and "partial-success".
+	Options []string `json:"options,omitempty"`
+	// Errors are the run's failures as "service: error", without the CLI
+	// output captured with them, which may hold credentials.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// newAuditRecord returns the audit record of a run of operation with
+// options and result, which may be nil.
+func newAuditRecord(operation, switchID string, options SwitchOptions, result *SwitchResult) *AuditRecord {
+	record := &AuditRecord{Operation: operation, SwitchID: switchID}
+	record.Host, _ = os.Hostname()
+
+	for _, option := range []struct {
+		set  bool
+		name string
+	}{
+		{options.Force, "force"},
+		{options.Parallel, "parallel"},
+		{options.PartialSuccess, "partial-success"},
+		{options.StrictHooks, "strict-hooks"},
+		{options.SkipValidation, "skip-validation"},
+		{options.AllowRoot, "allow-root"},
+	} {
+		if option.set {
+			record.Options = append(record.Options, option.name)
+		}
+	}
+
+	if result != nil {
+		for _, err := range result.Errors {
+			record.Errors = append(record.Errors, err.Service+": "+err.Error)
+		}
+	}
+	return record
+}
+
+// HistoryPartialRollback is the Type of the entries RollbackService records.
+const HistoryPartialRollback = "partial-rollback"
+
+// History is a file-backed log of environment switches, most recent last.
+// The deactivate script is recorded with each entry so it can run when
+// switching away, even if the environment file has since changed.
+type History struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultHistoryPath returns the default history location, ~/.gzh/dev-env/history.json.
+func DefaultHistoryPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "history.json")
+}
+
+// NewHistory creates a history stored at path, or at DefaultHistoryPath if empty.
+func NewHistory(path string) *History {
+	if path == "" {
+		path = DefaultHistoryPath()
+	}
+	return &History{path: path}
+}
+
+// Entries returns all recorded switches, oldest first.
+func (h *History) Entries() ([]HistoryEntry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.load()
+}
+
+// Last returns the most recent switch, or nil if there is none.
+func (h *History) Last() (*HistoryEntry, error) {
+	entries, err := h.Entries()
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return &entries[len(entries)-1], nil
+}
+
+// Find returns the switch with switchID, or the most recent switch if
+// switchID is empty. Partial rollbacks are not switches and are skipped.
+func (h *History) Find(switchID string) (*HistoryEntry, error) {
+	entries, err := h.Entries()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := &entries[i]
+		if entry.Type == HistoryPartialRollback {
+			continue
+		}
+		if switchID == "" || entry.SwitchID == switchID {
+			return entry, nil
+		}
+	}
+	if switchID == "" {
+		return nil, errors.New("no switch has been recorded")
+	}
+	return nil, fmt.Errorf("switch %s not found in the history", switchID)
+}
+
+// Record appends a switch to the history.
+func (h *History) Record(entry HistoryEntry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries, err := h.load()
+	if err != nil {
+		return err
+	}
+
+	entries = append(entries, entry)
+	if len(entries) > maxHistoryEntries {
+		entries = entries[len(entries)-maxHistoryEntries:]
+	}
+	return h.save(entries)
+}
+
+// UpdateLast applies update to the most recent switch. It is an error if
+// the history is empty.
+func (h *History) UpdateLast(update func(entry *HistoryEntry)) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries, err := h.load()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return fmt.Errorf("history is empty")
+	}
+
+	update(&entries[len(entries)-1])
+	return h.save(entries)
+}
+
+// save writes entries to the history file.
+func (h *History) save(entries []HistoryEntry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode history: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+	if err := os.WriteFile(h.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write history: %w", err)
+	}
+	return nil
+}
+
+// load reads the history file; a missing file is an empty history.
+func (h *History) load() ([]HistoryEntry, error) {
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var entries []HistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse history %s: %w", h.path, err)
+	}
+	return entries, nil
+}
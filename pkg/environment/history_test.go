@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHistory_RecordAndLast tests recording switches and reading them back.
+func TestHistory_RecordAndLast(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "nested", "history.json"))
+
+	last, err := history.Last()
+	if err != nil || last != nil {
+		t.Fatalf("Last() on empty history = %v, %v, want nil, nil", last, err)
+	}
+
+	for _, name := range []string{"dev", "staging"} {
+		if err := history.Record(HistoryEntry{Environment: name, SwitchedAt: time.Now()}); err != nil {
+			t.Fatalf("Record(%s) error = %v", name, err)
+		}
+	}
+
+	last, err = history.Last()
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if last.Environment != "staging" {
+		t.Errorf("Last().Environment = %q, want %q", last.Environment, "staging")
+	}
+}
+
+// TestHistory_Cap tests that old entries are dropped.
+func TestHistory_Cap(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+
+	for i := 0; i < maxHistoryEntries+5; i++ {
+		if err := history.Record(HistoryEntry{Environment: "env"}); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	entries, err := history.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != maxHistoryEntries {
+		t.Errorf("len(Entries()) = %d, want %d", len(entries), maxHistoryEntries)
+	}
+}
+
+// TestEnvironmentSwitcher_ActivationOrder tests that switching from A to B runs
+// A's activate, then A's deactivate before B's activate.
+func TestEnvironmentSwitcher_ActivationOrder(t *testing.T) {
+	dir := t.TempDir()
+	marker := func(name string) string { return filepath.Join(dir, name) }
+
+	envA := &Environment{
+		Name:     "env-a",
+		Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "a"}}},
+		// A must not be deactivated before it was activated.
+		Activate:   "touch " + marker("a-activated"),
+		Deactivate: "set -e\nls " + marker("a-activated") + "\ntouch " + marker("a-deactivated"),
+	}
+	envB := &Environment{
+		Name:     "env-b",
+		Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "b"}}},
+		// B's activate fails unless A was deactivated first.
+		Activate:   "set -e\nls " + marker("a-deactivated") + "\ntouch " + marker("b-activated"),
+		Deactivate: "touch " + marker("b-deactivated"),
+	}
+
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+	history := NewHistory(filepath.Join(dir, "history.json"))
+	es.SetHistory(history)
+
+	for _, env := range []*Environment{envA, envB} {
+		result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+		if err != nil {
+			t.Fatalf("SwitchEnvironment(%s) error = %v", env.Name, err)
+		}
+		if len(result.Errors) != 0 {
+			t.Fatalf("SwitchEnvironment(%s) errors = %+v", env.Name, result.Errors)
+		}
+	}
+
+	for _, name := range []string{"a-activated", "a-deactivated", "b-activated"} {
+		if _, err := os.Stat(marker(name)); err != nil {
+			t.Errorf("%s script did not run: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(marker("b-deactivated")); err == nil {
+		t.Error("b should not be deactivated while it is the current environment")
+	}
+
+	entries, err := history.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 2 || entries[0].Environment != "env-a" || entries[1].Environment != "env-b" {
+		t.Errorf("history = %+v, want env-a then env-b", entries)
+	}
+}
+
+// TestEnvironmentSwitcher_ActivationSameEnvironment tests that switching to
+// the environment already active does not deactivate it.
+func TestEnvironmentSwitcher_ActivationSameEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	env := &Environment{
+		Name:       "env-a",
+		Services:   map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "a"}}},
+		Activate:   "touch " + filepath.Join(dir, "activated"),
+		Deactivate: "touch " + filepath.Join(dir, "deactivated"),
+	}
+
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+	es.SetHistory(NewHistory(filepath.Join(dir, "history.json")))
+
+	for i := 0; i < 2; i++ {
+		result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+		if err != nil || len(result.Errors) != 0 {
+			t.Fatalf("SwitchEnvironment() = %+v, %v", result, err)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "deactivated")); err == nil {
+		t.Error("re-switching to env-a should not deactivate it")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "activated")); err != nil {
+		t.Errorf("activate script did not run: %v", err)
+	}
+}
+
+// TestEnvironmentSwitcher_ActivationSkippedOnDryRun tests that dry runs neither
+// run scripts nor record history.
+func TestEnvironmentSwitcher_ActivationSkippedOnDryRun(t *testing.T) {
+	dir := t.TempDir()
+	env := &Environment{
+		Name:     "env-a",
+		Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "a"}}},
+		Activate: "touch " + filepath.Join(dir, "activated"),
+	}
+
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+	history := NewHistory(filepath.Join(dir, "history.json"))
+	es.SetHistory(history)
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{DryRun: true}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "activated")); err == nil {
+		t.Error("activate script should not run in dry-run mode")
+	}
+	if last, _ := history.Last(); last != nil {
+		t.Errorf("history should be empty after dry run, got %+v", last)
+	}
+}
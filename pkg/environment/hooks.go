@@ -0,0 +1,286 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// hookNamePattern matches valid hook names. Names cannot contain "->" or
+// glob characters, so they can be ordered by the dependency resolver.
+var hookNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// hookName returns the name of the i-th hook of hookType: its Name, or
+// "<hookType>-<i>" for an unnamed hook.
+func hookName(hook Hook, hookType string, i int) string {
+	if hook.Name != "" {
+		return hook.Name
+	}
+	return fmt.Sprintf("%s-%d", hookType, i)
+}
+
+// assignmentPattern matches a leading VAR=value word of a command.
+var assignmentPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*=`)
+
+// hookLabel returns how the hook called name appears in errors and
+// results: name, followed for an unnamed hook by the program its command
+// runs, such as "pre-hook-0 (make)", so a failing hook can be told apart
+// from its index alone.
+func hookLabel(hook Hook, name string) string {
+	program := hookProgram(hook.Command)
+	if hook.Name != "" || program == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, program)
+}
+
+// hookProgram returns the base name of the program command runs, skipping
+// leading VAR=value assignments and a "cd dir &&" or "cd dir;" prefix, so
+// that "cd infra && TF_LOG=info terraform apply" is "terraform". It is ""
+// for a command with no program.
+func hookProgram(command string) string {
+	words, err := commandWords(command)
+	if err != nil {
+		words = strings.Fields(command)
+	}
+	for i := 0; i < len(words); i++ {
+		word := words[i]
+		switch {
+		case assignmentPattern.MatchString(word):
+		case word == "cd":
+			next := slices.IndexFunc(words[i:], func(w string) bool {
+				return w == "&&" || w == ";" || strings.HasSuffix(w, ";")
+			})
+			if next < 0 {
+				return word
+			}
+			i += next
+		default:
+			return filepath.Base(word)
+		}
+	}
+	return ""
+}
+
+// continuedHooksError is returned by executeHooks when hooks with OnError
+// "continue" failed. The hooks after them still ran, and each failure is
+// in the result's Errors, marked Continued.
+type continuedHooksError struct {
+	hooks []string
+}
+
+func (e *continuedHooksError) Error() string {
+	return fmt.Sprintf("%d hook(s) failed with onError continue: %s", len(e.hooks), strings.Join(e.hooks, ", "))
+}
+
+// validateHooks checks the names, runAfter references, and conditions of
+// the pre or post hooks of an environment with services, and that their
+// runAfter references do not form a cycle.
+func validateHooks(hooks []Hook, hookType string, services map[string]ServiceConfig) error {
+	names := make(map[string]bool, len(hooks))
+	for i, hook := range hooks {
+		name := hookName(hook, hookType, i)
+		if hook.Name != "" && !hookNamePattern.MatchString(hook.Name) {
+			return fmt.Errorf("invalid %s name '%s': use letters, digits, '.', '_', and '-'", hookType, hook.Name)
+		}
+		if names[name] {
+			return fmt.Errorf("duplicate %s name '%s'", hookType, name)
+		}
+		names[name] = true
+
+		if hookType == "pre-hook" && (len(hook.OnlyIfSwitched) > 0 || len(hook.OnlyIfChanged) > 0) {
+			return fmt.Errorf("pre-hook '%s': onlyIfSwitched and onlyIfChanged apply to post-hooks only", name)
+		}
+		for _, service := range slices.Concat(hook.OnlyIfSwitched, hook.OnlyIfChanged) {
+			if _, exists := services[service]; !exists {
+				return fmt.Errorf("%s '%s' condition names unknown service '%s'", hookType, name, service)
+			}
+		}
+	}
+
+	for i, hook := range hooks {
+		for _, after := range hook.RunAfter {
+			if !names[after] {
+				return fmt.Errorf("%s '%s' runs after unknown hook '%s'", hookType, hookName(hook, hookType, i), after)
+			}
+		}
+	}
+
+	_, err := orderHooks(hooks, hookType)
+	return err
+}
+
+// orderHooks returns the indexes of hooks in the order they run. Hooks are
+// grouped into levels by the dependency resolver, RunAfter being their
+// dependencies: first the hooks that run after none, then those that run
+// after only hooks of the first level, and so on. Within a level, hooks run
+// in the order they are declared.
+func orderHooks(hooks []Hook, hookType string) ([]int, error) {
+	index := make(map[string]int, len(hooks))
+	nodes := make(map[string]ServiceConfig, len(hooks))
+	var edges []string
+	for i, hook := range hooks {
+		name := hookName(hook, hookType, i)
+		index[name] = i
+		nodes[name] = ServiceConfig{}
+		for _, after := range hook.RunAfter {
+			edges = append(edges, after+" -> "+name)
+		}
+	}
+
+	groups, err := NewDependencyResolver(nodes, edges).GetParallelGroups()
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s runAfter: %w", hookType, err)
+	}
+
+	order := make([]int, 0, len(hooks))
+	for _, group := range groups {
+		level := make([]int, len(group.Services))
+		for i, name := range group.Services {
+			level[i] = index[name]
+		}
+		sort.Ints(level)
+		order = append(order, level...)
+	}
+	return order, nil
+}
+
+// hookConditions are the outcomes of a switch that post-hook conditions
+// are evaluated against.
+type hookConditions struct {
+	switched map[string]bool
+	changed  map[string]bool
+}
+
+// newHookConditions returns the conditions after the switch of env to
+// result, given the states the services were in before it. A switched
+// service has changed if any field its configuration sets differs from its
+// previous state; a service whose state cannot be compared is taken to
+// have changed.
+func newHookConditions(env *Environment, result *SwitchResult, previousStates map[string]interface{}) *hookConditions {
+	c := &hookConditions{
+		switched: make(map[string]bool, len(result.SwitchedServices)),
+		changed:  make(map[string]bool, len(result.SwitchedServices)),
+	}
+	for _, service := range result.SwitchedServices {
+		c.switched[service] = true
+		previous := serviceConfigFromState(previousStates[service])
+		if len(diffServiceConfig(service, previous, env.Services[service], true)) > 0 {
+			c.changed[service] = true
+		}
+	}
+	return c
+}
+
+// skipReason returns why hook does not run under c, or "" if it does.
+func (c *hookConditions) skipReason(hook Hook) string {
+	if c == nil {
+		return ""
+	}
+	if len(hook.OnlyIfSwitched) > 0 && !anyService(hook.OnlyIfSwitched, c.switched) {
+		return noneOf(hook.OnlyIfSwitched, "switched")
+	}
+	if len(hook.OnlyIfChanged) > 0 && !anyService(hook.OnlyIfChanged, c.changed) {
+		return noneOf(hook.OnlyIfChanged, "changed")
+	}
+	return ""
+}
+
+// anyService reports whether any of services is in set.
+func anyService(services []string, set map[string]bool) bool {
+	for _, service := range services {
+		if set[service] {
+			return true
+		}
+	}
+	return false
+}
+
+// noneOf describes services none of which were verb.
+func noneOf(services []string, verb string) string {
+	if len(services) == 1 {
+		return fmt.Sprintf("%s was not %s", services[0], verb)
+	}
+	return fmt.Sprintf("none of %s was %s", strings.Join(services, ", "), verb)
+}
+
+// executeHooks executes pre or post hooks in RunAfter order. A hook is
+// skipped if conditions, nil for pre-hooks, are not met, or if a hook it
+// runs after failed or was skipped for a failure; a hook skipped for its
+// conditions does not hold back the hooks after it. A hook failure stops
+// the remaining hooks unless the hook's OnError is "continue", in which
+// case the failure is added to result's Errors, marked Continued, and a
+// *continuedHooksError naming such hooks is returned once all have run.
+// Skipped hooks are recorded in result with the reason.
+func (es *EnvironmentSwitcher) executeHooks(ctx context.Context, hooks []Hook, hookType string, conditions *hookConditions, result *SwitchResult) error {
+	order, err := orderHooks(hooks, hookType)
+	if err != nil {
+		return err
+	}
+
+	skip := func(label, reason string) {
+		result.SkippedHooks = append(result.SkippedHooks, SkippedHook{Hook: label, Reason: reason})
+	}
+	// failed maps the hooks that failed or were skipped for a failure to
+	// what happened to them.
+	failed := make(map[string]string)
+	var continued []string
+
+	for n, i := range order {
+		hook := hooks[i]
+		name := hookName(hook, hookType, i)
+		label := hookLabel(hook, name)
+
+		if reason := blockedReason(hook, failed); reason != "" {
+			failed[name] = "was skipped"
+			skip(label, reason)
+			continue
+		}
+		if reason := conditions.skipReason(hook); reason != "" {
+			skip(label, reason)
+			continue
+		}
+
+		if err := es.executeHook(ctx, hook, name); err != nil {
+			if hook.OnError == "continue" {
+				failed[name] = "failed"
+				continued = append(continued, label)
+				result.Errors = append(result.Errors, SwitchError{
+					Service:   hookType,
+					Error:     err.Error(),
+					Time:      time.Now(),
+					Continued: true,
+				})
+				continue
+			}
+			for _, j := range order[n+1:] {
+				skip(hookLabel(hooks[j], hookName(hooks[j], hookType, j)), fmt.Sprintf("hook '%s' failed", label))
+			}
+			return fmt.Errorf("hook execution failed: %w", err)
+		}
+	}
+
+	if len(continued) > 0 {
+		return &continuedHooksError{hooks: continued}
+	}
+	return nil
+}
+
+// blockedReason returns why hook cannot run after the hooks in failed, or
+// "" if none of the hooks it runs after is there.
+func blockedReason(hook Hook, failed map[string]string) string {
+	for _, after := range hook.RunAfter {
+		if what, ok := failed[after]; ok {
+			return fmt.Sprintf("runs after hook '%s', which %s", after, what)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,365 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// simulateHooks simulates a switch to env, with "false" hooks failing, and
+// returns the hooks run and the result.
+func simulateHooks(t *testing.T, es *EnvironmentSwitcher, env *Environment) ([]string, *SwitchResult) {
+	t.Helper()
+	return simulateHooksWith(t, es, env, SwitchOptions{})
+}
+
+// simulateHooksWith is simulateHooks with switch options.
+func simulateHooksWith(t *testing.T, es *EnvironmentSwitcher, env *Environment, options SwitchOptions) ([]string, *SwitchResult) {
+	t.Helper()
+	opts := SimulateOptions{SwitchOptions: options, Config: SimulatorConfig{CommandResponses: map[string]SimulatedResponse{
+		"sh -c false": {Err: errors.New("exit status 1")},
+	}}}
+	report, err := es.Simulate(context.Background(), env, opts)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.Result == nil {
+		t.Fatalf("Simulate() = %+v, want a result", report)
+	}
+	return commands(report), report.Result
+}
+
+// TestEnvironmentSwitcher_HookOrder tests running hooks after the hooks
+// they name in runAfter, and otherwise in declaration order.
+func TestEnvironmentSwitcher_HookOrder(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("docker"))
+
+	env := &Environment{
+		Name:     "dev",
+		Services: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}},
+		PostHooks: []Hook{
+			{Name: "notify", Command: "echo notify", RunAfter: []string{"reload", "warm"}},
+			{Name: "reload", Command: "echo reload"},
+			{Command: "echo unnamed"},
+			{Name: "warm", Command: "echo warm", RunAfter: []string{"reload"}},
+		},
+	}
+
+	got, result := simulateHooks(t, es, env)
+	want := []string{"sh -c echo reload", "sh -c echo unnamed", "sh -c echo warm", "sh -c echo notify"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hooks run = %v, want %v", got, want)
+	}
+	if !result.Success || len(result.SkippedHooks) != 0 {
+		t.Errorf("result = %+v, want success with no skipped hooks", result)
+	}
+}
+
+// TestEnvironmentSwitcher_HookConditions tests skipping post-hooks whose
+// services were not switched or not changed.
+func TestEnvironmentSwitcher_HookConditions(t *testing.T) {
+	disabled := false
+	es := newTestSwitcher()
+	es.Register(&stateSwitcher{name: "kubernetes", current: &KubernetesConfig{Context: "prod"}})
+	es.Register(&stateSwitcher{name: "docker", current: &DockerConfig{Context: "default"}})
+	es.Register(&stateSwitcher{name: "aws"})
+
+	env := &Environment{
+		Name: "prod",
+		Services: map[string]ServiceConfig{
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod"}},
+			"docker":     {Docker: &DockerConfig{Context: "prod"}},
+			"aws":        {Enabled: &disabled, AWS: &AWSConfig{Profile: "prod"}},
+		},
+		PostHooks: []Hook{
+			{Name: "kubeconfig", Command: "echo kubeconfig", OnlyIfChanged: []string{"kubernetes"}},
+			{Name: "either", Command: "echo either", OnlyIfChanged: []string{"kubernetes", "docker"}},
+			{Name: "switched", Command: "echo switched", OnlyIfSwitched: []string{"kubernetes"}},
+			{Name: "sso", Command: "echo sso", OnlyIfSwitched: []string{"aws"}},
+			{Name: "after", Command: "echo after", RunAfter: []string{"kubeconfig"}},
+		},
+	}
+
+	got, result := simulateHooks(t, es, env)
+	want := []string{"sh -c echo either", "sh -c echo switched", "sh -c echo after"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("hooks run = %v, want %v", got, want)
+	}
+	wantSkipped := []SkippedHook{
+		{Hook: "kubeconfig", Reason: "kubernetes was not changed"},
+		{Hook: "sso", Reason: "aws was not switched"},
+	}
+	if !reflect.DeepEqual(result.SkippedHooks, wantSkipped) {
+		t.Errorf("SkippedHooks = %+v, want %+v", result.SkippedHooks, wantSkipped)
+	}
+}
+
+// TestEnvironmentSwitcher_HookOnError tests skipping the hooks after a
+// failed hook.
+func TestEnvironmentSwitcher_HookOnError(t *testing.T) {
+	services := map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}}
+
+	t.Run("continue", func(t *testing.T) {
+		es := newTestSwitcher()
+		es.Register(newMockSwitcher("docker"))
+		env := &Environment{
+			Name:     "dev",
+			Services: services,
+			PostHooks: []Hook{
+				{Name: "a", Command: "false", OnError: "continue"},
+				{Name: "b", Command: "echo b", RunAfter: []string{"a"}},
+				{Name: "c", Command: "echo c", RunAfter: []string{"b"}},
+				{Name: "d", Command: "echo d"},
+			},
+		}
+
+		got, result := simulateHooks(t, es, env)
+		if want := []string{"sh -c false", "sh -c echo d"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("hooks run = %v, want %v", got, want)
+		}
+		wantSkipped := []SkippedHook{
+			{Hook: "b", Reason: "runs after hook 'a', which failed"},
+			{Hook: "c", Reason: "runs after hook 'b', which was skipped"},
+		}
+		if !reflect.DeepEqual(result.SkippedHooks, wantSkipped) {
+			t.Errorf("SkippedHooks = %+v, want %+v", result.SkippedHooks, wantSkipped)
+		}
+		if !result.Success || len(result.Errors) != 1 {
+			t.Fatalf("result = %+v, want success with one continued error", result)
+		}
+		if err := result.Errors[0]; !err.Continued || err.Service != "post-hook" || !strings.Contains(err.Error, "hook 'a' failed") {
+			t.Errorf("Errors[0] = %+v, want hook 'a' continued", err)
+		}
+	})
+
+	t.Run("fail", func(t *testing.T) {
+		docker := newMockSwitcher("docker")
+		es := newTestSwitcher()
+		es.Register(docker)
+		env := &Environment{
+			Name:     "dev",
+			Services: services,
+			PreHooks: []Hook{
+				{Name: "check", Command: "false"},
+				{Name: "login", Command: "echo login"},
+			},
+		}
+
+		got, result := simulateHooks(t, es, env)
+		if want := []string{"sh -c false"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("hooks run = %v, want %v", got, want)
+		}
+		if want := []SkippedHook{{Hook: "login", Reason: "hook 'check' failed"}}; !reflect.DeepEqual(result.SkippedHooks, want) {
+			t.Errorf("SkippedHooks = %+v, want %+v", result.SkippedHooks, want)
+		}
+		if result.Success || docker.switchCalled {
+			t.Errorf("result = %+v, want the switch aborted before docker", result)
+		}
+	})
+}
+
+// TestEnvironmentSwitcher_HookOnError_Mixed tests labeling failed hooks
+// and recording every continued failure across pre and post hooks with
+// mixed OnError behaviors.
+func TestEnvironmentSwitcher_HookOnError_Mixed(t *testing.T) {
+	services := map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}}
+	env := &Environment{
+		Name:     "dev",
+		Services: services,
+		PreHooks: []Hook{
+			{Command: "false", OnError: "continue"},
+			{Name: "login", Command: "echo login"},
+		},
+		PostHooks: []Hook{
+			{Name: "lint", Command: "false", OnError: "continue"},
+			{Command: "echo reload", RunAfter: []string{"lint"}},
+			{Command: "false", OnError: "continue"},
+			{Name: "notify", Command: "echo notify"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		options     SwitchOptions
+		wantSuccess bool
+	}{
+		{name: "default", wantSuccess: true},
+		{name: "strict", options: SwitchOptions{StrictHooks: true}, wantSuccess: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docker := newMockSwitcher("docker")
+			es := newTestSwitcher()
+			es.Register(docker)
+
+			got, result := simulateHooksWith(t, es, env, tt.options)
+			want := []string{"sh -c false", "sh -c echo login", "sh -c false", "sh -c false", "sh -c echo notify"}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("hooks run = %v, want %v", got, want)
+			}
+			if !docker.switchCalled {
+				t.Error("docker was not switched, want the switch to go on past continued pre-hooks")
+			}
+			wantSkipped := []SkippedHook{{Hook: "post-hook-1 (echo)", Reason: "runs after hook 'lint', which failed"}}
+			if !reflect.DeepEqual(result.SkippedHooks, wantSkipped) {
+				t.Errorf("SkippedHooks = %+v, want %+v", result.SkippedHooks, wantSkipped)
+			}
+
+			wantErrors := []struct{ service, hook string }{
+				{"pre-hook", "hook 'pre-hook-0 (false)' failed"},
+				{"post-hook", "hook 'lint' failed"},
+				{"post-hook", "hook 'post-hook-2 (false)' failed"},
+			}
+			if len(result.Errors) != len(wantErrors) {
+				t.Fatalf("Errors = %+v, want %d continued errors", result.Errors, len(wantErrors))
+			}
+			for i, want := range wantErrors {
+				err := result.Errors[i]
+				if !err.Continued || err.Service != want.service || !strings.Contains(err.Error, want.hook) {
+					t.Errorf("Errors[%d] = %+v, want %s %q continued", i, err, want.service, want.hook)
+				}
+			}
+			if result.Success != tt.wantSuccess {
+				t.Errorf("Success = %v, want %v", result.Success, tt.wantSuccess)
+			}
+		})
+	}
+
+	t.Run("continue then fail", func(t *testing.T) {
+		docker := newMockSwitcher("docker")
+		es := newTestSwitcher()
+		es.Register(docker)
+		env := &Environment{
+			Name:     "dev",
+			Services: services,
+			PreHooks: []Hook{
+				{Name: "warm", Command: "false", OnError: "continue"},
+				{Command: "false"},
+				{Command: "/usr/bin/make login"},
+			},
+		}
+
+		_, result := simulateHooks(t, es, env)
+		if result.Success || docker.switchCalled {
+			t.Errorf("result = %+v, want the switch aborted before docker", result)
+		}
+		wantSkipped := []SkippedHook{{Hook: "pre-hook-2 (make)", Reason: "hook 'pre-hook-1 (false)' failed"}}
+		if !reflect.DeepEqual(result.SkippedHooks, wantSkipped) {
+			t.Errorf("SkippedHooks = %+v, want %+v", result.SkippedHooks, wantSkipped)
+		}
+		if len(result.Errors) != 2 || !result.Errors[0].Continued || result.Errors[1].Continued {
+			t.Errorf("Errors = %+v, want the continued failure of warm followed by the abort", result.Errors)
+		}
+	})
+}
+
+// TestHookLabel tests naming hooks in errors and results.
+func TestHookLabel(t *testing.T) {
+	tests := []struct {
+		hook Hook
+		name string
+		want string
+	}{
+		{Hook{Name: "reload", Command: "make reload"}, "reload", "reload"},
+		{Hook{Command: "make reload"}, "post-hook-0", "post-hook-0 (make)"},
+		{Hook{Command: "  /usr/local/bin/kubectl config view"}, "pre-hook-3", "pre-hook-3 (kubectl)"},
+		{Hook{}, "pre-hook-0", "pre-hook-0"},
+		{Hook{Command: "AWS_PROFILE=prod KUBECONFIG=/tmp/kube ./deploy.sh"}, "post-hook-1", "post-hook-1 (deploy.sh)"},
+		{Hook{Command: "cd infra && TF_LOG=info terraform apply"}, "post-hook-2", "post-hook-2 (terraform)"},
+		{Hook{Command: "cd infra; make plan"}, "pre-hook-1", "pre-hook-1 (make)"},
+		{Hook{Command: "cd infra"}, "pre-hook-2", "pre-hook-2 (cd)"},
+		{Hook{Command: "DEBUG=1"}, "pre-hook-4", "pre-hook-4"},
+	}
+
+	for _, tt := range tests {
+		if got := hookLabel(tt.hook, tt.name); got != tt.want {
+			t.Errorf("hookLabel(%+v, %q) = %q, want %q", tt.hook, tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestEnvironment_Validate_Hooks tests rejecting invalid hook names,
+// references, conditions, and runAfter cycles.
+func TestEnvironment_Validate_Hooks(t *testing.T) {
+	tests := []struct {
+		name      string
+		preHooks  []Hook
+		postHooks []Hook
+		wantErr   string
+	}{
+		{
+			name:      "valid",
+			preHooks:  []Hook{{Name: "a", Command: "echo a"}, {Command: "echo b", RunAfter: []string{"a"}}},
+			postHooks: []Hook{{Command: "echo c", RunAfter: []string{"post-hook-1"}, OnlyIfChanged: []string{"docker"}}, {Command: "echo d"}},
+		},
+		{
+			name:      "cycle",
+			postHooks: []Hook{{Name: "a", Command: "echo a", RunAfter: []string{"b"}}, {Name: "b", Command: "echo b", RunAfter: []string{"a"}}},
+			wantErr:   "circular dependency",
+		},
+		{
+			name:     "self",
+			preHooks: []Hook{{Name: "a", Command: "echo a", RunAfter: []string{"a"}}},
+			wantErr:  "circular dependency",
+		},
+		{
+			name:      "unknown hook",
+			postHooks: []Hook{{Name: "a", Command: "echo a", RunAfter: []string{"b"}}},
+			wantErr:   "post-hook 'a' runs after unknown hook 'b'",
+		},
+		{
+			name:      "other list",
+			preHooks:  []Hook{{Name: "a", Command: "echo a"}},
+			postHooks: []Hook{{Name: "b", Command: "echo b", RunAfter: []string{"a"}}},
+			wantErr:   "runs after unknown hook 'a'",
+		},
+		{
+			name:      "duplicate",
+			postHooks: []Hook{{Command: "echo a"}, {Name: "post-hook-0", Command: "echo b"}},
+			wantErr:   "duplicate post-hook name 'post-hook-0'",
+		},
+		{
+			name:      "invalid name",
+			postHooks: []Hook{{Name: "a -> b", Command: "echo a"}},
+			wantErr:   "invalid post-hook name",
+		},
+		{
+			name:     "pre-hook condition",
+			preHooks: []Hook{{Name: "a", Command: "echo a", OnlyIfSwitched: []string{"docker"}}},
+			wantErr:  "apply to post-hooks only",
+		},
+		{
+			name:      "unknown service",
+			postHooks: []Hook{{Name: "a", Command: "echo a", OnlyIfChanged: []string{"aws"}}},
+			wantErr:   "condition names unknown service 'aws'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{
+				Name:      "dev",
+				Services:  map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}},
+				PreHooks:  tt.preHooks,
+				PostHooks: tt.postHooks,
+			}
+			err := env.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
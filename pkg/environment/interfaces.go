@@ -24,3 +24,69 @@ type ServiceSwitcher interface {
 	// Rollback restores the service to a previous state.
 	Rollback(ctx context.Context, previousState interface{}) error
 }
+
+// Built-in resource claims declared by this module's switchers.
+const (
+	// ClaimKubeconfig is held by switchers that modify the kubeconfig:
+	// kubernetes, and aws because EKS contexts reference AWS profiles. It is
+	// the default kubeconfig; KubeconfigClaims honors KUBECONFIG.
+	ClaimKubeconfig = "file:~/.kube/config"
+	// ClaimAWSConfig is held by the aws switcher.
+	ClaimAWSConfig = "file:~/.aws/config"
+	// ClaimGCloudConfig is held by the gcp switcher.
+	ClaimGCloudConfig = "file:~/.config/gcloud"
+	// ClaimGCloudADC is held by the gcp switcher, which logs application
+	// default credentials in for impersonation chains. Unlike the gcloud
+	// directory, it is a file, so it is snapshotted.
+	ClaimGCloudADC = "file:~/.config/gcloud/application_default_credentials.json"
+	// ClaimAzureConfig is held by the azure switcher.
+	ClaimAzureConfig = "file:~/.azure"
+	// ClaimDockerConfig is held by the docker switcher.
+	ClaimDockerConfig = "file:~/.docker/config.json"
+	// ClaimSSHConfig is held by the ssh switcher.
+	ClaimSSHConfig = "file:~/.ssh/config"
+	// ClaimGitConfig is held by the gpg switcher, which sets the signing
+	// identity in the global git configuration.
+	ClaimGitConfig = "file:~/.gitconfig"
+	// ClaimNpmrc is held by the pkgmgr switcher, which sets npm registries.
+	ClaimNpmrc = "file:~/.npmrc"
+	// ClaimGoEnv is held by the pkgmgr switcher, which runs `go env -w`.
+	// Go's environment file lives under the OS configuration directory, so
+	// it is not a "file:" claim and is not snapshotted.
+	ClaimGoEnv = "go:env"
+)
+
+// ResourceClaimer is an optional interface for switchers that modify shared
+// resources, such as configuration files. Two switchers holding the same
+// claim never run concurrently; within a parallel group they are switched
+// one after another. The files named by "file:" claims are also the ones
+// copied by FileSnapshots before a switch.
+type ResourceClaimer interface {
+	// Claims returns the resources the switcher modifies, e.g.
+	// "file:~/.kube/config".
+	Claims() []string
+}
+
+// TargetLister is an optional interface for switchers that can enumerate
+// the targets they switch between, such as AWS profiles or kube contexts.
+type TargetLister interface {
+	// ListTargets returns the available targets. It may be slow, since it
+	// typically shells out to the service's CLI.
+	ListTargets(ctx context.Context) ([]string, error)
+}
+
+// ConfigValidator is an optional interface for switchers that can check a
+// configuration without running their CLI, so mistakes are caught by
+// switch-all --check before anything is switched.
+type ConfigValidator interface {
+	// Validate returns an error if config cannot be switched to. It must
+	// not change or query the service.
+	Validate(config interface{}) error
+}
+
+// CLIRequirer is an optional interface for switchers that run external
+// tools, so their availability can be checked before a switch.
+type CLIRequirer interface {
+	// RequiredCLIs returns the executables switching to config runs.
+	RequiredCLIs(config interface{}) []string
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// environmentExtensions are the file extensions recognized as environment files.
+var environmentExtensions = []string{".yaml", ".yml"}
+
+// environmentFile is an environment file discovered in a search path.
+type environmentFile struct {
+	path string
+	stem string
+	env  *Environment
+}
+
+// FindEnvironmentFile resolves an environment name to a file in searchPaths.
+//
+// Resolution happens in order of decreasing precision:
+//  1. a file named exactly <name>.yaml or <name>.yml, in search path order;
+//  2. a file whose base name or environment name matches, ignoring case;
+//  3. a file whose environment lists name among its aliases, ignoring case.
+//
+// The first step that matches wins, so a real name always takes priority over
+// another environment's alias. If a step matches more than one file, an error
+// listing the conflicting files is returned.
+func FindEnvironmentFile(searchPaths []string, name string) (string, error) {
+	for _, dir := range searchPaths {
+		for _, ext := range environmentExtensions {
+			filename := filepath.Join(dir, name+ext)
+			if _, err := os.Stat(filename); err == nil {
+				return filename, nil
+			}
+		}
+	}
+
+	files := scanEnvironmentFiles(searchPaths)
+
+	var nameMatches, aliasMatches []string
+	for _, f := range files {
+		switch {
+		case strings.EqualFold(f.stem, name) || strings.EqualFold(f.env.Name, name):
+			nameMatches = append(nameMatches, f.path)
+		case f.env.HasAlias(name):
+			aliasMatches = append(aliasMatches, f.path)
+		}
+	}
+
+	for _, matches := range [][]string{nameMatches, aliasMatches} {
+		switch len(matches) {
+		case 0:
+			continue
+		case 1:
+			return matches[0], nil
+		default:
+			return "", fmt.Errorf("environment '%s' is ambiguous, matched by: %s", name, strings.Join(matches, ", "))
+		}
+	}
+
+	return "", fmt.Errorf("environment '%s' not found", name)
+}
+
+// ListEnvironmentNames returns the sorted, de-duplicated environment names
+// and aliases found in searchPaths, suitable for shell completion.
+func ListEnvironmentNames(searchPaths []string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	for _, f := range scanEnvironmentFiles(searchPaths) {
+		add(f.stem)
+		add(f.env.Name)
+		for _, alias := range f.env.Aliases {
+			add(alias)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// scanEnvironmentFiles loads every parseable environment file in searchPaths.
+// Unreadable directories and files that are not environments are skipped.
+func scanEnvironmentFiles(searchPaths []string) []environmentFile {
+	var files []environmentFile
+	seen := make(map[string]bool)
+
+	for _, dir := range searchPaths {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			ext := filepath.Ext(entry.Name())
+			if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if abs, err := filepath.Abs(path); err == nil {
+				if seen[abs] {
+					continue
+				}
+				seen[abs] = true
+			}
+
+			env, err := LoadEnvironmentFromFile(path)
+			if err != nil {
+				continue
+			}
+
+			files = append(files, environmentFile{
+				path: path,
+				stem: strings.TrimSuffix(entry.Name(), ext),
+				env:  env,
+			})
+		}
+	}
+
+	return files
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeEnvFile writes a minimal environment file and returns its path.
+func writeEnvFile(t *testing.T, dir, filename, name string, aliases ...string) string {
+	t.Helper()
+
+	var sb strings.Builder
+	sb.WriteString("name: " + name + "\n")
+	if len(aliases) > 0 {
+		sb.WriteString("aliases: [" + strings.Join(aliases, ", ") + "]\n")
+	}
+	sb.WriteString("services:\n  aws:\n    aws:\n      profile: default\n")
+
+	path := filepath.Join(dir, filename)
+	if err := os.WriteFile(path, []byte(sb.String()), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+	return path
+}
+
+// TestFindEnvironmentFile tests exact, case-insensitive, and alias resolution.
+func TestFindEnvironmentFile(t *testing.T) {
+	dir := t.TempDir()
+	production := writeEnvFile(t, dir, "production.yaml", "production", "prod", "prd")
+	staging := writeEnvFile(t, dir, "staging.yml", "staging", "stg")
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "exact file name", query: "production", want: production},
+		{name: "yml extension", query: "staging", want: staging},
+		{name: "case-insensitive name", query: "Production", want: production},
+		{name: "alias", query: "prod", want: production},
+		{name: "case-insensitive alias", query: "PRD", want: production},
+		{name: "second environment alias", query: "stg", want: staging},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FindEnvironmentFile([]string{dir}, tt.query)
+			if err != nil {
+				t.Fatalf("FindEnvironmentFile(%q) error = %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("FindEnvironmentFile(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFindEnvironmentFile_NotFound tests the error for unknown names.
+func TestFindEnvironmentFile_NotFound(t *testing.T) {
+	dir := t.TempDir()
+	writeEnvFile(t, dir, "dev.yaml", "dev")
+
+	_, err := FindEnvironmentFile([]string{dir, filepath.Join(dir, "missing")}, "qa")
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("FindEnvironmentFile() error = %v, want not found error", err)
+	}
+}
+
+// TestFindEnvironmentFile_NamePriorityOverAlias tests that a real name wins
+// over another environment's alias.
+func TestFindEnvironmentFile_NamePriorityOverAlias(t *testing.T) {
+	dir := t.TempDir()
+	prod := writeEnvFile(t, dir, "Prod.yaml", "prod")
+	writeEnvFile(t, dir, "production.yaml", "production", "prod")
+
+	got, err := FindEnvironmentFile([]string{dir}, "PROD")
+	if err != nil {
+		t.Fatalf("FindEnvironmentFile() error = %v", err)
+	}
+	if got != prod {
+		t.Errorf("FindEnvironmentFile() = %q, want %q", got, prod)
+	}
+}
+
+// TestFindEnvironmentFile_AmbiguousAlias tests that an alias shared by two
+// environments is reported with both files.
+func TestFindEnvironmentFile_AmbiguousAlias(t *testing.T) {
+	dir := t.TempDir()
+	first := writeEnvFile(t, dir, "production.yaml", "production", "live")
+	second := writeEnvFile(t, dir, "production-eu.yaml", "production-eu", "live")
+
+	_, err := FindEnvironmentFile([]string{dir}, "live")
+	if err == nil {
+		t.Fatal("FindEnvironmentFile() should fail for an ambiguous alias")
+	}
+	for _, path := range []string{first, second} {
+		if !strings.Contains(err.Error(), path) {
+			t.Errorf("error %q should list %s", err, path)
+		}
+	}
+}
+
+// TestFindEnvironmentFile_AmbiguousAcrossPaths tests case-insensitive collisions
+// between search paths.
+func TestFindEnvironmentFile_AmbiguousAcrossPaths(t *testing.T) {
+	home := t.TempDir()
+	local := t.TempDir()
+	writeEnvFile(t, home, "Staging.yaml", "Staging")
+	writeEnvFile(t, local, "STAGING.yaml", "STAGING")
+
+	_, err := FindEnvironmentFile([]string{home, local}, "staging")
+	if err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("FindEnvironmentFile() error = %v, want ambiguity error", err)
+	}
+}
+
+// TestListEnvironmentNames tests that completion candidates include aliases.
+func TestListEnvironmentNames(t *testing.T) {
+	dir := t.TempDir()
+	writeEnvFile(t, dir, "production.yaml", "production", "prod", "prd")
+	writeEnvFile(t, dir, "dev.yaml", "dev")
+	if err := os.WriteFile(filepath.Join(dir, "notes.yaml"), []byte("- not an environment\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got := ListEnvironmentNames([]string{dir, dir})
+	want := []string{"dev", "prd", "prod", "production"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("ListEnvironmentNames() = %v, want %v", got, want)
+	}
+}
+
+// TestEnvironment_HasAlias tests case-insensitive alias matching.
+func TestEnvironment_HasAlias(t *testing.T) {
+	env := &Environment{Name: "production", Aliases: []string{"prod", "prd"}}
+
+	if !env.HasAlias("PROD") {
+		t.Error("HasAlias(PROD) = false, want true")
+	}
+	if env.HasAlias("production") {
+		t.Error("HasAlias(production) = true, want false")
+	}
+}
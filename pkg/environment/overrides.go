@@ -0,0 +1,293 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Override sets one field of an environment, such as
+// services.kubernetes.kubernetes.namespace=pr-123, so a shared environment
+// file can be reused with small changes.
+//
+// Path is a dotted path of the field's YAML keys. Map keys, such as
+// service names, and list indexes are path segments too; a segment
+// containing dots is written in double quotes, as in
+// services."db.internal".extra.host. Value is parsed for the type of the
+// field: durations such as 10m, booleans, numbers, and comma-separated
+// lists.
+type Override struct {
+	Path  string
+	Value string
+}
+
+// String returns the override as path=value, the form ParseOverride
+// accepts.
+func (o Override) String() string {
+	return o.Path + "=" + o.Value
+}
+
+// ParseOverride parses a path=value override. The path ends at the first
+// "=" outside double quotes.
+func ParseOverride(s string) (Override, error) {
+	quoted := false
+	for i, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == '=' && !quoted:
+			if i == 0 {
+				return Override{}, fmt.Errorf("invalid override %q: empty path", s)
+			}
+			return Override{Path: s[:i], Value: s[i+1:]}, nil
+		}
+	}
+	return Override{}, fmt.Errorf("invalid override %q: expected path=value", s)
+}
+
+// ParseOverrides parses path=value overrides, returning an error that
+// lists every invalid one.
+func ParseOverrides(args []string) ([]Override, error) {
+	overrides := make([]Override, 0, len(args))
+	var problems []string
+	for _, arg := range args {
+		override, err := ParseOverride(arg)
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		overrides = append(overrides, override)
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("invalid overrides:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return overrides, nil
+}
+
+// ApplyOverrides sets the fields of e the overrides name, in order, and
+// returns an error listing every override that could not be applied. The
+// valid overrides are applied even then.
+func (e *Environment) ApplyOverrides(overrides []Override) error {
+	var problems []string
+	for _, override := range overrides {
+		if err := e.Set(override.Path, override.Value); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid overrides:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// Set sets the field of e at path to value, as described for Override.
+// Missing map entries and configuration blocks on the way are created, so
+// a service can be added or configured by an override alone.
+func (e *Environment) Set(path, value string) error {
+	segments, err := splitPath(path)
+	if err != nil {
+		return err
+	}
+	if err := setPath(reflect.ValueOf(e).Elem(), segments, "", value); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// splitPath splits an override path into segments at the dots outside
+// double quotes, removing the quotes.
+func splitPath(path string) ([]string, error) {
+	var (
+		segments []string
+		segment  strings.Builder
+		quoted   bool
+		// wasQuoted allows an empty segment written as "".
+		wasQuoted bool
+	)
+	for _, r := range path {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			wasQuoted = true
+		case r == '.' && !quoted:
+			if segment.Len() == 0 && !wasQuoted {
+				return nil, fmt.Errorf("invalid path %q: empty segment", path)
+			}
+			segments = append(segments, segment.String())
+			segment.Reset()
+			wasQuoted = false
+		default:
+			segment.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("invalid path %q: unterminated quote", path)
+	}
+	if segment.Len() == 0 && !wasQuoted {
+		return nil, fmt.Errorf("invalid path %q: empty segment", path)
+	}
+	return append(segments, segment.String()), nil
+}
+
+// setPath sets the value v's field at segments to value. at is the path
+// of v, for errors.
+func setPath(v reflect.Value, segments []string, at, value string) error {
+	if len(segments) == 0 {
+		return setValue(v, at, value)
+	}
+	segment := segments[0]
+	next := joinPath(at, segment)
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setPath(v.Elem(), segments, at, value)
+	case reflect.Struct:
+		field, err := yamlField(v, segment, at)
+		if err != nil {
+			return err
+		}
+		return setPath(field, segments[1:], next, value)
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		key := reflect.ValueOf(segment).Convert(v.Type().Key())
+		// Map entries cannot be set in place, so the entry is copied,
+		// set, and stored back.
+		entry := reflect.New(v.Type().Elem()).Elem()
+		if existing := v.MapIndex(key); existing.IsValid() {
+			entry.Set(existing)
+		}
+		if err := setPath(entry, segments[1:], next, value); err != nil {
+			return err
+		}
+		v.SetMapIndex(key, entry)
+		return nil
+	case reflect.Slice:
+		index, err := strconv.Atoi(segment)
+		if err != nil || index < 0 {
+			return fmt.Errorf("'%s' is not an index of the list %s", segment, describePath(at))
+		}
+		if index >= v.Len() {
+			return fmt.Errorf("index %d is out of range of the list %s, which has %d entries", index, describePath(at), v.Len())
+		}
+		return setPath(v.Index(index), segments[1:], next, value)
+	default:
+		return fmt.Errorf("%s is a %s value and has no field '%s'", describePath(at), v.Type(), segment)
+	}
+}
+
+// yamlField returns the field of the struct v whose YAML key is name.
+func yamlField(v reflect.Value, name, at string) (reflect.Value, error) {
+	t := v.Type()
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := strings.Split(t.Field(i).Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" {
+			continue
+		}
+		if key == name {
+			return v.Field(i), nil
+		}
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	if suggestion := closest(name, keys); suggestion != "" {
+		return reflect.Value{}, fmt.Errorf("unknown field '%s' in %s (did you mean '%s'?)", name, describePath(at), suggestion)
+	}
+	return reflect.Value{}, fmt.Errorf("unknown field '%s' in %s", name, describePath(at))
+}
+
+// durationType is the type of time.Duration fields, which are parsed as
+// durations rather than integers.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setValue parses value for the type of v and sets v to it.
+func setValue(v reflect.Value, at, value string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid duration '%s'", value)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean '%s'", value)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer '%s'", value)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer '%s'", value)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(value, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number '%s'", value)
+		}
+		v.SetFloat(f)
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if err := setValue(elem.Elem(), at, value); err != nil {
+			return err
+		}
+		v.Set(elem)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s is a list of %s; set the fields of one entry instead", describePath(at), v.Type().Elem())
+		}
+		list := reflect.MakeSlice(v.Type(), 0, 0)
+		if value != "" {
+			for _, item := range strings.Split(value, ",") {
+				list = reflect.Append(list, reflect.ValueOf(strings.TrimSpace(item)).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(list)
+	default:
+		return fmt.Errorf("%s is not a single value; set one of its fields instead", describePath(at))
+	}
+	return nil
+}
+
+// joinPath appends segment to the path at, quoting it if it contains a dot.
+func joinPath(at, segment string) string {
+	if strings.Contains(segment, ".") {
+		segment = `"` + segment + `"`
+	}
+	if at == "" {
+		return segment
+	}
+	return at + "." + segment
+}
+
+// describePath names the field at path for errors.
+func describePath(path string) string {
+	if path == "" {
+		return "the environment"
+	}
+	return "'" + path + "'"
+}
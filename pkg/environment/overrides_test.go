@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// overrideEnv returns an environment for override tests.
+func overrideEnv() *Environment {
+	return &Environment{
+		Name: "production",
+		Services: map[string]ServiceConfig{
+			"kubernetes":  {Kubernetes: &KubernetesConfig{Context: "prod", Namespace: "default"}},
+			"aws":         {AWS: &AWSConfig{Profile: "prod", Region: "us-east-1"}},
+			"db.internal": {Extra: ExtraConfig{"host": "db1", "tls.mode": "strict"}},
+		},
+		Dependencies: []string{"aws -> kubernetes"},
+		PreHooks:     []Hook{{Command: "echo pre", Timeout: time.Second}},
+	}
+}
+
+// TestParseOverride tests splitting path=value overrides.
+func TestParseOverride(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    Override
+		wantErr string
+	}{
+		{"timeout=10m", Override{Path: "timeout", Value: "10m"}, ""},
+		{"description=", Override{Path: "description", Value: ""}, ""},
+		{"activate=export A=B", Override{Path: "activate", Value: "export A=B"}, ""},
+		{`services."a=b".extra.x=1`, Override{Path: `services."a=b".extra.x`, Value: "1"}, ""},
+		{"timeout", Override{}, "expected path=value"},
+		{"=10m", Override{}, "empty path"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := ParseOverride(tt.arg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ParseOverride() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParseOverride() = %+v, %v, want %+v", got, err, tt.want)
+			}
+			if got.String() != tt.arg {
+				t.Errorf("String() = %q, want %q", got.String(), tt.arg)
+			}
+		})
+	}
+
+	if _, err := ParseOverrides([]string{"a=1", "b", "=c"}); err == nil ||
+		!strings.Contains(err.Error(), `"b"`) || !strings.Contains(err.Error(), `"=c"`) {
+		t.Errorf("ParseOverrides() error = %v, want both invalid overrides listed", err)
+	}
+}
+
+// TestSplitPath tests splitting override paths, with quoted segments.
+func TestSplitPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    []string
+		wantErr string
+	}{
+		{"timeout", []string{"timeout"}, ""},
+		{"services.kubernetes.kubernetes.namespace", []string{"services", "kubernetes", "kubernetes", "namespace"}, ""},
+		{`services."db.internal".extra.host`, []string{"services", "db.internal", "extra", "host"}, ""},
+		{`services.db.extra."tls.mode"`, []string{"services", "db", "extra", "tls.mode"}, ""},
+		{`services."".extra`, []string{"services", "", "extra"}, ""},
+		{"preHooks.0.command", []string{"preHooks", "0", "command"}, ""},
+		{"services..aws", nil, "empty segment"},
+		{"services.", nil, "empty segment"},
+		{".services", nil, "empty segment"},
+		{`services."db.internal`, nil, "unterminated quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := splitPath(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("splitPath() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitPath() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// TestEnvironment_Set tests setting fields of each type by path.
+func TestEnvironment_Set(t *testing.T) {
+	tests := []struct {
+		path  string
+		value string
+		check func(env *Environment) bool
+	}{
+		{"services.kubernetes.kubernetes.namespace", "pr-123", func(env *Environment) bool {
+			k := env.Services["kubernetes"].Kubernetes
+			return k.Namespace == "pr-123" && k.Context == "prod"
+		}},
+		{"description", "per-PR", func(env *Environment) bool { return env.Description == "per-PR" }},
+		{"timeout", "10m", func(env *Environment) bool { return env.Timeout == 10*time.Minute }},
+		{"preHooks.0.timeout", "1m30s", func(env *Environment) bool { return env.PreHooks[0].Timeout == 90*time.Second }},
+		{"preHooks.0.command", "echo override", func(env *Environment) bool { return env.PreHooks[0].Command == "echo override" }},
+		{"services.aws.enabled", "false", func(env *Environment) bool {
+			enabled := env.Services["aws"].Enabled
+			return enabled != nil && !*enabled && env.Services["aws"].AWS.Profile == "prod"
+		}},
+		{"services.ssh.ssh.startAgent", "true", func(env *Environment) bool {
+			ssh := env.Services["ssh"].SSH
+			return ssh != nil && ssh.StartAgent
+		}},
+		{"aliases", "prod, live", func(env *Environment) bool { return reflect.DeepEqual(env.Aliases, []string{"prod", "live"}) }},
+		{"dependencies", "", func(env *Environment) bool { return len(env.Dependencies) == 0 }},
+		{`services."db.internal".extra.host`, "db2", func(env *Environment) bool {
+			extra := env.Services["db.internal"].Extra
+			return extra["host"] == "db2" && extra["tls.mode"] == "strict"
+		}},
+		{`services."db.internal".extra."tls.mode"`, "off", func(env *Environment) bool {
+			return env.Services["db.internal"].Extra["tls.mode"] == "off"
+		}},
+		{`services.vault.extra."role.name"`, "ci", func(env *Environment) bool {
+			return env.Services["vault"].Extra["role.name"] == "ci"
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			env := overrideEnv()
+			if err := env.Set(tt.path, tt.value); err != nil {
+				t.Fatalf("Set(%q, %q) error = %v", tt.path, tt.value, err)
+			}
+			if !tt.check(env) {
+				t.Errorf("Set(%q, %q) = %+v", tt.path, tt.value, env)
+			}
+		})
+	}
+}
+
+// TestEnvironment_Set_Errors tests rejecting invalid paths and values.
+func TestEnvironment_Set_Errors(t *testing.T) {
+	tests := []struct {
+		path    string
+		value   string
+		wantErr string
+	}{
+		{"services.kubernetes.kubernetes.namespce", "x", "unknown field 'namespce' in 'services.kubernetes.kubernetes' (did you mean 'namespace'?)"},
+		{"nmae", "x", "unknown field 'nmae' in the environment (did you mean 'name'?)"},
+		{"zzz", "x", "unknown field 'zzz' in the environment"},
+		{`services."db.internal".bogus`, "x", `unknown field 'bogus' in 'services."db.internal"'`},
+		{"timeout", "soon", "invalid duration 'soon'"},
+		{"services.aws.enabled", "maybe", "invalid boolean 'maybe'"},
+		{"preHooks.1.command", "x", "index 1 is out of range of the list 'preHooks', which has 1 entries"},
+		{"preHooks.first.command", "x", "'first' is not an index of the list 'preHooks'"},
+		{"preHooks", "x", "'preHooks' is a list of environment.Hook"},
+		{"services.kubernetes.kubernetes", "x", "'services.kubernetes.kubernetes' is not a single value"},
+		{"services", "x", "'services' is not a single value"},
+		{"name.first", "x", "'name' is a string value and has no field 'first'"},
+		{"services..aws", "x", "empty segment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := overrideEnv().Set(tt.path, tt.value)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Set(%q, %q) error = %v, want %q", tt.path, tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestEnvironment_ApplyOverrides tests applying overrides in order and
+// listing every invalid one.
+func TestEnvironment_ApplyOverrides(t *testing.T) {
+	env := overrideEnv()
+	err := env.ApplyOverrides([]Override{
+		{Path: "services.kubernetes.kubernetes.namespace", Value: "pr-1"},
+		{Path: "services.kubernetes.kubernetes.namespace", Value: "pr-2"},
+		{Path: "timeout", Value: "5m"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyOverrides() error = %v", err)
+	}
+	if ns := env.Services["kubernetes"].Kubernetes.Namespace; ns != "pr-2" || env.Timeout != 5*time.Minute {
+		t.Errorf("namespace = %q, timeout = %v, want the last override and 5m", ns, env.Timeout)
+	}
+
+	err = env.ApplyOverrides([]Override{
+		{Path: "timout", Value: "5m"},
+		{Path: "description", Value: "kept"},
+		{Path: "timeout", Value: "later"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "timout: unknown field") || !strings.Contains(err.Error(), "timeout: invalid duration") {
+		t.Errorf("ApplyOverrides() error = %v, want both invalid paths listed", err)
+	}
+	if env.Description != "kept" {
+		t.Errorf("Description = %q, want the valid override applied", env.Description)
+	}
+}
+
+// TestSwitchEnvironment_RecordsOverrides tests that the overrides of a
+// switch are recorded in the history.
+func TestSwitchEnvironment_RecordsOverrides(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es := newTestSwitcher()
+	es.geteuid = func() int { return 1000 }
+	es.Register(newMockSwitcher("kubernetes"))
+	es.SetHistory(history)
+
+	env := &Environment{
+		Name:     "production",
+		Services: map[string]ServiceConfig{"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod"}}},
+	}
+	overrides := []string{"services.kubernetes.kubernetes.namespace=pr-123"}
+	parsed, err := ParseOverrides(overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.ApplyOverrides(parsed); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{Overrides: overrides}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	last, err := history.Last()
+	if err != nil || last == nil || !reflect.DeepEqual(last.Overrides, overrides) {
+		t.Errorf("Last() = %+v, %v, want overrides %v", last, err, overrides)
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/recovery"
+)
+
+// PanicError reports a service switcher that panicked. The switch treats it
+// like any other failure of that service, so a buggy switcher cannot end
+// the process halfway through a switch without rolling back.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace, starting at the frame that panicked.
+	Stack string
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("switcher panicked: %v", e.Value)
+}
+
+// safeSwitch calls switcher.Switch, converting a panic into a
+// *PanicError.
+func safeSwitch(ctx context.Context, switcher ServiceSwitcher, config interface{}) (err error) {
+	defer recovery.Recover("service switcher", switcher.Name(), &err, newPanicError)
+	return switcher.Switch(withCommandService(ctx, switcher.Name()), config)
+}
+
+// safeGetCurrentState calls switcher.GetCurrentState, converting a panic
+// into a *PanicError.
+func safeGetCurrentState(ctx context.Context, switcher ServiceSwitcher) (state interface{}, err error) {
+	defer recovery.Recover("service switcher", switcher.Name(), &err, newPanicError)
+	return switcher.GetCurrentState(withCommandService(ctx, switcher.Name()))
+}
+
+// safeRollback calls switcher.Rollback, converting a panic into a
+// *PanicError.
+func safeRollback(ctx context.Context, switcher ServiceSwitcher, previousState interface{}) (err error) {
+	defer recovery.Recover("service switcher", switcher.Name(), &err, newPanicError)
+	return switcher.Rollback(withCommandService(ctx, switcher.Name()), previousState)
+}
+
+// newPanicError is the error recovery.Recover stores for a panic.
+func newPanicError(value interface{}, stack string) error {
+	return &PanicError{Value: value, Stack: stack}
+}
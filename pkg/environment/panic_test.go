@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+)
+
+// panickingSwitcher is a switcher whose Switch panics.
+type panickingSwitcher struct {
+	name           string
+	rollbackCalled bool
+}
+
+func (p *panickingSwitcher) Name() string { return p.name }
+
+func (p *panickingSwitcher) Switch(ctx context.Context, config interface{}) error {
+	panic("switch exploded")
+}
+
+func (p *panickingSwitcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	return map[string]string{"mock": "state"}, nil
+}
+
+func (p *panickingSwitcher) Rollback(ctx context.Context, previousState interface{}) error {
+	p.rollbackCalled = true
+	return nil
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_Panic tests that a panicking
+// switcher fails its service and still triggers rollback.
+func TestEnvironmentSwitcher_SwitchEnvironment_Panic(t *testing.T) {
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	es := newTestSwitcher()
+	plugin := &panickingSwitcher{name: "aws"}
+	es.Register(plugin)
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "test"}},
+		},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{RollbackOnError: true})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should return an error when a switcher panics")
+	}
+	if len(result.FailedServices) != 1 || result.FailedServices[0] != "aws" {
+		t.Errorf("FailedServices = %v, want [aws]", result.FailedServices)
+	}
+	if !result.RollbackPerformed || !plugin.rollbackCalled {
+		t.Errorf("RollbackPerformed = %v, rollback called = %v, want both true", result.RollbackPerformed, plugin.rollbackCalled)
+	}
+	if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Error, "switcher panicked: switch exploded") {
+		t.Errorf("Errors = %v, want the panic recorded", result.Errors)
+	}
+	if !strings.Contains(logs.String(), "service switcher aws panicked") {
+		t.Errorf("log = %q, want the panic logged", logs.String())
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PreflightCheck is the outcome of one of the checks made by Preflight.
+type PreflightCheck struct {
+	// Name identifies the check, such as "dependencies".
+	Name string `json:"name"`
+	// Problems are what the check found wrong; none if it passed.
+	Problems []string `json:"problems,omitempty"`
+}
+
+// Passed reports whether the check found no problems.
+func (c PreflightCheck) Passed() bool {
+	return len(c.Problems) == 0
+}
+
+// PreflightPassed reports whether every check passed, so the switch would
+// be accepted.
+func PreflightPassed(checks []PreflightCheck) bool {
+	for _, check := range checks {
+		if !check.Passed() {
+			return false
+		}
+	}
+	return true
+}
+
+// Preflight makes the checks that would reject a switch to env with
+// options before it changes anything, without fetching current state,
+// running hooks, or switching:
+//   - privileges: not running as root, unless options.AllowRoot
+//   - environment: Environment.Validate
+//   - values: the ValueValidator, unless options.SkipValidation
+//   - dependencies: dependency resolution
+//   - switchers: a switcher is registered for every enabled service
+//   - hooks: ValidateHooks
+//   - configurations: each service's configuration, checked by its
+//     switcher if it is a ConfigValidator
+//   - clis: the executables CLIRequirer switchers need are in PATH
+//
+// Every check runs even if an earlier one fails, so all problems are
+// reported at once.
+func (es *EnvironmentSwitcher) Preflight(env *Environment, options SwitchOptions) []PreflightCheck {
+	var privileges []string
+	if es.geteuid != nil && es.geteuid() == 0 && !options.AllowRoot {
+		privileges = []string{ErrRunningAsRoot.Error()}
+	}
+
+	var environment []string
+	if err := env.Validate(); err != nil {
+		environment = []string{err.Error()}
+	}
+
+	var values []string
+	if !options.SkipValidation {
+		var valueErrs ValueErrors
+		if err := es.values.Validate(env); errors.As(err, &valueErrs) {
+			for _, valueErr := range valueErrs {
+				values = append(values, valueErr.Error())
+			}
+		} else if err != nil {
+			values = []string{err.Error()}
+		}
+	}
+
+	var dependencies []string
+	if _, err := NewDependencyResolver(env.EnabledServices(), env.EnabledDependencies()).GetParallelGroups(); err != nil {
+		dependencies = []string{err.Error()}
+	}
+
+	var hooks []string
+	for _, err := range es.ValidateHooks(env) {
+		hooks = append(hooks, err.Error())
+	}
+
+	configurations, clis := es.serviceProblems(env)
+
+	return []PreflightCheck{
+		{Name: "privileges", Problems: privileges},
+		{Name: "environment", Problems: environment},
+		{Name: "values", Problems: values},
+		{Name: "dependencies", Problems: dependencies},
+		{Name: "switchers", Problems: es.missingSwitcherProblems(env)},
+		{Name: "hooks", Problems: hooks},
+		{Name: "configurations", Problems: configurations},
+		{Name: "clis", Problems: clis},
+	}
+}
+
+// missingSwitcherProblems describes the enabled services of env without a
+// registered switcher, suggesting a likely intended service.
+func (es *EnvironmentSwitcher) missingSwitcherProblems(env *Environment) []string {
+	var problems []string
+	for _, service := range es.MissingSwitchers(env) {
+		problem := fmt.Sprintf("no switcher for service '%s'", service)
+		if suggestion := es.SuggestService(service); suggestion != "" {
+			problem += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// serviceProblems checks the configuration of each enabled service of env
+// that has a switcher, and looks up the executables the switchers need.
+// It returns the configuration problems and the missing executables.
+func (es *EnvironmentSwitcher) serviceProblems(env *Environment) (configProblems, cliProblems []string) {
+	services := env.EnabledServices()
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	neededBy := make(map[string][]string)
+	for _, name := range names {
+		es.mu.RLock()
+		switcher, exists := es.serviceSwitchers[name]
+		es.mu.RUnlock()
+		if !exists {
+			continue
+		}
+
+		config, err := serviceConfigValue(name, services[name])
+		if err != nil {
+			configProblems = append(configProblems, err.Error())
+			continue
+		}
+		if validator, ok := switcher.(ConfigValidator); ok {
+			if err := validator.Validate(config); err != nil {
+				configProblems = append(configProblems, fmt.Sprintf("%s: %v", name, err))
+			}
+		}
+		if requirer, ok := switcher.(CLIRequirer); ok {
+			for _, cli := range requirer.RequiredCLIs(config) {
+				neededBy[cli] = append(neededBy[cli], name)
+			}
+		}
+	}
+
+	clis := make([]string, 0, len(neededBy))
+	for cli := range neededBy {
+		clis = append(clis, cli)
+	}
+	sort.Strings(clis)
+	for _, cli := range clis {
+		if _, err := es.lookPath(cli); err != nil {
+			cliProblems = append(cliProblems, fmt.Sprintf("%s, needed by %s, not found in PATH", cli, strings.Join(neededBy[cli], ", ")))
+		}
+	}
+	return configProblems, cliProblems
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// checkedSwitcher is a switcher that validates configurations and requires
+// CLIs, and records whether the service was queried or switched.
+type checkedSwitcher struct {
+	mockSwitcher
+	clis        []string
+	validateErr error
+	stateCalled bool
+}
+
+func (c *checkedSwitcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	c.stateCalled = true
+	return c.mockSwitcher.GetCurrentState(ctx)
+}
+
+func (c *checkedSwitcher) Validate(interface{}) error { return c.validateErr }
+
+func (c *checkedSwitcher) RequiredCLIs(interface{}) []string { return c.clis }
+
+// preflightEnv returns an environment of the kubernetes and docker services.
+func preflightEnv() *Environment {
+	return &Environment{
+		Name: "prod",
+		Services: map[string]ServiceConfig{
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod", Namespace: "team"}},
+			"docker":     {Docker: &DockerConfig{Context: "prod"}},
+		},
+		Dependencies: []string{"docker -> kubernetes"},
+		PostHooks:    []Hook{{Command: "echo done"}},
+	}
+}
+
+// TestEnvironmentSwitcher_Preflight tests that a valid environment passes
+// every check without the services being queried or switched.
+func TestEnvironmentSwitcher_Preflight(t *testing.T) {
+	kubernetes := &checkedSwitcher{mockSwitcher: *newMockSwitcher("kubernetes"), clis: []string{"kubectl"}}
+	docker := &checkedSwitcher{mockSwitcher: *newMockSwitcher("docker"), clis: []string{"docker"}}
+
+	es := newTestSwitcher()
+	es.geteuid = func() int { return 1000 }
+	es.lookPath = func(file string) (string, error) { return "/usr/bin/" + file, nil }
+	es.Register(kubernetes)
+	es.Register(docker)
+
+	checks := es.Preflight(preflightEnv(), SwitchOptions{})
+	if !PreflightPassed(checks) {
+		t.Errorf("Preflight() = %+v, want every check passed", checks)
+	}
+	if len(checks) != 8 {
+		t.Errorf("Preflight() made %d checks, want 8", len(checks))
+	}
+	for _, s := range []*checkedSwitcher{kubernetes, docker} {
+		if s.stateCalled || s.switchCalled {
+			t.Errorf("%s was queried or switched by Preflight()", s.name)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_Preflight_Failures tests the problems each check
+// reports.
+func TestEnvironmentSwitcher_Preflight_Failures(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(env *Environment)
+		root        bool
+		validateErr error
+		missingCLI  string
+		wantCheck   string
+		wantProblem string
+	}{
+		{
+			name:        "root",
+			root:        true,
+			wantCheck:   "privileges",
+			wantProblem: "refusing to switch environments as root",
+		},
+		{
+			name:        "invalid environment",
+			modify:      func(env *Environment) { env.PostHooks[0].RunAfter = []string{"missing"} },
+			wantCheck:   "environment",
+			wantProblem: "runs after unknown hook 'missing'",
+		},
+		{
+			name:        "invalid value",
+			modify:      func(env *Environment) { env.Services["kubernetes"].Kubernetes.Namespace = "Team_A" },
+			wantCheck:   "values",
+			wantProblem: "Team_A",
+		},
+		{
+			name:        "dependency cycle",
+			modify:      func(env *Environment) { env.Dependencies = append(env.Dependencies, "kubernetes -> docker") },
+			wantCheck:   "dependencies",
+			wantProblem: "circular dependency",
+		},
+		{
+			name: "unknown service",
+			modify: func(env *Environment) {
+				env.Services["kubernets"] = ServiceConfig{Kubernetes: &KubernetesConfig{Context: "prod"}}
+			},
+			wantCheck:   "switchers",
+			wantProblem: "no switcher for service 'kubernets' (did you mean 'kubernetes'?)",
+		},
+		{
+			name:        "rejected hook",
+			modify:      func(env *Environment) { env.PostHooks[0].Command = "echo done; rm -rf /" },
+			wantCheck:   "hooks",
+			wantProblem: "postHooks[0]",
+		},
+		{
+			name:        "invalid configuration",
+			validateErr: errors.New("context name too long"),
+			wantCheck:   "configurations",
+			wantProblem: "kubernetes: context name too long",
+		},
+		{
+			name:        "missing CLI",
+			missingCLI:  "kubectl",
+			wantCheck:   "clis",
+			wantProblem: "kubectl, needed by kubernetes, not found in PATH",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			es := newTestSwitcher()
+			es.geteuid = func() int { return 1000 }
+			if tt.root {
+				es.geteuid = func() int { return 0 }
+			}
+			es.lookPath = func(file string) (string, error) {
+				if file == tt.missingCLI {
+					return "", exec.ErrNotFound
+				}
+				return "/usr/bin/" + file, nil
+			}
+			es.Register(&checkedSwitcher{mockSwitcher: *newMockSwitcher("kubernetes"), clis: []string{"kubectl"}, validateErr: tt.validateErr})
+			es.Register(&checkedSwitcher{mockSwitcher: *newMockSwitcher("docker"), clis: []string{"docker"}})
+
+			env := preflightEnv()
+			if tt.modify != nil {
+				tt.modify(env)
+			}
+
+			checks := es.Preflight(env, SwitchOptions{})
+			if PreflightPassed(checks) {
+				t.Fatalf("Preflight() passed, want %s to fail", tt.wantCheck)
+			}
+			for _, check := range checks {
+				if check.Name != tt.wantCheck {
+					if !check.Passed() {
+						t.Errorf("check %s failed with %v, want only %s to fail", check.Name, check.Problems, tt.wantCheck)
+					}
+					continue
+				}
+				if !strings.Contains(strings.Join(check.Problems, "\n"), tt.wantProblem) {
+					t.Errorf("check %s problems = %v, want %q", check.Name, check.Problems, tt.wantProblem)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+)
+
+// DefaultRecentSwitchWindow is how recent another user's switch must be for
+// RecentSwitchByOther to report it, unless the settings say otherwise.
+const DefaultRecentSwitchWindow = 30 * time.Minute
+
+// CurrentUser returns the OS user recorded in the history. Under sudo it is
+// the user who ran sudo, since on a shared account that is the person to
+// ask.
+func CurrentUser() string {
+	if name := os.Getenv("SUDO_USER"); name != "" {
+		return name
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// RecentSwitchByOther reports whether last, the newest history entry, is a
+// switch made by a user other than currentUser less than window before now,
+// so switching away could stomp on their work. The history is per account,
+// so the other users are those sharing it, such as through sudo. Entries
+// recorded before the history kept users, and a window that is not
+// positive, never match. A switch timestamped after now, from clock skew,
+// counts as recent.
+func RecentSwitchByOther(last *HistoryEntry, currentUser string, window time.Duration, now time.Time) bool {
+	if last == nil || last.User == "" || last.User == currentUser || window <= 0 {
+		return false
+	}
+	return now.Sub(last.SwitchedAt) < window
+}
+
+// RecentSwitchWarning describes entry relative to now, such as
+// "prod was switched to by alice 12 minutes ago".
+func RecentSwitchWarning(entry *HistoryEntry, now time.Time) string {
+	return fmt.Sprintf("%s was switched to by %s %s", entry.Environment, entry.User, ago(now.Sub(entry.SwitchedAt)))
+}
+
+// ago renders d as a rough phrase, such as "just now" or "3 hours ago".
+func ago(d time.Duration) string {
+	plural := func(n int, unit string) string {
+		if n == 1 {
+			return fmt.Sprintf("1 %s ago", unit)
+		}
+		return fmt.Sprintf("%d %ss ago", n, unit)
+	}
+
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d.Minutes()), "minute")
+	case d < 24*time.Hour:
+		return plural(int(d.Hours()), "hour")
+	default:
+		return plural(int(d.Hours()/24), "day")
+	}
+}
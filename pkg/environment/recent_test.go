@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRecentSwitchByOther tests the user and time matrix for warning about
+// another user's recent switch.
+func TestRecentSwitchByOther(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	entry := func(user string, ago time.Duration) *HistoryEntry {
+		return &HistoryEntry{Environment: "prod", User: user, SwitchedAt: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name   string
+		last   *HistoryEntry
+		window time.Duration
+		want   bool
+	}{
+		{"other user within window", entry("alice", 12*time.Minute), 30 * time.Minute, true},
+		{"other user just now", entry("alice", 0), 30 * time.Minute, true},
+		{"other user outside window", entry("alice", 45*time.Minute), 30 * time.Minute, false},
+		{"other user exactly at window", entry("alice", 30*time.Minute), 30 * time.Minute, false},
+		{"other user in the future", entry("alice", -5*time.Minute), 30 * time.Minute, true},
+		{"same user within window", entry("bob", 1*time.Minute), 30 * time.Minute, false},
+		{"same user outside window", entry("bob", 2*time.Hour), 30 * time.Minute, false},
+		{"entry without user", entry("", 1*time.Minute), 30 * time.Minute, false},
+		{"no history", nil, 30 * time.Minute, false},
+		{"window disabled", entry("alice", 1*time.Minute), 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RecentSwitchByOther(tt.last, "bob", tt.window, now); got != tt.want {
+				t.Errorf("RecentSwitchByOther() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRecentSwitchByOther_MissingHistory tests that a history file that
+// does not exist yet never warns.
+func TestRecentSwitchByOther_MissingHistory(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	last, err := history.Last()
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if RecentSwitchByOther(last, "bob", DefaultRecentSwitchWindow, time.Now()) {
+		t.Error("RecentSwitchByOther() = true for a missing history, want false")
+	}
+}
+
+// TestRecentSwitchWarning tests naming the user and how long ago they switched.
+func TestRecentSwitchWarning(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		ago  time.Duration
+		want string
+	}{
+		{12*time.Minute + 40*time.Second, "prod was switched to by alice 12 minutes ago"},
+		{time.Minute, "prod was switched to by alice 1 minute ago"},
+		{20 * time.Second, "prod was switched to by alice just now"},
+		{-time.Minute, "prod was switched to by alice just now"},
+		{3 * time.Hour, "prod was switched to by alice 3 hours ago"},
+		{50 * time.Hour, "prod was switched to by alice 2 days ago"},
+	}
+
+	for _, tt := range tests {
+		entry := &HistoryEntry{Environment: "prod", User: "alice", SwitchedAt: now.Add(-tt.ago)}
+		if got := RecentSwitchWarning(entry, now); got != tt.want {
+			t.Errorf("RecentSwitchWarning(%v) = %q, want %q", tt.ago, got, tt.want)
+		}
+	}
+}
+
+// TestCurrentUser tests that the user behind sudo is preferred.
+func TestCurrentUser(t *testing.T) {
+	t.Setenv("SUDO_USER", "alice")
+	if got := CurrentUser(); got != "alice" {
+		t.Errorf("CurrentUser() = %q, want %q under sudo", got, "alice")
+	}
+
+	t.Setenv("SUDO_USER", "")
+	if got := CurrentUser(); got == "" {
+		t.Error("CurrentUser() = \"\", want the OS user")
+	}
+}
+
+// TestEnvironmentSwitcher_RecordsUser tests that switches are recorded with
+// the OS user who made them.
+func TestEnvironmentSwitcher_RecordsUser(t *testing.T) {
+	t.Setenv("SUDO_USER", "alice")
+
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es.SetHistory(history)
+
+	env := &Environment{Name: "prod", Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "prod"}}}}
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	last, err := history.Last()
+	if err != nil || last == nil || last.User != "alice" {
+		t.Errorf("history entry = %+v, %v, want User %q", last, err, "alice")
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// SwitchFailure is one failed step of a switch, prepared for display.
+type SwitchFailure struct {
+	Service string
+	Error   string
+	// Detail is the failing CLI's standard error, if captured.
+	Detail string
+	// Hint is the suggested fix, if the failure is a known problem.
+	Hint string
+	Time time.Time
+	// Continued marks a failure the switch went on past.
+	Continued bool
+}
+
+// Failures returns result's errors in the order they occurred, followed by
+// its rollback errors, each with the remediation hint for its error or CLI
+// output.
+func Failures(result *SwitchResult) []SwitchFailure {
+	if result == nil {
+		return nil
+	}
+
+	failures := make([]SwitchFailure, 0, len(result.Errors)+len(result.RollbackErrors))
+	for _, err := range slices.Concat(result.Errors, result.RollbackErrors) {
+		failures = append(failures, SwitchFailure{
+			Service:   err.Service,
+			Error:     err.Error,
+			Detail:    err.Detail,
+			Hint:      status.RemediationHint(err.Service, err.Error+"\n"+err.Detail),
+			Time:      err.Time,
+			Continued: err.Continued,
+		})
+	}
+	return failures
+}
+
+// MixedState reports whether a rollback was attempted but left some
+// services switched, so the machine is partly in the new environment and
+// partly in the previous one.
+func (r *SwitchResult) MixedState() bool {
+	return r != nil && r.RollbackPerformed && !r.RollbackComplete
+}
+
+// MixedStateServices splits env's enabled services after an incomplete
+// rollback: stranded are left switched, or partly switched, to env because
+// their rollback failed; previous are on the previous environment, either
+// rolled back or never switched. Both are sorted.
+func MixedStateServices(result *SwitchResult, env *Environment) (stranded, previous []string) {
+	failed := make(map[string]bool, len(result.RollbackErrors))
+	for _, err := range result.RollbackErrors {
+		if !failed[err.Service] {
+			failed[err.Service] = true
+			stranded = append(stranded, err.Service)
+		}
+	}
+	for service := range env.EnabledServices() {
+		if !failed[service] {
+			previous = append(previous, service)
+		}
+	}
+	sort.Strings(stranded)
+	sort.Strings(previous)
+	return stranded, previous
+}
+
+// Timestamp layouts for ResultReporter.SetTimestamps.
+const (
+	TimestampClock   = "15:04:05"
+	TimestampRFC3339 = time.RFC3339
+)
+
+// ParseTimestampLayout returns the layout for a timestamp format name:
+// "clock" (HH:MM:SS) or "rfc3339".
+func ParseTimestampLayout(format string) (string, error) {
+	switch strings.ToLower(format) {
+	case "clock":
+		return TimestampClock, nil
+	case "rfc3339":
+		return TimestampRFC3339, nil
+	default:
+		return "", fmt.Errorf("unsupported timestamp format: %s (supported: clock, rfc3339)", format)
+	}
+}
+
+// ResultReporter prints switch progress and results. The switch-all command
+// and the TUI both use it, so failures read the same wherever a switch runs.
+// It is safe for concurrent use, since parallel switches report service
+// events from several goroutines; each line is written whole.
+type ResultReporter struct {
+	mu sync.Mutex
+	w  io.Writer
+	// timestampLayout, if set, prefixes every line with the time.
+	timestampLayout string
+	now             func() time.Time
+}
+
+// NewResultReporter creates a reporter writing to w.
+func NewResultReporter(w io.Writer) *ResultReporter {
+	return &ResultReporter{w: w, now: time.Now}
+}
+
+// SetTimestamps prefixes every line printed from now on with the current
+// time in layout, e.g. TimestampClock, to correlate a long switch with
+// other logs. An empty layout turns timestamps off.
+func (r *ResultReporter) SetTimestamps(layout string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timestampLayout = layout
+}
+
+// printf prints a line, or a blank line and a line if format starts with a
+// newline, prefixing the line with the time if timestamps are on. The line
+// is formatted first and written in one call, so lines printed
+// concurrently do not interleave.
+func (r *ResultReporter) printf(format string, args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var line strings.Builder
+	if rest, ok := strings.CutPrefix(format, "\n"); ok {
+		line.WriteString("\n")
+		format = rest
+	}
+	if r.timestampLayout != "" {
+		fmt.Fprintf(&line, "[%s] ", r.now().Format(r.timestampLayout))
+	}
+	fmt.Fprintf(&line, format, args...)
+	_, _ = io.WriteString(r.w, line.String())
+}
+
+// ReportProgress prints a switch progress update.
+func (r *ResultReporter) ReportProgress(progress SwitchProgress) {
+	percentage := float64(progress.CompletedServices) / float64(progress.TotalServices) * 100
+	r.printf("⏳ Progress: %.1f%% (%d/%d) - %s\n",
+		percentage,
+		progress.CompletedServices,
+		progress.TotalServices,
+		progress.Status)
+
+	if progress.CurrentService != "" {
+		r.printf("   Current: %s\n", progress.CurrentService)
+	}
+}
+
+// ReportServiceEvent prints the start or end of a service switch.
+func (r *ResultReporter) ReportServiceEvent(event ServiceEvent) {
+	switch event.Type {
+	case ServiceStarted:
+		r.printf("   ▶ %s: switching...\n", event.Service)
+	case ServiceCompleted:
+		r.printf("   ✓ %s: done (%v)\n", event.Service, event.Duration.Round(time.Millisecond))
+	case ServiceFailed:
+		r.printf("   ✗ %s: failed (%v): %s\n", event.Service, event.Duration.Round(time.Millisecond), event.Error)
+	}
+}
+
+// Report prints a summary of result followed by its failures.
+func (r *ResultReporter) Report(result *SwitchResult) {
+	r.printf("\n📊 Switch Results:\n")
+	if result.SwitchID != "" {
+		r.printf("   Switch ID: %s\n", result.SwitchID)
+	}
+	if result.FileSnapshot != "" {
+		r.printf("   File snapshot: %s\n", result.FileSnapshot)
+	}
+	r.printf("   Duration: %v\n", result.Duration)
+	r.printf("   Success: %v\n", result.Success)
+	if result.Partial {
+		r.printf("   Partial: %v\n", result.Partial)
+	}
+
+	if len(result.SwitchedServices) > 0 {
+		r.printf("   ✅ Switched: %v\n", result.SwitchedServices)
+	}
+
+	if len(result.FailedServices) > 0 {
+		r.printf("   ❌ Failed: %v\n", result.FailedServices)
+	}
+
+	if len(result.SkippedServices) > 0 {
+		r.printf("   ⏭️  Skipped services:\n")
+		for _, skipped := range result.SkippedServices {
+			r.printf("      %s: %s\n", skipped.Service, skipped.Reason)
+		}
+	}
+
+	if len(result.SkippedHooks) > 0 {
+		r.printf("   ⏭️  Skipped hooks:\n")
+		for _, hook := range result.SkippedHooks {
+			r.printf("      %s: %s\n", hook.Hook, hook.Reason)
+		}
+	}
+
+	if result.RollbackPerformed {
+		if result.RollbackComplete {
+			r.printf("   🔄 Rollback: Performed\n")
+		} else {
+			r.printf("   🔄 Rollback: Incomplete (%d failed)\n", len(result.RollbackErrors))
+		}
+	}
+
+	if len(result.Errors) > 0 || len(result.RollbackErrors) > 0 {
+		r.printf("\n❌ Errors:\n")
+		r.ReportFailures(result)
+	}
+}
+
+// ReportFailures prints each failure of result with the CLI output it
+// captured and the suggested fix, if known.
+func (r *ResultReporter) ReportFailures(result *SwitchResult) {
+	for _, failure := range Failures(result) {
+		marker := ""
+		if failure.Continued {
+			marker = " (continued)"
+		}
+		r.printf("   [%s] %s: %s%s\n", failure.Time.Format("15:04:05"), failure.Service, failure.Error, marker)
+		if failure.Detail != "" {
+			r.printf("      output: %s\n", strings.ReplaceAll(failure.Detail, "\n", "\n              "))
+		}
+		if failure.Hint != "" {
+			r.printf("      hint:   %s\n", failure.Hint)
+		}
+	}
+}
+
+// ReportMixedState prints a prominent warning if result's rollback was
+// incomplete, listing which of env's services are left on env and which
+// are on the previous environment. It reports whether it printed anything.
+func (r *ResultReporter) ReportMixedState(result *SwitchResult, env *Environment) bool {
+	if !result.MixedState() {
+		return false
+	}
+
+	stranded, previous := MixedStateServices(result, env)
+	r.printf("\n⚠️  WARNING: rollback incomplete, services are in a mixed state\n")
+	r.printf("   On %s: %s\n", env.Name, strings.Join(stranded, ", "))
+	if len(previous) > 0 {
+		r.printf("   On the previous environment: %s\n", strings.Join(previous, ", "))
+	}
+	r.printf("   Fix the failures above, then switch again or roll the services back by hand\n")
+	return true
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestSwitchEnvironment_CapturesStderr tests that a failing CLI's stderr ends up in SwitchError.Detail.
+func TestSwitchEnvironment_CapturesStderr(t *testing.T) {
+	es := newTestSwitcher()
+	mock := newMockSwitcher("kubernetes")
+	mock.switchError = &CommandError{
+		Command: "kubectl config use-context prod",
+		Stderr:  "error: no context exists with the name: \"prod\"\n",
+		Err:     errors.New("exit status 1"),
+	}
+	es.Register(mock)
+
+	env := &Environment{
+		Name:     "prod",
+		Services: map[string]ServiceConfig{"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod"}}},
+	}
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should fail")
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("Errors = %+v, want one error", result.Errors)
+	}
+	if want := `error: no context exists with the name: "prod"`; result.Errors[0].Detail != want {
+		t.Errorf("Detail = %q, want %q", result.Errors[0].Detail, want)
+	}
+
+	var out bytes.Buffer
+	NewResultReporter(&out).ReportFailures(result)
+	for _, want := range []string{"kubernetes: exit status 1", `output: error: no context exists with the name: "prod"`, "hint:   Check the context name against `kubectl config get-contexts`."} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("ReportFailures() = %q, want it to contain %q", out.String(), want)
+		}
+	}
+}
+
+// TestResultReporter_Report tests the summary, that failures without output or hint print alone, and that continued failures are marked.
+func TestResultReporter_Report(t *testing.T) {
+	result := &SwitchResult{
+		Partial:          true,
+		SwitchedServices: []string{"aws"},
+		FailedServices:   []string{"gcp"},
+		Errors: []SwitchError{
+			{Service: "gcp", Error: "failed to set project"},
+			{Service: "post-hook", Error: "hook 'lint' failed", Continued: true},
+		},
+		SkippedHooks: []SkippedHook{{Hook: "reload", Reason: "gcp was not switched"}},
+	}
+
+	var out bytes.Buffer
+	NewResultReporter(&out).Report(result)
+
+	for _, want := range []string{"Partial: true", "✅ Switched: [aws]", "❌ Failed: [gcp]", "gcp: failed to set project\n", "post-hook: hook 'lint' failed (continued)", "reload: gcp was not switched"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Report() = %q, want it to contain %q", out.String(), want)
+		}
+	}
+	for _, unwanted := range []string{"output:", "hint:"} {
+		if strings.Contains(out.String(), unwanted) {
+			t.Errorf("Report() = %q, should not contain %q", out.String(), unwanted)
+		}
+	}
+}
+
+// TestCommandStderr tests reading stderr through wrapped errors.
+func TestCommandStderr(t *testing.T) {
+	cmdErr := &CommandError{Stderr: "  denied\n", Err: errors.New("exit status 1")}
+	wrapped := errors.Join(errors.New("failed to switch"), cmdErr)
+
+	if got := CommandStderr(wrapped); got != "denied" {
+		t.Errorf("CommandStderr() = %q, want %q", got, "denied")
+	}
+	if got := CommandStderr(errors.New("plain")); got != "" {
+		t.Errorf("CommandStderr() = %q, want empty", got)
+	}
+	if cmdErr.Error() != "exit status 1" {
+		t.Errorf("Error() = %q, want the underlying error", cmdErr.Error())
+	}
+}
+
+// TestResultReporter_Timestamps tests that every progress and result line
+// carries the time when timestamps are on, and none does otherwise.
+func TestResultReporter_Timestamps(t *testing.T) {
+	now := time.Date(2025, 6, 1, 9, 5, 7, 0, time.UTC)
+	tests := []struct {
+		name   string
+		layout string
+		prefix string
+	}{
+		{"off", "", ""},
+		{"clock", TimestampClock, "[09:05:07] "},
+		{"rfc3339", TimestampRFC3339, "[2025-06-01T09:05:07Z] "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			reporter := NewResultReporter(&buf)
+			reporter.now = func() time.Time { return now }
+			reporter.SetTimestamps(tt.layout)
+
+			reporter.ReportServiceEvent(ServiceEvent{Service: "aws", Type: ServiceStarted})
+			reporter.ReportServiceEvent(ServiceEvent{Service: "aws", Type: ServiceCompleted, Duration: time.Second})
+			reporter.ReportProgress(SwitchProgress{TotalServices: 2, CompletedServices: 1, Status: "Completed group 0"})
+			reporter.Report(&SwitchResult{Success: true, SwitchedServices: []string{"aws"}, Duration: time.Second})
+
+			lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			for _, line := range lines {
+				if line == "" {
+					continue
+				}
+				hasPrefix := strings.HasPrefix(line, "[")
+				if tt.prefix == "" && hasPrefix {
+					t.Errorf("line %q has a timestamp, want none", line)
+				}
+				if tt.prefix != "" && !strings.HasPrefix(line, tt.prefix) {
+					t.Errorf("line %q, want prefix %q", line, tt.prefix)
+				}
+			}
+			if !strings.Contains(buf.String(), "\n\n"+tt.prefix+"📊 Switch Results:") {
+				t.Errorf("output should keep the blank line before the results:\n%s", buf.String())
+			}
+		})
+	}
+}
+
+// TestParseTimestampLayout tests the timestamp format names.
+func TestParseTimestampLayout(t *testing.T) {
+	tests := []struct {
+		format  string
+		want    string
+		wantErr bool
+	}{
+		{"clock", TimestampClock, false},
+		{"RFC3339", TimestampRFC3339, false},
+		{"unix", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseTimestampLayout(tt.format)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTimestampLayout(%q) error = %v, wantErr %v", tt.format, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseTimestampLayout(%q) = %q, want %q", tt.format, got, tt.want)
+		}
+	}
+}
+
+// rollbackFailingSwitcher is a mock whose Rollback fails.
+type rollbackFailingSwitcher struct {
+	*mockSwitcher
+}
+
+func (m rollbackFailingSwitcher) Rollback(ctx context.Context, previousState interface{}) error {
+	return errors.New("profile no longer exists")
+}
+
+// TestSwitchEnvironment_RollbackIncomplete tests that a failed rollback is
+// recorded per service and leaves the result in a mixed state.
+func TestSwitchEnvironment_RollbackIncomplete(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(rollbackFailingSwitcher{newMockSwitcher("aws")})
+	es.Register(newMockSwitcher("docker"))
+	es.Register(newErrorMockSwitcher("kubernetes"))
+
+	env := &Environment{
+		Name: "production",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "prod"}},
+			"docker":     {Docker: &DockerConfig{Context: "prod"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod"}},
+			"ssh":        {SSH: &SSHConfig{Config: "prod"}},
+		},
+		Dependencies: []string{"aws -> kubernetes", "docker -> kubernetes", "kubernetes -> ssh"},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{RollbackOnError: true})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should fail")
+	}
+	if !result.RollbackPerformed || result.RollbackComplete || !result.MixedState() {
+		t.Errorf("RollbackPerformed = %v, RollbackComplete = %v, MixedState() = %v, want true, false, true",
+			result.RollbackPerformed, result.RollbackComplete, result.MixedState())
+	}
+	if got, want := strings.Join(result.RolledBackServices, ","), "docker,kubernetes"; got != want {
+		t.Errorf("RolledBackServices = %q, want %q", got, want)
+	}
+	if len(result.RollbackErrors) != 1 || result.RollbackErrors[0].Service != "aws" {
+		t.Fatalf("RollbackErrors = %+v, want one for aws", result.RollbackErrors)
+	}
+	if !strings.Contains(result.RollbackErrors[0].Error, "profile no longer exists") {
+		t.Errorf("RollbackErrors[0].Error = %q, want the rollback error", result.RollbackErrors[0].Error)
+	}
+
+	stranded, previous := MixedStateServices(result, env)
+	if got, want := strings.Join(stranded, ","), "aws"; got != want {
+		t.Errorf("MixedStateServices() stranded = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(previous, ","), "docker,kubernetes,ssh"; got != want {
+		t.Errorf("MixedStateServices() previous = %q, want %q", got, want)
+	}
+
+	var buf bytes.Buffer
+	reporter := NewResultReporter(&buf)
+	reporter.Report(result)
+	if !reporter.ReportMixedState(result, env) {
+		t.Error("ReportMixedState() = false, want true")
+	}
+	for _, want := range []string{
+		"Rollback: Incomplete (1 failed)",
+		"aws: rollback failed: profile no longer exists",
+		"WARNING: rollback incomplete",
+		"On production: aws",
+		"On the previous environment: docker, kubernetes, ssh",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("report = %q, want it to contain %q", buf.String(), want)
+		}
+	}
+}
+
+// writeRecorder records each Write call separately.
+type writeRecorder struct {
+	writes []string
+}
+
+func (w *writeRecorder) Write(p []byte) (int, error) {
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+// TestResultReporter_Concurrent tests that service events reported from
+// several goroutines, as in a parallel switch, are each written as one whole
+// line with its timestamp.
+func TestResultReporter_Concurrent(t *testing.T) {
+	w := &writeRecorder{}
+	reporter := NewResultReporter(w)
+	reporter.SetTimestamps(TimestampClock)
+
+	var wg sync.WaitGroup
+	for _, service := range []string{"aws", "gcp", "azure", "docker", "kubernetes", "ssh"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 20 {
+				reporter.ReportServiceEvent(ServiceEvent{Service: service, Type: ServiceStarted})
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(w.writes) != 120 {
+		t.Fatalf("got %d writes, want one per event", len(w.writes))
+	}
+	for _, write := range w.writes {
+		if !strings.HasPrefix(write, "[") || !strings.HasSuffix(write, ": switching...\n") || strings.Count(write, "\n") != 1 {
+			t.Errorf("write %q, want one timestamped line", write)
+		}
+	}
+}
+
+// TestResultReporter_ReportMixedState_Complete tests that a complete rollback prints no warning.
+func TestResultReporter_ReportMixedState_Complete(t *testing.T) {
+	result := &SwitchResult{RollbackPerformed: true, RollbackComplete: true, RolledBackServices: []string{"aws"}}
+	env := &Environment{Name: "production", Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "prod"}}}}
+
+	var buf bytes.Buffer
+	if NewResultReporter(&buf).ReportMixedState(result, env) || buf.Len() != 0 {
+		t.Errorf("ReportMixedState() printed %q, want nothing", buf.String())
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrNothingToRetry is returned by RetryFailed when the last recorded switch
+// has no failed services.
+var ErrNothingToRetry = errors.New("the last switch has no failed services to retry")
+
+// RetryFailed re-attempts only the services that failed in the last recorded
+// switch, which must have been to env. It requires SetHistory.
+//
+// Every failed service is attempted, as with PartialSuccess, and dependencies
+// among them are still honoured. Hooks and the activate and deactivate
+// scripts already ran with the original switch, so they are not run again.
+// Unless this is a dry run, the history entry is updated with the services
+// that still fail, the ones that now switched along with their previous
+// states and configurations, and the retry's audit record, so RetryFailed
+// can be repeated until none are left and the retried services can be rolled
+// back on their own. The current environment marker is written once none
+// fail.
+func (es *EnvironmentSwitcher) RetryFailed(ctx context.Context, env *Environment, options SwitchOptions) (*SwitchResult, error) {
+	if es.history == nil {
+		return nil, errors.New("retrying failed services requires switch history")
+	}
+
+	release, err := es.lockSwitch(options)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	last, err := es.history.Last()
+	if err != nil {
+		return nil, err
+	}
+	if last == nil || len(last.Failed) == 0 {
+		return nil, ErrNothingToRetry
+	}
+	if last.Environment != env.Name {
+		return nil, fmt.Errorf("the last switch was to '%s', not '%s'", last.Environment, env.Name)
+	}
+
+	retry, err := retryEnvironment(env, last.Failed)
+	if err != nil {
+		return nil, err
+	}
+	options.PartialSuccess = true
+	var previousStates map[string]interface{}
+	if !options.DryRun {
+		previousStates = es.retryStates(ctx, retry)
+	}
+
+	ctx, endTelemetry := es.startSwitchTelemetry(ctx, env.Name, options)
+	result, err := es.sandbox().switchEnvironment(ctx, retry, options)
+	endTelemetry(result, err)
+	es.recordOutcome(result, err)
+	if err != nil || options.DryRun {
+		return result, err
+	}
+
+	addError := func(service string, err error) {
+		result.Errors = append(result.Errors, SwitchError{
+			Service: service,
+			Error:   err.Error(),
+			Time:    time.Now(),
+		})
+	}
+	if err := es.history.UpdateLast(func(entry *HistoryEntry) {
+		entry.Failed = unswitched(result)
+		entry.addSwitched(result.SwitchedServices, previousStates, env)
+		entry.Retries = append(entry.Retries, *newAuditRecord(AuditRetry, result.SwitchID, options, result))
+	}); err != nil {
+		addError("history", err)
+	}
+	es.updateCurrent(env, result, addError)
+	return result, nil
+}
+
+// retryStates returns the current states of the services retry switches,
+// read before retrying them, since the sandbox the retry runs in keeps the
+// states it reads to itself. A state that cannot be read is left out, as
+// the switch would fail that service anyway.
+func (es *EnvironmentSwitcher) retryStates(ctx context.Context, retry *Environment) map[string]interface{} {
+	states := make(map[string]interface{})
+	for name := range retry.EnabledServices() {
+		es.mu.RLock()
+		switcher, exists := es.serviceSwitchers[name]
+		es.mu.RUnlock()
+		if !exists {
+			continue
+		}
+		if state, err := safeGetCurrentState(ctx, switcher); err == nil {
+			states[name] = state
+		}
+	}
+	return states
+}
+
+// addSwitched adds the services a retry switched to e's Services, with their
+// previousStates and their configurations in env, as activate records them
+// for the original switch. Configurations are not added to an entry that
+// predates SwitchedConfigs, which would otherwise look complete.
+func (e *HistoryEntry) addSwitched(switched []string, previousStates map[string]interface{}, env *Environment) {
+	services, states := switchedStates(switched, previousStates)
+	if len(services) == 0 {
+		return
+	}
+	legacy := e.SwitchedConfigs == nil && len(e.Services) > 0
+
+	for _, service := range services {
+		if !slices.Contains(e.Services, service) {
+			e.Services = append(e.Services, service)
+		}
+	}
+	sort.Strings(e.Services)
+	for service, state := range states {
+		if e.PreviousStates == nil {
+			e.PreviousStates = make(map[string]ServiceConfig)
+		}
+		e.PreviousStates[service] = state
+	}
+	if legacy {
+		return
+	}
+	for service, config := range switchedConfigs(services, env) {
+		if e.SwitchedConfigs == nil {
+			e.SwitchedConfigs = make(map[string]ServiceConfig)
+		}
+		e.SwitchedConfigs[service] = config
+	}
+}
+
+// retryEnvironment returns a copy of env that switches only the failed
+// services. The others are disabled rather than removed, so dependencies on
+// them are dropped the same way as for a disabled service.
+func retryEnvironment(env *Environment, failed []string) (*Environment, error) {
+	retry := *env
+	retry.PreHooks = nil
+	retry.PostHooks = nil
+	retry.Activate = ""
+	retry.Deactivate = ""
+	retry.Services = make(map[string]ServiceConfig, len(env.Services))
+
+	retrying := make(map[string]bool, len(failed))
+	for _, service := range failed {
+		retrying[service] = true
+	}
+
+	disabled := false
+	var missing []string
+	for service := range retrying {
+		if config, exists := env.Services[service]; !exists || !config.IsEnabled() {
+			missing = append(missing, service)
+		}
+	}
+	for name, config := range env.Services {
+		if !retrying[name] {
+			config.Enabled = &disabled
+		}
+		retry.Services[name] = config
+	}
+
+	if len(missing) == len(retrying) {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("failed services %v are no longer enabled in environment '%s'", missing, env.Name)
+	}
+	return &retry, nil
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// retryEnv returns an environment with three services, where aws is switched before docker.
+func retryEnv() *Environment {
+	return &Environment{
+		Name:         "dev",
+		Dependencies: []string{"aws -> docker"},
+		PostHooks:    []Hook{{Command: "echo done"}},
+		Services: map[string]ServiceConfig{
+			"aws":    {AWS: &AWSConfig{Profile: "dev"}},
+			"gcp":    {GCP: &GCPConfig{Project: "dev"}},
+			"docker": {Docker: &DockerConfig{Context: "dev"}},
+		},
+	}
+}
+
+// TestEnvironmentSwitcher_RetryFailed tests that only the services that failed are retried.
+func TestEnvironmentSwitcher_RetryFailed(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es := newTestSwitcher()
+	es.SetHistory(history)
+
+	mocks := map[string]*mockSwitcher{}
+	for _, name := range []string{"aws", "gcp", "docker"} {
+		mocks[name] = newMockSwitcher(name)
+		es.Register(mocks[name])
+	}
+	mocks["gcp"].switchError = errors.New("token expired")
+	mocks["docker"].switchError = errors.New("daemon not running")
+
+	env := retryEnv()
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{PartialSuccess: true})
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if !result.Partial {
+		t.Fatal("SwitchEnvironment() should be a partial switch")
+	}
+
+	last, err := history.Last()
+	if err != nil {
+		t.Fatalf("Last() error = %v", err)
+	}
+	if want := []string{"docker", "gcp"}; !reflect.DeepEqual(sortedCopy(last.Failed), want) {
+		t.Fatalf("history Failed = %v, want %v", last.Failed, want)
+	}
+
+	// Fix gcp only; docker keeps failing.
+	for _, mock := range mocks {
+		mock.switchCalled = false
+	}
+	mocks["gcp"].switchError = nil
+
+	result, err = es.RetryFailed(context.Background(), env, SwitchOptions{})
+	if err != nil {
+		t.Fatalf("RetryFailed() error = %v", err)
+	}
+	if mocks["aws"].switchCalled {
+		t.Error("RetryFailed() should not switch aws, which succeeded")
+	}
+	if !mocks["gcp"].switchCalled || !mocks["docker"].switchCalled {
+		t.Error("RetryFailed() should switch gcp and docker")
+	}
+	if want := []string{"gcp"}; !reflect.DeepEqual(result.SwitchedServices, want) {
+		t.Errorf("SwitchedServices = %v, want %v", result.SwitchedServices, want)
+	}
+
+	entries, err := history.Entries()
+	if err != nil {
+		t.Fatalf("Entries() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("history has %d entries, want the retry to update the existing one", len(entries))
+	}
+	if want := []string{"docker"}; !reflect.DeepEqual(entries[0].Failed, want) {
+		t.Errorf("history Failed = %v, want %v", entries[0].Failed, want)
+	}
+	if retries := entries[0].Retries; len(retries) != 1 || retries[0].Operation != AuditRetry || retries[0].SwitchID != result.SwitchID {
+		t.Errorf("history Retries = %+v, want the retry's audit record with ID %q", retries, result.SwitchID)
+	}
+
+	// Fix docker; the next retry clears the failed set.
+	mocks["docker"].switchError = nil
+	if _, err := es.RetryFailed(context.Background(), env, SwitchOptions{}); err != nil {
+		t.Fatalf("second RetryFailed() error = %v", err)
+	}
+	if _, err := es.RetryFailed(context.Background(), env, SwitchOptions{}); !errors.Is(err, ErrNothingToRetry) {
+		t.Errorf("third RetryFailed() error = %v, want ErrNothingToRetry", err)
+	}
+}
+
+// TestEnvironmentSwitcher_RetryFailed_Configuration tests that retries
+// validate values and hooks as configured on the switcher.
+func TestEnvironmentSwitcher_RetryFailed_Configuration(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es := newTestSwitcher()
+	es.SetHistory(history)
+	es.SetValueValidator(ValueValidator{AWSRegions: []string{"mars-north-1"}})
+	es.SetHookAllowlist([]string{"echo"})
+
+	aws := newMockSwitcher("aws")
+	aws.switchError = errors.New("token expired")
+	es.Register(aws)
+	es.Register(newMockSwitcher("docker"))
+
+	env := &Environment{
+		Name: "dev",
+		Services: map[string]ServiceConfig{
+			"aws":    {AWS: &AWSConfig{Profile: "dev", Region: "mars-north-1"}},
+			"docker": {Docker: &DockerConfig{Context: "dev"}},
+		},
+		PostHooks: []Hook{{Command: "echo done"}},
+	}
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{PartialSuccess: true}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	aws.switchError = nil
+	result, err := es.RetryFailed(context.Background(), env, SwitchOptions{})
+	if err != nil {
+		t.Fatalf("RetryFailed() error = %v, want the custom region accepted", err)
+	}
+	if !aws.switchCalled || !reflect.DeepEqual(result.SwitchedServices, []string{"aws"}) {
+		t.Errorf("RetryFailed() = %+v, want aws switched", result)
+	}
+
+	sandbox := es.sandbox()
+	if !reflect.DeepEqual(sandbox.hookAllowlist, []string{"echo"}) || !reflect.DeepEqual(sandbox.values, es.values) {
+		t.Errorf("sandbox allowlist = %v, values = %+v, want the switcher's", sandbox.hookAllowlist, sandbox.values)
+	}
+}
+
+// TestEnvironmentSwitcher_RetryFailed_Errors tests when there is nothing to retry against.
+func TestEnvironmentSwitcher_RetryFailed_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		history *HistoryEntry
+		env     string
+		wantErr string
+	}{
+		{name: "no history", wantErr: "nothing"},
+		{name: "nothing failed", history: &HistoryEntry{Environment: "dev"}, env: "dev", wantErr: "no failed services"},
+		{name: "other environment", history: &HistoryEntry{Environment: "prod", Failed: []string{"gcp"}}, env: "dev", wantErr: "the last switch was to 'prod'"},
+		{name: "service removed", history: &HistoryEntry{Environment: "dev", Failed: []string{"azure"}}, env: "dev", wantErr: "no longer enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+			if tt.history != nil {
+				if err := history.Record(*tt.history); err != nil {
+					t.Fatal(err)
+				}
+			}
+			es := newTestSwitcher()
+			es.SetHistory(history)
+
+			env := retryEnv()
+			env.Name = tt.env
+			_, err := es.RetryFailed(context.Background(), env, SwitchOptions{})
+			if err == nil {
+				t.Fatal("RetryFailed() should fail")
+			}
+			if tt.wantErr == "nothing" {
+				if !errors.Is(err, ErrNothingToRetry) {
+					t.Errorf("RetryFailed() error = %v, want ErrNothingToRetry", err)
+				}
+				return
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("RetryFailed() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// sortedCopy returns a sorted copy of s.
+func sortedCopy(s []string) []string {
+	sorted := append([]string(nil), s...)
+	sort.Strings(sorted)
+	return sorted
+}
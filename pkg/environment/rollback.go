@@ -0,0 +1,117 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// PreviousState returns the state service was in before the switch of e.
+// It is an error if the switch did not switch service, or recorded no
+// state for it: switches recorded before states were, and services whose
+// switchers keep a state of their own type.
+func (e *HistoryEntry) PreviousState(service string) (ServiceConfig, error) {
+	if !slices.Contains(e.Services, service) {
+		if len(e.Services) == 0 {
+			return ServiceConfig{}, fmt.Errorf("switch %s recorded no services; it predates per-service rollback", e.SwitchID)
+		}
+		return ServiceConfig{}, fmt.Errorf("service %s was not part of switch %s (switched: %v)", service, e.SwitchID, e.Services)
+	}
+	state, ok := e.PreviousStates[service]
+	if !ok {
+		return ServiceConfig{}, fmt.Errorf("switch %s recorded no previous state for %s", e.SwitchID, service)
+	}
+	return state, nil
+}
+
+// RollbackService restores service to the state it was in before the switch
+// of entry, as found by History.Find, with its switcher's Rollback, and
+// records the rollback as a HistoryPartialRollback entry, which it returns.
+// The other services are left as they are, so the current environment
+// marker is cleared. It requires SetHistory. Like a switch, it refuses to
+// run as root with ErrRunningAsRoot unless options.AllowRoot is set; the
+// other options only apply to the switch lock and the audit record.
+func (es *EnvironmentSwitcher) RollbackService(ctx context.Context, entry *HistoryEntry, service string, options SwitchOptions) (*HistoryEntry, error) {
+	if es.history == nil {
+		return nil, errors.New("rolling back a service requires switch history")
+	}
+	if es.geteuid != nil && es.geteuid() == 0 && !options.AllowRoot {
+		return nil, ErrRunningAsRoot
+	}
+
+	state, err := entry.PreviousState(service)
+	if err != nil {
+		return nil, err
+	}
+	config, err := serviceConfigValue(service, state)
+	if err != nil {
+		return nil, err
+	}
+
+	es.mu.RLock()
+	switcher, exists := es.serviceSwitchers[service]
+	es.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("no switcher registered for service: %s", service)
+	}
+
+	release, err := es.lockSwitch(options)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	rollback := &HistoryEntry{
+		SwitchID:    NewSwitchID(),
+		Type:        HistoryPartialRollback,
+		Environment: entry.Environment,
+		// The rest of the environment is still in place, so its
+		// deactivate script must still run when switching away.
+		Deactivate: entry.Deactivate,
+		User:       CurrentUser(),
+		Services:   []string{service},
+		RollbackOf: entry.SwitchID,
+	}
+	rollback.Audit = newAuditRecord(AuditRollback, rollback.SwitchID, options, nil)
+	if err := safeRollback(withSwitchID(ctx, rollback.SwitchID), switcher, config); err != nil {
+		return nil, fmt.Errorf("failed to roll back %s: %w", service, err)
+	}
+
+	rollback.SwitchedAt = time.Now()
+	if err := es.history.Record(*rollback); err != nil {
+		return rollback, err
+	}
+	if es.currentPath != "" {
+		if err := ClearCurrent(es.currentPath); err != nil {
+			return rollback, err
+		}
+	}
+	return rollback, nil
+}
+
+// switchedStates returns the switched services, sorted, and the previous
+// states of those whose state can be recorded in the history.
+func switchedStates(switched []string, previousStates map[string]interface{}) ([]string, map[string]ServiceConfig) {
+	if len(switched) == 0 {
+		return nil, nil
+	}
+	services := append([]string(nil), switched...)
+	sort.Strings(services)
+
+	states := make(map[string]ServiceConfig, len(services))
+	for _, service := range services {
+		if config := serviceConfigFromState(previousStates[service]); !config.IsEmpty() {
+			states[service] = config
+		}
+	}
+	if len(states) == 0 {
+		states = nil
+	}
+	return services, states
+}
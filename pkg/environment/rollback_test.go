@@ -0,0 +1,272 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stateSwitcher is a switcher whose state is the configuration it was last
+// switched to, like the built-in switchers.
+type stateSwitcher struct {
+	name    string
+	current interface{}
+	// switchErr, if set, fails switches, leaving current as it is.
+	switchErr error
+}
+
+func (s *stateSwitcher) Name() string { return s.name }
+
+func (s *stateSwitcher) Switch(_ context.Context, config interface{}) error {
+	if s.switchErr != nil {
+		return s.switchErr
+	}
+	s.current = config
+	return nil
+}
+
+func (s *stateSwitcher) GetCurrentState(context.Context) (interface{}, error) { return s.current, nil }
+
+func (s *stateSwitcher) Rollback(ctx context.Context, previousState interface{}) error {
+	return s.Switch(ctx, previousState)
+}
+
+// TestEnvironmentSwitcher_RollbackService tests rolling back one service of
+// a recorded switch.
+func TestEnvironmentSwitcher_RollbackService(t *testing.T) {
+	dir := t.TempDir()
+	kubernetes := &stateSwitcher{name: "kubernetes", current: &KubernetesConfig{Context: "dev", Namespace: "team"}}
+	docker := &stateSwitcher{name: "docker", current: &DockerConfig{Context: "default"}}
+
+	es := newTestSwitcher()
+	es.Register(kubernetes)
+	es.Register(docker)
+	history := NewHistory(filepath.Join(dir, "history.json"))
+	es.SetHistory(history)
+	currentPath := filepath.Join(dir, "current")
+	es.SetCurrentMarker(currentPath)
+
+	env := &Environment{
+		Name: "prod",
+		Services: map[string]ServiceConfig{
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "prod"}},
+			"docker":     {Docker: &DockerConfig{Context: "prod"}},
+		},
+		Deactivate: "echo bye",
+	}
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{SwitchID: "01SWITCH"})
+	if err != nil || !result.Success {
+		t.Fatalf("SwitchEnvironment() = %+v, %v", result, err)
+	}
+
+	entry, err := history.Find("")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if !reflect.DeepEqual(entry.Services, []string{"docker", "kubernetes"}) {
+		t.Errorf("Services = %v, want [docker kubernetes]", entry.Services)
+	}
+	if !reflect.DeepEqual(entry.SwitchedConfigs, env.Services) {
+		t.Errorf("SwitchedConfigs = %+v, want %+v", entry.SwitchedConfigs, env.Services)
+	}
+	state, err := entry.PreviousState("kubernetes")
+	if err != nil || state.Kubernetes == nil || *state.Kubernetes != (KubernetesConfig{Context: "dev", Namespace: "team"}) {
+		t.Fatalf("PreviousState(kubernetes) = %+v, %v, want dev/team", state.Kubernetes, err)
+	}
+
+	rollback, err := es.RollbackService(context.Background(), entry, "kubernetes", SwitchOptions{})
+	if err != nil {
+		t.Fatalf("RollbackService() error = %v", err)
+	}
+	if got := kubernetes.current.(*KubernetesConfig); *got != (KubernetesConfig{Context: "dev", Namespace: "team"}) {
+		t.Errorf("kubernetes = %+v after rollback, want dev/team", got)
+	}
+	if got := docker.current.(*DockerConfig); got.Context != "prod" {
+		t.Errorf("docker = %+v after rollback, want it left on prod", got)
+	}
+
+	last, err := history.Last()
+	if err != nil || last == nil {
+		t.Fatalf("Last() = %v, %v", last, err)
+	}
+	if last.Type != HistoryPartialRollback || last.RollbackOf != "01SWITCH" || last.SwitchID != rollback.SwitchID ||
+		last.Environment != "prod" || last.Deactivate != "echo bye" || !reflect.DeepEqual(last.Services, []string{"kubernetes"}) {
+		t.Errorf("last history entry = %+v, want the partial rollback of 01SWITCH", last)
+	}
+	if name, _ := ReadCurrent(currentPath); name != "" {
+		t.Errorf("current = %q after a partial rollback, want none", name)
+	}
+
+	// The rollback entry is not a switch, so the switch is still found.
+	if found, err := history.Find(""); err != nil || found.SwitchID != "01SWITCH" {
+		t.Errorf("Find() = %+v, %v after rollback, want 01SWITCH", found, err)
+	}
+}
+
+// TestHistoryEntry_PreviousState_Errors tests refusing to roll back services
+// a switch did not switch or recorded no state for.
+func TestHistoryEntry_PreviousState_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   HistoryEntry
+		service string
+		wantErr string
+	}{
+		{
+			name:    "not switched",
+			entry:   HistoryEntry{SwitchID: "01A", Services: []string{"aws"}, PreviousStates: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "dev"}}}},
+			service: "kubernetes",
+			wantErr: "service kubernetes was not part of switch 01A",
+		},
+		{
+			name:    "old entry",
+			entry:   HistoryEntry{SwitchID: "01B"},
+			service: "aws",
+			wantErr: "predates per-service rollback",
+		},
+		{
+			name:    "no state",
+			entry:   HistoryEntry{SwitchID: "01C", Services: []string{"mock"}},
+			service: "mock",
+			wantErr: "recorded no previous state for mock",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.entry.PreviousState(tt.service)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("PreviousState() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestHistory_Find tests finding switches by ID.
+func TestHistory_Find(t *testing.T) {
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	if _, err := history.Find(""); err == nil {
+		t.Error("Find() should fail on an empty history")
+	}
+
+	for _, entry := range []HistoryEntry{
+		{SwitchID: "01A", Environment: "dev"},
+		{SwitchID: "01B", Environment: "prod"},
+		{SwitchID: "01C", Environment: "prod", Type: HistoryPartialRollback, RollbackOf: "01B"},
+	} {
+		if err := history.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		switchID string
+		want     string
+		wantErr  bool
+	}{
+		{"", "01B", false},
+		{"01A", "01A", false},
+		{"01C", "", true},
+		{"01Z", "", true},
+	}
+	for _, tt := range tests {
+		got, err := history.Find(tt.switchID)
+		if (err != nil) != tt.wantErr || (err == nil && got.SwitchID != tt.want) {
+			t.Errorf("Find(%q) = %+v, %v, want %q", tt.switchID, got, err, tt.want)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_RollbackService_Retried tests rolling back a
+// service that failed in a partial switch and switched on the retry.
+func TestEnvironmentSwitcher_RollbackService_Retried(t *testing.T) {
+	aws := &stateSwitcher{name: "aws", current: &AWSConfig{Profile: "default"}}
+	gcp := &stateSwitcher{name: "gcp", current: &GCPConfig{Project: "sandbox"}, switchErr: errors.New("token expired")}
+
+	es := newTestSwitcher()
+	es.Register(aws)
+	es.Register(gcp)
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es.SetHistory(history)
+
+	env := &Environment{
+		Name: "dev",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "dev"}},
+			"gcp": {GCP: &GCPConfig{Project: "dev"}},
+		},
+	}
+	ctx := context.Background()
+	if _, err := es.SwitchEnvironment(ctx, env, SwitchOptions{SwitchID: "01SWITCH", PartialSuccess: true}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	gcp.switchErr = nil
+	if result, err := es.RetryFailed(ctx, env, SwitchOptions{}); err != nil || !reflect.DeepEqual(result.SwitchedServices, []string{"gcp"}) {
+		t.Fatalf("RetryFailed() = %+v, %v, want gcp switched", result, err)
+	}
+
+	entry, err := history.Find("01SWITCH")
+	if err != nil {
+		t.Fatalf("Find() error = %v", err)
+	}
+	if !reflect.DeepEqual(entry.Services, []string{"aws", "gcp"}) {
+		t.Errorf("Services = %v, want [aws gcp]", entry.Services)
+	}
+	if !reflect.DeepEqual(entry.SwitchedConfigs, env.Services) {
+		t.Errorf("SwitchedConfigs = %+v, want %+v", entry.SwitchedConfigs, env.Services)
+	}
+
+	if _, err := es.RollbackService(ctx, entry, "gcp", SwitchOptions{}); err != nil {
+		t.Fatalf("RollbackService() error = %v", err)
+	}
+	if got := gcp.current.(*GCPConfig); got.Project != "sandbox" {
+		t.Errorf("gcp = %+v after rollback, want sandbox", got)
+	}
+	if got := aws.current.(*AWSConfig); got.Profile != "dev" {
+		t.Errorf("aws = %+v after rollback, want it left on dev", got)
+	}
+}
+
+// TestEnvironmentSwitcher_RollbackService_RefusesRoot tests that rolling
+// back a service as root needs AllowRoot, like a switch.
+func TestEnvironmentSwitcher_RollbackService_RefusesRoot(t *testing.T) {
+	kubernetes := &stateSwitcher{name: "kubernetes", current: &KubernetesConfig{Context: "prod"}}
+	es := newTestSwitcher()
+	es.geteuid = func() int { return 0 }
+	es.Register(kubernetes)
+	history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+	es.SetHistory(history)
+
+	entry := &HistoryEntry{
+		SwitchID:       "01SWITCH",
+		Environment:    "prod",
+		Services:       []string{"kubernetes"},
+		PreviousStates: map[string]ServiceConfig{"kubernetes": {Kubernetes: &KubernetesConfig{Context: "dev"}}},
+	}
+	if _, err := es.RollbackService(context.Background(), entry, "kubernetes", SwitchOptions{}); !errors.Is(err, ErrRunningAsRoot) {
+		t.Fatalf("RollbackService() error = %v, want ErrRunningAsRoot", err)
+	}
+	if got := kubernetes.current.(*KubernetesConfig); got.Context != "prod" {
+		t.Errorf("kubernetes = %+v, want it left on prod when running as root", got)
+	}
+	if entries, _ := history.Entries(); len(entries) != 0 {
+		t.Errorf("history = %+v, want nothing recorded", entries)
+	}
+
+	rollback, err := es.RollbackService(context.Background(), entry, "kubernetes", SwitchOptions{AllowRoot: true})
+	if err != nil {
+		t.Fatalf("RollbackService() with AllowRoot error = %v", err)
+	}
+	if got := kubernetes.current.(*KubernetesConfig); got.Context != "dev" {
+		t.Errorf("kubernetes = %+v after rollback with AllowRoot, want dev", got)
+	}
+	if !reflect.DeepEqual(rollback.Audit.Options, []string{"allow-root"}) {
+		t.Errorf("audit options = %v, want [allow-root]", rollback.Audit.Options)
+	}
+}
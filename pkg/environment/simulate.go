@@ -0,0 +1,315 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SimulatedResponse is the fake outcome of a simulated command.
+type SimulatedResponse struct {
+	// Output is returned as the command's standard output.
+	Output string
+	// Err, if set, is returned as the command's error.
+	Err error
+	// Stderr, if set with Err, is what the command wrote to standard
+	// error. It is returned in a CommandError, as RunCommand would.
+	Stderr string
+	// Delay is how long the command appears to take. It is cut short if
+	// the context is cancelled, e.g. by the switch timeout.
+	Delay time.Duration
+}
+
+// Simulator answers the external commands run during Simulate in place of
+// the real CLI tools.
+type Simulator interface {
+	// Respond returns the fake outcome of running name with args.
+	Respond(name string, args ...string) SimulatedResponse
+}
+
+// SimulatorConfig is a Simulator backed by a table of canned responses.
+type SimulatorConfig struct {
+	// CommandResponses maps a command prefix, written as "<binary> <args>",
+	// to its response. The longest matching prefix wins, so
+	// "gcloud config get-value project" can override "gcloud config".
+	// Commands without a match succeed with no output.
+	CommandResponses map[string]SimulatedResponse
+}
+
+// Respond returns the response of the longest prefix matching the command.
+func (c SimulatorConfig) Respond(name string, args ...string) SimulatedResponse {
+	line := commandLine(name, args)
+
+	var (
+		best    SimulatedResponse
+		bestLen = -1
+	)
+	for prefix, response := range c.CommandResponses {
+		if hasCommandPrefix(line, prefix) && len(prefix) > bestLen {
+			best, bestLen = response, len(prefix)
+		}
+	}
+	return best
+}
+
+// hasCommandPrefix reports whether line starts with prefix on a word boundary,
+// so "kubectl config" does not match "kubectl configure".
+func hasCommandPrefix(line, prefix string) bool {
+	return strings.HasPrefix(line, prefix) && (len(line) == len(prefix) || line[len(prefix)] == ' ')
+}
+
+// commandLine joins a command and its arguments with spaces.
+func commandLine(name string, args []string) string {
+	return strings.TrimSpace(name + " " + strings.Join(args, " "))
+}
+
+// SimulateOptions contains options for Simulate.
+type SimulateOptions struct {
+	SwitchOptions
+	// Simulator answers the commands. If nil, Config is used.
+	Simulator Simulator
+	// Config holds canned responses used when Simulator is nil.
+	Config SimulatorConfig
+}
+
+// SimulatedCommand records one command answered by the simulator.
+type SimulatedCommand struct {
+	Command  string        `json:"command"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// SimulationReport is the outcome of Simulate.
+type SimulationReport struct {
+	Environment string             `json:"environment"`
+	Commands    []SimulatedCommand `json:"commands"`
+	Duration    time.Duration      `json:"duration"`
+	Result      *SwitchResult      `json:"result,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// Simulate runs a full switch to env, including state capture, hooks, and
+// rollback, with every external command answered by a simulator instead of
+// the real CLI tools. The switch history and health outcome are left
+// untouched, and the root check is skipped since nothing is executed.
+//
+// A failed switch is reported in the report's Result and Error rather than
+// as an error; the returned error is only set if the simulation itself
+// could not run.
+func (es *EnvironmentSwitcher) Simulate(ctx context.Context, env *Environment, opts SimulateOptions) (*SimulationReport, error) {
+	if env == nil {
+		return nil, fmt.Errorf("environment is required")
+	}
+
+	simulator := opts.Simulator
+	if simulator == nil {
+		simulator = opts.Config
+	}
+	recorder := &simulationRecorder{simulator: simulator}
+
+	start := time.Now()
+	// Nothing is executed or written, so there is no root check or file
+	// snapshot.
+	sandbox := es.sandbox()
+	sandbox.geteuid = nil
+	sandbox.fileSnapshots = nil
+	result, err := sandbox.switchEnvironment(withSimulator(ctx, recorder), env, opts.SwitchOptions)
+
+	report := &SimulationReport{
+		Environment: env.Name,
+		Commands:    recorder.commands(),
+		Duration:    time.Since(start),
+		Result:      result,
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report, nil
+}
+
+// sandbox returns a switcher configured like es: the same service
+// switchers, callbacks, value validator, hook allowlist, file snapshots,
+// state cache, telemetry, root check, and CLI lookup. It has no history,
+// switch lock, current environment marker, or health outcome, so switching
+// with it records nothing; callers that should record do so themselves.
+func (es *EnvironmentSwitcher) sandbox() *EnvironmentSwitcher {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+
+	sandbox := &EnvironmentSwitcher{
+		serviceSwitchers: make(map[string]ServiceSwitcher, len(es.serviceSwitchers)),
+		progressCallback: es.progressCallback,
+		eventCallback:    es.eventCallback,
+		values:           es.values,
+		hookAllowlist:    es.hookAllowlist,
+		fileSnapshots:    es.fileSnapshots,
+		stateCache:       es.stateCache,
+		telemetry:        es.telemetry,
+		geteuid:          es.geteuid,
+		lookPath:         es.lookPath,
+	}
+	for name, switcher := range es.serviceSwitchers {
+		sandbox.serviceSwitchers[name] = switcher
+	}
+	return sandbox
+}
+
+// simulationRecorder wraps a Simulator and records the commands it answers.
+type simulationRecorder struct {
+	simulator Simulator
+	mu        sync.Mutex
+	log       []SimulatedCommand
+}
+
+// run answers a command, honouring its simulated delay.
+func (r *simulationRecorder) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	start := time.Now()
+	response := r.simulator.Respond(name, args...)
+
+	err := response.Err
+	if response.Delay > 0 {
+		timer := time.NewTimer(response.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			err = ctx.Err()
+		}
+	}
+
+	command := SimulatedCommand{
+		Command:  commandLine(name, args),
+		Output:   response.Output,
+		Start:    start,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		command.Error = err.Error()
+	}
+
+	r.mu.Lock()
+	r.log = append(r.log, command)
+	r.mu.Unlock()
+
+	if err != nil {
+		if response.Stderr != "" {
+			return nil, &CommandError{Command: command.Command, Stderr: response.Stderr, Err: err}
+		}
+		return nil, err
+	}
+	return []byte(response.Output), nil
+}
+
+// commands returns the recorded commands in the order they finished.
+func (r *simulationRecorder) commands() []SimulatedCommand {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]SimulatedCommand(nil), r.log...)
+}
+
+// simulatorKey is the context key of the active simulation recorder.
+type simulatorKey struct{}
+
+// withSimulator returns a context whose commands are answered by recorder.
+func withSimulator(ctx context.Context, recorder *simulationRecorder) context.Context {
+	return context.WithValue(ctx, simulatorKey{}, recorder)
+}
+
+// SimulateCommands returns a context in which RunCommand and hooks are
+// answered by simulator instead of being run, as under Simulate, for
+// switches that must not touch the host, such as those of mock services.
+func SimulateCommands(ctx context.Context, simulator Simulator) context.Context {
+	return withSimulator(ctx, &simulationRecorder{simulator: simulator})
+}
+
+// Simulating reports whether ctx belongs to a Simulate run or comes from
+// SimulateCommands, for switchers with side effects other than commands,
+// which they must skip.
+func Simulating(ctx context.Context) bool {
+	_, ok := ctx.Value(simulatorKey{}).(*simulationRecorder)
+	return ok
+}
+
+// CommandRunner runs an external command and returns its standard output.
+// RunCommand is the real one; switchers and checkers hold a CommandRunner so
+// tests can answer their commands.
+type CommandRunner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+// RunCommand runs an external command and returns its standard output.
+// Service switchers and status checkers use it for every CLI invocation so
+// that, under Simulate, the command is answered by the simulator instead,
+// and so that the command trace covers them.
+func RunCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return runCommand(ctx, false, name, args)
+}
+
+// RunCombinedCommand is RunCommand for commands that may answer on standard
+// error, such as `aws --version` of AWS CLI v1: it returns standard output
+// and standard error interleaved.
+func RunCombinedCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return runCommand(ctx, true, name, args)
+}
+
+// runCommand runs a command for RunCommand and RunCombinedCommand.
+func runCommand(ctx context.Context, combined bool, name string, args []string) ([]byte, error) {
+	if recorder, ok := ctx.Value(simulatorKey{}).(*simulationRecorder); ok {
+		return recorder.run(ctx, name, args...)
+	}
+	start := time.Now()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...) // #nosec G204 - callers pass fixed CLI invocations
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if combined {
+		cmd.Stderr = &stdout
+	}
+	err := cmd.Run()
+	output := stdout.Bytes()
+	traceCommand(ctx, name, args, start, len(output), stderr.Len(), err)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return output, &CommandError{Command: commandLine(name, args), Stderr: stderr.String(), Err: err}
+	}
+	return output, err
+}
+
+// CommandError is returned by RunCommand when a command fails. It keeps
+// what the command wrote to standard error, which usually explains an
+// error that otherwise reads only "exit status 1".
+type CommandError struct {
+	Command string
+	Stderr  string
+	Err     error
+}
+
+// Error implements the error interface. It is the underlying error alone,
+// so wrapping messages read as before.
+func (e *CommandError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// CommandStderr returns the trimmed standard error of the command that
+// caused err, or "" if err does not come from RunCommand.
+func CommandStderr(err error) string {
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		return strings.TrimSpace(cmdErr.Stderr)
+	}
+	return ""
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// commandSwitcher is a docker-like switcher that runs its CLI through RunCommand.
+type commandSwitcher struct{}
+
+func (commandSwitcher) Name() string { return "docker" }
+
+func (commandSwitcher) Switch(ctx context.Context, config interface{}) error {
+	_, err := RunCommand(ctx, "docker", "context", "use", config.(*DockerConfig).Context)
+	return err
+}
+
+func (commandSwitcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	output, _ := RunCommand(ctx, "docker", "context", "show")
+	return &DockerConfig{Context: strings.TrimSpace(string(output))}, nil
+}
+
+func (s commandSwitcher) Rollback(ctx context.Context, previousState interface{}) error {
+	return s.Switch(ctx, previousState)
+}
+
+// commands returns the command lines recorded in a report.
+func commands(report *SimulationReport) []string {
+	lines := make([]string, len(report.Commands))
+	for i, command := range report.Commands {
+		lines[i] = command.Command
+	}
+	return lines
+}
+
+// TestSimulatorConfig_Respond tests longest-prefix matching of responses.
+func TestSimulatorConfig_Respond(t *testing.T) {
+	config := SimulatorConfig{CommandResponses: map[string]SimulatedResponse{
+		"kubectl config":                 {Output: "config"},
+		"kubectl config current-context": {Output: "current"},
+		"docker":                         {Output: "docker"},
+	}}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"kubectl", []string{"config", "current-context"}, "current"},
+		{"kubectl", []string{"config", "view"}, "config"},
+		{"kubectl", []string{"configure"}, ""},
+		{"docker", []string{"context", "ls"}, "docker"},
+		{"az", []string{"account", "show"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(commandLine(tt.name, tt.args), func(t *testing.T) {
+			if got := config.Respond(tt.name, tt.args...).Output; got != tt.want {
+				t.Errorf("Respond() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEnvironmentSwitcher_Simulate tests that a simulated switch records
+// every command and leaves the history untouched.
+func TestEnvironmentSwitcher_Simulate(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.json")
+
+	es := newTestSwitcher()
+	es.Register(commandSwitcher{})
+	es.SetHistory(NewHistory(historyPath))
+
+	env := &Environment{
+		Name:     "dev",
+		Services: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}},
+		PreHooks: []Hook{{Command: "echo before"}},
+		Activate: "echo activated",
+	}
+	opts := SimulateOptions{Config: SimulatorConfig{CommandResponses: map[string]SimulatedResponse{
+		"docker context show": {Output: "prod\n", Delay: 10 * time.Millisecond},
+	}}}
+
+	report, err := es.Simulate(context.Background(), env, opts)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+
+	want := []string{
+		"sh -c echo before",
+		"docker context show",
+		"docker context use dev",
+		"sh -c echo activated",
+	}
+	if got := commands(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulate() commands = %v, want %v", got, want)
+	}
+	if report.Commands[1].Duration < 10*time.Millisecond {
+		t.Errorf("simulated delay = %v, want at least 10ms", report.Commands[1].Duration)
+	}
+	if report.Result == nil || !report.Result.Success || report.Error != "" {
+		t.Errorf("Simulate() result = %+v, error %q, want success", report.Result, report.Error)
+	}
+	if _, err := os.Stat(historyPath); !os.IsNotExist(err) {
+		t.Errorf("Simulate() wrote history: stat error = %v", err)
+	}
+}
+
+// TestEnvironmentSwitcher_Simulate_Failure tests that a simulated failure is
+// reported and triggers a simulated rollback.
+func TestEnvironmentSwitcher_Simulate_Failure(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(commandSwitcher{})
+
+	env := &Environment{
+		Name:     "dev",
+		Services: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}},
+	}
+	opts := SimulateOptions{
+		SwitchOptions: SwitchOptions{RollbackOnError: true},
+		Config: SimulatorConfig{CommandResponses: map[string]SimulatedResponse{
+			"docker context show":    {Output: "prod\n"},
+			"docker context use dev": {Err: errors.New("context not found")},
+		}},
+	}
+
+	report, err := es.Simulate(context.Background(), env, opts)
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+
+	want := []string{
+		"docker context show",
+		"docker context use dev",
+		"docker context use prod",
+	}
+	if got := commands(report); !reflect.DeepEqual(got, want) {
+		t.Errorf("Simulate() commands = %v, want %v", got, want)
+	}
+	if report.Commands[1].Error != "context not found" {
+		t.Errorf("Commands[1].Error = %q, want %q", report.Commands[1].Error, "context not found")
+	}
+	if report.Result == nil || report.Result.Success || !report.Result.RollbackPerformed {
+		t.Errorf("Simulate() result = %+v, want failed with rollback", report.Result)
+	}
+	if !strings.Contains(report.Error, "context not found") {
+		t.Errorf("Simulate() report error = %q, want it to mention the failure", report.Error)
+	}
+}
+
+// TestEnvironmentSwitcher_Simulate_Configuration tests that simulations
+// validate values and hooks as configured on the switcher.
+func TestEnvironmentSwitcher_Simulate_Configuration(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+	es.SetValueValidator(ValueValidator{AWSRegions: []string{"mars-north-1"}})
+	es.SetHookAllowlist([]string{"echo"})
+
+	tests := []struct {
+		name    string
+		hook    string
+		wantErr string
+	}{
+		{name: "allowed", hook: "echo before"},
+		{name: "not allowed", hook: "touch /tmp/before", wantErr: "does not match any allowed prefix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{
+				Name:     "dev",
+				Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "dev", Region: "mars-north-1"}}},
+				PreHooks: []Hook{{Command: tt.hook}},
+			}
+			report, err := es.Simulate(context.Background(), env, SimulateOptions{})
+			if err != nil {
+				t.Fatalf("Simulate() error = %v", err)
+			}
+			if tt.wantErr == "" && report.Error != "" {
+				t.Errorf("Simulate() report error = %q, want the custom region and hook accepted", report.Error)
+			}
+			if tt.wantErr != "" && !strings.Contains(report.Error, tt.wantErr) {
+				t.Errorf("Simulate() report error = %q, want %q", report.Error, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSimulateCommands tests that a real switch in a SimulateCommands context
+// answers hooks and CLI commands from the simulator without running them.
+func TestSimulateCommands(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "hook-ran")
+	es := newTestSwitcher()
+	es.Register(commandSwitcher{})
+	env := &Environment{
+		Name:      "dev",
+		Services:  map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}},
+		PostHooks: []Hook{{Command: "touch " + marker}},
+	}
+
+	ctx := SimulateCommands(context.Background(), SimulatorConfig{})
+	if !Simulating(ctx) {
+		t.Error("Simulating() = false, want true")
+	}
+	if _, err := es.SwitchEnvironment(ctx, env, SwitchOptions{}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("hook ran on the host: stat error = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Snapshot captures the current state of services into an environment named
+// name, so it can be saved and switched back to later. Services whose state
+// has no fields set, such as a tool with nothing configured, are left out.
+func (es *EnvironmentSwitcher) Snapshot(ctx context.Context, name string, services []string) (*Environment, error) {
+	sorted := append([]string(nil), services...)
+	sort.Strings(sorted)
+
+	env := &Environment{
+		Name:     name,
+		Services: make(map[string]ServiceConfig),
+	}
+	for _, service := range sorted {
+		es.mu.RLock()
+		switcher, exists := es.serviceSwitchers[service]
+		es.mu.RUnlock()
+
+		if !exists {
+			return nil, fmt.Errorf("no switcher registered for service: %s", service)
+		}
+
+		state, err := switcher.GetCurrentState(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current state for %s: %w", service, err)
+		}
+
+		config := serviceConfigFromState(state)
+		if len(flattenServiceConfig(service, config)) == 0 {
+			continue
+		}
+		env.Services[service] = config
+	}
+	return env, nil
+}
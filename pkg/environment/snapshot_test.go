@@ -0,0 +1,39 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"testing"
+)
+
+// TestEnvironmentSwitcher_Snapshot tests capturing current state into an environment.
+func TestEnvironmentSwitcher_Snapshot(t *testing.T) {
+	es := newTestSwitcher()
+	awsMock := newMockSwitcher("aws")
+	awsMock.state = &AWSConfig{Profile: "prod", Region: "us-east-1"}
+	dockerMock := newMockSwitcher("docker")
+	dockerMock.state = &DockerConfig{}
+	es.Register(awsMock)
+	es.Register(dockerMock)
+
+	env, err := es.Snapshot(context.Background(), "default", []string{"docker", "aws"})
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	if env.Name != "default" {
+		t.Errorf("Snapshot().Name = %q, want %q", env.Name, "default")
+	}
+	if got := env.Services["aws"].AWS; got == nil || got.Profile != "prod" {
+		t.Errorf("Snapshot() aws = %+v, want profile prod", got)
+	}
+	if env.HasService("docker") {
+		t.Error("Snapshot() should leave out docker, which has nothing set")
+	}
+
+	if _, err := es.Snapshot(context.Background(), "default", []string{"gcp"}); err == nil {
+		t.Error("Snapshot() should fail for a service without a switcher")
+	}
+}
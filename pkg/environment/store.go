@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// EnvStore is a place environments are discovered and loaded from. The
+// filesystem is the only backend so far; the interface leaves room for
+// others, such as an HTTP URL, a git repository, or S3.
+type EnvStore interface {
+	// List returns every environment in the store. Entries that are not
+	// valid environments are skipped.
+	List(ctx context.Context) ([]*Environment, error)
+	// Load returns the environment called name, or listing name among its
+	// aliases.
+	Load(ctx context.Context, name string) (*Environment, error)
+}
+
+// FileStore is the EnvStore of the environment files in a list of
+// directories, searched in order.
+type FileStore struct {
+	searchPaths []string
+}
+
+var _ EnvStore = (*FileStore)(nil)
+
+// NewFileStore returns the EnvStore of the environment files in
+// searchPaths.
+func NewFileStore(searchPaths []string) *FileStore {
+	return &FileStore{searchPaths: searchPaths}
+}
+
+// List returns the environments of the parseable files in the search paths,
+// in search path order.
+func (s *FileStore) List(context.Context) ([]*Environment, error) {
+	files := scanEnvironmentFiles(s.searchPaths)
+	environments := make([]*Environment, 0, len(files))
+	for _, f := range files {
+		environments = append(environments, f.env)
+	}
+	return environments, nil
+}
+
+// Load resolves name as FindEnvironmentFile does and loads the file.
+func (s *FileStore) Load(_ context.Context, name string) (*Environment, error) {
+	path, err := FindEnvironmentFile(s.searchPaths, name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadEnvironmentFromFile(path)
+}
+
+// StoreResolver returns a resolver that loads composed environments from
+// store.
+func StoreResolver(ctx context.Context, store EnvStore) EnvironmentResolver {
+	return func(name string) (*Environment, error) {
+		return store.Load(ctx, name)
+	}
+}
+
+// OpenEnvStore returns the EnvStore a --source value selects: searchPaths
+// if source is empty, otherwise the directory source names, as a path or a
+// file:// URL. Other URL schemes are not supported yet.
+func OpenEnvStore(source string, searchPaths []string) (EnvStore, error) {
+	if source == "" {
+		return NewFileStore(searchPaths), nil
+	}
+	if !strings.Contains(source, "://") {
+		return NewFileStore([]string{source}), nil
+	}
+
+	u, err := url.Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid environment source %q: %w", source, err)
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return nil, fmt.Errorf("environment source %q: file URLs must not name a remote host", source)
+		}
+		if u.Path == "" {
+			return nil, fmt.Errorf("environment source %q has no path", source)
+		}
+		return NewFileStore([]string{u.Path}), nil
+	default:
+		return nil, fmt.Errorf("unsupported environment source scheme %q (supported: file)", u.Scheme)
+	}
+}
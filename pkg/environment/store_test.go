@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// memoryStore is an EnvStore holding environments in memory.
+type memoryStore struct {
+	envs []*Environment
+}
+
+func (m memoryStore) List(context.Context) ([]*Environment, error) { return m.envs, nil }
+
+func (m memoryStore) Load(_ context.Context, name string) (*Environment, error) {
+	for _, env := range m.envs {
+		if strings.EqualFold(env.Name, name) || env.HasAlias(name) {
+			return env, nil
+		}
+	}
+	return nil, fmt.Errorf("environment '%s' not found", name)
+}
+
+// TestEnvStore_Switch tests switching to an environment loaded, along with
+// the environments it composes, from a store other than the filesystem.
+func TestEnvStore_Switch(t *testing.T) {
+	store := memoryStore{envs: []*Environment{
+		{
+			Name:     "base",
+			Services: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "shared"}}},
+		},
+		{
+			Name:     "staging",
+			Aliases:  []string{"stg"},
+			Compose:  []string{"base"},
+			Services: map[string]ServiceConfig{"kubernetes": {Kubernetes: &KubernetesConfig{Context: "staging"}}},
+		},
+	}}
+
+	ctx := context.Background()
+	env, err := store.Load(ctx, "stg")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	env, err = ExpandComposition(env, StoreResolver(ctx, store))
+	if err != nil {
+		t.Fatalf("ExpandComposition() error = %v", err)
+	}
+
+	docker, kubernetes := newMockSwitcher("docker"), newMockSwitcher("kubernetes")
+	es := newTestSwitcher()
+	es.Register(docker)
+	es.Register(kubernetes)
+
+	result, err := es.SwitchEnvironment(ctx, env, SwitchOptions{})
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+	if !result.Success || !docker.switchCalled || !kubernetes.switchCalled {
+		t.Errorf("SwitchEnvironment() = %+v, want docker and kubernetes switched", result)
+	}
+
+	if _, err := ExpandComposition(&Environment{Name: "broken", Compose: []string{"missing"}}, StoreResolver(ctx, store)); err == nil {
+		t.Error("ExpandComposition() should fail for an environment missing from the store")
+	}
+}
+
+// TestFileStore tests listing and loading environment files.
+func TestFileStore(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"dev.yaml":     "name: dev\naliases: [development]\nservices:\n  docker:\n    context: dev\n",
+		"prod.yml":     "name: prod\nservices:\n  docker:\n    context: prod\n",
+		"notes.txt":    "not an environment\n",
+		"invalid.yaml": "name: [\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store := NewFileStore([]string{dir, filepath.Join(dir, "missing")})
+	ctx := context.Background()
+
+	envs, err := store.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	var names []string
+	for _, env := range envs {
+		names = append(names, env.Name)
+	}
+	if strings.Join(names, ",") != "dev,prod" {
+		t.Errorf("List() = %v, want [dev prod]", names)
+	}
+
+	env, err := store.Load(ctx, "development")
+	if err != nil || env.Name != "dev" {
+		t.Errorf("Load(development) = %v, %v, want dev", env, err)
+	}
+	if _, err := store.Load(ctx, "staging"); err == nil {
+		t.Error("Load(staging) should fail")
+	}
+}
+
+// TestOpenEnvStore tests selecting a store from a --source value.
+func TestOpenEnvStore(t *testing.T) {
+	defaults := []string{"/home/user/.gzh/dev-env/environments", "."}
+
+	tests := []struct {
+		source    string
+		wantPaths []string
+		wantErr   string
+	}{
+		{"", defaults, ""},
+		{"/srv/envs", []string{"/srv/envs"}, ""},
+		{"envs", []string{"envs"}, ""},
+		{"file:///srv/envs", []string{"/srv/envs"}, ""},
+		{"file://localhost/srv/envs", []string{"/srv/envs"}, ""},
+		{"file://server/srv/envs", nil, "remote host"},
+		{"file://", nil, "no path"},
+		{"https://example.com/envs", nil, `unsupported environment source scheme "https"`},
+		{"s3://bucket/envs", nil, `unsupported environment source scheme "s3"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			store, err := OpenEnvStore(tt.source, defaults)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("OpenEnvStore() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OpenEnvStore() error = %v", err)
+			}
+			fileStore, ok := store.(*FileStore)
+			if !ok || strings.Join(fileStore.searchPaths, ",") != strings.Join(tt.wantPaths, ",") {
+				t.Errorf("OpenEnvStore() = %+v, want a FileStore of %v", store, tt.wantPaths)
+			}
+		})
+	}
+}
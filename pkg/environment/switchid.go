@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"crypto/rand"
+	"time"
+)
+
+// SwitchIDEnvVar is the environment variable that hooks receive the switch
+// ID in.
+const SwitchIDEnvVar = "GZH_SWITCH_ID"
+
+// crockfordBase32 is the ULID alphabet.
+const crockfordBase32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewSwitchID returns a new switch ID, a ULID: 26 characters that sort by
+// creation time, so IDs of later switches sort after earlier ones.
+func NewSwitchID() string {
+	return newULID(time.Now())
+}
+
+// newULID encodes the millisecond timestamp of t followed by 80 random bits.
+func newULID(t time.Time) string {
+	var data [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+	_, _ = rand.Read(data[6:])
+
+	// 128 bits encode to 26 base32 characters, the first carrying 3 bits.
+	var id [26]byte
+	var buffer uint64
+	bits := 2 // pad to 130 bits so the groups of 5 line up
+	index := 0
+	for _, b := range data {
+		buffer = buffer<<8 | uint64(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			id[index] = crockfordBase32[(buffer>>uint(bits))&0x1f]
+			index++
+		}
+	}
+	return string(id[:])
+}
+
+// switchIDKey is the context key of the switch ID.
+type switchIDKey struct{}
+
+// withSwitchID returns a context carrying the switch ID, for the switchers
+// and hooks of one switch.
+func withSwitchID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, switchIDKey{}, id)
+}
+
+// SwitchIDFromContext returns the ID of the switch a service switcher is
+// called for, or "" outside a switch. Switchers can pass it on to external
+// systems to trace a switch across them.
+func SwitchIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(switchIDKey{}).(string)
+	return id
+}
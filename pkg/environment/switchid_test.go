@@ -0,0 +1,151 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestNewSwitchID tests the ULID format and that IDs sort by time.
+func TestNewSwitchID(t *testing.T) {
+	id := NewSwitchID()
+	if len(id) != 26 {
+		t.Fatalf("NewSwitchID() = %q, want 26 characters", id)
+	}
+	for _, c := range id {
+		if !strings.ContainsRune(crockfordBase32, c) {
+			t.Errorf("NewSwitchID() = %q contains %q, not in the ULID alphabet", id, c)
+		}
+	}
+	if id == NewSwitchID() {
+		t.Error("NewSwitchID() returned the same ID twice")
+	}
+
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	earlier, later := newULID(at), newULID(at.Add(time.Millisecond))
+	if earlier >= later {
+		t.Errorf("newULID() = %q then %q, want IDs sorted by time", earlier, later)
+	}
+	// The timestamp of the ULID spec's example, 1469918176385 ms.
+	if got := newULID(time.UnixMilli(1469918176385))[:10]; got != "01ARYZ6S41" {
+		t.Errorf("newULID() timestamp = %q, want %q", got, "01ARYZ6S41")
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchID tests that one switch ID appears in the
+// result, progress and service events, the history entry and its audit
+// record, and hooks.
+func TestEnvironmentSwitcher_SwitchID(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the hook is a shell script")
+	}
+
+	dir := t.TempDir()
+	hookOutput := filepath.Join(dir, "hook-id")
+	hook := filepath.Join(dir, "record-id.sh")
+	script := "#!/bin/sh\necho \"$" + SwitchIDEnvVar + "\" > " + hookOutput + "\n"
+	if err := os.WriteFile(hook, []byte(script), 0o700); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		switchID string
+	}{
+		{"generated", ""},
+		{"supplied", "trace-1234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{
+				Name: "staging",
+				Services: map[string]ServiceConfig{
+					"aws":    {AWS: &AWSConfig{Profile: "staging"}},
+					"docker": {Docker: &DockerConfig{Context: "staging"}},
+				},
+				Activate: hook,
+			}
+
+			es := newTestSwitcher()
+			es.Register(newMockSwitcher("aws"))
+			es.Register(newMockSwitcher("docker"))
+			history := NewHistory(filepath.Join(t.TempDir(), "history.json"))
+			es.SetHistory(history)
+
+			var mu sync.Mutex
+			var ids []string
+			es.SetProgressCallback(func(p SwitchProgress) {
+				mu.Lock()
+				defer mu.Unlock()
+				ids = append(ids, p.SwitchID)
+			})
+			es.SetServiceEventCallback(func(e ServiceEvent) {
+				mu.Lock()
+				defer mu.Unlock()
+				ids = append(ids, e.SwitchID)
+			})
+
+			result, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{SwitchID: tt.switchID})
+			if err != nil {
+				t.Fatalf("SwitchEnvironment() error = %v", err)
+			}
+
+			want := result.SwitchID
+			if want == "" || (tt.switchID != "" && want != tt.switchID) {
+				t.Fatalf("result.SwitchID = %q, want %q or a generated ID", want, tt.switchID)
+			}
+			if len(ids) == 0 {
+				t.Fatal("no progress or service events were reported")
+			}
+			for _, id := range ids {
+				if id != want {
+					t.Errorf("event switch ID = %q, want %q", id, want)
+				}
+			}
+
+			last, err := history.Last()
+			if err != nil || last == nil {
+				t.Fatalf("history.Last() = %v, %v", last, err)
+			}
+			if last.SwitchID != want {
+				t.Errorf("history switch ID = %q, want %q", last.SwitchID, want)
+			}
+			if last.Audit == nil || last.Audit.SwitchID != want || last.Audit.Operation != AuditSwitch {
+				t.Errorf("history audit record = %+v, want a switch with ID %q", last.Audit, want)
+			}
+
+			data, err := os.ReadFile(hookOutput) // #nosec G304 - test file
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSpace(string(data)); got != want {
+				t.Errorf("hook %s = %q, want %q", SwitchIDEnvVar, got, want)
+			}
+		})
+	}
+}
+
+// TestNewAuditRecord tests the options and errors an audit record keeps.
+func TestNewAuditRecord(t *testing.T) {
+	result := &SwitchResult{Errors: []SwitchError{{Service: "kubernetes", Error: "exit status 1", Detail: "token=hunter2"}}}
+	record := newAuditRecord(AuditRetry, "01JZ", SwitchOptions{Force: true, PartialSuccess: true, DryRun: true}, result)
+
+	if record.Operation != AuditRetry || record.SwitchID != "01JZ" {
+		t.Errorf("newAuditRecord() = %+v, want a retry with ID 01JZ", record)
+	}
+	if got, want := strings.Join(record.Options, ","), "force,partial-success"; got != want {
+		t.Errorf("newAuditRecord() options = %q, want %q", got, want)
+	}
+	if got, want := strings.Join(record.Errors, ","), "kubernetes: exit status 1"; got != want {
+		t.Errorf("newAuditRecord() errors = %q, want %q without the CLI output", got, want)
+	}
+}
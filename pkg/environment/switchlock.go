@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrSwitchInProgress is returned when another process holds the switch lock.
+var ErrSwitchInProgress = errors.New("another switch is in progress")
+
+// errLockHeld is returned by tryLockFile when another holder has the lock.
+var errLockHeld = errors.New("lock held")
+
+// DefaultSwitchLockPath returns ~/.gzh/dev-env/switch.lock.
+func DefaultSwitchLockPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "switch.lock")
+}
+
+// SwitchLock serializes switches across processes, so two concurrent
+// switches cannot interleave their writes to shared configuration files
+// such as the kubeconfig. It is an advisory lock on a file, released by
+// the operating system if its holder dies, and a no-op on platforms
+// without flock. The file records who holds the lock, for the error shown
+// to the next switch.
+type SwitchLock struct {
+	path string
+	now  func() time.Time
+}
+
+// NewSwitchLock locks the file at path, or DefaultSwitchLockPath if empty.
+func NewSwitchLock(path string) *SwitchLock {
+	if path == "" {
+		path = DefaultSwitchLockPath()
+	}
+	return &SwitchLock{path: path, now: time.Now}
+}
+
+// Acquire takes the lock without waiting. If another switch holds it, the
+// error wraps ErrSwitchInProgress and names the holder. The returned
+// function releases the lock.
+func (l *SwitchLock) Acquire() (release func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create switch lock directory: %w", err)
+	}
+	// #nosec G304 - The lock file is in dev-env's own directory
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open switch lock: %w", err)
+	}
+
+	if err := tryLockFile(f); err != nil {
+		holder, _ := os.ReadFile(l.path)
+		_ = f.Close()
+		if !errors.Is(err, errLockHeld) {
+			return nil, fmt.Errorf("failed to lock %s: %w", l.path, err)
+		}
+		if h := strings.TrimSpace(string(holder)); h != "" {
+			return nil, fmt.Errorf("%w (%s)", ErrSwitchInProgress, h)
+		}
+		return nil, ErrSwitchInProgress
+	}
+
+	holder := fmt.Sprintf("pid %d", os.Getpid())
+	if user := CurrentUser(); user != "" {
+		holder += ", user " + user
+	}
+	holder += ", since " + l.now().Format(time.RFC3339)
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(holder+"\n"), 0)
+	}
+
+	return func() {
+		_ = f.Truncate(0)
+		_ = unlockFile(f)
+		_ = f.Close()
+	}, nil
+}
+
+// ForceUnlock removes the lock file, so the next Acquire succeeds even if
+// a stuck switch still holds the lock on the removed file. That switch
+// may still be writing, so this is only for a switch known to be hung.
+func (l *SwitchLock) ForceUnlock() error {
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove switch lock: %w", err)
+	}
+	return nil
+}
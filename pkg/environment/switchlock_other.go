@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package environment
+
+import "os"
+
+// tryLockFile is a no-op where flock is unavailable; concurrent switches
+// from separate processes are then not serialized.
+func tryLockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op where flock is unavailable.
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+//go:build unix
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestSwitchLock_Acquire tests that a held lock blocks a second acquirer
+// until it is released.
+func TestSwitchLock_Acquire(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dev-env", "switch.lock")
+	first, second := NewSwitchLock(path), NewSwitchLock(path)
+
+	release, err := first.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	_, err = second.Acquire()
+	if !errors.Is(err, ErrSwitchInProgress) {
+		t.Fatalf("second Acquire() error = %v, want %v", err, ErrSwitchInProgress)
+	}
+	if want := fmt.Sprintf("pid %d", os.Getpid()); !strings.Contains(err.Error(), want) {
+		t.Errorf("second Acquire() error = %q, want it to name the holder (%s)", err, want)
+	}
+
+	release()
+	releaseSecond, err := second.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire() after release error = %v", err)
+	}
+	releaseSecond()
+}
+
+// TestSwitchLock_ForceUnlock tests that a forced unlock lets a new switch
+// past a lock that is still held.
+func TestSwitchLock_ForceUnlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "switch.lock")
+	stuck := NewSwitchLock(path)
+	release, err := stuck.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer release()
+
+	next := NewSwitchLock(path)
+	if err := next.ForceUnlock(); err != nil {
+		t.Fatalf("ForceUnlock() error = %v", err)
+	}
+	releaseNext, err := next.Acquire()
+	if err != nil {
+		t.Fatalf("Acquire() after ForceUnlock() error = %v", err)
+	}
+	releaseNext()
+
+	if err := NewSwitchLock(filepath.Join(t.TempDir(), "missing.lock")).ForceUnlock(); err != nil {
+		t.Errorf("ForceUnlock() without a lock file error = %v, want nil", err)
+	}
+}
+
+// TestEnvironmentSwitcher_SwitchLock tests that a switch fails while
+// another holds the lock, and that dry runs do not need it.
+func TestEnvironmentSwitcher_SwitchLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "switch.lock")
+	es := newTestSwitcher()
+	es.Register(newMockSwitcher("docker"))
+	es.SetSwitchLock(NewSwitchLock(path))
+
+	env := &Environment{
+		Name:     "dev",
+		Services: map[string]ServiceConfig{"docker": {Docker: &DockerConfig{Context: "dev"}}},
+	}
+
+	release, err := NewSwitchLock(path).Acquire()
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); !errors.Is(err, ErrSwitchInProgress) {
+		t.Errorf("SwitchEnvironment() error = %v, want %v", err, ErrSwitchInProgress)
+	}
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{DryRun: true}); err != nil {
+		t.Errorf("dry run SwitchEnvironment() error = %v, want nil", err)
+	}
+
+	release()
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err != nil {
+		t.Errorf("SwitchEnvironment() after release error = %v", err)
+	}
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{}); err != nil {
+		t.Errorf("SwitchEnvironment() should release the lock when done, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// DefaultTargetCacheTTL is how long cached targets are considered fresh.
+const DefaultTargetCacheTTL = 10 * time.Minute
+
+// TargetSnapshot is the list of targets for a service as of FetchedAt.
+type TargetSnapshot struct {
+	Targets   []string  `json:"targets"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
+
+// Fresh reports whether the snapshot is younger than ttl at now.
+func (s TargetSnapshot) Fresh(ttl time.Duration, now time.Time) bool {
+	return !s.FetchedAt.IsZero() && now.Sub(s.FetchedAt) < ttl
+}
+
+// TargetCache stores target snapshots per service. Implementations must be
+// safe for concurrent use.
+type TargetCache interface {
+	// Get returns the cached snapshot for service, if any, however old.
+	Get(service string) (TargetSnapshot, bool)
+	// Set replaces the cached snapshot for service.
+	Set(service string, snapshot TargetSnapshot) error
+}
+
+// MemoryTargetCache is an in-process TargetCache.
+type MemoryTargetCache struct {
+	mu        sync.RWMutex
+	snapshots map[string]TargetSnapshot
+}
+
+// NewMemoryTargetCache creates an empty in-memory target cache.
+func NewMemoryTargetCache() *MemoryTargetCache {
+	return &MemoryTargetCache{snapshots: make(map[string]TargetSnapshot)}
+}
+
+// Get implements TargetCache.
+func (c *MemoryTargetCache) Get(service string) (TargetSnapshot, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	snapshot, ok := c.snapshots[service]
+	return snapshot, ok
+}
+
+// Set implements TargetCache.
+func (c *MemoryTargetCache) Set(service string, snapshot TargetSnapshot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshots[service] = snapshot
+	return nil
+}
+
+// FileTargetCache is a TargetCache stored as a JSON file, so snapshots can
+// be reused across invocations.
+type FileTargetCache struct {
+	path string
+	mu   sync.Mutex
+}
+
+// DefaultTargetCachePath returns the default cache location, ~/.gzh/dev-env/targets.json.
+func DefaultTargetCachePath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "targets.json")
+}
+
+// NewFileTargetCache creates a cache stored at path, or at
+// DefaultTargetCachePath if empty.
+func NewFileTargetCache(path string) *FileTargetCache {
+	if path == "" {
+		path = DefaultTargetCachePath()
+	}
+	return &FileTargetCache{path: path}
+}
+
+// Get implements TargetCache. An unreadable cache file is treated as empty.
+func (c *FileTargetCache) Get(service string) (TargetSnapshot, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshots, err := c.load()
+	if err != nil {
+		return TargetSnapshot{}, false
+	}
+	snapshot, ok := snapshots[service]
+	return snapshot, ok
+}
+
+// Set implements TargetCache.
+func (c *FileTargetCache) Set(service string, snapshot TargetSnapshot) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshots, err := c.load()
+	if err != nil {
+		// Start over rather than fail on a corrupt cache.
+		snapshots = nil
+	}
+	if snapshots == nil {
+		snapshots = make(map[string]TargetSnapshot)
+	}
+	snapshots[service] = snapshot
+
+	data, err := json.MarshalIndent(snapshots, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode target cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create target cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write target cache: %w", err)
+	}
+	return nil
+}
+
+// load reads the cache file; a missing file is an empty cache.
+func (c *FileTargetCache) load() (map[string]TargetSnapshot, error) {
+	data, err := os.ReadFile(c.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read target cache: %w", err)
+	}
+
+	var snapshots map[string]TargetSnapshot
+	if err := json.Unmarshal(data, &snapshots); err != nil {
+		return nil, fmt.Errorf("failed to parse target cache %s: %w", c.path, err)
+	}
+	return snapshots, nil
+}
+
+// ListTargets lists the targets of a registered service whose switcher
+// implements TargetLister.
+func (es *EnvironmentSwitcher) ListTargets(ctx context.Context, service string) ([]string, error) {
+	es.mu.RLock()
+	switcher, exists := es.serviceSwitchers[service]
+	es.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("no switcher registered for service: %s", service)
+	}
+	lister, ok := switcher.(TargetLister)
+	if !ok {
+		return nil, fmt.Errorf("service %s does not support listing targets", service)
+	}
+	return lister.ListTargets(ctx)
+}
+
+// FetchTargets lists a service's targets and stores them in cache.
+func (es *EnvironmentSwitcher) FetchTargets(ctx context.Context, service string, cache TargetCache) (TargetSnapshot, error) {
+	targets, err := es.ListTargets(ctx, service)
+	if err != nil {
+		return TargetSnapshot{}, err
+	}
+
+	snapshot := TargetSnapshot{Targets: targets, FetchedAt: time.Now()}
+	if err := cache.Set(service, snapshot); err != nil {
+		return snapshot, err
+	}
+	return snapshot, nil
+}
+
+// TargetConfig returns the service configuration that selects target, for
+// services whose targets are listed by their switchers.
+func TargetConfig(service, target string) (ServiceConfig, error) {
+	switch service {
+	case "aws":
+		return ServiceConfig{AWS: &AWSConfig{Profile: target}}, nil
+	case "gcp":
+		return ServiceConfig{GCP: &GCPConfig{Project: target}}, nil
+	case "azure":
+		return ServiceConfig{Azure: &AzureConfig{Subscription: target}}, nil
+	case "docker":
+		return ServiceConfig{Docker: &DockerConfig{Context: target}}, nil
+	case "kubernetes":
+		return ServiceConfig{Kubernetes: &KubernetesConfig{Context: target}}, nil
+	default:
+		return ServiceConfig{}, fmt.Errorf("unsupported service for target selection: %s", service)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// listingSwitcher is a mock switcher that lists targets.
+type listingSwitcher struct {
+	mockSwitcher
+	targets []string
+	calls   int
+}
+
+func (l *listingSwitcher) ListTargets(ctx context.Context) ([]string, error) {
+	l.calls++
+	return l.targets, nil
+}
+
+// TestTargetCaches tests the memory and file target caches.
+func TestTargetCaches(t *testing.T) {
+	dir := t.TempDir()
+	caches := map[string]func() TargetCache{
+		"memory": func() TargetCache { return NewMemoryTargetCache() },
+		"file":   func() TargetCache { return NewFileTargetCache(filepath.Join(dir, "nested", "targets.json")) },
+	}
+
+	for name, newCache := range caches {
+		t.Run(name, func(t *testing.T) {
+			cache := newCache()
+			if _, ok := cache.Get("aws"); ok {
+				t.Fatal("Get() on empty cache should miss")
+			}
+
+			want := TargetSnapshot{Targets: []string{"dev", "prod"}, FetchedAt: time.Now().Truncate(time.Second)}
+			if err := cache.Set("aws", want); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+			if err := cache.Set("docker", TargetSnapshot{Targets: []string{"default"}}); err != nil {
+				t.Fatalf("Set() error = %v", err)
+			}
+
+			got, ok := cache.Get("aws")
+			if !ok || !reflect.DeepEqual(got.Targets, want.Targets) || !got.FetchedAt.Equal(want.FetchedAt) {
+				t.Errorf("Get() = %+v, %v, want %+v", got, ok, want)
+			}
+		})
+	}
+}
+
+// TestFileTargetCache_Shared tests that separate instances share the file.
+func TestFileTargetCache_Shared(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "targets.json")
+	if err := NewFileTargetCache(path).Set("gcp", TargetSnapshot{Targets: []string{"proj"}}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	if got, ok := NewFileTargetCache(path).Get("gcp"); !ok || len(got.Targets) != 1 {
+		t.Errorf("Get() = %+v, %v, want cached snapshot", got, ok)
+	}
+
+	// A corrupt file is treated as empty and overwritten.
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cache := NewFileTargetCache(path)
+	if _, ok := cache.Get("gcp"); ok {
+		t.Error("Get() on corrupt cache should miss")
+	}
+	if err := cache.Set("gcp", TargetSnapshot{}); err != nil {
+		t.Errorf("Set() on corrupt cache error = %v", err)
+	}
+}
+
+// TestTargetSnapshot_Fresh tests snapshot freshness.
+func TestTargetSnapshot_Fresh(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		fetchedAt time.Time
+		want      bool
+	}{
+		{name: "never fetched", fetchedAt: time.Time{}, want: false},
+		{name: "recent", fetchedAt: now.Add(-time.Minute), want: true},
+		{name: "expired", fetchedAt: now.Add(-time.Hour), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (TargetSnapshot{FetchedAt: tt.fetchedAt}).Fresh(10*time.Minute, now); got != tt.want {
+				t.Errorf("Fresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestEnvironmentSwitcher_FetchTargets tests listing targets into a cache.
+func TestEnvironmentSwitcher_FetchTargets(t *testing.T) {
+	es := newTestSwitcher()
+	lister := &listingSwitcher{mockSwitcher: *newMockSwitcher("aws"), targets: []string{"dev", "prod"}}
+	es.Register(lister)
+	es.Register(newMockSwitcher("ssh"))
+
+	cache := NewMemoryTargetCache()
+	snapshot, err := es.FetchTargets(context.Background(), "aws", cache)
+	if err != nil {
+		t.Fatalf("FetchTargets() error = %v", err)
+	}
+	if !reflect.DeepEqual(snapshot.Targets, lister.targets) || snapshot.FetchedAt.IsZero() {
+		t.Errorf("FetchTargets() = %+v, want targets with fetch time", snapshot)
+	}
+	if cached, ok := cache.Get("aws"); !ok || !reflect.DeepEqual(cached, snapshot) {
+		t.Errorf("cache.Get() = %+v, %v, want fetched snapshot", cached, ok)
+	}
+
+	if _, err := es.ListTargets(context.Background(), "ssh"); err == nil {
+		t.Error("ListTargets() should fail for a switcher without TargetLister")
+	}
+	if _, err := es.ListTargets(context.Background(), "missing"); err == nil {
+		t.Error("ListTargets() should fail for an unregistered service")
+	}
+}
+
+// TestTargetConfig tests mapping picker targets to service configurations.
+func TestTargetConfig(t *testing.T) {
+	tests := []struct {
+		service string
+		want    ServiceConfig
+		wantErr bool
+	}{
+		{service: "aws", want: ServiceConfig{AWS: &AWSConfig{Profile: "t"}}},
+		{service: "gcp", want: ServiceConfig{GCP: &GCPConfig{Project: "t"}}},
+		{service: "azure", want: ServiceConfig{Azure: &AzureConfig{Subscription: "t"}}},
+		{service: "docker", want: ServiceConfig{Docker: &DockerConfig{Context: "t"}}},
+		{service: "kubernetes", want: ServiceConfig{Kubernetes: &KubernetesConfig{Context: "t"}}},
+		{service: "ssh", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			got, err := TargetConfig(tt.service, "t")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("TargetConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TargetConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import "context"
+
+// Telemetry is told about each switch, and each service and hook within
+// it, as they run, so their spans and durations can be exported, e.g. with
+// OpenTelemetry by pkg/telemetry. Each Start method returns the context to
+// run the step in and a function to call with the step's outcome once it
+// ends.
+type Telemetry interface {
+	// StartSwitch starts a switch to env. result may be nil if the switch
+	// failed before it began, such as on validation.
+	StartSwitch(ctx context.Context, env string) (context.Context, func(result *SwitchResult, err error))
+	// StartService starts switching service.
+	StartService(ctx context.Context, service string) (context.Context, func(err error))
+	// StartHook starts running the hook named hook, such as "pre-hook-0"
+	// or "activate-dev".
+	StartHook(ctx context.Context, hook string) (context.Context, func(err error))
+}
+
+// SetTelemetry reports switches, other than dry runs, to telemetry. Without
+// it, switches do no telemetry work at all.
+func (es *EnvironmentSwitcher) SetTelemetry(telemetry Telemetry) {
+	es.telemetry = telemetry
+}
+
+// telemetryKey is the context key of the Telemetry reporting a switch.
+type telemetryKey struct{}
+
+// startSwitchTelemetry starts reporting a switch to env, if telemetry is
+// set and this is not a dry run, and returns the context for the switch and
+// the function that ends the report. Services and hooks run in the
+// returned context are reported as part of the switch.
+func (es *EnvironmentSwitcher) startSwitchTelemetry(ctx context.Context, env string, options SwitchOptions) (context.Context, func(*SwitchResult, error)) {
+	if es.telemetry == nil || options.DryRun {
+		return ctx, func(*SwitchResult, error) {}
+	}
+	ctx, end := es.telemetry.StartSwitch(ctx, env)
+	return context.WithValue(ctx, telemetryKey{}, es.telemetry), end
+}
+
+// telemetryFrom returns the Telemetry reporting the switch ctx belongs to,
+// or nil if it is not reported.
+func telemetryFrom(ctx context.Context) Telemetry {
+	telemetry, _ := ctx.Value(telemetryKey{}).(Telemetry)
+	return telemetry
+}
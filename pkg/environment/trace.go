@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TraceCommandsEnvVar enables the command trace when --trace-commands is not
+// given: "1", "true", or "-" traces to standard error, and any other value
+// is the file to append to.
+const TraceCommandsEnvVar = "GZH_TRACE_COMMANDS"
+
+// CommandTrace is one line of the command trace: an external command that
+// dev-env ran and how it ended.
+type CommandTrace struct {
+	Time time.Time `json:"time"`
+	// Service is the service whose switcher ran the command, if any.
+	Service string `json:"service,omitempty"`
+	// Argv is the command and its arguments, with secrets redacted.
+	Argv []string `json:"argv"`
+	Dir  string   `json:"cwd"`
+	// DurationMS is how long the command ran, in milliseconds.
+	DurationMS int64 `json:"durationMs"`
+	// ExitCode is the command's exit status, or -1 if it did not exit
+	// normally, for example because it was not found or was killed.
+	ExitCode int `json:"exitCode"`
+	// StdoutBytes and StderrBytes are the sizes of the command's output;
+	// the output itself is not recorded, since it may hold credentials.
+	StdoutBytes int    `json:"stdoutBytes"`
+	StderrBytes int    `json:"stderrBytes"`
+	SwitchID    string `json:"switchId,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// CommandTracer writes a CommandTrace as a JSON line for every command run
+// through RunCommand, hooks, and external switchers.
+type CommandTracer struct {
+	mu  sync.Mutex
+	w   io.Writer
+	dir func() (string, error)
+}
+
+// NewCommandTracer traces commands to w.
+func NewCommandTracer(w io.Writer) *CommandTracer {
+	return &CommandTracer{w: w, dir: os.Getwd}
+}
+
+// commandTracer is the active tracer, or nil when tracing is off.
+var commandTracer atomic.Pointer[CommandTracer]
+
+// SetCommandTracer enables the command trace for the whole process, or
+// disables it if tracer is nil.
+func SetCommandTracer(tracer *CommandTracer) {
+	commandTracer.Store(tracer)
+}
+
+// record writes trace as one JSON line. Write errors are ignored: the trace
+// is a debugging aid and must not fail the command it describes.
+func (t *CommandTracer) record(trace CommandTrace) {
+	if dir, err := t.dir(); err == nil {
+		trace.Dir = dir
+	}
+	data, err := json.Marshal(trace)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	_, _ = t.w.Write(append(data, '\n'))
+}
+
+// serviceKey is the context key of the service whose switcher is running.
+type serviceKey struct{}
+
+// withCommandService returns a context whose traced commands are attributed
+// to service.
+func withCommandService(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, serviceKey{}, service)
+}
+
+// traceCommand records a command that started at start, if tracing is on.
+func traceCommand(ctx context.Context, name string, args []string, start time.Time, stdout, stderr int, err error) {
+	tracer := commandTracer.Load()
+	if tracer == nil {
+		return
+	}
+
+	service, ok := ctx.Value(serviceKey{}).(string)
+	if !ok {
+		service = status.CheckerFromContext(ctx)
+	}
+	trace := CommandTrace{
+		Time:        start.UTC(),
+		Service:     service,
+		Argv:        status.RedactArgs(append([]string{name}, args...)),
+		DurationMS:  time.Since(start).Milliseconds(),
+		StdoutBytes: stdout,
+		StderrBytes: stderr,
+		SwitchID:    SwitchIDFromContext(ctx),
+	}
+	if err != nil {
+		trace.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			trace.ExitCode = exitErr.ExitCode()
+		}
+		trace.Error = status.SanitizeValue(err.Error(), status.DefaultMaxDetailSize)
+	}
+	tracer.record(trace)
+}
+
+// OpenCommandTrace returns the writer a --trace-commands or
+// GZH_TRACE_COMMANDS value selects: standard error for "1", "true", or "-",
+// otherwise the named file, opened for appending and left open for the
+// tracer to write to.
+func OpenCommandTrace(spec string) (io.Writer, error) {
+	switch spec {
+	case "1", "true", "-":
+		return os.Stderr, nil
+	}
+
+	// #nosec G304 - The trace file is chosen by the user running dev-env
+	f, err := os.OpenFile(spec, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open command trace: %w", err)
+	}
+	return f, nil
+}
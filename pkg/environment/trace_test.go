@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TestCommandTrace tests the schema of trace lines and that secrets in
+// arguments are redacted.
+func TestCommandTrace(t *testing.T) {
+	var buf bytes.Buffer
+	SetCommandTracer(NewCommandTracer(&buf))
+	t.Cleanup(func() { SetCommandTracer(nil) })
+
+	ctx := withCommandService(withSwitchID(context.Background(), "01SWITCH"), "docker")
+	if _, err := RunCommand(ctx, "sh", "-c", "printf out; printf error >&2", "docker", "login", "--password", "hunter2"); err != nil {
+		t.Fatalf("RunCommand() error = %v", err)
+	}
+	if _, err := RunCommand(context.Background(), "sh", "-c", "exit 3"); err == nil {
+		t.Fatal("RunCommand() should fail for exit status 3")
+	}
+	_, _ = RunCommand(context.Background(), "dev-env-no-such-command")
+
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("trace contains the password:\n%s", buf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("trace has %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &fields); err != nil {
+		t.Fatalf("trace line is not JSON: %v", err)
+	}
+	for _, key := range []string{"time", "service", "argv", "cwd", "durationMs", "exitCode", "stdoutBytes", "stderrBytes", "switchId"} {
+		if _, ok := fields[key]; !ok {
+			t.Errorf("trace line %s has no %q field", lines[0], key)
+		}
+	}
+
+	var traces []CommandTrace
+	for _, line := range lines {
+		var trace CommandTrace
+		if err := json.Unmarshal([]byte(line), &trace); err != nil {
+			t.Fatalf("trace line is not JSON: %v", err)
+		}
+		traces = append(traces, trace)
+	}
+
+	first := traces[0]
+	wd, _ := os.Getwd()
+	if first.Service != "docker" || first.SwitchID != "01SWITCH" || first.Dir != wd {
+		t.Errorf("trace = %+v, want service docker, switch 01SWITCH, cwd %s", first, wd)
+	}
+	if got := strings.Join(first.Argv, " "); !strings.HasSuffix(got, "docker login --password (redacted)") {
+		t.Errorf("Argv = %q, want the password redacted", got)
+	}
+	if first.ExitCode != 0 || first.StdoutBytes != 3 || first.StderrBytes != 5 {
+		t.Errorf("trace = exit %d, stdout %d, stderr %d, want 0, 3, 5", first.ExitCode, first.StdoutBytes, first.StderrBytes)
+	}
+
+	if traces[1].ExitCode != 3 || traces[1].Error == "" {
+		t.Errorf("failed command trace = %+v, want exit code 3 and an error", traces[1])
+	}
+	if traces[2].ExitCode != -1 {
+		t.Errorf("missing command exit code = %d, want -1", traces[2].ExitCode)
+	}
+}
+
+// commandChecker is a status checker that reads its version from standard
+// error, like AWS CLI v1.
+type commandChecker struct {
+	version string
+}
+
+func (c *commandChecker) Name() string { return "aws" }
+
+func (c *commandChecker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
+	output, err := RunCombinedCommand(ctx, "sh", "-c", "printf aws-cli/1.18.69 >&2")
+	if err != nil {
+		return nil, err
+	}
+	c.version = string(output)
+	return &status.ServiceStatus{Name: "aws", Status: status.StatusActive}, nil
+}
+
+func (c *commandChecker) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
+	return &status.HealthStatus{Status: status.StatusActive}, nil
+}
+
+// TestCommandTrace_Checker tests that commands run by status checkers are
+// traced and attributed to their service.
+func TestCommandTrace_Checker(t *testing.T) {
+	var buf bytes.Buffer
+	SetCommandTracer(NewCommandTracer(&buf))
+	t.Cleanup(func() { SetCommandTracer(nil) })
+
+	checker := &commandChecker{}
+	collector := status.NewStatusCollector([]status.ServiceChecker{checker}, time.Minute)
+	if _, err := collector.CollectAll(context.Background(), status.StatusOptions{}); err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if checker.version != "aws-cli/1.18.69" {
+		t.Errorf("RunCombinedCommand() output = %q, want the standard error", checker.version)
+	}
+
+	var trace CommandTrace
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatalf("trace is not one JSON line: %v\n%s", err, buf.String())
+	}
+	if trace.Service != "aws" {
+		t.Errorf("Service = %q, want aws", trace.Service)
+	}
+}
+
+// TestOpenCommandTrace tests selecting standard error or a trace file.
+func TestOpenCommandTrace(t *testing.T) {
+	for _, spec := range []string{"1", "true", "-"} {
+		if w, err := OpenCommandTrace(spec); err != nil || w != os.Stderr {
+			t.Errorf("OpenCommandTrace(%q) = %v, %v, want standard error", spec, w, err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "trace.jsonl")
+	w, err := OpenCommandTrace(path)
+	if err != nil {
+		t.Fatalf("OpenCommandTrace() error = %v", err)
+	}
+	defer w.(*os.File).Close()
+	if _, err := w.Write([]byte("{}\n")); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("trace file mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o600))
+	}
+
+	if _, err := OpenCommandTrace(filepath.Join(t.TempDir(), "missing", "trace.jsonl")); err == nil {
+		t.Error("OpenCommandTrace() should fail for a file in a missing directory")
+	}
+}
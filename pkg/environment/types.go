@@ -10,11 +10,25 @@ import (
 // Environment represents a complete development environment configuration.
 type Environment struct {
 	Name         string                   `yaml:"name"`
+	Aliases      []string                 `yaml:"aliases,omitempty"`
 	Description  string                   `yaml:"description"`
 	Services     map[string]ServiceConfig `yaml:"services"`
 	Dependencies []string                 `yaml:"dependencies"`
 	PreHooks     []Hook                   `yaml:"preHooks,omitempty"`
 	PostHooks    []Hook                   `yaml:"postHooks,omitempty"`
+
+	// Compose lists environments whose services, dependencies, and hooks are
+	// merged into this one by ExpandComposition.
+	Compose []string `yaml:"compose,omitempty"`
+
+	// Activate runs after the environment's services are switched; Deactivate
+	// runs when switching away from it to another environment.
+	Activate   string `yaml:"activate,omitempty"`
+	Deactivate string `yaml:"deactivate,omitempty"`
+
+	// Timeout bounds the whole switch (YAML: "timeout: 10m"). It applies when
+	// SwitchOptions.Timeout is unset; when both are set, the smaller wins.
+	Timeout time.Duration `yaml:"timeout,omitempty"`
 }
 
 // ServiceConfig contains configuration for a specific service.
@@ -25,6 +39,16 @@ type ServiceConfig struct {
 	Docker     *DockerConfig     `yaml:"docker,omitempty"`
 	Kubernetes *KubernetesConfig `yaml:"kubernetes,omitempty"`
 	SSH        *SSHConfig        `yaml:"ssh,omitempty"`
+	GPG        *GPGConfig        `yaml:"gpg,omitempty"`
+	PkgMgr     *PkgMgrConfig     `yaml:"pkgmgr,omitempty"`
+
+	// Extra configures services without a built-in configuration type, such
+	// as those switched by a CommandSwitcher.
+	Extra ExtraConfig `yaml:"extra,omitempty"`
+
+	// Enabled set to false keeps the service's block in the file but skips
+	// it when switching; see Environment.EnabledServices. Nil means enabled.
+	Enabled *bool `yaml:"enabled,omitempty"`
 }
 
 // AWSConfig represents AWS service configuration.
@@ -39,12 +63,19 @@ type GCPConfig struct {
 	Project string `yaml:"project"`
 	Account string `yaml:"account,omitempty"`
 	Region  string `yaml:"region,omitempty"`
+	// ImpersonationChain lists service accounts to impersonate, in delegation
+	// order; the last entry is the target account.
+	ImpersonationChain []string `yaml:"impersonationChain,omitempty"`
 }
 
 // AzureConfig represents Azure service configuration.
 type AzureConfig struct {
 	Subscription string `yaml:"subscription"`
 	Tenant       string `yaml:"tenant,omitempty"`
+	// Cloud is the Azure cloud the subscription lives in, such as
+	// AzureCloud or AzureUSGovernment, as listed by `az cloud list`. It is
+	// left unchanged if empty.
+	Cloud string `yaml:"cloud,omitempty"`
 }
 
 // DockerConfig represents Docker service configuration.
@@ -58,20 +89,87 @@ type KubernetesConfig struct {
 	Namespace string `yaml:"namespace,omitempty"`
 }
 
+// ExtraConfig holds the string values configuring a service without a
+// built-in configuration type.
+type ExtraConfig map[string]string
+
 // SSHConfig represents SSH service configuration.
 type SSHConfig struct {
 	Config string `yaml:"config"`
+	// StartAgent starts ssh-agent when none is reachable through
+	// SSH_AUTH_SOCK.
+	StartAgent bool `yaml:"startAgent,omitempty"`
+	// Identities are private key files loaded into the agent with ssh-add.
+	Identities []string `yaml:"identities,omitempty"`
+}
+
+// GPGConfig represents the commit-signing identity set in the global git
+// configuration.
+type GPGConfig struct {
+	// SigningKey is the key git signs commits with (user.signingkey), such
+	// as a key ID or fingerprint in the GPG keyring.
+	SigningKey string `yaml:"signingKey,omitempty"`
+	// Email is the commit email (user.email), which should be a user ID of
+	// the signing key.
+	Email string `yaml:"email,omitempty"`
+	// SignCommits sets commit.gpgsign. If unset, commits are signed when a
+	// SigningKey is given.
+	SignCommits *bool `yaml:"signCommits,omitempty"`
+}
+
+// PkgMgrConfig represents the package registries npm and Go fetch from.
+// npm settings are kept in ~/.npmrc, which yarn 1 reads too; Go settings
+// are set with `go env -w`.
+type PkgMgrConfig struct {
+	// NpmRegistry is the default npm registry, such as
+	// "https://npm.example.com/".
+	NpmRegistry string `yaml:"npmRegistry,omitempty"`
+	// NpmScopeRegistries maps npm scopes, such as "@acme", to the registry
+	// their packages come from.
+	NpmScopeRegistries map[string]string `yaml:"npmScopeRegistries,omitempty"`
+	// GoPrivate sets GOPRIVATE, the module path patterns fetched directly
+	// and not checked against the checksum database. GONOPROXY and
+	// GONOSUMDB default to it.
+	GoPrivate string `yaml:"goPrivate,omitempty"`
+	// GoProxy sets GOPROXY.
+	GoProxy string `yaml:"goProxy,omitempty"`
 }
 
 // Hook represents a command to execute before or after environment switching.
 type Hook struct {
+	// Name identifies the hook in RunAfter, results, and errors. Unnamed
+	// hooks are called "pre-hook-<i>" or "post-hook-<i>" after their index.
+	Name    string        `yaml:"name,omitempty"`
 	Command string        `yaml:"command"`
 	Timeout time.Duration `yaml:"timeout,omitempty"`
 	OnError string        `yaml:"onError,omitempty"` // continue, fail, rollback
+	// RunAfter names hooks of the same list that must run first. The hook
+	// is skipped if one of them fails.
+	RunAfter []string `yaml:"runAfter,omitempty"`
+	// OnlyIfSwitched skips the post-hook unless one of these services was
+	// switched.
+	OnlyIfSwitched []string `yaml:"onlyIfSwitched,omitempty"`
+	// OnlyIfChanged skips the post-hook unless one of these services was
+	// switched to a configuration different from its previous state.
+	OnlyIfChanged []string `yaml:"onlyIfChanged,omitempty"`
+}
+
+// SkippedHook is a hook that did not run during a switch.
+type SkippedHook struct {
+	Hook   string `json:"hook"`
+	Reason string `json:"reason"`
+}
+
+// SkippedService is a service not switched in partial-success mode because
+// a service it depends on failed or was itself skipped.
+type SkippedService struct {
+	Service string `json:"service"`
+	Reason  string `json:"reason"`
 }
 
 // SwitchProgress represents the progress of environment switching.
 type SwitchProgress struct {
+	SwitchID          string        `json:"switchId"`
 	TotalServices     int           `json:"totalServices"`
 	CompletedServices int           `json:"completedServices"`
 	CurrentService    string        `json:"currentService"`
@@ -81,21 +179,68 @@ type SwitchProgress struct {
 	Errors            []SwitchError `json:"errors,omitempty"`
 }
 
-// SwitchError represents an error during environment switching.
+// ServiceEventType identifies a per-service switch event.
+type ServiceEventType string
+
+const (
+	ServiceStarted   ServiceEventType = "started"
+	ServiceCompleted ServiceEventType = "completed"
+	ServiceFailed    ServiceEventType = "failed"
+)
+
+// ServiceEvent reports the start or end of switching a single service.
+type ServiceEvent struct {
+	SwitchID string           `json:"switchId"`
+	Service  string           `json:"service"`
+	Type     ServiceEventType `json:"type"`
+	Time     time.Time        `json:"time"`
+	Duration time.Duration    `json:"duration,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
+
+// SwitchError represents an error during environment switching. Detail
+// holds what the failing CLI wrote to standard error, if anything.
 type SwitchError struct {
 	Service string    `json:"service"`
 	Error   string    `json:"error"`
+	Detail  string    `json:"detail,omitempty"`
 	Time    time.Time `json:"time"`
+	// Continued marks a failure the switch went on past, such as a hook
+	// whose OnError is "continue".
+	Continued bool `json:"continued,omitempty"`
 }
 
 // SwitchResult represents the result of environment switching.
 type SwitchResult struct {
+	// SwitchID identifies the switch in progress events, history, and hook
+	// environments; see SwitchOptions.SwitchID.
+	SwitchID          string        `json:"switchId"`
 	Success           bool          `json:"success"`
+	Partial           bool          `json:"partial"`
 	SwitchedServices  []string      `json:"switchedServices"`
 	FailedServices    []string      `json:"failedServices"`
 	RollbackPerformed bool          `json:"rollbackPerformed"`
 	Duration          time.Duration `json:"duration"`
 	Errors            []SwitchError `json:"errors,omitempty"`
+
+	// RollbackComplete reports whether every service was rolled back. A
+	// performed but incomplete rollback leaves a mixed state; see MixedState.
+	RollbackComplete bool `json:"rollbackComplete"`
+	// RolledBackServices are the services restored to their previous
+	// state, sorted.
+	RolledBackServices []string `json:"rolledBackServices,omitempty"`
+	// RollbackErrors lists the services that could not be rolled back.
+	RollbackErrors []SwitchError `json:"rollbackErrors,omitempty"`
+	// FileSnapshot is the directory holding the files copied before the
+	// switch, if file snapshots are enabled; see FileSnapshots.
+	FileSnapshot string `json:"fileSnapshot,omitempty"`
+	// SkippedHooks are the hooks not run because of their conditions or a
+	// failed hook, in the order they would have run.
+	SkippedHooks []SkippedHook `json:"skippedHooks,omitempty"`
+	// SkippedServices are the services not switched in partial-success
+	// mode because a service they depend on failed, in the order they
+	// would have been switched.
+	SkippedServices []SkippedService `json:"skippedServices,omitempty"`
 }
 
 // SwitchOptions contains options for environment switching.
@@ -105,6 +250,25 @@ type SwitchOptions struct {
 	Parallel        bool
 	RollbackOnError bool
 	Timeout         time.Duration
+	// AllowRoot permits switching (and running hooks) with an effective UID of 0.
+	AllowRoot bool
+	// PartialSuccess keeps switching the remaining services after a failure.
+	// The switch only fails if every service fails; otherwise the result is
+	// successful with Partial set and the failures listed in Errors.
+	PartialSuccess bool
+	// SkipValidation skips the ValueValidator pre-flight check, e.g. for an
+	// AWS region newer than the built-in list.
+	SkipValidation bool
+	// SwitchID identifies the switch, e.g. to trace it across systems.
+	// A new ID from NewSwitchID is used if empty.
+	SwitchID string
+	// StrictHooks makes the result unsuccessful if a hook whose OnError is
+	// "continue" failed. The remaining hooks and services still run.
+	StrictHooks bool
+	// Overrides are the path=value overrides applied to the environment
+	// with Environment.ApplyOverrides. They are recorded in the switch
+	// history so the switch can be reproduced.
+	Overrides []string
 }
 
 // ServiceGroup represents a group of services that can be executed in parallel.
@@ -251,3 +251,30 @@ func TestServiceGroup_Fields(t *testing.T) {
 		t.Error("Level mismatch")
 	}
 }
+
+// TestServiceConfig_IsEmpty tests empty service config detection.
+func TestServiceConfig_IsEmpty(t *testing.T) {
+	tests := []struct {
+		name   string
+		config ServiceConfig
+		want   bool
+	}{
+		{name: "no config", config: ServiceConfig{}, want: true},
+		{name: "aws", config: ServiceConfig{AWS: &AWSConfig{}}, want: false},
+		{name: "gcp", config: ServiceConfig{GCP: &GCPConfig{}}, want: false},
+		{name: "azure", config: ServiceConfig{Azure: &AzureConfig{}}, want: false},
+		{name: "docker", config: ServiceConfig{Docker: &DockerConfig{}}, want: false},
+		{name: "kubernetes", config: ServiceConfig{Kubernetes: &KubernetesConfig{}}, want: false},
+		{name: "ssh", config: ServiceConfig{SSH: &SSHConfig{}}, want: false},
+		{name: "gpg", config: ServiceConfig{GPG: &GPGConfig{}}, want: false},
+		{name: "pkgmgr", config: ServiceConfig{PkgMgr: &PkgMgrConfig{}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.config.IsEmpty(); got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
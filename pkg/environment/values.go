@@ -0,0 +1,291 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultAWSRegions are the AWS regions ValueValidator accepts unless
+// ValueValidator.AWSRegions overrides them.
+var DefaultAWSRegions = []string{
+	"af-south-1",
+	"ap-east-1", "ap-east-2",
+	"ap-northeast-1", "ap-northeast-2", "ap-northeast-3",
+	"ap-south-1", "ap-south-2",
+	"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ap-southeast-4",
+	"ap-southeast-5", "ap-southeast-6", "ap-southeast-7",
+	"ca-central-1", "ca-west-1",
+	"cn-north-1", "cn-northwest-1",
+	"eu-central-1", "eu-central-2",
+	"eu-north-1",
+	"eu-south-1", "eu-south-2",
+	"eu-west-1", "eu-west-2", "eu-west-3",
+	"il-central-1",
+	"me-central-1", "me-south-1",
+	"mx-central-1",
+	"sa-east-1",
+	"us-east-1", "us-east-2",
+	"us-gov-east-1", "us-gov-west-1",
+	"us-west-1", "us-west-2",
+}
+
+var (
+	// gcpRegionPattern matches GCP regions such as us-central1 or
+	// northamerica-northeast2.
+	gcpRegionPattern = regexp.MustCompile(`^[a-z]+-[a-z]+[0-9]+$`)
+	// gcpZonePattern matches GCP zones, a region plus a zone letter.
+	gcpZonePattern = regexp.MustCompile(`^([a-z]+-[a-z]+[0-9]+)-[a-z]$`)
+	// rfc1123LabelPattern matches Kubernetes namespace names.
+	rfc1123LabelPattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	// dockerContextPattern matches the names Docker accepts for contexts.
+	dockerContextPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.+-]*$`)
+	// uuidPattern matches Azure subscription and tenant IDs.
+	uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	// uuidLikePattern matches values meant as a UUID: only hex digits and
+	// dashes, with at least one dash.
+	uuidLikePattern = regexp.MustCompile(`^[0-9a-fA-F]+(-[0-9a-fA-F]*)+$`)
+)
+
+// ValueError reports a service configuration value that breaks a rule.
+type ValueError struct {
+	Service    string
+	Field      string
+	Value      string
+	Rule       string
+	Suggestion string
+}
+
+// Error implements the error interface.
+func (e ValueError) Error() string {
+	msg := fmt.Sprintf("%s.%s: '%s' %s", e.Service, e.Field, e.Value, e.Rule)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(" (did you mean '%s'?)", e.Suggestion)
+	}
+	return msg
+}
+
+// ValueErrors lists every invalid value found in an environment.
+type ValueErrors []ValueError
+
+// Error implements the error interface.
+func (e ValueErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ValueValidator checks service values, such as regions and namespaces,
+// that the CLIs would otherwise only reject mid-switch with cryptic errors.
+type ValueValidator struct {
+	// AWSRegions are the accepted AWS regions, DefaultAWSRegions if empty.
+	// Extend it for regions launched after this list was written.
+	AWSRegions []string
+}
+
+// Validate checks the values of env's enabled services. It returns nil or
+// a ValueErrors listing every violation, ordered by service.
+func (v ValueValidator) Validate(env *Environment) error {
+	services := env.EnabledServices()
+	names := make([]string, 0, len(services))
+	for name := range services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs ValueErrors
+	for _, name := range names {
+		config := services[name]
+		if config.AWS != nil && config.AWS.Region != "" {
+			errs = append(errs, v.checkAWSRegion(name, config.AWS.Region)...)
+		}
+		if config.GCP != nil && config.GCP.Region != "" {
+			errs = append(errs, checkGCPRegion(name, config.GCP.Region)...)
+		}
+		if config.Azure != nil {
+			errs = append(errs, checkAzure(name, config.Azure)...)
+		}
+		if config.Docker != nil && config.Docker.Context != "" && !dockerContextPattern.MatchString(config.Docker.Context) {
+			errs = append(errs, ValueError{
+				Service: name,
+				Field:   "context",
+				Value:   config.Docker.Context,
+				Rule:    "is not a valid Docker context name (letters, digits, and _.+- only, not starting with a symbol)",
+			})
+		}
+		if config.Kubernetes != nil && config.Kubernetes.Namespace != "" {
+			errs = append(errs, checkNamespace(name, config.Kubernetes.Namespace)...)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// checkAWSRegion checks region against the known AWS regions.
+func (v ValueValidator) checkAWSRegion(service, region string) []ValueError {
+	regions := v.AWSRegions
+	if len(regions) == 0 {
+		regions = DefaultAWSRegions
+	}
+	for _, known := range regions {
+		if region == known {
+			return nil
+		}
+	}
+	return []ValueError{{
+		Service:    service,
+		Field:      "region",
+		Value:      region,
+		Rule:       "is not a known AWS region",
+		Suggestion: closest(region, regions),
+	}}
+}
+
+// checkGCPRegion checks the format of a GCP region, pointing out zones.
+func checkGCPRegion(service, region string) []ValueError {
+	if gcpRegionPattern.MatchString(region) {
+		return nil
+	}
+	if m := gcpZonePattern.FindStringSubmatch(region); m != nil {
+		return []ValueError{{
+			Service:    service,
+			Field:      "region",
+			Value:      region,
+			Rule:       "is a GCP zone, not a region",
+			Suggestion: m[1],
+		}}
+	}
+	return []ValueError{{
+		Service: service,
+		Field:   "region",
+		Value:   region,
+		Rule:    "is not a valid GCP region (expected a name like us-central1)",
+	}}
+}
+
+// checkAzure checks that IDs meant as UUIDs are well formed. A subscription
+// that does not look like a UUID is taken to be a subscription name.
+func checkAzure(service string, config *AzureConfig) []ValueError {
+	var errs []ValueError
+	for _, field := range []struct{ name, value string }{
+		{"subscription", config.Subscription},
+		{"tenant", config.Tenant},
+	} {
+		if field.value == "" || uuidPattern.MatchString(field.value) || !uuidLikePattern.MatchString(field.value) {
+			continue
+		}
+		errs = append(errs, ValueError{
+			Service: service,
+			Field:   field.name,
+			Value:   field.value,
+			Rule:    "looks like an ID but is not a valid UUID (expected 8-4-4-4-12 hex digits)",
+		})
+	}
+	if len(config.Subscription) > 64 && !uuidPattern.MatchString(config.Subscription) {
+		errs = append(errs, ValueError{
+			Service: service,
+			Field:   "subscription",
+			Value:   config.Subscription,
+			Rule:    "is too long for a subscription name (max 64 characters)",
+		})
+	}
+	return errs
+}
+
+// checkNamespace checks a Kubernetes namespace against RFC 1123 label rules.
+func checkNamespace(service, namespace string) []ValueError {
+	if len(namespace) <= 63 && rfc1123LabelPattern.MatchString(namespace) {
+		return nil
+	}
+
+	err := ValueError{
+		Service: service,
+		Field:   "namespace",
+		Value:   namespace,
+		Rule:    "is not a valid RFC 1123 label (lowercase letters, digits, and '-', at most 63 characters, starting and ending with a letter or digit)",
+	}
+	if lower := strings.ToLower(namespace); lower != namespace && rfc1123LabelPattern.MatchString(lower) && len(lower) <= 63 {
+		err.Suggestion = lower
+	}
+	return []ValueError{err}
+}
+
+// NormalizeValues trims surrounding whitespace from service values and
+// lowercases AWS and GCP regions, which the CLIs treat case-insensitively
+// in some places and not in others.
+func (e *Environment) NormalizeValues() {
+	for name, config := range e.Services {
+		if c := config.AWS; c != nil {
+			c.Profile = strings.TrimSpace(c.Profile)
+			c.Region = strings.ToLower(strings.TrimSpace(c.Region))
+			c.AccountID = strings.TrimSpace(c.AccountID)
+		}
+		if c := config.GCP; c != nil {
+			c.Project = strings.TrimSpace(c.Project)
+			c.Account = strings.TrimSpace(c.Account)
+			c.Region = strings.ToLower(strings.TrimSpace(c.Region))
+		}
+		if c := config.Azure; c != nil {
+			c.Subscription = strings.TrimSpace(c.Subscription)
+			c.Tenant = strings.TrimSpace(c.Tenant)
+			c.Cloud = strings.TrimSpace(c.Cloud)
+		}
+		if c := config.Docker; c != nil {
+			c.Context = strings.TrimSpace(c.Context)
+		}
+		if c := config.Kubernetes; c != nil {
+			c.Context = strings.TrimSpace(c.Context)
+			c.Namespace = strings.TrimSpace(c.Namespace)
+		}
+		e.Services[name] = config
+	}
+}
+
+// closest returns the candidate nearest to value by edit distance, if it is
+// close enough to be a likely typo.
+func closest(value string, candidates []string) string {
+	best, bestDistance := "", len(value)/3+1
+	for _, candidate := range candidates {
+		if d := editDistance(value, candidate); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Damerau-Levenshtein (optimal string alignment)
+// distance between a and b, so a swapped pair of letters counts as one edit.
+func editDistance(a, b string) int {
+	rows, cols := len(a)+1, len(b)+1
+	d := make([][]int, rows)
+	for i := range d {
+		d[i] = make([]int, cols)
+		d[i][0] = i
+	}
+	for j := 0; j < cols; j++ {
+		d[0][j] = j
+	}
+
+	for i := 1; i < rows; i++ {
+		for j := 1; j < cols; j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[rows-1][cols-1]
+}
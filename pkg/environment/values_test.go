@@ -0,0 +1,207 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package environment
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestValueValidator_Validate tests the value rules of each service type.
+func TestValueValidator_Validate(t *testing.T) {
+	tests := []struct {
+		name       string
+		validator  ValueValidator
+		config     ServiceConfig
+		wantErr    string
+		suggestion string
+	}{
+		{
+			name:   "known aws region",
+			config: ServiceConfig{AWS: &AWSConfig{Region: "us-east-1"}},
+		},
+		{
+			name:       "aws region typo",
+			config:     ServiceConfig{AWS: &AWSConfig{Region: "us-esat-1"}},
+			wantErr:    "is not a known AWS region",
+			suggestion: "us-east-1",
+		},
+		{
+			name:    "unrelated aws region",
+			config:  ServiceConfig{AWS: &AWSConfig{Region: "mars-north-9"}},
+			wantErr: "is not a known AWS region",
+		},
+		{
+			name:      "aws region from settings",
+			validator: ValueValidator{AWSRegions: append([]string{"xx-new-1"}, DefaultAWSRegions...)},
+			config:    ServiceConfig{AWS: &AWSConfig{Region: "xx-new-1"}},
+		},
+		{
+			name:   "gcp region",
+			config: ServiceConfig{GCP: &GCPConfig{Region: "northamerica-northeast2"}},
+		},
+		{
+			name:       "gcp zone as region",
+			config:     ServiceConfig{GCP: &GCPConfig{Region: "us-central1-a"}},
+			wantErr:    "is a GCP zone",
+			suggestion: "us-central1",
+		},
+		{
+			name:    "gcp region format",
+			config:  ServiceConfig{GCP: &GCPConfig{Region: "us_central"}},
+			wantErr: "is not a valid GCP region",
+		},
+		{
+			name:   "azure subscription uuid",
+			config: ServiceConfig{Azure: &AzureConfig{Subscription: "0b1f6471-1bf0-4dda-aec3-cb9272f09590"}},
+		},
+		{
+			name:   "azure subscription name",
+			config: ServiceConfig{Azure: &AzureConfig{Subscription: "Pay-As-You-Go"}},
+		},
+		{
+			name:    "malformed azure tenant",
+			config:  ServiceConfig{Azure: &AzureConfig{Tenant: "0b1f6471-1bf0-4dda-aec3"}},
+			wantErr: "is not a valid UUID",
+		},
+		{
+			name:    "azure subscription name too long",
+			config:  ServiceConfig{Azure: &AzureConfig{Subscription: strings.Repeat("a", 65)}},
+			wantErr: "is too long",
+		},
+		{
+			name:   "docker context",
+			config: ServiceConfig{Docker: &DockerConfig{Context: "desktop-linux"}},
+		},
+		{
+			name:    "invalid docker context",
+			config:  ServiceConfig{Docker: &DockerConfig{Context: "-bad"}},
+			wantErr: "is not a valid Docker context name",
+		},
+		{
+			name:   "namespace",
+			config: ServiceConfig{Kubernetes: &KubernetesConfig{Namespace: "my-app"}},
+		},
+		{
+			name:       "uppercase namespace",
+			config:     ServiceConfig{Kubernetes: &KubernetesConfig{Namespace: "MyApp"}},
+			wantErr:    "is not a valid RFC 1123 label",
+			suggestion: "myapp",
+		},
+		{
+			name:    "namespace too long",
+			config:  ServiceConfig{Kubernetes: &KubernetesConfig{Namespace: strings.Repeat("a", 64)}},
+			wantErr: "is not a valid RFC 1123 label",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := &Environment{Name: "dev", Services: map[string]ServiceConfig{"svc": tt.config}}
+			err := tt.validator.Validate(env)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var errs ValueErrors
+			if !errors.As(err, &errs) || len(errs) != 1 {
+				t.Fatalf("Validate() error = %v, want one ValueError", err)
+			}
+			if !strings.Contains(errs[0].Rule, tt.wantErr) {
+				t.Errorf("Rule = %q, want it to contain %q", errs[0].Rule, tt.wantErr)
+			}
+			if errs[0].Suggestion != tt.suggestion {
+				t.Errorf("Suggestion = %q, want %q", errs[0].Suggestion, tt.suggestion)
+			}
+		})
+	}
+}
+
+// TestValueValidator_Validate_DisabledService tests that disabled services are not checked.
+func TestValueValidator_Validate_DisabledService(t *testing.T) {
+	disabled := false
+	env := &Environment{
+		Name: "dev",
+		Services: map[string]ServiceConfig{
+			"aws": {Enabled: &disabled, AWS: &AWSConfig{Region: "us-esat-1"}},
+		},
+	}
+
+	if err := (ValueValidator{}).Validate(env); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+// TestValueError_Error tests the message format.
+func TestValueError_Error(t *testing.T) {
+	err := ValueError{Service: "aws", Field: "region", Value: "us-esat-1", Rule: "is not a known AWS region", Suggestion: "us-east-1"}
+	want := "aws.region: 'us-esat-1' is not a known AWS region (did you mean 'us-east-1'?)"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+// TestEnvironment_NormalizeValues tests trimming and region lowercasing.
+func TestEnvironment_NormalizeValues(t *testing.T) {
+	env := &Environment{
+		Services: map[string]ServiceConfig{
+			"aws":        {AWS: &AWSConfig{Profile: " dev ", Region: " US-East-1\n"}},
+			"gcp":        {GCP: &GCPConfig{Project: "proj ", Region: "US-Central1"}},
+			"kubernetes": {Kubernetes: &KubernetesConfig{Context: " kind", Namespace: "default "}},
+		},
+	}
+
+	env.NormalizeValues()
+
+	tests := []struct {
+		field string
+		got   string
+		want  string
+	}{
+		{"aws profile", env.Services["aws"].AWS.Profile, "dev"},
+		{"aws region", env.Services["aws"].AWS.Region, "us-east-1"},
+		{"gcp project", env.Services["gcp"].GCP.Project, "proj"},
+		{"gcp region", env.Services["gcp"].GCP.Region, "us-central1"},
+		{"kubernetes context", env.Services["kubernetes"].Kubernetes.Context, "kind"},
+		{"kubernetes namespace", env.Services["kubernetes"].Kubernetes.Namespace, "default"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %q, want %q", tt.field, tt.got, tt.want)
+		}
+	}
+}
+
+// TestEnvironmentSwitcher_ValueValidation tests that invalid values stop a switch unless validation is skipped.
+func TestEnvironmentSwitcher_ValueValidation(t *testing.T) {
+	env := &Environment{
+		Name:     "dev",
+		Services: map[string]ServiceConfig{"aws": {AWS: &AWSConfig{Profile: "dev", Region: "us-esat-1"}}},
+	}
+
+	es := newTestSwitcher()
+	mock := newMockSwitcher("aws")
+	es.Register(mock)
+
+	_, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{})
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'us-east-1'") {
+		t.Fatalf("SwitchEnvironment() error = %v, want a region suggestion", err)
+	}
+	if mock.switchCalled {
+		t.Error("SwitchEnvironment() should not switch services with invalid values")
+	}
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, SwitchOptions{SkipValidation: true}); err != nil {
+		t.Fatalf("SwitchEnvironment() with SkipValidation error = %v", err)
+	}
+	if !mock.switchCalled {
+		t.Error("SwitchEnvironment() with SkipValidation should switch aws")
+	}
+}
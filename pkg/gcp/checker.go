@@ -5,21 +5,36 @@ package gcp
 
 import (
 	"context"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
+// DefaultMaxKeyAge is the default age after which a user-managed service
+// account key is reported by the key age check.
+const DefaultMaxKeyAge = 90 * 24 * time.Hour
+
 // Checker implements status.ServiceChecker for Google Cloud Platform.
-type Checker struct{}
+type Checker struct {
+	// CheckKeyAge makes CheckHealth list the user-managed keys of the active
+	// service account and warn about keys older than MaxKeyAge.
+	CheckKeyAge bool
+	// MaxKeyAge overrides DefaultMaxKeyAge when positive.
+	MaxKeyAge time.Duration
+
+	run environment.CommandRunner
+	now func() time.Time
+}
 
 // NewChecker creates a new GCP status checker.
 func NewChecker() *Checker {
-	return &Checker{}
+	return &Checker{run: environment.RunCommand, now: time.Now}
 }
 
 // Name returns the service name.
@@ -27,6 +42,11 @@ func (g *Checker) Name() string {
 	return "gcp"
 }
 
+// Category returns the service's category for grouping and selection.
+func (g *Checker) Category() status.Category {
+	return status.CategoryCloud
+}
+
 // CheckStatus checks GCP current status.
 func (g *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
 	st := &status.ServiceStatus{
@@ -41,10 +61,15 @@ func (g *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if gcloud CLI is available
 	if !g.isCLIAvailable() {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
 		st.Details["error"] = "gcloud CLI not found"
 		return st, nil
 	}
 
+	if version := g.getCLIVersion(ctx); version != "" {
+		st.Details["cli_version"] = version
+	}
+
 	// Get current project
 	project, err := g.getCurrentProject(ctx)
 	if err != nil {
@@ -55,6 +80,7 @@ func (g *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 
 	if project == "" {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotConfigured
 		st.Details["error"] = "No GCP project configured"
 		return st, nil
 	}
@@ -86,6 +112,7 @@ func (g *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 		st.Status = status.StatusActive
 	} else {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonCredentialsInvalid
 	}
 
 	return st, nil
@@ -101,16 +128,14 @@ func (g *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	}
 
 	// Test GCP connectivity with gcloud auth list
-	cmd := exec.CommandContext(ctx, "gcloud", "auth", "list", "--format=json")
-	output, err := cmd.Output()
+	output, err := g.runner()(ctx, "gcloud", "auth", "list", "--format=json")
 	health.Duration = time.Since(start)
 
 	if err != nil {
 		health.Status = status.StatusError
 		health.Message = fmt.Sprintf("Failed to check GCP authentication: %v", err)
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			health.Details["stderr"] = string(exitErr.Stderr)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
 		}
 		return health, nil
 	}
@@ -119,43 +144,171 @@ func (g *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	health.Message = "GCP credentials are valid and accessible"
 	health.Details["auth_list"] = string(output)
 
+	if g.CheckKeyAge {
+		g.checkKeyAge(ctx, health)
+	}
+
 	return health, nil
 }
 
+// serviceAccountKey is an entry of `gcloud iam service-accounts keys list`.
+type serviceAccountKey struct {
+	Name           string `json:"name"`
+	KeyType        string `json:"keyType"`
+	ValidAfterTime string `json:"validAfterTime"`
+}
+
+// checkKeyAge adds a key_age_warning detail when the active account is a
+// service account with user-managed keys older than the maximum age.
+// Failures are recorded as key_age_error and do not affect health.
+func (g *Checker) checkKeyAge(ctx context.Context, health *status.HealthStatus) {
+	output, err := g.runner()(ctx, "gcloud", "config", "get-value", "account")
+	if err != nil {
+		health.Details["key_age_error"] = fmt.Sprintf("failed to get account: %v", err)
+		return
+	}
+	account := parseConfigValue(string(output))
+	if !strings.HasSuffix(account, ".iam.gserviceaccount.com") {
+		return
+	}
+
+	output, err = g.runner()(ctx, "gcloud", "iam", "service-accounts", "keys", "list",
+		"--iam-account="+account, "--managed-by=user", "--format", "json")
+	if err != nil {
+		health.Details["key_age_error"] = fmt.Sprintf("failed to list keys for %s: %v", account, err)
+		return
+	}
+
+	maxAge := g.MaxKeyAge
+	if maxAge <= 0 {
+		maxAge = DefaultMaxKeyAge
+	}
+	now := time.Now()
+	if g.now != nil {
+		now = g.now()
+	}
+
+	old, err := oldKeys(output, maxAge, now)
+	if err != nil {
+		health.Details["key_age_error"] = err.Error()
+		return
+	}
+	if len(old) > 0 {
+		health.Details["key_age_warning"] = fmt.Sprintf("%d user-managed key(s) of %s older than %d days: %s",
+			len(old), account, int(maxAge.Hours()/24), strings.Join(old, ", "))
+	}
+}
+
+// oldKeys parses `gcloud iam service-accounts keys list --format json`
+// output and describes the user-managed keys older than maxAge at now,
+// oldest first, as "KEY_ID (N days)".
+func oldKeys(output []byte, maxAge time.Duration, now time.Time) ([]string, error) {
+	var keys []serviceAccountKey
+	if err := json.Unmarshal(output, &keys); err != nil {
+		return nil, fmt.Errorf("failed to parse service account keys: %w", err)
+	}
+
+	type aged struct {
+		id  string
+		age time.Duration
+	}
+	var old []aged
+	for _, key := range keys {
+		if key.KeyType != "" && key.KeyType != "USER_MANAGED" {
+			continue
+		}
+		validAfter, err := time.Parse(time.RFC3339, key.ValidAfterTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid validAfterTime for key %s: %w", key.Name, err)
+		}
+		if age := now.Sub(validAfter); age > maxAge {
+			old = append(old, aged{id: key.Name[strings.LastIndex(key.Name, "/")+1:], age: age})
+		}
+	}
+
+	sort.Slice(old, func(i, j int) bool { return old[i].age > old[j].age })
+	descriptions := make([]string, 0, len(old))
+	for _, key := range old {
+		descriptions = append(descriptions, fmt.Sprintf("%s (%d days)", key.id, int(key.age.Hours()/24)))
+	}
+	return descriptions, nil
+}
+
+// runner returns the checker's command runner, defaulting to RunCommand
+// for checkers built without NewChecker.
+func (g *Checker) runner() environment.CommandRunner {
+	if g.run == nil {
+		return environment.RunCommand
+	}
+	return g.run
+}
+
 // isCLIAvailable checks if gcloud CLI is installed.
 func (g *Checker) isCLIAvailable() bool {
 	_, err := exec.LookPath("gcloud")
 	return err == nil
 }
 
+// getCLIVersion returns the installed Google Cloud SDK version, or an empty string.
+func (g *Checker) getCLIVersion(ctx context.Context) string {
+	output, err := g.runner()(ctx, "gcloud", "--version")
+	if err != nil {
+		return ""
+	}
+	return parseCLIVersion(string(output))
+}
+
+// parseCLIVersion extracts the SDK version from `gcloud --version` output,
+// whose first line reads "Google Cloud SDK 450.0.0".
+func parseCLIVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		if version, ok := strings.CutPrefix(strings.TrimSpace(line), "Google Cloud SDK "); ok {
+			return strings.TrimSpace(version)
+		}
+	}
+	return ""
+}
+
+// parseConfigValue normalizes `gcloud config get-value` output.
+// Older SDKs print "(unset)" on stdout for missing properties and may mix in
+// informational lines such as "Your active configuration is: [default]",
+// while newer SDKs print only the value and send notices to stderr.
+func parseConfigValue(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line == "(unset)" || strings.HasPrefix(line, "Your active configuration is") {
+			continue
+		}
+		return line
+	}
+	return ""
+}
+
 // getCurrentProject gets the current GCP project.
 func (g *Checker) getCurrentProject(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "gcloud", "config", "get-value", "project")
-	output, err := cmd.Output()
+	output, err := g.runner()(ctx, "gcloud", "config", "get-value", "project")
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(string(output)), nil
+	return parseConfigValue(string(output)), nil
 }
 
 // getCurrentAccount gets the current GCP account.
 func (g *Checker) getCurrentAccount(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "gcloud", "config", "get-value", "account")
-	output, err := cmd.Output()
+	output, err := g.runner()(ctx, "gcloud", "config", "get-value", "account")
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(string(output)), nil
+	return parseConfigValue(string(output)), nil
 }
 
 // getCurrentRegion gets the current GCP region.
 func (g *Checker) getCurrentRegion(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "gcloud", "config", "get-value", "compute/region")
-	output, err := cmd.Output()
+	output, err := g.runner()(ctx, "gcloud", "config", "get-value", "compute/region")
 	if err != nil {
 		return "", err
 	}
-	return strings.TrimSpace(string(output)), nil
+	return parseConfigValue(string(output)), nil
 }
 
 // checkCredentials checks GCP credentials validity.
@@ -166,8 +319,7 @@ func (g *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatu
 	}
 
 	// Test credentials with gcloud auth application-default print-access-token
-	cmd := exec.CommandContext(ctx, "gcloud", "auth", "print-access-token")
-	err := cmd.Run()
+	_, err := g.runner()(ctx, "gcloud", "auth", "print-access-token")
 	if err != nil {
 		credStatus.Warning = "Credentials invalid or expired"
 		return credStatus, nil
@@ -176,10 +328,9 @@ func (g *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatu
 	credStatus.Valid = true
 
 	// Check if using service account
-	cmd = exec.CommandContext(ctx, "gcloud", "config", "get-value", "account")
-	output, err := cmd.Output()
+	output, err := g.runner()(ctx, "gcloud", "config", "get-value", "account")
 	if err == nil {
-		account := strings.TrimSpace(string(output))
+		account := parseConfigValue(string(output))
 		if strings.Contains(account, ".iam.gserviceaccount.com") {
 			credStatus.Type = "service-account"
 		} else {
@@ -187,5 +338,10 @@ func (g *Checker) checkCredentials(ctx context.Context) (*status.CredentialStatu
 		}
 	}
 
+	// The access token gcloud hands out expires, typically after an hour
+	if output, err := g.runner()(ctx, "gcloud", "config", "config-helper", "--format=json"); err == nil {
+		_ = credStatus.SetExpiry(status.GCPTokenExpiry, output)
+	}
+
 	return credStatus, nil
 }
@@ -5,9 +5,12 @@ package gcp
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -188,3 +191,180 @@ func TestChecker_CheckStatus_ContextCanceled(t *testing.T) {
 		t.Error("CheckStatus() should return non-nil status even with canceled context")
 	}
 }
+
+// TestParseCLIVersion tests version parsing of `gcloud --version` output.
+func TestParseCLIVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "current output",
+			output: "Google Cloud SDK 450.0.0\nbq 2.0.98\ncore 2023.10.13\ngsutil 5.27\n",
+			want:   "450.0.0",
+		},
+		{
+			name:   "legacy output",
+			output: "Google Cloud SDK 206.0.0\nalpha 2018.06.18\nbeta 2018.06.18\n",
+			want:   "206.0.0",
+		},
+		{
+			name:   "unrecognized output",
+			output: "",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCLIVersion(tt.output); got != tt.want {
+				t.Errorf("parseCLIVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseConfigValue tests normalization of old and new get-value output.
+func TestParseConfigValue(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{
+			name:   "current output",
+			output: "my-project\n",
+			want:   "my-project",
+		},
+		{
+			name:   "current output unset",
+			output: "\n",
+			want:   "",
+		},
+		{
+			name:   "legacy output unset",
+			output: "(unset)\n",
+			want:   "",
+		},
+		{
+			name:   "legacy output with active configuration notice",
+			output: "Your active configuration is: [default]\nmy-project\n",
+			want:   "my-project",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseConfigValue(tt.output); got != tt.want {
+				t.Errorf("parseConfigValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeGcloud returns canned output keyed by the joined command line.
+type fakeGcloud struct {
+	outputs map[string]string
+	calls   []string
+}
+
+func (f *fakeGcloud) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	line := name + " " + strings.Join(args, " ")
+	f.calls = append(f.calls, line)
+	output, ok := f.outputs[line]
+	if !ok {
+		return nil, fmt.Errorf("unexpected command: %s", line)
+	}
+	return []byte(output), nil
+}
+
+// TestChecker_CheckHealth_KeyAge tests the service account key age check.
+func TestChecker_CheckHealth_KeyAge(t *testing.T) {
+	const sa = "deployer@proj.iam.gserviceaccount.com"
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	keys := `[
+  {"name": "projects/proj/serviceAccounts/` + sa + `/keys/fresh", "keyType": "USER_MANAGED", "validAfterTime": "2025-05-01T00:00:00Z"},
+  {"name": "projects/proj/serviceAccounts/` + sa + `/keys/old", "keyType": "USER_MANAGED", "validAfterTime": "2025-01-01T00:00:00Z"},
+  {"name": "projects/proj/serviceAccounts/` + sa + `/keys/system", "keyType": "SYSTEM_MANAGED", "validAfterTime": "2024-01-01T00:00:00Z"}
+]`
+
+	tests := []struct {
+		name        string
+		checkKeyAge bool
+		account     string
+		wantWarning string
+		wantListing bool
+	}{
+		{
+			name:        "disabled",
+			checkKeyAge: false,
+			account:     sa,
+		},
+		{
+			name:        "user account skipped",
+			checkKeyAge: true,
+			account:     "dev@example.com",
+		},
+		{
+			name:        "old key reported",
+			checkKeyAge: true,
+			account:     sa,
+			wantWarning: "1 user-managed key(s) of " + sa + " older than 90 days: old (151 days)",
+			wantListing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeGcloud{outputs: map[string]string{
+				"gcloud auth list --format=json":  "[]",
+				"gcloud config get-value account": tt.account + "\n",
+				"gcloud iam service-accounts keys list --iam-account=" + sa + " --managed-by=user --format json": keys,
+			}}
+			checker := &Checker{CheckKeyAge: tt.checkKeyAge, run: fake.run, now: func() time.Time { return now }}
+
+			health, err := checker.CheckHealth(context.Background())
+			if err != nil {
+				t.Fatalf("CheckHealth() error = %v", err)
+			}
+			if health.Status != status.StatusActive {
+				t.Errorf("CheckHealth().Status = %v, want active", health.Status)
+			}
+
+			got, _ := health.Details["key_age_warning"].(string)
+			if got != tt.wantWarning {
+				t.Errorf("key_age_warning = %q, want %q", got, tt.wantWarning)
+			}
+			if errDetail, ok := health.Details["key_age_error"]; ok {
+				t.Errorf("unexpected key_age_error: %v", errDetail)
+			}
+
+			listed := false
+			for _, call := range fake.calls {
+				if strings.Contains(call, "keys list") {
+					listed = true
+				}
+			}
+			if listed != tt.wantListing {
+				t.Errorf("listed keys = %v, want %v (calls: %v)", listed, tt.wantListing, fake.calls)
+			}
+		})
+	}
+}
+
+// TestOldKeys_InvalidOutput tests parsing failures.
+func TestOldKeys_InvalidOutput(t *testing.T) {
+	if _, err := oldKeys([]byte("not json"), DefaultMaxKeyAge, time.Now()); err == nil {
+		t.Error("oldKeys() should fail on invalid JSON")
+	}
+	if _, err := oldKeys([]byte(`[{"name": "k", "validAfterTime": "yesterday"}]`), DefaultMaxKeyAge, time.Now()); err == nil {
+		t.Error("oldKeys() should fail on an invalid timestamp")
+	}
+}
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
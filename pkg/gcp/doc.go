@@ -3,5 +3,6 @@
 //
 // This package implements:
 //   - GCPSwitcher: Switches GCP projects, regions, and service accounts
-//   - GCPChecker: Checks GCP service status and health
+//   - GCPChecker: Checks GCP service status, health, and optionally the age
+//     of user-managed service account keys
 package gcp
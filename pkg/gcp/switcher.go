@@ -5,19 +5,40 @@ package gcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
-	"os/exec"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 )
 
+// MaxImpersonationChainLength is the maximum number of service accounts in an
+// impersonation chain accepted by the GCP IAM Credentials API.
+const MaxImpersonationChainLength = 5
+
+// impersonatedCredentialsType is the type of application default
+// credentials that impersonate a service account.
+const impersonatedCredentialsType = "impersonated_service_account"
+
 // Switcher implements environment.ServiceSwitcher for GCP.
-type Switcher struct{}
+type Switcher struct {
+	run environment.CommandRunner
+}
 
 // NewSwitcher creates a new GCP switcher.
 func NewSwitcher() *Switcher {
-	return &Switcher{}
+	return &Switcher{run: environment.RunCommand}
+}
+
+// runner returns the switcher's command runner, defaulting to RunCommand
+// for switchers built without NewSwitcher.
+func (g *Switcher) runner() environment.CommandRunner {
+	if g.run == nil {
+		return environment.RunCommand
+	}
+	return g.run
 }
 
 // Name returns the service name.
@@ -25,6 +46,30 @@ func (g *Switcher) Name() string {
 	return "gcp"
 }
 
+// Claims returns the shared resources the switcher modifies: the gcloud
+// configuration and the application default credentials in it.
+func (g *Switcher) Claims() []string {
+	return []string{environment.ClaimGCloudConfig, environment.ClaimGCloudADC}
+}
+
+// Validate checks that config is a GCP configuration with a valid
+// impersonation chain, without running gcloud.
+func (g *Switcher) Validate(config interface{}) error {
+	gcpConfig, ok := config.(*environment.GCPConfig)
+	if !ok {
+		return fmt.Errorf("invalid GCP configuration type")
+	}
+	if gcpConfig == nil {
+		return fmt.Errorf("no GCP configuration provided")
+	}
+	return ValidateImpersonationChain(gcpConfig.ImpersonationChain)
+}
+
+// RequiredCLIs returns the gcloud CLI, which every switch runs.
+func (g *Switcher) RequiredCLIs(interface{}) []string {
+	return []string{"gcloud"}
+}
+
 // Switch switches to the specified GCP configuration.
 func (g *Switcher) Switch(ctx context.Context, config interface{}) error {
 	gcpConfig, ok := config.(*environment.GCPConfig)
@@ -32,55 +77,150 @@ func (g *Switcher) Switch(ctx context.Context, config interface{}) error {
 		return fmt.Errorf("invalid GCP configuration type")
 	}
 
+	if err := ValidateImpersonationChain(gcpConfig.ImpersonationChain); err != nil {
+		return err
+	}
+
 	// Set GCP project
 	if gcpConfig.Project != "" {
-		cmd := exec.CommandContext(ctx, "gcloud", "config", "set", "project", gcpConfig.Project)
-		if err := cmd.Run(); err != nil {
+		if _, err := g.runner()(ctx, "gcloud", "config", "set", "project", gcpConfig.Project); err != nil {
 			return fmt.Errorf("failed to set GCP project: %w", err)
 		}
 	}
 
 	// Set GCP account
 	if gcpConfig.Account != "" {
-		cmd := exec.CommandContext(ctx, "gcloud", "config", "set", "account", gcpConfig.Account)
-		if err := cmd.Run(); err != nil {
+		if _, err := g.runner()(ctx, "gcloud", "config", "set", "account", gcpConfig.Account); err != nil {
 			return fmt.Errorf("failed to set GCP account: %w", err)
 		}
 	}
 
 	// Set GCP region
 	if gcpConfig.Region != "" {
-		cmd := exec.CommandContext(ctx, "gcloud", "config", "set", "compute/region", gcpConfig.Region)
-		if err := cmd.Run(); err != nil {
+		if _, err := g.runner()(ctx, "gcloud", "config", "set", "compute/region", gcpConfig.Region); err != nil {
 			return fmt.Errorf("failed to set GCP region: %w", err)
 		}
 	}
 
+	// Log application default credentials in through the impersonation chain
+	if len(gcpConfig.ImpersonationChain) > 0 {
+		if _, err := g.runner()(ctx, "gcloud", impersonationArgs(gcpConfig.ImpersonationChain)...); err != nil { // #nosec G204 - chain validated above
+			return fmt.Errorf("failed to log in with GCP impersonation chain: %w", err)
+		}
+	}
+
 	return nil
 }
 
-// GetCurrentState retrieves the current GCP configuration state.
+// ValidateImpersonationChain checks that a chain is within the GCP hop limit
+// and contains no empty entries.
+func ValidateImpersonationChain(chain []string) error {
+	if len(chain) > MaxImpersonationChainLength {
+		return fmt.Errorf("impersonation chain has %d service accounts (max %d)", len(chain), MaxImpersonationChainLength)
+	}
+	for i, account := range chain {
+		if strings.TrimSpace(account) == "" {
+			return fmt.Errorf("impersonation chain entry %d is empty", i)
+		}
+		if strings.Contains(account, ",") {
+			return fmt.Errorf("impersonation chain entry %d contains a comma: %s", i, account)
+		}
+	}
+	return nil
+}
+
+// impersonationArgs builds the gcloud arguments that log application default
+// credentials in as the last service account of chain, through the
+// intermediate service accounts before it as delegates.
+func impersonationArgs(chain []string) []string {
+	target := chain[len(chain)-1]
+	args := []string{"auth", "application-default", "login", "--impersonate-service-account", target}
+	if delegates := chain[:len(chain)-1]; len(delegates) > 0 {
+		args = append(args, "--delegates", strings.Join(delegates, ","))
+	}
+	return args
+}
+
+// adcPath returns the application default credentials file written by
+// gcloud auth application-default login, in the gcloud configuration
+// directory, which CLOUDSDK_CONFIG can move.
+func adcPath() string {
+	if dir := os.Getenv("CLOUDSDK_CONFIG"); dir != "" {
+		return filepath.Join(dir, "application_default_credentials.json")
+	}
+	path, _ := environment.ClaimPath(environment.ClaimGCloudADC)
+	return path
+}
+
+// adcCredentials is the part of an application default credentials file
+// that records impersonation.
+type adcCredentials struct {
+	Type string `json:"type"`
+	// ImpersonationURL ends in "serviceAccounts/<target>:generateAccessToken".
+	ImpersonationURL string   `json:"service_account_impersonation_url"`
+	Delegates        []string `json:"delegates"`
+}
+
+// parseImpersonationChain returns the impersonation chain of application
+// default credentials: the delegates followed by the target service
+// account. It is nil for credentials that do not impersonate.
+func parseImpersonationChain(data []byte) []string {
+	var creds adcCredentials
+	if err := json.Unmarshal(data, &creds); err != nil || creds.Type != impersonatedCredentialsType {
+		return nil
+	}
+
+	_, target, ok := strings.Cut(creds.ImpersonationURL, "/serviceAccounts/")
+	target, _, _ = strings.Cut(target, ":")
+	if !ok || target == "" {
+		return nil
+	}
+	return append(append([]string{}, creds.Delegates...), target)
+}
+
+// GetCurrentState retrieves the current GCP configuration state. Unset
+// properties are left empty, so a rollback does not set them to "(unset)".
 func (g *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 	// Get current GCP project
-	cmd := exec.CommandContext(ctx, "gcloud", "config", "get-value", "project")
-	projectOutput, _ := cmd.Output()
+	projectOutput, _ := g.runner()(ctx, "gcloud", "config", "get-value", "project")
 
 	// Get current GCP account
-	cmd = exec.CommandContext(ctx, "gcloud", "config", "get-value", "account")
-	accountOutput, _ := cmd.Output()
+	accountOutput, _ := g.runner()(ctx, "gcloud", "config", "get-value", "account")
 
 	// Get current GCP region
-	cmd = exec.CommandContext(ctx, "gcloud", "config", "get-value", "compute/region")
-	regionOutput, _ := cmd.Output()
+	regionOutput, _ := g.runner()(ctx, "gcloud", "config", "get-value", "compute/region")
+
+	// Get current impersonation chain from the application default credentials
+	adc, _ := os.ReadFile(adcPath())
 
 	return &environment.GCPConfig{
-		Project: strings.TrimSpace(string(projectOutput)),
-		Account: strings.TrimSpace(string(accountOutput)),
-		Region:  strings.TrimSpace(string(regionOutput)),
+		Project:            parseConfigValue(string(projectOutput)),
+		Account:            parseConfigValue(string(accountOutput)),
+		Region:             parseConfigValue(string(regionOutput)),
+		ImpersonationChain: parseImpersonationChain(adc),
 	}, nil
 }
 
-// Rollback rolls back to the previous GCP configuration.
+// Rollback rolls back to the previous GCP configuration. A previous
+// impersonation chain is logged in again; credentials that did not
+// impersonate cannot be recreated without the user, so they are left to
+// the file snapshot of the application default credentials.
 func (g *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
 	return g.Switch(ctx, previousState)
 }
+
+// ListTargets returns the available Google Cloud projects.
+func (g *Switcher) ListTargets(ctx context.Context) ([]string, error) {
+	output, err := g.runner()(ctx, "gcloud", "projects", "list", "--format", "value(projectId)")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GCP projects: %w", err)
+	}
+
+	var targets []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if target := strings.TrimSpace(line); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
@@ -5,6 +5,10 @@ package gcp
 
 import (
 	"context"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +34,10 @@ func TestSwitcher_Name(t *testing.T) {
 // TestSwitcher_ImplementsInterface verifies Switcher implements ServiceSwitcher.
 func TestSwitcher_ImplementsInterface(t *testing.T) {
 	var _ environment.ServiceSwitcher = (*Switcher)(nil)
+	var _ environment.ResourceClaimer = (*Switcher)(nil)
+	var _ environment.TargetLister = (*Switcher)(nil)
+	var _ environment.ConfigValidator = (*Switcher)(nil)
+	var _ environment.CLIRequirer = (*Switcher)(nil)
 }
 
 // TestSwitcher_Switch_InvalidConfigType tests error handling for invalid config type.
@@ -181,3 +189,169 @@ func TestSwitcher_GetCurrentState_ReturnsGCPConfig(t *testing.T) {
 	t.Logf("Current GCP account: %s", gcpConfig.Account)
 	t.Logf("Current GCP region: %s", gcpConfig.Region)
 }
+
+// TestSwitcher_GetCurrentState_Unset tests that properties older SDKs
+// print as "(unset)" are captured as empty rather than as that text.
+func TestSwitcher_GetCurrentState_Unset(t *testing.T) {
+	t.Setenv("CLOUDSDK_CONFIG", t.TempDir())
+	fake := &fakeGcloud{outputs: map[string]string{
+		"gcloud config get-value project":        "(unset)\n",
+		"gcloud config get-value account":        "Your active configuration is: [default]\ndev@example.com\n",
+		"gcloud config get-value compute/region": "Your active configuration is: [default]\n(unset)\n",
+	}}
+	switcher := &Switcher{run: fake.run}
+
+	state, err := switcher.GetCurrentState(context.Background())
+	if err != nil {
+		t.Fatalf("GetCurrentState() error = %v", err)
+	}
+	want := &environment.GCPConfig{Account: "dev@example.com"}
+	if !reflect.DeepEqual(state, want) {
+		t.Errorf("GetCurrentState() = %+v, want %+v", state, want)
+	}
+
+	// Rolling back to it sets only the account.
+	fake.outputs["gcloud config set account dev@example.com"] = ""
+	fake.calls = nil
+	if err := switcher.Rollback(context.Background(), state); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if want := []string{"gcloud config set account dev@example.com"}; !reflect.DeepEqual(fake.calls, want) {
+		t.Errorf("Rollback() ran %v, want %v", fake.calls, want)
+	}
+}
+
+// TestValidateImpersonationChain tests chain length and entry validation.
+func TestValidateImpersonationChain(t *testing.T) {
+	sa := func(n int) string { return fmt.Sprintf("sa%d@proj.iam.gserviceaccount.com", n) }
+
+	tests := []struct {
+		name      string
+		chain     []string
+		wantError bool
+	}{
+		{name: "empty chain", chain: nil, wantError: false},
+		{name: "single hop", chain: []string{sa(1)}, wantError: false},
+		{name: "five hops", chain: []string{sa(1), sa(2), sa(3), sa(4), sa(5)}, wantError: false},
+		{name: "six hops", chain: []string{sa(1), sa(2), sa(3), sa(4), sa(5), sa(6)}, wantError: true},
+		{name: "empty entry", chain: []string{sa(1), " "}, wantError: true},
+		{name: "comma in entry", chain: []string{sa(1) + "," + sa(2)}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateImpersonationChain(tt.chain)
+			if (err != nil) != tt.wantError {
+				t.Errorf("ValidateImpersonationChain() error = %v, wantError = %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestSwitcher_Switch_ImpersonationChainTooLong tests that long chains are rejected before any change.
+func TestSwitcher_Switch_ImpersonationChainTooLong(t *testing.T) {
+	switcher := NewSwitcher()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	config := &environment.GCPConfig{
+		Project:            "test-project",
+		ImpersonationChain: []string{"a", "b", "c", "d", "e", "f"},
+	}
+
+	if err := switcher.Switch(ctx, config); err == nil {
+		t.Error("Switch() should reject an impersonation chain longer than 5")
+	}
+}
+
+// TestSwitcher_Validate tests checking configurations without gcloud.
+func TestSwitcher_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    interface{}
+		wantError bool
+	}{
+		{name: "valid", config: &environment.GCPConfig{Project: "test-project"}, wantError: false},
+		{name: "wrong type", config: &environment.AWSConfig{}, wantError: true},
+		{name: "nil", config: (*environment.GCPConfig)(nil), wantError: true},
+		{name: "long chain", config: &environment.GCPConfig{ImpersonationChain: []string{"a", "b", "c", "d", "e", "f"}}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSwitcher().Validate(tt.config)
+			if (err != nil) != tt.wantError {
+				t.Errorf("Validate() error = %v, wantError = %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+// TestImpersonationArgs tests passing the intermediate service accounts as
+// delegates of the target account.
+func TestImpersonationArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		chain []string
+		want  []string
+	}{
+		{
+			name:  "single",
+			chain: []string{"target@p.iam.gserviceaccount.com"},
+			want:  []string{"auth", "application-default", "login", "--impersonate-service-account", "target@p.iam.gserviceaccount.com"},
+		},
+		{
+			name:  "chain",
+			chain: []string{"a@p.iam.gserviceaccount.com", "b@p.iam.gserviceaccount.com", "target@p.iam.gserviceaccount.com"},
+			want: []string{"auth", "application-default", "login", "--impersonate-service-account", "target@p.iam.gserviceaccount.com",
+				"--delegates", "a@p.iam.gserviceaccount.com,b@p.iam.gserviceaccount.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := impersonationArgs(tt.chain); strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("impersonationArgs() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseImpersonationChain tests reading the current chain for rollback
+// from the application default credentials.
+func TestParseImpersonationChain(t *testing.T) {
+	const url = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/target@p.iam.gserviceaccount.com:generateAccessToken"
+
+	tests := []struct {
+		name string
+		adc  string
+		want []string
+	}{
+		{name: "missing", adc: "", want: nil},
+		{name: "user credentials", adc: `{"type": "authorized_user", "client_id": "x"}`, want: nil},
+		{name: "single", adc: `{"type": "impersonated_service_account", "service_account_impersonation_url": "` + url + `"}`, want: []string{"target@p.iam.gserviceaccount.com"}},
+		{
+			name: "chain",
+			adc:  `{"type": "impersonated_service_account", "service_account_impersonation_url": "` + url + `", "delegates": ["a@p.iam.gserviceaccount.com", "b@p.iam.gserviceaccount.com"]}`,
+			want: []string{"a@p.iam.gserviceaccount.com", "b@p.iam.gserviceaccount.com", "target@p.iam.gserviceaccount.com"},
+		},
+		{name: "no target", adc: `{"type": "impersonated_service_account"}`, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseImpersonationChain([]byte(tt.adc))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") || len(got) != len(tt.want) {
+				t.Errorf("parseImpersonationChain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestADCPath tests locating the application default credentials.
+func TestADCPath(t *testing.T) {
+	t.Setenv("CLOUDSDK_CONFIG", "/tmp/gcloud")
+	if got, want := adcPath(), filepath.Join("/tmp/gcloud", "application_default_credentials.json"); got != want {
+		t.Errorf("adcPath() = %q, want %q", got, want)
+	}
+}
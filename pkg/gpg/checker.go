@@ -0,0 +1,171 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package gpg
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// Checker implements status.ServiceChecker for the GPG commit-signing
+// identity.
+type Checker struct{}
+
+// NewChecker creates a new GPG status checker.
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// Name returns the service name.
+func (c *Checker) Name() string {
+	return "gpg"
+}
+
+// Category returns the service's category for grouping and selection.
+func (c *Checker) Category() status.Category {
+	return status.CategoryAccess
+}
+
+// CheckStatus checks which key git signs commits with, whether it is in
+// the GPG keyring, and when it expires.
+func (c *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
+	st := &status.ServiceStatus{
+		Name:        "gpg",
+		Status:      status.StatusUnknown,
+		Current:     status.CurrentConfig{},
+		Credentials: status.CredentialStatus{},
+		LastUsed:    time.Now(),
+		Details:     make(map[string]string),
+	}
+
+	// Check if GPG is available
+	if !c.isCLIAvailable() {
+		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
+		st.Details["error"] = "GPG not found"
+		return st, nil
+	}
+
+	// Get the signing key git is configured to use
+	key, err := getGitConfig(ctx, "--get", keySigningKey)
+	if err != nil {
+		st.Status = status.StatusError
+		st.Details["error"] = fmt.Sprintf("Failed to read git configuration: %v", err)
+		return st, nil
+	}
+	if key == "" {
+		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotConfigured
+		st.Details["error"] = "No signing key configured in git"
+		return st, nil
+	}
+
+	st.Current.Context = key
+	email, _ := getGitConfig(ctx, "--get", keyEmail)
+	st.Current.Account = email
+	sign, _ := getGitConfig(ctx, "--type=bool", "--get", keyGPGSign)
+	if sign == "" {
+		sign = "false"
+	}
+	st.Details["commit_gpgsign"] = sign
+
+	// Look the key up in the keyring
+	output, err := listSecretKeys(ctx, key)
+	keys := parseSecretKeys(output)
+	if err != nil || len(keys) == 0 {
+		st.Status = status.StatusError
+		st.Reason = status.ReasonCredentialsInvalid
+		st.Details["error"] = fmt.Sprintf("Signing key %s not found in the GPG keyring", key)
+		return st, nil
+	}
+
+	signingKey := keys[0]
+	st.Details["fingerprint"] = signingKey.Fingerprint
+	if len(signingKey.UserIDs) > 0 {
+		st.Details["user_id"] = signingKey.UserIDs[0]
+	}
+	st.Credentials = c.checkKey(signingKey, email, output)
+
+	if !st.Credentials.Valid {
+		st.Status = status.StatusError
+		st.Reason = status.ReasonCredentialsInvalid
+		return st, nil
+	}
+	st.Status = status.StatusActive
+	return st, nil
+}
+
+// checkKey returns the credential status of the signing key, listed in
+// output, used with the commit email.
+func (c *Checker) checkKey(key secretKey, email string, output []byte) status.CredentialStatus {
+	credStatus := status.CredentialStatus{
+		Valid: true,
+		Type:  "gpg-key",
+	}
+	_ = credStatus.SetExpiry(status.GPGKeyExpiry, output)
+
+	switch {
+	case key.revoked():
+		credStatus.Valid = false
+		credStatus.Warning = "Signing key has been revoked"
+	case key.expired() || (!credStatus.ExpiresAt.IsZero() && credStatus.ExpiresAt.Before(time.Now())):
+		credStatus.Valid = false
+		credStatus.Warning = "Signing key has expired"
+	case email != "" && !key.hasEmail(email):
+		credStatus.Warning = fmt.Sprintf("Commit email %s is not a user ID of the signing key", email)
+	}
+	return credStatus
+}
+
+// CheckHealth performs detailed health check for GPG.
+func (c *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
+	start := time.Now()
+	health := &status.HealthStatus{
+		Status:    status.StatusUnknown,
+		CheckedAt: start,
+		Details:   make(map[string]interface{}),
+	}
+
+	output, err := environment.RunCommand(ctx, "gpg", "--version")
+	if err != nil {
+		health.Duration = time.Since(start)
+		health.Status = status.StatusError
+		health.Message = fmt.Sprintf("Failed to run gpg: %v", err)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
+		}
+		return health, nil
+	}
+	health.Details["version"] = strings.SplitN(strings.TrimSpace(string(output)), "\n", 2)[0]
+
+	// Check the configured signing key is in the keyring
+	key, _ := getGitConfig(ctx, "--get", keySigningKey)
+	if key == "" {
+		health.Status = status.StatusActive
+		health.Message = "GPG is installed; git has no signing key configured"
+	} else if _, err := listSecretKeys(ctx, key); err != nil {
+		health.Status = status.StatusError
+		health.Message = fmt.Sprintf("Signing key %s not found in the GPG keyring", key)
+		health.Details["signing_key"] = key
+	} else {
+		health.Status = status.StatusActive
+		health.Message = "GPG is installed and the signing key is in the keyring"
+		health.Details["signing_key"] = key
+	}
+	health.Duration = time.Since(start)
+
+	return health, nil
+}
+
+// isCLIAvailable checks if GPG is installed.
+func (c *Checker) isCLIAvailable() bool {
+	_, err := exec.LookPath("gpg")
+	return err == nil
+}
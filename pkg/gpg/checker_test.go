@@ -0,0 +1,182 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package gpg
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TestNewChecker verifies the constructor creates a valid checker.
+func TestNewChecker(t *testing.T) {
+	checker := NewChecker()
+	if checker == nil {
+		t.Fatal("NewChecker() returned nil")
+	}
+}
+
+// TestChecker_Name verifies the service name.
+func TestChecker_Name(t *testing.T) {
+	if got := NewChecker().Name(); got != "gpg" {
+		t.Errorf("Name() = %q, want %q", got, "gpg")
+	}
+}
+
+// TestChecker_ImplementsInterface verifies Checker implements ServiceChecker.
+func TestChecker_ImplementsInterface(t *testing.T) {
+	var _ status.ServiceChecker = (*Checker)(nil)
+	var _ status.Categorizer = (*Checker)(nil)
+}
+
+// TestChecker_CheckStatus tests the status reported for the configured
+// signing key.
+func TestChecker_CheckStatus(t *testing.T) {
+	keys := map[string]string{
+		"1111AAAA2222BBBB": workKey,
+		"7777AAAA8888BBBB": expiredKey,
+		"9999CCCC0000DDDD": revokedKey,
+	}
+	tests := []struct {
+		name        string
+		config      map[string]string
+		wantStatus  status.StatusType
+		wantReason  status.Reason
+		wantValid   bool
+		wantWarning string
+		wantError   string
+	}{
+		{
+			name:       "active",
+			config:     map[string]string{keySigningKey: "1111AAAA2222BBBB", keyEmail: "jane@work.example", keyGPGSign: "true"},
+			wantStatus: status.StatusActive,
+			wantValid:  true,
+		},
+		{
+			name:        "email not on key",
+			config:      map[string]string{keySigningKey: "1111AAAA2222BBBB", keyEmail: "jane@home.example"},
+			wantStatus:  status.StatusActive,
+			wantValid:   true,
+			wantWarning: "Commit email jane@home.example is not a user ID of the signing key",
+		},
+		{
+			name:       "no signing key",
+			config:     map[string]string{keyEmail: "jane@work.example"},
+			wantStatus: status.StatusInactive,
+			wantReason: status.ReasonNotConfigured,
+			wantError:  "No signing key configured in git",
+		},
+		{
+			name:       "key not in keyring",
+			config:     map[string]string{keySigningKey: "DEADBEEFDEADBEEF"},
+			wantStatus: status.StatusError,
+			wantReason: status.ReasonCredentialsInvalid,
+			wantError:  "Signing key DEADBEEFDEADBEEF not found in the GPG keyring",
+		},
+		{
+			name:        "expired key",
+			config:      map[string]string{keySigningKey: "7777AAAA8888BBBB"},
+			wantStatus:  status.StatusError,
+			wantReason:  status.ReasonCredentialsInvalid,
+			wantWarning: "Signing key has expired",
+		},
+		{
+			name:        "revoked key",
+			config:      map[string]string{keySigningKey: "9999CCCC0000DDDD"},
+			wantStatus:  status.StatusError,
+			wantReason:  status.ReasonCredentialsInvalid,
+			wantWarning: "Signing key has been revoked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installCLIs(t, keys, tt.config)
+
+			st, err := NewChecker().CheckStatus(context.Background())
+			if err != nil {
+				t.Fatalf("CheckStatus() error = %v", err)
+			}
+			if st.Status != tt.wantStatus || st.Reason != tt.wantReason {
+				t.Errorf("CheckStatus() = %v, %q, want %v, %q", st.Status, st.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if st.Credentials.Valid != tt.wantValid || st.Credentials.Warning != tt.wantWarning {
+				t.Errorf("Credentials = %+v, want valid %v, warning %q", st.Credentials, tt.wantValid, tt.wantWarning)
+			}
+			if st.Details["error"] != tt.wantError {
+				t.Errorf("Details[error] = %q, want %q", st.Details["error"], tt.wantError)
+			}
+		})
+	}
+}
+
+// TestChecker_CheckStatus_Active tests the identity and expiry reported
+// for a usable signing key.
+func TestChecker_CheckStatus_Active(t *testing.T) {
+	installCLIs(t, map[string]string{"1111AAAA2222BBBB": workKey},
+		map[string]string{keySigningKey: "1111AAAA2222BBBB", keyEmail: "jane@work.example"})
+
+	st, err := NewChecker().CheckStatus(context.Background())
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if st.Current.Context != "1111AAAA2222BBBB" || st.Current.Account != "jane@work.example" {
+		t.Errorf("Current = %+v, want the signing key and email", st.Current)
+	}
+	if want := time.Unix(4102444800, 0).UTC(); !st.Credentials.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", st.Credentials.ExpiresAt, want)
+	}
+	if st.Details["fingerprint"] != "0123456789ABCDEF01231111AAAA2222BBBB" || st.Details["user_id"] != "Jane Doe <jane@work.example>" {
+		t.Errorf("Details = %v, want the key's fingerprint and user ID", st.Details)
+	}
+	if st.Details["commit_gpgsign"] != "false" {
+		t.Errorf("Details[commit_gpgsign] = %q, want false when unset", st.Details["commit_gpgsign"])
+	}
+}
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
+
+// TestChecker_CheckHealth tests the health check with and without the
+// signing key in the keyring.
+func TestChecker_CheckHealth(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		wantStatus  status.StatusType
+		wantMessage string
+	}{
+		{"key in keyring", "1111AAAA2222BBBB", status.StatusActive, "signing key is in the keyring"},
+		{"key missing", "DEADBEEFDEADBEEF", status.StatusError, "not found in the GPG keyring"},
+		{"no key", "", status.StatusActive, "no signing key configured"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]string{}
+			if tt.key != "" {
+				config[keySigningKey] = tt.key
+			}
+			installCLIs(t, map[string]string{"1111AAAA2222BBBB": workKey}, config)
+
+			health, err := NewChecker().CheckHealth(context.Background())
+			if err != nil {
+				t.Fatalf("CheckHealth() error = %v", err)
+			}
+			if health.Status != tt.wantStatus || !strings.Contains(health.Message, tt.wantMessage) {
+				t.Errorf("CheckHealth() = %v, %q, want %v, %q", health.Status, health.Message, tt.wantStatus, tt.wantMessage)
+			}
+			if health.Details["version"] != "gpg (GnuPG) 2.4.5" {
+				t.Errorf("Details[version] = %v, want the first line of gpg --version", health.Details["version"])
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Package gpg provides implementations for switching and checking the GPG
+// identity git signs commits with.
+//
+// This package implements:
+//   - GPGSwitcher: Sets user.signingkey, user.email, and commit.gpgsign with
+//     `git config --global`, checking the key is in the keyring first
+//   - GPGChecker: Checks the configured signing key is in the keyring and
+//     when it expires
+package gpg
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package gpg
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// secretKey is a secret key listed by `gpg --list-secret-keys --with-colons`.
+type secretKey struct {
+	ID          string
+	Fingerprint string
+	// Validity is gpg's validity field: "e" for expired, "r" for revoked.
+	Validity string
+	UserIDs  []string
+}
+
+// expired reports whether gpg lists the key as expired.
+func (k secretKey) expired() bool {
+	return k.Validity == "e"
+}
+
+// revoked reports whether gpg lists the key as revoked.
+func (k secretKey) revoked() bool {
+	return k.Validity == "r"
+}
+
+// hasEmail reports whether one of the key's user IDs is for email.
+func (k secretKey) hasEmail(email string) bool {
+	for _, uid := range k.UserIDs {
+		if strings.Contains(strings.ToLower(uid), "<"+strings.ToLower(email)+">") {
+			return true
+		}
+	}
+	return false
+}
+
+// listSecretKeys runs `gpg --list-secret-keys --with-colons`, for the given
+// key only if one is given. gpg fails if the key is not in the keyring.
+func listSecretKeys(ctx context.Context, key ...string) ([]byte, error) {
+	return environment.RunCommand(ctx, "gpg", append([]string{"--list-secret-keys", "--with-colons"}, key...)...)
+}
+
+// parseSecretKeys parses the sec records of gpg's colon listing, with the
+// fingerprint and user IDs that follow each.
+func parseSecretKeys(output []byte) []secretKey {
+	var keys []secretKey
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, ":")
+		switch {
+		case fields[0] == "sec" && len(fields) > 4:
+			keys = append(keys, secretKey{ID: fields[4], Validity: fields[1]})
+		case len(keys) == 0:
+		case fields[0] == "fpr" && len(fields) > 9 && keys[len(keys)-1].Fingerprint == "":
+			keys[len(keys)-1].Fingerprint = fields[9]
+		case fields[0] == "uid" && len(fields) > 9:
+			keys[len(keys)-1].UserIDs = append(keys[len(keys)-1].UserIDs, fields[9])
+		}
+	}
+	return keys
+}
+
+// getGitConfig runs `git config` with args, such as "--get" and a key, and
+// returns the value printed. A key that is not set is "", not an error.
+func getGitConfig(ctx context.Context, args ...string) (string, error) {
+	output, err := environment.RunCommand(ctx, "git", append([]string{"config"}, args...)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package gpg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// Git configuration keys holding the commit-signing identity.
+const (
+	keySigningKey = "user.signingkey"
+	keyEmail      = "user.email"
+	keyGPGSign    = "commit.gpgsign"
+)
+
+// Switcher implements environment.ServiceSwitcher for the GPG
+// commit-signing identity, set with `git config --global`.
+type Switcher struct{}
+
+// NewSwitcher creates a new GPG switcher.
+func NewSwitcher() *Switcher {
+	return &Switcher{}
+}
+
+// Name returns the service name.
+func (s *Switcher) Name() string {
+	return "gpg"
+}
+
+// Claims returns the shared resources the switcher modifies: the global git configuration.
+func (s *Switcher) Claims() []string {
+	return []string{environment.ClaimGitConfig}
+}
+
+// Validate checks that config is a GPG configuration that sets something.
+func (s *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.GPGConfig)
+	if !ok {
+		return fmt.Errorf("invalid GPG configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no GPG configuration provided")
+	}
+	if c.SigningKey == "" && c.Email == "" && c.SignCommits == nil {
+		return fmt.Errorf("GPG configuration sets none of signingKey, email, and signCommits")
+	}
+	return nil
+}
+
+// RequiredCLIs returns git, which every switch runs, and gpg, which checks
+// the signing key is in the keyring, if a signing key is set.
+func (s *Switcher) RequiredCLIs(config interface{}) []string {
+	if c, ok := config.(*environment.GPGConfig); ok && c != nil && c.SigningKey != "" {
+		return []string{"git", "gpg"}
+	}
+	return []string{"git"}
+}
+
+// Switch sets the signing key, email, and commit signing given in config
+// in the global git configuration, leaving unset fields unchanged. It fails
+// without changing anything if the signing key is not in the GPG keyring.
+func (s *Switcher) Switch(ctx context.Context, config interface{}) error {
+	gpgConfig, ok := config.(*environment.GPGConfig)
+	if !ok || gpgConfig == nil {
+		return fmt.Errorf("invalid GPG configuration type")
+	}
+
+	if gpgConfig.SigningKey != "" {
+		if _, err := listSecretKeys(ctx, gpgConfig.SigningKey); err != nil {
+			return fmt.Errorf("signing key %s is not in the GPG keyring: %w", gpgConfig.SigningKey, err)
+		}
+		if err := setGitConfig(ctx, keySigningKey, gpgConfig.SigningKey); err != nil {
+			return err
+		}
+	}
+	if gpgConfig.Email != "" {
+		if err := setGitConfig(ctx, keyEmail, gpgConfig.Email); err != nil {
+			return err
+		}
+	}
+	if sign := signCommits(gpgConfig); sign != nil {
+		if err := setGitConfig(ctx, keyGPGSign, strconv.FormatBool(*sign)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCurrentState retrieves the signing identity in the global git
+// configuration. Keys that are not set are empty, or nil for SignCommits.
+func (s *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	key, err := getGitConfig(ctx, "--global", "--get", keySigningKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+	email, err := getGitConfig(ctx, "--global", "--get", keyEmail)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+	sign, err := getGitConfig(ctx, "--global", "--type=bool", "--get", keyGPGSign)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read git configuration: %w", err)
+	}
+
+	state := &environment.GPGConfig{SigningKey: key, Email: email}
+	if sign != "" {
+		signed := sign == "true"
+		state.SignCommits = &signed
+	}
+	return state, nil
+}
+
+// Rollback restores a state returned by GetCurrentState, unsetting the
+// keys that were not set then.
+func (s *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
+	previous, ok := previousState.(*environment.GPGConfig)
+	if !ok || previous == nil {
+		return fmt.Errorf("invalid GPG state type")
+	}
+
+	sign := ""
+	if previous.SignCommits != nil {
+		sign = strconv.FormatBool(*previous.SignCommits)
+	}
+	for _, setting := range []struct{ key, value string }{
+		{keySigningKey, previous.SigningKey},
+		{keyEmail, previous.Email},
+		{keyGPGSign, sign},
+	} {
+		var err error
+		if setting.value == "" {
+			err = unsetGitConfig(ctx, setting.key)
+		} else {
+			err = setGitConfig(ctx, setting.key, setting.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTargets returns the IDs of the secret keys that can sign: those
+// neither expired nor revoked.
+func (s *Switcher) ListTargets(ctx context.Context) ([]string, error) {
+	output, err := listSecretKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list GPG secret keys: %w", err)
+	}
+
+	var targets []string
+	for _, key := range parseSecretKeys(output) {
+		if !key.expired() && !key.revoked() {
+			targets = append(targets, key.ID)
+		}
+	}
+	return targets, nil
+}
+
+// signCommits returns the commit.gpgsign value config sets: SignCommits,
+// or true if only a signing key is given. It is nil if neither is set.
+func signCommits(config *environment.GPGConfig) *bool {
+	if config.SignCommits != nil || config.SigningKey == "" {
+		return config.SignCommits
+	}
+	signed := true
+	return &signed
+}
+
+// setGitConfig sets key to value in the global git configuration.
+func setGitConfig(ctx context.Context, key, value string) error {
+	if _, err := environment.RunCommand(ctx, "git", "config", "--global", key, value); err != nil {
+		return fmt.Errorf("failed to set git %s: %w", key, err)
+	}
+	return nil
+}
+
+// unsetGitConfig removes key from the global git configuration. A key that
+// is not set is left alone.
+func unsetGitConfig(ctx context.Context, key string) error {
+	_, err := environment.RunCommand(ctx, "git", "config", "--global", "--unset", key)
+	// git exits with status 5 when the key is not set.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 5 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to unset git %s: %w", key, err)
+	}
+	return nil
+}
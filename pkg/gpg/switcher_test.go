@@ -0,0 +1,341 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package gpg
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// Secret key listings, as printed by `gpg --list-secret-keys --with-colons`.
+const (
+	workKey = "sec:u:255:22:1111AAAA2222BBBB:1700000000:4102444800::u:::scESC:::+:::23::0:\n" +
+		"fpr:::::::::0123456789ABCDEF01231111AAAA2222BBBB:\n" +
+		"uid:u::::1700000000::HASH::Jane Doe <jane@work.example>::::::::::0:\n" +
+		"ssb:u:255:18:3333CCCC4444DDDD:1700000000:4102444800:::::e:::+:::23:\n"
+	personalKey = "sec:u:255:22:5555EEEE6666FFFF:1700000000:::u:::scESC:::+:::23::0:\n" +
+		"fpr:::::::::0123456789ABCDEF01235555EEEE6666FFFF:\n" +
+		"uid:u::::1700000000::HASH::Jane Doe <jane@home.example>::::::::::0:\n"
+	expiredKey = "sec:e:255:22:7777AAAA8888BBBB:1500000000:1600000000::u:::sc:::+:::23::0:\n" +
+		"fpr:::::::::0123456789ABCDEF01237777AAAA8888BBBB:\n" +
+		"uid:e::::1500000000::HASH::Jane Doe <jane@old.example>::::::::::0:\n"
+	revokedKey = "sec:r:255:22:9999CCCC0000DDDD:1500000000:::u:::sc:::+:::23::0:\n" +
+		"uid:r::::1500000000::HASH::Jane Doe <jane@work.example>::::::::::0:\n"
+)
+
+// fakeCLIs are fake git and gpg scripts on PATH. git keeps each
+// configuration key in a file of gitDir; gpg lists the keys in keys.
+type fakeCLIs struct {
+	gitDir string
+	log    string
+}
+
+// installCLIs puts fake git and gpg scripts on PATH. gpg knows the secret
+// keys in keys, by key ID; git starts with the configuration in config.
+func installCLIs(t *testing.T, keys map[string]string, config map[string]string) *fakeCLIs {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake git and gpg are shell scripts")
+	}
+
+	dir := t.TempDir()
+	f := &fakeCLIs{gitDir: filepath.Join(dir, "gitconfig"), log: filepath.Join(dir, "cli.log")}
+	keyDir := filepath.Join(dir, "keys")
+	for _, d := range []string{f.gitDir, keyDir} {
+		if err := os.Mkdir(d, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for id, listing := range keys {
+		if err := os.WriteFile(filepath.Join(keyDir, id), []byte(listing), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for key, value := range config {
+		f.set(t, key, value)
+	}
+
+	git := `#!/bin/sh
+echo "git $*" >> "` + f.log + `"
+[ "$1" = config ] || exit 129
+shift
+op=set
+while [ $# -gt 0 ]; do
+	case "$1" in
+	--global|--type=bool) shift ;;
+	--get) op=get; shift ;;
+	--unset) op=unset; shift ;;
+	*) break ;;
+	esac
+done
+file="` + f.gitDir + `/$1"
+case $op in
+get) [ -f "$file" ] || exit 1; cat "$file" ;;
+unset) [ -f "$file" ] || exit 5; rm "$file" ;;
+set) printf '%s\n' "$2" > "$file" ;;
+esac
+`
+	gpg := `#!/bin/sh
+echo "gpg $*" >> "` + f.log + `"
+case "$*" in
+--version) echo "gpg (GnuPG) 2.4.5"; echo "libgcrypt 1.10.3" ;;
+"--list-secret-keys --with-colons") cat "` + keyDir + `"/* 2>/dev/null ;;
+"--list-secret-keys --with-colons "*)
+	[ -f "` + keyDir + `/$3" ] || { echo "gpg: error reading key: No secret key" >&2; exit 2; }
+	cat "` + keyDir + `/$3" ;;
+*) exit 2 ;;
+esac
+`
+	for name, script := range map[string]string{"git": git, "gpg": gpg} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o700); err != nil { // #nosec G306 - test executable
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return f
+}
+
+// set sets a key in the fake git configuration.
+func (f *fakeCLIs) set(t *testing.T, key, value string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(f.gitDir, key), []byte(value+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// config returns the fake git configuration.
+func (f *fakeCLIs) config(t *testing.T) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(f.gitDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := make(map[string]string)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(f.gitDir, entry.Name())) // #nosec G304 - test file
+		if err != nil {
+			t.Fatal(err)
+		}
+		config[entry.Name()] = strings.TrimSpace(string(data))
+	}
+	return config
+}
+
+// boolPtr returns a pointer to b.
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// TestNewSwitcher verifies the constructor creates a valid switcher.
+func TestNewSwitcher(t *testing.T) {
+	switcher := NewSwitcher()
+	if switcher == nil {
+		t.Fatal("NewSwitcher() returned nil")
+	}
+}
+
+// TestSwitcher_Name verifies the service name.
+func TestSwitcher_Name(t *testing.T) {
+	if got := NewSwitcher().Name(); got != "gpg" {
+		t.Errorf("Name() = %q, want %q", got, "gpg")
+	}
+}
+
+// TestSwitcher_ImplementsInterface verifies Switcher implements ServiceSwitcher.
+func TestSwitcher_ImplementsInterface(t *testing.T) {
+	var _ environment.ServiceSwitcher = (*Switcher)(nil)
+	var _ environment.ResourceClaimer = (*Switcher)(nil)
+	var _ environment.TargetLister = (*Switcher)(nil)
+	var _ environment.ConfigValidator = (*Switcher)(nil)
+	var _ environment.CLIRequirer = (*Switcher)(nil)
+}
+
+// TestSwitcher_Conformance tests that a switch is reported by
+// GetCurrentState and undone by Rollback, following the steps of
+// environment.VerifyExecSwitcher.
+func TestSwitcher_Conformance(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial map[string]string
+		config  *environment.GPGConfig
+		want    map[string]string
+	}{
+		{
+			name:    "from personal to work identity",
+			initial: map[string]string{keySigningKey: "5555EEEE6666FFFF", keyEmail: "jane@home.example", keyGPGSign: "false"},
+			config:  &environment.GPGConfig{SigningKey: "1111AAAA2222BBBB", Email: "jane@work.example"},
+			want:    map[string]string{keySigningKey: "1111AAAA2222BBBB", keyEmail: "jane@work.example", keyGPGSign: "true"},
+		},
+		{
+			name:    "from nothing set",
+			initial: map[string]string{},
+			config:  &environment.GPGConfig{SigningKey: "1111AAAA2222BBBB", Email: "jane@work.example", SignCommits: boolPtr(false)},
+			want:    map[string]string{keySigningKey: "1111AAAA2222BBBB", keyEmail: "jane@work.example", keyGPGSign: "false"},
+		},
+		{
+			name:    "email only",
+			initial: map[string]string{keyEmail: "jane@home.example"},
+			config:  &environment.GPGConfig{Email: "jane@work.example"},
+			want:    map[string]string{keyEmail: "jane@work.example"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := installCLIs(t, map[string]string{"1111AAAA2222BBBB": workKey, "5555EEEE6666FFFF": personalKey}, tt.initial)
+			switcher := NewSwitcher()
+			ctx := context.Background()
+
+			original, err := switcher.GetCurrentState(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentState() error = %v", err)
+			}
+			if err := switcher.Validate(tt.config); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if err := switcher.Switch(ctx, tt.config); err != nil {
+				t.Fatalf("Switch() error = %v", err)
+			}
+			if got := cli.config(t); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("git config after Switch() = %v, want %v", got, tt.want)
+			}
+
+			switched, err := switcher.GetCurrentState(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentState() error = %v", err)
+			}
+			want := *tt.config
+			want.SignCommits = signCommits(tt.config)
+			if !reflect.DeepEqual(switched, &want) {
+				t.Errorf("GetCurrentState() after Switch() = %+v, want %+v", switched, &want)
+			}
+
+			if err := switcher.Rollback(ctx, original); err != nil {
+				t.Fatalf("Rollback() error = %v", err)
+			}
+			if got := cli.config(t); !reflect.DeepEqual(got, tt.initial) {
+				t.Errorf("git config after Rollback() = %v, want %v", got, tt.initial)
+			}
+			restored, err := switcher.GetCurrentState(ctx)
+			if err != nil || !reflect.DeepEqual(restored, original) {
+				t.Errorf("GetCurrentState() after Rollback() = %+v, %v, want %+v", restored, err, original)
+			}
+		})
+	}
+}
+
+// TestSwitcher_Switch_UnknownKey tests that a key missing from the keyring
+// fails the switch without changing the git configuration.
+func TestSwitcher_Switch_UnknownKey(t *testing.T) {
+	initial := map[string]string{keySigningKey: "5555EEEE6666FFFF", keyEmail: "jane@home.example"}
+	cli := installCLIs(t, map[string]string{"5555EEEE6666FFFF": personalKey}, initial)
+
+	err := NewSwitcher().Switch(context.Background(), &environment.GPGConfig{SigningKey: "DEADBEEFDEADBEEF", Email: "jane@work.example"})
+	if err == nil || !strings.Contains(err.Error(), "signing key DEADBEEFDEADBEEF is not in the GPG keyring") {
+		t.Errorf("Switch() error = %v, want the missing key", err)
+	}
+	if hint := status.RemediationHint("gpg", environment.CommandStderr(err)); !strings.Contains(hint, "gpg --list-secret-keys") {
+		t.Errorf("RemediationHint() = %q, want a keyring hint", hint)
+	}
+	if got := cli.config(t); !reflect.DeepEqual(got, initial) {
+		t.Errorf("git config = %v, want it unchanged %v", got, initial)
+	}
+}
+
+// TestSwitcher_Switch_InvalidConfigType tests error handling for invalid config type.
+func TestSwitcher_Switch_InvalidConfigType(t *testing.T) {
+	for _, config := range []interface{}{"invalid-config", nil, (*environment.GPGConfig)(nil)} {
+		if err := NewSwitcher().Switch(context.Background(), config); err == nil || err.Error() != "invalid GPG configuration type" {
+			t.Errorf("Switch(%#v) error = %v, want %q", config, err, "invalid GPG configuration type")
+		}
+	}
+	if err := NewSwitcher().Rollback(context.Background(), "invalid-state"); err == nil {
+		t.Error("Rollback() with invalid state should return error")
+	}
+}
+
+// TestSwitcher_Validate tests rejecting configurations that set nothing.
+func TestSwitcher_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  interface{}
+		wantErr string
+	}{
+		{"signing key", &environment.GPGConfig{SigningKey: "1111AAAA2222BBBB"}, ""},
+		{"signing off", &environment.GPGConfig{SignCommits: boolPtr(false)}, ""},
+		{"empty", &environment.GPGConfig{}, "sets none of signingKey, email, and signCommits"},
+		{"nil", (*environment.GPGConfig)(nil), "no GPG configuration provided"},
+		{"wrong type", &environment.SSHConfig{}, "invalid GPG configuration type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSwitcher().Validate(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSwitcher_RequiredCLIs tests that gpg is needed only to check a signing key.
+func TestSwitcher_RequiredCLIs(t *testing.T) {
+	s := NewSwitcher()
+	if got := s.RequiredCLIs(&environment.GPGConfig{SigningKey: "1111AAAA2222BBBB"}); !reflect.DeepEqual(got, []string{"git", "gpg"}) {
+		t.Errorf("RequiredCLIs() = %v, want [git gpg]", got)
+	}
+	if got := s.RequiredCLIs(&environment.GPGConfig{Email: "jane@work.example"}); !reflect.DeepEqual(got, []string{"git"}) {
+		t.Errorf("RequiredCLIs() = %v, want [git]", got)
+	}
+}
+
+// TestSwitcher_ListTargets tests listing the keys that can sign.
+func TestSwitcher_ListTargets(t *testing.T) {
+	installCLIs(t, map[string]string{
+		"1111AAAA2222BBBB": workKey,
+		"5555EEEE6666FFFF": personalKey,
+		"7777AAAA8888BBBB": expiredKey,
+		"9999CCCC0000DDDD": revokedKey,
+	}, nil)
+
+	got, err := NewSwitcher().ListTargets(context.Background())
+	if err != nil {
+		t.Fatalf("ListTargets() error = %v", err)
+	}
+	if want := []string{"1111AAAA2222BBBB", "5555EEEE6666FFFF"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListTargets() = %v, want %v", got, want)
+	}
+}
+
+// TestParseSecretKeys tests reading keys with their fingerprints and user IDs.
+func TestParseSecretKeys(t *testing.T) {
+	keys := parseSecretKeys([]byte(workKey + revokedKey))
+	want := []secretKey{
+		{ID: "1111AAAA2222BBBB", Fingerprint: "0123456789ABCDEF01231111AAAA2222BBBB", Validity: "u", UserIDs: []string{"Jane Doe <jane@work.example>"}},
+		{ID: "9999CCCC0000DDDD", Validity: "r", UserIDs: []string{"Jane Doe <jane@work.example>"}},
+	}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("parseSecretKeys() = %+v, want %+v", keys, want)
+	}
+	if !keys[0].hasEmail("Jane@Work.example") || keys[0].hasEmail("jane@home.example") {
+		t.Error("hasEmail() should match the user ID's email case-insensitively")
+	}
+	if !keys[1].revoked() || keys[0].revoked() || keys[0].expired() {
+		t.Error("revoked() and expired() should follow the validity field")
+	}
+}
@@ -5,24 +5,40 @@ package kubernetes
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"os/exec"
 	"strings"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
 // DefaultNamespace is the default Kubernetes namespace.
 const DefaultNamespace = "default"
 
+// DefaultRequestTimeout bounds each kubectl request to the cluster.
+const DefaultRequestTimeout = 10 * time.Second
+
 // Checker implements status.ServiceChecker for Kubernetes.
-type Checker struct{}
+type Checker struct {
+	// RequestTimeout is passed to kubectl as --request-timeout, for
+	// clusters slower to answer than DefaultRequestTimeout allows.
+	RequestTimeout time.Duration
+}
 
 // NewChecker creates a new Kubernetes status checker.
 func NewChecker() *Checker {
-	return &Checker{}
+	return &Checker{RequestTimeout: DefaultRequestTimeout}
+}
+
+// requestTimeout returns the --request-timeout flag for kubectl.
+func (k *Checker) requestTimeout() string {
+	timeout := k.RequestTimeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	return "--request-timeout=" + timeout.String()
 }
 
 // Name returns the service name.
@@ -30,6 +46,11 @@ func (k *Checker) Name() string {
 	return "kubernetes"
 }
 
+// Category returns the service's category for grouping and selection.
+func (k *Checker) Category() status.Category {
+	return status.CategoryContainer
+}
+
 // CheckStatus checks Kubernetes current status.
 func (k *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
 	st := &status.ServiceStatus{
@@ -44,21 +65,35 @@ func (k *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if kubectl is available
 	if !k.isCLIAvailable() {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
 		st.Details["error"] = "kubectl not found"
 		return st, nil
 	}
 
+	// Report the kubeconfig files kubectl merges and which one owns the
+	// current context, since KUBECONFIG may list several.
+	paths := kubeconfigPaths()
+	st.Details["kubeconfig"] = joinPaths(paths)
+	if owner, _, err := currentContextFile(paths); err == nil {
+		st.Details["current_context_file"] = owner
+	}
+
 	// Get current context
 	k8sCtx, err := k.getCurrentContext(ctx)
 	if err != nil {
 		st.Status = status.StatusError
 		st.Details["error"] = fmt.Sprintf("Failed to get current context: %v", err)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			st.Details["stderr"] = stderr
+		}
 		return st, nil
 	}
 
+	// A kubeconfig without a current context is a valid, if unused, setup.
 	if k8sCtx == "" {
 		st.Status = status.StatusInactive
-		st.Details["error"] = "No Kubernetes context set"
+		st.Reason = status.ReasonNotConfigured
+		st.Details["reason"] = "No current context selected; run 'kubectl config use-context <name>'"
 		return st, nil
 	}
 
@@ -83,12 +118,16 @@ func (k *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 		st.Status = status.StatusActive
 	} else {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonCredentialsInvalid
 	}
 
 	return st, nil
 }
 
-// CheckHealth performs detailed health check for Kubernetes.
+// CheckHealth performs detailed health check for Kubernetes. It probes
+// the cluster's connectivity and then its nodes, recording each as a
+// sub-check. Nodes that are not ready make the whole check an error, while
+// nodes that cannot be listed, which RBAC often forbids, leave it active.
 func (k *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
 	start := time.Now()
 	health := &status.HealthStatus{
@@ -98,45 +137,126 @@ func (k *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	}
 
 	// Test cluster connectivity with kubectl cluster-info
-	cmd := exec.CommandContext(ctx, "kubectl", "cluster-info", "--request-timeout=10s")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "kubectl", "cluster-info", k.requestTimeout())
 	health.Duration = time.Since(start)
 
 	if err != nil {
-		health.Status = status.StatusError
-		health.Message = fmt.Sprintf("Failed to connect to Kubernetes cluster: %v", err)
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			health.Details["stderr"] = string(exitErr.Stderr)
+		stderr := environment.CommandStderr(err)
+		if stderr != "" {
+			health.Details["stderr"] = stderr
 		}
+		health.Status = status.StatusError
+		health.Message = connectFailureMessage(err, stderr)
+		health.AddCheck("connectivity", status.StatusError, health.Message, start)
 		return health, nil
 	}
 
 	health.Status = status.StatusActive
 	health.Message = "Kubernetes cluster is accessible"
 	health.Details["cluster_info"] = string(output)
+	health.AddCheck("connectivity", status.StatusActive, health.Message, start)
 
 	// Additional check: get node status
-	cmd = exec.CommandContext(ctx, "kubectl", "get", "nodes", "--no-headers", "-o", "custom-columns=NAME:.metadata.name,STATUS:.status.conditions[?(@.type==\"Ready\")].status")
-	nodeOutput, err := cmd.Output()
-	if err == nil {
+	nodesStart := time.Now()
+	nodeOutput, err := environment.RunCommand(ctx, "kubectl", "get", "nodes", "--no-headers", "-o", "custom-columns=NAME:.metadata.name,STATUS:.status.conditions[?(@.type==\"Ready\")].status", k.requestTimeout())
+	if err != nil {
+		health.AddCheck("nodes", status.StatusUnknown, fmt.Sprintf("Failed to list nodes: %v", err), nodesStart)
+	} else {
 		health.Details["node_status"] = string(nodeOutput)
+		nodeStatus, message := nodeReadiness(string(nodeOutput))
+		health.AddCheck("nodes", nodeStatus, message, nodesStart)
+		if nodeStatus == status.StatusError {
+			health.Status = status.StatusError
+			health.Message = message
+		}
 	}
+	health.Duration = time.Since(start)
 
 	return health, nil
 }
 
+// nodeReadiness summarizes `kubectl get nodes` output listing each node's
+// name and Ready condition. Any node that is not ready is an error.
+func nodeReadiness(output string) (status.StatusType, string) {
+	var total int
+	var notReady []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		total++
+		if len(fields) < 2 || fields[1] != "True" {
+			notReady = append(notReady, fields[0])
+		}
+	}
+
+	switch {
+	case total == 0:
+		return status.StatusUnknown, "No nodes visible"
+	case len(notReady) > 0:
+		return status.StatusError, fmt.Sprintf("%d of %d nodes not ready: %s", len(notReady), total, strings.Join(notReady, ", "))
+	default:
+		return status.StatusActive, fmt.Sprintf("%d of %d nodes ready", total, total)
+	}
+}
+
+// tlsFailures map stderr fragments of kubectl failing to verify the
+// cluster's certificate to what to do about it. kubectl prefixes every
+// verification error with "tls: failed to verify certificate", so the
+// specific failures come first.
+var tlsFailures = []struct {
+	fragment string
+	message  string
+}{
+	{"x509: certificate is valid for",
+		"Cluster certificate does not match the server address: point the kubeconfig's server at a name " +
+			"the certificate is valid for, or set tls-server-name, instead of skipping TLS verification"},
+	{"x509: certificate has expired or is not yet valid",
+		"Cluster certificate has expired or is not yet valid: check this machine's clock, " +
+			"or have the cluster's certificate renewed, instead of skipping TLS verification"},
+	{"x509: certificate signed by unknown authority",
+		"Cluster uses an untrusted certificate: add its CA to the kubeconfig " +
+			"(certificate-authority-data) instead of skipping TLS verification"},
+	{"certificate is not trusted",
+		"Cluster uses an untrusted certificate: add its CA to the kubeconfig " +
+			"(certificate-authority-data) instead of skipping TLS verification"},
+	{"tls: failed to verify certificate",
+		"Cluster certificate failed verification: check the kubeconfig's server and " +
+			"certificate-authority-data instead of skipping TLS verification"},
+}
+
+// connectFailureMessage describes a failed cluster-info. A certificate that
+// fails verification gets its own message, since it needs a certificate fix
+// rather than a network one. Verification is never turned off.
+func connectFailureMessage(err error, stderr string) string {
+	for _, failure := range tlsFailures {
+		if strings.Contains(stderr, failure.fragment) {
+			return failure.message
+		}
+	}
+	return fmt.Sprintf("Failed to connect to Kubernetes cluster: %v", err)
+}
+
 // isCLIAvailable checks if kubectl is installed.
 func (k *Checker) isCLIAvailable() bool {
 	_, err := exec.LookPath("kubectl")
 	return err == nil
 }
 
-// getCurrentContext gets the current Kubernetes context.
+// noCurrentContext is what kubectl prints to stderr, exiting non-zero, when
+// the kubeconfig selects no context.
+const noCurrentContext = "current-context is not set"
+
+// getCurrentContext gets the current Kubernetes context. It returns an
+// empty context, not an error, when none is selected, so an error means
+// kubectl itself failed.
 func (k *Checker) getCurrentContext(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "config", "current-context")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "kubectl", "config", "current-context")
 	if err != nil {
+		if strings.Contains(environment.CommandStderr(err), noCurrentContext) {
+			return "", nil
+		}
 		return "", err
 	}
 	return strings.TrimSpace(string(output)), nil
@@ -144,8 +264,7 @@ func (k *Checker) getCurrentContext(ctx context.Context) (string, error) {
 
 // getCurrentNamespace gets the current Kubernetes namespace.
 func (k *Checker) getCurrentNamespace(ctx context.Context) (string, error) {
-	cmd := exec.CommandContext(ctx, "kubectl", "config", "view", "--minify", "--output", "jsonpath={..namespace}")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "kubectl", "config", "view", "--minify", "--output", "jsonpath={..namespace}")
 	if err != nil {
 		return DefaultNamespace, nil // Default to "default" namespace
 	}
@@ -165,8 +284,7 @@ func (k *Checker) checkClusterAccess(ctx context.Context) (*status.CredentialSta
 	}
 
 	// Test cluster access with a simple API call
-	cmd := exec.CommandContext(ctx, "kubectl", "auth", "can-i", "get", "pods", "--request-timeout=10s")
-	err := cmd.Run()
+	_, err := environment.RunCommand(ctx, "kubectl", "auth", "can-i", "get", "pods", k.requestTimeout())
 	if err != nil {
 		credStatus.Warning = "Cannot access Kubernetes cluster"
 		return credStatus, nil
@@ -177,11 +295,14 @@ func (k *Checker) checkClusterAccess(ctx context.Context) (*status.CredentialSta
 	// Check if credentials have expiration (for OIDC/cloud providers)
 	currentUser := k.getCurrentUser(ctx)
 	jsonPath := fmt.Sprintf("{.users[?(@.name==%q)].user}", currentUser)
-	cmd = exec.CommandContext(ctx, "kubectl", "config", "view", "--raw", "-o", "jsonpath="+jsonPath) // #nosec G204 - validated kubectl command with controlled arguments
-	output, err := cmd.Output()
-	if err == nil && strings.Contains(string(output), "expiry") {
-		credStatus.Type = "oidc-token"
-		credStatus.Warning = "Token may expire - check manually"
+	output, err := environment.RunCommand(ctx, "kubectl", "config", "view", "--raw", "-o", "jsonpath="+jsonPath)
+	if err == nil && len(output) > 0 {
+		if err := credStatus.SetExpiry(status.KubeOIDCExpiry, output); err == nil && !credStatus.ExpiresAt.IsZero() {
+			credStatus.Type = "oidc-token"
+		} else if strings.Contains(string(output), "expiry") {
+			credStatus.Type = "oidc-token"
+			credStatus.Warning = "Token may expire - check manually"
+		}
 	}
 
 	return credStatus, nil
@@ -189,8 +310,7 @@ func (k *Checker) checkClusterAccess(ctx context.Context) (*status.CredentialSta
 
 // getCurrentUser gets the current Kubernetes user.
 func (k *Checker) getCurrentUser(ctx context.Context) string {
-	cmd := exec.CommandContext(ctx, "kubectl", "config", "view", "--minify", "--output", "jsonpath={.contexts[0].context.user}")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "kubectl", "config", "view", "--minify", "--output", "jsonpath={.contexts[0].context.user}")
 	if err != nil {
 		return ""
 	}
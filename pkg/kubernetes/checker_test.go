@@ -5,9 +5,13 @@ package kubernetes
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -188,3 +192,245 @@ func TestChecker_CheckStatus_ContextCanceled(t *testing.T) {
 		t.Error("CheckStatus() should return non-nil status even with canceled context")
 	}
 }
+
+// TestConnectFailureMessage tests that certificate failures in kubectl's
+// stderr get advice for the kind of failure.
+func TestConnectFailureMessage(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	tests := []struct {
+		name   string
+		stderr string
+		want   string
+	}{
+		{
+			name:   "unknown authority",
+			stderr: "Unable to connect to the server: x509: certificate signed by unknown authority\n",
+			want:   "untrusted certificate",
+		},
+		{
+			name:   "go 1.20+ verification failure",
+			stderr: `Unable to connect to the server: tls: failed to verify certificate: x509: certificate signed by unknown authority (possibly because of "crypto/rsa: verification error" while trying to verify candidate authority certificate "kubernetes")`,
+			want:   "untrusted certificate",
+		},
+		{
+			name:   "macOS keychain",
+			stderr: `Unable to connect to the server: x509: "kube-apiserver" certificate is not trusted`,
+			want:   "untrusted certificate",
+		},
+		{
+			name:   "hostname mismatch",
+			stderr: "Unable to connect to the server: tls: failed to verify certificate: x509: certificate is valid for 10.0.0.1, not 192.168.1.10",
+			want:   "does not match the server address",
+		},
+		{
+			name:   "expired",
+			stderr: "Unable to connect to the server: tls: failed to verify certificate: x509: certificate has expired or is not yet valid: current time 2026-10-16T12:00:00Z is after 2026-01-01T00:00:00Z",
+			want:   "has expired or is not yet valid",
+		},
+		{
+			name:   "other verification failure",
+			stderr: "Unable to connect to the server: tls: failed to verify certificate: x509: certificate specifies an incompatible key usage",
+			want:   "failed verification",
+		},
+		{
+			name:   "connection refused",
+			stderr: "The connection to the server localhost:8080 was refused - did you specify the right host or port?",
+			want:   exitErr.Error(),
+		},
+		{
+			name: "no stderr",
+			want: exitErr.Error(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := connectFailureMessage(exitErr, tt.stderr)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("connectFailureMessage() = %q, want it to mention %q", got, tt.want)
+			}
+			if tt.want != "untrusted certificate" && strings.Contains(got, "add its CA") {
+				t.Errorf("connectFailureMessage() = %q, want no CA advice", got)
+			}
+		})
+	}
+}
+
+// TestChecker_requestTimeout tests the --request-timeout flag and its default.
+func TestChecker_requestTimeout(t *testing.T) {
+	if got, want := NewChecker().requestTimeout(), "--request-timeout=10s"; got != want {
+		t.Errorf("requestTimeout() = %q, want %q", got, want)
+	}
+	if got, want := (&Checker{}).requestTimeout(), "--request-timeout=10s"; got != want {
+		t.Errorf("requestTimeout() = %q, want %q", got, want)
+	}
+	checker := &Checker{RequestTimeout: 45 * time.Second}
+	if got, want := checker.requestTimeout(), "--request-timeout=45s"; got != want {
+		t.Errorf("requestTimeout() = %q, want %q", got, want)
+	}
+}
+
+// TestChecker_CheckStatus_CurrentContext tests that a kubeconfig without a
+// current context is inactive while a failing kubectl is an error.
+func TestChecker_CheckStatus_CurrentContext(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		wantContext string
+		wantErr     bool
+		wantStatus  status.StatusType
+		wantReason  status.Reason
+		wantDetail  string
+	}{
+		{
+			name:       "current context not set",
+			script:     "echo 'error: current-context is not set' >&2; exit 1\n",
+			wantStatus: status.StatusInactive,
+			wantReason: status.ReasonNotConfigured,
+			wantDetail: "reason",
+		},
+		{
+			name:       "empty output",
+			script:     "exit 0\n",
+			wantStatus: status.StatusInactive,
+			wantReason: status.ReasonNotConfigured,
+			wantDetail: "reason",
+		},
+		{
+			name:       "kubectl failure",
+			script:     "echo 'error: error loading config file \"/root/.kube/config\": permission denied' >&2; exit 1\n",
+			wantErr:    true,
+			wantStatus: status.StatusError,
+			wantDetail: "stderr",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "config"))
+			installKubectl(t, tt.script)
+			checker := NewChecker()
+
+			k8sCtx, err := checker.getCurrentContext(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getCurrentContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if k8sCtx != tt.wantContext {
+				t.Errorf("getCurrentContext() = %q, want %q", k8sCtx, tt.wantContext)
+			}
+
+			st, err := checker.CheckStatus(context.Background())
+			if err != nil {
+				t.Fatalf("CheckStatus() error = %v", err)
+			}
+			if st.Status != tt.wantStatus {
+				t.Errorf("CheckStatus() status = %v, want %v", st.Status, tt.wantStatus)
+			}
+			if st.Reason != tt.wantReason {
+				t.Errorf("CheckStatus() reason = %q, want %q", st.Reason, tt.wantReason)
+			}
+			if st.Details[tt.wantDetail] == "" {
+				t.Errorf("CheckStatus() details = %v, want %q set", st.Details, tt.wantDetail)
+			}
+		})
+	}
+}
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
+
+// TestChecker_CheckHealth_SubChecks tests the connectivity and node
+// sub-checks recorded by the health check.
+func TestChecker_CheckHealth_SubChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		script      string
+		wantStatus  status.StatusType
+		wantMessage string
+		want        []status.SubCheck
+	}{
+		{
+			name: "all nodes ready",
+			script: `case "$1" in
+cluster-info) echo "Kubernetes control plane is running" ;;
+get) printf 'node-1   True\nnode-2   True\n' ;;
+esac
+`,
+			wantStatus:  status.StatusActive,
+			wantMessage: "Kubernetes cluster is accessible",
+			want: []status.SubCheck{
+				{Name: "connectivity", Status: status.StatusActive, Message: "Kubernetes cluster is accessible"},
+				{Name: "nodes", Status: status.StatusActive, Message: "2 of 2 nodes ready"},
+			},
+		},
+		{
+			name: "node not ready",
+			script: `case "$1" in
+cluster-info) echo "Kubernetes control plane is running" ;;
+get) printf 'node-1   True\nnode-2   False\nnode-3   Unknown\n' ;;
+esac
+`,
+			wantStatus:  status.StatusError,
+			wantMessage: "2 of 3 nodes not ready: node-2, node-3",
+			want: []status.SubCheck{
+				{Name: "connectivity", Status: status.StatusActive, Message: "Kubernetes cluster is accessible"},
+				{Name: "nodes", Status: status.StatusError, Message: "2 of 3 nodes not ready: node-2, node-3"},
+			},
+		},
+		{
+			name: "nodes forbidden",
+			script: `case "$1" in
+cluster-info) echo "Kubernetes control plane is running" ;;
+get) echo 'Error from server (Forbidden): nodes is forbidden' >&2; exit 1 ;;
+esac
+`,
+			wantStatus:  status.StatusActive,
+			wantMessage: "Kubernetes cluster is accessible",
+			want: []status.SubCheck{
+				{Name: "connectivity", Status: status.StatusActive, Message: "Kubernetes cluster is accessible"},
+				{Name: "nodes", Status: status.StatusUnknown, Message: "Failed to list nodes: exit status 1"},
+			},
+		},
+		{
+			name:        "unreachable",
+			script:      "echo 'Unable to connect to the server: dial tcp: i/o timeout' >&2; exit 1\n",
+			wantStatus:  status.StatusError,
+			wantMessage: "Failed to connect to Kubernetes cluster: exit status 1",
+			want: []status.SubCheck{
+				{Name: "connectivity", Status: status.StatusError, Message: "Failed to connect to Kubernetes cluster: exit status 1"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installKubectl(t, tt.script)
+
+			health, err := NewChecker().CheckHealth(context.Background())
+			if err != nil {
+				t.Fatalf("CheckHealth() error = %v", err)
+			}
+			if health.Status != tt.wantStatus {
+				t.Errorf("CheckHealth() status = %v, want %v", health.Status, tt.wantStatus)
+			}
+			if health.Message != tt.wantMessage {
+				t.Errorf("CheckHealth() message = %q, want %q", health.Message, tt.wantMessage)
+			}
+			if len(health.Checks) != len(tt.want) {
+				t.Fatalf("Checks = %+v, want %d sub-checks", health.Checks, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := health.Checks[i]
+				if got.Name != want.Name || got.Status != want.Status || got.Message != want.Message {
+					t.Errorf("Checks[%d] = %+v, want %+v", i, got, want)
+				}
+				if got.Duration <= 0 {
+					t.Errorf("Checks[%d].Duration = %v, want it measured", i, got.Duration)
+				}
+			}
+		})
+	}
+}
@@ -4,4 +4,7 @@
 // This package implements:
 //   - K8sSwitcher: Switches Kubernetes contexts and namespaces
 //   - K8sChecker: Checks Kubernetes cluster status and health
+//
+// Both honor KUBECONFIG, including several paths: the context is set in the
+// file that owns the merged current-context, and status reports the files.
 package kubernetes
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kubeconfigPaths returns the kubeconfig files kubectl merges, in
+// precedence order: the entries of KUBECONFIG, or ~/.kube/config if it is
+// unset.
+func kubeconfigPaths() []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, path := range filepath.SplitList(os.Getenv("KUBECONFIG")) {
+		if path != "" && !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) > 0 {
+		return paths
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	return []string{filepath.Join(home, ".kube", "config")}
+}
+
+// currentContextFile returns the file among paths that owns the merged
+// current-context, and the context it sets. As in kubectl, the first file
+// setting a current-context wins. If none does, the owner is the first
+// existing file, or the first path, which is where a new one belongs.
+func currentContextFile(paths []string) (path, currentContext string, err error) {
+	var firstExisting string
+	for _, p := range paths {
+		data, err := os.ReadFile(p) // #nosec G304 - kubeconfig paths chosen by the user
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read kubeconfig %s: %w", p, err)
+		}
+		if firstExisting == "" {
+			firstExisting = p
+		}
+
+		var config struct {
+			CurrentContext string `yaml:"current-context"`
+		}
+		if err := yaml.Unmarshal(data, &config); err != nil {
+			return "", "", fmt.Errorf("failed to parse kubeconfig %s: %w", p, err)
+		}
+		if config.CurrentContext != "" {
+			return p, config.CurrentContext, nil
+		}
+	}
+
+	if firstExisting != "" {
+		return firstExisting, "", nil
+	}
+	if len(paths) > 0 {
+		return paths[0], "", nil
+	}
+	return "", "", fmt.Errorf("no kubeconfig file found")
+}
+
+// joinPaths joins kubeconfig paths the way KUBECONFIG lists them.
+func joinPaths(paths []string) string {
+	return strings.Join(paths, string(os.PathListSeparator))
+}
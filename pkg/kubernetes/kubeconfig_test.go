@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// writeKubeconfig writes a kubeconfig with the given current-context to dir/name.
+func writeKubeconfig(t *testing.T, dir, name, currentContext string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	content := "apiVersion: v1\nkind: Config\n"
+	if currentContext != "" {
+		content += "current-context: " + currentContext + "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// fakeKubectl puts a kubectl on PATH that logs its arguments and answers
+// current-context and get-contexts. It returns the log file.
+func fakeKubectl(t *testing.T, currentContext string) string {
+	t.Helper()
+	return installKubectl(t, `case "$*" in
+"config current-context") echo "`+currentContext+`" ;;
+"config get-contexts --output name") printf 'dev\nprod\n' ;;
+esac
+`)
+}
+
+// installKubectl puts a kubectl shell script on PATH that logs its
+// arguments and then runs body. It returns the log file.
+func installKubectl(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake kubectl is a shell script")
+	}
+
+	dir := t.TempDir()
+	log := filepath.Join(dir, "kubectl.log")
+	script := "#!/bin/sh\necho \"$@\" >> \"" + log + "\"\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0o700); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+// kubectlCalls returns the argument lines logged by fakeKubectl.
+func kubectlCalls(t *testing.T, log string) []string {
+	t.Helper()
+	data, err := os.ReadFile(log) // #nosec G304 - test log file
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+// TestKubeconfigPaths tests KUBECONFIG parsing and the default path.
+func TestKubeconfigPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	sep := string(os.PathListSeparator)
+	tests := []struct {
+		name       string
+		kubeconfig string
+		want       []string
+	}{
+		{"unset", "", []string{filepath.Join(home, ".kube", "config")}},
+		{"single", "/a", []string{"/a"}},
+		{"multiple", "/a" + sep + "/b", []string{"/a", "/b"}},
+		{"empty entries and duplicates", sep + "/a" + sep + sep + "/b" + sep + "/a", []string{"/a", "/b"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.kubeconfig)
+			if got := kubeconfigPaths(); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("kubeconfigPaths() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCurrentContextFile tests which file owns the merged current-context.
+func TestCurrentContextFile(t *testing.T) {
+	dir := t.TempDir()
+	a := writeKubeconfig(t, dir, "a", "")
+	b := writeKubeconfig(t, dir, "b", "dev")
+	c := writeKubeconfig(t, dir, "c", "prod")
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name        string
+		paths       []string
+		wantPath    string
+		wantContext string
+	}{
+		{"single file", []string{c}, c, "prod"},
+		{"first setting wins", []string{a, b, c}, b, "dev"},
+		{"missing files skipped", []string{missing, c}, c, "prod"},
+		{"none set uses first existing", []string{missing, a}, a, ""},
+		{"none exist uses first path", []string{missing}, missing, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, currentContext, err := currentContextFile(tt.paths)
+			if err != nil {
+				t.Fatalf("currentContextFile() error = %v", err)
+			}
+			if path != tt.wantPath || currentContext != tt.wantContext {
+				t.Errorf("currentContextFile() = %q, %q, want %q, %q", path, currentContext, tt.wantPath, tt.wantContext)
+			}
+		})
+	}
+}
+
+// TestSwitcher_Switch_Kubeconfig tests that switching writes the context to the right kubeconfig.
+func TestSwitcher_Switch_Kubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	a := writeKubeconfig(t, dir, "a", "")
+	b := writeKubeconfig(t, dir, "b", "dev")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	sep := string(os.PathListSeparator)
+
+	tests := []struct {
+		name       string
+		kubeconfig string
+		context    string
+		wantCall   string
+		wantErr    bool
+	}{
+		{name: "unset", kubeconfig: "", context: "prod", wantCall: "config use-context prod"},
+		{name: "single", kubeconfig: b, context: "prod", wantCall: "config use-context prod"},
+		{name: "multiple", kubeconfig: a + sep + b, context: "prod", wantCall: "--kubeconfig " + b + " config set current-context prod"},
+		{name: "multiple unknown context", kubeconfig: a + sep + b, context: "staging", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.kubeconfig)
+			log := fakeKubectl(t, "dev")
+
+			err := NewSwitcher().Switch(context.Background(), &environment.KubernetesConfig{Context: tt.context})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Switch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			calls := kubectlCalls(t, log)
+			if tt.wantErr {
+				for _, call := range calls {
+					if strings.Contains(call, "current-context") || strings.Contains(call, "use-context") {
+						t.Errorf("Switch() ran %q for an unknown context", call)
+					}
+				}
+				return
+			}
+			if last := calls[len(calls)-1]; last != tt.wantCall {
+				t.Errorf("kubectl %q, want %q", last, tt.wantCall)
+			}
+		})
+	}
+}
+
+// TestChecker_CheckStatus_Kubeconfig tests that the effective kubeconfig files are reported.
+func TestChecker_CheckStatus_Kubeconfig(t *testing.T) {
+	dir := t.TempDir()
+	a := writeKubeconfig(t, dir, "a", "")
+	b := writeKubeconfig(t, dir, "b", "dev")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	sep := string(os.PathListSeparator)
+
+	tests := []struct {
+		name       string
+		kubeconfig string
+		wantPaths  string
+		wantOwner  string
+	}{
+		{"unset", "", filepath.Join(home, ".kube", "config"), filepath.Join(home, ".kube", "config")},
+		{"single", b, b, b},
+		{"multiple", a + sep + b, a + sep + b, b},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KUBECONFIG", tt.kubeconfig)
+			fakeKubectl(t, "dev")
+
+			st, err := NewChecker().CheckStatus(context.Background())
+			if err != nil {
+				t.Fatalf("CheckStatus() error = %v", err)
+			}
+			if st.Current.Context != "dev" {
+				t.Errorf("Current.Context = %q, want %q", st.Current.Context, "dev")
+			}
+			if st.Details["kubeconfig"] != tt.wantPaths {
+				t.Errorf("Details[kubeconfig] = %q, want %q", st.Details["kubeconfig"], tt.wantPaths)
+			}
+			if st.Details["current_context_file"] != tt.wantOwner {
+				t.Errorf("Details[current_context_file] = %q, want %q", st.Details["current_context_file"], tt.wantOwner)
+			}
+		})
+	}
+}
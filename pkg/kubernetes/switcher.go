@@ -6,7 +6,6 @@ package kubernetes
 import (
 	"context"
 	"fmt"
-	"os/exec"
 	"strings"
 
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
@@ -25,6 +24,29 @@ func (k *Switcher) Name() string {
 	return "kubernetes"
 }
 
+// Claims returns the shared resources the switcher modifies: the kubeconfig
+// files, as KUBECONFIG lists them.
+func (k *Switcher) Claims() []string {
+	return environment.KubeconfigClaims()
+}
+
+// Validate checks that config is a Kubernetes configuration.
+func (k *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.KubernetesConfig)
+	if !ok {
+		return fmt.Errorf("invalid Kubernetes configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no Kubernetes configuration provided")
+	}
+	return nil
+}
+
+// RequiredCLIs returns the kubectl CLI, which every switch runs.
+func (k *Switcher) RequiredCLIs(interface{}) []string {
+	return []string{"kubectl"}
+}
+
 // Switch switches to the specified Kubernetes configuration.
 func (k *Switcher) Switch(ctx context.Context, config interface{}) error {
 	kubernetesConfig, ok := config.(*environment.KubernetesConfig)
@@ -34,16 +56,14 @@ func (k *Switcher) Switch(ctx context.Context, config interface{}) error {
 
 	// Set Kubernetes context
 	if kubernetesConfig.Context != "" {
-		cmd := exec.CommandContext(ctx, "kubectl", "config", "use-context", kubernetesConfig.Context)
-		if err := cmd.Run(); err != nil {
+		if err := k.useContext(ctx, kubernetesConfig.Context); err != nil {
 			return fmt.Errorf("failed to set Kubernetes context: %w", err)
 		}
 	}
 
 	// Set Kubernetes namespace
 	if kubernetesConfig.Namespace != "" {
-		cmd := exec.CommandContext(ctx, "kubectl", "config", "set-context", "--current", "--namespace", kubernetesConfig.Namespace)
-		if err := cmd.Run(); err != nil {
+		if _, err := environment.RunCommand(ctx, "kubectl", "config", "set-context", "--current", "--namespace", kubernetesConfig.Namespace); err != nil {
 			return fmt.Errorf("failed to set Kubernetes namespace: %w", err)
 		}
 	}
@@ -51,15 +71,49 @@ func (k *Switcher) Switch(ctx context.Context, config interface{}) error {
 	return nil
 }
 
+// useContext makes name the current context. With a single kubeconfig this
+// is kubectl's use-context. When KUBECONFIG lists several files, the
+// current-context is set in the file that owns it, so the change is not
+// shadowed by, or written over, another file's setting.
+func (k *Switcher) useContext(ctx context.Context, name string) error {
+	paths := kubeconfigPaths()
+	if len(paths) <= 1 {
+		_, err := environment.RunCommand(ctx, "kubectl", "config", "use-context", name)
+		return err
+	}
+
+	// config set does not check the context exists, so check it in the
+	// merged configuration as use-context would.
+	output, err := environment.RunCommand(ctx, "kubectl", "config", "get-contexts", "--output", "name")
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, line := range strings.Split(string(output), "\n") {
+		if strings.TrimSpace(line) == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("no context exists with the name: %q in %s", name, joinPaths(paths))
+	}
+
+	owner, _, err := currentContextFile(paths)
+	if err != nil {
+		return err
+	}
+	_, err = environment.RunCommand(ctx, "kubectl", "--kubeconfig", owner, "config", "set", "current-context", name)
+	return err
+}
+
 // GetCurrentState retrieves the current Kubernetes configuration state.
 func (k *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 	// Get current Kubernetes context
-	cmd := exec.CommandContext(ctx, "kubectl", "config", "current-context")
-	contextOutput, _ := cmd.Output()
+	contextOutput, _ := environment.RunCommand(ctx, "kubectl", "config", "current-context")
 
 	// Get current namespace
-	cmd = exec.CommandContext(ctx, "kubectl", "config", "view", "--minify", "--output", "jsonpath={..namespace}")
-	namespaceOutput, _ := cmd.Output()
+	namespaceOutput, _ := environment.RunCommand(ctx, "kubectl", "config", "view", "--minify", "--output", "jsonpath={..namespace}")
 
 	return &environment.KubernetesConfig{
 		Context:   strings.TrimSpace(string(contextOutput)),
@@ -71,3 +125,19 @@ func (k *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 func (k *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
 	return k.Switch(ctx, previousState)
 }
+
+// ListTargets returns the available kubeconfig contexts.
+func (k *Switcher) ListTargets(ctx context.Context) ([]string, error) {
+	output, err := environment.RunCommand(ctx, "kubectl", "config", "get-contexts", "--output", "name")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Kubernetes contexts: %w", err)
+	}
+
+	var targets []string
+	for _, line := range strings.Split(string(output), "\n") {
+		if target := strings.TrimSpace(line); target != "" {
+			targets = append(targets, target)
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,15 @@
+// Package mockservice provides fake services for demos and end-to-end tests
+// of the dev-env CLI and TUI, where real CLIs and credentials are not
+// available. Mock services never run commands or touch the host's
+// configuration files.
+//
+// The main abstractions are:
+//   - Fixture: YAML description of each service's state, delays, and
+//     injected failures
+//   - Service: ServiceChecker and ServiceSwitcher driven by a fixture,
+//     whose switches change the status it reports
+//   - Set: the mock services that replace the default registrations
+//
+// Mock services are enabled by setting GZH_DEV_ENV_MOCK to 1, or to the path
+// of a fixture file.
+package mockservice
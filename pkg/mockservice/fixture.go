@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package mockservice
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// EnvVar enables mock services. "1" or "true" uses DefaultFixture; any
+// other value except "0" and "false" is the path of a fixture file.
+const EnvVar = "GZH_DEV_ENV_MOCK"
+
+// Fixture describes the mock services, keyed by service name.
+//
+// Example:
+//
+//	services:
+//	  aws:
+//	    current: {profile: dev, region: us-east-1}
+//	    switchDelay: 2s
+//	  kubernetes:
+//	    status: error
+//	    switchError: context "prod" does not exist
+//	    switchStderr: "error: no context exists with the name: \"prod\""
+type Fixture struct {
+	Services map[string]ServiceFixture `yaml:"services"`
+}
+
+// ServiceFixture configures one mock service.
+type ServiceFixture struct {
+	// Status is the reported status, active if empty.
+	Status status.StatusType `yaml:"status,omitempty"`
+	// Reason is why the service is not active, such as notInstalled.
+	Reason status.Reason `yaml:"reason,omitempty"`
+	// Category overrides the category of a built-in service name.
+	Category status.Category `yaml:"category,omitempty"`
+	// Current is the configuration reported before the first switch.
+	Current status.CurrentConfig `yaml:"current,omitempty"`
+	// Credentials are the reported credentials. Nil means valid ones of
+	// type "mock".
+	Credentials *status.CredentialStatus `yaml:"credentials,omitempty"`
+	// Health is the health check status, Status if empty.
+	Health        status.StatusType `yaml:"health,omitempty"`
+	HealthMessage string            `yaml:"healthMessage,omitempty"`
+	// Targets are listed by ListTargets, e.g. for the TUI's target picker.
+	Targets []string `yaml:"targets,omitempty"`
+
+	// CheckDelay and SwitchDelay are how long checks and switches take.
+	// Both are cut short when the context is cancelled.
+	CheckDelay  time.Duration `yaml:"checkDelay,omitempty"`
+	SwitchDelay time.Duration `yaml:"switchDelay,omitempty"`
+
+	// CheckError, if set, fails CheckStatus with this message.
+	CheckError string `yaml:"checkError,omitempty"`
+	// SwitchError, if set, fails every switch with this message. The
+	// failure carries SwitchStderr as the CLI's standard error.
+	SwitchError  string `yaml:"switchError,omitempty"`
+	SwitchStderr string `yaml:"switchStderr,omitempty"`
+	// SwitchFailures fails only the first n switches with SwitchError, or
+	// a generic error, for exercising retries. Zero fails all of them.
+	SwitchFailures int `yaml:"switchFailures,omitempty"`
+	// RollbackError, if set, fails every rollback with this message.
+	RollbackError string `yaml:"rollbackError,omitempty"`
+}
+
+// DefaultFixture returns healthy mock services for every built-in service.
+func DefaultFixture() *Fixture {
+	return &Fixture{Services: map[string]ServiceFixture{
+		"aws": {
+			Current: status.CurrentConfig{Profile: "default", Region: "us-east-1"},
+			Targets: []string{"default", "staging", "production"},
+		},
+		"gcp": {
+			Current: status.CurrentConfig{Project: "demo-project", Account: "dev@example.com", Region: "us-central1"},
+			Targets: []string{"demo-project", "staging-project", "production-project"},
+		},
+		"azure": {
+			Current: status.CurrentConfig{Project: "Demo Subscription", Account: "dev@example.com"},
+			Targets: []string{"Demo Subscription", "Production Subscription"},
+		},
+		"docker": {
+			Current: status.CurrentConfig{Context: "default"},
+			Targets: []string{"default", "remote"},
+		},
+		"kubernetes": {
+			Current: status.CurrentConfig{Context: "kind-demo", Namespace: "default"},
+			Targets: []string{"kind-demo", "staging", "production"},
+		},
+		"ssh": {
+			Current: status.CurrentConfig{Context: "~/.ssh/config"},
+		},
+		"gpg": {
+			Current: status.CurrentConfig{Context: "1111AAAA2222BBBB", Account: "dev@example.com"},
+			Targets: []string{"1111AAAA2222BBBB", "5555EEEE6666FFFF"},
+		},
+		"pkgmgr": {
+			Current: status.CurrentConfig{Context: "https://registry.npmjs.org/"},
+		},
+	}}
+}
+
+// LoadFixture reads a fixture file.
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path is chosen by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock fixture: %w", err)
+	}
+
+	var fixture Fixture
+	if err := yaml.Unmarshal(data, &fixture); err != nil {
+		return nil, fmt.Errorf("failed to parse mock fixture %s: %w", path, err)
+	}
+	if len(fixture.Services) == 0 {
+		return nil, fmt.Errorf("mock fixture %s defines no services", path)
+	}
+	for name, service := range fixture.Services {
+		if err := service.validate(); err != nil {
+			return nil, fmt.Errorf("mock fixture %s: service %s: %w", path, name, err)
+		}
+	}
+	return &fixture, nil
+}
+
+// validate checks the statuses and category of a fixture.
+func (f ServiceFixture) validate() error {
+	for _, st := range []status.StatusType{f.Status, f.Health} {
+		switch st {
+		case "", status.StatusActive, status.StatusInactive, status.StatusError, status.StatusUnknown:
+		default:
+			return fmt.Errorf("unknown status %q", st)
+		}
+	}
+	if f.Category != "" {
+		if _, err := status.ParseCategory(string(f.Category)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FromEnv returns the mock services selected by spec, a value of EnvVar,
+// or nil if spec does not enable them.
+func FromEnv(spec string) (*Set, error) {
+	switch strings.ToLower(strings.TrimSpace(spec)) {
+	case "", "0", "false":
+		return nil, nil
+	case "1", "true":
+		return NewSet(DefaultFixture()), nil
+	}
+
+	fixture, err := LoadFixture(spec)
+	if err != nil {
+		return nil, err
+	}
+	return NewSet(fixture), nil
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package mockservice
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TestLoadFixture tests loading a fixture file with every kind of setting.
+func TestLoadFixture(t *testing.T) {
+	fixture, err := LoadFixture(filepath.Join("testdata", "fixture.yaml"))
+	if err != nil {
+		t.Fatalf("LoadFixture() error = %v", err)
+	}
+
+	aws := fixture.Services["aws"]
+	if aws.Current.Profile != "dev" || aws.SwitchDelay != 10*time.Millisecond {
+		t.Errorf("aws = %+v, want profile dev and a 10ms switch delay", aws)
+	}
+	kube := fixture.Services["kubernetes"]
+	if kube.Status != status.StatusError || kube.Health != status.StatusInactive || kube.SwitchStderr == "" {
+		t.Errorf("kubernetes = %+v, want error status, inactive health, and switch stderr", kube)
+	}
+	vault := fixture.Services["vault"]
+	if vault.Category != status.CategoryAccess || vault.Credentials == nil || vault.Credentials.Valid {
+		t.Errorf("vault = %+v, want access category and invalid credentials", vault)
+	}
+}
+
+// TestLoadFixture_Invalid tests that broken fixtures are rejected.
+func TestLoadFixture_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"no services", "services: {}\n"},
+		{"bad yaml", "services: [\n"},
+		{"unknown status", "services:\n  aws:\n    status: sleeping\n"},
+		{"unknown category", "services:\n  aws:\n    category: database\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fixture.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := LoadFixture(path); err == nil {
+				t.Error("LoadFixture() should fail")
+			}
+		})
+	}
+}
+
+// TestFromEnv tests which GZH_DEV_ENV_MOCK values enable mock services.
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		spec     string
+		services int
+	}{
+		{"", 0},
+		{"0", 0},
+		{"false", 0},
+		{"1", 8},
+		{"TRUE", 8},
+		{filepath.Join("testdata", "fixture.yaml"), 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			set, err := FromEnv(tt.spec)
+			if err != nil {
+				t.Fatalf("FromEnv() error = %v", err)
+			}
+			got := 0
+			if set != nil {
+				got = len(set.Services())
+			}
+			if got != tt.services {
+				t.Errorf("FromEnv(%q) services = %d, want %d", tt.spec, got, tt.services)
+			}
+		})
+	}
+
+	if _, err := FromEnv(filepath.Join("testdata", "missing.yaml")); err == nil {
+		t.Error("FromEnv() should fail for a missing fixture")
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package mockservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// builtinCategories are the categories of the built-in service names.
+var builtinCategories = map[string]status.Category{
+	"aws":        status.CategoryCloud,
+	"gcp":        status.CategoryCloud,
+	"azure":      status.CategoryCloud,
+	"docker":     status.CategoryContainer,
+	"kubernetes": status.CategoryContainer,
+	"ssh":        status.CategoryAccess,
+	"gpg":        status.CategoryAccess,
+	"pkgmgr":     status.CategoryAccess,
+}
+
+// Service is a mock service. It implements status.ServiceChecker and
+// environment.ServiceSwitcher, sharing state between them so a switch shows
+// up in the next status check. It is safe for concurrent use.
+type Service struct {
+	name    string
+	fixture ServiceFixture
+
+	mu       sync.Mutex
+	current  status.CurrentConfig
+	switches int
+}
+
+// NewService creates a mock service named name.
+func NewService(name string, fixture ServiceFixture) *Service {
+	return &Service{
+		name:    name,
+		fixture: fixture,
+		current: fixture.Current,
+	}
+}
+
+// Name returns the service name.
+func (s *Service) Name() string {
+	return s.name
+}
+
+// Category returns the fixture's category, or that of the built-in service
+// of the same name.
+func (s *Service) Category() status.Category {
+	if s.fixture.Category != "" {
+		return s.fixture.Category
+	}
+	if category, ok := builtinCategories[s.name]; ok {
+		return category
+	}
+	return status.CategoryCustom
+}
+
+// CheckStatus reports the fixture's status with the current configuration.
+func (s *Service) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
+	if err := sleep(ctx, s.fixture.CheckDelay); err != nil {
+		return nil, err
+	}
+	if s.fixture.CheckError != "" {
+		return nil, errors.New(s.fixture.CheckError)
+	}
+
+	credentials := status.CredentialStatus{Valid: true, Type: "mock"}
+	if s.fixture.Credentials != nil {
+		credentials = *s.fixture.Credentials
+	}
+
+	s.mu.Lock()
+	current := s.current
+	s.mu.Unlock()
+
+	return &status.ServiceStatus{
+		Name:        s.name,
+		Status:      orDefault(s.fixture.Status, status.StatusActive),
+		Reason:      s.fixture.Reason,
+		Current:     current,
+		Credentials: credentials,
+		LastUsed:    time.Now(),
+		Details:     map[string]string{"mock": "true"},
+	}, nil
+}
+
+// CheckHealth reports the fixture's health status.
+func (s *Service) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
+	start := time.Now()
+	if err := sleep(ctx, s.fixture.CheckDelay); err != nil {
+		return nil, err
+	}
+
+	message := s.fixture.HealthMessage
+	if message == "" {
+		message = "Mock service"
+	}
+	return &status.HealthStatus{
+		Status:    orDefault(s.fixture.Health, orDefault(s.fixture.Status, status.StatusActive)),
+		Message:   message,
+		CheckedAt: time.Now(),
+		Duration:  time.Since(start),
+		Details:   map[string]interface{}{"mock": true},
+	}, nil
+}
+
+// Switch applies config, the service's environment configuration such as
+// *environment.AWSConfig, after SwitchDelay, unless the fixture injects a
+// failure.
+func (s *Service) Switch(ctx context.Context, config interface{}) error {
+	if err := sleep(ctx, s.fixture.SwitchDelay); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.switches++
+	if err := s.switchError(); err != nil {
+		return err
+	}
+
+	current, err := currentConfig(config)
+	if err != nil {
+		return err
+	}
+	s.current = current
+	return nil
+}
+
+// switchError returns the failure the fixture injects into the current
+// switch, if any.
+func (s *Service) switchError() error {
+	f := s.fixture
+	failing := f.SwitchError != "" || f.SwitchFailures > 0
+	if !failing || (f.SwitchFailures > 0 && s.switches > f.SwitchFailures) {
+		return nil
+	}
+
+	message := f.SwitchError
+	if message == "" {
+		message = fmt.Sprintf("mock %s switch failed", s.name)
+	}
+	return &environment.CommandError{
+		Command: "mock " + s.name,
+		Stderr:  f.SwitchStderr,
+		Err:     errors.New(message),
+	}
+}
+
+// GetCurrentState returns the current configuration for rollback.
+func (s *Service) GetCurrentState(ctx context.Context) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.current, nil
+}
+
+// Rollback restores a state returned by GetCurrentState.
+func (s *Service) Rollback(ctx context.Context, previousState interface{}) error {
+	if s.fixture.RollbackError != "" {
+		return errors.New(s.fixture.RollbackError)
+	}
+	previous, ok := previousState.(status.CurrentConfig)
+	if !ok {
+		return fmt.Errorf("invalid state type for mock %s rollback", s.name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.current = previous
+	return nil
+}
+
+// ListTargets returns the fixture's targets.
+func (s *Service) ListTargets(ctx context.Context) ([]string, error) {
+	if err := sleep(ctx, s.fixture.CheckDelay); err != nil {
+		return nil, err
+	}
+	return append([]string(nil), s.fixture.Targets...), nil
+}
+
+// currentConfig maps a service configuration to the current configuration
+// its checker would report after switching.
+func currentConfig(config interface{}) (status.CurrentConfig, error) {
+	switch c := config.(type) {
+	case *environment.AWSConfig:
+		return status.CurrentConfig{Profile: c.Profile, Region: c.Region, Account: c.AccountID}, nil
+	case *environment.GCPConfig:
+		return status.CurrentConfig{Project: c.Project, Account: c.Account, Region: c.Region}, nil
+	case *environment.AzureConfig:
+		return status.CurrentConfig{Project: c.Subscription, Account: c.Tenant}, nil
+	case *environment.DockerConfig:
+		return status.CurrentConfig{Context: c.Context}, nil
+	case *environment.KubernetesConfig:
+		return status.CurrentConfig{Context: c.Context, Namespace: c.Namespace}, nil
+	case *environment.SSHConfig:
+		return status.CurrentConfig{Context: c.Config}, nil
+	case *environment.GPGConfig:
+		return status.CurrentConfig{Context: c.SigningKey, Account: c.Email}, nil
+	case *environment.PkgMgrConfig:
+		return status.CurrentConfig{Context: c.NpmRegistry}, nil
+	default:
+		return status.CurrentConfig{}, fmt.Errorf("unsupported mock configuration type %T", config)
+	}
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// orDefault returns st, or def if st is empty.
+func orDefault(st, def status.StatusType) status.StatusType {
+	if st == "" {
+		return def
+	}
+	return st
+}
+
+// Set holds the mock services that replace the default checkers and
+// switchers.
+type Set struct {
+	services []*Service
+}
+
+// NewSet creates a mock service for each service in fixture, ordered by
+// name.
+func NewSet(fixture *Fixture) *Set {
+	names := make([]string, 0, len(fixture.Services))
+	for name := range fixture.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	set := &Set{services: make([]*Service, 0, len(names))}
+	for _, name := range names {
+		set.services = append(set.services, NewService(name, fixture.Services[name]))
+	}
+	return set
+}
+
+// Services returns the mock services.
+func (s *Set) Services() []*Service {
+	return s.services
+}
+
+// Checkers returns the mock services as status checkers.
+func (s *Set) Checkers() []status.ServiceChecker {
+	checkers := make([]status.ServiceChecker, len(s.services))
+	for i, service := range s.services {
+		checkers[i] = service
+	}
+	return checkers
+}
+
+// Switchers returns the mock services as service switchers.
+func (s *Set) Switchers() []environment.ServiceSwitcher {
+	switchers := make([]environment.ServiceSwitcher, len(s.services))
+	for i, service := range s.services {
+		switchers[i] = service
+	}
+	return switchers
+}
@@ -0,0 +1,158 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package mockservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TestService_CheckStatus tests the reported status, defaults included.
+func TestService_CheckStatus(t *testing.T) {
+	ctx := context.Background()
+
+	st, err := NewService("aws", ServiceFixture{Current: status.CurrentConfig{Profile: "dev"}}).CheckStatus(ctx)
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if st.Status != status.StatusActive || !st.Credentials.Valid || st.Current.Profile != "dev" {
+		t.Errorf("CheckStatus() = %+v, want active with valid credentials and profile dev", st)
+	}
+
+	health, err := NewService("aws", ServiceFixture{Status: status.StatusError}).CheckHealth(ctx)
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if health.Status != status.StatusError {
+		t.Errorf("CheckHealth() status = %v, want %v", health.Status, status.StatusError)
+	}
+
+	if _, err := NewService("aws", ServiceFixture{CheckError: "boom"}).CheckStatus(ctx); err == nil {
+		t.Error("CheckStatus() should fail with CheckError set")
+	}
+
+	slow := NewService("aws", ServiceFixture{CheckDelay: time.Minute})
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := slow.CheckStatus(cancelled); err == nil {
+		t.Error("CheckStatus() should stop waiting when the context is cancelled")
+	}
+}
+
+// TestService_Category tests built-in, overridden, and custom categories.
+func TestService_Category(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  ServiceFixture
+		expected status.Category
+	}{
+		{"kubernetes", ServiceFixture{}, status.CategoryContainer},
+		{"aws", ServiceFixture{Category: status.CategoryCustom}, status.CategoryCustom},
+		{"vault", ServiceFixture{}, status.CategoryCustom},
+	}
+
+	for _, tt := range tests {
+		if got := status.CheckerCategory(NewService(tt.name, tt.fixture)); got != tt.expected {
+			t.Errorf("CheckerCategory(%s) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+}
+
+// TestService_SwitchAndRollback tests that switches show up in the status
+// and rollbacks restore the previous state.
+func TestService_SwitchAndRollback(t *testing.T) {
+	ctx := context.Background()
+	service := NewService("kubernetes", ServiceFixture{Current: status.CurrentConfig{Context: "kind-demo"}})
+
+	previous, err := service.GetCurrentState(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentState() error = %v", err)
+	}
+	if err := service.Switch(ctx, &environment.KubernetesConfig{Context: "staging", Namespace: "apps"}); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+	st, _ := service.CheckStatus(ctx)
+	if st.Current.Context != "staging" || st.Current.Namespace != "apps" {
+		t.Errorf("Current after Switch() = %+v, want staging/apps", st.Current)
+	}
+
+	if err := service.Rollback(ctx, previous); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	st, _ = service.CheckStatus(ctx)
+	if st.Current.Context != "kind-demo" {
+		t.Errorf("Current after Rollback() = %+v, want kind-demo", st.Current)
+	}
+
+	if err := service.Switch(ctx, "staging"); err == nil {
+		t.Error("Switch() should fail for an unsupported configuration type")
+	}
+}
+
+// TestService_SwitchFailures tests injected switch failures.
+func TestService_SwitchFailures(t *testing.T) {
+	ctx := context.Background()
+	config := &environment.AWSConfig{Profile: "prod"}
+
+	failing := NewService("aws", ServiceFixture{SwitchError: "no such profile", SwitchStderr: "The config profile (prod) could not be found"})
+	err := failing.Switch(ctx, config)
+	if err == nil || err.Error() != "no such profile" {
+		t.Fatalf("Switch() error = %v, want %q", err, "no such profile")
+	}
+	if got := environment.CommandStderr(err); got != "The config profile (prod) could not be found" {
+		t.Errorf("CommandStderr() = %q, want the fixture's stderr", got)
+	}
+
+	flaky := NewService("aws", ServiceFixture{SwitchFailures: 2})
+	for i, wantErr := range []bool{true, true, false, false} {
+		if err := flaky.Switch(ctx, config); (err != nil) != wantErr {
+			t.Errorf("Switch() #%d error = %v, wantErr %v", i+1, err, wantErr)
+		}
+	}
+}
+
+// TestSet_SwitchEnvironment tests mock services end to end through the
+// environment switcher, including rollback after a failure.
+func TestSet_SwitchEnvironment(t *testing.T) {
+	ctx := context.Background()
+	set := NewSet(&Fixture{Services: map[string]ServiceFixture{
+		"aws":        {Current: status.CurrentConfig{Profile: "dev"}},
+		"kubernetes": {Current: status.CurrentConfig{Context: "kind-demo"}, SwitchError: "context not found"},
+	}})
+
+	switcher := environment.NewEnvironmentSwitcher()
+	for _, s := range set.Switchers() {
+		switcher.Register(s)
+	}
+
+	env := &environment.Environment{
+		Name: "staging",
+		Services: map[string]environment.ServiceConfig{
+			"aws":        {AWS: &environment.AWSConfig{Profile: "staging"}},
+			"kubernetes": {Kubernetes: &environment.KubernetesConfig{Context: "staging"}},
+		},
+		Dependencies: []string{"aws -> kubernetes"},
+	}
+	result, err := switcher.SwitchEnvironment(ctx, env, environment.SwitchOptions{RollbackOnError: true, AllowRoot: true, SkipValidation: true})
+	if err == nil {
+		t.Fatal("SwitchEnvironment() should fail")
+	}
+	if !result.RollbackPerformed {
+		t.Error("RollbackPerformed = false, want true")
+	}
+
+	statuses, err := status.NewStatusCollector(set.Checkers(), time.Second).CollectAll(ctx, status.StatusOptions{})
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	for _, st := range statuses {
+		if st.Name == "aws" && st.Current.Profile != "dev" {
+			t.Errorf("aws profile = %q after rollback, want %q", st.Current.Profile, "dev")
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// Package notify tells the user an environment switch has finished, for
+// long switches that complete while they are elsewhere.
+//
+// This package implements:
+//   - Notifier: Sends the result of a switch
+//   - Webhook: POSTs the SwitchResult as JSON to a validated URL, with a timeout
+//   - Desktop: Shows a desktop notification with notify-send or osascript
+//   - Summary: Describes a switch result in one title and message
+package notify
@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// DefaultWebhookTimeout bounds a webhook POST when Webhook.Timeout is unset,
+// so an unreachable endpoint cannot hold up the end of a switch.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// ErrDesktopUnsupported is returned by Desktop on platforms without a
+// supported notification command.
+var ErrDesktopUnsupported = errors.New("desktop notifications are not supported on this platform")
+
+// Notifier tells the user a switch has finished.
+type Notifier interface {
+	Notify(ctx context.Context, environment string, result *environment.SwitchResult) error
+}
+
+// Notification is the JSON body a Webhook POSTs.
+type Notification struct {
+	// Environment is the name of the environment switched to.
+	Environment string `json:"environment"`
+	// Result is the outcome of the switch, as webhookResult leaves it for
+	// a third party to see.
+	Result *environment.SwitchResult `json:"result"`
+}
+
+// webhookResult returns a copy of result for a webhook, which may be a
+// third-party service: the errors keep their service and message, but not
+// their Detail or the output of a failed hook, which can hold anything a
+// command printed, and their messages are sanitized as status details are.
+func webhookResult(result *environment.SwitchResult) *environment.SwitchResult {
+	if result == nil {
+		return nil
+	}
+	sanitized := *result
+	sanitized.Errors = webhookErrors(result.Errors)
+	sanitized.RollbackErrors = webhookErrors(result.RollbackErrors)
+	return &sanitized
+}
+
+// webhookErrors returns copies of errs for webhookResult.
+func webhookErrors(errs []environment.SwitchError) []environment.SwitchError {
+	if errs == nil {
+		return nil
+	}
+	sanitized := make([]environment.SwitchError, len(errs))
+	for i, e := range errs {
+		message, _, _ := strings.Cut(e.Error, " (output: ")
+		e.Error = status.SanitizeValue(message, status.DefaultMaxDetailSize)
+		e.Detail = ""
+		sanitized[i] = e
+	}
+	return sanitized
+}
+
+// ValidateWebhookURL checks that rawURL is an absolute http or https URL.
+func ValidateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL %q: %w", rawURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("invalid webhook URL %q: scheme must be http or https", rawURL)
+	}
+	if u.Host == "" {
+		return fmt.Errorf("invalid webhook URL %q: missing host", rawURL)
+	}
+	return nil
+}
+
+// Webhook POSTs a Notification as JSON to a URL.
+type Webhook struct {
+	URL string
+	// Timeout bounds the POST, or DefaultWebhookTimeout if zero.
+	Timeout time.Duration
+	// Client sends the POST, or http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// NewWebhook returns a Webhook posting to rawURL, after validating it.
+func NewWebhook(rawURL string) (*Webhook, error) {
+	if err := ValidateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+	return &Webhook{URL: rawURL, Timeout: DefaultWebhookTimeout}, nil
+}
+
+// Notify POSTs the result of the switch to name, without the output of
+// failed commands and hooks. Responses other than 2xx are errors.
+func (w *Webhook) Notify(ctx context.Context, name string, result *environment.SwitchResult) error {
+	body, err := json.Marshal(Notification{Environment: name, Result: webhookResult(result)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	timeout := w.Timeout
+	if timeout <= 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post webhook notification: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Desktop shows a desktop notification with notify-send on Linux and
+// osascript on macOS.
+type Desktop struct {
+	// goos selects the notification command; runtime.GOOS by default.
+	goos string
+	// run runs the notification command; exec by default.
+	run func(ctx context.Context, name string, args ...string) error
+}
+
+// NewDesktop returns a Desktop for the current platform.
+func NewDesktop() *Desktop {
+	return &Desktop{
+		goos: runtime.GOOS,
+		run: func(ctx context.Context, name string, args ...string) error {
+			return exec.CommandContext(ctx, name, args...).Run() // #nosec G204 - fixed notification commands
+		},
+	}
+}
+
+// Notify shows the outcome of the switch to name.
+func (d *Desktop) Notify(ctx context.Context, name string, result *environment.SwitchResult) error {
+	title, message := Summary(name, result)
+	command, args, err := desktopCommand(d.goos, title, message)
+	if err != nil {
+		return err
+	}
+	if err := d.run(ctx, command, args...); err != nil {
+		return fmt.Errorf("failed to show desktop notification with %s: %w", command, err)
+	}
+	return nil
+}
+
+// desktopCommand returns the command showing a notification on goos.
+func desktopCommand(goos, title, message string) (string, []string, error) {
+	switch goos {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return "notify-send", []string{"--app-name=dev-env", title, message}, nil
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", appleScriptString(message), appleScriptString(title))
+		return "osascript", []string{"-e", script}, nil
+	default:
+		return "", nil, ErrDesktopUnsupported
+	}
+}
+
+// appleScriptString quotes s as an AppleScript string literal.
+func appleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// Summary returns a one-line title and message describing the outcome of
+// the switch to name.
+func Summary(name string, result *environment.SwitchResult) (title, message string) {
+	duration := result.Duration.Round(time.Second)
+	switch {
+	case !result.Success:
+		title = fmt.Sprintf("dev-env: switch to %s failed", name)
+		message = fmt.Sprintf("Failed: %s", strings.Join(result.FailedServices, ", "))
+		if result.RollbackPerformed {
+			message += " (rolled back)"
+		}
+	case result.Partial:
+		title = fmt.Sprintf("dev-env: partially switched to %s", name)
+		message = fmt.Sprintf("%d switched, %d failed: %s", len(result.SwitchedServices), len(result.FailedServices), strings.Join(result.FailedServices, ", "))
+	default:
+		title = fmt.Sprintf("dev-env: switched to %s", name)
+		message = fmt.Sprintf("%d services switched in %s", len(result.SwitchedServices), duration)
+	}
+	return title, message
+}
+
+// All sends the result of the switch to name with every notifier, returning
+// their errors joined.
+func All(ctx context.Context, notifiers []Notifier, name string, result *environment.SwitchResult) error {
+	var errs []error
+	for _, notifier := range notifiers {
+		if err := notifier.Notify(ctx, name, result); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,177 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// defaultGoProxy is the GOPROXY go uses when none is set.
+const defaultGoProxy = "https://proxy.golang.org,direct"
+
+// Checker implements status.ServiceChecker for package registries. It
+// reports which registries are in effect and whether npm has a token for
+// them, never the token itself.
+type Checker struct {
+	npmrcPath string
+}
+
+// NewChecker creates a new package registry status checker.
+func NewChecker() *Checker {
+	return &Checker{npmrcPath: DefaultNpmrcPath()}
+}
+
+// Name returns the service name.
+func (c *Checker) Name() string {
+	return "pkgmgr"
+}
+
+// Category returns the service's category for grouping and selection.
+func (c *Checker) Category() status.Category {
+	return status.CategoryAccess
+}
+
+// CheckStatus checks the npm registries in ~/.npmrc, whether each has an
+// auth token, and the Go module proxy settings.
+func (c *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
+	st := &status.ServiceStatus{
+		Name:        "pkgmgr",
+		Status:      status.StatusUnknown,
+		Current:     status.CurrentConfig{},
+		Credentials: status.CredentialStatus{},
+		LastUsed:    time.Now(),
+		Details:     make(map[string]string),
+	}
+
+	// Check if npm or go is available
+	hasNpm, hasGo := isCLIAvailable("npm"), isCLIAvailable("go")
+	if !hasNpm && !hasGo {
+		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
+		st.Details["error"] = "Neither npm nor go found"
+		return st, nil
+	}
+
+	rc, err := readNpmrc(c.npmrcPath)
+	if err != nil {
+		st.Status = status.StatusError
+		st.Details["error"] = err.Error()
+		return st, nil
+	}
+
+	registry := rc.get(npmRegistryKey)
+	scopes := rc.scopeRegistries()
+	configured := registry != "" || len(scopes) > 0
+	if registry == "" {
+		registry = DefaultNpmRegistry
+	}
+	st.Current.Context = registry
+	st.Details["npm_registry"] = registry
+
+	// Registries other than the public one usually need a token
+	var missing []string
+	if registry != DefaultNpmRegistry && !rc.hasAuthToken(registry) {
+		missing = append(missing, registry)
+	}
+	scopeList := make([]string, 0, len(scopes))
+	for _, scope := range sortedScopes(scopes) {
+		scopeList = append(scopeList, scope+"="+scopes[scope])
+		if !rc.hasAuthToken(scopes[scope]) {
+			missing = append(missing, scopes[scope])
+		}
+	}
+	if len(scopeList) > 0 {
+		st.Details["npm_scope_registries"] = strings.Join(scopeList, ", ")
+	}
+	st.Details["npm_auth_token"] = authTokenDetail(rc.hasAuthToken(registry))
+
+	st.Credentials = status.CredentialStatus{Valid: true, Type: "npm-token"}
+	if len(missing) > 0 {
+		st.Credentials.Warning = fmt.Sprintf("No npm auth token for %s", strings.Join(missing, ", "))
+	}
+
+	if hasGo {
+		vars, err := effectiveGoEnv(ctx, goPrivateVar, goProxyVar, "GONOSUMDB")
+		if err != nil {
+			st.Status = status.StatusError
+			st.Details["error"] = fmt.Sprintf("Failed to read go environment: %v", err)
+			return st, nil
+		}
+		st.Details["go_private"] = vars[goPrivateVar]
+		st.Details["go_proxy"] = vars[goProxyVar]
+		st.Details["go_nosumdb"] = vars["GONOSUMDB"]
+		if vars[goPrivateVar] != "" || (vars[goProxyVar] != "" && vars[goProxyVar] != defaultGoProxy) {
+			configured = true
+		}
+	}
+
+	if !configured {
+		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotConfigured
+		st.Details["error"] = "No private registries or Go proxy settings configured"
+		return st, nil
+	}
+	st.Status = status.StatusActive
+	return st, nil
+}
+
+// authTokenDetail describes whether a token is present, for Details.
+func authTokenDetail(present bool) string {
+	if present {
+		return "present"
+	}
+	return "absent"
+}
+
+// CheckHealth performs detailed health check for npm and go.
+func (c *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
+	start := time.Now()
+	health := &status.HealthStatus{
+		Status:    status.StatusUnknown,
+		CheckedAt: start,
+		Details:   make(map[string]interface{}),
+	}
+
+	var found []string
+	for _, cli := range []struct{ name, arg, key string }{
+		{"npm", "--version", "npm_version"},
+		{"go", "version", "go_version"},
+	} {
+		if !isCLIAvailable(cli.name) {
+			continue
+		}
+		output, err := environment.RunCommand(ctx, cli.name, cli.arg)
+		if err != nil {
+			health.Duration = time.Since(start)
+			health.Status = status.StatusError
+			health.Message = fmt.Sprintf("Failed to run %s: %v", cli.name, err)
+			return health, nil
+		}
+		health.Details[cli.key] = strings.TrimSpace(string(output))
+		found = append(found, cli.name)
+	}
+	health.Duration = time.Since(start)
+
+	if len(found) == 0 {
+		health.Status = status.StatusInactive
+		health.Message = "Neither npm nor go is installed"
+		return health, nil
+	}
+	health.Status = status.StatusActive
+	health.Message = fmt.Sprintf("%s installed", strings.Join(found, " and "))
+	return health, nil
+}
+
+// isCLIAvailable checks if name is installed.
+func isCLIAvailable(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// TestNewChecker verifies the constructor creates a valid checker.
+func TestNewChecker(t *testing.T) {
+	checker := NewChecker()
+	if checker == nil {
+		t.Fatal("NewChecker() returned nil")
+	}
+}
+
+// TestChecker_Name verifies the service name.
+func TestChecker_Name(t *testing.T) {
+	if got := NewChecker().Name(); got != "pkgmgr" {
+		t.Errorf("Name() = %q, want %q", got, "pkgmgr")
+	}
+}
+
+// TestChecker_ImplementsInterface verifies Checker implements ServiceChecker.
+func TestChecker_ImplementsInterface(t *testing.T) {
+	var _ status.ServiceChecker = (*Checker)(nil)
+	var _ status.Categorizer = (*Checker)(nil)
+}
+
+// TestChecker_CheckStatus tests the registries and token presence reported
+// for different configurations.
+func TestChecker_CheckStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		npmrc       string
+		goEnv       map[string]string
+		wantStatus  status.StatusType
+		wantReason  status.Reason
+		wantDetails map[string]string
+		wantWarning string
+	}{
+		{
+			name:       "defaults",
+			wantStatus: status.StatusInactive,
+			wantReason: status.ReasonNotConfigured,
+			wantDetails: map[string]string{
+				"npm_registry":   DefaultNpmRegistry,
+				"npm_auth_token": "absent",
+				"go_proxy":       defaultGoProxy,
+				"go_private":     "",
+			},
+		},
+		{
+			name:       "private registry with token",
+			npmrc:      "registry=https://npm.acme.example/\n//npm.acme.example/:_authToken=s3cr3t-token\n",
+			wantStatus: status.StatusActive,
+			wantDetails: map[string]string{
+				"npm_registry":   "https://npm.acme.example/",
+				"npm_auth_token": "present",
+			},
+		},
+		{
+			name:        "scope registry without token",
+			npmrc:       "@acme:registry=https://npm.acme.example/\n@tools:registry=https://tools.example.com/\n//tools.example.com/:_authToken=s3cr3t-token\n",
+			wantStatus:  status.StatusActive,
+			wantWarning: "No npm auth token for https://npm.acme.example/",
+			wantDetails: map[string]string{
+				"npm_registry":         DefaultNpmRegistry,
+				"npm_scope_registries": "@acme=https://npm.acme.example/, @tools=https://tools.example.com/",
+			},
+		},
+		{
+			name:       "go private modules",
+			goEnv:      map[string]string{goPrivateVar: "github.com/acme/*"},
+			wantStatus: status.StatusActive,
+			wantDetails: map[string]string{
+				"go_private": "github.com/acme/*",
+				"go_nosumdb": "github.com/acme/*",
+				"go_proxy":   defaultGoProxy,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			installCLIs(t, tt.goEnv)
+			checker := &Checker{npmrcPath: writeNpmrc(t, tt.npmrc)}
+
+			st, err := checker.CheckStatus(context.Background())
+			if err != nil {
+				t.Fatalf("CheckStatus() error = %v", err)
+			}
+			if st.Status != tt.wantStatus || st.Reason != tt.wantReason {
+				t.Errorf("CheckStatus() = %v, %q, want %v, %q", st.Status, st.Reason, tt.wantStatus, tt.wantReason)
+			}
+			if st.Credentials.Warning != tt.wantWarning {
+				t.Errorf("Credentials.Warning = %q, want %q", st.Credentials.Warning, tt.wantWarning)
+			}
+			for key, want := range tt.wantDetails {
+				if st.Details[key] != want {
+					t.Errorf("Details[%s] = %q, want %q", key, st.Details[key], want)
+				}
+			}
+			for key, value := range st.Details {
+				if strings.Contains(value, "s3cr3t") {
+					t.Errorf("Details[%s] = %q, want the token left out", key, value)
+				}
+			}
+		})
+	}
+}
+
+// TestChecker_CheckStatus_NotInstalled tests that missing CLIs are
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
+
+// TestChecker_CheckHealth tests the versions reported by the health check.
+func TestChecker_CheckHealth(t *testing.T) {
+	installCLIs(t, nil)
+
+	health, err := NewChecker().CheckHealth(context.Background())
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if health.Status != status.StatusActive || health.Message != "npm and go installed" {
+		t.Errorf("CheckHealth() = %v, %q, want %v, %q", health.Status, health.Message, status.StatusActive, "npm and go installed")
+	}
+	if health.Details["npm_version"] != "10.8.2" || health.Details["go_version"] != "go version go1.25.0 linux/amd64" {
+		t.Errorf("Details = %v, want the npm and go versions", health.Details)
+	}
+}
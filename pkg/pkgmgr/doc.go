@@ -0,0 +1,9 @@
+// Package pkgmgr provides implementations for switching and checking the
+// package registries npm and Go fetch from.
+//
+// This package implements:
+//   - PkgMgrSwitcher: Sets the default and per-scope npm registries in
+//     ~/.npmrc and GOPRIVATE and GOPROXY with `go env -w`
+//   - PkgMgrChecker: Reports the registries and Go proxy settings in effect
+//     and whether npm has an auth token for each registry, without the token
+package pkgmgr
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// Go environment variables the switcher sets.
+const (
+	goPrivateVar = "GOPRIVATE"
+	goProxyVar   = "GOPROXY"
+)
+
+// readGoEnvFile returns the variables set with `go env -w`, which go keeps
+// in the file `go env GOENV` names. Variables only in the OS environment or
+// at their defaults are not included. Without go installed nothing can have
+// been set, so the result is empty.
+func readGoEnvFile(ctx context.Context) (map[string]string, error) {
+	vars := make(map[string]string)
+	output, err := environment.RunCommand(ctx, "go", "env", "GOENV")
+	if errors.Is(err, exec.ErrNotFound) {
+		return vars, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate the go environment file: %w", err)
+	}
+
+	path := strings.TrimSpace(string(output))
+	if path == "" || path == "off" {
+		return vars, nil
+	}
+	// #nosec G304 - The path is go's own environment file
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return vars, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the go environment file: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			vars[key] = value
+		}
+	}
+	return vars, nil
+}
+
+// writeGoEnv sets each variable in vars with `go env -w`, or unsets it
+// with `go env -u` if its value is empty.
+func writeGoEnv(ctx context.Context, vars map[string]string) error {
+	var set, unset []string
+	for _, key := range []string{goPrivateVar, goProxyVar} {
+		value, ok := vars[key]
+		switch {
+		case !ok:
+		case value == "":
+			unset = append(unset, key)
+		default:
+			set = append(set, key+"="+value)
+		}
+	}
+
+	if len(set) > 0 {
+		if _, err := environment.RunCommand(ctx, "go", append([]string{"env", "-w"}, set...)...); err != nil {
+			return fmt.Errorf("failed to set %s: %w", strings.Join(set, " "), err)
+		}
+	}
+	if len(unset) > 0 {
+		if _, err := environment.RunCommand(ctx, "go", append([]string{"env", "-u"}, unset...)...); err != nil {
+			return fmt.Errorf("failed to unset %s: %w", strings.Join(unset, " "), err)
+		}
+	}
+	return nil
+}
+
+// effectiveGoEnv returns the values go uses for keys, wherever they are
+// set, including defaults.
+func effectiveGoEnv(ctx context.Context, keys ...string) (map[string]string, error) {
+	output, err := environment.RunCommand(ctx, "go", append([]string{"env"}, keys...)...)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	vars := make(map[string]string, len(keys))
+	for i, key := range keys {
+		if i < len(lines) {
+			vars[key] = strings.TrimSpace(lines[i])
+		}
+	}
+	return vars, nil
+}
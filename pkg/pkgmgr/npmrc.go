@@ -0,0 +1,174 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultNpmRegistry is the registry npm uses when ~/.npmrc sets none.
+const DefaultNpmRegistry = "https://registry.npmjs.org/"
+
+// npmRegistryKey is the ~/.npmrc key of the default registry. Scope
+// registries are keyed "@scope:registry".
+const npmRegistryKey = "registry"
+
+// DefaultNpmrcPath returns ~/.npmrc, the per-user npm configuration.
+func DefaultNpmrcPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".npmrc")
+}
+
+// npmrc is the content of an .npmrc file, line by line. Lines other than
+// the registry settings, such as auth tokens and comments, are kept as
+// they are.
+type npmrc struct {
+	lines []string
+}
+
+// readNpmrc reads the .npmrc file at path. A missing file is empty.
+func readNpmrc(path string) (*npmrc, error) {
+	// #nosec G304 - The path is the user's npm configuration
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &npmrc{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return &npmrc{lines: strings.Split(strings.TrimRight(string(data), "\n"), "\n")}, nil
+}
+
+// write replaces the file at path with n. The file is replaced atomically,
+// keeping its permissions, since it may hold auth tokens.
+func (n *npmrc) write(path string) error {
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	data := ""
+	if len(n.lines) > 0 {
+		data = strings.Join(n.lines, "\n") + "\n"
+	}
+	tmp := path + ".dev-env-tmp"
+	if err := os.WriteFile(tmp, []byte(data), mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// parseLine returns the key and value of an .npmrc line, or ok false for
+// blank lines and comments.
+func parseLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return "", "", false
+	}
+	key, value, ok = strings.Cut(line, "=")
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value), true
+}
+
+// get returns the value of key, or "" if it is not set.
+func (n *npmrc) get(key string) string {
+	value := ""
+	for _, line := range n.lines {
+		if k, v, ok := parseLine(line); ok && k == key {
+			value = v
+		}
+	}
+	return value
+}
+
+// set sets key to value, replacing the lines that set it, or removes them
+// if value is empty. It reports whether anything changed.
+func (n *npmrc) set(key, value string) bool {
+	if n.get(key) == value {
+		return false
+	}
+
+	var lines []string
+	replaced := false
+	for _, line := range n.lines {
+		if k, _, ok := parseLine(line); ok && k == key {
+			if value != "" && !replaced {
+				lines = append(lines, key+"="+value)
+				replaced = true
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if value != "" && !replaced {
+		lines = append(lines, key+"="+value)
+	}
+	n.lines = lines
+	return true
+}
+
+// scopeRegistries returns the registries set for npm scopes, by scope.
+func (n *npmrc) scopeRegistries() map[string]string {
+	registries := make(map[string]string)
+	for _, line := range n.lines {
+		key, value, ok := parseLine(line)
+		if !ok {
+			continue
+		}
+		if scope, found := strings.CutSuffix(key, ":"+npmRegistryKey); found && strings.HasPrefix(scope, "@") {
+			registries[scope] = value
+		}
+	}
+	return registries
+}
+
+// hasAuthToken reports whether n holds credentials for registry: an
+// _authToken, _auth, or _password keyed by a prefix of the registry URL
+// without its scheme, as in "//npm.example.com/:_authToken".
+func (n *npmrc) hasAuthToken(registry string) bool {
+	u, err := url.Parse(registry)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	target := "//" + u.Host + strings.TrimSuffix(u.Path, "/") + "/"
+
+	for _, line := range n.lines {
+		key, value, ok := parseLine(line)
+		if !ok || value == "" || !strings.HasPrefix(key, "//") {
+			continue
+		}
+		// The field follows the last colon; the URL may hold a port.
+		i := strings.LastIndex(key, ":")
+		prefix, field := key[2:max(i, 2)], key[i+1:]
+		if field != "_authToken" && field != "_auth" && field != "_password" {
+			continue
+		}
+		if strings.HasPrefix(target, "//"+strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedScopes returns the scopes of registries in order.
+func sortedScopes(registries map[string]string) []string {
+	scopes := make([]string, 0, len(registries))
+	for scope := range registries {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+	return scopes
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestNpmrc_Set tests setting and removing keys while keeping other lines.
+func TestNpmrc_Set(t *testing.T) {
+	tests := []struct {
+		name        string
+		lines       []string
+		key         string
+		value       string
+		want        []string
+		wantChanged bool
+	}{
+		{
+			name:        "append",
+			lines:       []string{"; comment", "save-exact=true"},
+			key:         "registry",
+			value:       "https://npm.example.com/",
+			want:        []string{"; comment", "save-exact=true", "registry=https://npm.example.com/"},
+			wantChanged: true,
+		},
+		{
+			name:        "replace in place",
+			lines:       []string{"registry = https://old.example.com/", "save-exact=true"},
+			key:         "registry",
+			value:       "https://npm.example.com/",
+			want:        []string{"registry=https://npm.example.com/", "save-exact=true"},
+			wantChanged: true,
+		},
+		{
+			name:        "remove duplicates",
+			lines:       []string{"@acme:registry=https://a.example.com/", "x=1", "@acme:registry=https://b.example.com/"},
+			key:         "@acme:registry",
+			value:       "https://npm.example.com/",
+			want:        []string{"@acme:registry=https://npm.example.com/", "x=1"},
+			wantChanged: true,
+		},
+		{
+			name:        "unset",
+			lines:       []string{"registry=https://npm.example.com/", "x=1"},
+			key:         "registry",
+			value:       "",
+			want:        []string{"x=1"},
+			wantChanged: true,
+		},
+		{
+			name:        "unchanged",
+			lines:       []string{"registry=https://npm.example.com/"},
+			key:         "registry",
+			value:       "https://npm.example.com/",
+			want:        []string{"registry=https://npm.example.com/"},
+			wantChanged: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &npmrc{lines: tt.lines}
+			if changed := rc.set(tt.key, tt.value); changed != tt.wantChanged {
+				t.Errorf("set() = %v, want %v", changed, tt.wantChanged)
+			}
+			if !reflect.DeepEqual(rc.lines, tt.want) {
+				t.Errorf("lines = %q, want %q", rc.lines, tt.want)
+			}
+		})
+	}
+}
+
+// TestNpmrc_ScopeRegistries tests reading the registries set for scopes.
+func TestNpmrc_ScopeRegistries(t *testing.T) {
+	rc := &npmrc{lines: []string{
+		"registry=https://npm.example.com/",
+		"@acme:registry=https://npm.acme.example/",
+		"# @old:registry=https://old.example.com/",
+		"@tools:registry = https://tools.example.com/",
+	}}
+	want := map[string]string{"@acme": "https://npm.acme.example/", "@tools": "https://tools.example.com/"}
+	if got := rc.scopeRegistries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("scopeRegistries() = %v, want %v", got, want)
+	}
+}
+
+// TestNpmrc_HasAuthToken tests matching credentials to registry URLs.
+func TestNpmrc_HasAuthToken(t *testing.T) {
+	rc := &npmrc{lines: []string{
+		"//npm.example.com/:_authToken=secret",
+		"//registry.local:4873/:_auth=c2VjcmV0",
+		"//pkgs.example.com/npm/private/:_authToken=secret",
+		"//empty.example.com/:_authToken=",
+		"//npm.acme.example/:always-auth=true",
+	}}
+	tests := []struct {
+		registry string
+		want     bool
+	}{
+		{"https://npm.example.com/", true},
+		{"https://npm.example.com", true},
+		{"http://registry.local:4873/", true},
+		{"https://pkgs.example.com/npm/private/", true},
+		{"https://pkgs.example.com/npm/public/", false},
+		{"https://empty.example.com/", false},
+		{"https://npm.acme.example/", false},
+		{"https://registry.npmjs.org/", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			if got := rc.hasAuthToken(tt.registry); got != tt.want {
+				t.Errorf("hasAuthToken(%q) = %v, want %v", tt.registry, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestNpmrc_Write tests that writing keeps the file's permissions.
+func TestNpmrc_Write(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".npmrc")
+	if err := os.WriteFile(path, []byte("//npm.example.com/:_authToken=secret\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := readNpmrc(path)
+	if err != nil {
+		t.Fatalf("readNpmrc() error = %v", err)
+	}
+	rc.set("registry", "https://npm.example.com/")
+	if err := rc.write(path); err != nil {
+		t.Fatalf("write() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 - test file
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "//npm.example.com/:_authToken=secret\nregistry=https://npm.example.com/\n"; string(data) != want {
+		t.Errorf("file = %q, want %q", data, want)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o640 {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), os.FileMode(0o640))
+	}
+
+	missing, err := readNpmrc(filepath.Join(t.TempDir(), ".npmrc"))
+	if err != nil || len(missing.lines) != 0 || !strings.HasSuffix(DefaultNpmrcPath(), ".npmrc") {
+		t.Errorf("readNpmrc() of a missing file = %v, %v, want empty", missing, err)
+	}
+}
@@ -0,0 +1,195 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// Switcher implements environment.ServiceSwitcher for package registries:
+// npm's in ~/.npmrc and Go's module proxy settings.
+type Switcher struct {
+	npmrcPath string
+}
+
+// NewSwitcher creates a new package registry switcher.
+func NewSwitcher() *Switcher {
+	return &Switcher{npmrcPath: DefaultNpmrcPath()}
+}
+
+// Name returns the service name.
+func (s *Switcher) Name() string {
+	return "pkgmgr"
+}
+
+// Claims returns the shared resources the switcher modifies: the npm
+// configuration and Go's environment file.
+func (s *Switcher) Claims() []string {
+	return []string{environment.ClaimNpmrc, environment.ClaimGoEnv}
+}
+
+// Validate checks that config is a package registry configuration that
+// sets something, with http or https registry URLs and scopes starting
+// with @.
+func (s *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.PkgMgrConfig)
+	if !ok {
+		return fmt.Errorf("invalid package manager configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no package manager configuration provided")
+	}
+	if c.NpmRegistry == "" && len(c.NpmScopeRegistries) == 0 && c.GoPrivate == "" && c.GoProxy == "" {
+		return fmt.Errorf("package manager configuration sets none of npmRegistry, npmScopeRegistries, goPrivate, and goProxy")
+	}
+
+	if c.NpmRegistry != "" {
+		if err := validateRegistry(c.NpmRegistry); err != nil {
+			return fmt.Errorf("npmRegistry: %w", err)
+		}
+	}
+	for _, scope := range sortedScopes(c.NpmScopeRegistries) {
+		if !strings.HasPrefix(scope, "@") || len(scope) == 1 {
+			return fmt.Errorf("npmScopeRegistries: scope %q must start with @", scope)
+		}
+		if err := validateRegistry(c.NpmScopeRegistries[scope]); err != nil {
+			return fmt.Errorf("npmScopeRegistries: %s: %w", scope, err)
+		}
+	}
+	return nil
+}
+
+// validateRegistry checks that registry is an http or https URL with a host.
+func validateRegistry(registry string) error {
+	u, err := url.Parse(registry)
+	if err != nil {
+		return fmt.Errorf("invalid registry URL %q: %w", registry, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("registry URL %q must be http or https with a host", registry)
+	}
+	return nil
+}
+
+// RequiredCLIs returns go if config sets Go settings. npm settings are
+// written to ~/.npmrc directly.
+func (s *Switcher) RequiredCLIs(config interface{}) []string {
+	if c, ok := config.(*environment.PkgMgrConfig); ok && c != nil && (c.GoPrivate != "" || c.GoProxy != "") {
+		return []string{"go"}
+	}
+	return nil
+}
+
+// Switch sets the registries and Go settings given in config, leaving
+// unset fields and other scopes unchanged.
+func (s *Switcher) Switch(ctx context.Context, config interface{}) error {
+	c, ok := config.(*environment.PkgMgrConfig)
+	if !ok || c == nil {
+		return fmt.Errorf("invalid package manager configuration type")
+	}
+
+	if c.NpmRegistry != "" || len(c.NpmScopeRegistries) > 0 {
+		if err := s.updateNpmrc(ctx, func(rc *npmrc) bool {
+			changed := c.NpmRegistry != "" && rc.set(npmRegistryKey, c.NpmRegistry)
+			for _, scope := range sortedScopes(c.NpmScopeRegistries) {
+				changed = rc.set(scope+":"+npmRegistryKey, c.NpmScopeRegistries[scope]) || changed
+			}
+			return changed
+		}); err != nil {
+			return err
+		}
+	}
+
+	vars := make(map[string]string)
+	if c.GoPrivate != "" {
+		vars[goPrivateVar] = c.GoPrivate
+	}
+	if c.GoProxy != "" {
+		vars[goProxyVar] = c.GoProxy
+	}
+	if len(vars) > 0 {
+		return writeGoEnv(ctx, vars)
+	}
+	return nil
+}
+
+// GetCurrentState retrieves the registries in ~/.npmrc and the Go settings
+// set with `go env -w`. Settings that are not set are empty, so Rollback
+// removes them again.
+func (s *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
+	rc, err := readNpmrc(s.npmrcPath)
+	if err != nil {
+		return nil, err
+	}
+	vars, err := readGoEnvFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &environment.PkgMgrConfig{
+		NpmRegistry: rc.get(npmRegistryKey),
+		GoPrivate:   vars[goPrivateVar],
+		GoProxy:     vars[goProxyVar],
+	}
+	if scopes := rc.scopeRegistries(); len(scopes) > 0 {
+		state.NpmScopeRegistries = scopes
+	}
+	return state, nil
+}
+
+// Rollback restores a state returned by GetCurrentState, removing the
+// registries and Go settings that were not set then.
+func (s *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
+	previous, ok := previousState.(*environment.PkgMgrConfig)
+	if !ok || previous == nil {
+		return fmt.Errorf("invalid package manager state type")
+	}
+
+	if err := s.updateNpmrc(ctx, func(rc *npmrc) bool {
+		changed := rc.set(npmRegistryKey, previous.NpmRegistry)
+		for scope := range rc.scopeRegistries() {
+			if _, ok := previous.NpmScopeRegistries[scope]; !ok {
+				changed = rc.set(scope+":"+npmRegistryKey, "") || changed
+			}
+		}
+		for _, scope := range sortedScopes(previous.NpmScopeRegistries) {
+			changed = rc.set(scope+":"+npmRegistryKey, previous.NpmScopeRegistries[scope]) || changed
+		}
+		return changed
+	}); err != nil {
+		return err
+	}
+
+	// Only touch the Go settings that differ, so go is not needed when
+	// they were never changed.
+	current, err := readGoEnvFile(ctx)
+	if err != nil {
+		return err
+	}
+	vars := make(map[string]string)
+	for key, value := range map[string]string{goPrivateVar: previous.GoPrivate, goProxyVar: previous.GoProxy} {
+		if current[key] != value {
+			vars[key] = value
+		}
+	}
+	return writeGoEnv(ctx, vars)
+}
+
+// updateNpmrc applies update to ~/.npmrc, writing the file if update
+// reports a change. Under environment.Simulate the file is left alone.
+func (s *Switcher) updateNpmrc(ctx context.Context, update func(*npmrc) bool) error {
+	rc, err := readNpmrc(s.npmrcPath)
+	if err != nil {
+		return err
+	}
+	if !update(rc) || environment.Simulating(ctx) {
+		return nil
+	}
+	return rc.write(s.npmrcPath)
+}
@@ -0,0 +1,311 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package pkgmgr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// fakeCLIs are fake go and npm scripts on PATH. go keeps the variables set
+// with `go env -w` in envFile, as the real one does.
+type fakeCLIs struct {
+	envFile string
+	log     string
+}
+
+// installCLIs puts fake go and npm scripts on PATH, with goEnv set in go's
+// environment file.
+func installCLIs(t *testing.T, goEnv map[string]string) *fakeCLIs {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go and npm are shell scripts")
+	}
+
+	dir := t.TempDir()
+	f := &fakeCLIs{envFile: filepath.Join(dir, "goenv"), log: filepath.Join(dir, "cli.log")}
+	var lines []string
+	for key, value := range goEnv {
+		lines = append(lines, key+"="+value+"\n")
+	}
+	if err := os.WriteFile(f.envFile, []byte(strings.Join(lines, "")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	goScript := `#!/bin/sh
+echo "go $*" >> "` + f.log + `"
+envfile="` + f.envFile + `"
+[ "$1" = version ] && { echo "go version go1.25.0 linux/amd64"; exit 0; }
+[ "$1" = env ] || exit 2
+shift
+drop() { grep -v "^$1=" "$envfile" > "$envfile.tmp"; mv "$envfile.tmp" "$envfile"; }
+case "$1" in
+-w) shift; for kv in "$@"; do drop "${kv%%=*}"; echo "$kv" >> "$envfile"; done ;;
+-u) shift; for key in "$@"; do drop "$key"; done ;;
+*)
+	for key in "$@"; do
+		value=$(grep "^$key=" "$envfile" | cut -d= -f2-)
+		case "$key" in
+		GOENV) value="$envfile" ;;
+		GOPROXY) [ -n "$value" ] || value=https://proxy.golang.org,direct ;;
+		GONOSUMDB) [ -n "$value" ] || value=$(grep "^GOPRIVATE=" "$envfile" | cut -d= -f2-) ;;
+		esac
+		echo "$value"
+	done ;;
+esac
+`
+	npmScript := `#!/bin/sh
+[ "$1" = --version ] && echo "10.8.2"
+`
+	for name, script := range map[string]string{"go": goScript, "npm": npmScript} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0o700); err != nil { // #nosec G306 - test executable
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return f
+}
+
+// goEnv returns the variables in the fake go's environment file.
+func (f *fakeCLIs) goEnv(t *testing.T) map[string]string {
+	t.Helper()
+	vars, err := readGoEnvFile(context.Background())
+	if err != nil {
+		t.Fatalf("readGoEnvFile() error = %v", err)
+	}
+	return vars
+}
+
+// writeNpmrc writes content to an .npmrc in a temporary directory and
+// returns its path. Empty content leaves the file missing.
+func writeNpmrc(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".npmrc")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return path
+}
+
+// readFile returns the content of path, or "" if it does not exist.
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path) // #nosec G304 - test file
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestNewSwitcher verifies the constructor creates a valid switcher.
+func TestNewSwitcher(t *testing.T) {
+	switcher := NewSwitcher()
+	if switcher == nil {
+		t.Fatal("NewSwitcher() returned nil")
+	}
+}
+
+// TestSwitcher_Name verifies the service name.
+func TestSwitcher_Name(t *testing.T) {
+	if got := NewSwitcher().Name(); got != "pkgmgr" {
+		t.Errorf("Name() = %q, want %q", got, "pkgmgr")
+	}
+}
+
+// TestSwitcher_ImplementsInterface verifies Switcher implements ServiceSwitcher.
+func TestSwitcher_ImplementsInterface(t *testing.T) {
+	var _ environment.ServiceSwitcher = (*Switcher)(nil)
+	var _ environment.ResourceClaimer = (*Switcher)(nil)
+	var _ environment.ConfigValidator = (*Switcher)(nil)
+	var _ environment.CLIRequirer = (*Switcher)(nil)
+}
+
+// TestSwitcher_Conformance tests that a switch is reported by
+// GetCurrentState and undone by Rollback, following the steps of
+// environment.VerifyExecSwitcher.
+func TestSwitcher_Conformance(t *testing.T) {
+	tests := []struct {
+		name      string
+		npmrc     string
+		goEnv     map[string]string
+		config    *environment.PkgMgrConfig
+		wantNpmrc string
+		wantGoEnv map[string]string
+		wantState *environment.PkgMgrConfig
+	}{
+		{
+			name:  "from public to private registries",
+			npmrc: "; work laptop\nregistry=https://registry.npmjs.org/\n//npm.acme.example/:_authToken=secret\n",
+			goEnv: map[string]string{},
+			config: &environment.PkgMgrConfig{
+				NpmRegistry:        "https://npm.acme.example/",
+				NpmScopeRegistries: map[string]string{"@acme": "https://npm.acme.example/"},
+				GoPrivate:          "github.com/acme/*",
+				GoProxy:            "https://goproxy.acme.example,direct",
+			},
+			wantNpmrc: "; work laptop\nregistry=https://npm.acme.example/\n//npm.acme.example/:_authToken=secret\n@acme:registry=https://npm.acme.example/\n",
+			wantGoEnv: map[string]string{goPrivateVar: "github.com/acme/*", goProxyVar: "https://goproxy.acme.example,direct"},
+		},
+		{
+			name:  "scope only, keeping other scopes and go settings",
+			npmrc: "@tools:registry=https://tools.example.com/\n@acme:registry=https://old.acme.example/\n",
+			goEnv: map[string]string{goPrivateVar: "github.com/home/*"},
+			config: &environment.PkgMgrConfig{
+				NpmScopeRegistries: map[string]string{"@acme": "https://npm.acme.example/"},
+			},
+			wantNpmrc: "@tools:registry=https://tools.example.com/\n@acme:registry=https://npm.acme.example/\n",
+			wantGoEnv: map[string]string{goPrivateVar: "github.com/home/*"},
+			wantState: &environment.PkgMgrConfig{
+				NpmScopeRegistries: map[string]string{"@tools": "https://tools.example.com/", "@acme": "https://npm.acme.example/"},
+				GoPrivate:          "github.com/home/*",
+			},
+		},
+		{
+			name:      "go only, from nothing set",
+			goEnv:     map[string]string{},
+			config:    &environment.PkgMgrConfig{GoPrivate: "gitlab.acme.example"},
+			wantGoEnv: map[string]string{goPrivateVar: "gitlab.acme.example"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cli := installCLIs(t, tt.goEnv)
+			switcher := &Switcher{npmrcPath: writeNpmrc(t, tt.npmrc)}
+			ctx := context.Background()
+
+			original, err := switcher.GetCurrentState(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentState() error = %v", err)
+			}
+			if err := switcher.Validate(tt.config); err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+			if err := switcher.Switch(ctx, tt.config); err != nil {
+				t.Fatalf("Switch() error = %v", err)
+			}
+			if got := readFile(t, switcher.npmrcPath); got != tt.wantNpmrc {
+				t.Errorf(".npmrc after Switch() = %q, want %q", got, tt.wantNpmrc)
+			}
+			if got := cli.goEnv(t); !reflect.DeepEqual(got, tt.wantGoEnv) {
+				t.Errorf("go env after Switch() = %v, want %v", got, tt.wantGoEnv)
+			}
+
+			switched, err := switcher.GetCurrentState(ctx)
+			if err != nil {
+				t.Fatalf("GetCurrentState() error = %v", err)
+			}
+			want := tt.wantState
+			if want == nil {
+				want = tt.config
+			}
+			if !reflect.DeepEqual(switched, want) {
+				t.Errorf("GetCurrentState() after Switch() = %+v, want %+v", switched, want)
+			}
+
+			if err := switcher.Rollback(ctx, original); err != nil {
+				t.Fatalf("Rollback() error = %v", err)
+			}
+			if got := readFile(t, switcher.npmrcPath); got != tt.npmrc {
+				t.Errorf(".npmrc after Rollback() = %q, want %q", got, tt.npmrc)
+			}
+			if got := cli.goEnv(t); !reflect.DeepEqual(got, tt.goEnv) {
+				t.Errorf("go env after Rollback() = %v, want %v", got, tt.goEnv)
+			}
+			restored, err := switcher.GetCurrentState(ctx)
+			if err != nil || !reflect.DeepEqual(restored, original) {
+				t.Errorf("GetCurrentState() after Rollback() = %+v, %v, want %+v", restored, err, original)
+			}
+		})
+	}
+}
+
+// TestSwitcher_Rollback_WithoutGo tests that rolling back npm settings
+// does not need go when the Go settings were never changed.
+func TestSwitcher_Rollback_WithoutGo(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	switcher := &Switcher{npmrcPath: writeNpmrc(t, "registry=https://npm.example.com/\n")}
+	ctx := context.Background()
+
+	original, err := switcher.GetCurrentState(ctx)
+	if err != nil {
+		t.Fatalf("GetCurrentState() error = %v", err)
+	}
+	if err := switcher.Switch(ctx, &environment.PkgMgrConfig{NpmRegistry: "https://npm.acme.example/"}); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+	if err := switcher.Rollback(ctx, original); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if got, want := readFile(t, switcher.npmrcPath), "registry=https://npm.example.com/\n"; got != want {
+		t.Errorf(".npmrc after Rollback() = %q, want %q", got, want)
+	}
+}
+
+// TestSwitcher_Switch_InvalidConfigType tests error handling for invalid config type.
+func TestSwitcher_Switch_InvalidConfigType(t *testing.T) {
+	for _, config := range []interface{}{"invalid-config", nil, (*environment.PkgMgrConfig)(nil)} {
+		if err := NewSwitcher().Switch(context.Background(), config); err == nil || err.Error() != "invalid package manager configuration type" {
+			t.Errorf("Switch(%#v) error = %v, want %q", config, err, "invalid package manager configuration type")
+		}
+	}
+	if err := NewSwitcher().Rollback(context.Background(), "invalid-state"); err == nil {
+		t.Error("Rollback() with invalid state should return error")
+	}
+}
+
+// TestSwitcher_Validate tests rejecting configurations that set nothing or
+// name invalid registries.
+func TestSwitcher_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  interface{}
+		wantErr string
+	}{
+		{"registry", &environment.PkgMgrConfig{NpmRegistry: "https://npm.example.com/"}, ""},
+		{"go proxy", &environment.PkgMgrConfig{GoProxy: "off"}, ""},
+		{"scope", &environment.PkgMgrConfig{NpmScopeRegistries: map[string]string{"@acme": "http://localhost:4873/"}}, ""},
+		{"empty", &environment.PkgMgrConfig{}, "sets none of npmRegistry"},
+		{"registry without scheme", &environment.PkgMgrConfig{NpmRegistry: "npm.example.com"}, "must be http or https"},
+		{"scope without @", &environment.PkgMgrConfig{NpmScopeRegistries: map[string]string{"acme": "https://npm.example.com/"}}, `scope "acme" must start with @`},
+		{"scope registry", &environment.PkgMgrConfig{NpmScopeRegistries: map[string]string{"@acme": "ftp://npm.example.com/"}}, "@acme: registry URL"},
+		{"nil", (*environment.PkgMgrConfig)(nil), "no package manager configuration provided"},
+		{"wrong type", &environment.GPGConfig{}, "invalid package manager configuration type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewSwitcher().Validate(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// TestSwitcher_RequiredCLIs tests that go is needed only for Go settings.
+func TestSwitcher_RequiredCLIs(t *testing.T) {
+	s := NewSwitcher()
+	if got := s.RequiredCLIs(&environment.PkgMgrConfig{GoPrivate: "github.com/acme/*"}); !reflect.DeepEqual(got, []string{"go"}) {
+		t.Errorf("RequiredCLIs() = %v, want [go]", got)
+	}
+	if got := s.RequiredCLIs(&environment.PkgMgrConfig{NpmRegistry: "https://npm.example.com/"}); got != nil {
+		t.Errorf("RequiredCLIs() = %v, want none", got)
+	}
+}
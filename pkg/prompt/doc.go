@@ -0,0 +1,9 @@
+// Package prompt renders the active environment as a short shell prompt
+// segment from cached state only, so it is fast enough to run on every
+// prompt.
+//
+// This package implements:
+//   - Load: The last switch from the history and its services' cached statuses
+//   - State.Render: A status glyph and environment name, dimmed when stale
+//   - InitScript: Snippets embedding the segment in zsh, bash, and fish prompts
+package prompt
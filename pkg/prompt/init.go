@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Shells lists the shells InitScript supports.
+var Shells = []string{"zsh", "bash", "fish"}
+
+// initScripts are the prompt snippets per shell. COMMAND is replaced with
+// the command that prints the segment. Each snippet prepends the segment
+// to the existing prompt once, however often it is evaluated.
+var initScripts = map[string]string{
+	"zsh": `# dev-env prompt segment; add to ~/.zshrc: eval "$(COMMAND --init zsh)"
+__dev_env_prompt() {
+  local segment
+  segment="$(COMMAND --shell zsh 2>/dev/null)"
+  [[ -n "$segment" ]] && print -rn -- "($segment) "
+}
+setopt PROMPT_SUBST
+[[ "$PROMPT" == *__dev_env_prompt* ]] || PROMPT='$(__dev_env_prompt)'"$PROMPT"
+`,
+	"bash": `# dev-env prompt segment; add to ~/.bashrc: eval "$(COMMAND --init bash)"
+__dev_env_prompt() {
+  local segment
+  segment="$(COMMAND --shell bash 2>/dev/null)"
+  [ -n "$segment" ] && printf '(%s) ' "$segment"
+}
+case "$PS1" in
+  *__dev_env_prompt*) ;;
+  *) PS1='$(__dev_env_prompt)'"$PS1" ;;
+esac
+`,
+	"fish": `# dev-env prompt segment; add to ~/.config/fish/config.fish: COMMAND --init fish | source
+function __dev_env_prompt
+    set -l segment (COMMAND 2>/dev/null)
+    test -n "$segment"; and printf '(%s) ' "$segment"
+end
+if functions -q fish_prompt; and not functions -q __dev_env_original_prompt
+    functions -c fish_prompt __dev_env_original_prompt
+    function fish_prompt
+        __dev_env_prompt
+        __dev_env_original_prompt
+    end
+end
+`,
+}
+
+// InitScript returns the snippet that embeds the segment printed by command
+// in the prompt of shell.
+func InitScript(shell, command string) (string, error) {
+	script, ok := initScripts[shell]
+	if !ok {
+		return "", fmt.Errorf("unsupported shell %q (supported: %s)", shell, strings.Join(Shells, ", "))
+	}
+	return strings.ReplaceAll(script, "COMMAND", command), nil
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestInitScript tests the snippets for each supported shell.
+func TestInitScript(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"zsh", []string{"dev-env prompt --shell zsh", "setopt PROMPT_SUBST", "PROMPT='$(__dev_env_prompt)'"}},
+		{"bash", []string{"dev-env prompt --shell bash", "PS1='$(__dev_env_prompt)'"}},
+		{"fish", []string{"(dev-env prompt 2>/dev/null)", "function fish_prompt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			script, err := InitScript(tt.shell, "dev-env prompt")
+			if err != nil {
+				t.Fatalf("InitScript() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("InitScript() = %q, want it to contain %q", script, want)
+				}
+			}
+			if strings.Contains(script, "COMMAND") {
+				t.Errorf("InitScript() = %q, want the command substituted", script)
+			}
+		})
+	}
+
+	if _, err := InitScript("tcsh", "dev-env prompt"); err == nil {
+		t.Error("InitScript(tcsh) error = nil, want an unsupported shell error")
+	}
+}
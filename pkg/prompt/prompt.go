@@ -0,0 +1,179 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// DefaultStaleAfter is how old the known state may be before the segment
+// is rendered as stale.
+const DefaultStaleAfter = 15 * time.Minute
+
+// cacheTimeout bounds waiting for a process writing the status cache. The
+// prompt renders without statuses rather than hold up the shell.
+const cacheTimeout = 10 * time.Millisecond
+
+// ANSI color codes used for the segment.
+const (
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorDim    = "\033[2m"
+	colorReset  = "\033[0m"
+)
+
+// Health summarizes the last switch and the cached statuses of its services.
+type Health int
+
+const (
+	// HealthOK means the switch succeeded and no cached status disagrees.
+	HealthOK Health = iota
+	// HealthPartial means the switch left some services failed.
+	HealthPartial
+	// HealthDegraded means a service has been checked as inactive or
+	// erroring since the switch.
+	HealthDegraded
+)
+
+// glyph returns the symbol and color for h.
+func (h Health) glyph() (string, string) {
+	switch h {
+	case HealthPartial:
+		return "!", colorYellow
+	case HealthDegraded:
+		return "✗", colorRed
+	default:
+		return "✓", colorGreen
+	}
+}
+
+// Sources locates the cached state the segment is read from. Empty paths
+// use the defaults.
+type Sources struct {
+	HistoryPath     string
+	StatusCachePath string
+}
+
+// State is the active environment as known from cached state.
+type State struct {
+	Environment string
+	Health      Health
+	// AsOf is when the state was last known to hold: the oldest status
+	// checked since the switch, or the switch itself if none was.
+	AsOf time.Time
+}
+
+// Load reads the last switch from the history and the cached statuses of
+// the services it switched. It never runs a command. It returns nil if no
+// switch has been recorded. An unreadable or busy status cache is not an
+// error; the state then rests on the switch alone.
+func Load(src Sources) (*State, error) {
+	entries, err := environment.NewHistory(src.HistoryPath).Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	var last *environment.HistoryEntry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Type != environment.HistoryPartialRollback {
+			last = &entries[i]
+			break
+		}
+	}
+	if last == nil {
+		return nil, nil
+	}
+
+	state := &State{Environment: last.Environment, AsOf: last.SwitchedAt}
+	if len(last.Failed) > 0 {
+		state.Health = HealthPartial
+	}
+
+	cache, err := status.OpenStatusCacheReadOnly(src.StatusCachePath, cacheTimeout)
+	if err != nil {
+		return state, nil
+	}
+	defer cache.Close()
+
+	var checked []status.ServiceStatus
+	for _, service := range last.Services {
+		st, ok := cache.Latest(service)
+		// Statuses from before the switch describe the previous environment
+		if !ok || st.CheckedAt.Before(last.SwitchedAt) {
+			continue
+		}
+		checked = append(checked, *st)
+		if st.Status == status.StatusInactive || st.Status == status.StatusError {
+			state.Health = HealthDegraded
+		}
+	}
+	if asOf := status.AsOf(checked); !asOf.IsZero() {
+		state.AsOf = asOf
+	}
+	return state, nil
+}
+
+// Options controls how a State is rendered.
+type Options struct {
+	// Color renders the glyph colored and a stale name dimmed.
+	Color bool
+	// Shell is the shell the segment is embedded in, so color codes can be
+	// marked as zero-width: zsh or bash. Other shells need no marking.
+	Shell string
+	// StaleAfter is the age past which the state is stale; zero uses
+	// DefaultStaleAfter.
+	StaleAfter time.Duration
+}
+
+// Render returns the segment for s as of now: a status glyph and the
+// environment name, which is followed by an asterisk when stale.
+func (s *State) Render(now time.Time, opts Options) string {
+	staleAfter := opts.StaleAfter
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	stale := now.Sub(s.AsOf) > staleAfter
+
+	name := s.Environment
+	if opts.Shell == "zsh" {
+		name = strings.ReplaceAll(name, "%", "%%")
+	}
+	if stale {
+		name += "*"
+	}
+
+	glyph, color := s.Health.glyph()
+	if !opts.Color {
+		return glyph + " " + name
+	}
+
+	colorize := func(code, text string) string {
+		return zeroWidth(code, opts.Shell) + text + zeroWidth(colorReset, opts.Shell)
+	}
+	segment := colorize(color, glyph) + " "
+	if stale {
+		return segment + colorize(colorDim, name)
+	}
+	return segment + name
+}
+
+// zeroWidth marks an escape sequence as taking no space, so the shell
+// measures the prompt correctly.
+func zeroWidth(code, shell string) string {
+	switch shell {
+	case "zsh":
+		return "%{" + code + "%}"
+	case "bash":
+		// Readline's ignore markers; \[ and \] are not honored in the
+		// output of a command substitution
+		return "\001" + code + "\002"
+	default:
+		return code
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package prompt
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// latencyBudget is the most one Load and Render may take with warm caches.
+const latencyBudget = 50 * time.Millisecond
+
+// writeSources records entries in a history and statuses in a status cache
+// in a temporary directory.
+func writeSources(tb testing.TB, entries []environment.HistoryEntry, statuses []status.ServiceStatus) Sources {
+	tb.Helper()
+	dir := tb.TempDir()
+	src := Sources{
+		HistoryPath:     filepath.Join(dir, "history.json"),
+		StatusCachePath: filepath.Join(dir, "status.db"),
+	}
+
+	history := environment.NewHistory(src.HistoryPath)
+	for _, entry := range entries {
+		if err := history.Record(entry); err != nil {
+			tb.Fatalf("Record() error = %v", err)
+		}
+	}
+
+	if statuses != nil {
+		cache, err := status.OpenStatusCache(src.StatusCachePath)
+		if err != nil {
+			tb.Fatalf("OpenStatusCache() error = %v", err)
+		}
+		for i := range statuses {
+			if err := cache.Set(statuses[i].Name, &statuses[i], time.Minute); err != nil {
+				tb.Fatalf("Set() error = %v", err)
+			}
+		}
+		if err := cache.Close(); err != nil {
+			tb.Fatalf("Close() error = %v", err)
+		}
+	}
+	return src
+}
+
+// TestLoad tests the health and age derived from the last switch and the
+// cached statuses of its services.
+func TestLoad(t *testing.T) {
+	switchedAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	switched := environment.HistoryEntry{
+		Environment: "prod",
+		SwitchedAt:  switchedAt,
+		Services:    []string{"aws", "kubernetes"},
+	}
+
+	tests := []struct {
+		name       string
+		entries    []environment.HistoryEntry
+		statuses   []status.ServiceStatus
+		wantHealth Health
+		wantAsOf   time.Time
+	}{
+		{
+			name:       "no cache",
+			entries:    []environment.HistoryEntry{switched},
+			wantHealth: HealthOK,
+			wantAsOf:   switchedAt,
+		},
+		{
+			name:    "checked since the switch",
+			entries: []environment.HistoryEntry{switched},
+			statuses: []status.ServiceStatus{
+				{Name: "aws", Status: status.StatusActive, CheckedAt: switchedAt.Add(2 * time.Minute)},
+				{Name: "kubernetes", Status: status.StatusActive, CheckedAt: switchedAt.Add(time.Minute)},
+			},
+			wantHealth: HealthOK,
+			wantAsOf:   switchedAt.Add(time.Minute),
+		},
+		{
+			name:    "error since the switch",
+			entries: []environment.HistoryEntry{switched},
+			statuses: []status.ServiceStatus{
+				{Name: "aws", Status: status.StatusActive, CheckedAt: switchedAt.Add(time.Minute)},
+				{Name: "kubernetes", Status: status.StatusError, CheckedAt: switchedAt.Add(time.Minute)},
+			},
+			wantHealth: HealthDegraded,
+			wantAsOf:   switchedAt.Add(time.Minute),
+		},
+		{
+			name:    "error before the switch",
+			entries: []environment.HistoryEntry{switched},
+			statuses: []status.ServiceStatus{
+				{Name: "kubernetes", Status: status.StatusError, CheckedAt: switchedAt.Add(-time.Minute)},
+			},
+			wantHealth: HealthOK,
+			wantAsOf:   switchedAt,
+		},
+		{
+			name:    "service not switched",
+			entries: []environment.HistoryEntry{switched},
+			statuses: []status.ServiceStatus{
+				{Name: "docker", Status: status.StatusError, CheckedAt: switchedAt.Add(time.Minute)},
+			},
+			wantHealth: HealthOK,
+			wantAsOf:   switchedAt,
+		},
+		{
+			name: "partial switch",
+			entries: []environment.HistoryEntry{{
+				Environment: "prod",
+				SwitchedAt:  switchedAt,
+				Services:    []string{"aws"},
+				Failed:      []string{"kubernetes"},
+			}},
+			wantHealth: HealthPartial,
+			wantAsOf:   switchedAt,
+		},
+		{
+			name: "partial rollback skipped",
+			entries: []environment.HistoryEntry{switched, {
+				Type:        environment.HistoryPartialRollback,
+				Environment: "staging",
+				SwitchedAt:  switchedAt.Add(time.Hour),
+				Services:    []string{"aws"},
+			}},
+			wantHealth: HealthOK,
+			wantAsOf:   switchedAt,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state, err := Load(writeSources(t, tt.entries, tt.statuses))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if state == nil {
+				t.Fatal("Load() = nil, want a state")
+			}
+			if state.Environment != "prod" || state.Health != tt.wantHealth || !state.AsOf.Equal(tt.wantAsOf) {
+				t.Errorf("Load() = %q, %v, %v, want %q, %v, %v", state.Environment, state.Health, state.AsOf, "prod", tt.wantHealth, tt.wantAsOf)
+			}
+		})
+	}
+}
+
+// TestLoad_NoSwitch tests that there is no state without a recorded switch.
+func TestLoad_NoSwitch(t *testing.T) {
+	state, err := Load(writeSources(t, nil, nil))
+	if err != nil || state != nil {
+		t.Errorf("Load() = %v, %v, want nil, nil", state, err)
+	}
+}
+
+// TestState_Render tests the glyphs, colors, and staleness marker.
+func TestState_Render(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	fresh := now.Add(-time.Minute)
+	stale := now.Add(-time.Hour)
+
+	tests := []struct {
+		name  string
+		state State
+		opts  Options
+		want  string
+	}{
+		{
+			name:  "ok",
+			state: State{Environment: "prod", Health: HealthOK, AsOf: fresh},
+			want:  "✓ prod",
+		},
+		{
+			name:  "partial",
+			state: State{Environment: "prod", Health: HealthPartial, AsOf: fresh},
+			want:  "! prod",
+		},
+		{
+			name:  "degraded and stale",
+			state: State{Environment: "prod", Health: HealthDegraded, AsOf: stale},
+			want:  "✗ prod*",
+		},
+		{
+			name:  "custom threshold",
+			state: State{Environment: "prod", AsOf: fresh},
+			opts:  Options{StaleAfter: 30 * time.Second},
+			want:  "✓ prod*",
+		},
+		{
+			name:  "color",
+			state: State{Environment: "prod", AsOf: fresh},
+			opts:  Options{Color: true},
+			want:  "\033[32m✓\033[0m prod",
+		},
+		{
+			name:  "color stale",
+			state: State{Environment: "prod", Health: HealthPartial, AsOf: stale},
+			opts:  Options{Color: true},
+			want:  "\033[33m!\033[0m \033[2mprod*\033[0m",
+		},
+		{
+			name:  "zsh",
+			state: State{Environment: "100%", Health: HealthDegraded, AsOf: fresh},
+			opts:  Options{Color: true, Shell: "zsh"},
+			want:  "%{\033[31m%}✗%{\033[0m%} 100%%",
+		},
+		{
+			name:  "bash",
+			state: State{Environment: "prod", AsOf: fresh},
+			opts:  Options{Color: true, Shell: "bash"},
+			want:  "\001\033[32m\002✓\001\033[0m\002 prod",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.Render(now, tt.opts); got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// benchmarkSources returns sources with a full history and cached statuses
+// for every service, as after a while of regular use.
+func benchmarkSources(b *testing.B) Sources {
+	services := []string{"aws", "gcp", "azure", "docker", "kubernetes", "ssh", "gpg", "pkgmgr"}
+	now := time.Now()
+
+	entries := make([]environment.HistoryEntry, 0, 50)
+	for i := 0; i < 50; i++ {
+		entries = append(entries, environment.HistoryEntry{
+			SwitchID:    fmt.Sprintf("switch-%d", i),
+			Environment: fmt.Sprintf("env-%d", i%5),
+			SwitchedAt:  now.Add(time.Duration(i-50) * time.Minute),
+			Services:    services,
+		})
+	}
+	statuses := make([]status.ServiceStatus, 0, len(services))
+	for _, service := range services {
+		statuses = append(statuses, status.ServiceStatus{
+			Name:      service,
+			Status:    status.StatusActive,
+			Details:   map[string]string{"profile": "default", "region": "us-east-1"},
+			CheckedAt: now,
+		})
+	}
+	return writeSources(b, entries, statuses)
+}
+
+// BenchmarkLoadRender measures the prompt's fast path with warm caches.
+func BenchmarkLoadRender(b *testing.B) {
+	src := benchmarkSources(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		state, err := Load(src)
+		if err != nil || state == nil {
+			b.Fatalf("Load() = %v, %v, want a state", state, err)
+		}
+		_ = state.Render(time.Now(), Options{Color: true, Shell: "zsh"})
+	}
+}
+
+// TestLoadRender_LatencyBudget tests that the fast path stays within the
+// latency budget of a shell prompt.
+func TestLoadRender_LatencyBudget(t *testing.T) {
+	result := testing.Benchmark(BenchmarkLoadRender)
+	if result.N == 0 {
+		t.Fatal("BenchmarkLoadRender failed")
+	}
+	if got := time.Duration(result.NsPerOp()); got > latencyBudget {
+		t.Errorf("Load() and Render() took %v, want at most %v", got, latencyBudget)
+	}
+}
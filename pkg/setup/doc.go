@@ -0,0 +1,9 @@
+// Package setup provides the first-run wizard behind `dev-env init`.
+//
+// This package implements:
+//   - Wizard: Detects installed CLIs, records enabled services, snapshots
+//     the current state, and installs shell integration, one skippable step
+//     at a time
+//   - Prompter: Injected question answering, interactive or defaults-only
+//   - InstallShellHook: Idempotent shell rc file integration
+package setup
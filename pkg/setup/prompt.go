@@ -0,0 +1,128 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package setup
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prompter asks the user questions on behalf of the wizard.
+type Prompter interface {
+	// Confirm asks a yes/no question. def is the answer assumed when the
+	// user just presses enter.
+	Confirm(question string, def bool) (bool, error)
+
+	// MultiSelect asks the user to choose any number of options. defaults
+	// are preselected.
+	MultiSelect(question string, options, defaults []string) ([]string, error)
+}
+
+// DefaultsPrompter answers every question with its default, for
+// non-interactive runs such as `dev-env init --yes`.
+type DefaultsPrompter struct{}
+
+// Confirm returns def.
+func (DefaultsPrompter) Confirm(_ string, def bool) (bool, error) {
+	return def, nil
+}
+
+// MultiSelect returns defaults.
+func (DefaultsPrompter) MultiSelect(_ string, _, defaults []string) ([]string, error) {
+	return defaults, nil
+}
+
+// LinePrompter asks questions on a line-oriented terminal.
+type LinePrompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewLinePrompter creates a prompter reading answers from in and writing
+// questions to out.
+func NewLinePrompter(in io.Reader, out io.Writer) *LinePrompter {
+	return &LinePrompter{in: bufio.NewReader(in), out: out}
+}
+
+// Confirm asks a yes/no question until it gets a valid answer.
+func (p *LinePrompter) Confirm(question string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+
+	for {
+		answer, err := p.ask(fmt.Sprintf("%s %s ", question, hint))
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(p.out, "Please answer y or n.")
+	}
+}
+
+// MultiSelect asks for a comma-separated list of options until every entry
+// is valid. An empty answer keeps the defaults and "none" selects nothing.
+func (p *LinePrompter) MultiSelect(question string, options, defaults []string) ([]string, error) {
+	valid := make(map[string]bool, len(options))
+	for _, option := range options {
+		valid[option] = true
+	}
+
+	for {
+		fmt.Fprintf(p.out, "%s\n  options: %s\n", question, strings.Join(options, ", "))
+		answer, err := p.ask(fmt.Sprintf("  [%s]: ", strings.Join(defaults, ",")))
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(answer) {
+		case "":
+			return defaults, nil
+		case "none":
+			return []string{}, nil
+		}
+
+		selected, invalid := splitList(answer), []string{}
+		for _, choice := range selected {
+			if !valid[choice] {
+				invalid = append(invalid, choice)
+			}
+		}
+		if len(invalid) == 0 {
+			return selected, nil
+		}
+		fmt.Fprintf(p.out, "Unknown option(s): %s\n", strings.Join(invalid, ", "))
+	}
+}
+
+// ask prints prompt and reads one trimmed line.
+func (p *LinePrompter) ask(prompt string) (string, error) {
+	fmt.Fprint(p.out, prompt)
+	line, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("failed to read answer: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package setup
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestLinePrompter_Confirm tests yes/no answers, defaults, and retries.
+func TestLinePrompter_Confirm(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		def   bool
+		want  bool
+	}{
+		{name: "yes", input: "y\n", want: true},
+		{name: "no", input: "No\n", def: true, want: false},
+		{name: "default", input: "\n", def: true, want: true},
+		{name: "retry after invalid", input: "maybe\nyes\n", want: true},
+		{name: "no trailing newline", input: "y", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompter := NewLinePrompter(strings.NewReader(tt.input), io.Discard)
+			got, err := prompter.Confirm("Continue?", tt.def)
+			if err != nil {
+				t.Fatalf("Confirm() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Confirm() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLinePrompter_MultiSelect tests list answers, defaults, and validation.
+func TestLinePrompter_MultiSelect(t *testing.T) {
+	options := []string{"aws", "docker", "gcp"}
+	defaults := []string{"aws"}
+
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "default", input: "\n", want: []string{"aws"}},
+		{name: "list", input: "docker, gcp\n", want: []string{"docker", "gcp"}},
+		{name: "none", input: "none\n", want: []string{}},
+		{name: "retry after unknown", input: "azure\ngcp\n", want: []string{"gcp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prompter := NewLinePrompter(strings.NewReader(tt.input), io.Discard)
+			got, err := prompter.MultiSelect("Services:", options, defaults)
+			if err != nil {
+				t.Fatalf("MultiSelect() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MultiSelect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestLinePrompter_EOF tests that running out of input is an error, not a loop.
+func TestLinePrompter_EOF(t *testing.T) {
+	prompter := NewLinePrompter(strings.NewReader("maybe\n"), io.Discard)
+	if _, err := prompter.Confirm("Continue?", false); err == nil {
+		t.Error("Confirm() should fail when input ends")
+	}
+}
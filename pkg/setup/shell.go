@@ -0,0 +1,58 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package setup
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Markers delimiting the block InstallShellHook manages in a shell rc file.
+const (
+	hookBegin = "# >>> dev-env >>>"
+	hookEnd   = "# <<< dev-env <<<"
+)
+
+// RCFile returns the rc file of shell ("bash" or "zsh") under homeDir.
+func RCFile(homeDir, shell string) (string, error) {
+	switch shell {
+	case "bash":
+		return filepath.Join(homeDir, ".bashrc"), nil
+	case "zsh":
+		return filepath.Join(homeDir, ".zshrc"), nil
+	default:
+		return "", fmt.Errorf("unsupported shell: %s (supported: bash, zsh)", shell)
+	}
+}
+
+// InstallShellHook writes snippet into rcPath between dev-env markers. An
+// existing dev-env block is replaced, so running it again is harmless; the
+// rest of the file is left as it is.
+func InstallShellHook(rcPath, snippet string) error {
+	data, err := os.ReadFile(rcPath)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", rcPath, err)
+	}
+	content := string(data)
+
+	block := hookBegin + "\n" + strings.TrimRight(snippet, "\n") + "\n" + hookEnd + "\n"
+
+	start := strings.Index(content, hookBegin)
+	end := strings.Index(content, hookEnd)
+	switch {
+	case start >= 0 && end > start:
+		content = content[:start] + block + strings.TrimPrefix(content[end+len(hookEnd):], "\n")
+	case content == "" || strings.HasSuffix(content, "\n"):
+		content += block
+	default:
+		content += "\n" + block
+	}
+
+	if err := os.WriteFile(rcPath, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", rcPath, err)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package setup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestInstallShellHook tests adding and replacing the dev-env block.
+func TestInstallShellHook(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name: "new file",
+			want: "# >>> dev-env >>>\nsource one\n# <<< dev-env <<<\n",
+		},
+		{
+			name:     "append without trailing newline",
+			existing: "export PATH=/bin",
+			want:     "export PATH=/bin\n# >>> dev-env >>>\nsource one\n# <<< dev-env <<<\n",
+		},
+		{
+			name:     "replace existing block",
+			existing: "before\n# >>> dev-env >>>\nsource old\n# <<< dev-env <<<\nafter\n",
+			want:     "before\n# >>> dev-env >>>\nsource one\n# <<< dev-env <<<\nafter\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := filepath.Join(t.TempDir(), ".bashrc")
+			if tt.existing != "" {
+				if err := os.WriteFile(rc, []byte(tt.existing), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			// Installing twice must give the same result.
+			for i := 0; i < 2; i++ {
+				if err := InstallShellHook(rc, "source one\n"); err != nil {
+					t.Fatalf("InstallShellHook() error = %v", err)
+				}
+			}
+
+			got, err := os.ReadFile(rc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("rc file = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestRCFile tests rc file selection by shell.
+func TestRCFile(t *testing.T) {
+	if got, err := RCFile("/home/u", "zsh"); err != nil || got != "/home/u/.zshrc" {
+		t.Errorf("RCFile(zsh) = %q, %v, want /home/u/.zshrc", got, err)
+	}
+	if _, err := RCFile("/home/u", "fish"); err == nil {
+		t.Error("RCFile(fish) should fail")
+	}
+}
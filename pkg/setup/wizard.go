@@ -0,0 +1,273 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package setup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/builtin"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// DefaultEnvironmentName is the name of the environment the wizard
+// snapshots the current state into.
+const DefaultEnvironmentName = "default"
+
+// ServiceBinaries maps each built-in service to the CLIs it relies on, any
+// one of which makes it usable.
+func ServiceBinaries() map[string][]string {
+	binaries := make(map[string][]string)
+	for _, service := range builtin.Services() {
+		binaries[service.Name] = service.Binaries
+	}
+	return binaries
+}
+
+// Wizard walks a new user through setting up dev-env. Every step asks
+// before changing anything, so any of them can be skipped.
+type Wizard struct {
+	// Prompter answers the wizard's questions.
+	Prompter Prompter
+	// Out receives progress messages.
+	Out io.Writer
+	// BaseDir is the dev-env directory, normally ~/.gzh/dev-env.
+	BaseDir string
+	// Services, if set, are enabled without asking, e.g. from --services.
+	Services []string
+
+	// Snapshot captures the current state of services as an environment.
+	// The snapshot step is skipped when nil.
+	Snapshot func(ctx context.Context, name string, services []string) (*environment.Environment, error)
+	// InstallShellIntegration installs shell completion, typically by adding
+	// a block to the shell rc file. The step is skipped when nil.
+	InstallShellIntegration func() error
+	// ShellIntegrationDefault is the default answer to installing shell
+	// integration. It is off by default because it edits the rc file.
+	ShellIntegrationDefault bool
+
+	// lookPath finds installed binaries; replaced in tests.
+	lookPath func(file string) (string, error)
+}
+
+// Result summarizes what the wizard did.
+type Result struct {
+	// Installed lists services whose CLI was found.
+	Installed []string
+	// Enabled lists the services chosen, or nil if that step was skipped.
+	Enabled []string
+	// SnapshotPath is the environment file written, if any.
+	SnapshotPath string
+	// ShellIntegration reports whether shell integration was installed.
+	ShellIntegration bool
+}
+
+// EnvironmentsDir returns the directory environment files are stored in.
+func (w *Wizard) EnvironmentsDir() string {
+	return filepath.Join(w.BaseDir, "environments")
+}
+
+// SettingsPath returns the settings file the enabled services are saved to.
+func (w *Wizard) SettingsPath() string {
+	return filepath.Join(w.BaseDir, "settings.yaml")
+}
+
+// Run runs the wizard's steps in order: create the directory layout, detect
+// installed CLIs, choose services, snapshot the current state, and install
+// shell integration.
+func (w *Wizard) Run(ctx context.Context) (*Result, error) {
+	result := &Result{}
+
+	if err := w.createLayout(); err != nil {
+		return result, err
+	}
+
+	result.Installed = w.detect()
+
+	enabled, err := w.chooseServices(result.Installed)
+	if err != nil {
+		return result, err
+	}
+	result.Enabled = enabled
+
+	services := enabled
+	if services == nil {
+		services = result.Installed
+	}
+	if result.SnapshotPath, err = w.snapshot(ctx, services); err != nil {
+		return result, err
+	}
+
+	if result.ShellIntegration, err = w.installShellIntegration(); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// createLayout creates the dev-env directories.
+func (w *Wizard) createLayout() error {
+	if _, err := os.Stat(w.EnvironmentsDir()); err == nil {
+		return nil
+	}
+
+	ok, err := w.Prompter.Confirm(fmt.Sprintf("Create %s?", w.EnvironmentsDir()), true)
+	if err != nil || !ok {
+		return err
+	}
+	if err := os.MkdirAll(w.EnvironmentsDir(), config.PrivateDirMode); err != nil {
+		return fmt.Errorf("failed to create %s: %w", w.EnvironmentsDir(), err)
+	}
+	fmt.Fprintf(w.Out, "Created %s\n", w.EnvironmentsDir())
+	return nil
+}
+
+// detect reports which service CLIs are installed.
+func (w *Wizard) detect() []string {
+	lookPath := w.lookPath
+	if lookPath == nil {
+		lookPath = exec.LookPath
+	}
+
+	fmt.Fprintln(w.Out, "Detected CLIs:")
+	binaries := ServiceBinaries()
+	var installed []string
+	for _, service := range allServices() {
+		var paths []string
+		for _, binary := range binaries[service] {
+			if path, err := lookPath(binary); err == nil {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) > 0 {
+			installed = append(installed, service)
+			fmt.Fprintf(w.Out, "  ✅ %-10s %s\n", service, strings.Join(paths, ", "))
+		} else {
+			fmt.Fprintf(w.Out, "  ❌ %-10s %s not found\n", service, strings.Join(binaries[service], " or "))
+		}
+	}
+	return installed
+}
+
+// chooseServices asks which services to enable, defaulting to the installed
+// ones, and records the rest as disabled. It returns nil if skipped.
+func (w *Wizard) chooseServices(installed []string) ([]string, error) {
+	enabled := w.Services
+	if enabled == nil {
+		ok, err := w.Prompter.Confirm("Choose which services dev-env manages?", true)
+		if err != nil || !ok {
+			return nil, err
+		}
+		enabled, err = w.Prompter.MultiSelect("Services to enable:", allServices(), installed)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	known := make(map[string]bool)
+	for _, service := range allServices() {
+		known[service] = true
+	}
+	chosen := make(map[string]bool)
+	for _, service := range enabled {
+		if !known[service] {
+			return nil, fmt.Errorf("unknown service: %s", service)
+		}
+		chosen[service] = true
+	}
+
+	settings, err := config.LoadSettings(w.SettingsPath())
+	if err != nil {
+		return nil, err
+	}
+	settings.DisabledServices = nil
+	for _, service := range allServices() {
+		if !chosen[service] {
+			settings.DisabledServices = append(settings.DisabledServices, service)
+		}
+	}
+	if err := settings.Save(w.SettingsPath()); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w.Out, "Saved enabled services to %s\n", w.SettingsPath())
+
+	return enabled, nil
+}
+
+// snapshot offers to save the current state as the default environment and
+// returns the file written, if any.
+func (w *Wizard) snapshot(ctx context.Context, services []string) (string, error) {
+	if w.Snapshot == nil || len(services) == 0 {
+		return "", nil
+	}
+
+	path := filepath.Join(w.EnvironmentsDir(), DefaultEnvironmentName+".yaml")
+	question := fmt.Sprintf("Save the current state as the '%s' environment?", DefaultEnvironmentName)
+	def := true
+	if _, err := os.Stat(path); err == nil {
+		question = fmt.Sprintf("Overwrite the existing '%s' environment with the current state?", DefaultEnvironmentName)
+		def = false
+	}
+
+	ok, err := w.Prompter.Confirm(question, def)
+	if err != nil || !ok {
+		return "", err
+	}
+
+	env, err := w.Snapshot(ctx, DefaultEnvironmentName, services)
+	if err != nil {
+		return "", fmt.Errorf("failed to snapshot current state: %w", err)
+	}
+	if len(env.Services) == 0 {
+		fmt.Fprintln(w.Out, "Nothing is configured yet; skipped the snapshot")
+		return "", nil
+	}
+	env.Description = "Snapshot taken by dev-env init"
+
+	data, err := env.ToYAML()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), config.PrivateDirMode); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, config.PrivateFileMode); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Fprintf(w.Out, "Saved %s\n", path)
+	return path, nil
+}
+
+// installShellIntegration offers to install shell completion.
+func (w *Wizard) installShellIntegration() (bool, error) {
+	if w.InstallShellIntegration == nil {
+		return false, nil
+	}
+
+	ok, err := w.Prompter.Confirm("Install shell completion (adds a block to your shell rc file)?", w.ShellIntegrationDefault)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := w.InstallShellIntegration(); err != nil {
+		return false, fmt.Errorf("failed to install shell integration: %w", err)
+	}
+	return true, nil
+}
+
+// allServices returns the known services in a stable order.
+func allServices() []string {
+	var services []string
+	for _, service := range builtin.Services() {
+		services = append(services, service.Name)
+	}
+	sort.Strings(services)
+	return services
+}
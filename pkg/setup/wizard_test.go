@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package setup
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/config"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// scriptedPrompter answers questions containing a key of confirms, and
+// records every question asked. Unscripted questions get their default.
+type scriptedPrompter struct {
+	confirms map[string]bool
+	selected []string
+	asked    []string
+}
+
+func (p *scriptedPrompter) Confirm(question string, def bool) (bool, error) {
+	p.asked = append(p.asked, question)
+	for key, answer := range p.confirms {
+		if strings.Contains(question, key) {
+			return answer, nil
+		}
+	}
+	return def, nil
+}
+
+func (p *scriptedPrompter) MultiSelect(question string, _, defaults []string) ([]string, error) {
+	p.asked = append(p.asked, question)
+	if p.selected != nil {
+		return p.selected, nil
+	}
+	return defaults, nil
+}
+
+// fakeLookPath finds only the given binaries.
+func fakeLookPath(binaries ...string) func(string) (string, error) {
+	return func(file string) (string, error) {
+		for _, binary := range binaries {
+			if binary == file {
+				return "/usr/bin/" + file, nil
+			}
+		}
+		return "", fmt.Errorf("%s not found", file)
+	}
+}
+
+// fakeSnapshot returns an environment with an AWS profile per service asked for.
+func fakeSnapshot(_ context.Context, name string, services []string) (*environment.Environment, error) {
+	env := &environment.Environment{Name: name, Services: map[string]environment.ServiceConfig{}}
+	for _, service := range services {
+		env.Services[service] = environment.ServiceConfig{AWS: &environment.AWSConfig{Profile: service}}
+	}
+	return env, nil
+}
+
+// TestWizard_Run tests the full wizard with default answers.
+func TestWizard_Run(t *testing.T) {
+	installed := false
+	prompter := &scriptedPrompter{}
+	wizard := &Wizard{
+		Prompter: prompter,
+		Out:      io.Discard,
+		BaseDir:  filepath.Join(t.TempDir(), "dev-env"),
+		Snapshot: fakeSnapshot,
+		InstallShellIntegration: func() error {
+			installed = true
+			return nil
+		},
+		lookPath: fakeLookPath("aws", "kubectl"),
+	}
+
+	result, err := wizard.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if want := []string{"aws", "kubernetes"}; !reflect.DeepEqual(result.Installed, want) {
+		t.Errorf("Installed = %v, want %v", result.Installed, want)
+	}
+	if want := []string{"aws", "kubernetes"}; !reflect.DeepEqual(result.Enabled, want) {
+		t.Errorf("Enabled = %v, want %v", result.Enabled, want)
+	}
+
+	settings, err := config.LoadSettings(wizard.SettingsPath())
+	if err != nil {
+		t.Fatalf("LoadSettings() error = %v", err)
+	}
+	if want := []string{"azure", "docker", "gcp", "gpg", "pkgmgr", "ssh"}; !reflect.DeepEqual(settings.DisabledServices, want) {
+		t.Errorf("DisabledServices = %v, want %v", settings.DisabledServices, want)
+	}
+
+	env, err := environment.LoadEnvironmentFromFile(result.SnapshotPath)
+	if err != nil {
+		t.Fatalf("failed to load snapshot: %v", err)
+	}
+	if env.Name != DefaultEnvironmentName || !env.HasService("aws") || !env.HasService("kubernetes") {
+		t.Errorf("snapshot = %s with %v, want default with aws and kubernetes", env.Name, env.GetServiceNames())
+	}
+
+	if installed || result.ShellIntegration {
+		t.Error("shell integration should be off by default")
+	}
+}
+
+// TestWizard_Run_SkipSteps tests that declining every step changes nothing.
+func TestWizard_Run_SkipSteps(t *testing.T) {
+	snapshotCalled := false
+	prompter := &scriptedPrompter{confirms: map[string]bool{
+		"Create":   false,
+		"Choose":   false,
+		"Save":     false,
+		"complete": false,
+	}}
+	base := filepath.Join(t.TempDir(), "dev-env")
+	wizard := &Wizard{
+		Prompter: prompter,
+		Out:      io.Discard,
+		BaseDir:  base,
+		Snapshot: func(ctx context.Context, name string, services []string) (*environment.Environment, error) {
+			snapshotCalled = true
+			return fakeSnapshot(ctx, name, services)
+		},
+		InstallShellIntegration: func() error { return nil },
+		lookPath:                fakeLookPath("aws"),
+	}
+
+	result, err := wizard.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if _, err := os.Stat(base); !os.IsNotExist(err) {
+		t.Errorf("BaseDir should not be created, stat error = %v", err)
+	}
+	if result.Enabled != nil || result.SnapshotPath != "" || result.ShellIntegration || snapshotCalled {
+		t.Errorf("Run() = %+v, snapshot called %v, want every step skipped", result, snapshotCalled)
+	}
+	if len(prompter.asked) != 4 {
+		t.Errorf("asked %d questions, want 4: %v", len(prompter.asked), prompter.asked)
+	}
+}
+
+// TestWizard_Run_NonInteractive tests preselected services with DefaultsPrompter.
+func TestWizard_Run_NonInteractive(t *testing.T) {
+	wizard := &Wizard{
+		Prompter:                DefaultsPrompter{},
+		Out:                     io.Discard,
+		BaseDir:                 filepath.Join(t.TempDir(), "dev-env"),
+		Services:                []string{"gcp"},
+		Snapshot:                fakeSnapshot,
+		InstallShellIntegration: func() error { return nil },
+		ShellIntegrationDefault: true,
+		lookPath:                fakeLookPath(),
+	}
+
+	result, err := wizard.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"gcp"}; !reflect.DeepEqual(result.Enabled, want) {
+		t.Errorf("Enabled = %v, want %v", result.Enabled, want)
+	}
+	if result.SnapshotPath == "" || !result.ShellIntegration {
+		t.Errorf("Run() = %+v, want snapshot and shell integration", result)
+	}
+
+	// A second run keeps the existing snapshot, since overwriting defaults to no.
+	if err := os.WriteFile(result.SnapshotPath, []byte("name: default\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	again, err := wizard.Run(context.Background())
+	if err != nil {
+		t.Fatalf("second Run() error = %v", err)
+	}
+	if again.SnapshotPath != "" {
+		t.Errorf("second Run() SnapshotPath = %q, want the snapshot kept", again.SnapshotPath)
+	}
+}
+
+// TestWizard_Run_UnknownService tests that a typo in --services is reported.
+func TestWizard_Run_UnknownService(t *testing.T) {
+	wizard := &Wizard{
+		Prompter: DefaultsPrompter{},
+		Out:      io.Discard,
+		BaseDir:  t.TempDir(),
+		Services: []string{"k8s"},
+		lookPath: fakeLookPath(),
+	}
+
+	if _, err := wizard.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "unknown service: k8s") {
+		t.Errorf("Run() error = %v, want unknown service", err)
+	}
+}
+
+// TestWizard_Run_AnyBinary tests that a service relying on several CLIs is
+// installed when one of them is found, and can be chosen by name.
+func TestWizard_Run_AnyBinary(t *testing.T) {
+	wizard := &Wizard{
+		Prompter: DefaultsPrompter{},
+		Out:      io.Discard,
+		BaseDir:  t.TempDir(),
+		Services: []string{"pkgmgr"},
+		lookPath: fakeLookPath("go"),
+	}
+
+	result, err := wizard.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if want := []string{"pkgmgr"}; !reflect.DeepEqual(result.Installed, want) {
+		t.Errorf("Installed = %v, want %v", result.Installed, want)
+	}
+	if want := []string{"pkgmgr"}; !reflect.DeepEqual(result.Enabled, want) {
+		t.Errorf("Enabled = %v, want %v", result.Enabled, want)
+	}
+}
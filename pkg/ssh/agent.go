@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// DefaultAgentEnvPath returns ~/.gzh/dev-env/ssh-agent.env, where the
+// switcher writes the environment of an agent it starts. A child process
+// cannot change its parent shell's environment, so shells pick the agent up
+// with `. ~/.gzh/dev-env/ssh-agent.env`.
+func DefaultAgentEnvPath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "ssh-agent.env")
+}
+
+// agentEnv names the variables ssh-agent prints for its clients.
+var agentEnv = []string{"SSH_AUTH_SOCK", "SSH_AGENT_PID"}
+
+// startedAgent records an agent the switcher started and the environment it
+// replaced, so Rollback can stop it and put the environment back.
+type startedAgent struct {
+	pid      string
+	previous map[string]*string
+}
+
+// agentRunning reports whether an agent is reachable through SSH_AUTH_SOCK.
+// ssh-add -l exits with 2 when it cannot reach one, and 1 when the agent
+// holds no keys.
+func (s *Switcher) agentRunning(ctx context.Context) bool {
+	if os.Getenv("SSH_AUTH_SOCK") == "" {
+		return false
+	}
+	_, err := s.run(ctx, "ssh-add", "-l")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode() != 2
+	}
+	return err == nil
+}
+
+// startAgent starts ssh-agent, exports its variables to this process so
+// later commands and hooks reach it, and writes them to the agent env file.
+func (s *Switcher) startAgent(ctx context.Context) error {
+	output, err := s.run(ctx, "ssh-agent", "-s")
+	if err != nil {
+		return fmt.Errorf("failed to start ssh-agent: %w", err)
+	}
+	if environment.Simulating(ctx) {
+		return nil
+	}
+	vars, err := parseAgentOutput(output)
+	if err != nil {
+		return err
+	}
+
+	started := &startedAgent{pid: vars["SSH_AGENT_PID"], previous: make(map[string]*string, len(agentEnv))}
+	for _, name := range agentEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			started.previous[name] = &value
+		} else {
+			started.previous[name] = nil
+		}
+		if err := os.Setenv(name, vars[name]); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+	s.started = started
+
+	var env strings.Builder
+	for _, name := range agentEnv {
+		fmt.Fprintf(&env, "%s=%s; export %s;\n", name, vars[name], name)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.agentEnvPath), 0o700); err != nil {
+		return fmt.Errorf("failed to create ssh-agent env directory: %w", err)
+	}
+	if err := os.WriteFile(s.agentEnvPath, []byte(env.String()), 0o600); err != nil {
+		return fmt.Errorf("failed to write ssh-agent env: %w", err)
+	}
+	return nil
+}
+
+// stopAgent stops the agent startAgent started, if any, and restores the
+// environment and env file it replaced.
+func (s *Switcher) stopAgent(ctx context.Context) error {
+	started := s.started
+	if started == nil {
+		return nil
+	}
+	s.started = nil
+
+	// ssh-agent -k stops the agent named by SSH_AGENT_PID, which still
+	// points at the started agent.
+	_, killErr := s.run(ctx, "ssh-agent", "-k")
+	for name, value := range started.previous {
+		if value == nil {
+			_ = os.Unsetenv(name)
+		} else {
+			_ = os.Setenv(name, *value)
+		}
+	}
+	if err := os.Remove(s.agentEnvPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove ssh-agent env: %w", err)
+	}
+	if killErr != nil {
+		return fmt.Errorf("failed to stop ssh-agent %s: %w", started.pid, killErr)
+	}
+	return nil
+}
+
+// addIdentities loads identities into the agent, expanding a leading ~.
+func (s *Switcher) addIdentities(ctx context.Context, identities []string) error {
+	for _, identity := range identities {
+		path := identity
+		if rest, ok := strings.CutPrefix(identity, "~/"); ok {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("failed to expand %s: %w", identity, err)
+			}
+			path = filepath.Join(homeDir, rest)
+		}
+		if _, err := s.run(ctx, "ssh-add", path); err != nil {
+			return fmt.Errorf("failed to add SSH identity %s: %w", identity, err)
+		}
+	}
+	return nil
+}
+
+// parseAgentOutput reads SSH_AUTH_SOCK and SSH_AGENT_PID from the
+// Bourne shell commands printed by `ssh-agent -s`, such as
+// "SSH_AUTH_SOCK=/tmp/ssh-XXXX/agent.123; export SSH_AUTH_SOCK;".
+func parseAgentOutput(output []byte) (map[string]string, error) {
+	vars := make(map[string]string, len(agentEnv))
+	for _, line := range strings.Split(string(output), "\n") {
+		assignment, _, _ := strings.Cut(line, ";")
+		name, value, ok := strings.Cut(strings.TrimSpace(assignment), "=")
+		if ok {
+			vars[name] = value
+		}
+	}
+	for _, name := range agentEnv {
+		if vars[name] == "" {
+			return nil, fmt.Errorf("ssh-agent output has no %s", name)
+		}
+	}
+	return vars, nil
+}
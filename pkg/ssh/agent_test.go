@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package ssh
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// sampleAgentOutput is what `ssh-agent -s` prints.
+const sampleAgentOutput = `SSH_AUTH_SOCK=/tmp/ssh-XXXXabcd/agent.4120; export SSH_AUTH_SOCK;
+SSH_AGENT_PID=4121; export SSH_AGENT_PID;
+echo Agent pid 4121;
+`
+
+// agentRunner returns a CommandRunner that answers `ssh-agent -s` with
+// sampleAgentOutput, or startErr, and records every command it receives.
+func agentRunner(startErr error, calls *[]string) environment.CommandRunner {
+	return func(_ context.Context, name string, args ...string) ([]byte, error) {
+		cmdline := name + " " + strings.Join(args, " ")
+		*calls = append(*calls, cmdline)
+		if cmdline == "ssh-agent -s" {
+			return []byte(sampleAgentOutput), startErr
+		}
+		return nil, nil
+	}
+}
+
+// TestParseAgentOutput tests reading the agent variables from ssh-agent.
+func TestParseAgentOutput(t *testing.T) {
+	got, err := parseAgentOutput([]byte(sampleAgentOutput))
+	if err != nil {
+		t.Fatalf("parseAgentOutput() error = %v", err)
+	}
+	want := map[string]string{"SSH_AUTH_SOCK": "/tmp/ssh-XXXXabcd/agent.4120", "SSH_AGENT_PID": "4121"}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("parseAgentOutput()[%s] = %q, want %q", name, got[name], value)
+		}
+	}
+
+	if _, err := parseAgentOutput([]byte("SSH_AUTH_SOCK=/tmp/agent; export SSH_AUTH_SOCK;\n")); err == nil {
+		t.Error("parseAgentOutput() should fail without SSH_AGENT_PID")
+	}
+}
+
+// TestSwitcher_Switch_StartAgent tests starting an agent, loading
+// identities into it, and stopping it on rollback.
+func TestSwitcher_Switch_StartAgent(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("SSH_AGENT_PID", "")
+
+	var calls []string
+	envPath := filepath.Join(home, "dev-env", "ssh-agent.env")
+	switcher := &Switcher{run: agentRunner(nil, &calls), agentEnvPath: envPath}
+
+	config := &environment.SSHConfig{StartAgent: true, Identities: []string{"~/.ssh/id_ed25519", "/keys/deploy"}}
+	if err := switcher.Switch(context.Background(), config); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+
+	want := []string{"ssh-agent -s", "ssh-add " + filepath.Join(home, ".ssh", "id_ed25519"), "ssh-add /keys/deploy"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("commands = %v, want %v", calls, want)
+	}
+	if got := os.Getenv("SSH_AUTH_SOCK"); got != "/tmp/ssh-XXXXabcd/agent.4120" {
+		t.Errorf("SSH_AUTH_SOCK = %q, want the started agent's socket", got)
+	}
+	data, err := os.ReadFile(envPath)
+	if err != nil {
+		t.Fatalf("agent env file: %v", err)
+	}
+	if wantEnv := "SSH_AUTH_SOCK=/tmp/ssh-XXXXabcd/agent.4120; export SSH_AUTH_SOCK;\nSSH_AGENT_PID=4121; export SSH_AGENT_PID;\n"; string(data) != wantEnv {
+		t.Errorf("agent env file = %q, want %q", data, wantEnv)
+	}
+
+	// Switching again reuses the agent.
+	calls = nil
+	if err := switcher.Switch(context.Background(), &environment.SSHConfig{StartAgent: true}); err != nil {
+		t.Fatalf("second Switch() error = %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("second Switch() commands = %v, want none", calls)
+	}
+
+	calls = nil
+	if err := switcher.Rollback(context.Background(), &environment.SSHConfig{Config: "default"}); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if want := []string{"ssh-agent -k"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("Rollback() commands = %v, want %v", calls, want)
+	}
+	if got := os.Getenv("SSH_AUTH_SOCK"); got != "" {
+		t.Errorf("SSH_AUTH_SOCK = %q after Rollback(), want it restored", got)
+	}
+	if _, err := os.Stat(envPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("agent env file should be removed on rollback, stat error = %v", err)
+	}
+}
+
+// TestSwitcher_Switch_AgentRunning tests that a reachable agent is used
+// as is and left running on rollback.
+func TestSwitcher_Switch_AgentRunning(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "/tmp/existing/agent.1")
+
+	var calls []string
+	switcher := &Switcher{run: agentRunner(nil, &calls), agentEnvPath: filepath.Join(t.TempDir(), "ssh-agent.env")}
+
+	config := &environment.SSHConfig{StartAgent: true, Identities: []string{"/keys/deploy"}}
+	if err := switcher.Switch(context.Background(), config); err != nil {
+		t.Fatalf("Switch() error = %v", err)
+	}
+	if want := []string{"ssh-add -l", "ssh-add /keys/deploy"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("commands = %v, want %v", calls, want)
+	}
+
+	calls = nil
+	if err := switcher.Rollback(context.Background(), &environment.SSHConfig{}); err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if len(calls) != 0 {
+		t.Errorf("Rollback() commands = %v, want none for an agent it did not start", calls)
+	}
+	if got := os.Getenv("SSH_AUTH_SOCK"); got != "/tmp/existing/agent.1" {
+		t.Errorf("SSH_AUTH_SOCK = %q, want it untouched", got)
+	}
+}
+
+// TestSwitcher_Switch_StartAgentError tests that a failed start leaves the
+// environment alone and loads no identities.
+func TestSwitcher_Switch_StartAgentError(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+
+	var calls []string
+	switcher := &Switcher{run: agentRunner(errors.New("exit status 1"), &calls), agentEnvPath: filepath.Join(t.TempDir(), "ssh-agent.env")}
+
+	config := &environment.SSHConfig{StartAgent: true, Identities: []string{"/keys/deploy"}}
+	if err := switcher.Switch(context.Background(), config); err == nil {
+		t.Fatal("Switch() should fail when ssh-agent does not start")
+	}
+	if want := []string{"ssh-agent -s"}; !reflect.DeepEqual(calls, want) {
+		t.Errorf("commands = %v, want %v", calls, want)
+	}
+	if got := os.Getenv("SSH_AUTH_SOCK"); got != "" {
+		t.Errorf("SSH_AUTH_SOCK = %q, want it unchanged", got)
+	}
+}
+
+// TestSwitcher_Switch_StartAgentSimulated tests that a simulated switch
+// records the agent start without exporting or writing its environment.
+func TestSwitcher_Switch_StartAgentSimulated(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	envPath := filepath.Join(t.TempDir(), "ssh-agent.env")
+
+	es := environment.NewEnvironmentSwitcher()
+	es.Register(&Switcher{run: environment.RunCommand, agentEnvPath: envPath})
+	env := &environment.Environment{
+		Name:     "dev",
+		Services: map[string]environment.ServiceConfig{"ssh": {SSH: &environment.SSHConfig{StartAgent: true}}},
+	}
+
+	report, err := es.Simulate(context.Background(), env, environment.SimulateOptions{})
+	if err != nil {
+		t.Fatalf("Simulate() error = %v", err)
+	}
+	if report.Error != "" || len(report.Commands) != 1 || report.Commands[0].Command != "ssh-agent -s" {
+		t.Errorf("Simulate() commands = %+v, error %q, want only ssh-agent -s", report.Commands, report.Error)
+	}
+	if got := os.Getenv("SSH_AUTH_SOCK"); got != "" {
+		t.Errorf("SSH_AUTH_SOCK = %q, want it unchanged by a simulation", got)
+	}
+	if _, err := os.Stat(envPath); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Simulate() wrote the agent env file: stat error = %v", err)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -29,6 +30,11 @@ func (s *Checker) Name() string {
 	return "ssh"
 }
 
+// Category returns the service's category for grouping and selection.
+func (s *Checker) Category() status.Category {
+	return status.CategoryAccess
+}
+
 // CheckStatus checks SSH current status.
 func (s *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
 	st := &status.ServiceStatus{
@@ -43,14 +49,16 @@ func (s *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 	// Check if SSH is available
 	if !s.isCLIAvailable() {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotInstalled
 		st.Details["error"] = "SSH not found"
 		return st, nil
 	}
 
 	// Check SSH agent status
-	agentStatus := s.checkSSHAgent()
+	agentStatus := s.checkSSHAgent(ctx)
 	if !agentStatus {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotRunning
 		st.Details["error"] = "SSH agent not running"
 		return st, nil
 	}
@@ -65,6 +73,7 @@ func (s *Checker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error
 
 	if len(keys) == 0 {
 		st.Status = status.StatusInactive
+		st.Reason = status.ReasonNotConfigured
 		st.Details["error"] = "No SSH keys loaded"
 		return st, nil
 	}
@@ -89,16 +98,14 @@ func (s *Checker) CheckHealth(ctx context.Context) (*status.HealthStatus, error)
 	}
 
 	// Check SSH agent connectivity
-	cmd := exec.CommandContext(ctx, "ssh-add", "-l")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "ssh-add", "-l")
 	health.Duration = time.Since(start)
 
 	if err != nil {
 		health.Status = status.StatusError
 		health.Message = fmt.Sprintf("Failed to connect to SSH agent: %v", err)
-		var exitErr *exec.ExitError
-		if errors.As(err, &exitErr) {
-			health.Details["stderr"] = string(exitErr.Stderr)
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
 		}
 		return health, nil
 	}
@@ -123,15 +130,14 @@ func (s *Checker) isCLIAvailable() bool {
 }
 
 // checkSSHAgent checks if SSH agent is running.
-func (s *Checker) checkSSHAgent() bool {
+func (s *Checker) checkSSHAgent(ctx context.Context) bool {
 	// Check SSH_AUTH_SOCK environment variable
 	if os.Getenv("SSH_AUTH_SOCK") == "" {
 		return false
 	}
 
 	// Try to connect to SSH agent
-	cmd := exec.Command("ssh-add", "-l")
-	err := cmd.Run()
+	_, err := environment.RunCommand(ctx, "ssh-add", "-l")
 	// ssh-add -l returns 0 if keys are loaded, 1 if no keys, 2 if agent not running
 	var exitErr *exec.ExitError
 	if errors.As(err, &exitErr) {
@@ -142,8 +148,7 @@ func (s *Checker) checkSSHAgent() bool {
 
 // getLoadedKeys gets the list of loaded SSH keys.
 func (s *Checker) getLoadedKeys(ctx context.Context) ([]string, error) {
-	cmd := exec.CommandContext(ctx, "ssh-add", "-l")
-	output, err := cmd.Output()
+	output, err := environment.RunCommand(ctx, "ssh-add", "-l")
 	if err != nil {
 		// Check if it's "no keys loaded" vs actual error
 		var exitErr *exec.ExitError
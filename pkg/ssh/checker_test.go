@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -87,3 +88,9 @@ func TestChecker_CheckHealth_ReturnsValidHealth(t *testing.T) {
 		t.Errorf("health.Status = %v, not a valid status type", health.Status)
 	}
 }
+
+// TestChecker_CheckStatus_NotInstalled tests that a missing CLI is
+// inactive with the not-installed reason.
+func TestChecker_CheckStatus_NotInstalled(t *testing.T) {
+	testutil.AssertNotInstalled(t, NewChecker)
+}
@@ -2,7 +2,9 @@
 // status checking, and configuration parsing.
 //
 // This package implements:
-//   - SSHSwitcher: Switches SSH configurations
+//   - SSHSwitcher: Switches SSH configurations, starting ssh-agent and loading
+//     identities if configured
 //   - SSHChecker: Checks SSH key and connection status
+//   - JumpHostChecker: Checks connectivity through SSH jump hosts
 //   - Parser: Parses SSH config files
 package ssh
@@ -0,0 +1,184 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// jumpConnectTimeout is the ssh ConnectTimeout, in seconds, for jump checks.
+const jumpConnectTimeout = "5"
+
+// JumpHostChecker implements status.ServiceChecker for connectivity to a
+// target host through an SSH jump host (bastion), as configured in ~/.ssh/config.
+type JumpHostChecker struct {
+	JumpHost string
+	Target   string
+	run      environment.CommandRunner
+}
+
+// NewJumpHostChecker creates a checker for reaching target through jumpHost.
+func NewJumpHostChecker(jumpHost, target string) *JumpHostChecker {
+	return &JumpHostChecker{
+		JumpHost: jumpHost,
+		Target:   target,
+		run:      environment.RunCommand,
+	}
+}
+
+// ParseJumpSpec parses a HOST[:PORT]:TARGET[:PORT] specification into its
+// jump host and target, each keeping its port as host:port. Hosts may be
+// ssh config aliases or user@host.
+func ParseJumpSpec(spec string) (jumpHost, target string, err error) {
+	parts := strings.Split(spec, ":")
+	switch {
+	case len(parts) == 2:
+		jumpHost, target = parts[0], parts[1]
+	case len(parts) == 3 && isPort(parts[1]):
+		jumpHost, target = parts[0]+":"+parts[1], parts[2]
+	case len(parts) == 3 && isPort(parts[2]):
+		jumpHost, target = parts[0], parts[1]+":"+parts[2]
+	case len(parts) == 4 && isPort(parts[1]) && isPort(parts[3]):
+		jumpHost, target = parts[0]+":"+parts[1], parts[2]+":"+parts[3]
+	}
+	if jumpHost == "" || target == "" || strings.HasPrefix(target, ":") {
+		return "", "", fmt.Errorf("invalid jump specification %q (expected HOST[:PORT]:TARGET[:PORT])", spec)
+	}
+	if err := validateHost(jumpHost); err != nil {
+		return "", "", err
+	}
+	if err := validateHost(target); err != nil {
+		return "", "", err
+	}
+	return jumpHost, target, nil
+}
+
+// isPort reports whether s is a TCP port number.
+func isPort(s string) bool {
+	port, err := strconv.Atoi(s)
+	return err == nil && port > 0 && port <= 65535 && s[0] != '+'
+}
+
+// splitPort splits a host:port target into its host and port, which is
+// empty if the target has none.
+func splitPort(target string) (host, port string) {
+	if host, port, ok := strings.Cut(target, ":"); ok && isPort(port) {
+		return host, port
+	}
+	return target, ""
+}
+
+// validateHost rejects host names that ssh would parse as options.
+func validateHost(host string) error {
+	if strings.HasPrefix(host, "-") || strings.ContainsAny(host, " \t\n") {
+		return fmt.Errorf("invalid SSH host %q", host)
+	}
+	return nil
+}
+
+// Name returns the service name.
+func (j *JumpHostChecker) Name() string {
+	return "ssh-jump-" + j.Target
+}
+
+// Category returns the service's category for grouping and selection.
+func (j *JumpHostChecker) Category() status.Category {
+	return status.CategoryAccess
+}
+
+// CheckStatus checks that the target answers through the jump host.
+func (j *JumpHostChecker) CheckStatus(ctx context.Context) (*status.ServiceStatus, error) {
+	st := &status.ServiceStatus{
+		Name:   j.Name(),
+		Status: status.StatusUnknown,
+		Current: status.CurrentConfig{
+			Context: fmt.Sprintf("%s via %s", j.Target, j.JumpHost),
+		},
+		Credentials: status.CredentialStatus{Type: "ssh-jump"},
+		LastUsed:    time.Now(),
+		Details: map[string]string{
+			"jump_host": j.JumpHost,
+			"target":    j.Target,
+		},
+	}
+
+	if err := j.probe(ctx); err != nil {
+		st.Status = status.StatusError
+		st.Details["error"] = err.Error()
+		return st, nil
+	}
+
+	st.Status = status.StatusActive
+	st.Credentials.Valid = true
+	return st, nil
+}
+
+// CheckHealth measures the round-trip latency to the target through the jump host.
+func (j *JumpHostChecker) CheckHealth(ctx context.Context) (*status.HealthStatus, error) {
+	start := time.Now()
+	health := &status.HealthStatus{
+		Status:    status.StatusUnknown,
+		CheckedAt: start,
+		Details: map[string]interface{}{
+			"jump_host": j.JumpHost,
+			"target":    j.Target,
+		},
+	}
+
+	err := j.probe(ctx)
+	health.Duration = time.Since(start)
+	health.Details["latency_ms"] = health.Duration.Milliseconds()
+
+	if err != nil {
+		health.Status = status.StatusError
+		health.Message = fmt.Sprintf("Failed to reach %s via %s: %v", j.Target, j.JumpHost, err)
+		return health, nil
+	}
+
+	health.Status = status.StatusActive
+	health.Message = fmt.Sprintf("%s reachable via %s in %v", j.Target, j.JumpHost, health.Duration.Round(time.Millisecond))
+	return health, nil
+}
+
+// probe runs `echo ok` on the target through the jump host. ssh takes the
+// jump host's port in -J, and the target's with -p.
+func (j *JumpHostChecker) probe(ctx context.Context) error {
+	if err := validateHost(j.JumpHost); err != nil {
+		return err
+	}
+	if err := validateHost(j.Target); err != nil {
+		return err
+	}
+
+	// BatchMode fails instead of waiting at a password or passphrase prompt
+	args := []string{
+		"-J", j.JumpHost,
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "ConnectTimeout=" + jumpConnectTimeout,
+	}
+	host, port := splitPort(j.Target)
+	if port != "" {
+		args = append(args, "-p", port)
+	}
+	output, err := j.run(ctx, "ssh", append(args, host, "echo", "ok")...)
+	if err != nil {
+		if stderr := environment.CommandStderr(err); stderr != "" {
+			return fmt.Errorf("%w: %s", err, stderr)
+		}
+		return err
+	}
+
+	if strings.TrimSpace(string(output)) != "ok" {
+		return fmt.Errorf("unexpected response from %s: %q", j.Target, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
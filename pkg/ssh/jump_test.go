@@ -0,0 +1,140 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package ssh
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// newFakeJumpChecker returns a checker whose ssh invocations return output and err.
+func newFakeJumpChecker(output string, err error, calls *[]string) *JumpHostChecker {
+	checker := NewJumpHostChecker("bastion", "db1")
+	checker.run = func(_ context.Context, name string, args ...string) ([]byte, error) {
+		*calls = append(*calls, name+" "+strings.Join(args, " "))
+		return []byte(output), err
+	}
+	return checker
+}
+
+// TestJumpHostChecker_ImplementsInterface verifies JumpHostChecker implements ServiceChecker.
+func TestJumpHostChecker_ImplementsInterface(t *testing.T) {
+	var _ status.ServiceChecker = (*JumpHostChecker)(nil)
+}
+
+// TestJumpHostChecker_Name verifies the service name includes the target.
+func TestJumpHostChecker_Name(t *testing.T) {
+	if got := NewJumpHostChecker("bastion", "db1").Name(); got != "ssh-jump-db1" {
+		t.Errorf("Name() = %q, want %q", got, "ssh-jump-db1")
+	}
+}
+
+// TestJumpHostChecker_CheckStatus tests status for reachable and unreachable targets.
+func TestJumpHostChecker_CheckStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   status.StatusType
+	}{
+		{name: "reachable", output: "ok\n", want: status.StatusActive},
+		{name: "connection failed", err: errors.New("exit status 255"), want: status.StatusError},
+		{name: "unexpected output", output: "banner\n", want: status.StatusError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			checker := newFakeJumpChecker(tt.output, tt.err, &calls)
+
+			st, err := checker.CheckStatus(context.Background())
+			if err != nil {
+				t.Fatalf("CheckStatus() error = %v", err)
+			}
+			if st.Status != tt.want {
+				t.Errorf("Status = %s, want %s", st.Status, tt.want)
+			}
+			if st.Details["jump_host"] != "bastion" || st.Details["target"] != "db1" {
+				t.Errorf("Details = %v, want jump_host and target", st.Details)
+			}
+
+			wantCall := "ssh -J bastion -o BatchMode=yes -o StrictHostKeyChecking=no -o ConnectTimeout=5 db1 echo ok"
+			if len(calls) != 1 || calls[0] != wantCall {
+				t.Errorf("calls = %v, want [%s]", calls, wantCall)
+			}
+		})
+	}
+}
+
+// TestJumpHostChecker_Ports tests passing the jump host's port in -J and the
+// target's with -p.
+func TestJumpHostChecker_Ports(t *testing.T) {
+	var calls []string
+	checker := newFakeJumpChecker("ok\n", nil, &calls)
+	checker.JumpHost, checker.Target = "bastion:2222", "db1:2200"
+
+	if _, err := checker.CheckStatus(context.Background()); err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	wantCall := "ssh -J bastion:2222 -o BatchMode=yes -o StrictHostKeyChecking=no -o ConnectTimeout=5 -p 2200 db1 echo ok"
+	if len(calls) != 1 || calls[0] != wantCall {
+		t.Errorf("calls = %v, want [%s]", calls, wantCall)
+	}
+}
+
+// TestJumpHostChecker_CheckHealth tests latency reporting.
+func TestJumpHostChecker_CheckHealth(t *testing.T) {
+	var calls []string
+	checker := newFakeJumpChecker("ok\n", nil, &calls)
+
+	health, err := checker.CheckHealth(context.Background())
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if health.Status != status.StatusActive {
+		t.Errorf("Status = %s, want %s", health.Status, status.StatusActive)
+	}
+	if _, ok := health.Details["latency_ms"]; !ok {
+		t.Error("Details should include latency_ms")
+	}
+}
+
+// TestParseJumpSpec tests HOST[:PORT]:TARGET[:PORT] parsing.
+func TestParseJumpSpec(t *testing.T) {
+	tests := []struct {
+		spec       string
+		wantJump   string
+		wantTarget string
+		wantErr    bool
+	}{
+		{spec: "bastion:db1", wantJump: "bastion", wantTarget: "db1"},
+		{spec: "ops@bastion.example.com:10.0.0.5", wantJump: "ops@bastion.example.com", wantTarget: "10.0.0.5"},
+		{spec: "bastion:2222:db1", wantJump: "bastion:2222", wantTarget: "db1"},
+		{spec: "bastion:db1:2200", wantJump: "bastion", wantTarget: "db1:2200"},
+		{spec: "ops@bastion:2222:db1:2200", wantJump: "ops@bastion:2222", wantTarget: "db1:2200"},
+		{spec: "bastion", wantErr: true},
+		{spec: "bastion:db1:ssh", wantErr: true},
+		{spec: "bastion:70000:db1", wantErr: true},
+		{spec: "bastion:2222:", wantErr: true},
+		{spec: ":db1", wantErr: true},
+		{spec: "bastion:", wantErr: true},
+		{spec: "-oProxyCommand=x:db1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			jump, target, err := ParseJumpSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJumpSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if jump != tt.wantJump || target != tt.wantTarget {
+				t.Errorf("ParseJumpSpec() = %q, %q, want %q, %q", jump, target, tt.wantJump, tt.wantTarget)
+			}
+		})
+	}
+}
@@ -6,16 +6,24 @@ package ssh
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 )
 
 // Switcher implements environment.ServiceSwitcher for SSH.
-type Switcher struct{}
+type Switcher struct {
+	run          environment.CommandRunner
+	agentEnvPath string
+
+	// started is the agent Switch started, which Rollback stops.
+	started *startedAgent
+	mu      sync.Mutex
+}
 
 // NewSwitcher creates a new SSH switcher.
 func NewSwitcher() *Switcher {
-	return &Switcher{}
+	return &Switcher{run: environment.RunCommand, agentEnvPath: DefaultAgentEnvPath()}
 }
 
 // Name returns the service name.
@@ -23,6 +31,39 @@ func (s *Switcher) Name() string {
 	return "ssh"
 }
 
+// Claims returns the shared resources the switcher modifies: the SSH client configuration.
+func (s *Switcher) Claims() []string {
+	return []string{environment.ClaimSSHConfig}
+}
+
+// Validate checks that config is an SSH configuration.
+func (s *Switcher) Validate(config interface{}) error {
+	c, ok := config.(*environment.SSHConfig)
+	if !ok {
+		return fmt.Errorf("invalid SSH configuration type")
+	}
+	if c == nil {
+		return fmt.Errorf("no SSH configuration provided")
+	}
+	return nil
+}
+
+// RequiredCLIs returns the OpenSSH agent tools config needs: ssh-agent to
+// start an agent and ssh-add to check it and load identities.
+func (s *Switcher) RequiredCLIs(config interface{}) []string {
+	sshConfig, ok := config.(*environment.SSHConfig)
+	switch {
+	case !ok || sshConfig == nil:
+		return nil
+	case sshConfig.StartAgent:
+		return []string{"ssh-agent", "ssh-add"}
+	case len(sshConfig.Identities) > 0:
+		return []string{"ssh-add"}
+	default:
+		return nil
+	}
+}
+
 // Switch switches to the specified SSH configuration.
 func (s *Switcher) Switch(ctx context.Context, config interface{}) error {
 	sshConfig, ok := config.(*environment.SSHConfig)
@@ -38,7 +79,15 @@ func (s *Switcher) Switch(ctx context.Context, config interface{}) error {
 		_ = sshConfig.Config // Use the config silently
 	}
 
-	return nil
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if sshConfig.StartAgent && s.started == nil && !s.agentRunning(ctx) {
+		if err := s.startAgent(ctx); err != nil {
+			return err
+		}
+	}
+	return s.addIdentities(ctx, sshConfig.Identities)
 }
 
 // GetCurrentState retrieves the current SSH configuration state.
@@ -49,7 +98,15 @@ func (s *Switcher) GetCurrentState(ctx context.Context) (interface{}, error) {
 	}, nil
 }
 
-// Rollback rolls back to the previous SSH configuration.
+// Rollback rolls back to the previous SSH configuration, stopping the
+// agent Switch started, if any. Identities added to an agent that was
+// already running stay loaded.
 func (s *Switcher) Rollback(ctx context.Context, previousState interface{}) error {
+	s.mu.Lock()
+	err := s.stopAgent(ctx)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
 	return s.Switch(ctx, previousState)
 }
@@ -30,6 +30,8 @@ func TestSwitcher_Name(t *testing.T) {
 // TestSwitcher_ImplementsInterface verifies Switcher implements ServiceSwitcher.
 func TestSwitcher_ImplementsInterface(t *testing.T) {
 	var _ environment.ServiceSwitcher = (*Switcher)(nil)
+	var _ environment.ConfigValidator = (*Switcher)(nil)
+	var _ environment.CLIRequirer = (*Switcher)(nil)
 }
 
 // TestSwitcher_Switch_InvalidConfigType tests error handling for invalid config type.
@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// DefaultStatusCacheTTL is how long cached statuses stay valid.
+const DefaultStatusCacheTTL = 5 * time.Minute
+
+// statusBucket is the bbolt bucket holding cached statuses.
+var statusBucket = []byte("statuses")
+
+// StatusCache is a persistent on-disk cache of service statuses backed by bbolt.
+// It survives restarts, so the first status check of a new process can be
+// served from the previous run's results.
+type StatusCache struct {
+	db  *bbolt.DB
+	now func() time.Time
+}
+
+// cacheEntry is the stored representation of a cached status.
+type cacheEntry struct {
+	Status    ServiceStatus `json:"status"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// DefaultStatusCachePath returns the default cache location, ~/.gzh/dev-env/status.db.
+func DefaultStatusCachePath() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".gzh", "dev-env", "status.db")
+}
+
+// OpenStatusCache opens (creating if necessary) the cache database at path.
+func OpenStatusCache(path string) (*StatusCache, error) {
+	if path == "" {
+		path = DefaultStatusCachePath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open status cache %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(statusBucket)
+		return err
+	})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to initialize status cache: %w", err)
+	}
+
+	return &StatusCache{db: db, now: time.Now}, nil
+}
+
+// OpenStatusCacheReadOnly opens the existing cache database at path for
+// reading only, waiting at most timeout while another process has it open
+// for writing. Unlike OpenStatusCache it never creates the file.
+func OpenStatusCacheReadOnly(path string, timeout time.Duration) (*StatusCache, error) {
+	if path == "" {
+		path = DefaultStatusCachePath()
+	}
+
+	// bbolt waits forever for the lock when the timeout is zero
+	if timeout <= 0 {
+		timeout = time.Second
+	}
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: timeout, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open status cache %s: %w", path, err)
+	}
+	return &StatusCache{db: db, now: time.Now}, nil
+}
+
+// Close closes the underlying database.
+func (c *StatusCache) Close() error {
+	return c.db.Close()
+}
+
+// Get returns the cached status for a service if present and not expired.
+func (c *StatusCache) Get(service string) (*ServiceStatus, bool) {
+	entry, found := c.load(service)
+	if !found || c.now().After(entry.ExpiresAt) {
+		return nil, false
+	}
+	return &entry.Status, true
+}
+
+// Latest returns the cached status for a service even if it has expired,
+// for callers that judge its age themselves from CheckedAt.
+func (c *StatusCache) Latest(service string) (*ServiceStatus, bool) {
+	entry, found := c.load(service)
+	if !found {
+		return nil, false
+	}
+	return &entry.Status, true
+}
+
+// load reads the stored entry for a service.
+func (c *StatusCache) load(service string) (cacheEntry, bool) {
+	var entry cacheEntry
+	found := false
+
+	_ = c.db.View(func(tx *bbolt.Tx) error {
+		// A read-only cache may predate the bucket
+		bucket := tx.Bucket(statusBucket)
+		if bucket == nil {
+			return nil
+		}
+		data := bucket.Get([]byte(service))
+		if data == nil {
+			return nil
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		found = true
+		return nil
+	})
+	return entry, found
+}
+
+// Set stores a status for a service, valid for ttl.
+func (c *StatusCache) Set(service string, status *ServiceStatus, ttl time.Duration) error {
+	if status == nil {
+		return fmt.Errorf("cannot cache nil status for %s", service)
+	}
+	if ttl <= 0 {
+		ttl = DefaultStatusCacheTTL
+	}
+
+	data, err := json.Marshal(cacheEntry{
+		Status:    *status,
+		ExpiresAt: c.now().Add(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode status for %s: %w", service, err)
+	}
+
+	return c.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(statusBucket).Put([]byte(service), data)
+	})
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// openTestCache opens a StatusCache in a temporary directory.
+func openTestCache(t *testing.T) (*StatusCache, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "status.db")
+	cache, err := OpenStatusCache(path)
+	if err != nil {
+		t.Fatalf("OpenStatusCache() error = %v", err)
+	}
+	t.Cleanup(func() { _ = cache.Close() })
+	return cache, path
+}
+
+// TestStatusCache_SetGet tests storing and retrieving a status.
+func TestStatusCache_SetGet(t *testing.T) {
+	cache, _ := openTestCache(t)
+
+	st := &ServiceStatus{
+		Name:    "aws",
+		Status:  StatusActive,
+		Details: map[string]string{"profile": "prod"},
+	}
+	if err := cache.Set("aws", st, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	got, ok := cache.Get("aws")
+	if !ok {
+		t.Fatal("Get() ok = false, want true")
+	}
+	if got.Status != StatusActive || got.Details["profile"] != "prod" {
+		t.Errorf("Get() = %+v, want active status with profile prod", got)
+	}
+
+	if _, ok := cache.Get("gcp"); ok {
+		t.Error("Get() for missing service ok = true, want false")
+	}
+}
+
+// TestStatusCache_Expiry tests that expired entries are not returned.
+func TestStatusCache_Expiry(t *testing.T) {
+	cache, _ := openTestCache(t)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Set("aws", &ServiceStatus{Name: "aws", Status: StatusActive}, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.Get("aws"); ok {
+		t.Error("Get() after expiry ok = true, want false")
+	}
+}
+
+// TestStatusCache_SetNil tests that nil statuses are rejected.
+func TestStatusCache_SetNil(t *testing.T) {
+	cache, _ := openTestCache(t)
+
+	if err := cache.Set("aws", nil, time.Minute); err == nil {
+		t.Error("Set(nil) should return an error")
+	}
+}
+
+// TestStatusCache_Persistence tests that entries survive reopening the database.
+func TestStatusCache_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "status.db")
+
+	cache, err := OpenStatusCache(path)
+	if err != nil {
+		t.Fatalf("OpenStatusCache() error = %v", err)
+	}
+	if err := cache.Set("gcp", &ServiceStatus{Name: "gcp", Status: StatusActive}, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	reopened, err := OpenStatusCache(path)
+	if err != nil {
+		t.Fatalf("OpenStatusCache() reopen error = %v", err)
+	}
+	defer reopened.Close()
+
+	got, ok := reopened.Get("gcp")
+	if !ok || got.Status != StatusActive {
+		t.Errorf("Get() after reopen = %v, %v, want active status", got, ok)
+	}
+}
+
+// TestStatusCache_ReadOnlyLatest tests reading expired entries through a
+// read-only cache, and that a missing database is not created.
+func TestStatusCache_ReadOnlyLatest(t *testing.T) {
+	cache, path := openTestCache(t)
+	now := time.Now()
+	cache.now = func() time.Time { return now.Add(-time.Hour) }
+	if err := cache.Set("aws", &ServiceStatus{Name: "aws", Status: StatusError}, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := cache.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	readOnly, err := OpenStatusCacheReadOnly(path, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("OpenStatusCacheReadOnly() error = %v", err)
+	}
+	defer readOnly.Close()
+
+	if _, ok := readOnly.Get("aws"); ok {
+		t.Error("Get() of an expired entry ok = true, want false")
+	}
+	if got, ok := readOnly.Latest("aws"); !ok || got.Status != StatusError {
+		t.Errorf("Latest() = %v, %v, want the expired error status", got, ok)
+	}
+	if _, ok := readOnly.Latest("gcp"); ok {
+		t.Error("Latest() for missing service ok = true, want false")
+	}
+
+	missing := filepath.Join(t.TempDir(), "status.db")
+	if _, err := OpenStatusCacheReadOnly(missing, 10*time.Millisecond); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenStatusCacheReadOnly() of a missing file error = %v, want %v", err, os.ErrNotExist)
+	}
+}
+
+// TestStatusCollector_WithPersistentCache tests serving cached statuses with background refresh.
+func TestStatusCollector_WithPersistentCache(t *testing.T) {
+	cache, _ := openTestCache(t)
+
+	checker := newMockChecker("aws")
+	collector := NewStatusCollector([]ServiceChecker{checker}, 5*time.Second, WithPersistentCache(cache))
+	collector.geteuid = func() int { return 1000 }
+
+	ctx := context.Background()
+	if _, err := collector.CollectAll(ctx, StatusOptions{IncludeCache: true}); err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if _, ok := cache.Get("aws"); !ok {
+		t.Fatal("first CollectAll() should write through to the cache")
+	}
+
+	checker.status = &ServiceStatus{Name: "aws", Status: StatusInactive, Details: make(map[string]string)}
+
+	results, err := collector.CollectAll(ctx, StatusOptions{IncludeCache: true})
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if results[0].Status != StatusActive {
+		t.Errorf("cached Status = %s, want %s", results[0].Status, StatusActive)
+	}
+	if results[0].Details["cache"] != "hit" {
+		t.Errorf("Details[cache] = %q, want %q", results[0].Details["cache"], "hit")
+	}
+
+	collector.Wait()
+
+	refreshed, ok := cache.Get("aws")
+	if !ok || refreshed.Status != StatusInactive {
+		t.Errorf("refreshed cache = %v, %v, want inactive status", refreshed, ok)
+	}
+	if got := checker.checkCount.Load(); got != 2 {
+		t.Errorf("checkCount = %d, want 2", got)
+	}
+}
+
+// TestStatusCollector_WithPersistentCache_Bypass tests that IncludeCache=false always checks live.
+func TestStatusCollector_WithPersistentCache_Bypass(t *testing.T) {
+	cache, _ := openTestCache(t)
+	if err := cache.Set("aws", &ServiceStatus{Name: "aws", Status: StatusError}, time.Minute); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	checker := newMockChecker("aws")
+	collector := NewStatusCollector([]ServiceChecker{checker}, 5*time.Second, WithPersistentCache(cache))
+
+	results, err := collector.CollectAll(context.Background(), StatusOptions{})
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if results[0].Status != StatusActive {
+		t.Errorf("Status = %s, want %s", results[0].Status, StatusActive)
+	}
+	if got, _ := cache.Get("aws"); got == nil || got.Status != StatusActive {
+		t.Errorf("cache after live check = %v, want active status", got)
+	}
+}
+
+// TestStatusCollector_MaxAge tests that statuses served from a stale cache
+// fail the MaxAge guard, while live checks pass it.
+func TestStatusCollector_MaxAge(t *testing.T) {
+	cache, _ := openTestCache(t)
+	checkedAt := time.Now().Add(-time.Hour)
+	if err := cache.Set("aws", &ServiceStatus{Name: "aws", Status: StatusActive, CheckedAt: checkedAt}, 2*time.Hour); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	collector := NewStatusCollector([]ServiceChecker{newMockChecker("aws")}, 5*time.Second, WithPersistentCache(cache))
+	collector.geteuid = func() int { return 1000 }
+	ctx := context.Background()
+
+	_, err := collector.CollectAll(ctx, StatusOptions{IncludeCache: true, MaxAge: 10 * time.Minute})
+	collector.Wait()
+	var staleErr *StaleError
+	if !errors.As(err, &staleErr) || len(staleErr.Services) != 1 || staleErr.Services[0] != "aws" {
+		t.Fatalf("CollectAll() error = %v, want a StaleError for aws", err)
+	}
+	if !staleErr.AsOf.Equal(checkedAt) {
+		t.Errorf("AsOf = %v, want %v", staleErr.AsOf, checkedAt)
+	}
+
+	if _, err := collector.CollectAll(ctx, StatusOptions{MaxAge: 10 * time.Minute}); err != nil {
+		t.Errorf("CollectAll() without the cache error = %v, want live checks to pass", err)
+	}
+}
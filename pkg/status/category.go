@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Category groups related services for display and selection.
+type Category string
+
+const (
+	CategoryCloud     Category = "cloud"
+	CategoryContainer Category = "container"
+	CategoryAccess    Category = "access"
+	CategoryCustom    Category = "custom"
+)
+
+// Categories lists the categories in display order.
+var Categories = []Category{CategoryCloud, CategoryContainer, CategoryAccess, CategoryCustom}
+
+// CategorySelectorPrefix marks a service selector that names a category
+// rather than a service, as in "@cloud".
+const CategorySelectorPrefix = "@"
+
+// Categorizer is implemented by checkers that declare their category.
+// Checkers that do not, such as custom checkers, are CategoryCustom.
+type Categorizer interface {
+	Category() Category
+}
+
+// CheckerCategory returns the category checker declares.
+func CheckerCategory(checker ServiceChecker) Category {
+	if c, ok := checker.(Categorizer); ok && c.Category() != "" {
+		return c.Category()
+	}
+	return CategoryCustom
+}
+
+// ParseCategory parses a category name, with or without the "@" prefix.
+func ParseCategory(s string) (Category, error) {
+	name := Category(strings.ToLower(strings.TrimPrefix(s, CategorySelectorPrefix)))
+	for _, category := range Categories {
+		if name == category {
+			return category, nil
+		}
+	}
+	return "", fmt.Errorf("unknown service category: %s (supported: cloud, container, access, custom)", s)
+}
+
+// SelectCheckers returns the checkers matching any of selectors, in the
+// order of checkers. A selector is a service name or a category such as
+// "@cloud". With no selectors, all checkers are returned.
+func SelectCheckers(checkers []ServiceChecker, selectors []string) []ServiceChecker {
+	if len(selectors) == 0 {
+		return checkers
+	}
+
+	names := make(map[string]bool)
+	categories := make(map[Category]bool)
+	for _, selector := range selectors {
+		if strings.HasPrefix(selector, CategorySelectorPrefix) {
+			if category, err := ParseCategory(selector); err == nil {
+				categories[category] = true
+			}
+			continue
+		}
+		names[selector] = true
+	}
+
+	var selected []ServiceChecker
+	for _, checker := range checkers {
+		if names[checker.Name()] || categories[CheckerCategory(checker)] {
+			selected = append(selected, checker)
+		}
+	}
+	return selected
+}
+
+// CategoryTitle returns the display heading of a category.
+func CategoryTitle(category Category) string {
+	switch category {
+	case CategoryCloud:
+		return "Cloud"
+	case CategoryContainer:
+		return "Containers"
+	case CategoryAccess:
+		return "Access"
+	default:
+		return "Custom"
+	}
+}
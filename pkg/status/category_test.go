@@ -0,0 +1,156 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// categorizedChecker is a mock checker that declares a category.
+type categorizedChecker struct {
+	*mockChecker
+	category Category
+}
+
+func (c *categorizedChecker) Category() Category {
+	return c.category
+}
+
+// categoryCheckers returns checkers for two cloud services, a container
+// service, and a custom one.
+func categoryCheckers() []ServiceChecker {
+	return []ServiceChecker{
+		&categorizedChecker{newMockChecker("aws"), CategoryCloud},
+		&categorizedChecker{newMockChecker("docker"), CategoryContainer},
+		&categorizedChecker{newMockChecker("gcp"), CategoryCloud},
+		newMockChecker("custom"),
+	}
+}
+
+// TestSelectCheckers tests selecting checkers by name and category.
+func TestSelectCheckers(t *testing.T) {
+	tests := []struct {
+		name      string
+		selectors []string
+		want      string
+	}{
+		{"no selectors", nil, "aws,docker,gcp,custom"},
+		{"by name", []string{"gcp"}, "gcp"},
+		{"by category", []string{"@cloud"}, "aws,gcp"},
+		{"category is case-insensitive", []string{"@Container"}, "docker"},
+		{"undeclared category is custom", []string{"@custom"}, "custom"},
+		{"mixed keeps checker order", []string{"custom", "@cloud"}, "aws,gcp,custom"},
+		{"unknown category", []string{"@storage"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, checker := range SelectCheckers(categoryCheckers(), tt.selectors) {
+				names = append(names, checker.Name())
+			}
+			if got := strings.Join(names, ","); got != tt.want {
+				t.Errorf("SelectCheckers() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestParseCategory tests parsing category names.
+func TestParseCategory(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Category
+		wantErr bool
+	}{
+		{"cloud", CategoryCloud, false},
+		{"@access", CategoryAccess, false},
+		{"@CONTAINER", CategoryContainer, false},
+		{"@storage", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCategory(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCategory(%q) = %q, %v, want %q, wantErr %v", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// TestStatusCollector_CollectAll_Category tests that results carry their checker's category.
+func TestStatusCollector_CollectAll_Category(t *testing.T) {
+	failing := &categorizedChecker{newMockChecker("gcp"), CategoryCloud}
+	failing.statusErr = context.DeadlineExceeded
+	checkers := []ServiceChecker{
+		&categorizedChecker{newMockChecker("aws"), CategoryCloud},
+		failing,
+		newMockChecker("custom"),
+	}
+
+	for _, parallel := range []bool{false, true} {
+		collector := NewStatusCollector(checkers, 5*time.Second)
+		results, err := collector.CollectAll(context.Background(), StatusOptions{Parallel: parallel, Services: []string{"@cloud", "custom"}})
+		if err != nil {
+			t.Fatalf("CollectAll() error = %v", err)
+		}
+
+		want := map[string]Category{"aws": CategoryCloud, "gcp": CategoryCloud, "custom": CategoryCustom}
+		if len(results) != len(want) {
+			t.Fatalf("parallel=%v: CollectAll() returned %d results, want %d", parallel, len(results), len(want))
+		}
+		for _, r := range results {
+			if r.Category != want[r.Name] {
+				t.Errorf("parallel=%v: %s Category = %q, want %q", parallel, r.Name, r.Category, want[r.Name])
+			}
+		}
+	}
+}
+
+// TestStatusTableFormatter_FormatGroupByCategory tests category sections and their summaries.
+func TestStatusTableFormatter_FormatGroupByCategory(t *testing.T) {
+	statuses := []ServiceStatus{
+		{Name: "aws", Status: StatusActive, Category: CategoryCloud},
+		{Name: "ssh", Status: StatusActive, Category: CategoryAccess},
+		{Name: "gcp", Status: StatusInactive, Category: CategoryCloud},
+		{Name: "mine", Status: StatusActive},
+	}
+
+	formatter := &StatusTableFormatter{GroupByCategory: true, GroupByStatus: true}
+	output, err := formatter.Format(statuses)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	headings := []string{"Cloud — 1/2 active", "Access — 1/1 active", "Custom — 1/1 active"}
+	last := -1
+	for _, heading := range headings {
+		i := strings.Index(output, heading+"\n")
+		if i < 0 {
+			t.Fatalf("output has no %q heading:\n%s", heading, output)
+		}
+		if i < last {
+			t.Errorf("heading %q out of order:\n%s", heading, output)
+		}
+		last = i
+	}
+	if strings.Contains(output, "Containers") {
+		t.Errorf("output should leave out empty categories:\n%s", output)
+	}
+
+	cloud := output[strings.Index(output, headings[0]):strings.Index(output, headings[1])]
+	for _, name := range []string{"aws", "gcp"} {
+		if !strings.Contains(cloud, name) {
+			t.Errorf("Cloud section should list %s:\n%s", name, cloud)
+		}
+	}
+	if strings.Contains(cloud, "ssh") || strings.Contains(cloud, "mine") {
+		t.Errorf("Cloud section should list only cloud services:\n%s", cloud)
+	}
+	if !strings.Contains(output, "Active Environments: 3/4") {
+		t.Errorf("output should keep the summary footer:\n%s", output)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Defaults for ChangeLogWriter rotation.
+const (
+	DefaultChangeLogMaxSize  = 10 << 20 // 10 MiB
+	DefaultChangeLogMaxFiles = 5
+)
+
+// ChangeLogWriter appends change events to a file as JSON lines, one event
+// per line. When the file would grow past MaxSize it is rotated to path.1,
+// path.1 to path.2, and so on, keeping MaxFiles files in total.
+//
+// Appends and rotation hold an exclusive lock on path.lock, so several
+// processes, such as two watch sessions, can share a log safely.
+type ChangeLogWriter struct {
+	path     string
+	maxSize  int64
+	maxFiles int
+}
+
+// NewChangeLogWriter creates a writer for path. Non-positive maxSize and
+// maxFiles select the defaults.
+func NewChangeLogWriter(path string, maxSize int64, maxFiles int) *ChangeLogWriter {
+	if maxSize <= 0 {
+		maxSize = DefaultChangeLogMaxSize
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultChangeLogMaxFiles
+	}
+	return &ChangeLogWriter{path: path, maxSize: maxSize, maxFiles: maxFiles}
+}
+
+// Append writes events to the log, rotating first if needed.
+func (w *ChangeLogWriter) Append(events ...ChangeEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, event := range events {
+		if err := enc.Encode(event); err != nil {
+			return fmt.Errorf("failed to encode change event: %w", err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(w.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	lock, err := os.OpenFile(w.path+".lock", os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log lock: %w", err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock log: %w", err)
+	}
+	defer func() { _ = unlockFile(lock) }()
+
+	if info, err := os.Stat(w.path); err == nil && info.Size() > 0 && info.Size()+int64(buf.Len()) > w.maxSize {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open log: %w", err)
+	}
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write log: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts path.N-1 to path.N down to path to path.1, dropping the
+// file that falls off the end. The caller holds the lock.
+func (w *ChangeLogWriter) rotate() error {
+	if w.maxFiles == 1 {
+		if err := os.Remove(w.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log: %w", err)
+		}
+		return nil
+	}
+
+	for i := w.maxFiles - 1; i >= 1; i-- {
+		from := w.path
+		if i > 1 {
+			from = fmt.Sprintf("%s.%d", w.path, i-1)
+		}
+		if err := os.Rename(from, fmt.Sprintf("%s.%d", w.path, i)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate log: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadChangeLog reads change events written by ChangeLogWriter.
+func ReadChangeLog(r io.Reader) ([]ChangeEvent, error) {
+	var events []ChangeEvent
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var event ChangeEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("invalid change event on line %d: %w", line, err)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read change log: %w", err)
+	}
+	return events, nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// readLog reads the change events in path, failing the test on error.
+func readLog(t *testing.T, path string) []ChangeEvent {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	events, err := ReadChangeLog(f)
+	if err != nil {
+		t.Fatalf("ReadChangeLog(%s) error = %v", path, err)
+	}
+	return events
+}
+
+// TestChangeLogWriter_Append tests that events round-trip as JSON lines.
+func TestChangeLogWriter_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "changes.jsonl")
+	w := NewChangeLogWriter(path, 0, 0)
+
+	events := []ChangeEvent{
+		{Time: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), Service: "aws", Field: "profile", From: "dev", To: "prod"},
+		{Time: time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC), Service: "gcp", Field: "status", To: "active"},
+	}
+	if err := w.Append(events...); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	if got := readLog(t, path); !reflect.DeepEqual(got, events) {
+		t.Errorf("log = %v, want %v", got, events)
+	}
+}
+
+// TestChangeLogWriter_Rotation tests size-based rotation and the file limit.
+func TestChangeLogWriter_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	event := func(n int) ChangeEvent {
+		return ChangeEvent{Service: "aws", Field: "profile", To: fmt.Sprintf("p%d", n)}
+	}
+
+	// Each event is ~80 bytes, so a 100-byte limit rotates on every append.
+	w := NewChangeLogWriter(path, 100, 3)
+	for i := 0; i < 5; i++ {
+		if err := w.Append(event(i)); err != nil {
+			t.Fatalf("Append(%d) error = %v", i, err)
+		}
+	}
+
+	for file, want := range map[string]string{path: "p4", path + ".1": "p3", path + ".2": "p2"} {
+		events := readLog(t, file)
+		if len(events) != 1 || events[0].To != want {
+			t.Errorf("%s = %v, want only %s", filepath.Base(file), events, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("only 3 files should be kept, stat .3 error = %v", err)
+	}
+}
+
+// TestChangeLogWriter_ConcurrentWriters tests that separate writers sharing a log do not lose or interleave lines.
+func TestChangeLogWriter_ConcurrentWriters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	const writers, appends = 4, 50
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			// Each goroutine has its own writer, like separate processes.
+			w := NewChangeLogWriter(path, 1<<20, 2)
+			for j := 0; j < appends; j++ {
+				event := ChangeEvent{Service: fmt.Sprintf("w%d", writer), Field: "n", To: fmt.Sprint(j)}
+				if err := w.Append(event, event); err != nil {
+					t.Errorf("Append() error = %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	events := readLog(t, path)
+	if len(events) != writers*appends*2 {
+		t.Fatalf("log has %d events, want %d", len(events), writers*appends*2)
+	}
+	// Both events of an append are written together.
+	for i := 0; i < len(events); i += 2 {
+		if events[i] != events[i+1] {
+			t.Fatalf("events %d and %d were interleaved: %v, %v", i, i+1, events[i], events[i+1])
+		}
+	}
+}
+
+// TestChangeLogWriter_ConcurrentRotation tests that rotation under contention keeps every event.
+func TestChangeLogWriter_ConcurrentRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	const writers, appends = 4, 25
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+			w := NewChangeLogWriter(path, 1000, 100)
+			for j := 0; j < appends; j++ {
+				if err := w.Append(ChangeEvent{Service: fmt.Sprintf("w%d", writer), To: fmt.Sprint(j)}); err != nil {
+					t.Errorf("Append() error = %v", err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	files, err := filepath.Glob(path + "*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := 0
+	for _, file := range files {
+		if filepath.Ext(file) == ".lock" {
+			continue
+		}
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 1000 {
+			t.Errorf("%s is %d bytes, over the 1000-byte limit", filepath.Base(file), info.Size())
+		}
+		total += len(readLog(t, file))
+	}
+	if total != writers*appends {
+		t.Errorf("logs hold %d events, want %d", total, writers*appends)
+	}
+}
+
+// TestReadChangeLog_Invalid tests that a corrupt line is reported.
+func TestReadChangeLog_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "changes.jsonl")
+	if err := os.WriteFile(path, []byte("{\"service\":\"aws\"}\nnot json\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err := ReadChangeLog(f); err == nil {
+		t.Error("ReadChangeLog() should fail on line 2")
+	}
+}
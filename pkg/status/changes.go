@@ -0,0 +1,245 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChangeEvent records one field of a service that changed between two
+// status snapshots, e.g. in watch mode.
+type ChangeEvent struct {
+	Time    time.Time `json:"time"`
+	Service string    `json:"service"`
+	Field   string    `json:"field"`
+	From    string    `json:"from"`
+	To      string    `json:"to"`
+}
+
+// ChangeKind is how a service differs between two status snapshots.
+type ChangeKind string
+
+// Change kinds, in the order DiffStatuses reports them for a service.
+const (
+	ChangeAdded       ChangeKind = "added"
+	ChangeRemoved     ChangeKind = "removed"
+	ChangeStatus      ChangeKind = "statusChanged"
+	ChangeCredentials ChangeKind = "credentialsChanged"
+	ChangeCurrent     ChangeKind = "currentChanged"
+)
+
+// StatusChange is one way a service differs between two snapshots. Old is
+// nil for an added service and New for a removed one.
+type StatusChange struct {
+	Service string         `json:"service"`
+	Kind    ChangeKind     `json:"kind"`
+	Old     *ServiceStatus `json:"old,omitempty"`
+	New     *ServiceStatus `json:"new,omitempty"`
+}
+
+// DiffStatuses compares two snapshots by service name and returns how each
+// service changed from prev to next, ordered by service and then kind. A
+// service present in both can change its status or reason, its
+// credentials' validity, and its current configuration, each reported as
+// a separate change; other fields, such as check times, are ignored.
+func DiffStatuses(prev, next []ServiceStatus) []StatusChange {
+	before := statusesByName(prev)
+	after := statusesByName(next)
+
+	names := make([]string, 0, len(before)+len(after))
+	for name := range before {
+		names = append(names, name)
+	}
+	for name := range after {
+		if _, ok := before[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var changes []StatusChange
+	for _, name := range names {
+		from, to := before[name], after[name]
+		switch {
+		case from == nil:
+			changes = append(changes, StatusChange{Service: name, Kind: ChangeAdded, New: to})
+		case to == nil:
+			changes = append(changes, StatusChange{Service: name, Kind: ChangeRemoved, Old: from})
+		default:
+			if from.Status != to.Status || from.Reason != to.Reason {
+				changes = append(changes, StatusChange{Service: name, Kind: ChangeStatus, Old: from, New: to})
+			}
+			if from.Credentials.Valid != to.Credentials.Valid {
+				changes = append(changes, StatusChange{Service: name, Kind: ChangeCredentials, Old: from, New: to})
+			}
+			if from.Current != to.Current {
+				changes = append(changes, StatusChange{Service: name, Kind: ChangeCurrent, Old: from, New: to})
+			}
+		}
+	}
+	return changes
+}
+
+// statusesByName indexes statuses by service name. If a name repeats, the
+// last status wins.
+func statusesByName(statuses []ServiceStatus) map[string]*ServiceStatus {
+	byName := make(map[string]*ServiceStatus, len(statuses))
+	for i := range statuses {
+		byName[statuses[i].Name] = &statuses[i]
+	}
+	return byName
+}
+
+// String renders a change for display, e.g. "aws: active → inactive" or
+// "kubernetes: context dev → prod".
+func (c StatusChange) String() string {
+	switch c.Kind {
+	case ChangeAdded, ChangeRemoved:
+		return c.Service + ": " + string(c.Kind)
+	case ChangeStatus:
+		return c.Service + ": " + string(c.Old.Status) + " → " + string(c.New.Status)
+	case ChangeCredentials:
+		return c.Service + ": credentials " + credentialState(c.Old.Credentials) + " → " + credentialState(c.New.Credentials)
+	case ChangeCurrent:
+		from, to := changeFields(c.Old), changeFields(c.New)
+		var fields []string
+		for _, field := range changeFieldNames {
+			if field != "status" && field != "credentials" && from[field] != to[field] {
+				fields = append(fields, field+" "+quoteEmpty(from[field])+" → "+quoteEmpty(to[field]))
+			}
+		}
+		return c.Service + ": " + strings.Join(fields, ", ")
+	default:
+		return c.Service + ": " + string(c.Kind)
+	}
+}
+
+// DetectChanges compares two snapshots and returns the changes from prev to
+// next, field by field, stamped with at and ordered by service and field. A
+// service that appears or disappears changes its status from or to "".
+func DetectChanges(prev, next []ServiceStatus, at time.Time) []ChangeEvent {
+	var events []ChangeEvent
+	var last string
+	for _, change := range DiffStatuses(prev, next) {
+		// A service with several kinds of change has its fields compared once.
+		if change.Service == last {
+			continue
+		}
+		last = change.Service
+
+		from, to := changeFields(change.Old), changeFields(change.New)
+		for _, field := range changeFieldNames {
+			if from[field] != to[field] {
+				events = append(events, ChangeEvent{
+					Time:    at,
+					Service: change.Service,
+					Field:   field,
+					From:    from[field],
+					To:      to[field],
+				})
+			}
+		}
+	}
+	return events
+}
+
+// changeFieldNames are the fields compared by DetectChanges, in output order.
+var changeFieldNames = []string{
+	"status",
+	"profile",
+	"region",
+	"project",
+	"context",
+	"namespace",
+	"account",
+	"credentials",
+}
+
+// changeFields flattens a status into the fields DetectChanges compares. A
+// nil status, for a service that is absent, has no fields.
+func changeFields(st *ServiceStatus) map[string]string {
+	if st == nil {
+		return map[string]string{}
+	}
+	return map[string]string{
+		"status":      string(st.Status),
+		"profile":     st.Current.Profile,
+		"region":      st.Current.Region,
+		"project":     st.Current.Project,
+		"context":     st.Current.Context,
+		"namespace":   st.Current.Namespace,
+		"account":     st.Current.Account,
+		"credentials": credentialState(st.Credentials),
+	}
+}
+
+// credentialState summarizes credentials as "valid" or "invalid".
+func credentialState(creds CredentialStatus) string {
+	if creds.Valid {
+		return "valid"
+	}
+	return "invalid"
+}
+
+// ChangeRing keeps the most recent change events in a fixed amount of
+// memory, dropping the oldest once full. It is safe for concurrent use.
+type ChangeRing struct {
+	events []ChangeEvent
+	next   int
+	full   bool
+	mu     sync.Mutex
+}
+
+// NewChangeRing creates a ring holding up to capacity events.
+func NewChangeRing(capacity int) *ChangeRing {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &ChangeRing{events: make([]ChangeEvent, capacity)}
+}
+
+// Add appends events, overwriting the oldest when the ring is full.
+func (r *ChangeRing) Add(events ...ChangeEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, event := range events {
+		r.events[r.next] = event
+		r.next = (r.next + 1) % len(r.events)
+		if r.next == 0 {
+			r.full = true
+		}
+	}
+}
+
+// Events returns the held events, oldest first.
+func (r *ChangeRing) Events() []ChangeEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]ChangeEvent(nil), r.events[:r.next]...)
+	}
+	events := make([]ChangeEvent, 0, len(r.events))
+	events = append(events, r.events[r.next:]...)
+	return append(events, r.events[:r.next]...)
+}
+
+// String renders an event as a single line for display.
+func (e ChangeEvent) String() string {
+	return e.Time.Format("15:04:05") + " " + e.Service + " " + e.Field + ": " +
+		quoteEmpty(e.From) + " → " + quoteEmpty(e.To)
+}
+
+// quoteEmpty quotes a value so an empty one stays visible.
+func quoteEmpty(s string) string {
+	if s == "" {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestDetectChanges tests field changes, and services appearing and disappearing.
+func TestDetectChanges(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	prev := []ServiceStatus{
+		{Name: "aws", Status: StatusActive, Current: CurrentConfig{Profile: "dev", Region: "us-east-1"}, Credentials: CredentialStatus{Valid: true}},
+		{Name: "docker", Status: StatusActive, Current: CurrentConfig{Context: "default"}},
+	}
+	next := []ServiceStatus{
+		{Name: "aws", Status: StatusActive, Current: CurrentConfig{Profile: "prod", Region: "us-east-1"}},
+		{Name: "gcp", Status: StatusInactive},
+	}
+
+	want := []ChangeEvent{
+		{Time: at, Service: "aws", Field: "profile", From: "dev", To: "prod"},
+		{Time: at, Service: "aws", Field: "credentials", From: "valid", To: "invalid"},
+		{Time: at, Service: "docker", Field: "status", From: "active", To: ""},
+		{Time: at, Service: "docker", Field: "context", From: "default", To: ""},
+		{Time: at, Service: "docker", Field: "credentials", From: "invalid", To: ""},
+		{Time: at, Service: "gcp", Field: "status", From: "", To: "inactive"},
+		{Time: at, Service: "gcp", Field: "credentials", From: "", To: "invalid"},
+	}
+
+	if got := DetectChanges(prev, next, at); !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectChanges() = %v, want %v", got, want)
+	}
+	if got := DetectChanges(next, next, at); len(got) != 0 {
+		t.Errorf("DetectChanges() on identical snapshots = %v, want none", got)
+	}
+}
+
+// TestDiffStatuses tests each kind of change between two snapshots.
+func TestDiffStatuses(t *testing.T) {
+	checked := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	base := ServiceStatus{Name: "aws", Status: StatusActive, Current: CurrentConfig{Profile: "dev"}, Credentials: CredentialStatus{Valid: true}, CheckedAt: checked}
+
+	tests := []struct {
+		name   string
+		change func(st *ServiceStatus)
+		want   []ChangeKind
+		text   []string
+	}{
+		{"unchanged apart from check time", func(st *ServiceStatus) { st.CheckedAt = checked.Add(time.Minute) }, nil, nil},
+		{"status", func(st *ServiceStatus) { st.Status = StatusError }, []ChangeKind{ChangeStatus}, []string{"aws: active → error"}},
+		{"reason", func(st *ServiceStatus) { st.Status, st.Reason = StatusInactive, ReasonNotConfigured }, []ChangeKind{ChangeStatus}, []string{"aws: active → inactive"}},
+		{"credentials", func(st *ServiceStatus) { st.Credentials.Valid = false }, []ChangeKind{ChangeCredentials}, []string{"aws: credentials valid → invalid"}},
+		{"current", func(st *ServiceStatus) { st.Current.Profile, st.Current.Region = "prod", "eu-west-1" }, []ChangeKind{ChangeCurrent}, []string{`aws: profile dev → prod, region "" → eu-west-1`}},
+		{
+			"several at once",
+			func(st *ServiceStatus) { st.Status, st.Credentials.Valid, st.Current.Profile = StatusError, false, "prod" },
+			[]ChangeKind{ChangeStatus, ChangeCredentials, ChangeCurrent},
+			[]string{"aws: active → error", "aws: credentials valid → invalid", "aws: profile dev → prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := base
+			tt.change(&next)
+
+			changes := DiffStatuses([]ServiceStatus{base}, []ServiceStatus{next})
+			var kinds []ChangeKind
+			var text []string
+			for _, change := range changes {
+				kinds = append(kinds, change.Kind)
+				text = append(text, change.String())
+				if change.Service != "aws" || change.Old == nil || change.New == nil {
+					t.Errorf("change = %+v, want aws with old and new statuses", change)
+				}
+			}
+			if !reflect.DeepEqual(kinds, tt.want) {
+				t.Errorf("DiffStatuses() kinds = %v, want %v", kinds, tt.want)
+			}
+			if !reflect.DeepEqual(text, tt.text) {
+				t.Errorf("DiffStatuses() strings = %q, want %q", text, tt.text)
+			}
+		})
+	}
+}
+
+// TestDiffStatuses_AddedRemoved tests services appearing and disappearing,
+// and that changes are ordered by service.
+func TestDiffStatuses_AddedRemoved(t *testing.T) {
+	prev := []ServiceStatus{{Name: "kubernetes", Status: StatusActive}, {Name: "docker", Status: StatusActive}}
+	next := []ServiceStatus{{Name: "kubernetes", Status: StatusActive}, {Name: "aws", Status: StatusInactive}}
+
+	changes := DiffStatuses(prev, next)
+	if len(changes) != 2 {
+		t.Fatalf("DiffStatuses() = %v, want 2 changes", changes)
+	}
+
+	added, removed := changes[0], changes[1]
+	if added.Service != "aws" || added.Kind != ChangeAdded || added.Old != nil || added.New == nil || added.New.Status != StatusInactive {
+		t.Errorf("DiffStatuses()[0] = %+v, want aws added", added)
+	}
+	if removed.Service != "docker" || removed.Kind != ChangeRemoved || removed.Old == nil || removed.New != nil {
+		t.Errorf("DiffStatuses()[1] = %+v, want docker removed", removed)
+	}
+	if got := added.String(); got != "aws: added" {
+		t.Errorf("String() = %q, want %q", got, "aws: added")
+	}
+	if got := DiffStatuses(nil, nil); len(got) != 0 {
+		t.Errorf("DiffStatuses(nil, nil) = %v, want none", got)
+	}
+}
+
+// TestChangeRing tests that the ring keeps only the most recent events in order.
+func TestChangeRing(t *testing.T) {
+	event := func(n int) ChangeEvent {
+		return ChangeEvent{Service: "aws", Field: "profile", To: string(rune('a' + n))}
+	}
+
+	tests := []struct {
+		name  string
+		added int
+		want  []string
+	}{
+		{name: "empty", added: 0, want: nil},
+		{name: "partial", added: 2, want: []string{"a", "b"}},
+		{name: "exactly full", added: 3, want: []string{"a", "b", "c"}},
+		{name: "wrapped", added: 5, want: []string{"c", "d", "e"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := NewChangeRing(3)
+			for i := 0; i < tt.added; i++ {
+				ring.Add(event(i))
+			}
+
+			var got []string
+			for _, e := range ring.Events() {
+				got = append(got, e.To)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Events() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
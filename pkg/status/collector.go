@@ -5,26 +5,91 @@ package status
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
 	"sync"
 	"time"
 )
 
+// ObserverFunc is called after every service check with the service name,
+// how long the check took, the resulting status, and any check error.
+// It runs synchronously on the checking goroutine, so it must be cheap and,
+// when collecting in parallel, safe for concurrent use.
+type ObserverFunc func(service string, duration time.Duration, st StatusType, err error)
+
+// CollectorOption configures optional StatusCollector behavior.
+type CollectorOption func(*StatusCollector)
+
+// WithObserver registers an observer invoked after every service check.
+func WithObserver(observer ObserverFunc) CollectorOption {
+	return func(sc *StatusCollector) {
+		sc.observer = observer
+	}
+}
+
+// WithPersistentCache serves statuses from an on-disk cache when
+// StatusOptions.IncludeCache is set, refreshing them in the background.
+// Fresh results are always written through to the cache.
+func WithPersistentCache(cache *StatusCache) CollectorOption {
+	return func(sc *StatusCollector) {
+		sc.cache = cache
+	}
+}
+
+// WithMaxDetailSize sets the limit, in bytes, for each detail value and
+// health message; longer values are truncated. Zero or less disables
+// truncation. Values are always stripped of escapes and redacted.
+func WithMaxDetailSize(n int) CollectorOption {
+	return func(sc *StatusCollector) {
+		sc.maxDetailSize = n
+	}
+}
+
 // StatusCollector collects status information from multiple services.
 type StatusCollector struct {
 	checkers []ServiceChecker
 	timeout  time.Duration
+	observer ObserverFunc
+	geteuid  func() int
+
+	// maxDetailSize bounds each sanitized detail value.
+	maxDetailSize int
+
+	// cache is the optional persistent cache; refreshing tracks services
+	// with an in-flight background refresh.
+	cache      *StatusCache
+	refreshing map[string]bool
+	refreshWG  sync.WaitGroup
+
+	// cached holds the statuses from the most recent collection.
+	cached []ServiceStatus
+	mu     sync.RWMutex
+}
+
+// collectorSnapshot is the JSON representation of a StatusCollector.
+type collectorSnapshot struct {
+	Checkers       []string        `json:"checkers"`
+	Timeout        time.Duration   `json:"timeout"`
+	CachedStatuses []ServiceStatus `json:"cachedStatuses,omitempty"`
 }
 
 // NewStatusCollector creates a new status collector.
-func NewStatusCollector(checkers []ServiceChecker, timeout time.Duration) *StatusCollector {
+func NewStatusCollector(checkers []ServiceChecker, timeout time.Duration, opts ...CollectorOption) *StatusCollector {
 	if timeout == 0 {
 		timeout = 30 * time.Second
 	}
-	return &StatusCollector{
-		checkers: checkers,
-		timeout:  timeout,
+	sc := &StatusCollector{
+		checkers:      checkers,
+		timeout:       timeout,
+		geteuid:       os.Geteuid,
+		maxDetailSize: DefaultMaxDetailSize,
+		refreshing:    make(map[string]bool),
+	}
+	for _, opt := range opts {
+		opt(sc)
 	}
+	return sc
 }
 
 // CollectAll collects status from all registered services.
@@ -34,41 +99,61 @@ func (sc *StatusCollector) CollectAll(ctx context.Context, options StatusOptions
 		return nil, fmt.Errorf("no services found to check")
 	}
 
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, options.Timeout)
 	if options.Timeout == 0 {
-		ctxWithTimeout, cancel = context.WithTimeout(ctx, sc.timeout)
+		options.Timeout = sc.timeout
+	}
+	// Each service is bounded by its own timeout; the collection as a
+	// whole by the longest of them.
+	overall := options.Timeout
+	for _, checker := range checkers {
+		overall = max(overall, options.ForService(checker.Name()).Timeout)
 	}
+	ctxWithTimeout, cancel := context.WithTimeout(ctx, overall)
 	defer cancel()
 
+	var results []ServiceStatus
+	var err error
 	if options.Parallel {
-		return sc.collectParallel(ctxWithTimeout, checkers, options)
+		results, err = sc.collectParallel(ctxWithTimeout, checkers, options)
+	} else {
+		results, err = sc.collectSequential(ctxWithTimeout, checkers, options)
+	}
+	if err == nil {
+		// Results are in checker order, including failed checks.
+		for i := range results {
+			results[i].Category = CheckerCategory(checkers[i])
+			results[i].Hint = StatusHint(results[i])
+		}
+		sc.warnIfRoot(results)
+		sc.mu.Lock()
+		sc.cached = results
+		sc.mu.Unlock()
+		if staleErr := CheckMaxAge(results, options.MaxAge, time.Now()); staleErr != nil {
+			return nil, staleErr
+		}
 	}
-	return sc.collectSequential(ctxWithTimeout, checkers, options)
+	return results, err
 }
 
 // collectParallel collects status information in parallel.
+// Each service's health check runs in its own goroutine alongside its status
+// check, both bounded by options.MaxConcurrency, and is merged into the
+// status once both complete.
 func (sc *StatusCollector) collectParallel(ctx context.Context, checkers []ServiceChecker, options StatusOptions) ([]ServiceStatus, error) {
 	var wg sync.WaitGroup
 	results := make([]ServiceStatus, len(checkers))
-	errors := make([]error, len(checkers))
+	sem := newSemaphore(options.MaxConcurrency)
 
 	for i, checker := range checkers {
 		wg.Add(1)
 		go func(index int, c ServiceChecker) {
 			defer wg.Done()
-			status, err := sc.checkService(ctx, c, options)
+			status, err := sc.checkServiceParallel(ctx, c, options, sem)
 			if err != nil {
-				errors[index] = fmt.Errorf("failed to check %s: %w", c.Name(), err)
-				results[index] = ServiceStatus{
-					Name:   c.Name(),
-					Status: StatusError,
-					Details: map[string]string{
-						"error": err.Error(),
-					},
-				}
-			} else {
-				results[index] = *status
+				results[index] = errorStatus(c.Name(), err, sc.maxDetailSize)
+				return
 			}
+			results[index] = *status
 		}(i, checker)
 	}
 
@@ -76,6 +161,75 @@ func (sc *StatusCollector) collectParallel(ctx context.Context, checkers []Servi
 	return results, nil
 }
 
+// checkServiceParallel runs the status and health checks for a single service
+// concurrently, reporting the combined result to the observer.
+func (sc *StatusCollector) checkServiceParallel(ctx context.Context, checker ServiceChecker, options StatusOptions, sem semaphore) (*ServiceStatus, error) {
+	start := time.Now()
+	options = options.ForService(checker.Name())
+	ctx, cancel := serviceContext(ctx, options)
+	defer cancel()
+
+	if cached, ok := sc.cacheHit(checker, options); ok {
+		sc.observe(checker.Name(), start, cached, nil)
+		return cached, nil
+	}
+
+	var (
+		health    *HealthStatus
+		healthErr error
+		healthWG  sync.WaitGroup
+	)
+	if options.CheckHealth {
+		healthWG.Add(1)
+		go func() {
+			defer healthWG.Done()
+			sem.acquire()
+			defer sem.release()
+			health, healthErr = checkHealth(ctx, checker)
+		}()
+	}
+
+	sem.acquire()
+	status, err := checkStatus(ctx, checker)
+	sem.release()
+	healthWG.Wait()
+
+	if err == nil {
+		if options.CheckHealth {
+			mergeHealth(status, health, healthErr)
+		}
+		status.CheckedAt = time.Now()
+		sanitizeStatus(status, sc.maxDetailSize)
+		sc.storeCache(checker.Name(), status)
+	}
+
+	sc.observe(checker.Name(), start, status, err)
+	return status, err
+}
+
+// semaphore bounds concurrent checks; a nil semaphore is unbounded.
+type semaphore chan struct{}
+
+// newSemaphore creates a semaphore allowing n concurrent holders.
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire() {
+	if s != nil {
+		s <- struct{}{}
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
 // collectSequential collects status information sequentially.
 func (sc *StatusCollector) collectSequential(ctx context.Context, checkers []ServiceChecker, options StatusOptions) ([]ServiceStatus, error) {
 	results := make([]ServiceStatus, 0, len(checkers))
@@ -83,13 +237,7 @@ func (sc *StatusCollector) collectSequential(ctx context.Context, checkers []Ser
 	for _, checker := range checkers {
 		status, err := sc.checkService(ctx, checker, options)
 		if err != nil {
-			results = append(results, ServiceStatus{
-				Name:   checker.Name(),
-				Status: StatusError,
-				Details: map[string]string{
-					"error": err.Error(),
-				},
-			})
+			results = append(results, errorStatus(checker.Name(), err, sc.maxDetailSize))
 			continue
 		}
 		results = append(results, *status)
@@ -98,47 +246,181 @@ func (sc *StatusCollector) collectSequential(ctx context.Context, checkers []Ser
 	return results, nil
 }
 
-// checkService checks a single service status.
+// checkService checks a single service status, with the service's
+// PerService overrides applied, and reports it to the observer.
 func (sc *StatusCollector) checkService(ctx context.Context, checker ServiceChecker, options StatusOptions) (*ServiceStatus, error) {
-	status, err := checker.CheckStatus(ctx)
+	options = options.ForService(checker.Name())
+	ctx, cancel := serviceContext(ctx, options)
+	defer cancel()
+
+	if sc.observer == nil {
+		return sc.cachedCheck(ctx, checker, options)
+	}
+
+	start := time.Now()
+	status, err := sc.cachedCheck(ctx, checker, options)
+	sc.observe(checker.Name(), start, status, err)
+	return status, err
+}
+
+// serviceContext bounds ctx by options.Timeout, if set.
+func serviceContext(ctx context.Context, options StatusOptions) (context.Context, context.CancelFunc) {
+	if options.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, options.Timeout)
+}
+
+// observe reports a finished check to the observer, if one is registered.
+func (sc *StatusCollector) observe(service string, start time.Time, status *ServiceStatus, err error) {
+	if sc.observer == nil {
+		return
+	}
+
+	statusType := StatusError
+	if err == nil {
+		statusType = status.Status
+	}
+	sc.observer(service, time.Since(start), statusType, err)
+}
+
+// cachedCheck serves a service status from the persistent cache when allowed,
+// otherwise runs the check and writes the result through to the cache.
+func (sc *StatusCollector) cachedCheck(ctx context.Context, checker ServiceChecker, options StatusOptions) (*ServiceStatus, error) {
+	if cached, ok := sc.cacheHit(checker, options); ok {
+		return cached, nil
+	}
+
+	status, err := sc.runCheck(ctx, checker, options)
+	if err == nil {
+		sc.storeCache(checker.Name(), status)
+	}
+	return status, err
+}
+
+// cacheHit returns the persistently cached status for a service when caching
+// is enabled and requested, scheduling a background refresh on a hit.
+func (sc *StatusCollector) cacheHit(checker ServiceChecker, options StatusOptions) (*ServiceStatus, bool) {
+	if sc.cache == nil || !options.IncludeCache {
+		return nil, false
+	}
+
+	cached, ok := sc.cache.Get(checker.Name())
+	if !ok {
+		return nil, false
+	}
+
+	sc.refreshInBackground(checker, options)
+	if cached.Details == nil {
+		cached.Details = make(map[string]string)
+	}
+	cached.Details["cache"] = "hit"
+	return cached, true
+}
+
+// storeCache writes a fresh status through to the persistent cache, if any.
+func (sc *StatusCollector) storeCache(service string, status *ServiceStatus) {
+	if sc.cache != nil {
+		_ = sc.cache.Set(service, status, DefaultStatusCacheTTL)
+	}
+}
+
+// refreshInBackground re-runs a check and updates the cache without blocking
+// the caller. At most one refresh per service is in flight at a time.
+func (sc *StatusCollector) refreshInBackground(checker ServiceChecker, options StatusOptions) {
+	name := checker.Name()
+
+	sc.mu.Lock()
+	if sc.refreshing == nil {
+		sc.refreshing = make(map[string]bool)
+	}
+	if sc.refreshing[name] {
+		sc.mu.Unlock()
+		return
+	}
+	sc.refreshing[name] = true
+	sc.mu.Unlock()
+
+	sc.refreshWG.Add(1)
+	go func() {
+		defer sc.refreshWG.Done()
+		defer func() {
+			sc.mu.Lock()
+			delete(sc.refreshing, name)
+			sc.mu.Unlock()
+		}()
+
+		// The caller's context ends when CollectAll returns, so the refresh
+		// runs on its own bounded context.
+		timeout := sc.timeout
+		if options.Timeout > 0 {
+			timeout = options.Timeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		if status, err := sc.runCheck(ctx, checker, options); err == nil {
+			sc.storeCache(name, status)
+		}
+	}()
+}
+
+// Wait blocks until all background cache refreshes have completed.
+// Short-lived callers should call it before exiting so refreshed statuses
+// reach the cache.
+func (sc *StatusCollector) Wait() {
+	sc.refreshWG.Wait()
+}
+
+// runCheck runs the status and optional health check for a single service
+// and sanitizes the result before it is cached or returned.
+func (sc *StatusCollector) runCheck(ctx context.Context, checker ServiceChecker, options StatusOptions) (*ServiceStatus, error) {
+	status, err := checkStatus(ctx, checker)
 	if err != nil {
 		return nil, err
 	}
 
 	if options.CheckHealth {
-		healthStatus, healthErr := checker.CheckHealth(ctx)
-		if healthErr == nil {
-			status.HealthCheck = healthStatus
-		} else {
-			if status.Details == nil {
-				status.Details = make(map[string]string)
-			}
-			status.Details["health_check_error"] = healthErr.Error()
-		}
+		healthStatus, healthErr := checkHealth(ctx, checker)
+		mergeHealth(status, healthStatus, healthErr)
 	}
 
+	status.CheckedAt = time.Now()
+	sanitizeStatus(status, sc.maxDetailSize)
 	return status, nil
 }
 
-// filterCheckers filters checkers based on requested service names.
-func (sc *StatusCollector) filterCheckers(services []string) []ServiceChecker {
-	if len(services) == 0 {
-		return sc.checkers
+// mergeHealth attaches a health check result, or its error, to a status.
+func mergeHealth(status *ServiceStatus, health *HealthStatus, healthErr error) {
+	if healthErr == nil {
+		status.HealthCheck = health
+		return
 	}
 
-	serviceSet := make(map[string]bool)
-	for _, service := range services {
-		serviceSet[service] = true
+	if status.Details == nil {
+		status.Details = make(map[string]string)
 	}
+	status.Details["health_check_error"] = healthErr.Error()
+}
 
-	var filtered []ServiceChecker
-	for _, checker := range sc.checkers {
-		if serviceSet[checker.Name()] {
-			filtered = append(filtered, checker)
+// warnIfRoot adds a warning detail to every status when running as root.
+func (sc *StatusCollector) warnIfRoot(results []ServiceStatus) {
+	if sc.geteuid == nil || sc.geteuid() != 0 {
+		return
+	}
+
+	for i := range results {
+		if results[i].Details == nil {
+			results[i].Details = make(map[string]string)
 		}
+		results[i].Details["root_warning"] = "status collected as root; results may not reflect your user's configuration"
 	}
+}
 
-	return filtered
+// filterCheckers filters checkers based on requested service names and
+// "@category" selectors.
+func (sc *StatusCollector) filterCheckers(services []string) []ServiceChecker {
+	return SelectCheckers(sc.checkers, services)
 }
 
 // AddChecker adds a checker to the collector.
@@ -150,3 +432,62 @@ func (sc *StatusCollector) AddChecker(checker ServiceChecker) {
 func (sc *StatusCollector) GetCheckers() []ServiceChecker {
 	return sc.checkers
 }
+
+// CachedStatuses returns a copy of the statuses from the most recent collection.
+func (sc *StatusCollector) CachedStatuses() []ServiceStatus {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	if len(sc.cached) == 0 {
+		return nil
+	}
+	cached := make([]ServiceStatus, len(sc.cached))
+	copy(cached, sc.cached)
+	return cached
+}
+
+// MarshalJSON snapshots the collector's checker names, timeout, and cached statuses.
+func (sc *StatusCollector) MarshalJSON() ([]byte, error) {
+	names := make([]string, 0, len(sc.checkers))
+	for _, checker := range sc.checkers {
+		names = append(names, checker.Name())
+	}
+
+	return json.Marshal(collectorSnapshot{
+		Checkers:       names,
+		Timeout:        sc.timeout,
+		CachedStatuses: sc.CachedStatuses(),
+	})
+}
+
+// UnmarshalJSON reconstructs a collector from a snapshot produced by MarshalJSON.
+// Real checkers cannot be rebuilt, so each checker is replaced by a NoopChecker
+// that replays its cached status, if one was recorded.
+func (sc *StatusCollector) UnmarshalJSON(data []byte) error {
+	var snapshot collectorSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return fmt.Errorf("failed to parse collector snapshot: %w", err)
+	}
+
+	byName := make(map[string]*ServiceStatus, len(snapshot.CachedStatuses))
+	for i := range snapshot.CachedStatuses {
+		byName[snapshot.CachedStatuses[i].Name] = &snapshot.CachedStatuses[i]
+	}
+
+	checkers := make([]ServiceChecker, 0, len(snapshot.Checkers))
+	for _, name := range snapshot.Checkers {
+		checkers = append(checkers, NewNoopChecker(name, byName[name]))
+	}
+
+	timeout := snapshot.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	sc.checkers = checkers
+	sc.timeout = timeout
+	sc.cached = snapshot.CachedStatuses
+	return nil
+}
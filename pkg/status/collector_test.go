@@ -5,7 +5,10 @@ package status
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -21,6 +24,7 @@ type mockChecker struct {
 	checkCount   atomic.Int32
 	healthCount  atomic.Int32
 	delay        time.Duration
+	healthDelay  time.Duration
 }
 
 func newMockChecker(name string) *mockChecker {
@@ -60,6 +64,13 @@ func (m *mockChecker) CheckStatus(ctx context.Context) (*ServiceStatus, error) {
 
 func (m *mockChecker) CheckHealth(ctx context.Context) (*HealthStatus, error) {
 	m.healthCount.Add(1)
+	if m.healthDelay > 0 {
+		select {
+		case <-time.After(m.healthDelay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
 	if m.healthErr != nil {
 		return nil, m.healthErr
 	}
@@ -474,3 +485,345 @@ func TestStatusCollector_checkService_NilDetails(t *testing.T) {
 		t.Error("Details should be initialized even if originally nil")
 	}
 }
+
+// TestStatusCollector_MarshalJSON tests snapshot serialization.
+func TestStatusCollector_MarshalJSON(t *testing.T) {
+	mock1 := newMockChecker("service1")
+	mock2 := newMockChecker("service2")
+	collector := NewStatusCollector([]ServiceChecker{mock1, mock2}, 5*time.Second)
+
+	data, err := json.Marshal(collector)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	var snapshot map[string]interface{}
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		t.Fatalf("snapshot is not valid JSON: %v", err)
+	}
+
+	checkers, ok := snapshot["checkers"].([]interface{})
+	if !ok || len(checkers) != 2 {
+		t.Errorf("checkers = %v, want 2 names", snapshot["checkers"])
+	}
+	if _, ok := snapshot["timeout"]; !ok {
+		t.Error("snapshot should include timeout")
+	}
+	if _, ok := snapshot["cachedStatuses"]; ok {
+		t.Error("cachedStatuses should be omitted before any collection")
+	}
+}
+
+// TestStatusCollector_JSONRoundTrip tests replaying a collector from a snapshot.
+func TestStatusCollector_JSONRoundTrip(t *testing.T) {
+	mock1 := newMockChecker("service1")
+	mock2 := newMockChecker("service2")
+	mock2.status.Status = StatusInactive
+	mock2.status.Current.Profile = "staging"
+	collector := NewStatusCollector([]ServiceChecker{mock1, mock2}, 7*time.Second)
+
+	if _, err := collector.CollectAll(context.Background(), StatusOptions{}); err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+
+	data, err := json.Marshal(collector)
+	if err != nil {
+		t.Fatalf("MarshalJSON() error = %v", err)
+	}
+
+	var restored StatusCollector
+	if err := json.Unmarshal(data, &restored); err != nil {
+		t.Fatalf("UnmarshalJSON() error = %v", err)
+	}
+
+	if restored.timeout != 7*time.Second {
+		t.Errorf("timeout = %v, want 7s", restored.timeout)
+	}
+	if len(restored.GetCheckers()) != 2 {
+		t.Fatalf("restored %d checkers, want 2", len(restored.GetCheckers()))
+	}
+	if _, ok := restored.GetCheckers()[0].(*NoopChecker); !ok {
+		t.Errorf("restored checker type = %T, want *NoopChecker", restored.GetCheckers()[0])
+	}
+
+	results, err := restored.CollectAll(context.Background(), StatusOptions{})
+	if err != nil {
+		t.Fatalf("CollectAll() on restored collector error = %v", err)
+	}
+
+	if results[1].Status != StatusInactive || results[1].Current.Profile != "staging" {
+		t.Errorf("replayed status = %+v, want inactive/staging", results[1])
+	}
+}
+
+// TestStatusCollector_UnmarshalJSON_Invalid tests rejection of malformed snapshots.
+func TestStatusCollector_UnmarshalJSON_Invalid(t *testing.T) {
+	var collector StatusCollector
+	if err := json.Unmarshal([]byte(`{"checkers": "aws"}`), &collector); err == nil {
+		t.Error("UnmarshalJSON() should fail for malformed snapshot")
+	}
+}
+
+// TestStatusCollector_WithObserver tests observer invocation in both collection modes.
+func TestStatusCollector_WithObserver(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			mock1 := newMockChecker("service1")
+			mock2 := newMockChecker("service2")
+			mock3 := newMockChecker("service3")
+			mock3.statusErr = errors.New("check failed")
+
+			var calls atomic.Int32
+			var mu sync.Mutex
+			observed := make(map[string]StatusType)
+			observer := func(service string, duration time.Duration, st StatusType, err error) {
+				calls.Add(1)
+				if duration < 0 {
+					t.Errorf("observer duration for %s = %v, want >= 0", service, duration)
+				}
+				if service == "service3" && err == nil {
+					t.Error("observer should receive the check error for service3")
+				}
+				mu.Lock()
+				observed[service] = st
+				mu.Unlock()
+			}
+
+			collector := NewStatusCollector([]ServiceChecker{mock1, mock2, mock3}, 5*time.Second, WithObserver(observer))
+			if _, err := collector.CollectAll(context.Background(), StatusOptions{Parallel: parallel}); err != nil {
+				t.Fatalf("CollectAll() error = %v", err)
+			}
+
+			if got := calls.Load(); got != 3 {
+				t.Errorf("observer called %d times, want 3", got)
+			}
+			if observed["service1"] != StatusActive {
+				t.Errorf("observed service1 = %s, want active", observed["service1"])
+			}
+			if observed["service3"] != StatusError {
+				t.Errorf("observed service3 = %s, want error", observed["service3"])
+			}
+		})
+	}
+}
+
+// TestStatusCollector_CollectAll_RootWarning tests the warning detail added when running as root.
+func TestStatusCollector_CollectAll_RootWarning(t *testing.T) {
+	mock := newMockChecker("service1")
+	mock.status.Details = nil
+	collector := NewStatusCollector([]ServiceChecker{mock}, 5*time.Second)
+	collector.geteuid = func() int { return 0 }
+
+	results, err := collector.CollectAll(context.Background(), StatusOptions{})
+	if err != nil {
+		t.Fatalf("CollectAll() as root error = %v", err)
+	}
+
+	if results[0].Details["root_warning"] == "" {
+		t.Error("expected root_warning detail when collecting as root")
+	}
+
+	collector.geteuid = func() int { return 1000 }
+	mock.status.Details = nil
+	results, err = collector.CollectAll(context.Background(), StatusOptions{})
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+
+	if _, ok := results[0].Details["root_warning"]; ok {
+		t.Error("root_warning should not be set for non-root users")
+	}
+}
+
+// TestStatusCollector_CollectAll_ParallelHealth tests that health checks overlap
+// with status checks instead of running after them.
+func TestStatusCollector_CollectAll_ParallelHealth(t *testing.T) {
+	const delay = 100 * time.Millisecond
+
+	checkers := make([]ServiceChecker, 0, 6)
+	for i := 0; i < 6; i++ {
+		mock := newMockChecker(fmt.Sprintf("service%d", i))
+		mock.delay = delay
+		mock.healthDelay = delay
+		checkers = append(checkers, mock)
+	}
+	collector := NewStatusCollector(checkers, 5*time.Second)
+
+	start := time.Now()
+	results, err := collector.CollectAll(context.Background(), StatusOptions{
+		Parallel:    true,
+		CheckHealth: true,
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	for _, r := range results {
+		if r.HealthCheck == nil {
+			t.Errorf("%s: HealthCheck = nil, want merged health result", r.Name)
+		}
+	}
+
+	// Sequential status-then-health would take 2*delay per service.
+	if elapsed >= 2*delay {
+		t.Errorf("CollectAll() took %v, want < %v", elapsed, 2*delay)
+	}
+}
+
+// TestStatusCollector_CollectAll_MaxConcurrency tests that checks are bounded by MaxConcurrency.
+func TestStatusCollector_CollectAll_MaxConcurrency(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	mock1 := newMockChecker("service1")
+	mock1.delay = delay
+	mock1.healthDelay = delay
+	mock2 := newMockChecker("service2")
+	mock2.delay = delay
+	mock2.healthDelay = delay
+	collector := NewStatusCollector([]ServiceChecker{mock1, mock2}, 5*time.Second)
+
+	start := time.Now()
+	results, err := collector.CollectAll(context.Background(), StatusOptions{
+		Parallel:       true,
+		CheckHealth:    true,
+		MaxConcurrency: 1,
+	})
+	elapsed := time.Since(start)
+
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+	if len(results) != 2 || results[0].HealthCheck == nil || results[1].HealthCheck == nil {
+		t.Fatalf("CollectAll() = %+v, want two results with health", results)
+	}
+
+	// Four checks with a single slot cannot finish in less than 4*delay.
+	if elapsed < 4*delay {
+		t.Errorf("CollectAll() took %v, want >= %v with MaxConcurrency=1", elapsed, 4*delay)
+	}
+}
+
+// TestStatusCollector_CheckedAt tests that CollectAll stamps every result,
+// including failed checks, with the collection time.
+func TestStatusCollector_CheckedAt(t *testing.T) {
+	for _, parallel := range []bool{false, true} {
+		ok := newMockChecker("ok")
+		failing := newMockChecker("failing")
+		failing.statusErr = errors.New("check failed")
+		collector := NewStatusCollector([]ServiceChecker{ok, failing}, 5*time.Second)
+
+		before := time.Now()
+		results, err := collector.CollectAll(context.Background(), StatusOptions{Parallel: parallel})
+		after := time.Now()
+		if err != nil {
+			t.Fatalf("CollectAll() error = %v", err)
+		}
+
+		for _, result := range results {
+			if result.CheckedAt.Before(before) || result.CheckedAt.After(after) {
+				t.Errorf("parallel=%v %s CheckedAt = %v, want within [%v, %v]",
+					parallel, result.Name, result.CheckedAt, before, after)
+			}
+		}
+	}
+}
+
+// TestNewStatusOptions tests that the sequential flag flips the collection mode.
+func TestNewStatusOptions(t *testing.T) {
+	const delay = 50 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		sequential   bool
+		wantParallel bool
+	}{
+		{name: "default", sequential: false, wantParallel: true},
+		{name: "sequential", sequential: true, wantParallel: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := NewStatusOptions(true, tt.sequential)
+			if options.Parallel != tt.wantParallel {
+				t.Errorf("NewStatusOptions().Parallel = %v, want %v", options.Parallel, tt.wantParallel)
+			}
+			if !options.CheckHealth {
+				t.Error("NewStatusOptions().CheckHealth = false, want true")
+			}
+
+			checkers := make([]ServiceChecker, 0, 3)
+			for i := 0; i < 3; i++ {
+				mock := newMockChecker(fmt.Sprintf("service%d", i))
+				mock.delay = delay
+				checkers = append(checkers, mock)
+			}
+			collector := NewStatusCollector(checkers, 5*time.Second)
+
+			start := time.Now()
+			if _, err := collector.CollectAll(context.Background(), NewStatusOptions(false, tt.sequential)); err != nil {
+				t.Fatalf("CollectAll() error = %v", err)
+			}
+			elapsed := time.Since(start)
+
+			// Three status checks one after another take at least 3*delay.
+			if sequential := elapsed >= 3*delay; sequential == tt.wantParallel {
+				t.Errorf("CollectAll() took %v, want parallel = %v", elapsed, tt.wantParallel)
+			}
+		})
+	}
+}
+
+// TestStatusCollector_CollectAll_PerService tests a mix of per-service
+// overrides in one collection: health checks turned off for one service,
+// and timeouts longer and shorter than the global one.
+func TestStatusCollector_CollectAll_PerService(t *testing.T) {
+	off := false
+
+	for _, parallel := range []bool{true, false} {
+		t.Run(fmt.Sprintf("parallel=%v", parallel), func(t *testing.T) {
+			kubernetes := newMockChecker("kubernetes")
+			azure := newMockChecker("azure")
+			gcp := newMockChecker("gcp")
+			gcp.delay = 150 * time.Millisecond
+			docker := newMockChecker("docker")
+			docker.delay = 40 * time.Millisecond
+
+			collector := NewStatusCollector([]ServiceChecker{kubernetes, azure, gcp, docker}, time.Second)
+			options := StatusOptions{
+				CheckHealth: true,
+				Timeout:     100 * time.Millisecond,
+				Parallel:    parallel,
+				PerService: map[string]ServiceCheckOptions{
+					"azure":  {CheckHealth: &off},
+					"gcp":    {Timeout: time.Second},
+					"docker": {Timeout: 10 * time.Millisecond},
+				},
+			}
+
+			statuses, err := collector.CollectAll(context.Background(), options)
+			if err != nil {
+				t.Fatalf("CollectAll() error = %v", err)
+			}
+
+			if got := kubernetes.healthCount.Load(); got != 1 {
+				t.Errorf("kubernetes health checks = %d, want 1 (global)", got)
+			}
+			if got := azure.healthCount.Load(); got != 0 {
+				t.Errorf("azure health checks = %d, want 0 (overridden)", got)
+			}
+
+			want := map[string]StatusType{
+				"kubernetes": StatusActive,
+				"azure":      StatusActive,
+				"gcp":        StatusActive, // outlives the global timeout
+				"docker":     StatusError,  // times out before the global timeout
+			}
+			for _, st := range statuses {
+				if st.Status != want[st.Name] {
+					t.Errorf("%s status = %v, want %v", st.Name, st.Status, want[st.Name])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// goldenStatuses returns a fixture with every field of the output contract set.
+func goldenStatuses() []status.ServiceStatus {
+	checkedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	return []status.ServiceStatus{
+		{
+			Name:     "aws",
+			Status:   status.StatusActive,
+			Category: status.CategoryCloud,
+			Current: status.CurrentConfig{
+				Profile:   "prod",
+				Region:    "us-east-1",
+				Project:   "platform",
+				Context:   "prod-cluster",
+				Namespace: "payments",
+				Account:   "123456789012",
+			},
+			Credentials: status.CredentialStatus{
+				Valid:     true,
+				ExpiresAt: checkedAt.Add(time.Hour),
+				Type:      "sso",
+				Warning:   "expires soon",
+			},
+			LastUsed: checkedAt.Add(-time.Minute),
+			HealthCheck: &status.HealthStatus{
+				Status:    status.StatusActive,
+				Message:   "ok",
+				CheckedAt: checkedAt,
+				Duration:  250 * time.Millisecond,
+				Details:   map[string]interface{}{"caller_arn": "arn:aws:iam::123456789012:user/dev"},
+				Checks: []status.SubCheck{
+					{Name: "sts", Status: status.StatusActive, Message: "caller identity returned", Duration: 200 * time.Millisecond},
+				},
+			},
+			Details:   map[string]string{"account_alias": "prod"},
+			CheckedAt: checkedAt,
+		},
+		{
+			Name:        "docker",
+			Status:      status.StatusInactive,
+			Reason:      status.ReasonNotRunning,
+			Credentials: status.CredentialStatus{Type: "none"},
+		},
+	}
+}
+
+// TestOutputContract tests the JSON and YAML field names against golden
+// files. A failure here means the output contract changed; see status.ServiceStatus.
+func TestOutputContract(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter status.StatusFormatter
+		golden    string
+	}{
+		{name: "json", formatter: status.NewStatusJSONFormatter(true), golden: "status.golden.json"},
+		{name: "yaml", formatter: status.NewStatusYAMLFormatter(), golden: "status.golden.yaml"},
+		{name: "legacy yaml", formatter: &status.StatusYAMLFormatter{Version: status.OutputVersionLegacy}, golden: "status_legacy.golden.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := tt.formatter.Format(goldenStatuses())
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			testutil.AssertGolden(t, tt.golden, output)
+		})
+	}
+}
+
+// TestParseOutputVersion tests parsing output versions.
+func TestParseOutputVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    status.OutputVersion
+		wantErr bool
+	}{
+		{input: "", want: status.OutputVersionStable},
+		{input: "stable", want: status.OutputVersionStable},
+		{input: "Legacy", want: status.OutputVersionLegacy},
+		{input: "v3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := status.ParseOutputVersion(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("status.ParseOutputVersion(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("status.ParseOutputVersion(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
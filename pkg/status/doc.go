@@ -4,6 +4,14 @@
 //   - ServiceChecker: Interface for checking individual service status
 //   - StatusCollector: Aggregates status from multiple checkers
 //   - Formatter: Formats status output for display
+//   - DiffStatuses: Added, removed, and changed services between snapshots
+//   - DetectChanges, ChangeRing: Change events between snapshots, kept in bounded memory
+//   - ChangeLogWriter: Rotating, lock-protected JSON-lines change log
+//   - ExpiryParser: Extracts credential expiry per credential type
+//   - ExecChecker: Runs an external checker executable from a checkers.d directory
+//   - Category: Groups services (cloud, container, access) for selection and display
+//   - SubCheck: One probe of a health check, such as connectivity or disk usage
+//   - RemediationHint, StatusHint: Suggested fixes for known check and switch failures
 //
 // Example usage:
 //
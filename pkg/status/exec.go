@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	plugin "github.com/gizzahub/gzh-cli-dev-env/internal/exec"
+)
+
+// DefaultExecCheckerTimeout bounds a single run of an external checker.
+const DefaultExecCheckerTimeout = 10 * time.Second
+
+// ExecCheckerArgs are the arguments an external checker is invoked with.
+var ExecCheckerArgs = []string{"status", "--format", "json"}
+
+// ExecChecker is a ServiceChecker backed by an external executable, so
+// checkers for internal systems can be added without recompiling.
+//
+// The executable is run with ExecCheckerArgs and must print one
+// ServiceStatus as a JSON object, in the field names of the JSON output of
+// `dev-env status`, and exit 0. Its name is taken from the checker, and its
+// category is always custom. A checker that fails, times out, or prints
+// anything else is reported as a StatusError row; collection carries on.
+type ExecChecker struct {
+	name string
+	path string
+	// Timeout bounds each run, DefaultExecCheckerTimeout if zero.
+	Timeout time.Duration
+}
+
+// NewExecChecker creates a checker running the executable at path, named
+// after its base name without extension.
+func NewExecChecker(path string) *ExecChecker {
+	return &ExecChecker{name: plugin.Name(path), path: path}
+}
+
+// DiscoverExecCheckers returns a checker for every executable in dir,
+// ordered by name. Hidden files are skipped, and a missing dir has none.
+func DiscoverExecCheckers(dir string) ([]*ExecChecker, error) {
+	paths, err := plugin.Discover(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkers directory: %w", err)
+	}
+
+	var checkers []*ExecChecker
+	for _, path := range paths {
+		checkers = append(checkers, NewExecChecker(path))
+	}
+	return checkers, nil
+}
+
+// Name returns the service name.
+func (e *ExecChecker) Name() string {
+	return e.name
+}
+
+// Path returns the path of the executable.
+func (e *ExecChecker) Path() string {
+	return e.path
+}
+
+// CheckStatus runs the executable and returns the status it prints, or a
+// StatusError status describing why it could not.
+func (e *ExecChecker) CheckStatus(ctx context.Context) (*ServiceStatus, error) {
+	st, err := e.run(ctx)
+	if err != nil {
+		return e.errorStatus(err), nil
+	}
+	return st, nil
+}
+
+// CheckHealth runs the executable and returns the health check it prints,
+// or one derived from its status.
+func (e *ExecChecker) CheckHealth(ctx context.Context) (*HealthStatus, error) {
+	start := time.Now()
+	st, err := e.run(ctx)
+	health := &HealthStatus{
+		CheckedAt: start,
+		Duration:  time.Since(start),
+		Details:   map[string]interface{}{"checker": e.path},
+	}
+
+	switch {
+	case err != nil:
+		health.Status = StatusError
+		health.Message = err.Error()
+		if stderr := execStderr(err); stderr != "" {
+			health.Details["stderr"] = stderr
+		}
+	case st.HealthCheck != nil:
+		reported := *st.HealthCheck
+		reported.CheckedAt = start
+		reported.Duration = health.Duration
+		return &reported, nil
+	default:
+		health.Status = st.Status
+	}
+	return health, nil
+}
+
+// run invokes the executable and parses its output.
+func (e *ExecChecker) run(ctx context.Context) (*ServiceStatus, error) {
+	command := plugin.Command{Path: e.path, Args: ExecCheckerArgs, Timeout: e.Timeout}
+	if command.Timeout <= 0 {
+		command.Timeout = DefaultExecCheckerTimeout
+	}
+
+	stdout, stderr, err := command.Run(ctx)
+	if errors.Is(err, plugin.ErrTimedOut) {
+		return nil, fmt.Errorf("checker %w", err)
+	}
+	if err != nil {
+		return nil, &execCheckerError{err: fmt.Errorf("checker failed: %w", err), stderr: strings.TrimSpace(string(stderr))}
+	}
+
+	var st ServiceStatus
+	if err := json.Unmarshal(stdout, &st); err != nil {
+		return nil, &execCheckerError{err: fmt.Errorf("checker printed invalid status JSON: %w", err), stderr: strings.TrimSpace(string(stderr))}
+	}
+	switch st.Status {
+	case StatusActive, StatusInactive, StatusError, StatusUnknown:
+	default:
+		return nil, fmt.Errorf("checker reported unknown status %q", st.Status)
+	}
+
+	st.Name = e.name
+	if st.Details == nil {
+		st.Details = make(map[string]string)
+	}
+	return &st, nil
+}
+
+// errorStatus reports a checker that could not produce a status.
+func (e *ExecChecker) errorStatus(err error) *ServiceStatus {
+	st := &ServiceStatus{
+		Name:   e.name,
+		Status: StatusError,
+		Details: map[string]string{
+			"error":   err.Error(),
+			"checker": e.path,
+		},
+	}
+	if stderr := execStderr(err); stderr != "" {
+		st.Details["stderr"] = stderr
+	}
+	return st
+}
+
+// execCheckerError is a checker failure with what the checker wrote to
+// standard error.
+type execCheckerError struct {
+	err    error
+	stderr string
+}
+
+// Error implements the error interface.
+func (e *execCheckerError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *execCheckerError) Unwrap() error {
+	return e.err
+}
+
+// execStderr returns the standard error captured with err, if any.
+func execStderr(err error) string {
+	var checkerErr *execCheckerError
+	if errors.As(err, &checkerErr) {
+		return checkerErr.stderr
+	}
+	return ""
+}
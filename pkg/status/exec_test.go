@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// skipWithoutShell skips tests whose checkers are shell scripts.
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("external checkers in tests are shell scripts")
+	}
+}
+
+// writeChecker writes an executable shell script checker into dir.
+func writeChecker(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o700); err != nil { // #nosec G306 - test executable
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestDiscoverExecCheckers tests discovery of the example checker.
+func TestDiscoverExecCheckers(t *testing.T) {
+	skipWithoutShell(t)
+
+	checkers, err := DiscoverExecCheckers(filepath.Join("testdata", "checkers.d"))
+	if err != nil {
+		t.Fatalf("DiscoverExecCheckers() error = %v", err)
+	}
+	if len(checkers) != 1 || checkers[0].Name() != "vpn" {
+		t.Fatalf("DiscoverExecCheckers() = %v, want only the vpn checker", checkers)
+	}
+
+	missing, err := DiscoverExecCheckers(filepath.Join(t.TempDir(), "missing"))
+	if err != nil || missing != nil {
+		t.Errorf("DiscoverExecCheckers() on a missing dir = %v, %v, want none", missing, err)
+	}
+}
+
+// TestNewExecChecker_Name tests that checkers are named after the executable.
+func TestNewExecChecker_Name(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/checkers.d/vpn", "vpn"},
+		{"/checkers.d/artifactory.sh", "artifactory"},
+		{`C:\checkers.d\vpn.exe`, "vpn"},
+	}
+
+	for _, tt := range tests {
+		path := filepath.FromSlash(tt.path)
+		if runtime.GOOS != "windows" && strings.Contains(tt.path, `\`) {
+			continue
+		}
+		if got := NewExecChecker(path).Name(); got != tt.expected {
+			t.Errorf("NewExecChecker(%q).Name() = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+// TestExecChecker_Example tests the example checker end to end through a
+// status collector.
+func TestExecChecker_Example(t *testing.T) {
+	skipWithoutShell(t)
+	t.Setenv("VPN_INTERFACE", "dev-env-test0")
+
+	checker := NewExecChecker(filepath.Join("testdata", "checkers.d", "vpn"))
+	collector := NewStatusCollector([]ServiceChecker{checker}, 5*time.Second)
+	statuses, err := collector.CollectAll(context.Background(), StatusOptions{CheckHealth: true})
+	if err != nil {
+		t.Fatalf("CollectAll() error = %v", err)
+	}
+
+	st := statuses[0]
+	if st.Name != "vpn" || st.Status != StatusInactive || st.Category != CategoryCustom {
+		t.Errorf("status = %+v, want an inactive custom vpn service", st)
+	}
+	if st.Current.Context != "dev-env-test0" || st.Credentials.Type != "vpn" {
+		t.Errorf("status = %+v, want the fields the checker printed", st)
+	}
+	if st.HealthCheck == nil || st.HealthCheck.Status != StatusInactive {
+		t.Errorf("health = %+v, want inactive", st.HealthCheck)
+	}
+}
+
+// TestExecChecker_Misbehaving tests that broken checkers become error rows
+// without failing collection of the other services.
+func TestExecChecker_Misbehaving(t *testing.T) {
+	skipWithoutShell(t)
+	dir := t.TempDir()
+
+	tests := []struct {
+		name      string
+		body      string
+		wantError string
+		stderr    string
+	}{
+		{"exit status", "echo 'vpn client not installed' >&2; exit 3\n", "checker failed: exit status 3", "vpn client not installed"},
+		{"invalid json", "echo 'connected'\n", "invalid status JSON", ""},
+		{"unknown status", `echo '{"status": "connected"}'` + "\n", `unknown status "connected"`, ""},
+		{"timeout", "sleep 5\n", "timed out", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewExecChecker(writeChecker(t, dir, strings.ReplaceAll(tt.name, " ", "-"), tt.body))
+			checker.Timeout = 200 * time.Millisecond
+			healthy := NewNoopChecker("aws", &ServiceStatus{Name: "aws", Status: StatusActive})
+
+			collector := NewStatusCollector([]ServiceChecker{checker, healthy}, 5*time.Second)
+			statuses, err := collector.CollectAll(context.Background(), StatusOptions{Parallel: true})
+			if err != nil {
+				t.Fatalf("CollectAll() error = %v", err)
+			}
+			if len(statuses) != 2 || statuses[1].Status != StatusActive {
+				t.Fatalf("CollectAll() = %+v, want the other service unaffected", statuses)
+			}
+
+			st := statuses[0]
+			if st.Status != StatusError {
+				t.Errorf("status = %v, want %v", st.Status, StatusError)
+			}
+			if !strings.Contains(st.Details["error"], tt.wantError) {
+				t.Errorf("details error = %q, want it to contain %q", st.Details["error"], tt.wantError)
+			}
+			if st.Details["stderr"] != tt.stderr {
+				t.Errorf("details stderr = %q, want %q", st.Details["stderr"], tt.stderr)
+			}
+		})
+	}
+}
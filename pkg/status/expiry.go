@@ -0,0 +1,240 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExpiryParser extracts when a credential expires from the output of the
+// command or file that describes it. Each credential type has its own
+// parser, so supporting a new type only takes a new parser.
+type ExpiryParser interface {
+	// ParseExpiry returns the expiry time, or the zero time if the
+	// credential does not expire.
+	ParseExpiry(output []byte) (time.Time, error)
+}
+
+// ExpiryParserFunc adapts a function to an ExpiryParser.
+type ExpiryParserFunc func(output []byte) (time.Time, error)
+
+// ParseExpiry calls f.
+func (f ExpiryParserFunc) ParseExpiry(output []byte) (time.Time, error) {
+	return f(output)
+}
+
+// Parsers for the credential types dev-env checks.
+var (
+	// AWSSessionExpiry parses temporary AWS credentials, as printed by
+	// `aws configure export-credentials` or `aws sts get-session-token`.
+	// Long-lived access keys have no expiration.
+	AWSSessionExpiry ExpiryParser = ExpiryParserFunc(parseAWSSessionExpiry)
+
+	// AWSSSOExpiry parses an AWS SSO token cache file from ~/.aws/sso/cache.
+	AWSSSOExpiry ExpiryParser = ExpiryParserFunc(parseAWSSSOExpiry)
+
+	// GCPTokenExpiry parses `gcloud config config-helper --format=json`.
+	GCPTokenExpiry ExpiryParser = ExpiryParserFunc(parseGCPTokenExpiry)
+
+	// KubeOIDCExpiry parses a kubeconfig user entry as JSON, using the
+	// auth provider's expiry or the exp claim of its ID token, or an
+	// ExecCredential printed by an exec credential plugin.
+	KubeOIDCExpiry ExpiryParser = ExpiryParserFunc(parseKubeOIDCExpiry)
+
+	// GPGKeyExpiry parses `gpg --list-secret-keys --with-colons <key>`,
+	// using the expiry of the first secret key listed.
+	GPGKeyExpiry ExpiryParser = ExpiryParserFunc(parseGPGKeyExpiry)
+)
+
+// SetExpiry sets ExpiresAt from output using parser. ExpiresAt is left
+// unchanged if parsing fails or the credential does not expire.
+func (c *CredentialStatus) SetExpiry(parser ExpiryParser, output []byte) error {
+	expiresAt, err := parser.ParseExpiry(output)
+	if err != nil {
+		return err
+	}
+	if !expiresAt.IsZero() {
+		c.ExpiresAt = expiresAt
+	}
+	return nil
+}
+
+// parseAWSSessionExpiry reads Expiration at the top level or under Credentials.
+func parseAWSSessionExpiry(output []byte) (time.Time, error) {
+	var creds struct {
+		Expiration  string
+		Credentials struct {
+			Expiration string
+		}
+	}
+	if err := json.Unmarshal(output, &creds); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse AWS credentials: %w", err)
+	}
+
+	expiration := creds.Expiration
+	if expiration == "" {
+		expiration = creds.Credentials.Expiration
+	}
+	return parseTimestamp(expiration)
+}
+
+// parseAWSSSOExpiry reads expiresAt from an SSO token cache file.
+func parseAWSSSOExpiry(output []byte) (time.Time, error) {
+	var token struct {
+		ExpiresAt string `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(output, &token); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse AWS SSO token: %w", err)
+	}
+	return parseTimestamp(token.ExpiresAt)
+}
+
+// parseGCPTokenExpiry reads credential.token_expiry from config-helper.
+func parseGCPTokenExpiry(output []byte) (time.Time, error) {
+	var helper struct {
+		Credential struct {
+			TokenExpiry string `json:"token_expiry"`
+		} `json:"credential"`
+	}
+	if err := json.Unmarshal(output, &helper); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse gcloud credential: %w", err)
+	}
+	return parseTimestamp(helper.Credential.TokenExpiry)
+}
+
+// parseKubeOIDCExpiry reads a kubeconfig user or an ExecCredential.
+func parseKubeOIDCExpiry(output []byte) (time.Time, error) {
+	var user struct {
+		AuthProvider struct {
+			Config map[string]string `json:"config"`
+		} `json:"auth-provider"`
+		Status struct {
+			ExpirationTimestamp string `json:"expirationTimestamp"`
+		} `json:"status"`
+	}
+	if err := json.Unmarshal(output, &user); err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse kubeconfig user: %w", err)
+	}
+
+	if user.Status.ExpirationTimestamp != "" {
+		return parseTimestamp(user.Status.ExpirationTimestamp)
+	}
+	if expiry := user.AuthProvider.Config["expiry"]; expiry != "" {
+		return parseTimestamp(expiry)
+	}
+	if idToken := user.AuthProvider.Config["id-token"]; idToken != "" {
+		return jwtExpiry(idToken)
+	}
+	return time.Time{}, nil
+}
+
+// parseGPGKeyExpiry reads the expiration field, in seconds since the
+// epoch, of the first sec record of gpg's colon listing.
+func parseGPGKeyExpiry(output []byte) (time.Time, error) {
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, ":")
+		if fields[0] != "sec" {
+			continue
+		}
+		if len(fields) < 7 || fields[6] == "" {
+			return time.Time{}, nil
+		}
+		seconds, err := strconv.ParseInt(fields[6], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid GPG key expiry: %q", fields[6])
+		}
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return time.Time{}, fmt.Errorf("no secret key in GPG listing")
+}
+
+// jwtExpiry returns the exp claim of a JWT without verifying it.
+func jwtExpiry(token string) (time.Time, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, fmt.Errorf("malformed ID token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("malformed ID token payload: %w", err)
+	}
+
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return time.Time{}, fmt.Errorf("malformed ID token claims: %w", err)
+	}
+	if claims.Exp == 0 {
+		return time.Time{}, nil
+	}
+	return time.Unix(claims.Exp, 0).UTC(), nil
+}
+
+// parseTimestamp parses an RFC 3339 timestamp, or the "UTC"-suffixed form
+// older AWS CLI versions write. An empty string is the zero time.
+func parseTimestamp(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse("2006-01-02T15:04:05UTC", s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry timestamp: %q", s)
+}
+
+// DefaultExpiryWarningWindow is how far ahead NextExpiry looks in the
+// status table, matching when its credentials column starts warning.
+const DefaultExpiryWarningWindow = 24 * time.Hour
+
+// NextExpiry returns the status whose credentials expire soonest, if any
+// expire before now plus window. Credentials that have already expired
+// count, since they are the most urgent. ok is false if none qualify.
+func NextExpiry(statuses []ServiceStatus, now time.Time, window time.Duration) (st ServiceStatus, ok bool) {
+	deadline := now.Add(window)
+	for _, candidate := range statuses {
+		expiresAt := candidate.Credentials.ExpiresAt
+		if expiresAt.IsZero() || !expiresAt.Before(deadline) {
+			continue
+		}
+		if !ok || expiresAt.Before(st.Credentials.ExpiresAt) {
+			st, ok = candidate, true
+		}
+	}
+	return st, ok
+}
+
+// ExpirySummary renders st's credential expiry relative to now as a
+// one-line summary, such as "Next expiry: aws in 12m".
+func ExpirySummary(st ServiceStatus, now time.Time) string {
+	remaining := st.Credentials.ExpiresAt.Sub(now)
+	if remaining < 0 {
+		return fmt.Sprintf("Next expiry: %s expired %s ago", st.Name, shortDuration(-remaining))
+	}
+	return fmt.Sprintf("Next expiry: %s in %s", st.Name, shortDuration(remaining))
+}
+
+// shortDuration renders d in its largest whole unit, such as 45s, 12m,
+// 3h, or 2d.
+func shortDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	}
+}
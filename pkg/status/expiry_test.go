@@ -0,0 +1,237 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+// TestExpiryParsers tests each credential type's parser with representative output.
+func TestExpiryParsers(t *testing.T) {
+	want := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	idToken := "eyJhbGciOiJSUzI1NiJ9." +
+		base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"https://issuer","exp":1748781000}`)) +
+		".c2lnbmF0dXJl"
+
+	tests := []struct {
+		name    string
+		parser  ExpiryParser
+		output  string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:   "aws export-credentials",
+			parser: AWSSessionExpiry,
+			output: `{"Version": 1, "AccessKeyId": "ASIA", "SecretAccessKey": "x", "SessionToken": "y", "Expiration": "2025-06-01T12:30:00+00:00"}`,
+			want:   want,
+		},
+		{
+			name:   "aws get-session-token",
+			parser: AWSSessionExpiry,
+			output: `{"Credentials": {"AccessKeyId": "ASIA", "Expiration": "2025-06-01T12:30:00Z"}}`,
+			want:   want,
+		},
+		{
+			name:   "aws long-lived keys",
+			parser: AWSSessionExpiry,
+			output: `{"Version": 1, "AccessKeyId": "AKIA", "SecretAccessKey": "x"}`,
+		},
+		{
+			name:   "aws sso cache",
+			parser: AWSSSOExpiry,
+			output: `{"startUrl": "https://example.awsapps.com/start", "region": "us-east-1", "accessToken": "t", "expiresAt": "2025-06-01T12:30:00Z"}`,
+			want:   want,
+		},
+		{
+			name:   "aws sso cache from older CLI",
+			parser: AWSSSOExpiry,
+			output: `{"accessToken": "t", "expiresAt": "2025-06-01T12:30:00UTC"}`,
+			want:   want,
+		},
+		{
+			name:   "gcp config-helper",
+			parser: GCPTokenExpiry,
+			output: `{"configuration": {"active_configuration": "default"}, "credential": {"access_token": "ya29", "token_expiry": "2025-06-01T12:30:00Z"}}`,
+			want:   want,
+		},
+		{
+			name:   "kube oidc auth provider expiry",
+			parser: KubeOIDCExpiry,
+			output: `{"auth-provider": {"name": "gcp", "config": {"access-token": "ya29", "expiry": "2025-06-01T14:30:00+02:00"}}}`,
+			want:   want,
+		},
+		{
+			name:   "kube oidc id token",
+			parser: KubeOIDCExpiry,
+			output: `{"auth-provider": {"name": "oidc", "config": {"client-id": "k8s", "id-token": "` + idToken + `"}}}`,
+			want:   want,
+		},
+		{
+			name:   "kube exec credential",
+			parser: KubeOIDCExpiry,
+			output: `{"kind": "ExecCredential", "apiVersion": "client.authentication.k8s.io/v1", "status": {"token": "t", "expirationTimestamp": "2025-06-01T12:30:00Z"}}`,
+			want:   want,
+		},
+		{
+			name:   "kube client certificate",
+			parser: KubeOIDCExpiry,
+			output: `{"client-certificate-data": "REDACTED", "client-key-data": "REDACTED"}`,
+		},
+		{
+			name:   "gpg secret key",
+			parser: GPGKeyExpiry,
+			output: "sec:u:255:22:ABCDEF0123456789:1700000000:1748781000::u:::scESC:::+:::23::0:\nfpr:::::::::0123456789ABCDEF0123456789ABCDEF01234567:\n",
+			want:   want,
+		},
+		{
+			name:   "gpg key without expiry",
+			parser: GPGKeyExpiry,
+			output: "sec:u:255:22:ABCDEF0123456789:1700000000:::u:::scESC:::+:::23::0:\n",
+		},
+		{
+			name:    "gpg listing without secret key",
+			parser:  GPGKeyExpiry,
+			output:  "tru::1:1700000000:0:3:1:5\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid json",
+			parser:  GCPTokenExpiry,
+			output:  `not json`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid timestamp",
+			parser:  AWSSSOExpiry,
+			output:  `{"expiresAt": "tomorrow"}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed id token",
+			parser:  KubeOIDCExpiry,
+			output:  `{"auth-provider": {"config": {"id-token": "not-a-jwt"}}}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.parser.ParseExpiry([]byte(tt.output))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseExpiry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseExpiry() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCredentialStatus_SetExpiry tests that only a parsed expiry replaces ExpiresAt.
+func TestCredentialStatus_SetExpiry(t *testing.T) {
+	previous := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	creds := CredentialStatus{ExpiresAt: previous}
+
+	if err := creds.SetExpiry(AWSSessionExpiry, []byte(`{"AccessKeyId": "AKIA"}`)); err != nil {
+		t.Fatalf("SetExpiry() error = %v", err)
+	}
+	if !creds.ExpiresAt.Equal(previous) {
+		t.Errorf("ExpiresAt = %v, want it unchanged for a credential that does not expire", creds.ExpiresAt)
+	}
+
+	if err := creds.SetExpiry(AWSSessionExpiry, []byte(`{`)); err == nil {
+		t.Error("SetExpiry() should fail for invalid output")
+	}
+
+	if err := creds.SetExpiry(AWSSessionExpiry, []byte(`{"Expiration": "2025-06-01T12:30:00Z"}`)); err != nil {
+		t.Fatalf("SetExpiry() error = %v", err)
+	}
+	if want := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC); !creds.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", creds.ExpiresAt, want)
+	}
+}
+
+// TestNextExpiry tests that the soonest expiry within the window is found.
+func TestNextExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	expiring := func(name string, in time.Duration) ServiceStatus {
+		return ServiceStatus{Name: name, Credentials: CredentialStatus{Valid: true, ExpiresAt: now.Add(in)}}
+	}
+
+	tests := []struct {
+		name     string
+		statuses []ServiceStatus
+		want     string
+	}{
+		{
+			name: "soonest of several",
+			statuses: []ServiceStatus{
+				expiring("gcp", 50*time.Minute),
+				expiring("aws", 12*time.Minute),
+				expiring("kubernetes", 3*time.Hour),
+			},
+			want: "aws",
+		},
+		{
+			name: "expired counts as soonest",
+			statuses: []ServiceStatus{
+				expiring("aws", 12*time.Minute),
+				expiring("azure", -5*time.Minute),
+			},
+			want: "azure",
+		},
+		{
+			name: "no expiry is ignored",
+			statuses: []ServiceStatus{
+				{Name: "docker", Credentials: CredentialStatus{Valid: true}},
+				expiring("gcp", 20*time.Hour),
+			},
+			want: "gcp",
+		},
+		{
+			name: "outside the window",
+			statuses: []ServiceStatus{
+				expiring("aws", 25*time.Hour),
+				{Name: "docker", Credentials: CredentialStatus{Valid: true}},
+			},
+		},
+		{
+			name: "no services",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NextExpiry(tt.statuses, now, DefaultExpiryWarningWindow)
+			if ok != (tt.want != "") || got.Name != tt.want {
+				t.Errorf("NextExpiry() = %q, %v, want %q", got.Name, ok, tt.want)
+			}
+		})
+	}
+}
+
+// TestExpirySummary tests the rendered summary line.
+func TestExpirySummary(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{12*time.Minute + 30*time.Second, "Next expiry: aws in 12m"},
+		{30 * time.Second, "Next expiry: aws in 30s"},
+		{5 * time.Hour, "Next expiry: aws in 5h"},
+		{-3 * time.Minute, "Next expiry: aws expired 3m ago"},
+	}
+
+	for _, tt := range tests {
+		st := ServiceStatus{Name: "aws", Credentials: CredentialStatus{ExpiresAt: now.Add(tt.in)}}
+		if got := ExpirySummary(st, now); got != tt.want {
+			t.Errorf("ExpirySummary(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+//go:build !unix
+
+package status
+
+import "os"
+
+// lockFile is a no-op where flock is unavailable; concurrent writers from
+// separate processes may then interleave rotation.
+func lockFile(*os.File) error {
+	return nil
+}
+
+// unlockFile is a no-op where flock is unavailable.
+func unlockFile(*os.File) error {
+	return nil
+}
@@ -4,10 +4,12 @@
 package status
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"gopkg.in/yaml.v3"
 )
@@ -18,6 +20,19 @@ const DefaultNamespace = "default"
 // StatusTableFormatter formats status as a table.
 type StatusTableFormatter struct {
 	UseColor bool
+	// Wide renders untruncated values plus account, region, credential type,
+	// and health columns.
+	Wide bool
+	// GroupByStatus sections the rows under a heading per status, in the
+	// order active, inactive, error, unknown. Empty sections are left out.
+	GroupByStatus bool
+	// GroupByCategory sections the rows under a heading per category, in
+	// the order of Categories, each with its count of active services. It
+	// takes precedence over GroupByStatus.
+	GroupByCategory bool
+
+	// now returns the time expiries are measured from, time.Now if nil.
+	now func() time.Time
 }
 
 // NewStatusTableFormatter creates a new table formatter.
@@ -25,72 +40,380 @@ func NewStatusTableFormatter(useColor bool) *StatusTableFormatter {
 	return &StatusTableFormatter{UseColor: useColor}
 }
 
+// NewStatusWideTableFormatter creates a table formatter with extra columns.
+func NewStatusWideTableFormatter(useColor bool) *StatusTableFormatter {
+	return &StatusTableFormatter{UseColor: useColor, Wide: true}
+}
+
+// tableStrings holds all user-facing text of the table formatter, so the
+// wording is consistent and can be translated in one place.
+var tableStrings = struct {
+	Title         string
+	Empty         string
+	Warning       string
+	WarningDetail string
+	AllGood       string
+	ActiveCount   string // format: active, total
+	ReasonCount   string // format: count, reason title
+	AsOf          string // format: time
+	GroupHeading  string // format: status, count
+	CategoryHead  string // format: category, active, total
+
+	Service, Status, Current, Account, Region string
+	Credentials, CredType, Health, LastUsed   string
+
+	Active, Inactive, Error, Unknown string
+
+	NotInstalled, NotConfigured, NotRunning, CredentialsInvalid string
+
+	CredInvalid, CredExpires, CredWarning, CredValid string
+
+	LastUsedUnknown string
+	Ago             string // format: duration
+	LessThanMinute  string
+	Minutes         string // format: count
+	Hours           string // format: count
+	Days            string // format: count
+}{
+	Title:         "Development Environment Status",
+	Empty:         "No services to display",
+	Warning:       "⚠️ Warning",
+	WarningDetail: " (Some services have issues)",
+	AllGood:       "✅ All Good",
+	ActiveCount:   "Active Environments: %d/%d",
+	ReasonCount:   "%d %s",
+	AsOf:          "As of %s",
+	GroupHeading:  "%s (%d)",
+	CategoryHead:  "%s — %d/%d active",
+
+	Service:     "Service",
+	Status:      "Status",
+	Current:     "Current",
+	Account:     "Account",
+	Region:      "Region",
+	Credentials: "Credentials",
+	CredType:    "Cred Type",
+	Health:      "Health",
+	LastUsed:    "Last Used",
+
+	Active:   "✅ Active",
+	Inactive: "❌ Inactive",
+	Error:    "⚠️ Error",
+	Unknown:  "❓ Unknown",
+
+	NotInstalled:       "📦 Missing",
+	NotConfigured:      "⚙️ Unset",
+	NotRunning:         "💤 Stopped",
+	CredentialsInvalid: "🔑 Bad auth",
+
+	CredInvalid: "❌ Invalid",
+	CredExpires: "⚠️ Expires",
+	CredWarning: "⚠️ Warning",
+	CredValid:   "✅ Valid",
+
+	LastUsedUnknown: "Unknown",
+	Ago:             "%s ago",
+	LessThanMinute:  "< 1 min",
+	Minutes:         "%d min",
+	Hours:           "%d hour",
+	Days:            "%d days",
+}
+
+// tableColumns returns the column headers of the default table.
+func tableColumns() []string {
+	return []string{tableStrings.Service, tableStrings.Status, tableStrings.Current, tableStrings.Credentials, tableStrings.LastUsed}
+}
+
+// wideColumns returns the column headers of the wide table.
+func wideColumns() []string {
+	return []string{
+		tableStrings.Service, tableStrings.Status, tableStrings.Current, tableStrings.Account, tableStrings.Region,
+		tableStrings.Credentials, tableStrings.CredType, tableStrings.Health, tableStrings.LastUsed,
+	}
+}
+
 // Format formats the status as a table.
 func (t *StatusTableFormatter) Format(statuses []ServiceStatus) (string, error) {
 	if len(statuses) == 0 {
-		return "No services to display", nil
+		return tableStrings.Empty, nil
 	}
 
 	var sb strings.Builder
 
 	// Header
-	sb.WriteString("Development Environment Status\n")
+	sb.WriteString(tableStrings.Title + "\n")
 	sb.WriteString("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n\n")
 
-	// Table header
-	sb.WriteString("Service    │ Status      │ Current              │ Credentials    │ Last Used\n")
-	sb.WriteString("───────────┼─────────────┼──────────────────────┼────────────────┼───────────\n")
+	switch {
+	case t.GroupByCategory:
+		t.writeSections(&sb, t.categorySections(statuses))
+	case t.GroupByStatus:
+		t.writeSections(&sb, t.statusSections(statuses))
+	default:
+		t.writeRows(&sb, statuses)
+	}
 
-	activeCount := 0
+	summary := Summarize(statuses)
 	hasWarnings := false
+	for _, status := range statuses {
+		if status.Credentials.Warning != "" || status.Status == StatusError {
+			hasWarnings = true
+		}
+	}
+
+	// Summary
+	sb.WriteString("\n")
+	if hasWarnings {
+		sb.WriteString(t.colorize(tableStrings.Warning, "yellow"))
+		sb.WriteString(tableStrings.WarningDetail + "\n")
+	} else {
+		sb.WriteString(t.colorize(tableStrings.AllGood, "green"))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(fmt.Sprintf(tableStrings.ActiveCount+"\n", summary.Active, summary.Total))
+	if counts := t.formatReasonCounts(summary); counts != "" {
+		sb.WriteString(counts + "\n")
+	}
+	now := time.Now()
+	if t.now != nil {
+		now = t.now()
+	}
+	if st, ok := NextExpiry(statuses, now, DefaultExpiryWarningWindow); ok {
+		sb.WriteString(t.colorize(ExpirySummary(st, now), "yellow") + "\n")
+	}
+	if asOf := AsOf(statuses); !asOf.IsZero() {
+		sb.WriteString(fmt.Sprintf(tableStrings.AsOf+"\n", asOf.Format("15:04:05")))
+	}
+
+	return sb.String(), nil
+}
+
+// writeRows writes statuses as the default or wide table.
+func (t *StatusTableFormatter) writeRows(sb *strings.Builder, statuses []ServiceStatus) {
+	if t.Wide {
+		t.writeWideTable(sb, statuses)
+	} else {
+		t.writeTable(sb, statuses)
+	}
+}
+
+// tableSection is a group of rows under a heading.
+type tableSection struct {
+	heading  string
+	statuses []ServiceStatus
+}
+
+// writeSections writes a table per non-empty section under its heading.
+func (t *StatusTableFormatter) writeSections(sb *strings.Builder, sections []tableSection) {
+	first := true
+	for _, section := range sections {
+		if len(section.statuses) == 0 {
+			continue
+		}
+		if !first {
+			sb.WriteString("\n")
+		}
+		first = false
+
+		sb.WriteString(section.heading + "\n")
+		t.writeRows(sb, section.statuses)
+	}
+}
+
+// statusGroups is the order of the sections written by GroupByStatus.
+var statusGroups = []StatusType{StatusActive, StatusInactive, StatusError, StatusUnknown}
+
+// statusSections groups statuses by status, with a count per heading.
+// Statuses of an unrecognized type are grouped with unknown.
+func (t *StatusTableFormatter) statusSections(statuses []ServiceStatus) []tableSection {
+	groups := make(map[StatusType][]ServiceStatus, len(statusGroups))
+	for _, status := range statuses {
+		group := status.Status
+		switch group {
+		case StatusActive, StatusInactive, StatusError:
+		default:
+			group = StatusUnknown
+		}
+		groups[group] = append(groups[group], status)
+	}
+
+	sections := make([]tableSection, 0, len(statusGroups))
+	for _, group := range statusGroups {
+		label, color := statusLabel(group)
+		sections = append(sections, tableSection{
+			heading:  t.colorize(fmt.Sprintf(tableStrings.GroupHeading, label, len(groups[group])), color),
+			statuses: groups[group],
+		})
+	}
+	return sections
+}
+
+// categorySections groups statuses by category, with each category's
+// active count in its heading. Statuses without a category are custom.
+func (t *StatusTableFormatter) categorySections(statuses []ServiceStatus) []tableSection {
+	groups := make(map[Category][]ServiceStatus, len(Categories))
+	for _, status := range statuses {
+		category := status.Category
+		if _, err := ParseCategory(string(category)); err != nil {
+			category = CategoryCustom
+		}
+		groups[category] = append(groups[category], status)
+	}
+
+	sections := make([]tableSection, 0, len(Categories))
+	for _, category := range Categories {
+		active := 0
+		for _, status := range groups[category] {
+			if status.Status == StatusActive {
+				active++
+			}
+		}
+		sections = append(sections, tableSection{
+			heading:  fmt.Sprintf(tableStrings.CategoryHead, CategoryTitle(category), active, len(groups[category])),
+			statuses: groups[category],
+		})
+	}
+	return sections
+}
+
+// writeTable writes the default fixed-width table.
+func (t *StatusTableFormatter) writeTable(sb *strings.Builder, statuses []ServiceStatus) {
+	// Table header
+	columns := tableColumns()
+	sb.WriteString(fmt.Sprintf("%-10s │ %-11s │ %-20s │ %-14s │ %s\n", columns[0], columns[1], columns[2], columns[3], columns[4]))
+	sb.WriteString("───────────┼─────────────┼──────────────────────┼────────────────┼───────────\n")
 
 	// Table rows
 	for _, status := range statuses {
 		serviceName := fmt.Sprintf("%-10s", status.Name)
-		statusStr := t.formatStatus(status.Status)
+		statusStr := t.formatServiceStatus(status)
 		currentStr := t.formatCurrent(status.Current)
 		credStr := t.formatCredentials(status.Credentials)
 		lastUsedStr := t.formatLastUsed(status.LastUsed)
 
-		if status.Status == StatusActive {
-			activeCount++
-		}
-		if status.Credentials.Warning != "" || status.Status == StatusError {
-			hasWarnings = true
-		}
-
 		sb.WriteString(fmt.Sprintf("%s │ %s │ %-20s │ %-14s │ %s\n",
 			serviceName, statusStr, currentStr, credStr, lastUsedStr))
 	}
+}
 
-	// Summary
-	sb.WriteString("\n")
-	if hasWarnings {
-		sb.WriteString(t.colorize("⚠️ Warning", "yellow"))
-		sb.WriteString(" (Some services have issues)\n")
-	} else {
-		sb.WriteString(t.colorize("✅ All Good", "green"))
+// writeWideTable writes the wide table, sizing each column to its content.
+func (t *StatusTableFormatter) writeWideTable(sb *strings.Builder, statuses []ServiceStatus) {
+	rows := make([][]string, 0, len(statuses))
+	for _, status := range statuses {
+		rows = append(rows, []string{
+			status.Name,
+			t.formatServiceStatus(status),
+			orDash(t.formatCurrentWide(status.Current)),
+			orDash(AccountLabel(status)),
+			orDash(status.Current.Region),
+			t.formatCredentials(status.Credentials),
+			orDash(status.Credentials.Type),
+			t.formatHealth(status.HealthCheck),
+			t.formatLastUsed(status.LastUsed),
+		})
+	}
+
+	columns := wideColumns()
+	widths := make([]int, len(columns))
+	for i, header := range columns {
+		widths[i] = visibleWidth(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if w := visibleWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	writeRow := func(cells []string) {
+		for i, cell := range cells {
+			if i > 0 {
+				sb.WriteString(" │ ")
+			}
+			sb.WriteString(cell)
+			if i < len(cells)-1 {
+				sb.WriteString(strings.Repeat(" ", widths[i]-visibleWidth(cell)))
+			}
+		}
 		sb.WriteString("\n")
 	}
 
-	sb.WriteString(fmt.Sprintf("Active Environments: %d/%d\n", activeCount, len(statuses)))
+	writeRow(columns)
+	for i, w := range widths {
+		if i > 0 {
+			sb.WriteString("┼")
+		}
+		pad := w + 2
+		if i == 0 || i == len(widths)-1 {
+			pad = w + 1
+		}
+		sb.WriteString(strings.Repeat("─", pad))
+	}
+	sb.WriteString("\n")
+	for _, row := range rows {
+		writeRow(row)
+	}
+}
+
+// formatServiceStatus formats a service's status with colors, showing why
+// it is not active when the checker set a Reason.
+func (t *StatusTableFormatter) formatServiceStatus(st ServiceStatus) string {
+	label, color, ok := reasonLabel(st.Reason)
+	if !ok {
+		return t.formatStatus(st.Status)
+	}
+	return t.colorize(padLabel(label, 10), color)
+}
 
-	return sb.String(), nil
+// reasonLabel returns the display label and color of a reason. ok is false
+// for no reason or one this version does not know.
+func reasonLabel(reason Reason) (label, color string, ok bool) {
+	switch reason {
+	case ReasonNotInstalled:
+		return tableStrings.NotInstalled, "gray", true
+	case ReasonNotConfigured:
+		return tableStrings.NotConfigured, "blue", true
+	case ReasonNotRunning:
+		return tableStrings.NotRunning, "magenta", true
+	case ReasonCredentialsInvalid:
+		return tableStrings.CredentialsInvalid, "red", true
+	default:
+		return "", "", false
+	}
+}
+
+// formatReasonCounts lists how many services are not active for each
+// reason, such as "2 not installed, 1 credentials invalid".
+func (t *StatusTableFormatter) formatReasonCounts(summary Summary) string {
+	var parts []string
+	for _, reason := range Reasons {
+		if n := summary.Reasons[reason]; n > 0 {
+			_, color, _ := reasonLabel(reason)
+			parts = append(parts, t.colorize(fmt.Sprintf(tableStrings.ReasonCount, n, ReasonTitle(reason)), color))
+		}
+	}
+	return strings.Join(parts, ", ")
 }
 
 // formatStatus formats the service status with colors.
 func (t *StatusTableFormatter) formatStatus(status StatusType) string {
+	label, color := statusLabel(status)
+	return t.colorize(padLabel(label, 10), color)
+}
+
+// statusLabel returns the display label and color of a status.
+func statusLabel(status StatusType) (label, color string) {
 	switch status {
 	case StatusActive:
-		return t.colorize("✅ Active  ", "green")
+		return tableStrings.Active, "green"
 	case StatusInactive:
-		return t.colorize("❌ Inactive", "red")
+		return tableStrings.Inactive, "red"
 	case StatusError:
-		return t.colorize("⚠️ Error   ", "yellow")
-	case StatusUnknown:
-		return t.colorize("❓ Unknown ", "gray")
+		return tableStrings.Error, "yellow"
 	default:
-		return t.colorize("❓ Unknown ", "gray")
+		return tableStrings.Unknown, "gray"
 	}
 }
 
@@ -126,17 +449,86 @@ func (t *StatusTableFormatter) formatCurrent(current CurrentConfig) string {
 	return result
 }
 
+// formatCurrentWide formats the current configuration without truncation.
+// Region and account have their own columns in the wide table.
+func (t *StatusTableFormatter) formatCurrentWide(current CurrentConfig) string {
+	parts := []string{}
+
+	if current.Profile != "" {
+		parts = append(parts, current.Profile)
+	}
+	if current.Project != "" {
+		parts = append(parts, current.Project)
+	}
+	if current.Context != "" {
+		parts = append(parts, current.Context)
+	}
+	if current.Namespace != "" && current.Namespace != DefaultNamespace {
+		parts = append(parts, fmt.Sprintf("/%s", current.Namespace))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// formatHealth formats the health check message for the wide table.
+func (t *StatusTableFormatter) formatHealth(health *HealthStatus) string {
+	if health == nil {
+		return "-"
+	}
+	if health.Message != "" {
+		return health.Message
+	}
+	return string(health.Status)
+}
+
+// padLabel pads a status label to width runes. Emoji variation selectors
+// are not counted, so labels with and without them line up.
+func padLabel(label string, width int) string {
+	n := utf8.RuneCountInString(label) - strings.Count(label, "\uFE0F")
+	if n >= width {
+		return label
+	}
+	return label + strings.Repeat(" ", width-n)
+}
+
+// orDash returns "-" for empty values.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// visibleWidth returns the number of runes in s, ignoring ANSI color codes.
+func visibleWidth(s string) int {
+	width := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		case r == '\033':
+			inEscape = true
+		default:
+			width++
+		}
+	}
+	return width
+}
+
 // formatCredentials formats the credential status.
 func (t *StatusTableFormatter) formatCredentials(creds CredentialStatus) string {
 	if !creds.Valid {
-		return t.colorize("❌ Invalid", "red")
+		return t.colorize(tableStrings.CredInvalid, "red")
 	}
 
 	if creds.Warning != "" {
 		if strings.Contains(creds.Warning, "expire") {
-			return t.colorize("⚠️ Expires", "yellow")
+			return t.colorize(tableStrings.CredExpires, "yellow")
 		}
-		return t.colorize("⚠️ Warning", "yellow")
+		return t.colorize(tableStrings.CredWarning, "yellow")
 	}
 
 	if !creds.ExpiresAt.IsZero() {
@@ -147,31 +539,31 @@ func (t *StatusTableFormatter) formatCredentials(creds CredentialStatus) string
 		return t.colorize(fmt.Sprintf("✅ %s", t.formatDuration(timeUntilExpiry)), "green")
 	}
 
-	return t.colorize("✅ Valid", "green")
+	return t.colorize(tableStrings.CredValid, "green")
 }
 
 // formatLastUsed formats the last used time.
 func (t *StatusTableFormatter) formatLastUsed(lastUsed time.Time) string {
 	if lastUsed.IsZero() {
-		return "Unknown"
+		return tableStrings.LastUsedUnknown
 	}
 
 	duration := time.Since(lastUsed)
-	return t.formatDuration(duration) + " ago"
+	return fmt.Sprintf(tableStrings.Ago, t.formatDuration(duration))
 }
 
 // formatDuration formats duration in a human-readable way.
 func (t *StatusTableFormatter) formatDuration(d time.Duration) string {
 	if d < time.Minute {
-		return "< 1 min"
+		return tableStrings.LessThanMinute
 	}
 	if d < time.Hour {
-		return fmt.Sprintf("%d min", int(d.Minutes()))
+		return fmt.Sprintf(tableStrings.Minutes, int(d.Minutes()))
 	}
 	if d < 24*time.Hour {
-		return fmt.Sprintf("%d hour", int(d.Hours()))
+		return fmt.Sprintf(tableStrings.Hours, int(d.Hours()))
 	}
-	return fmt.Sprintf("%d days", int(d.Hours()/24))
+	return fmt.Sprintf(tableStrings.Days, int(d.Hours()/24))
 }
 
 // colorize adds color to text if colors are enabled.
@@ -181,11 +573,13 @@ func (t *StatusTableFormatter) colorize(text, color string) string {
 	}
 
 	colors := map[string]string{
-		"red":    "\033[31m",
-		"green":  "\033[32m",
-		"yellow": "\033[33m",
-		"gray":   "\033[37m",
-		"reset":  "\033[0m",
+		"red":     "\033[31m",
+		"green":   "\033[32m",
+		"yellow":  "\033[33m",
+		"gray":    "\033[37m",
+		"blue":    "\033[34m",
+		"magenta": "\033[35m",
+		"reset":   "\033[0m",
 	}
 
 	if colorCode, exists := colors[color]; exists {
@@ -214,16 +608,129 @@ func (j *StatusJSONFormatter) Format(statuses []ServiceStatus) (string, error) {
 	return string(bytes), err
 }
 
+// DefaultYAMLIndent is the indentation StatusYAMLFormatter uses unless
+// YAMLOptions.Indent sets another.
+const DefaultYAMLIndent = 4
+
+// YAMLServicesKey is the top-level key the services are listed under when
+// YAMLOptions.Wrap is set.
+const YAMLServicesKey = "services"
+
+// YAMLOptions controls the layout of StatusYAMLFormatter output. The zero
+// value is the default: a bare list of services indented by
+// DefaultYAMLIndent spaces, without a document separator.
+type YAMLOptions struct {
+	// Indent is the number of spaces per nesting level, or
+	// DefaultYAMLIndent if zero.
+	Indent int
+	// DocumentStart begins the output with a "---" separator, so it can be
+	// concatenated with other YAML documents.
+	DocumentStart bool
+	// Wrap lists the services under a top-level "services:" key instead
+	// of as a bare list, for consumers that need a named root.
+	Wrap bool
+}
+
 // StatusYAMLFormatter formats status as YAML.
-type StatusYAMLFormatter struct{}
+type StatusYAMLFormatter struct {
+	// Version selects the field names; the zero value is OutputVersionStable.
+	Version OutputVersion
+	YAMLOptions
+}
 
 // NewStatusYAMLFormatter creates a new YAML formatter.
 func NewStatusYAMLFormatter() *StatusYAMLFormatter {
 	return &StatusYAMLFormatter{}
 }
 
+// NewStatusYAMLFormatterWithOptions creates a YAML formatter with the
+// given layout.
+func NewStatusYAMLFormatterWithOptions(options YAMLOptions) *StatusYAMLFormatter {
+	return &StatusYAMLFormatter{YAMLOptions: options}
+}
+
 // Format formats the status as YAML.
 func (y *StatusYAMLFormatter) Format(statuses []ServiceStatus) (string, error) {
-	bytes, err := yaml.Marshal(statuses)
-	return string(bytes), err
+	var value interface{} = statuses
+	if y.Version == OutputVersionLegacy {
+		value = toLegacy(statuses)
+	}
+	if y.Wrap {
+		value = map[string]interface{}{YAMLServicesKey: value}
+	}
+
+	indent := y.Indent
+	if indent <= 0 {
+		indent = DefaultYAMLIndent
+	}
+
+	var buf bytes.Buffer
+	if y.DocumentStart {
+		buf.WriteString("---\n")
+	}
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(value); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// EnvVarPrefix starts every variable printed by StatusEnvFormatter.
+const EnvVarPrefix = "DEVENV_"
+
+// StatusEnvFormatter formats status as shell variable assignments, one
+// DEVENV_<SERVICE>_STATUS=<status> line per service, for scripts to source.
+type StatusEnvFormatter struct{}
+
+// NewStatusEnvFormatter creates a new env formatter.
+func NewStatusEnvFormatter() *StatusEnvFormatter {
+	return &StatusEnvFormatter{}
+}
+
+// Format formats the status as shell variable assignments.
+func (e *StatusEnvFormatter) Format(statuses []ServiceStatus) (string, error) {
+	var sb strings.Builder
+	for _, st := range statuses {
+		fmt.Fprintf(&sb, "%s=%s\n", EnvVarName(st.Name, "STATUS"), shellQuote(string(st.Status)))
+	}
+	return sb.String(), nil
+}
+
+// EnvVarName returns the variable StatusEnvFormatter uses for a service's
+// field: the service name is uppercased and every character other than a
+// letter or digit becomes an underscore, so "ssh-jump-db1" and "status"
+// give DEVENV_SSH_JUMP_DB1_STATUS.
+func EnvVarName(service, field string) string {
+	var sb strings.Builder
+	sb.WriteString(EnvVarPrefix)
+	for _, r := range strings.ToUpper(service) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte('_')
+		}
+	}
+	sb.WriteByte('_')
+	sb.WriteString(field)
+	return sb.String()
+}
+
+// shellQuote single-quotes value unless it is made only of characters that
+// need no quoting in a POSIX shell.
+func shellQuote(value string) string {
+	safe := value != ""
+	for _, r := range value {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:/@", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
 }
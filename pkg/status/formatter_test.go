@@ -62,6 +62,55 @@ func TestStatusTableFormatter_Format(t *testing.T) {
 	}
 }
 
+// TestStatusTableFormatter_FormatAsOf tests the "as of" footer.
+func TestStatusTableFormatter_FormatAsOf(t *testing.T) {
+	formatter := NewStatusTableFormatter(false)
+	checkedAt := time.Date(2025, 1, 1, 9, 30, 15, 0, time.Local)
+
+	output, err := formatter.Format([]ServiceStatus{
+		{Name: "aws", Status: StatusActive, CheckedAt: checkedAt},
+		{Name: "gcp", Status: StatusActive, CheckedAt: checkedAt.Add(time.Minute)},
+	})
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(output, "As of 09:30:15") {
+		t.Errorf("Output should contain the oldest check time, got:\n%s", output)
+	}
+
+	output, _ = formatter.Format([]ServiceStatus{{Name: "aws", Status: StatusActive}})
+	if strings.Contains(output, "As of") {
+		t.Error("Output should omit the footer when no check time is set")
+	}
+}
+
+// TestStatusTableFormatter_FormatNextExpiry tests the footer line naming
+// the soonest credential expiry.
+func TestStatusTableFormatter_FormatNextExpiry(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	formatter := NewStatusTableFormatter(false)
+	formatter.now = func() time.Time { return now }
+
+	output, err := formatter.Format([]ServiceStatus{
+		{Name: "gcp", Status: StatusActive, Credentials: CredentialStatus{Valid: true, ExpiresAt: now.Add(3 * time.Hour)}},
+		{Name: "aws", Status: StatusActive, Credentials: CredentialStatus{Valid: true, ExpiresAt: now.Add(12 * time.Minute)}},
+		{Name: "docker", Status: StatusActive, Credentials: CredentialStatus{Valid: true}},
+	})
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+	if !strings.Contains(output, "Next expiry: aws in 12m\n") {
+		t.Errorf("Output should name the soonest expiry, got:\n%s", output)
+	}
+
+	output, _ = formatter.Format([]ServiceStatus{
+		{Name: "gcp", Status: StatusActive, Credentials: CredentialStatus{Valid: true, ExpiresAt: now.Add(48 * time.Hour)}},
+	})
+	if strings.Contains(output, "Next expiry") {
+		t.Error("Output should omit the line when nothing expires within the window")
+	}
+}
+
 func TestStatusTableFormatter_FormatEmpty(t *testing.T) {
 	formatter := NewStatusTableFormatter(false)
 
@@ -172,6 +221,62 @@ func TestStatusYAMLFormatter_Format(t *testing.T) {
 	}
 }
 
+// TestStatusYAMLFormatter_Options tests that the default output is
+// unchanged and that the wrapped and unwrapped forms both parse.
+func TestStatusYAMLFormatter_Options(t *testing.T) {
+	statuses := []ServiceStatus{
+		{Name: "kubernetes", Status: StatusActive, Current: CurrentConfig{Context: "minikube", Namespace: "default"}},
+		{Name: "aws", Status: StatusInactive, Reason: ReasonNotConfigured},
+	}
+
+	plain, err := yaml.Marshal(statuses)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		options YAMLOptions
+		prefix  string
+		nested  string
+	}{
+		{"default", YAMLOptions{}, "- ", "\n    context: minikube\n"},
+		{"two-space indent", YAMLOptions{Indent: 2}, "- ", "\n    context: minikube\n"},
+		{"document start", YAMLOptions{DocumentStart: true}, "---\n- ", "\n    context: minikube\n"},
+		{"wrapped", YAMLOptions{Wrap: true}, "services:\n    - ", "\n        context: minikube\n"},
+		{"wrapped with two-space indent", YAMLOptions{Wrap: true, Indent: 2, DocumentStart: true}, "---\nservices:\n  - ", "\n      context: minikube\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := NewStatusYAMLFormatterWithOptions(tt.options).Format(statuses)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			if !strings.HasPrefix(output, tt.prefix) || !strings.Contains(output, tt.nested) {
+				t.Errorf("Format() = %q, want it to start with %q and contain %q", output, tt.prefix, tt.nested)
+			}
+			if tt.options == (YAMLOptions{}) && output != string(plain) {
+				t.Errorf("Format() with default options = %q, want %q", output, plain)
+			}
+
+			var parsed []ServiceStatus
+			if tt.options.Wrap {
+				var root map[string][]ServiceStatus
+				if err := yaml.Unmarshal([]byte(output), &root); err != nil {
+					t.Fatalf("wrapped output is not valid YAML: %v", err)
+				}
+				parsed = root[YAMLServicesKey]
+			} else if err := yaml.Unmarshal([]byte(output), &parsed); err != nil {
+				t.Fatalf("output is not valid YAML: %v", err)
+			}
+			if len(parsed) != 2 || parsed[0].Current.Context != "minikube" || parsed[1].Reason != ReasonNotConfigured {
+				t.Errorf("parsed statuses = %+v, want both services back", parsed)
+			}
+		})
+	}
+}
+
 func TestStatusTableFormatter_FormatDuration(t *testing.T) {
 	formatter := &StatusTableFormatter{UseColor: false}
 
@@ -405,3 +510,230 @@ func TestStatusTableFormatter_FormatWithColor(t *testing.T) {
 		t.Error("Output with UseColor should contain ANSI escape codes")
 	}
 }
+
+// TestStatusTableFormatter_FormatWide tests the wide table columns.
+func TestStatusTableFormatter_FormatWide(t *testing.T) {
+	formatter := NewStatusWideTableFormatter(false)
+	if !formatter.Wide {
+		t.Fatal("Wide should be true")
+	}
+
+	statuses := []ServiceStatus{
+		{
+			Name:   "gcp",
+			Status: StatusActive,
+			Current: CurrentConfig{
+				Project: "a-very-long-project-name-that-would-be-truncated",
+				Account: "dev@example.com",
+				Region:  "asia-northeast3",
+			},
+			Credentials: CredentialStatus{Valid: true, Type: "user-account"},
+			HealthCheck: &HealthStatus{Status: StatusActive, Message: "project reachable"},
+		},
+		{
+			Name:        "docker",
+			Status:      StatusActive,
+			Credentials: CredentialStatus{Valid: true, Type: "docker-socket"},
+		},
+	}
+
+	output, err := formatter.Format(statuses)
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	for _, want := range []string{
+		"Account", "Region", "Cred Type", "Health",
+		"dev@example.com", "asia-northeast3", "user-account", "docker-socket",
+		"project reachable", "a-very-long-project-name-that-would-be-truncated",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("wide output should contain %q\n%s", want, output)
+		}
+	}
+}
+
+// TestStatusTableFormatter_FormatWideAligned tests that wide rows share column positions.
+func TestStatusTableFormatter_FormatWideAligned(t *testing.T) {
+	formatter := NewStatusWideTableFormatter(true)
+
+	output, err := formatter.Format([]ServiceStatus{
+		{Name: "aws", Status: StatusActive, Current: CurrentConfig{Profile: "prod"}, Credentials: CredentialStatus{Valid: true, Type: "aws-credentials"}},
+		{Name: "kubernetes", Status: StatusError, Credentials: CredentialStatus{Valid: false, Type: "kubeconfig"}},
+	})
+	if err != nil {
+		t.Fatalf("Format failed: %v", err)
+	}
+
+	lines := strings.Split(output, "\n")
+	var tableLines []string
+	for _, line := range lines {
+		if strings.Contains(line, "│") {
+			tableLines = append(tableLines, line)
+		}
+	}
+	if len(tableLines) != 3 {
+		t.Fatalf("got %d table lines, want 3\n%s", len(tableLines), output)
+	}
+
+	// The third separator sits at the same visible offset on every line.
+	want := -1
+	for _, line := range tableLines {
+		parts := strings.SplitN(line, "│", 4)
+		offset := visibleWidth(strings.Join(parts[:3], "│"))
+		if want == -1 {
+			want = offset
+		} else if offset != want {
+			t.Errorf("column offset = %d, want %d in line %q", offset, want, line)
+		}
+	}
+}
+
+// TestVisibleWidth tests width calculation ignoring color codes.
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"\033[32mabc\033[0m", 3},
+		{"─┼─", 3},
+	}
+
+	for _, tt := range tests {
+		if got := visibleWidth(tt.input); got != tt.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestStatusTableFormatter_FormatGroupByStatus tests that services appear under their status heading.
+func TestStatusTableFormatter_FormatGroupByStatus(t *testing.T) {
+	statuses := []ServiceStatus{
+		{Name: "aws", Status: StatusActive},
+		{Name: "gcp", Status: StatusError},
+		{Name: "azure", Status: StatusInactive},
+		{Name: "docker", Status: StatusActive},
+		{Name: "ssh", Status: StatusType("mystery")},
+	}
+
+	for _, wide := range []bool{false, true} {
+		formatter := &StatusTableFormatter{Wide: wide, GroupByStatus: true}
+		output, err := formatter.Format(statuses)
+		if err != nil {
+			t.Fatalf("Format() error = %v", err)
+		}
+
+		// section returns the lines from a heading to the next blank line.
+		section := func(heading string) string {
+			start := strings.Index(output, heading+"\n")
+			if start < 0 {
+				t.Fatalf("wide=%v: output has no %q heading:\n%s", wide, heading, output)
+			}
+			rest := output[start:]
+			if end := strings.Index(rest, "\n\n"); end >= 0 {
+				rest = rest[:end]
+			}
+			return rest
+		}
+
+		tests := []struct {
+			heading string
+			want    []string
+			notWant []string
+		}{
+			{"✅ Active (2)", []string{"aws", "docker"}, []string{"gcp", "azure", "ssh"}},
+			{"❌ Inactive (1)", []string{"azure"}, []string{"aws", "gcp"}},
+			{"⚠️ Error (1)", []string{"gcp"}, []string{"aws", "azure"}},
+			{"❓ Unknown (1)", []string{"ssh"}, []string{"aws", "gcp"}},
+		}
+		for _, tt := range tests {
+			got := section(tt.heading)
+			for _, name := range tt.want {
+				if !strings.Contains(got, name) {
+					t.Errorf("wide=%v: %q section should list %s:\n%s", wide, tt.heading, name, got)
+				}
+			}
+			for _, name := range tt.notWant {
+				if strings.Contains(got, name) {
+					t.Errorf("wide=%v: %q section should not list %s:\n%s", wide, tt.heading, name, got)
+				}
+			}
+		}
+
+		if strings.Index(output, "Active (2)") > strings.Index(output, "Inactive (1)") ||
+			strings.Index(output, "Inactive (1)") > strings.Index(output, "Error (1)") {
+			t.Errorf("wide=%v: groups out of order:\n%s", wide, output)
+		}
+		if !strings.Contains(output, "Active Environments: 2/5") {
+			t.Errorf("wide=%v: output should keep the summary footer:\n%s", wide, output)
+		}
+	}
+}
+
+// TestStatusEnvFormatter_Format tests the variable names and values printed
+// for a set of statuses.
+func TestStatusEnvFormatter_Format(t *testing.T) {
+	statuses := []ServiceStatus{
+		{Name: "aws", Status: StatusActive},
+		{Name: "kubernetes", Status: StatusInactive},
+		{Name: "gcp", Status: StatusError},
+		{Name: "ssh-jump-db1.internal", Status: StatusUnknown},
+		{Name: "vault", Status: ""},
+	}
+
+	output, err := NewStatusEnvFormatter().Format(statuses)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	want := "DEVENV_AWS_STATUS=active\n" +
+		"DEVENV_KUBERNETES_STATUS=inactive\n" +
+		"DEVENV_GCP_STATUS=error\n" +
+		"DEVENV_SSH_JUMP_DB1_INTERNAL_STATUS=unknown\n" +
+		"DEVENV_VAULT_STATUS=''\n"
+	if output != want {
+		t.Errorf("Format() =\n%s\nwant\n%s", output, want)
+	}
+}
+
+// TestEnvVarName tests that service names become valid variable names.
+func TestEnvVarName(t *testing.T) {
+	tests := []struct {
+		service  string
+		expected string
+	}{
+		{"aws", "DEVENV_AWS_STATUS"},
+		{"ssh-jump-bastion", "DEVENV_SSH_JUMP_BASTION_STATUS"},
+		{"my.service v2", "DEVENV_MY_SERVICE_V2_STATUS"},
+		{"1password", "DEVENV_1PASSWORD_STATUS"},
+		{"café", "DEVENV_CAF__STATUS"},
+	}
+
+	for _, tt := range tests {
+		if got := EnvVarName(tt.service, "STATUS"); got != tt.expected {
+			t.Errorf("EnvVarName(%q) = %q, want %q", tt.service, got, tt.expected)
+		}
+	}
+}
+
+// TestShellQuote tests quoting of values that are unsafe in a shell.
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"active", "active"},
+		{"", "''"},
+		{"a b", "'a b'"},
+		{"it's", `'it'\''s'`},
+		{"$(rm -rf /)", "'$(rm -rf /)'"},
+	}
+
+	for _, tt := range tests {
+		if got := shellQuote(tt.value); got != tt.expected {
+			t.Errorf("shellQuote(%q) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
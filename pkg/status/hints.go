@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// remediationHints maps well-known CLI error messages to the action that
+// usually fixes them. Both failed switches and status checks are matched
+// against it. Entries are tried in order; the first match wins, so
+// service-specific entries come before the generic ones.
+var remediationHints = []struct {
+	service string // empty matches any service
+	pattern *regexp.Regexp
+	hint    string
+}{
+	{"aws", regexp.MustCompile(`(?i)expiredtoken|token has expired|sso session.*(expired|invalid)|error loading sso token`),
+		"Refresh the credentials with `aws sso login --profile <profile>`."},
+	{"aws", regexp.MustCompile(`(?i)profile .*could not be found|profile .*not found`),
+		"Check the profile name against `aws configure list-profiles`."},
+	{"gcp", regexp.MustCompile(`(?i)reauthentication|gcloud auth login|invalid_grant`),
+		"Refresh the credentials with `gcloud auth login`."},
+	{"gcp", regexp.MustCompile(`(?i)permission_denied|does not have permission`),
+		"Check the account can access the project, or pick another with `gcloud auth list`."},
+	{"azure", regexp.MustCompile(`(?i)az login|aadsts|refresh token has expired`),
+		"Refresh the credentials with `az login`."},
+	{"docker", regexp.MustCompile(`(?i)cannot connect to the docker daemon|is the docker daemon running`),
+		"Start Docker, e.g. Docker Desktop or `sudo systemctl start docker`."},
+	{"docker", regexp.MustCompile(`(?i)context .*(not found|does not exist)`),
+		"Check the context name against `docker context ls`."},
+	{"kubernetes", regexp.MustCompile(`(?i)no context exists|context .*(not found|does not exist)`),
+		"Check the context name against `kubectl config get-contexts`."},
+	{"kubernetes", regexp.MustCompile(`(?i)unable to connect to the server|connection refused|i/o timeout`),
+		"Check the cluster is running and reachable, e.g. over VPN, with `kubectl cluster-info`."},
+	{"kubernetes", regexp.MustCompile(`(?i)unauthorized|must be logged in|token .*expired`),
+		"Refresh the cluster credentials, e.g. with your cloud CLI's get-credentials command."},
+	{"ssh", regexp.MustCompile(`(?i)could not open a connection to your authentication agent|agent refused`),
+		"Start an agent with `eval $(ssh-agent)` and add keys with `ssh-add`."},
+	{"gpg", regexp.MustCompile(`(?i)no secret key|secret key not available|not in the GPG keyring`),
+		"Check the key ID against `gpg --list-secret-keys`, or import the key with `gpg --import`."},
+	{"pkgmgr", regexp.MustCompile(`(?i)does not override conflicting OS environment variable`),
+		"Unset the variable in your shell; `go env -w` settings apply only where it is not set."},
+	{"", regexp.MustCompile(`(?i)executable file not found`),
+		"Install the service's CLI or add it to PATH; `dev-env init` shows which CLIs are found."},
+	{"", regexp.MustCompile(`(?i)context deadline exceeded`),
+		"The switch timed out; raise the environment's timeout or check the CLI is not waiting for input."},
+}
+
+// reasonHints are the fallback fixes for statuses whose messages match no
+// known problem.
+var reasonHints = map[Reason]string{
+	ReasonNotInstalled:       "Install the service's CLI or add it to PATH; `dev-env init` shows which CLIs are found.",
+	ReasonNotConfigured:      "Select a profile, project, or context with the service's CLI, or switch to an environment with `dev-env switch-all`.",
+	ReasonNotRunning:         "Start the daemon or agent the service needs, e.g. Docker or ssh-agent.",
+	ReasonCredentialsInvalid: "Log in again with the service's CLI, e.g. `aws sso login` or `gcloud auth login`.",
+}
+
+// RemediationHint returns the suggested fix for a service failure whose
+// error or CLI output is message, or "" if no known problem matches.
+func RemediationHint(service, message string) string {
+	for _, h := range remediationHints {
+		if (h.service == "" || h.service == service) && h.pattern.MatchString(message) {
+			return h.hint
+		}
+	}
+	return ""
+}
+
+// StatusHint returns the suggested fix for a problem a check found: the hint
+// for the known problem its details and messages match, or else the hint for
+// its Reason. It returns "" for healthy services and unexplained problems.
+func StatusHint(st ServiceStatus) string {
+	if hint := RemediationHint(st.Name, statusMessages(st)); hint != "" {
+		return hint
+	}
+	return reasonHints[st.Reason]
+}
+
+// statusMessages joins the messages a checker left in st, one per line,
+// details in key order.
+func statusMessages(st ServiceStatus) string {
+	keys := make([]string, 0, len(st.Details))
+	for key := range st.Details {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	messages := make([]string, 0, len(keys)+2)
+	for _, key := range keys {
+		messages = append(messages, st.Details[key])
+	}
+	if st.Credentials.Warning != "" {
+		messages = append(messages, st.Credentials.Warning)
+	}
+	if st.HealthCheck != nil {
+		messages = append(messages, st.HealthCheck.Message)
+		for _, check := range st.HealthCheck.Checks {
+			messages = append(messages, check.Message)
+		}
+	}
+	return strings.Join(messages, "\n")
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestRemediationHint tests matching failures against the known problems.
+func TestRemediationHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		service string
+		message string
+		want    string
+	}{
+		{"aws expired token", "aws", "An error occurred (ExpiredToken) when calling the GetCallerIdentity operation", "aws sso login"},
+		{"kubernetes missing context", "kubernetes", "exit status 1\nerror: no context exists with the name: \"prod\"", "kubectl config get-contexts"},
+		{"docker daemon down", "docker", "Cannot connect to the Docker daemon at unix:///var/run/docker.sock", "Start Docker"},
+		{"missing cli for any service", "gcp", `exec: "gcloud": executable file not found in $PATH`, "dev-env init"},
+		{"hint is service specific", "docker", "error: no context exists with the name: \"prod\"", ""},
+		{"unknown failure", "aws", "exit status 1", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RemediationHint(tt.service, tt.message)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("RemediationHint() = %q, want none", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("RemediationHint() = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStatusHint tests the hints attached to checker results.
+func TestStatusHint(t *testing.T) {
+	tests := []struct {
+		name string
+		st   ServiceStatus
+		want string
+	}{
+		{"active", ServiceStatus{Name: "aws", Status: StatusActive, Credentials: CredentialStatus{Valid: true}}, ""},
+		{"known problem in details", ServiceStatus{Name: "docker", Status: StatusError, Reason: ReasonNotRunning,
+			Details: map[string]string{"error": "Cannot connect to the Docker daemon at unix:///var/run/docker.sock"}}, "Start Docker"},
+		{"known problem in health check", ServiceStatus{Name: "kubernetes", Status: StatusActive, Credentials: CredentialStatus{Valid: true},
+			HealthCheck: &HealthStatus{Checks: []SubCheck{{Name: "connectivity", Message: "Unable to connect to the server: dial tcp: i/o timeout"}}}}, "cluster is running"},
+		{"reason fallback", ServiceStatus{Name: "gcp", Status: StatusInactive, Reason: ReasonNotInstalled}, "dev-env init"},
+		{"expired credentials", ServiceStatus{Name: "aws", Status: StatusActive, Reason: ReasonCredentialsInvalid,
+			Credentials: CredentialStatus{Warning: "Token has expired"}}, "aws sso login"},
+		{"unexplained", ServiceStatus{Name: "azure", Status: StatusError}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := StatusHint(tt.st)
+			if tt.want == "" {
+				if got != "" {
+					t.Errorf("StatusHint() = %q, want none", got)
+				}
+				return
+			}
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("StatusHint() = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"time"
+)
+
+// NoopChecker implements ServiceChecker by replaying a fixed status.
+// It is used when restoring a StatusCollector from a snapshot and for
+// replaying status scenarios in tests.
+type NoopChecker struct {
+	name   string
+	status *ServiceStatus
+}
+
+// NewNoopChecker creates a checker that always reports the given status.
+// A nil status yields an unknown status for the named service.
+func NewNoopChecker(name string, st *ServiceStatus) *NoopChecker {
+	return &NoopChecker{
+		name:   name,
+		status: st,
+	}
+}
+
+// Name returns the service name.
+func (n *NoopChecker) Name() string {
+	return n.name
+}
+
+// CheckStatus returns a copy of the recorded status.
+func (n *NoopChecker) CheckStatus(ctx context.Context) (*ServiceStatus, error) {
+	if n.status == nil {
+		return &ServiceStatus{
+			Name:    n.name,
+			Status:  StatusUnknown,
+			Details: make(map[string]string),
+		}, nil
+	}
+
+	st := *n.status
+	if n.status.Details != nil {
+		st.Details = make(map[string]string, len(n.status.Details))
+		for k, v := range n.status.Details {
+			st.Details[k] = v
+		}
+	}
+	return &st, nil
+}
+
+// CheckHealth returns the recorded health check, or an unknown health status.
+func (n *NoopChecker) CheckHealth(ctx context.Context) (*HealthStatus, error) {
+	if n.status != nil && n.status.HealthCheck != nil {
+		health := *n.status.HealthCheck
+		return &health, nil
+	}
+
+	return &HealthStatus{
+		Status:    StatusUnknown,
+		CheckedAt: time.Now(),
+	}, nil
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"testing"
+)
+
+// TestNoopChecker_ImplementsInterface verifies NoopChecker implements ServiceChecker.
+func TestNoopChecker_ImplementsInterface(t *testing.T) {
+	var _ ServiceChecker = (*NoopChecker)(nil)
+}
+
+// TestNoopChecker_NilStatus tests the unknown fallback.
+func TestNoopChecker_NilStatus(t *testing.T) {
+	checker := NewNoopChecker("aws", nil)
+
+	if checker.Name() != "aws" {
+		t.Errorf("Name() = %q, want %q", checker.Name(), "aws")
+	}
+
+	st, err := checker.CheckStatus(context.Background())
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if st.Name != "aws" || st.Status != StatusUnknown {
+		t.Errorf("CheckStatus() = %s/%s, want aws/unknown", st.Name, st.Status)
+	}
+
+	health, err := checker.CheckHealth(context.Background())
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if health.Status != StatusUnknown {
+		t.Errorf("CheckHealth().Status = %s, want unknown", health.Status)
+	}
+}
+
+// TestNoopChecker_ReplaysStatus tests that recorded statuses are returned as copies.
+func TestNoopChecker_ReplaysStatus(t *testing.T) {
+	recorded := &ServiceStatus{
+		Name:        "gcp",
+		Status:      StatusActive,
+		Details:     map[string]string{"key": "value"},
+		HealthCheck: &HealthStatus{Status: StatusActive, Message: "ok"},
+	}
+	checker := NewNoopChecker("gcp", recorded)
+
+	st, err := checker.CheckStatus(context.Background())
+	if err != nil {
+		t.Fatalf("CheckStatus() error = %v", err)
+	}
+	if st.Status != StatusActive {
+		t.Errorf("CheckStatus().Status = %s, want active", st.Status)
+	}
+
+	st.Details["key"] = "mutated"
+	if recorded.Details["key"] != "value" {
+		t.Error("CheckStatus() should return a copy of the recorded details")
+	}
+
+	health, err := checker.CheckHealth(context.Background())
+	if err != nil {
+		t.Fatalf("CheckHealth() error = %v", err)
+	}
+	if health.Message != "ok" {
+		t.Errorf("CheckHealth().Message = %q, want %q", health.Message, "ok")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/internal/recovery"
+)
+
+// PanicError reports a checker that panicked. The collector turns it into a
+// StatusError row instead of letting one buggy checker, such as a plugin,
+// take down the process.
+type PanicError struct {
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the stack trace, starting at the frame that panicked.
+	Stack string
+}
+
+// Error implements the error interface.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("checker panicked: %v", e.Value)
+}
+
+// checkStatus calls checker.CheckStatus, converting a panic into a
+// *PanicError.
+func checkStatus(ctx context.Context, checker ServiceChecker) (st *ServiceStatus, err error) {
+	defer recovery.Recover("status checker", checker.Name(), &err, newPanicError)
+	return checker.CheckStatus(withChecker(ctx, checker.Name()))
+}
+
+// checkHealth calls checker.CheckHealth, converting a panic into a
+// *PanicError.
+func checkHealth(ctx context.Context, checker ServiceChecker) (health *HealthStatus, err error) {
+	defer recovery.Recover("status checker", checker.Name(), &err, newPanicError)
+	return checker.CheckHealth(withChecker(ctx, checker.Name()))
+}
+
+// checkerKey is the context key of the service whose checker is running.
+type checkerKey struct{}
+
+// withChecker returns a context that names service as the one being
+// checked.
+func withChecker(ctx context.Context, service string) context.Context {
+	return context.WithValue(ctx, checkerKey{}, service)
+}
+
+// CheckerFromContext returns the service whose checker the collector is
+// running with ctx, or "". Command traces attribute checker commands to it.
+func CheckerFromContext(ctx context.Context) string {
+	service, _ := ctx.Value(checkerKey{}).(string)
+	return service
+}
+
+// newPanicError is the error recovery.Recover stores for a panic.
+func newPanicError(value interface{}, stack string) error {
+	return &PanicError{Value: value, Stack: stack}
+}
+
+// errorStatus is the row reported for a service whose check failed. A
+// panic's truncated stack trace is kept in the details.
+func errorStatus(service string, err error, maxDetailSize int) ServiceStatus {
+	st := ServiceStatus{
+		Name:   service,
+		Status: StatusError,
+		Details: map[string]string{
+			"error": SanitizeValue(err.Error(), maxDetailSize),
+		},
+		CheckedAt: time.Now(),
+	}
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		st.Details["stack"] = SanitizeValue(panicErr.Stack, maxDetailSize)
+	}
+	return st
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	"time"
+)
+
+// panickingChecker is a checker whose status check panics.
+type panickingChecker struct {
+	name string
+}
+
+func (p *panickingChecker) Name() string { return p.name }
+
+func (p *panickingChecker) CheckStatus(ctx context.Context) (*ServiceStatus, error) {
+	var details map[string]string
+	details["boom"] = "assignment to entry in nil map"
+	return nil, nil
+}
+
+func (p *panickingChecker) CheckHealth(ctx context.Context) (*HealthStatus, error) {
+	panic("health check exploded")
+}
+
+// TestStatusCollector_CollectAll_Panic tests that a panicking checker
+// becomes an error row in both parallel and sequential collection.
+func TestStatusCollector_CollectAll_Panic(t *testing.T) {
+	var logs bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logs)
+
+	for _, parallel := range []bool{true, false} {
+		logs.Reset()
+		collector := NewStatusCollector([]ServiceChecker{
+			newMockChecker("aws"),
+			&panickingChecker{name: "plugin"},
+		}, 5*time.Second)
+
+		statuses, err := collector.CollectAll(context.Background(), StatusOptions{Parallel: parallel})
+		if err != nil {
+			t.Fatalf("CollectAll(parallel=%v) error = %v", parallel, err)
+		}
+		if len(statuses) != 2 {
+			t.Fatalf("CollectAll(parallel=%v) returned %d statuses, want 2", parallel, len(statuses))
+		}
+
+		byName := make(map[string]ServiceStatus, len(statuses))
+		for _, st := range statuses {
+			byName[st.Name] = st
+		}
+		if got := byName["aws"].Status; got != StatusActive {
+			t.Errorf("aws status (parallel=%v) = %v, want %v", parallel, got, StatusActive)
+		}
+
+		plugin := byName["plugin"]
+		if plugin.Status != StatusError {
+			t.Errorf("plugin status (parallel=%v) = %v, want %v", parallel, plugin.Status, StatusError)
+		}
+		if !strings.Contains(plugin.Details["error"], "checker panicked") {
+			t.Errorf("plugin error detail (parallel=%v) = %q, want it to mention the panic", parallel, plugin.Details["error"])
+		}
+		if !strings.Contains(plugin.Details["stack"], "CheckStatus") {
+			t.Errorf("plugin stack detail (parallel=%v) = %q, want the panicking frame", parallel, plugin.Details["stack"])
+		}
+		if !strings.Contains(logs.String(), "status checker plugin panicked") {
+			t.Errorf("log (parallel=%v) = %q, want the panic logged", parallel, logs.String())
+		}
+	}
+}
+
+// TestCheckHealth_Panic tests that a panicking health check returns a PanicError.
+func TestCheckHealth_Panic(t *testing.T) {
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&bytes.Buffer{})
+
+	health, err := checkHealth(context.Background(), &panickingChecker{name: "plugin"})
+	if health != nil {
+		t.Errorf("checkHealth() = %v, want nil", health)
+	}
+	panicErr, ok := err.(*PanicError)
+	if !ok {
+		t.Fatalf("checkHealth() error = %v, want *PanicError", err)
+	}
+	if panicErr.Value != "health check exploded" {
+		t.Errorf("PanicError.Value = %v, want %q", panicErr.Value, "health check exploded")
+	}
+}
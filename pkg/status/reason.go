@@ -0,0 +1,67 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+// Reason explains why a service is not active, so a missing CLI, a service
+// that was never set up, and expired credentials can be told apart without
+// parsing Details. It refines Status rather than replacing it: consumers
+// that only know Status keep seeing inactive or error.
+type Reason string
+
+const (
+	// ReasonNotInstalled means the service's CLI is not on the PATH.
+	ReasonNotInstalled Reason = "notInstalled"
+	// ReasonNotConfigured means the CLI is installed but has no profile,
+	// project, subscription, context, or keys selected.
+	ReasonNotConfigured Reason = "notConfigured"
+	// ReasonNotRunning means a daemon or agent the service needs, such as
+	// the Docker daemon or ssh-agent, is not running.
+	ReasonNotRunning Reason = "notRunning"
+	// ReasonCredentialsInvalid means the service is configured but its
+	// credentials were rejected or have expired.
+	ReasonCredentialsInvalid Reason = "credentialsInvalid"
+)
+
+// Reasons lists the reasons in display order.
+var Reasons = []Reason{ReasonNotInstalled, ReasonNotConfigured, ReasonNotRunning, ReasonCredentialsInvalid}
+
+// ReasonTitle returns the display name of a reason, such as "not installed".
+func ReasonTitle(reason Reason) string {
+	switch reason {
+	case ReasonNotInstalled:
+		return "not installed"
+	case ReasonNotConfigured:
+		return "not configured"
+	case ReasonNotRunning:
+		return "not running"
+	case ReasonCredentialsInvalid:
+		return "credentials invalid"
+	default:
+		return string(reason)
+	}
+}
+
+// Summary counts a collection of statuses for the table footer and the
+// dashboard.
+type Summary struct {
+	Total  int
+	Active int
+	// Reasons counts the statuses with each Reason; statuses without one
+	// are not counted.
+	Reasons map[Reason]int
+}
+
+// Summarize counts statuses by whether they are active and by Reason.
+func Summarize(statuses []ServiceStatus) Summary {
+	summary := Summary{Total: len(statuses), Reasons: make(map[Reason]int)}
+	for _, st := range statuses {
+		if st.Status == StatusActive {
+			summary.Active++
+		}
+		if st.Reason != "" {
+			summary.Reasons[st.Reason]++
+		}
+	}
+	return summary
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"strings"
+	"testing"
+)
+
+// reasonStatuses returns one service per reason plus an active one.
+func reasonStatuses() []ServiceStatus {
+	return []ServiceStatus{
+		{Name: "aws", Status: StatusActive},
+		{Name: "azure", Status: StatusInactive, Reason: ReasonNotInstalled},
+		{Name: "gcp", Status: StatusInactive, Reason: ReasonNotInstalled},
+		{Name: "ssh", Status: StatusInactive, Reason: ReasonNotConfigured},
+		{Name: "docker", Status: StatusInactive, Reason: ReasonNotRunning},
+		{Name: "kubernetes", Status: StatusInactive, Reason: ReasonCredentialsInvalid},
+		{Name: "custom", Status: StatusError},
+	}
+}
+
+// TestSummarize tests counting statuses by activity and reason.
+func TestSummarize(t *testing.T) {
+	got := Summarize(reasonStatuses())
+	if got.Total != 7 || got.Active != 1 {
+		t.Errorf("Summarize() = %d/%d active, want 1/7", got.Active, got.Total)
+	}
+
+	want := map[Reason]int{
+		ReasonNotInstalled:       2,
+		ReasonNotConfigured:      1,
+		ReasonNotRunning:         1,
+		ReasonCredentialsInvalid: 1,
+	}
+	for reason, n := range want {
+		if got.Reasons[reason] != n {
+			t.Errorf("Reasons[%s] = %d, want %d", reason, got.Reasons[reason], n)
+		}
+	}
+	if len(got.Reasons) != len(want) {
+		t.Errorf("Reasons = %v, want only %v", got.Reasons, want)
+	}
+}
+
+// TestStatusTableFormatter_FormatReasons tests that each reason has its own
+// label in the table and its own count in the summary.
+func TestStatusTableFormatter_FormatReasons(t *testing.T) {
+	output, err := NewStatusTableFormatter(false).Format(reasonStatuses())
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"azure      │ 📦 Missing",
+		"ssh        │ ⚙️ Unset",
+		"docker     │ 💤 Stopped",
+		"kubernetes │ 🔑 Bad auth",
+		"custom     │ ⚠️ Error",
+		"2 not installed, 1 not configured, 1 not running, 1 credentials invalid",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Format() output missing %q:\n%s", want, output)
+		}
+	}
+
+	// A reason from a newer checker falls back to the coarse status.
+	unknown := ServiceStatus{Name: "vault", Status: StatusInactive, Reason: Reason("sealed")}
+	if got := NewStatusTableFormatter(false).formatServiceStatus(unknown); !strings.Contains(got, "Inactive") {
+		t.Errorf("formatServiceStatus() = %q, want the inactive label for an unknown reason", got)
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxDetailSize is the default limit, in bytes, for a single detail
+// or health message value.
+const DefaultMaxDetailSize = 1024
+
+// Markers left in sanitized values so formatters show why data looks short.
+const (
+	TruncatedMarker = "(truncated)"
+	RedactedMarker  = "(redacted)"
+)
+
+// ansiPattern matches CSI and OSC terminal escape sequences.
+var ansiPattern = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
+// redactionRule replaces secrets matched by pattern. Replacement may refer
+// to submatches to keep non-secret context such as a key name.
+type redactionRule struct {
+	name        string
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// redactionRules are applied in order to every sanitized value.
+var redactionRules = []redactionRule{
+	{
+		name:        "private key",
+		pattern:     regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY-----(?s:.*?)(?:-----END [A-Z0-9 ]*PRIVATE KEY-----|$)`),
+		replacement: RedactedMarker,
+	},
+	{
+		name:        "bearer token",
+		pattern:     regexp.MustCompile(`(?i)(\bbearer\s+)[A-Za-z0-9\-._~+/]+=*`),
+		replacement: "${1}" + RedactedMarker,
+	},
+	{
+		name:        "aws secret key",
+		pattern:     regexp.MustCompile(`(?i)(secret_?(?:access_?)?key["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}`),
+		replacement: "${1}" + RedactedMarker,
+	},
+	{
+		name:        "aws access key id",
+		pattern:     regexp.MustCompile(`\b(?:AKIA|ASIA)[A-Z0-9]{16}\b`),
+		replacement: RedactedMarker,
+	},
+	{
+		name:        "url credentials",
+		pattern:     regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`),
+		replacement: "${1}" + RedactedMarker + "@",
+	},
+	{
+		name:        "url token parameter",
+		pattern:     regexp.MustCompile(`(?i)([?&](?:access_)?token=)[^&\s]+`),
+		replacement: "${1}" + RedactedMarker,
+	},
+}
+
+// SanitizeValue strips terminal escapes from s, redacts secrets, and
+// truncates the result to maxSize bytes. A maxSize of zero or less disables
+// truncation.
+func SanitizeValue(s string, maxSize int) string {
+	s = ansiPattern.ReplaceAllString(s, "")
+	for _, rule := range redactionRules {
+		s = rule.pattern.ReplaceAllString(s, rule.replacement)
+	}
+	return truncate(s, maxSize)
+}
+
+// secretFlagPattern matches flags whose value is a secret, such as
+// --password or --client-secret, but not ones naming where a secret is
+// read from, such as --password-stdin or --token-file.
+var secretFlagPattern = regexp.MustCompile(`(?i)^--?[a-z0-9-]*(?:password|passwd|secret|token)(?:-[a-z0-9]+)*$`)
+
+// secretSourceSuffixes mark secret flags that take no secret themselves.
+var secretSourceSuffixes = []string{"-stdin", "-file", "-env"}
+
+// RedactArgs returns a copy of a command's arguments with the values of
+// secret flags, given as "--password=x" or "--password x", replaced by
+// RedactedMarker, as is -p after a login subcommand (docker login -p).
+// The redaction rules applied by SanitizeValue cover the rest, such as
+// credentials in a registry URL.
+func RedactArgs(args []string) []string {
+	redacted := make([]string, len(args))
+	login, redactNext := false, false
+	for i, arg := range args {
+		if redactNext {
+			redacted[i], redactNext = RedactedMarker, false
+			continue
+		}
+		if arg == "login" {
+			login = true
+		}
+
+		flag, _, hasValue := strings.Cut(arg, "=")
+		if isSecretFlag(flag) || (login && flag == "-p") {
+			if hasValue {
+				redacted[i] = flag + "=" + RedactedMarker
+			} else {
+				redacted[i], redactNext = arg, true
+			}
+			continue
+		}
+		redacted[i] = SanitizeValue(arg, 0)
+	}
+	return redacted
+}
+
+// isSecretFlag reports whether flag takes a secret as its value.
+func isSecretFlag(flag string) bool {
+	if !secretFlagPattern.MatchString(flag) {
+		return false
+	}
+	for _, suffix := range secretSourceSuffixes {
+		if strings.HasSuffix(strings.ToLower(flag), suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// truncate shortens s to at most maxSize bytes without splitting a UTF-8
+// sequence, appending TruncatedMarker when anything was removed.
+func truncate(s string, maxSize int) string {
+	if maxSize <= 0 || len(s) <= maxSize {
+		return s
+	}
+
+	cut := maxSize
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "… " + TruncatedMarker
+}
+
+// sanitizeStatus sanitizes the free-form text of a status in place: its
+// details and its health check message and string details.
+func sanitizeStatus(status *ServiceStatus, maxSize int) {
+	if status == nil {
+		return
+	}
+
+	for k, v := range status.Details {
+		status.Details[k] = SanitizeValue(v, maxSize)
+	}
+
+	if health := status.HealthCheck; health != nil {
+		health.Message = SanitizeValue(health.Message, maxSize)
+		for i := range health.Checks {
+			health.Checks[i].Message = SanitizeValue(health.Checks[i].Message, maxSize)
+		}
+		for k, v := range health.Details {
+			if s, ok := v.(string); ok {
+				health.Details[k] = SanitizeValue(s, maxSize)
+			}
+		}
+	}
+}
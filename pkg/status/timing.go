@@ -0,0 +1,46 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// ServiceTiming records how long a single service check took.
+type ServiceTiming struct {
+	Service  string
+	Duration time.Duration
+	Status   StatusType
+	Err      error
+}
+
+// TimingRecorder collects per-service check timings from a collector. Its
+// Observe method is an ObserverFunc; it is safe for concurrent use.
+type TimingRecorder struct {
+	mu      sync.Mutex
+	timings []ServiceTiming
+}
+
+// Observe is an ObserverFunc that records a check timing.
+func (r *TimingRecorder) Observe(service string, duration time.Duration, st StatusType, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.timings = append(r.timings, ServiceTiming{Service: service, Duration: duration, Status: st, Err: err})
+}
+
+// Take returns the timings recorded since the last call, slowest first, and
+// resets the recorder.
+func (r *TimingRecorder) Take() []ServiceTiming {
+	r.mu.Lock()
+	timings := r.timings
+	r.timings = nil
+	r.mu.Unlock()
+
+	sort.SliceStable(timings, func(i, j int) bool {
+		return timings[i].Duration > timings[j].Duration
+	})
+	return timings
+}
@@ -0,0 +1,35 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestTimingRecorder tests that timings come back slowest first and that
+// Take resets the recorder.
+func TestTimingRecorder(t *testing.T) {
+	recorder := &TimingRecorder{}
+	recorder.Observe("docker", 20*time.Millisecond, StatusActive, nil)
+	recorder.Observe("azure", 6*time.Second, StatusError, context.DeadlineExceeded)
+	recorder.Observe("aws", time.Second, StatusActive, nil)
+
+	timings := recorder.Take()
+	var got []string
+	for _, timing := range timings {
+		got = append(got, timing.Service)
+	}
+	if want := []string{"azure", "aws", "docker"}; len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Errorf("Take() = %v, want %v", got, want)
+	}
+	if timings[0].Err != context.DeadlineExceeded || timings[0].Status != StatusError {
+		t.Errorf("Take()[0] = %+v, want the azure error", timings[0])
+	}
+
+	if again := recorder.Take(); len(again) != 0 {
+		t.Errorf("Take() after Take() = %v, want nothing", again)
+	}
+}
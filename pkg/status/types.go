@@ -5,6 +5,9 @@ package status
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -19,41 +22,140 @@ const (
 )
 
 // ServiceStatus represents the current status of a development environment service.
+//
+// The JSON and YAML field names of ServiceStatus, CurrentConfig,
+// CredentialStatus, and HealthStatus are a stable output contract: they are
+// pinned by struct tags and checked against golden files, so renaming a field
+// must not change them.
 type ServiceStatus struct {
-	Name        string            `json:"name"`
-	Status      StatusType        `json:"status"`
-	Current     CurrentConfig     `json:"current"`
-	Credentials CredentialStatus  `json:"credentials"`
-	LastUsed    time.Time         `json:"lastUsed"`
-	HealthCheck *HealthStatus     `json:"healthCheck,omitempty"`
-	Details     map[string]string `json:"details,omitempty"`
+	Name        string            `json:"name" yaml:"name"`
+	Status      StatusType        `json:"status" yaml:"status"`
+	Reason      Reason            `json:"reason,omitempty" yaml:"reason,omitempty"`
+	Category    Category          `json:"category,omitempty" yaml:"category,omitempty"`
+	Current     CurrentConfig     `json:"current" yaml:"current"`
+	Credentials CredentialStatus  `json:"credentials" yaml:"credentials"`
+	LastUsed    time.Time         `json:"lastUsed" yaml:"lastUsed"`
+	HealthCheck *HealthStatus     `json:"healthCheck,omitempty" yaml:"healthCheck,omitempty"`
+	Details     map[string]string `json:"details,omitempty" yaml:"details,omitempty"`
+
+	// Hint is the suggested fix for a problem the check found, if it is a
+	// known one. See StatusHint.
+	Hint string `json:"hint,omitempty" yaml:"hint,omitempty"`
+
+	// CheckedAt is when the collector ran the check that produced this
+	// status. Statuses served from cache keep their original CheckedAt.
+	CheckedAt time.Time `json:"checkedAt" yaml:"checkedAt"`
+}
+
+// AsOf returns the oldest CheckedAt among statuses, i.e. the time the
+// collection as a whole is current as of. It is zero if none is set.
+func AsOf(statuses []ServiceStatus) time.Time {
+	var oldest time.Time
+	for _, st := range statuses {
+		if st.CheckedAt.IsZero() {
+			continue
+		}
+		if oldest.IsZero() || st.CheckedAt.Before(oldest) {
+			oldest = st.CheckedAt
+		}
+	}
+	return oldest
+}
+
+// StaleError reports statuses checked longer ago than allowed, which can
+// only happen when they are served from the persistent cache.
+type StaleError struct {
+	// Services are the stale services, in collection order.
+	Services []string
+	// AsOf is when the oldest of them was checked.
+	AsOf   time.Time
+	MaxAge time.Duration
+}
+
+// Error implements the error interface.
+func (e *StaleError) Error() string {
+	return fmt.Sprintf("status of %s is older than %s (as of %s)",
+		strings.Join(e.Services, ", "), e.MaxAge, e.AsOf.Format(time.RFC3339))
+}
+
+// CheckMaxAge returns a *StaleError if any status was checked more than
+// maxAge before now. A maxAge of zero disables the check, as do statuses
+// without a CheckedAt.
+func CheckMaxAge(statuses []ServiceStatus, maxAge time.Duration, now time.Time) error {
+	if maxAge <= 0 {
+		return nil
+	}
+
+	var stale []ServiceStatus
+	for _, st := range statuses {
+		if !st.CheckedAt.IsZero() && now.Sub(st.CheckedAt) > maxAge {
+			stale = append(stale, st)
+		}
+	}
+	if len(stale) == 0 {
+		return nil
+	}
+
+	services := make([]string, len(stale))
+	for i, st := range stale {
+		services[i] = st.Name
+	}
+	return &StaleError{Services: services, AsOf: AsOf(stale), MaxAge: maxAge}
 }
 
 // CurrentConfig holds the current configuration details for a service.
 type CurrentConfig struct {
-	Profile   string `json:"profile,omitempty"`
-	Region    string `json:"region,omitempty"`
-	Project   string `json:"project,omitempty"`
-	Context   string `json:"context,omitempty"`
-	Namespace string `json:"namespace,omitempty"`
-	Account   string `json:"account,omitempty"`
+	Profile   string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Region    string `json:"region,omitempty" yaml:"region,omitempty"`
+	Project   string `json:"project,omitempty" yaml:"project,omitempty"`
+	Context   string `json:"context,omitempty" yaml:"context,omitempty"`
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Account   string `json:"account,omitempty" yaml:"account,omitempty"`
+}
+
+// AccountLabel returns st's account for display, with the alias its checker
+// found in Details["account_alias"] in front, such as "prod (123456789012)".
+func AccountLabel(st ServiceStatus) string {
+	alias := st.Details["account_alias"]
+	if alias == "" || st.Current.Account == "" {
+		return st.Current.Account
+	}
+	return fmt.Sprintf("%s (%s)", alias, st.Current.Account)
 }
 
 // CredentialStatus represents the status of service credentials.
 type CredentialStatus struct {
-	Valid     bool      `json:"valid"`
-	ExpiresAt time.Time `json:"expiresAt,omitempty"`
-	Type      string    `json:"type"`
-	Warning   string    `json:"warning,omitempty"`
+	Valid     bool      `json:"valid" yaml:"valid"`
+	ExpiresAt time.Time `json:"expiresAt,omitempty" yaml:"expiresAt,omitempty"`
+	Type      string    `json:"type" yaml:"type"`
+	Warning   string    `json:"warning,omitempty" yaml:"warning,omitempty"`
 }
 
 // HealthStatus represents detailed health check information.
 type HealthStatus struct {
-	Status    StatusType             `json:"status"`
-	Message   string                 `json:"message,omitempty"`
-	CheckedAt time.Time              `json:"checkedAt"`
-	Duration  time.Duration          `json:"duration"`
-	Details   map[string]interface{} `json:"details,omitempty"`
+	Status    StatusType             `json:"status" yaml:"status"`
+	Message   string                 `json:"message,omitempty" yaml:"message,omitempty"`
+	CheckedAt time.Time              `json:"checkedAt" yaml:"checkedAt"`
+	Duration  time.Duration          `json:"duration" yaml:"duration"`
+	Details   map[string]interface{} `json:"details,omitempty" yaml:"details,omitempty"`
+
+	// Checks breaks the health check down into the probes it ran, such as
+	// connectivity and disk usage, in the order they ran. Status and
+	// Message summarize them.
+	Checks []SubCheck `json:"checks,omitempty" yaml:"checks,omitempty"`
+}
+
+// SubCheck is the result of one probe of a health check.
+type SubCheck struct {
+	Name     string        `json:"name" yaml:"name"`
+	Status   StatusType    `json:"status" yaml:"status"`
+	Message  string        `json:"message,omitempty" yaml:"message,omitempty"`
+	Duration time.Duration `json:"duration" yaml:"duration"`
+}
+
+// AddCheck records the probe name, which started at start, as a sub-check.
+func (h *HealthStatus) AddCheck(name string, st StatusType, message string, start time.Time) {
+	h.Checks = append(h.Checks, SubCheck{Name: name, Status: st, Message: message, Duration: time.Since(start)})
 }
 
 // StatusOptions configures how status information is collected.
@@ -63,6 +165,71 @@ type StatusOptions struct {
 	Timeout      time.Duration `json:"timeout"`
 	Parallel     bool          `json:"parallel"`
 	IncludeCache bool          `json:"includeCache"`
+	// MaxConcurrency bounds how many status and health checks run at once
+	// in parallel mode. Zero means unlimited.
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+	// MaxAge makes CollectAll fail with a *StaleError if any status is
+	// older than this, e.g. served from a cache that has not refreshed.
+	// Zero allows any age.
+	MaxAge time.Duration `json:"maxAge,omitempty"`
+	// PerService overrides the options above for individual services,
+	// keyed by service name, e.g. to health-check kubernetes but not a
+	// slow azure.
+	PerService map[string]ServiceCheckOptions `json:"perService,omitempty"`
+}
+
+// ServiceCheckOptions overrides StatusOptions for one service. Unset fields
+// keep the global value.
+type ServiceCheckOptions struct {
+	// CheckHealth, if set, turns the service's health check on or off.
+	CheckHealth *bool `json:"checkHealth,omitempty" yaml:"checkHealth,omitempty"`
+	// Timeout, if positive, bounds the service's checks instead of the
+	// global timeout. It may be longer than the global timeout.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// ForService returns the options to check service with: o with the
+// service's PerService overrides applied.
+func (o StatusOptions) ForService(service string) StatusOptions {
+	override, ok := o.PerService[service]
+	if !ok {
+		return o
+	}
+	if override.CheckHealth != nil {
+		o.CheckHealth = *override.CheckHealth
+	}
+	if override.Timeout > 0 {
+		o.Timeout = override.Timeout
+	}
+	return o
+}
+
+// UnknownServices returns the PerService keys, sorted, that name none of
+// checkers, which are most likely typos.
+func UnknownServices(perService map[string]ServiceCheckOptions, checkers []ServiceChecker) []string {
+	known := make(map[string]bool, len(checkers))
+	for _, checker := range checkers {
+		known[checker.Name()] = true
+	}
+
+	var unknown []string
+	for service := range perService {
+		if !known[service] {
+			unknown = append(unknown, service)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// NewStatusOptions returns the options the status command collects with.
+// Checks run in parallel unless sequential is set, which is slower but
+// easier to follow when debugging a check or avoiding CLI rate limits.
+func NewStatusOptions(checkHealth, sequential bool) StatusOptions {
+	return StatusOptions{
+		CheckHealth: checkHealth,
+		Parallel:    !sequential,
+	}
 }
 
 // ServiceChecker interface for checking service status.
@@ -4,6 +4,8 @@
 package status
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -113,3 +115,160 @@ func TestStatusOptions_Defaults(t *testing.T) {
 		t.Error("Default Timeout should be zero")
 	}
 }
+
+// TestAsOf tests that AsOf returns the oldest check time.
+func TestAsOf(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		statuses []ServiceStatus
+		want     time.Time
+	}{
+		{name: "empty", statuses: nil, want: time.Time{}},
+		{name: "unset ignored", statuses: []ServiceStatus{{}, {CheckedAt: now}}, want: now},
+		{
+			name:     "oldest wins",
+			statuses: []ServiceStatus{{CheckedAt: now}, {CheckedAt: now.Add(-time.Minute)}},
+			want:     now.Add(-time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AsOf(tt.statuses); !got.Equal(tt.want) {
+				t.Errorf("AsOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestCheckMaxAge tests which statuses count as stale.
+func TestCheckMaxAge(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	statuses := []ServiceStatus{
+		{Name: "aws", CheckedAt: now.Add(-2 * time.Minute)},
+		{Name: "docker", CheckedAt: now},
+		{Name: "gcp", CheckedAt: now.Add(-time.Hour)},
+		{Name: "ssh"},
+	}
+
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		want   []string
+	}{
+		{name: "disabled", maxAge: 0},
+		{name: "all fresh enough", maxAge: 2 * time.Hour},
+		{name: "one stale", maxAge: 30 * time.Minute, want: []string{"gcp"}},
+		{name: "two stale", maxAge: time.Minute, want: []string{"aws", "gcp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckMaxAge(statuses, tt.maxAge, now)
+			if tt.want == nil {
+				if err != nil {
+					t.Errorf("CheckMaxAge() error = %v, want nil", err)
+				}
+				return
+			}
+
+			staleErr, ok := err.(*StaleError)
+			if !ok {
+				t.Fatalf("CheckMaxAge() error = %v, want a *StaleError", err)
+			}
+			if fmt.Sprint(staleErr.Services) != fmt.Sprint(tt.want) {
+				t.Errorf("Services = %v, want %v", staleErr.Services, tt.want)
+			}
+			if !staleErr.AsOf.Equal(now.Add(-time.Hour)) {
+				t.Errorf("AsOf = %v, want the oldest stale check", staleErr.AsOf)
+			}
+		})
+	}
+}
+
+// TestAccountLabel tests prefixing an account with its alias.
+func TestAccountLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		st   ServiceStatus
+		want string
+	}{
+		{
+			name: "with alias",
+			st:   ServiceStatus{Current: CurrentConfig{Account: "123456789012"}, Details: map[string]string{"account_alias": "prod"}},
+			want: "prod (123456789012)",
+		},
+		{
+			name: "without alias",
+			st:   ServiceStatus{Current: CurrentConfig{Account: "123456789012"}},
+			want: "123456789012",
+		},
+		{
+			name: "alias without account",
+			st:   ServiceStatus{Details: map[string]string{"account_alias": "prod"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AccountLabel(tt.st); got != tt.want {
+				t.Errorf("AccountLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestStatusOptions_ForService tests merging per-service overrides over
+// the global options.
+func TestStatusOptions_ForService(t *testing.T) {
+	on, off := true, false
+	global := StatusOptions{
+		CheckHealth: true,
+		Timeout:     10 * time.Second,
+		Parallel:    true,
+		PerService: map[string]ServiceCheckOptions{
+			"azure":      {CheckHealth: &off},
+			"kubernetes": {CheckHealth: &on, Timeout: 2 * time.Second},
+			"gcp":        {Timeout: 30 * time.Second},
+		},
+	}
+
+	tests := []struct {
+		service     string
+		wantHealth  bool
+		wantTimeout time.Duration
+	}{
+		{"azure", false, 10 * time.Second},
+		{"kubernetes", true, 2 * time.Second},
+		{"gcp", true, 30 * time.Second},
+		{"aws", true, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := global.ForService(tt.service)
+		if got.CheckHealth != tt.wantHealth || got.Timeout != tt.wantTimeout || !got.Parallel {
+			t.Errorf("ForService(%q) = CheckHealth %v, Timeout %v, Parallel %v, want %v, %v, true",
+				tt.service, got.CheckHealth, got.Timeout, got.Parallel, tt.wantHealth, tt.wantTimeout)
+		}
+	}
+}
+
+// TestUnknownServices tests finding per-service options without a checker.
+func TestUnknownServices(t *testing.T) {
+	checkers := []ServiceChecker{newMockChecker("aws"), newMockChecker("kubernetes")}
+	perService := map[string]ServiceCheckOptions{
+		"kubernetes": {},
+		"k8s":        {},
+		"azrue":      {},
+	}
+
+	got := UnknownServices(perService, checkers)
+	if want := []string{"azrue", "k8s"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnknownServices() = %v, want %v", got, want)
+	}
+	if got := UnknownServices(nil, checkers); len(got) != 0 {
+		t.Errorf("UnknownServices(nil) = %v, want none", got)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// OutputVersion selects the field names used by the YAML formatter.
+type OutputVersion int
+
+const (
+	// OutputVersionStable uses the lowerCamelCase names pinned by the struct
+	// tags, matching the JSON output. It is the default.
+	OutputVersionStable OutputVersion = iota
+
+	// OutputVersionLegacy reproduces the YAML output from before field names
+	// were pinned: yaml.v3 lowercased the Go field names ("lastused",
+	// "expiresat", "healthcheck", "checkedat") and empty fields were not
+	// omitted. JSON output is the same in both versions.
+	OutputVersionLegacy
+)
+
+// ParseOutputVersion parses "stable" or "legacy".
+func ParseOutputVersion(s string) (OutputVersion, error) {
+	switch strings.ToLower(s) {
+	case "", "stable":
+		return OutputVersionStable, nil
+	case "legacy":
+		return OutputVersionLegacy, nil
+	default:
+		return 0, fmt.Errorf("unsupported output version: %s (supported: stable, legacy)", s)
+	}
+}
+
+// The legacy types mirror the status types without YAML tags, so yaml.v3
+// derives the field names as it did before the tags were added.
+type (
+	legacyServiceStatus struct {
+		Name        string
+		Status      StatusType
+		Current     legacyCurrentConfig
+		Credentials legacyCredentialStatus
+		LastUsed    time.Time
+		HealthCheck *legacyHealthStatus
+		Details     map[string]string
+		CheckedAt   time.Time
+	}
+
+	legacyCurrentConfig struct {
+		Profile   string
+		Region    string
+		Project   string
+		Context   string
+		Namespace string
+		Account   string
+	}
+
+	legacyCredentialStatus struct {
+		Valid     bool
+		ExpiresAt time.Time
+		Type      string
+		Warning   string
+	}
+
+	legacyHealthStatus struct {
+		Status    StatusType
+		Message   string
+		CheckedAt time.Time
+		Duration  time.Duration
+		Details   map[string]interface{}
+	}
+)
+
+// toLegacy converts statuses to their legacy YAML representation.
+func toLegacy(statuses []ServiceStatus) []legacyServiceStatus {
+	legacy := make([]legacyServiceStatus, len(statuses))
+	for i, st := range statuses {
+		legacy[i] = legacyServiceStatus{
+			Name:        st.Name,
+			Status:      st.Status,
+			Current:     legacyCurrentConfig(st.Current),
+			Credentials: legacyCredentialStatus(st.Credentials),
+			LastUsed:    st.LastUsed,
+			Details:     st.Details,
+			CheckedAt:   st.CheckedAt,
+		}
+		if st.HealthCheck != nil {
+			// Sub-checks are newer than the legacy format, so are left out.
+			legacy[i].HealthCheck = &legacyHealthStatus{
+				Status:    st.HealthCheck.Status,
+				Message:   st.HealthCheck.Message,
+				CheckedAt: st.HealthCheck.CheckedAt,
+				Duration:  st.HealthCheck.Duration,
+				Details:   st.HealthCheck.Details,
+			}
+		}
+	}
+	return legacy
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// clearScreen clears the terminal and moves the cursor home.
+const clearScreen = "\033[2J\033[H"
+
+// WatchWriter writes successive status snapshots for watch mode. On a
+// terminal each snapshot replaces the previous one; otherwise snapshots are
+// appended as timestamped blocks so piped or redirected output stays
+// readable.
+type WatchWriter struct {
+	w        io.Writer
+	terminal bool
+}
+
+// NewWatchWriter creates a WatchWriter for w, detecting whether w is a
+// terminal.
+func NewWatchWriter(w io.Writer) *WatchWriter {
+	terminal := false
+	if f, ok := w.(interface{ Fd() uintptr }); ok {
+		terminal = term.IsTerminal(f.Fd())
+	}
+	return &WatchWriter{w: w, terminal: terminal}
+}
+
+// Terminal reports whether snapshots replace each other on screen.
+func (ww *WatchWriter) Terminal() bool {
+	return ww.terminal
+}
+
+// Begin starts a new snapshot taken at t.
+func (ww *WatchWriter) Begin(t time.Time) error {
+	stamp := t.Format("2006-01-02 15:04:05")
+	if ww.terminal {
+		_, err := fmt.Fprintf(ww.w, "%sLast updated: %s\n\n", clearScreen, stamp)
+		return err
+	}
+	_, err := fmt.Fprintf(ww.w, "=== %s ===\n", stamp)
+	return err
+}
+
+// Write writes snapshot content.
+func (ww *WatchWriter) Write(p []byte) (int, error) {
+	return ww.w.Write(p)
+}
+
+// ChangeFilter compares each watch snapshot with the one before it and, in
+// only-changed mode, decides which snapshots are worth printing, so a long
+// watch stays quiet until something happens.
+type ChangeFilter struct {
+	// OnlyChanged suppresses snapshots identical to the previous one. The
+	// first snapshot is always printed, as the baseline later ones differ
+	// from.
+	OnlyChanged bool
+
+	previous []ServiceStatus
+	seen     bool
+}
+
+// Observe records statuses as the latest snapshot and returns the changes
+// since the previous one, stamped with at, and whether to print it.
+func (f *ChangeFilter) Observe(statuses []ServiceStatus, at time.Time) (changes []ChangeEvent, show bool) {
+	first := !f.seen
+	if !first {
+		changes = DetectChanges(f.previous, statuses, at)
+	}
+	f.previous, f.seen = statuses, true
+	return changes, first || !f.OnlyChanged || len(changes) > 0
+}
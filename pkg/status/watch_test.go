@@ -0,0 +1,107 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package status
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWatchWriter_NonTerminal tests that non-terminal output appends
+// timestamped blocks without clear sequences.
+func TestWatchWriter_NonTerminal(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "watch.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	for name, w := range map[string]io.Writer{
+		"buffer": &bytes.Buffer{},
+		"file":   file,
+	} {
+		ww := NewWatchWriter(w)
+		if ww.Terminal() {
+			t.Errorf("%s: Terminal() = true, want false", name)
+		}
+	}
+
+	var buf bytes.Buffer
+	ww := NewWatchWriter(&buf)
+	first := time.Date(2025, 1, 1, 9, 0, 0, 0, time.Local)
+	for i := 0; i < 2; i++ {
+		if err := ww.Begin(first.Add(time.Duration(i) * time.Minute)); err != nil {
+			t.Fatalf("Begin() error = %v", err)
+		}
+		fmt.Fprintf(ww, "snapshot %d\n", i)
+	}
+
+	got := buf.String()
+	if strings.Contains(got, "\033[") {
+		t.Errorf("non-terminal output contains escape sequences: %q", got)
+	}
+	want := "=== 2025-01-01 09:00:00 ===\nsnapshot 0\n=== 2025-01-01 09:01:00 ===\nsnapshot 1\n"
+	if got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+// TestWatchWriter_Terminal tests that terminal output clears the screen.
+func TestWatchWriter_Terminal(t *testing.T) {
+	var buf bytes.Buffer
+	ww := &WatchWriter{w: &buf, terminal: true}
+
+	if err := ww.Begin(time.Date(2025, 1, 1, 9, 0, 0, 0, time.Local)); err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, clearScreen) || !strings.Contains(got, "Last updated: 2025-01-01 09:00:00") {
+		t.Errorf("output = %q, want clear sequence and timestamp", got)
+	}
+}
+
+// TestChangeFilter tests that only-changed mode shows the first snapshot
+// and then only snapshots that differ from the one before.
+func TestChangeFilter(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	active := []ServiceStatus{{Name: "aws", Status: StatusActive, Credentials: CredentialStatus{Valid: true}}}
+	expired := []ServiceStatus{{Name: "aws", Status: StatusActive, Credentials: CredentialStatus{Valid: false}}}
+	inactive := []ServiceStatus{{Name: "aws", Status: StatusInactive}}
+
+	snapshots := []struct {
+		statuses    []ServiceStatus
+		wantShow    bool
+		wantChanges int
+	}{
+		{active, true, 0},
+		{active, false, 0},
+		{active, false, 0},
+		{expired, true, 1},
+		{expired, false, 0},
+		{inactive, true, 1},
+		{active, true, 2},
+	}
+
+	var filter ChangeFilter
+	filter.OnlyChanged = true
+	for i, snapshot := range snapshots {
+		changes, show := filter.Observe(snapshot.statuses, at)
+		if show != snapshot.wantShow || len(changes) != snapshot.wantChanges {
+			t.Errorf("snapshot %d: Observe() = %d changes, show %v, want %d changes, show %v",
+				i, len(changes), show, snapshot.wantChanges, snapshot.wantShow)
+		}
+	}
+
+	var always ChangeFilter
+	for i := 0; i < 2; i++ {
+		if _, show := always.Observe(active, at); !show {
+			t.Errorf("snapshot %d: Observe() show = false without OnlyChanged, want true", i)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+// Package telemetry exports environment switches with OpenTelemetry.
+//
+// This package implements:
+//   - Telemetry: An environment.Telemetry producing a span per switch, with
+//     child spans per service and hook, and switch outcome, duration, and
+//     rollback metrics
+//   - Setup: Telemetry exporting over OTLP/HTTP as configured by the
+//     standard OTEL_* environment variables
+//
+// The OTLP exporter is built only with the otel build tag. Without it,
+// Setup returns ErrNotBuilt, so the exporter's gRPC and protobuf
+// dependencies stay out of binaries that do not export.
+package telemetry
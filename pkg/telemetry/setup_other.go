@@ -0,0 +1,22 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+//go:build !otel
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotBuilt is returned by Setup in builds without the otel build tag,
+// which leave the OTLP exporters and their gRPC and protobuf dependencies
+// out of the binary.
+var ErrNotBuilt = errors.New("OpenTelemetry export is not built in; rebuild with -tags otel")
+
+// Setup returns ErrNotBuilt: this build cannot export telemetry. Build with
+// -tags otel for the OTLP/HTTP exporter.
+func Setup(ctx context.Context) (t *Telemetry, shutdown func(context.Context) error, err error) {
+	return nil, nil, ErrNotBuilt
+}
@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// InstrumentationName names the tracer and meter switches are reported by.
+const InstrumentationName = "github.com/gizzahub/gzh-cli-dev-env/pkg/telemetry"
+
+// DefaultServiceName is the service.name of exported telemetry unless
+// OTEL_SERVICE_NAME or OTEL_RESOURCE_ATTRIBUTES sets another.
+const DefaultServiceName = "dev-env"
+
+// Attribute keys of spans and metrics.
+const (
+	EnvironmentKey = attribute.Key("devenv.environment")
+	ServiceKey     = attribute.Key("devenv.service")
+	HookKey        = attribute.Key("devenv.hook")
+	SwitchIDKey    = attribute.Key("devenv.switch_id")
+	// OutcomeKey is "success", "partial", or "failed" for switches, and
+	// "success" or "failed" for services.
+	OutcomeKey = attribute.Key("devenv.outcome")
+	// RollbackCompleteKey reports whether a rollback restored every
+	// service.
+	RollbackCompleteKey = attribute.Key("devenv.rollback.complete")
+)
+
+// Switch and service outcomes.
+const (
+	OutcomeSuccess = "success"
+	OutcomePartial = "partial"
+	OutcomeFailed  = "failed"
+)
+
+// Telemetry reports environment switches as OpenTelemetry spans and
+// metrics:
+//   - a "switch" span per switch, with a "service" child span per service
+//     switched and a "hook" child span per hook run
+//   - devenv.switches: switches, by environment and outcome
+//   - devenv.switch.duration: switch durations in seconds, by environment
+//     and outcome
+//   - devenv.service.duration: service switch durations in seconds, by
+//     service and outcome
+//   - devenv.rollbacks: rollbacks, by environment and whether they were
+//     complete
+type Telemetry struct {
+	tracer          trace.Tracer
+	switches        metric.Int64Counter
+	switchDuration  metric.Float64Histogram
+	serviceDuration metric.Float64Histogram
+	rollbacks       metric.Int64Counter
+}
+
+var _ environment.Telemetry = (*Telemetry)(nil)
+
+// New returns Telemetry that reports to tp and mp.
+func New(tp trace.TracerProvider, mp metric.MeterProvider) (*Telemetry, error) {
+	meter := mp.Meter(InstrumentationName)
+	t := &Telemetry{tracer: tp.Tracer(InstrumentationName)}
+
+	var err error
+	if t.switches, err = meter.Int64Counter("devenv.switches",
+		metric.WithDescription("Environment switches, by outcome"),
+		metric.WithUnit("{switch}")); err != nil {
+		return nil, fmt.Errorf("failed to create switch counter: %w", err)
+	}
+	if t.switchDuration, err = meter.Float64Histogram("devenv.switch.duration",
+		metric.WithDescription("Duration of environment switches"),
+		metric.WithUnit("s")); err != nil {
+		return nil, fmt.Errorf("failed to create switch duration histogram: %w", err)
+	}
+	if t.serviceDuration, err = meter.Float64Histogram("devenv.service.duration",
+		metric.WithDescription("Duration of switching a single service"),
+		metric.WithUnit("s")); err != nil {
+		return nil, fmt.Errorf("failed to create service duration histogram: %w", err)
+	}
+	if t.rollbacks, err = meter.Int64Counter("devenv.rollbacks",
+		metric.WithDescription("Rollbacks after failed switches"),
+		metric.WithUnit("{rollback}")); err != nil {
+		return nil, fmt.Errorf("failed to create rollback counter: %w", err)
+	}
+	return t, nil
+}
+
+// StartSwitch implements environment.Telemetry.
+func (t *Telemetry) StartSwitch(ctx context.Context, env string) (context.Context, func(*environment.SwitchResult, error)) {
+	start := time.Now()
+	ctx, span := t.tracer.Start(ctx, "switch", trace.WithAttributes(EnvironmentKey.String(env)))
+
+	return ctx, func(result *environment.SwitchResult, err error) {
+		outcome := switchOutcome(result, err)
+		attrs := metric.WithAttributes(EnvironmentKey.String(env), OutcomeKey.String(outcome))
+		t.switches.Add(ctx, 1, attrs)
+		t.switchDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+
+		span.SetAttributes(OutcomeKey.String(outcome))
+		if result != nil {
+			span.SetAttributes(SwitchIDKey.String(result.SwitchID))
+			if result.RollbackPerformed {
+				t.rollbacks.Add(ctx, 1, metric.WithAttributes(
+					EnvironmentKey.String(env), RollbackCompleteKey.Bool(result.RollbackComplete)))
+				span.SetAttributes(RollbackCompleteKey.Bool(result.RollbackComplete))
+			}
+		}
+		endSpan(span, err)
+	}
+}
+
+// StartService implements environment.Telemetry.
+func (t *Telemetry) StartService(ctx context.Context, service string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := t.tracer.Start(ctx, "service", trace.WithAttributes(ServiceKey.String(service)))
+
+	return ctx, func(err error) {
+		outcome := OutcomeSuccess
+		if err != nil {
+			outcome = OutcomeFailed
+		}
+		t.serviceDuration.Record(ctx, time.Since(start).Seconds(),
+			metric.WithAttributes(ServiceKey.String(service), OutcomeKey.String(outcome)))
+		endSpan(span, err)
+	}
+}
+
+// StartHook implements environment.Telemetry.
+func (t *Telemetry) StartHook(ctx context.Context, hook string) (context.Context, func(error)) {
+	ctx, span := t.tracer.Start(ctx, "hook", trace.WithAttributes(HookKey.String(hook)))
+	return ctx, func(err error) { endSpan(span, err) }
+}
+
+// switchOutcome classifies a switch for the outcome attribute.
+func switchOutcome(result *environment.SwitchResult, err error) string {
+	switch {
+	case err != nil || result == nil || (!result.Success && !result.Partial):
+		return OutcomeFailed
+	case result.Partial:
+		return OutcomePartial
+	default:
+		return OutcomeSuccess
+	}
+}
+
+// endSpan ends span, marking it failed with err if err is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
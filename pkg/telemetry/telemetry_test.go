@@ -0,0 +1,209 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// fakeSwitcher is a service switcher that fails its switches with err.
+type fakeSwitcher struct {
+	name string
+	err  error
+}
+
+func (f fakeSwitcher) Name() string { return f.name }
+
+func (f fakeSwitcher) Switch(context.Context, interface{}) error { return f.err }
+
+func (f fakeSwitcher) GetCurrentState(context.Context) (interface{}, error) { return "previous", nil }
+
+func (f fakeSwitcher) Rollback(context.Context, interface{}) error { return nil }
+
+// newTestTelemetry returns Telemetry recording to an in-memory span
+// exporter and a manual metric reader.
+func newTestTelemetry(t *testing.T) (*Telemetry, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) {
+	t.Helper()
+	spans := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	telemetry, err := New(
+		sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans)),
+		sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)),
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return telemetry, spans, reader
+}
+
+// collect returns the data of the metrics read from reader, by name.
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+// attr returns the value of key in set, or "" if it is not there.
+func attr(set attribute.Set, key attribute.Key) string {
+	value, _ := set.Value(key)
+	return value.Emit()
+}
+
+// TestTelemetry_Switch tests the spans and metrics of a switch that fails
+// and is rolled back.
+func TestTelemetry_Switch(t *testing.T) {
+	telemetry, spans, reader := newTestTelemetry(t)
+
+	es := environment.NewEnvironmentSwitcher()
+	es.Register(fakeSwitcher{name: "docker"})
+	es.Register(fakeSwitcher{name: "kubernetes", err: errors.New("cluster unreachable")})
+	es.SetTelemetry(telemetry)
+
+	env := &environment.Environment{
+		Name: "dev",
+		Services: map[string]environment.ServiceConfig{
+			"docker":     {Docker: &environment.DockerConfig{Context: "dev"}},
+			"kubernetes": {Kubernetes: &environment.KubernetesConfig{Context: "dev"}},
+		},
+		PreHooks: []environment.Hook{{Command: "echo pre"}},
+	}
+
+	if _, err := es.SwitchEnvironment(context.Background(), env, environment.SwitchOptions{RollbackOnError: true, AllowRoot: true}); err == nil {
+		t.Fatal("SwitchEnvironment() should fail when a service fails")
+	}
+
+	ended := spans.GetSpans()
+	var root tracetest.SpanStub
+	children := make(map[string]tracetest.SpanStub)
+	for _, span := range ended {
+		if span.Name == "switch" {
+			root = span
+			continue
+		}
+		for _, kv := range span.Attributes {
+			if kv.Key == ServiceKey || kv.Key == HookKey {
+				children[kv.Value.Emit()] = span
+			}
+		}
+	}
+	if root.Name == "" {
+		t.Fatalf("spans = %v, want a switch span", ended)
+	}
+	if root.Status.Code != codes.Error {
+		t.Errorf("switch span status = %v, want Error", root.Status.Code)
+	}
+	for _, name := range []string{"docker", "kubernetes", "pre-hook-0"} {
+		child, ok := children[name]
+		if !ok {
+			t.Errorf("no span for %s", name)
+			continue
+		}
+		if child.Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Errorf("%s span is not a child of the switch span", name)
+		}
+	}
+	if children["kubernetes"].Status.Code != codes.Error || children["docker"].Status.Code == codes.Error {
+		t.Errorf("service span statuses = docker %v, kubernetes %v, want only kubernetes failed",
+			children["docker"].Status.Code, children["kubernetes"].Status.Code)
+	}
+
+	metrics := collect(t, reader)
+
+	switches, ok := metrics["devenv.switches"].(metricdata.Sum[int64])
+	if !ok || len(switches.DataPoints) != 1 {
+		t.Fatalf("devenv.switches = %+v, want one data point", metrics["devenv.switches"])
+	}
+	if point := switches.DataPoints[0]; point.Value != 1 || attr(point.Attributes, OutcomeKey) != OutcomeFailed || attr(point.Attributes, EnvironmentKey) != "dev" {
+		t.Errorf("devenv.switches = %d %v, want 1 failed switch to dev", point.Value, point.Attributes.ToSlice())
+	}
+
+	rollbacks, ok := metrics["devenv.rollbacks"].(metricdata.Sum[int64])
+	if !ok || len(rollbacks.DataPoints) != 1 || attr(rollbacks.DataPoints[0].Attributes, RollbackCompleteKey) != "true" {
+		t.Errorf("devenv.rollbacks = %+v, want one complete rollback", metrics["devenv.rollbacks"])
+	}
+
+	durations, ok := metrics["devenv.service.duration"].(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("devenv.service.duration = %+v, want a histogram", metrics["devenv.service.duration"])
+	}
+	outcomes := make(map[string]string)
+	for _, point := range durations.DataPoints {
+		outcomes[attr(point.Attributes, ServiceKey)] = attr(point.Attributes, OutcomeKey)
+	}
+	if outcomes["docker"] != OutcomeSuccess || outcomes["kubernetes"] != OutcomeFailed {
+		t.Errorf("service duration outcomes = %v, want docker success and kubernetes failed", outcomes)
+	}
+
+	if _, ok := metrics["devenv.switch.duration"].(metricdata.Histogram[float64]); !ok {
+		t.Errorf("devenv.switch.duration = %+v, want a histogram", metrics["devenv.switch.duration"])
+	}
+}
+
+// TestTelemetry_DryRun tests that dry runs are not reported.
+func TestTelemetry_DryRun(t *testing.T) {
+	telemetry, spans, reader := newTestTelemetry(t)
+
+	es := environment.NewEnvironmentSwitcher()
+	es.Register(fakeSwitcher{name: "docker"})
+	es.SetTelemetry(telemetry)
+
+	env := &environment.Environment{
+		Name:     "dev",
+		Services: map[string]environment.ServiceConfig{"docker": {Docker: &environment.DockerConfig{Context: "dev"}}},
+	}
+	if _, err := es.SwitchEnvironment(context.Background(), env, environment.SwitchOptions{DryRun: true, AllowRoot: true}); err != nil {
+		t.Fatalf("SwitchEnvironment() error = %v", err)
+	}
+
+	if ended := spans.GetSpans(); len(ended) != 0 {
+		t.Errorf("spans = %v, want none for a dry run", ended)
+	}
+	if metrics := collect(t, reader); len(metrics) != 0 {
+		t.Errorf("metrics = %v, want none for a dry run", metrics)
+	}
+}
+
+// TestSwitchOutcome tests classifying switches for the outcome attribute.
+func TestSwitchOutcome(t *testing.T) {
+	tests := []struct {
+		name   string
+		result *environment.SwitchResult
+		err    error
+		want   string
+	}{
+		{"success", &environment.SwitchResult{Success: true}, nil, OutcomeSuccess},
+		{"partial", &environment.SwitchResult{Partial: true}, nil, OutcomePartial},
+		{"error", &environment.SwitchResult{Partial: true}, errors.New("failed"), OutcomeFailed},
+		{"no result", nil, errors.New("invalid"), OutcomeFailed},
+		{"unsuccessful", &environment.SwitchResult{}, nil, OutcomeFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := switchOutcome(tt.result, tt.err); got != tt.want {
+				t.Errorf("switchOutcome() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
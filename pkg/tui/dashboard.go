@@ -29,21 +29,93 @@ type DashboardModel struct {
 	currentEnv string
 	loading    bool
 	errorMsg   string
+
+	// switchable lists the services with a registered switcher, by
+	// lowercase name. While nil, the Switch column is left blank.
+	switchable map[string]bool
+
+	// groupByCategory sections the table under a heading row per service
+	// category, toggled with the ToggleGroup key.
+	groupByCategory bool
+
+	// timings is how long the last refresh took, shown in the footer and,
+	// while showTimings is set, broken down per service.
+	timings     *RefreshTimings
+	showTimings bool
+
+	// changes is how the services changed in the last refresh, listed in
+	// the footer. refreshed is set once the first statuses arrive, which
+	// are not changes.
+	changes   []status.StatusChange
+	refreshed bool
+
+	// now is the header clock, advanced by ClockTickMsg. body caches the
+	// rendered table, quick actions, and help so clock ticks only re-render
+	// the header; bodyValid is cleared by every other message.
+	now       time.Time
+	body      string
+	bodyValid bool
 }
 
-// NewDashboardModel creates a new dashboard model.
-func NewDashboardModel() *DashboardModel {
-	// Create table columns
-	columns := []table.Column{
+// noCurrentEnv is shown as the current environment until one is known.
+const noCurrentEnv = "unknown"
+
+// expiryWarning is how long before credentials expire the dashboard starts
+// warning about them.
+const expiryWarning = 2 * time.Hour
+
+// Layout breakpoints. Below narrowWidth columns the table abbreviates its
+// column titles and the quick actions lose their second row, and below
+// shortHeight rows the help line is hidden. A terminal that is both, or
+// too narrow for even the abbreviated table, gets a plain list of
+// "service: status" lines instead.
+const (
+	narrowWidth = 80
+	shortHeight = 20
+)
+
+// Table columns at full and narrow widths. The Current column, which
+// comes third, shrinks to fit the terminal, down to minCurrentWidth.
+var (
+	wideColumns = []table.Column{
 		{Title: "Service", Width: 12},
 		{Title: "Status", Width: 12},
 		{Title: "Current", Width: 25},
 		{Title: "Credentials", Width: 15},
+		{Title: "Switch", Width: 6},
 		{Title: "", Width: 3},
 	}
+	narrowColumns = []table.Column{
+		{Title: "Service", Width: 10},
+		{Title: "Status", Width: 10},
+		{Title: "Current", Width: 20},
+		{Title: "Creds", Width: 12},
+		{Title: "Sw", Width: 3},
+		{Title: "", Width: 2},
+	}
+)
 
+// currentColumn is the index of the Current column, and minCurrentWidth
+// the narrowest it gets before the dashboard switches to the compact list.
+const (
+	currentColumn   = 2
+	minCurrentWidth = 8
+)
+
+// dashboardLayout is which panels the dashboard renders at its size.
+type dashboardLayout struct {
+	// narrow abbreviates the table and drops the second quick actions row.
+	narrow bool
+	// short hides the help line.
+	short bool
+	// compact replaces everything with a list of "service: status" lines.
+	compact bool
+}
+
+// NewDashboardModel creates a new dashboard model.
+func NewDashboardModel() *DashboardModel {
 	t := table.New(
-		table.WithColumns(columns),
+		table.WithColumns(wideColumns),
 		table.WithFocused(true),
 		table.WithHeight(7),
 	)
@@ -67,11 +139,23 @@ func NewDashboardModel() *DashboardModel {
 		keymap:     DefaultKeyMap,
 		services:   []status.ServiceStatus{},
 		lastUpdate: time.Now(),
-		currentEnv: "production",
+		currentEnv: noCurrentEnv,
 		loading:    true,
+		now:        time.Now(),
 	}
 }
 
+// SetSwitchableServices records which services have a registered switcher,
+// so the dashboard can show that a service is visible but not switchable.
+func (m *DashboardModel) SetSwitchableServices(services []string) {
+	m.switchable = make(map[string]bool, len(services))
+	for _, service := range services {
+		m.switchable[strings.ToLower(service)] = true
+	}
+	m.updateServices(m.services)
+	m.bodyValid = false
+}
+
 // Init initializes the dashboard model.
 func (m *DashboardModel) Init() tea.Cmd {
 	return nil
@@ -81,6 +165,12 @@ func (m *DashboardModel) Init() tea.Cmd {
 func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if tick, ok := msg.(ClockTickMsg); ok {
+		m.now = tick.Time
+		return m, nil
+	}
+	m.bodyValid = false
+
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch {
@@ -92,6 +182,11 @@ func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 			return m, m.selectService()
 		case key.Matches(msg, m.keymap.Refresh):
 			return m, m.refreshStatus()
+		case key.Matches(msg, m.keymap.ToggleGroup):
+			m.groupByCategory = !m.groupByCategory
+			m.updateServices(m.services)
+		case key.Matches(msg, m.keymap.Timings):
+			m.showTimings = !m.showTimings
 		case key.Matches(msg, m.keymap.SwitchEnv):
 			return m, func() tea.Msg {
 				return NavigationMsg{View: ViewEnvironmentSwitch}
@@ -119,7 +214,17 @@ func (m *DashboardModel) Update(msg tea.Msg) (*DashboardModel, tea.Cmd) {
 		}
 
 	case StatusUpdateMsg:
+		if m.refreshed {
+			m.changes = status.DiffStatuses(m.services, msg.Statuses)
+		}
+		m.refreshed = true
 		m.updateServices(msg.Statuses)
+		if msg.Timings != nil {
+			m.timings = msg.Timings
+		}
+		if msg.CurrentEnv != "" {
+			m.currentEnv = msg.CurrentEnv
+		}
 		m.loading = false
 		m.errorMsg = ""
 		m.lastUpdate = time.Now()
@@ -158,56 +263,82 @@ func (m *DashboardModel) View() string {
 
 // renderDashboard renders the main dashboard view.
 func (m *DashboardModel) renderDashboard() string {
-	var b strings.Builder
+	if m.layout().compact {
+		return m.renderCompact()
+	}
+	if !m.bodyValid {
+		m.body = m.renderBody()
+		m.bodyValid = true
+	}
+	return m.renderHeader() + "\n" + m.body
+}
 
-	// Header
-	header := m.renderHeader()
-	b.WriteString(header)
-	b.WriteString("\n")
+// renderBody renders everything below the header.
+func (m *DashboardModel) renderBody() string {
+	var b strings.Builder
 
 	// Service table
 	tableView := m.table.View()
 	b.WriteString(tableView)
 	b.WriteString("\n")
 
+	if m.showTimings && m.timings != nil {
+		b.WriteString(m.timings.Breakdown())
+		b.WriteString("\n")
+	}
+
 	// Quick actions
 	quickActions := m.renderQuickActions()
 	b.WriteString(quickActions)
 	b.WriteString("\n")
 
 	// Help
-	helpView := m.help.View(m.keymap)
-	b.WriteString(helpView)
+	if !m.layout().short {
+		b.WriteString(m.help.View(m.keymap))
+	}
 
-	return b.String()
+	return strings.TrimSuffix(b.String(), "\n")
 }
 
 // renderHeader renders the dashboard header.
 func (m *DashboardModel) renderHeader() string {
 	title := "GZH Development Environment Manager"
 	env := fmt.Sprintf("Current Environment: %s", m.currentEnv)
-	updated := fmt.Sprintf("Updated: %s", m.lastUpdate.Format("15:04:05"))
+	// Show how fresh the data is, which can predate the last refresh when
+	// statuses come from cache.
+	asOf := status.AsOf(m.services)
+	if asOf.IsZero() {
+		asOf = m.lastUpdate
+	}
+	updated := fmt.Sprintf("As of %s  Clock: %s", asOf.Format("15:04:05"), m.now.Format("15:04:05"))
 
 	titleStyle := TitleStyle.Width(m.width - 2).Align(lipgloss.Center)
 	headerStyle := HeaderStyle.Width(m.width - 2)
 
-	padding := m.width - len(env) - len(updated) - 4
-	if padding < 0 {
-		padding = 0
+	// Stack the environment and the times when they do not fit side by
+	// side, rather than letting the line wrap mid-word.
+	var headerContent string
+	padding := m.width - lipgloss.Width(env) - lipgloss.Width(updated) - 4
+	if m.width > 0 && padding < 1 {
+		headerContent = lipgloss.JoinVertical(lipgloss.Left, env, updated)
+	} else {
+		headerContent = lipgloss.JoinHorizontal(
+			lipgloss.Left,
+			env,
+			strings.Repeat(" ", max(padding, 0)),
+			updated,
+		)
 	}
 
-	headerContent := lipgloss.JoinHorizontal(
-		lipgloss.Left,
-		env,
-		strings.Repeat(" ", padding),
-		updated,
-	)
-
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
+	lines := []string{
 		titleStyle.Render(title),
 		headerStyle.Render(headerContent),
-	)
+	}
+	// Point at the credential about to break, if any.
+	if next, ok := status.NextExpiry(m.services, m.now, expiryWarning); ok {
+		lines = append(lines, ServiceWarningStyle.Render(status.ExpirySummary(next, m.now)))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
 }
 
 // renderQuickActions renders the quick actions bar.
@@ -222,20 +353,101 @@ func (m *DashboardModel) renderQuickActions() string {
 	secondRow := []string{
 		"[s] Search",
 		"[f] Filter",
+		"[g] Group",
+		"[t] Timings",
 		"[?] Help",
 		"[Enter] Service Details",
 	}
 
 	style := FooterStyle.Width(m.width - 2)
 
-	firstLine := "Quick Actions: " + strings.Join(actions, "  ")
-	secondLine := strings.Join(secondRow, "  ")
+	// Wrap between actions, never within one, to the width inside the
+	// footer's padding.
+	actions[0] = "Quick Actions: " + actions[0]
+	lines := wrapItems(actions, m.width-4)
+	if !m.layout().narrow {
+		lines = append(lines, wrapItems(secondRow, m.width-4)...)
+	}
+	if m.timings != nil {
+		refresh := m.timings.Summary()
+		if m.timings.Total > slowRefresh {
+			refresh = ServiceWarningStyle.Render(refresh)
+		}
+		lines = append(lines, refresh)
+	}
+	if len(m.changes) > 0 {
+		lines = append(lines, wrapItems(changeItems(m.changes), m.width-4)...)
+	}
+
+	return style.Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
 
-	return style.Render(lipgloss.JoinVertical(
-		lipgloss.Left,
-		firstLine,
-		secondLine,
-	))
+// renderCompact renders the dashboard as a title line and one
+// "service: status" line per table row, for terminals too small for the
+// table. The cursor row is marked, and the list scrolls to keep it visible.
+func (m *DashboardModel) renderCompact() string {
+	line := lipgloss.NewStyle().MaxWidth(m.width)
+	lines := []string{line.Render(TitleStyle.Render("dev-env: " + m.currentEnv))}
+
+	rows := m.table.Rows()
+	cursor := m.table.Cursor()
+	visible := max(m.height-len(lines), 1)
+	start := max(min(cursor-visible+1, len(rows)-visible), 0)
+	for i := start; i < len(rows) && i < start+visible; i++ {
+		marker := "  "
+		if i == cursor {
+			marker = "> "
+		}
+		text := marker + rows[i][0] + ": " + rows[i][1]
+		if i == cursor {
+			text = TableSelectedStyle.Padding(0).Render(text)
+		}
+		lines = append(lines, line.Render(text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// maxFooterChanges is how many changes the footer lists before summing up
+// the rest.
+const maxFooterChanges = 3
+
+// changeItems renders changes as footer items, such as
+// "Changed: aws: active → inactive", listing at most maxFooterChanges.
+func changeItems(changes []status.StatusChange) []string {
+	items := make([]string, 0, maxFooterChanges+1)
+	for i, change := range changes {
+		if i == maxFooterChanges {
+			items = append(items, fmt.Sprintf("+%d more", len(changes)-i))
+			break
+		}
+		items = append(items, change.String())
+	}
+	items[0] = "Changed: " + items[0]
+	return items
+}
+
+// wrapItems joins items with two spaces into lines of at most width
+// columns, starting a new line before an item that would not fit. An item
+// wider than width gets a line of its own. A width of zero or less puts
+// every item on one line.
+func wrapItems(items []string, width int) []string {
+	var lines []string
+	var current string
+	for _, item := range items {
+		switch {
+		case current == "":
+			current = item
+		case width <= 0 || lipgloss.Width(current)+2+lipgloss.Width(item) <= width:
+			current += "  " + item
+		default:
+			lines = append(lines, current)
+			current = item
+		}
+	}
+	if current != "" {
+		lines = append(lines, current)
+	}
+	return lines
 }
 
 // renderLoading renders the loading state.
@@ -264,72 +476,171 @@ func (m *DashboardModel) renderError() string {
 	)
 }
 
+// groupRowPrefix starts the Service cell of a category heading row.
+const groupRowPrefix = "▸ "
+
 // updateServices updates the service list and table rows.
 func (m *DashboardModel) updateServices(services []status.ServiceStatus) {
 	m.services = services
 
-	rows := make([]table.Row, len(services))
-	for i, service := range services {
-		statusIcon := GetStatusIcon(strings.ToLower(string(service.Status)))
-		statusText := fmt.Sprintf("%s %s", statusIcon, string(service.Status))
+	if !m.groupByCategory {
+		rows := make([]table.Row, len(services))
+		for i, service := range services {
+			rows[i] = m.serviceRow(service)
+		}
+		m.table.SetRows(rows)
+		return
+	}
 
-		// Format current context
-		current := service.Current.Context
-		if len(current) > 22 {
-			current = current[:19] + "..."
+	groups := make(map[status.Category][]status.ServiceStatus, len(status.Categories))
+	for _, service := range services {
+		category := service.Category
+		if _, err := status.ParseCategory(string(category)); err != nil {
+			category = status.CategoryCustom
 		}
+		groups[category] = append(groups[category], service)
+	}
 
-		// Format credentials status
-		var credStatus string
-		if service.Credentials.Valid {
-			credStatus = "✅ Valid"
-			// Check if credentials are expiring soon
-			if !service.Credentials.ExpiresAt.IsZero() {
-				timeUntilExpiry := time.Until(service.Credentials.ExpiresAt)
-				if timeUntilExpiry < 0 {
-					credStatus = "❌ Expired"
-				} else if timeUntilExpiry < 2*time.Hour {
-					credStatus = fmt.Sprintf("⚠️ Expires %s", formatDuration(timeUntilExpiry))
-				} else {
-					credStatus = fmt.Sprintf("✅ Valid (%s)", formatDuration(timeUntilExpiry))
-				}
+	rows := make([]table.Row, 0, len(services)+len(status.Categories))
+	for _, category := range status.Categories {
+		members := groups[category]
+		if len(members) == 0 {
+			continue
+		}
+		active := 0
+		for _, service := range members {
+			if service.Status == status.StatusActive {
+				active++
 			}
-		} else {
-			if service.Credentials.Warning != "" {
-				credStatus = fmt.Sprintf("⚠️ %s", service.Credentials.Warning)
+		}
+		rows = append(rows, table.Row{
+			groupRowPrefix + status.CategoryTitle(category),
+			fmt.Sprintf("%d/%d active", active, len(members)),
+			"", "", "", "",
+		})
+		for _, service := range members {
+			rows = append(rows, m.serviceRow(service))
+		}
+	}
+	m.table.SetRows(rows)
+}
+
+// reasonShortLabels are the Status cell texts of services that are not
+// active for a known reason, short enough for the column.
+var reasonShortLabels = map[status.Reason]string{
+	status.ReasonNotInstalled:       "missing",
+	status.ReasonNotConfigured:      "unset",
+	status.ReasonNotRunning:         "stopped",
+	status.ReasonCredentialsInvalid: "bad auth",
+}
+
+// serviceRow renders a service as a table row.
+func (m *DashboardModel) serviceRow(service status.ServiceStatus) table.Row {
+	statusIcon := GetStatusIcon(strings.ToLower(string(service.Status)))
+	statusText := fmt.Sprintf("%s %s", statusIcon, string(service.Status))
+	if icon := GetReasonIcon(string(service.Reason)); icon != "" {
+		statusText = fmt.Sprintf("%s %s", icon, reasonShortLabels[service.Reason])
+	}
+
+	// Format current context
+	current := service.Current.Context
+	if len(current) > 22 {
+		current = current[:19] + "..."
+	}
+
+	// Format credentials status
+	var credStatus string
+	if service.Credentials.Valid {
+		credStatus = "✅ Valid"
+		// Check if credentials are expiring soon
+		if !service.Credentials.ExpiresAt.IsZero() {
+			timeUntilExpiry := time.Until(service.Credentials.ExpiresAt)
+			if timeUntilExpiry < 0 {
+				credStatus = "❌ Expired"
+			} else if timeUntilExpiry < expiryWarning {
+				credStatus = fmt.Sprintf("⚠️ Expires %s", formatDuration(timeUntilExpiry))
 			} else {
-				credStatus = "❌ Invalid"
+				credStatus = fmt.Sprintf("✅ Valid (%s)", formatDuration(timeUntilExpiry))
 			}
 		}
-
-		rows[i] = table.Row{
-			service.Name,
-			statusText,
-			current,
-			credStatus,
-			"→",
+	} else {
+		if service.Credentials.Warning != "" {
+			credStatus = fmt.Sprintf("⚠️ %s", service.Credentials.Warning)
+		} else {
+			credStatus = "❌ Invalid"
 		}
 	}
 
-	m.table.SetRows(rows)
+	return table.Row{
+		service.Name,
+		statusText,
+		current,
+		credStatus,
+		m.switchCell(service.Name),
+		"→",
+	}
+}
+
+// switchCell renders whether a service can be switched.
+func (m *DashboardModel) switchCell(service string) string {
+	switch {
+	case m.switchable == nil:
+		return ""
+	case m.switchable[strings.ToLower(service)]:
+		return "✓"
+	default:
+		return "✗"
+	}
+}
+
+// layout returns the panels to render at the dashboard's size. A size of
+// zero, before the first WindowSizeMsg, renders everything.
+func (m *DashboardModel) layout() dashboardLayout {
+	l := dashboardLayout{
+		narrow: m.width > 0 && m.width < narrowWidth,
+		short:  m.height > 0 && m.height < shortHeight,
+	}
+	l.compact = (l.narrow && l.short) || (m.width > 0 && m.width < tableWidth(narrowColumns, minCurrentWidth))
+	return l
+}
+
+// tableWidth returns how wide the table renders columns with the Current
+// column current wide, including each cell's padding.
+func tableWidth(columns []table.Column, current int) int {
+	width := current + 2*len(columns)
+	for i, column := range columns {
+		if i != currentColumn {
+			width += column.Width
+		}
+	}
+	return width
 }
 
 // updateTableSize updates the table size based on terminal dimensions.
 func (m *DashboardModel) updateTableSize() {
-	if m.width < 80 {
-		// Adjust column widths for smaller terminals
-		columns := []table.Column{
-			{Title: "Service", Width: 10},
-			{Title: "Status", Width: 10},
-			{Title: "Current", Width: 20},
-			{Title: "Creds", Width: 12},
-			{Title: "", Width: 2},
-		}
-		m.table.SetColumns(columns)
+	l := m.layout()
+	m.help.Width = m.width
+
+	// Abbreviate the titles on narrow terminals, and shrink the Current
+	// column to keep the table within the panels' width.
+	base := wideColumns
+	if l.narrow {
+		base = narrowColumns
+	}
+	columns := append([]table.Column(nil), base...)
+	if m.width > 0 {
+		spare := m.width - 2 - tableWidth(columns, 0)
+		columns[currentColumn].Width = max(min(columns[currentColumn].Width, spare), minCurrentWidth)
 	}
+	m.table.SetColumns(columns)
 
-	// Adjust table height
-	availableHeight := m.height - 8 // Reserve space for header, footer, help
+	// Adjust table height, reserving space for the header, quick actions,
+	// and help
+	reserved := 8
+	if l.short {
+		reserved--
+	}
+	availableHeight := m.height - reserved
 	if availableHeight < 5 {
 		availableHeight = 5
 	}
@@ -348,6 +659,9 @@ func (m *DashboardModel) selectService() tea.Cmd {
 	}
 
 	serviceName := selectedRow[0]
+	if strings.HasPrefix(serviceName, groupRowPrefix) {
+		return nil
+	}
 	var selectedService *status.ServiceStatus
 
 	for _, service := range m.services {
@@ -9,7 +9,10 @@ import (
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 
+	"github.com/gizzahub/gzh-cli-dev-env/internal/testutil"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -404,6 +407,28 @@ func TestDashboardModel_RenderHeader(t *testing.T) {
 	}
 }
 
+// TestDashboardModel_RenderHeader_NextExpiry tests that the header names
+// the credential expiring soonest.
+func TestDashboardModel_RenderHeader_NextExpiry(t *testing.T) {
+	model := NewDashboardModel()
+	model.width = 100
+	model.now = time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	model.services = []status.ServiceStatus{
+		{Name: "gcp", Credentials: status.CredentialStatus{Valid: true, ExpiresAt: model.now.Add(90 * time.Minute)}},
+		{Name: "aws", Credentials: status.CredentialStatus{Valid: true, ExpiresAt: model.now.Add(12 * time.Minute)}},
+		{Name: "kubernetes", Credentials: status.CredentialStatus{Valid: true, ExpiresAt: model.now.Add(5 * time.Hour)}},
+	}
+
+	if header := model.renderHeader(); !strings.Contains(header, "Next expiry: aws in 12m") {
+		t.Errorf("header should name the soonest expiry, got:\n%s", header)
+	}
+
+	model.services = model.services[2:]
+	if header := model.renderHeader(); strings.Contains(header, "Next expiry") {
+		t.Errorf("header should omit expiries outside the warning window, got:\n%s", header)
+	}
+}
+
 // TestDashboardModel_RenderQuickActions tests renderQuickActions method.
 func TestDashboardModel_RenderQuickActions(t *testing.T) {
 	model := NewDashboardModel()
@@ -430,3 +455,309 @@ type testError struct {
 func (e *testError) Error() string {
 	return e.message
 }
+
+// TestDashboardModel_Update_ClockTick tests that clock ticks update only the header.
+func TestDashboardModel_Update_ClockTick(t *testing.T) {
+	model := NewDashboardModel()
+	model.loading = false
+	model.width = 100
+	model.height = 30
+	model.View()
+
+	tick := time.Date(2025, 1, 1, 12, 34, 56, 0, time.Local)
+	_, cmd := model.Update(ClockTickMsg{Time: tick})
+	if cmd != nil {
+		t.Error("ClockTickMsg should not produce commands")
+	}
+	if !model.bodyValid {
+		t.Error("ClockTickMsg should keep the rendered body")
+	}
+	if view := model.View(); !strings.Contains(view, "Clock: 12:34:56") {
+		t.Errorf("View should show the ticked clock, got:\n%s", view)
+	}
+
+	model.Update(LoadingMsg{Loading: false})
+	if model.bodyValid {
+		t.Error("other messages should invalidate the rendered body")
+	}
+}
+
+// TestDashboardModel_SetSwitchableServices tests the Switch column.
+func TestDashboardModel_SetSwitchableServices(t *testing.T) {
+	model := NewDashboardModel()
+	model.updateServices([]status.ServiceStatus{
+		{Name: "aws", Status: status.StatusActive},
+		{Name: "ssh", Status: status.StatusActive},
+	})
+
+	switchColumn := len(model.table.Rows()[0]) - 2
+	if got := model.table.Rows()[0][switchColumn]; got != "" {
+		t.Errorf("Switch cell before SetSwitchableServices = %q, want blank", got)
+	}
+
+	model.SetSwitchableServices([]string{"aws"})
+	rows := model.table.Rows()
+	if got := rows[0][switchColumn]; got != "✓" {
+		t.Errorf("aws Switch cell = %q, want %q", got, "✓")
+	}
+	if got := rows[1][switchColumn]; got != "✗" {
+		t.Errorf("ssh Switch cell = %q, want %q", got, "✗")
+	}
+}
+
+// TestDashboardModel_ToggleGroup tests that grouping inserts a heading row per category.
+func TestDashboardModel_ToggleGroup(t *testing.T) {
+	m := NewDashboardModel()
+	m.updateServices([]status.ServiceStatus{
+		{Name: "docker", Status: status.StatusActive, Category: status.CategoryContainer},
+		{Name: "aws", Status: status.StatusActive, Category: status.CategoryCloud},
+		{Name: "gcp", Status: status.StatusError, Category: status.CategoryCloud},
+		{Name: "custom", Status: status.StatusActive},
+	})
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+
+	var got []string
+	for _, row := range m.table.Rows() {
+		got = append(got, row[0]+"|"+row[1])
+	}
+	want := []string{
+		"▸ Cloud|1/2 active",
+		"aws|" + GetStatusIcon("active") + " active",
+		"gcp|" + GetStatusIcon("error") + " error",
+		"▸ Containers|1/1 active",
+		"docker|" + GetStatusIcon("active") + " active",
+		"▸ Custom|1/1 active",
+		"custom|" + GetStatusIcon("active") + " active",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("rows =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	m.table.SetCursor(0)
+	if cmd := m.selectService(); cmd != nil {
+		t.Error("selectService() on a heading row should do nothing")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if rows := m.table.Rows(); len(rows) != 4 || rows[0][0] != "docker" {
+		t.Errorf("rows after toggling back = %v, want the flat list in collection order", rows)
+	}
+}
+
+// TestDashboardModel_Timings tests the refresh summary in the footer and
+// the per-service breakdown toggled with the Timings key.
+func TestDashboardModel_Timings(t *testing.T) {
+	m := NewDashboardModel()
+	m.width = 120
+	m, _ = m.Update(StatusUpdateMsg{
+		Statuses: []status.ServiceStatus{{Name: "aws", Status: status.StatusActive}},
+		Timings: &RefreshTimings{
+			Total: 7900 * time.Millisecond,
+			Services: []status.ServiceTiming{
+				{Service: "azure", Duration: 6200 * time.Millisecond, Status: status.StatusError},
+				{Service: "aws", Duration: 800 * time.Millisecond, Status: status.StatusActive},
+			},
+		},
+	})
+
+	view := m.View()
+	if !strings.Contains(view, "refresh 7.9s (azure 6.2s)") {
+		t.Errorf("View() should show the refresh summary, got:\n%s", view)
+	}
+	if strings.Contains(view, "Check Timings") {
+		t.Error("View() should not show the breakdown until toggled")
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("t")})
+	view = m.View()
+	_, breakdown, found := strings.Cut(view, "Check Timings (refresh 7.9s):")
+	azure, aws := strings.Index(breakdown, "azure"), strings.Index(breakdown, "aws")
+	if !found || azure < 0 || aws < azure || !strings.Contains(breakdown, "6.2s  error") {
+		t.Errorf("View() should list services slowest first, got:\n%s", view)
+	}
+
+	// A refresh without timings keeps the last ones.
+	m, _ = m.Update(StatusUpdateMsg{Statuses: m.services})
+	if m.timings == nil {
+		t.Error("timings should survive an update without timings")
+	}
+}
+
+// TestDashboardModel_ReasonStatus tests that services that are not active
+// for a known reason show it in the Status column.
+func TestDashboardModel_ReasonStatus(t *testing.T) {
+	m := NewDashboardModel()
+	m.updateServices([]status.ServiceStatus{
+		{Name: "aws", Status: status.StatusInactive, Reason: status.ReasonNotInstalled},
+		{Name: "kubernetes", Status: status.StatusInactive, Reason: status.ReasonCredentialsInvalid},
+		{Name: "docker", Status: status.StatusInactive},
+	})
+
+	want := []string{"📦 missing", "🔑 bad auth", GetStatusIcon("inactive") + " inactive"}
+	for i, row := range m.table.Rows() {
+		if row[1] != want[i] {
+			t.Errorf("%s Status cell = %q, want %q", row[0], row[1], want[i])
+		}
+	}
+}
+
+
+// TestDashboardModel_View_Sizes renders the dashboard at small, standard,
+// and large terminal sizes, checking that every line fits the terminal and
+// each panel appears at most once.
+func TestDashboardModel_View_Sizes(t *testing.T) {
+	at := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	services := []status.ServiceStatus{
+		{Name: "aws", Status: status.StatusActive, Current: status.CurrentConfig{Context: "production-admin-role"}, Credentials: status.CredentialStatus{Valid: true}, CheckedAt: at},
+		{Name: "kubernetes", Status: status.StatusActive, Current: status.CurrentConfig{Context: "prod-cluster"}, Credentials: status.CredentialStatus{Valid: true}, CheckedAt: at},
+		{Name: "gcp", Status: status.StatusInactive, Reason: status.ReasonNotConfigured, CheckedAt: at},
+		{Name: "docker", Status: status.StatusInactive, Reason: status.ReasonNotRunning, CheckedAt: at},
+	}
+
+	tests := []struct {
+		width, height int
+		golden        string
+		panels        []string
+		absent        []string
+	}{
+		{60, 15, "dashboard_60x15.golden", []string{"dev-env: production", "> aws: "}, []string{"Quick Actions", "toggle help", "Credentials"}},
+		{80, 24, "dashboard_80x24.golden", []string{"GZH Development Environment Manager", "Credentials", "Quick Actions", "[Enter] Service Details", "toggle help"}, nil},
+		{120, 40, "dashboard_120x40.golden", []string{"GZH Development Environment Manager", "Credentials", "Quick Actions", "[Enter] Service Details", "toggle help"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			model := NewDashboardModel()
+			model.SetSwitchableServices([]string{"aws", "kubernetes"})
+			model.Update(WindowSizeMsg{Width: tt.width, Height: tt.height})
+			model.Update(StatusUpdateMsg{Statuses: services, CurrentEnv: "production"})
+			model.now = at
+
+			view := model.View()
+			lines := strings.Split(view, "\n")
+			if len(lines) > tt.height {
+				t.Errorf("View() has %d lines, want at most %d", len(lines), tt.height)
+			}
+			for i, line := range lines {
+				if width := lipgloss.Width(line); width > tt.width {
+					t.Errorf("line %d is %d columns wide, want at most %d: %q", i+1, width, tt.width, line)
+				}
+			}
+			for _, panel := range tt.panels {
+				if n := strings.Count(view, panel); n != 1 {
+					t.Errorf("View() contains %q %d times, want once", panel, n)
+				}
+			}
+			for _, panel := range tt.absent {
+				if strings.Contains(view, panel) {
+					t.Errorf("View() contains %q, want it hidden at %dx%d", panel, tt.width, tt.height)
+				}
+			}
+
+			testutil.AssertGolden(t, tt.golden, ansi.Strip(view)+"\n")
+		})
+	}
+}
+
+// TestDashboardModel_Layout tests the breakpoints between the full,
+// narrow, short, and compact layouts.
+func TestDashboardModel_Layout(t *testing.T) {
+	tests := []struct {
+		width, height int
+		want          dashboardLayout
+	}{
+		{0, 0, dashboardLayout{}},
+		{120, 40, dashboardLayout{}},
+		{80, 24, dashboardLayout{}},
+		{79, 24, dashboardLayout{narrow: true}},
+		{100, 19, dashboardLayout{short: true}},
+		{60, 15, dashboardLayout{narrow: true, short: true, compact: true}},
+		{50, 40, dashboardLayout{narrow: true, compact: true}},
+	}
+
+	for _, tt := range tests {
+		model := NewDashboardModel()
+		model.width, model.height = tt.width, tt.height
+		if got := model.layout(); got != tt.want {
+			t.Errorf("layout() at %dx%d = %+v, want %+v", tt.width, tt.height, got, tt.want)
+		}
+	}
+}
+
+// TestDashboardModel_CompactScroll tests that the compact list keeps the
+// cursor row visible on a terminal shorter than the list.
+func TestDashboardModel_CompactScroll(t *testing.T) {
+	model := NewDashboardModel()
+	model.Update(WindowSizeMsg{Width: 60, Height: 4})
+	model.Update(StatusUpdateMsg{Statuses: []status.ServiceStatus{
+		{Name: "aws"}, {Name: "azure"}, {Name: "gcp"}, {Name: "docker"}, {Name: "ssh"},
+	}})
+	model.table.SetCursor(4)
+
+	view := ansi.Strip(model.View())
+	if lines := strings.Split(view, "\n"); len(lines) != 4 {
+		t.Errorf("View() has %d lines, want 4:\n%s", len(lines), view)
+	}
+	if !strings.Contains(view, "> ssh: ") || strings.Contains(view, "aws: ") {
+		t.Errorf("View() should scroll to the cursor row, got:\n%s", view)
+	}
+}
+
+// TestWrapItems tests wrapping quick actions between items.
+func TestWrapItems(t *testing.T) {
+	items := []string{"[1] One", "[2] Two", "[3] Three"}
+	tests := []struct {
+		width int
+		want  []string
+	}{
+		{0, []string{"[1] One  [2] Two  [3] Three"}},
+		{27, []string{"[1] One  [2] Two  [3] Three"}},
+		{20, []string{"[1] One  [2] Two", "[3] Three"}},
+		{5, []string{"[1] One", "[2] Two", "[3] Three"}},
+	}
+
+	for _, tt := range tests {
+		if got := wrapItems(items, tt.width); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wrapItems(%d) = %q, want %q", tt.width, got, tt.want)
+		}
+	}
+}
+
+// TestDashboardModel_Changes tests that the footer lists what changed in
+// the last refresh, but not the first statuses.
+func TestDashboardModel_Changes(t *testing.T) {
+	model := NewDashboardModel()
+	model.width = 120
+
+	model.Update(StatusUpdateMsg{Statuses: []status.ServiceStatus{
+		{Name: "aws", Status: status.StatusActive},
+		{Name: "docker", Status: status.StatusActive},
+	}})
+	if footer := model.renderQuickActions(); strings.Contains(footer, "Changed:") {
+		t.Errorf("the first statuses should not be listed as changes, got:\n%s", footer)
+	}
+
+	model.Update(StatusUpdateMsg{Statuses: []status.ServiceStatus{
+		{Name: "aws", Status: status.StatusInactive},
+		{Name: "gcp", Status: status.StatusActive},
+	}})
+	footer := model.renderQuickActions()
+	if !strings.Contains(footer, "Changed: aws: active → inactive  docker: removed  gcp: added") {
+		t.Errorf("footer should list the changes, got:\n%s", footer)
+	}
+
+	model.Update(StatusUpdateMsg{Statuses: model.services})
+	if footer := model.renderQuickActions(); strings.Contains(footer, "Changed:") {
+		t.Errorf("a refresh without changes should clear them, got:\n%s", footer)
+	}
+}
+
+// TestChangeItems tests that the footer sums up changes past the limit.
+func TestChangeItems(t *testing.T) {
+	changes := status.DiffStatuses(nil, []status.ServiceStatus{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}, {Name: "e"}})
+	want := []string{"Changed: a: added", "b: added", "c: added", "+2 more"}
+	if got := changeItems(changes); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("changeItems() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// ServiceDetailModel shows one service's status. For debugging, it can
+// toggle a scrollable pane with the raw CLI output its health check stored
+// in HealthStatus.Details.
+type ServiceDetailModel struct {
+	keymap  KeyMap
+	service string
+	status  *status.ServiceStatus
+	showRaw bool
+	raw     viewport.Model
+	width   int
+	height  int
+}
+
+// NewServiceDetailModel creates an empty detail view.
+func NewServiceDetailModel() *ServiceDetailModel {
+	return &ServiceDetailModel{
+		keymap: DefaultKeyMap,
+		raw:    viewport.New(80, 10),
+	}
+}
+
+// SetService shows service, whose status may be nil if it is not known yet.
+// The raw output pane starts hidden and scrolled to the top.
+func (d *ServiceDetailModel) SetService(service string, st *status.ServiceStatus) {
+	d.service = service
+	d.status = st
+	d.showRaw = false
+	d.raw.SetContent(rawOutput(st))
+	d.raw.GotoTop()
+}
+
+// Update handles messages for the detail view.
+func (d *ServiceDetailModel) Update(msg tea.Msg) (*ServiceDetailModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if key.Matches(msg, d.keymap.ToggleRaw) {
+			d.showRaw = !d.showRaw
+			return d, nil
+		}
+		if d.showRaw {
+			var cmd tea.Cmd
+			d.raw, cmd = d.raw.Update(msg)
+			return d, cmd
+		}
+
+	case StatusUpdateMsg:
+		// Keep showing fresh data, without losing the scroll position.
+		for i := range msg.Statuses {
+			if msg.Statuses[i].Name == d.service {
+				st := msg.Statuses[i]
+				d.status = &st
+				d.raw.SetContent(rawOutput(d.status))
+			}
+		}
+
+	case WindowSizeMsg:
+		d.width = msg.Width
+		d.height = msg.Height
+		d.raw.Width = max(msg.Width-4, 20)
+		// Leave room for the summary above the pane and the hint below it.
+		d.raw.Height = max(msg.Height-14, 5)
+	}
+
+	return d, nil
+}
+
+// View renders the detail view.
+func (d *ServiceDetailModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render("Service: " + d.service))
+	b.WriteString("\n\n")
+
+	if d.status == nil {
+		b.WriteString("No status collected yet. Press 'r' on the dashboard to refresh.\n")
+	} else {
+		st := d.status
+		fmt.Fprintf(&b, "Status:      %s %s\n", GetStatusIcon(strings.ToLower(string(st.Status))), st.Status)
+		if st.Reason != "" {
+			reason := strings.TrimSpace(GetReasonIcon(string(st.Reason)) + " " + status.ReasonTitle(st.Reason))
+			fmt.Fprintf(&b, "Reason:      %s\n", GetReasonStyle(string(st.Reason)).Render(reason))
+		}
+		if current := currentSummary(*st); current != "" {
+			fmt.Fprintf(&b, "Current:     %s\n", current)
+		}
+		credentials := "invalid"
+		if st.Credentials.Valid {
+			credentials = "valid"
+		}
+		if st.Credentials.Type != "" {
+			credentials += " (" + st.Credentials.Type + ")"
+		}
+		fmt.Fprintf(&b, "Credentials: %s\n", credentials)
+		if st.Credentials.Warning != "" {
+			fmt.Fprintf(&b, "Warning:     %s\n", st.Credentials.Warning)
+		}
+		if st.HealthCheck != nil {
+			fmt.Fprintf(&b, "Health:      %s %s (%v)\n", st.HealthCheck.Status, st.HealthCheck.Message, st.HealthCheck.Duration)
+			for _, check := range st.HealthCheck.Checks {
+				fmt.Fprintf(&b, "             %s %s: %s (%v)\n", GetStatusIcon(string(check.Status)), check.Name, check.Message, check.Duration.Round(time.Millisecond))
+			}
+		}
+		if st.Hint != "" {
+			fmt.Fprintf(&b, "Hint:        %s\n", st.Hint)
+		}
+	}
+
+	if d.showRaw {
+		b.WriteString("\n")
+		b.WriteString(InfoStyle.Render(fmt.Sprintf("Raw output (%.0f%%)", d.raw.ScrollPercent()*100)))
+		b.WriteString("\n")
+		b.WriteString(d.raw.View())
+		b.WriteString("\n\n↑/↓ scroll • o hide raw output • esc back")
+	} else {
+		b.WriteString("\no show raw output • esc back")
+	}
+
+	return b.String()
+}
+
+// rawOutput renders a status's health check details, one section per key
+// in key order, for the raw output pane.
+func rawOutput(st *status.ServiceStatus) string {
+	if st == nil || st.HealthCheck == nil || len(st.HealthCheck.Details) == 0 {
+		return "No raw output recorded. Raw output comes from health checks."
+	}
+
+	details := st.HealthCheck.Details
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	sections := make([]string, 0, len(keys))
+	for _, k := range keys {
+		value := strings.TrimRight(fmt.Sprint(details[k]), "\n")
+		sections = append(sections, fmt.Sprintf("── %s ──\n%s", k, value))
+	}
+	return strings.Join(sections, "\n\n")
+}
+
+// currentSummary joins the set fields of a service's current configuration.
+func currentSummary(st status.ServiceStatus) string {
+	current := st.Current
+	fields := []struct{ name, value string }{
+		{"profile", current.Profile},
+		{"region", current.Region},
+		{"project", current.Project},
+		{"context", current.Context},
+		{"namespace", current.Namespace},
+		{"account", status.AccountLabel(st)},
+	}
+
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f.value != "" {
+			parts = append(parts, f.name+"="+f.value)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,130 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// detailStatus returns an AWS status whose health check recorded raw CLI output.
+func detailStatus() *status.ServiceStatus {
+	return &status.ServiceStatus{
+		Name:    "aws",
+		Status:  status.StatusActive,
+		Current: status.CurrentConfig{Profile: "dev", Region: "us-east-1"},
+		HealthCheck: &status.HealthStatus{
+			Status:  status.StatusActive,
+			Message: "AWS API accessible",
+			Details: map[string]interface{}{
+				"caller_identity": `{"UserId": "AIDA", "Account": "123456789012"}`,
+			},
+		},
+	}
+}
+
+// TestServiceDetailModel_ToggleRaw tests that raw health check output renders only when toggled.
+func TestServiceDetailModel_ToggleRaw(t *testing.T) {
+	detail := NewServiceDetailModel()
+	detail.SetService("aws", detailStatus())
+
+	view := detail.View()
+	if !strings.Contains(view, "profile=dev region=us-east-1") {
+		t.Errorf("View() should show the current configuration, got:\n%s", view)
+	}
+	if strings.Contains(view, "caller_identity") {
+		t.Errorf("View() should hide raw output until toggled, got:\n%s", view)
+	}
+
+	toggle := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}}
+	detail.Update(toggle)
+	view = detail.View()
+	for _, want := range []string{"Raw output", "── caller_identity ──", `"Account": "123456789012"`} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q after toggling, got:\n%s", want, view)
+		}
+	}
+
+	detail.Update(toggle)
+	if view := detail.View(); strings.Contains(view, "caller_identity") {
+		t.Errorf("View() should hide raw output after toggling again, got:\n%s", view)
+	}
+}
+
+// TestServiceDetailModel_Scroll tests scrolling long raw output.
+func TestServiceDetailModel_Scroll(t *testing.T) {
+	lines := make([]string, 50)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %02d", i)
+	}
+	st := detailStatus()
+	st.HealthCheck.Details = map[string]interface{}{"cluster_info": strings.Join(lines, "\n")}
+
+	detail := NewServiceDetailModel()
+	detail.Update(WindowSizeMsg{Width: 80, Height: 20})
+	detail.SetService("kubernetes", st)
+	detail.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+
+	if view := detail.View(); !strings.Contains(view, "line 00") || strings.Contains(view, "line 49") {
+		t.Fatalf("View() should start at the top, got:\n%s", view)
+	}
+
+	for i := 0; i < 60; i++ {
+		detail.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	if view := detail.View(); strings.Contains(view, "line 00") || !strings.Contains(view, "line 49") {
+		t.Errorf("View() should be scrolled to the bottom, got:\n%s", view)
+	}
+}
+
+// TestServiceDetailModel_SubChecks tests that each health sub-check is
+// listed under the health summary.
+func TestServiceDetailModel_SubChecks(t *testing.T) {
+	st := detailStatus()
+	st.HealthCheck.Checks = []status.SubCheck{
+		{Name: "connectivity", Status: status.StatusActive, Message: "Kubernetes cluster is accessible", Duration: 12 * time.Millisecond},
+		{Name: "nodes", Status: status.StatusError, Message: "1 of 3 nodes not ready: node-2", Duration: 8 * time.Millisecond},
+	}
+
+	detail := NewServiceDetailModel()
+	detail.SetService("kubernetes", st)
+	view := detail.View()
+	for _, want := range []string{
+		"✅ connectivity: Kubernetes cluster is accessible (12ms)",
+		"🔴 nodes: 1 of 3 nodes not ready: node-2 (8ms)",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q, got:\n%s", want, view)
+		}
+	}
+}
+
+// TestServiceDetailModel_NoRawOutput tests the raw pane without health check details.
+func TestServiceDetailModel_NoRawOutput(t *testing.T) {
+	detail := NewServiceDetailModel()
+	detail.SetService("docker", &status.ServiceStatus{Name: "docker", Status: status.StatusInactive})
+	detail.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+
+	if view := detail.View(); !strings.Contains(view, "No raw output recorded") {
+		t.Errorf("View() should explain the missing raw output, got:\n%s", view)
+	}
+}
+
+// TestModel_ServiceDetailRawOutput tests toggling raw output through the main model.
+func TestModel_ServiceDetailRawOutput(t *testing.T) {
+	model := NewModel(context.Background())
+	model.Update(ServiceSelectedMsg{Service: "aws", Status: detailStatus()})
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'o'}})
+
+	if view := model.View(); !strings.Contains(view, "caller_identity") {
+		t.Errorf("View() should show raw output, got:\n%s", view)
+	}
+}
@@ -4,4 +4,6 @@
 // This package implements:
 //   - Dashboard: Main TUI dashboard using Bubbletea
 //   - Model: TUI state management
+//   - ServiceDetailModel: Service details with a toggleable raw CLI output pane
+//   - TargetPickerModel: Quick-switch picker backed by a target cache
 package tui
@@ -21,6 +21,9 @@ type KeyMap struct {
 	SwitchEnv    key.Binding
 	ViewLogs     key.Binding
 	ViewSettings key.Binding
+	ToggleRaw    key.Binding
+	ToggleGroup  key.Binding
+	Timings      key.Binding
 	QuickAction1 key.Binding
 	QuickAction2 key.Binding
 	QuickAction3 key.Binding
@@ -84,6 +87,18 @@ var DefaultKeyMap = KeyMap{
 		key.WithKeys("P"),
 		key.WithHelp("P", "preferences/settings"),
 	),
+	ToggleRaw: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "toggle raw output"),
+	),
+	ToggleGroup: key.NewBinding(
+		key.WithKeys("g"),
+		key.WithHelp("g", "group by category"),
+	),
+	Timings: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "check timings"),
+	),
 	QuickAction1: key.NewBinding(
 		key.WithKeys("1"),
 		key.WithHelp("1", "quick action 1"),
@@ -103,18 +118,30 @@ func (k KeyMap) ShortHelp() []key.Binding {
 	return []key.Binding{k.Help, k.Quit}
 }
 
-// FullHelp returns key bindings for the expanded help view.
-func (k KeyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right},                  // navigation
-		{k.Enter, k.Back, k.Quit, k.Help},                // actions
-		{k.Refresh, k.Search, k.Filter},                  // utilities
-		{k.SwitchEnv, k.ViewLogs, k.ViewSettings},        // views
-		{k.QuickAction1, k.QuickAction2, k.QuickAction3}, // quick actions
+// HelpSection is a titled group of key bindings in the help overlay.
+type HelpSection struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+// HelpSections returns the key bindings grouped for the help overlay and
+// the expanded help view.
+func (k KeyMap) HelpSections() []HelpSection {
+	return []HelpSection{
+		{Title: "Navigation", Bindings: []key.Binding{k.Up, k.Down, k.Left, k.Right}},
+		{Title: "Actions", Bindings: []key.Binding{k.Enter, k.Back, k.Quit, k.Help}},
+		{Title: "Utilities", Bindings: []key.Binding{k.Refresh, k.Search, k.Filter, k.ToggleRaw, k.ToggleGroup, k.Timings}},
+		{Title: "Views", Bindings: []key.Binding{k.SwitchEnv, k.ViewLogs, k.ViewSettings}},
+		{Title: "Quick Actions", Bindings: []key.Binding{k.QuickAction1, k.QuickAction2, k.QuickAction3}},
 	}
 }
 
-// Enabled returns whether the keymap is enabled.
-func (k KeyMap) Enabled() bool {
-	return true
+// FullHelp returns key bindings for the expanded help view.
+func (k KeyMap) FullHelp() [][]key.Binding {
+	sections := k.HelpSections()
+	groups := make([][]key.Binding, len(sections))
+	for i, section := range sections {
+		groups[i] = section.Bindings
+	}
+	return groups
 }
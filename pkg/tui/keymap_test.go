@@ -4,7 +4,10 @@
 package tui
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
 )
 
 // TestDefaultKeyMap tests the default keymap.
@@ -52,3 +55,30 @@ func TestKeyMap_FullHelp(t *testing.T) {
 		t.Error("FullHelp() should return at least one binding group")
 	}
 }
+
+// TestHelpContent tests that the help lists the keys and descriptions
+// defined on the keymap, including rebound and disabled ones.
+func TestHelpContent(t *testing.T) {
+	content := helpContent(DefaultKeyMap)
+	for _, section := range DefaultKeyMap.HelpSections() {
+		if !strings.Contains(content, section.Title+":") {
+			t.Errorf("help is missing section %q", section.Title)
+		}
+		for _, binding := range section.Bindings {
+			if !strings.Contains(content, binding.Help().Key) || !strings.Contains(content, binding.Help().Desc) {
+				t.Errorf("help is missing %q %q", binding.Help().Key, binding.Help().Desc)
+			}
+		}
+	}
+
+	km := DefaultKeyMap
+	km.Refresh = key.NewBinding(key.WithKeys("f5"), key.WithHelp("f5", "reload everything"))
+	km.ViewLogs.SetEnabled(false)
+	content = helpContent(km)
+	if !strings.Contains(content, "f5           reload everything") {
+		t.Errorf("help should reflect the rebound refresh key, got:\n%s", content)
+	}
+	if strings.Contains(content, DefaultKeyMap.ViewLogs.Help().Desc) {
+		t.Errorf("help should leave out disabled bindings, got:\n%s", content)
+	}
+}
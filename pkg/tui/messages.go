@@ -6,6 +6,7 @@ package tui
 import (
 	"time"
 
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -16,9 +17,20 @@ type (
 		Time time.Time
 	}
 
+	// ClockTickMsg advances the header clock once per second. Unlike
+	// TickMsg it does not trigger a status refresh.
+	ClockTickMsg struct {
+		Time time.Time
+	}
+
 	// StatusUpdateMsg represents an update to service statuses.
 	StatusUpdateMsg struct {
 		Statuses []status.ServiceStatus
+		// Timings is how long the refresh took, if it was measured.
+		Timings *RefreshTimings
+		// CurrentEnv is the current environment read with the statuses,
+		// or "" if the model has no current environment marker.
+		CurrentEnv string
 	}
 
 	// ErrorMsg represents an error.
@@ -51,6 +63,24 @@ type (
 		Error       error
 	}
 
+	// TargetsLoadedMsg carries freshly listed targets for a service.
+	TargetsLoadedMsg struct {
+		Service  string
+		Snapshot environment.TargetSnapshot
+		Err      error
+	}
+
+	// RefreshTargetsMsg requests that a service's targets be listed again.
+	RefreshTargetsMsg struct {
+		Service string
+	}
+
+	// TargetSelectedMsg represents a target chosen in the quick-switch picker.
+	TargetSelectedMsg struct {
+		Service string
+		Target  string
+	}
+
 	// RefreshMsg represents a manual refresh request.
 	RefreshMsg struct{}
 
@@ -5,18 +5,17 @@ package tui
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/aws"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/azure"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/docker"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/gcp"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/kubernetes"
-	"github.com/gizzahub/gzh-cli-dev-env/pkg/ssh"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/builtin"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
 	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
@@ -31,46 +30,128 @@ type Model struct {
 
 	// View models
 	dashboardModel *DashboardModel
+	detailModel    *ServiceDetailModel
+	pickerModel    *TargetPickerModel
+
+	// Quick switching; targets are prefetched into targetCache.
+	switcher    *environment.EnvironmentSwitcher
+	targetCache environment.TargetCache
 
 	// Status management
 	statusCollector *status.StatusCollector
+	perService      map[string]status.ServiceCheckOptions
+	timings         *status.TimingRecorder
 	lastUpdate      time.Time
 	updateInterval  time.Duration
 
+	// currentPath is the current environment marker shown in the header.
+	currentPath string
+
 	// Application state
 	ctx      context.Context
 	quitting bool
+
+	// showHelp overlays the key bindings on whichever view is shown.
+	showHelp bool
 }
 
 // NewModel creates a new TUI model.
 func NewModel(ctx context.Context) *Model {
-	// Create all available service checkers
-	checkers := []status.ServiceChecker{
-		aws.NewChecker(),
-		gcp.NewChecker(),
-		azure.NewChecker(),
-		docker.NewChecker(),
-		kubernetes.NewChecker(),
-		ssh.NewChecker(),
+	return NewModelWithServices(ctx, DefaultCheckers(), DefaultSwitchers())
+}
+
+// DefaultCheckers returns the checkers of all built-in services.
+func DefaultCheckers() []status.ServiceChecker {
+	return builtin.Checkers()
+}
+
+// DefaultSwitchers returns the switchers of all built-in services, matching
+// DefaultCheckers.
+func DefaultSwitchers() []environment.ServiceSwitcher {
+	return builtin.Switchers()
+}
+
+// NewModelWithServices creates a TUI model for the given checkers and
+// switchers instead of the built-in ones, e.g. mock services for demos and
+// end-to-end tests.
+func NewModelWithServices(ctx context.Context, checkers []status.ServiceChecker, switchers []environment.ServiceSwitcher) *Model {
+	switcher := environment.NewEnvironmentSwitcher()
+	for _, s := range switchers {
+		switcher.Register(s)
 	}
 
+	targetCache := environment.NewMemoryTargetCache()
+
+	// Checkers and switchers are registered separately, so surface which
+	// services can actually be switched.
+	dashboardModel := NewDashboardModel()
+	dashboardModel.SetSwitchableServices(switcher.GetAvailableServices())
+	pickerModel := NewTargetPickerModel(targetCache)
+	pickerModel.SetSwitchableServices(switcher.GetAvailableServices())
+
+	timings := &status.TimingRecorder{}
+
 	return &Model{
 		state:           StateLoading,
 		currentView:     ViewDashboard,
 		keymap:          DefaultKeyMap,
 		help:            help.New(),
-		dashboardModel:  NewDashboardModel(),
-		statusCollector: status.NewStatusCollector(checkers, 10*time.Second),
+		dashboardModel:  dashboardModel,
+		detailModel:     NewServiceDetailModel(),
+		pickerModel:     pickerModel,
+		switcher:        switcher,
+		targetCache:     targetCache,
+		statusCollector: status.NewStatusCollector(checkers, 10*time.Second, status.WithObserver(timings.Observe)),
+		timings:         timings,
 		updateInterval:  5 * time.Second,
 		ctx:             ctx,
 	}
 }
 
+// SetCurrentMarker makes the dashboard show the environment named in the
+// marker file at path, re-read on every refresh so switches made elsewhere
+// show up.
+func (m *Model) SetCurrentMarker(path string) {
+	m.currentPath = path
+}
+
+// readCurrentEnv returns the environment in the current marker, "none" if
+// there is no marker file, or "" if no marker is set or it is unreadable.
+func (m *Model) readCurrentEnv() string {
+	if m.currentPath == "" {
+		return ""
+	}
+	name, err := environment.ReadCurrent(m.currentPath)
+	if err != nil {
+		return ""
+	}
+	if name == "" {
+		return "none"
+	}
+	return name
+}
+
+// SetSwitchLock makes switches from the TUI hold lock, so they fail
+// instead of racing a switch-all running elsewhere.
+func (m *Model) SetSwitchLock(lock *environment.SwitchLock) {
+	m.switcher.SetSwitchLock(lock)
+}
+
+// SetServiceCheckOptions sets per-service overrides of the status options
+// used on each refresh, such as skipping the health check of a slow
+// service. It returns the services named that have no checker.
+func (m *Model) SetServiceCheckOptions(perService map[string]status.ServiceCheckOptions) []string {
+	m.perService = perService
+	return status.UnknownServices(perService, m.statusCollector.GetCheckers())
+}
+
 // Init initializes the TUI application.
 func (m *Model) Init() tea.Cmd {
 	return tea.Batch(
 		m.refreshStatus(),
 		m.startUpdateTicker(),
+		m.startClockTicker(),
+		m.prefetchTargets(),
 		tea.EnterAltScreen,
 	)
 }
@@ -81,6 +162,9 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.showHelp || key.Matches(msg, m.keymap.Help) {
+			return m, m.handleHelpKeys(msg)
+		}
 		if m.handleGlobalKeys(msg) {
 			return m, tea.Quit
 		}
@@ -106,6 +190,12 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, m.refreshStatus())
 		cmds = append(cmds, m.startUpdateTicker())
 
+	case ClockTickMsg:
+		// The dashboard keeps its clock current even while another view is
+		// shown, so it is never stale when the user returns.
+		m.dashboardModel, _ = m.dashboardModel.Update(msg)
+		cmds = append(cmds, m.startClockTicker())
+
 	case StatusUpdateMsg:
 		m.lastUpdate = time.Now()
 		m.state = StateDashboard
@@ -128,12 +218,30 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.updateStateFromView()
 
 	case ServiceSelectedMsg:
+		m.detailModel.SetService(msg.Service, msg.Status)
+		m.detailModel, _ = m.detailModel.Update(WindowSizeMsg{Width: m.width, Height: m.height})
 		m.currentView = ViewServiceDetail
 		m.state = StateServiceDetail
 
 	case RefreshMsg:
 		cmds = append(cmds, m.refreshStatus())
 
+	case TargetsLoadedMsg:
+		// Loads finish in the background, whichever view is shown.
+		m.pickerModel, _ = m.pickerModel.Update(msg)
+
+	case RefreshTargetsMsg:
+		m.pickerModel, _ = m.pickerModel.Update(msg)
+		cmds = append(cmds, m.fetchTargets(msg.Service))
+
+	case TargetSelectedMsg:
+		m.currentView = ViewDashboard
+		m.state = StateDashboard
+		cmds = append(cmds, m.switchTarget(msg.Service, msg.Target))
+
+	case HelpToggleMsg:
+		m.showHelp = !m.showHelp
+
 	case QuitMsg:
 		m.quitting = true
 		return m, tea.Quit
@@ -154,14 +262,17 @@ func (m *Model) View() string {
 	if m.quitting {
 		return "Goodbye! 👋\n"
 	}
+	if m.showHelp {
+		return m.renderHelp()
+	}
 
 	switch m.currentView {
 	case ViewDashboard:
 		return m.dashboardModel.View()
 	case ViewServiceDetail:
-		return m.renderServiceDetail()
+		return m.detailModel.View()
 	case ViewEnvironmentSwitch:
-		return m.renderEnvironmentSwitch()
+		return m.pickerModel.View()
 	case ViewSettings:
 		return m.renderSettings()
 	case ViewLogs:
@@ -175,6 +286,22 @@ func (m *Model) View() string {
 	}
 }
 
+// handleHelpKeys toggles the help overlay. While it is shown, it takes
+// every key, so the view underneath does not react to keys pressed to
+// dismiss it; only help, back, and ctrl+c do anything.
+func (m *Model) handleHelpKeys(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case msg.String() == "ctrl+c":
+		m.quitting = true
+		return tea.Quit
+	case key.Matches(msg, m.keymap.Help):
+		m.showHelp = !m.showHelp
+	case key.Matches(msg, m.keymap.Back):
+		m.showHelp = false
+	}
+	return nil
+}
+
 // handleGlobalKeys handles global keyboard shortcuts.
 func (m *Model) handleGlobalKeys(msg tea.KeyMsg) bool {
 	switch msg.String() {
@@ -206,9 +333,13 @@ func (m *Model) updateCurrentView(msg tea.Msg) tea.Cmd {
 		m.dashboardModel, cmd = m.dashboardModel.Update(msg)
 		return cmd
 	case ViewServiceDetail:
-		return nil
+		var cmd tea.Cmd
+		m.detailModel, cmd = m.detailModel.Update(msg)
+		return cmd
 	case ViewEnvironmentSwitch:
-		return nil
+		var cmd tea.Cmd
+		m.pickerModel, cmd = m.pickerModel.Update(msg)
+		return cmd
 	case ViewSettings:
 		return nil
 	case ViewLogs:
@@ -249,14 +380,17 @@ func (m *Model) refreshStatus() tea.Cmd {
 			Parallel:    true,
 			CheckHealth: true,
 			Timeout:     10 * time.Second,
+			PerService:  m.perService,
 		}
 
+		start := time.Now()
 		statuses, err := m.statusCollector.CollectAll(m.ctx, options)
+		timings := &RefreshTimings{Total: time.Since(start), Services: m.timings.Take()}
 		if err != nil {
 			return ErrorMsg{Error: err}
 		}
 
-		return StatusUpdateMsg{Statuses: statuses}
+		return StatusUpdateMsg{Statuses: statuses, Timings: timings, CurrentEnv: m.readCurrentEnv()}
 	}
 }
 
@@ -267,24 +401,92 @@ func (m *Model) startUpdateTicker() tea.Cmd {
 	})
 }
 
-// Placeholder view implementations.
+// startClockTicker schedules the next header clock tick on the second
+// boundary, independently of the status update ticker.
+func (m *Model) startClockTicker() tea.Cmd {
+	return tea.Every(time.Second, func(t time.Time) tea.Msg {
+		return ClockTickMsg{Time: t}
+	})
+}
 
-func (m *Model) renderServiceDetail() string {
-	return lipgloss.Place(
-		m.width, m.height,
-		lipgloss.Center, lipgloss.Center,
-		InfoStyle.Render("Service Detail View\n(Coming Soon)\n\nPress 'esc' to go back"),
-	)
+// prefetchTargets lists every picker service's targets in the background
+// so the quick-switch picker opens instantly.
+func (m *Model) prefetchTargets() tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(m.pickerModel.services))
+	for _, service := range m.pickerModel.services {
+		cmds = append(cmds, m.fetchTargets(service))
+	}
+	return tea.Batch(cmds...)
 }
 
-func (m *Model) renderEnvironmentSwitch() string {
-	return lipgloss.Place(
-		m.width, m.height,
-		lipgloss.Center, lipgloss.Center,
-		InfoStyle.Render("Environment Switch View\n(Coming Soon)\n\nPress 'esc' to go back"),
-	)
+// fetchTargets lists a service's targets into the target cache.
+func (m *Model) fetchTargets(service string) tea.Cmd {
+	return func() tea.Msg {
+		snapshot, err := m.switcher.FetchTargets(m.ctx, service, m.targetCache)
+		return TargetsLoadedMsg{Service: service, Snapshot: snapshot, Err: err}
+	}
+}
+
+// switchTarget switches a single service to a target chosen in the picker
+// and then refreshes the status.
+func (m *Model) switchTarget(service, target string) tea.Cmd {
+	return func() tea.Msg {
+		config, err := environment.TargetConfig(service, target)
+		if err != nil {
+			return ErrorMsg{Error: err}
+		}
+
+		env := &environment.Environment{
+			Name:     fmt.Sprintf("%s:%s", service, target),
+			Services: map[string]environment.ServiceConfig{service: config},
+		}
+		if missing := m.switcher.MissingSwitchers(env); len(missing) > 0 {
+			return ErrorMsg{Error: fmt.Errorf("no switcher registered for %s", strings.Join(missing, ", "))}
+		}
+		result, err := m.switcher.SwitchEnvironment(m.ctx, env, environment.SwitchOptions{RollbackOnError: true})
+		if err != nil {
+			err = switchFailure(err, result)
+			if warning := mixedStateWarning(result, env); warning != "" {
+				err = fmt.Errorf("%w\n\n%s", err, warning)
+			}
+			return ErrorMsg{Error: err}
+		}
+		return RefreshMsg{}
+	}
+}
+
+// switchFailure adds the failures of result, with their CLI output and
+// suggested fixes, to a switch error for display.
+func switchFailure(err error, result *environment.SwitchResult) error {
+	var b strings.Builder
+	environment.NewResultReporter(&b).ReportFailures(result)
+	if b.Len() == 0 {
+		return err
+	}
+	return fmt.Errorf("%w\n\n%s", err, strings.TrimRight(b.String(), "\n"))
+}
+
+// mixedStateWarning renders which services an incomplete rollback left on
+// env, highlighted as errors, and which are on the previous environment. It
+// returns "" if the rollback completed or was not needed.
+func mixedStateWarning(result *environment.SwitchResult, env *environment.Environment) string {
+	if !result.MixedState() {
+		return ""
+	}
+
+	stranded, previous := environment.MixedStateServices(result, env)
+	lines := []string{
+		ServiceWarningStyle.Render("⚠ Rollback incomplete: services are in a mixed state"),
+		"On " + env.Name + ": " + ServiceErrorStyle.Render(strings.Join(stranded, ", ")),
+	}
+	if len(previous) > 0 {
+		lines = append(lines, "On the previous environment: "+ServiceActiveStyle.Render(strings.Join(previous, ", ")))
+	}
+	return strings.Join(lines, "\n")
 }
 
+// Placeholder view implementations.
+
 func (m *Model) renderSettings() string {
 	return lipgloss.Place(
 		m.width, m.height,
@@ -301,37 +503,34 @@ func (m *Model) renderLogs() string {
 	)
 }
 
+// renderHelp renders the help overlay.
 func (m *Model) renderHelp() string {
-	helpContent := `GZH Development Environment Manager - Help
-
-Navigation:
-  ↑/k, ↓/j     Navigate up/down
-  ←/h, →/l     Navigate left/right
-  Enter        Select/confirm
-  Esc          Go back
-  q            Quit (from dashboard)
-
-Views:
-  s            Switch environment
-  L            View logs
-  P            Settings/preferences
-  ?            Toggle help
-
-Actions:
-  r            Refresh status
-  /            Search
-  f            Filter
-  1,2,3        Quick actions
-
-Press 'esc' to go back to dashboard`
-
 	return lipgloss.Place(
 		m.width, m.height,
 		lipgloss.Center, lipgloss.Center,
-		HelpHeaderStyle.Render(helpContent),
+		HelpHeaderStyle.Render(helpContent(m.keymap)),
 	)
 }
 
+// helpContent lists keymap's bindings by section, from the keys and
+// descriptions defined on them, so the help cannot drift from the real
+// bindings. Disabled bindings are left out.
+func helpContent(keymap KeyMap) string {
+	lines := []string{"GZH Development Environment Manager - Help"}
+	for _, section := range keymap.HelpSections() {
+		lines = append(lines, "", section.Title+":")
+		for _, binding := range section.Bindings {
+			if !binding.Enabled() {
+				continue
+			}
+			h := binding.Help()
+			lines = append(lines, fmt.Sprintf("  %-12s %s", h.Key, h.Desc))
+		}
+	}
+	lines = append(lines, "", fmt.Sprintf("Press '%s' or '%s' to close", keymap.Help.Help().Key, keymap.Back.Help().Key))
+	return strings.Join(lines, "\n")
+}
+
 func (m *Model) renderSearch() string {
 	return lipgloss.Place(
 		m.width, m.height,
@@ -5,10 +5,16 @@ package tui
 
 import (
 	"context"
+	"errors"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
 )
 
 // TestNewModel tests the Model constructor.
@@ -384,3 +390,162 @@ type modelTestError struct {
 func (e *modelTestError) Error() string {
 	return e.msg
 }
+
+// TestModel_Update_ClockTickMsg tests that clock ticks reschedule themselves
+// and reach the dashboard from any view.
+func TestModel_Update_ClockTickMsg(t *testing.T) {
+	model := NewModel(context.Background())
+	model.currentView = ViewSettings
+
+	tick := time.Now().Add(time.Hour)
+	_, cmd := model.Update(ClockTickMsg{Time: tick})
+
+	if cmd == nil {
+		t.Error("ClockTickMsg should schedule the next tick")
+	}
+	if !model.dashboardModel.now.Equal(tick) {
+		t.Errorf("dashboard clock = %v, want %v", model.dashboardModel.now, tick)
+	}
+}
+
+// TestSwitchFailure tests that switch errors shown in the TUI include the reported failures.
+func TestSwitchFailure(t *testing.T) {
+	err := errors.New("failed to switch docker: exit status 1")
+
+	if got := switchFailure(err, nil); got.Error() != err.Error() {
+		t.Errorf("switchFailure() = %q, want %q", got, err)
+	}
+
+	result := &environment.SwitchResult{
+		Errors: []environment.SwitchError{{
+			Service: "docker",
+			Error:   "exit status 1",
+			Detail:  "Cannot connect to the Docker daemon",
+		}},
+	}
+	got := switchFailure(err, result)
+	if !errors.Is(got, err) {
+		t.Error("switchFailure() should wrap the switch error")
+	}
+	for _, want := range []string{"output: Cannot connect to the Docker daemon", "hint:   Start Docker"} {
+		if !strings.Contains(got.Error(), want) {
+			t.Errorf("switchFailure() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+// TestMixedStateWarning tests the warning shown after an incomplete rollback.
+func TestMixedStateWarning(t *testing.T) {
+	env := &environment.Environment{
+		Name: "production",
+		Services: map[string]environment.ServiceConfig{
+			"aws":    {AWS: &environment.AWSConfig{Profile: "prod"}},
+			"docker": {Docker: &environment.DockerConfig{Context: "prod"}},
+		},
+	}
+
+	complete := &environment.SwitchResult{RollbackPerformed: true, RollbackComplete: true}
+	if got := mixedStateWarning(complete, env); got != "" {
+		t.Errorf("mixedStateWarning() = %q, want empty after a complete rollback", got)
+	}
+
+	incomplete := &environment.SwitchResult{
+		RollbackPerformed:  true,
+		RolledBackServices: []string{"docker"},
+		RollbackErrors:     []environment.SwitchError{{Service: "aws", Error: "rollback failed: profile no longer exists"}},
+	}
+	got := mixedStateWarning(incomplete, env)
+	for _, want := range []string{
+		"Rollback incomplete",
+		"On production: " + ServiceErrorStyle.Render("aws"),
+		"On the previous environment: " + ServiceActiveStyle.Render("docker"),
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("mixedStateWarning() = %q, want it to contain %q", got, want)
+		}
+	}
+}
+
+// TestModel_HelpOverlay tests that ? toggles the help over any view without
+// leaving it, and that keys pressed while it is shown do not reach the view.
+func TestModel_HelpOverlay(t *testing.T) {
+	model := NewModel(context.Background())
+	model.currentView = ViewServiceDetail
+	model.state = StateServiceDetail
+	press := func(msg tea.KeyMsg) {
+		updated, _ := model.Update(msg)
+		model = updated.(*Model)
+	}
+	question := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")}
+
+	press(question)
+	if !model.showHelp || !strings.Contains(model.View(), DefaultKeyMap.ToggleGroup.Help().Desc) {
+		t.Fatalf("? should show the help overlay, got:\n%s", model.View())
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if !model.showHelp || model.currentView != ViewServiceDetail {
+		t.Errorf("q under the overlay should do nothing, showHelp = %v, view = %v", model.showHelp, model.currentView)
+	}
+
+	press(question)
+	if model.showHelp || model.currentView != ViewServiceDetail {
+		t.Errorf("? should close the overlay and stay on the view, showHelp = %v, view = %v", model.showHelp, model.currentView)
+	}
+
+	press(question)
+	press(tea.KeyMsg{Type: tea.KeyEsc})
+	if model.showHelp || model.currentView != ViewServiceDetail {
+		t.Errorf("esc should close the overlay and stay on the view, showHelp = %v, view = %v", model.showHelp, model.currentView)
+	}
+}
+
+// TestModel_SetServiceCheckOptions tests that per-service options are kept
+// for refreshes and unknown services are reported.
+func TestModel_SetServiceCheckOptions(t *testing.T) {
+	model := NewModel(context.Background())
+	off := false
+
+	unknown := model.SetServiceCheckOptions(map[string]status.ServiceCheckOptions{
+		"azure": {CheckHealth: &off},
+		"vault": {Timeout: time.Second},
+	})
+	if len(unknown) != 1 || unknown[0] != "vault" {
+		t.Errorf("SetServiceCheckOptions() unknown = %v, want [vault]", unknown)
+	}
+	if _, ok := model.perService["azure"]; !ok {
+		t.Error("perService should hold the azure override")
+	}
+}
+
+// TestModel_CurrentMarker tests that the dashboard header follows the
+// current environment marker.
+func TestModel_CurrentMarker(t *testing.T) {
+	model := NewModel(context.Background())
+	if got := model.readCurrentEnv(); got != "" {
+		t.Errorf("readCurrentEnv() without a marker = %q, want \"\"", got)
+	}
+
+	path := filepath.Join(t.TempDir(), "current")
+	model.SetCurrentMarker(path)
+	if got := model.readCurrentEnv(); got != "none" {
+		t.Errorf("readCurrentEnv() without a marker file = %q, want none", got)
+	}
+
+	if err := environment.WriteCurrent(path, "staging"); err != nil {
+		t.Fatal(err)
+	}
+	if got := model.readCurrentEnv(); got != "staging" {
+		t.Errorf("readCurrentEnv() = %q, want staging", got)
+	}
+
+	dashboard := NewDashboardModel()
+	dashboard, _ = dashboard.Update(StatusUpdateMsg{CurrentEnv: "staging"})
+	if !strings.Contains(dashboard.renderHeader(), "Current Environment: staging") {
+		t.Errorf("renderHeader() = %q, want the marker's environment", dashboard.renderHeader())
+	}
+	dashboard, _ = dashboard.Update(StatusUpdateMsg{})
+	if dashboard.currentEnv != "staging" {
+		t.Errorf("currentEnv = %q after an update without a marker, want it kept", dashboard.currentEnv)
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// pickerServices are the services offered in the quick-switch picker.
+var pickerServices = []string{"aws", "gcp", "azure", "docker", "kubernetes"}
+
+// TargetPickerModel is the quick-switch picker. It renders targets from a
+// TargetCache, so opening it is instant; the data may be stale and is
+// labelled with when it was fetched.
+type TargetPickerModel struct {
+	keymap   KeyMap
+	cache    environment.TargetCache
+	services []string
+	service  int
+	cursor   int
+	loading  map[string]bool
+	errors   map[string]string
+
+	// switchable lists the services with a registered switcher. While nil,
+	// every service is assumed switchable.
+	switchable map[string]bool
+}
+
+// NewTargetPickerModel creates a picker reading from cache.
+func NewTargetPickerModel(cache environment.TargetCache) *TargetPickerModel {
+	return &TargetPickerModel{
+		keymap:   DefaultKeyMap,
+		cache:    cache,
+		services: pickerServices,
+		loading:  make(map[string]bool),
+		errors:   make(map[string]string),
+	}
+}
+
+// SetSwitchableServices records which services have a registered switcher.
+// The picker warns about the others and does not offer to switch them.
+func (p *TargetPickerModel) SetSwitchableServices(services []string) {
+	p.switchable = make(map[string]bool, len(services))
+	for _, service := range services {
+		p.switchable[service] = true
+	}
+}
+
+// Update handles messages for the picker.
+func (p *TargetPickerModel) Update(msg tea.Msg) (*TargetPickerModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, p.keymap.Left):
+			p.service = (p.service + len(p.services) - 1) % len(p.services)
+			p.cursor = 0
+		case key.Matches(msg, p.keymap.Right):
+			p.service = (p.service + 1) % len(p.services)
+			p.cursor = 0
+		case key.Matches(msg, p.keymap.Up):
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case key.Matches(msg, p.keymap.Down):
+			if p.cursor < len(p.targets())-1 {
+				p.cursor++
+			}
+		case key.Matches(msg, p.keymap.Refresh):
+			service := p.currentService()
+			p.loading[service] = true
+			return p, func() tea.Msg {
+				return RefreshTargetsMsg{Service: service}
+			}
+		case key.Matches(msg, p.keymap.Enter):
+			targets := p.targets()
+			if p.cursor >= len(targets) || !p.canSwitch(p.currentService()) {
+				return p, nil
+			}
+			selected := TargetSelectedMsg{Service: p.currentService(), Target: targets[p.cursor]}
+			return p, func() tea.Msg {
+				return selected
+			}
+		}
+
+	case RefreshTargetsMsg:
+		p.loading[msg.Service] = true
+
+	case TargetsLoadedMsg:
+		delete(p.loading, msg.Service)
+		if msg.Err != nil {
+			p.errors[msg.Service] = msg.Err.Error()
+		} else {
+			delete(p.errors, msg.Service)
+		}
+		if n := len(p.targets()); p.cursor >= n && n > 0 {
+			p.cursor = n - 1
+		}
+	}
+
+	return p, nil
+}
+
+// View renders the picker.
+func (p *TargetPickerModel) View() string {
+	var b strings.Builder
+
+	b.WriteString(TitleStyle.Render("Quick Switch"))
+	b.WriteString("\n")
+
+	tabs := make([]string, len(p.services))
+	for i, service := range p.services {
+		if i == p.service {
+			tabs[i] = InfoStyle.Render("[" + service + "]")
+		} else {
+			tabs[i] = " " + service + " "
+		}
+	}
+	b.WriteString(strings.Join(tabs, " "))
+	b.WriteString("\n\n")
+
+	service := p.currentService()
+	snapshot, cached := p.cache.Get(service)
+
+	if !p.canSwitch(service) {
+		b.WriteString(ServiceWarningStyle.Render(fmt.Sprintf("⚠ No switcher registered for %s; its targets cannot be switched to", service)))
+		b.WriteString("\n")
+	}
+
+	switch {
+	case p.loading[service] && !cached:
+		b.WriteString("Loading targets...\n")
+	case !cached && p.errors[service] != "":
+		b.WriteString(ErrorStyle.Render("Error: "+p.errors[service]) + "\n")
+	case !cached:
+		b.WriteString("No targets loaded. Press 'r' to fetch.\n")
+	default:
+		status := fmt.Sprintf("as of %s", snapshot.FetchedAt.Format("15:04"))
+		if p.loading[service] {
+			status += " (refreshing...)"
+		}
+		if p.errors[service] != "" {
+			status += " (refresh failed: " + p.errors[service] + ")"
+		}
+		b.WriteString(FooterStyle.Render(status))
+		b.WriteString("\n")
+
+		if len(snapshot.Targets) == 0 {
+			b.WriteString("No targets found\n")
+		}
+		for i, target := range snapshot.Targets {
+			if i == p.cursor {
+				b.WriteString(TableSelectedStyle.Render("> " + target))
+			} else {
+				b.WriteString("  " + target)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n←/→ service • ↑/↓ select • enter switch • r refresh • esc back")
+	return b.String()
+}
+
+// currentService returns the service whose targets are shown.
+func (p *TargetPickerModel) currentService() string {
+	return p.services[p.service]
+}
+
+// canSwitch reports whether service has a registered switcher.
+func (p *TargetPickerModel) canSwitch(service string) bool {
+	return p.switchable == nil || p.switchable[service]
+}
+
+// targets returns the cached targets of the current service.
+func (p *TargetPickerModel) targets() []string {
+	snapshot, _ := p.cache.Get(p.currentService())
+	return snapshot.Targets
+}
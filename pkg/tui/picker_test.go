@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/environment"
+)
+
+// TestTargetPickerModel_View tests rendering cached targets with their age.
+func TestTargetPickerModel_View(t *testing.T) {
+	cache := environment.NewMemoryTargetCache()
+	fetchedAt := time.Date(2025, 1, 1, 14, 2, 0, 0, time.Local)
+	_ = cache.Set("aws", environment.TargetSnapshot{Targets: []string{"dev", "prod"}, FetchedAt: fetchedAt})
+
+	picker := NewTargetPickerModel(cache)
+	view := picker.View()
+
+	for _, want := range []string{"as of 14:02", "dev", "prod"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("View() should contain %q, got:\n%s", want, view)
+		}
+	}
+
+	// Switch to gcp, which has nothing cached yet and is still loading.
+	picker.Update(RefreshTargetsMsg{Service: "gcp"})
+	picker.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := picker.View(); !strings.Contains(view, "Loading targets") {
+		t.Errorf("View() should show loading state, got:\n%s", view)
+	}
+}
+
+// TestTargetPickerModel_Keys tests refreshing and selecting targets.
+func TestTargetPickerModel_Keys(t *testing.T) {
+	cache := environment.NewMemoryTargetCache()
+	_ = cache.Set("aws", environment.TargetSnapshot{Targets: []string{"dev", "prod"}, FetchedAt: time.Now()})
+	picker := NewTargetPickerModel(cache)
+
+	_, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'r'}})
+	if cmd == nil {
+		t.Fatal("refresh key should produce a command")
+	}
+	if msg, ok := cmd().(RefreshTargetsMsg); !ok || msg.Service != "aws" {
+		t.Errorf("refresh key produced %#v, want RefreshTargetsMsg for aws", msg)
+	}
+	if !strings.Contains(picker.View(), "refreshing") {
+		t.Error("View() should show stale data while refreshing")
+	}
+
+	picker.Update(TargetsLoadedMsg{Service: "aws"})
+	picker.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = picker.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter should produce a command")
+	}
+	if msg, ok := cmd().(TargetSelectedMsg); !ok || msg.Service != "aws" || msg.Target != "prod" {
+		t.Errorf("enter produced %#v, want TargetSelectedMsg aws/prod", msg)
+	}
+}
+
+// TestTargetPickerModel_NoSwitcher tests that services without a switcher are flagged and not switched.
+func TestTargetPickerModel_NoSwitcher(t *testing.T) {
+	cache := environment.NewMemoryTargetCache()
+	_ = cache.Set("aws", environment.TargetSnapshot{Targets: []string{"dev"}, FetchedAt: time.Now()})
+	picker := NewTargetPickerModel(cache)
+	picker.SetSwitchableServices([]string{"gcp"})
+
+	if view := picker.View(); !strings.Contains(view, "No switcher registered for aws") {
+		t.Errorf("View() should warn about aws, got:\n%s", view)
+	}
+	if _, cmd := picker.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Errorf("enter produced %#v, want no switch for aws", cmd())
+	}
+
+	picker.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if view := picker.View(); strings.Contains(view, "No switcher registered") {
+		t.Errorf("View() should not warn about gcp, got:\n%s", view)
+	}
+}
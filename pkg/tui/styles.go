@@ -115,3 +115,36 @@ func GetStatusIcon(status string) string {
 		return "❓"
 	}
 }
+
+// GetReasonIcon returns the icon for why a service is not active, as in
+// status.ServiceStatus.Reason, or "" for no or an unknown reason.
+func GetReasonIcon(reason string) string {
+	switch reason {
+	case "notInstalled":
+		return "📦"
+	case "notConfigured":
+		return "⚙️"
+	case "notRunning":
+		return "💤"
+	case "credentialsInvalid":
+		return "🔑"
+	default:
+		return ""
+	}
+}
+
+// GetReasonStyle returns the style for why a service is not active.
+func GetReasonStyle(reason string) lipgloss.Style {
+	switch reason {
+	case "notInstalled":
+		return ServiceUnknownStyle
+	case "notConfigured":
+		return lipgloss.NewStyle().Foreground(ColorSecondary)
+	case "notRunning":
+		return ServiceWarningStyle
+	case "credentialsInvalid":
+		return ServiceErrorStyle
+	default:
+		return ServiceInactiveStyle
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Archmagece
+// SPDX-License-Identifier: MIT
+
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gizzahub/gzh-cli-dev-env/pkg/status"
+)
+
+// slowRefresh is how long a status refresh can take before the footer
+// highlights it.
+const slowRefresh = 5 * time.Second
+
+// RefreshTimings records how long a status refresh took and how long each
+// service check took within it.
+type RefreshTimings struct {
+	Total time.Duration
+	// Services are the check timings, slowest first.
+	Services []status.ServiceTiming
+}
+
+// Slowest returns the slowest service check, if any were recorded.
+func (t *RefreshTimings) Slowest() (status.ServiceTiming, bool) {
+	if t == nil || len(t.Services) == 0 {
+		return status.ServiceTiming{}, false
+	}
+	return t.Services[0], true
+}
+
+// Summary renders the refresh duration and the slowest service, such as
+// "refresh 7.9s (azure 6.2s)".
+func (t *RefreshTimings) Summary() string {
+	summary := "refresh " + seconds(t.Total)
+	if slowest, ok := t.Slowest(); ok {
+		summary += fmt.Sprintf(" (%s %s)", slowest.Service, seconds(slowest.Duration))
+	}
+	return summary
+}
+
+// Breakdown renders one line per service check, slowest first.
+func (t *RefreshTimings) Breakdown() string {
+	lines := []string{fmt.Sprintf("Check Timings (refresh %s):", seconds(t.Total))}
+	if len(t.Services) == 0 {
+		lines = append(lines, "  no checks recorded")
+	}
+	for _, timing := range t.Services {
+		line := fmt.Sprintf("  %-12s %7s  %s", timing.Service, seconds(timing.Duration), timing.Status)
+		if timing.Err != nil {
+			line += "  " + timing.Err.Error()
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// seconds renders d in seconds with one decimal, such as 6.2s.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}